@@ -1427,6 +1427,31 @@ func (r *ProtocolIncus) ExecInstance(instanceName string, exec api.InstanceExecP
 	return op, nil
 }
 
+// CaptureInstanceDevice captures the traffic of an instance NIC on its host-side interface.
+func (r *ProtocolIncus) CaptureInstanceDevice(instanceName string, deviceName string, capture api.NetworkCapturePost, args *NetworkCaptureArgs) (Operation, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.HasExtension("network_capture") {
+		return nil, errors.New("The server is missing the required \"network_capture\" API extension")
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/devices/%s/capture", path, url.PathEscape(instanceName), url.PathEscape(deviceName)), capture, "")
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.captureStream(op, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // GetInstanceFile retrieves the provided path from the instance.
 func (r *ProtocolIncus) GetInstanceFile(instanceName string, filePath string) (io.ReadCloser, *InstanceFileResponse, error) {
 	var err error
@@ -2752,7 +2777,7 @@ func (r *ProtocolIncus) ConsoleInstanceDynamic(instanceName string, console api.
 // GetInstanceConsoleLog requests that Incus attaches to the console device of a instance.
 //
 // Note that it's the caller's responsibility to close the returned ReadCloser.
-func (r *ProtocolIncus) GetInstanceConsoleLog(instanceName string, _ *InstanceConsoleLogArgs) (io.ReadCloser, error) {
+func (r *ProtocolIncus) GetInstanceConsoleLog(instanceName string, args *InstanceConsoleLogArgs) (io.ReadCloser, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
 	if err != nil {
 		return nil, err
@@ -2765,6 +2790,14 @@ func (r *ProtocolIncus) GetInstanceConsoleLog(instanceName string, _ *InstanceCo
 	// Prepare the HTTP request
 	uri := fmt.Sprintf("%s/1.0%s/%s/console", r.httpBaseURL.String(), path, url.PathEscape(instanceName))
 
+	if args != nil && args.Device != "" {
+		if !r.HasExtension("device_serial") {
+			return nil, errors.New("The server is missing the required \"device_serial\" API extension")
+		}
+
+		uri = fmt.Sprintf("%s?device=%s", uri, url.QueryEscape(args.Device))
+	}
+
 	uri, err = r.setQueryAttributes(uri)
 	if err != nil {
 		return nil, err
@@ -114,6 +114,23 @@ func (r *ProtocolIncus) GetNetworkACLLogfile(name string) (io.ReadCloser, error)
 	return resp.Body, err
 }
 
+// GetNetworkACLState returns the hit counters of the network ACL rules.
+func (r *ProtocolIncus) GetNetworkACLState(name string) (*api.NetworkACLState, error) {
+	if !r.HasExtension("network_acl_stats") {
+		return nil, errors.New(`The server is missing the required "network_acl_stats" API extension`)
+	}
+
+	aclState := api.NetworkACLState{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", fmt.Sprintf("/network-acls/%s/state", url.PathEscape(name)), nil, "", &aclState)
+	if err != nil {
+		return nil, err
+	}
+
+	return &aclState, nil
+}
+
 // CreateNetworkACL defines a new network ACL using the provided struct.
 func (r *ProtocolIncus) CreateNetworkACL(acl api.NetworkACLsPost) error {
 	if !r.HasExtension("network_acl") {
@@ -0,0 +1,106 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetNetworkFloatingIPAddresses returns a list of network floating IP addresses.
+func (r *ProtocolIncus) GetNetworkFloatingIPAddresses(networkName string) ([]string, error) {
+	if !r.HasExtension("network_floating_ips") {
+		return nil, errors.New(`The server is missing the required "network_floating_ips" API extension`)
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := fmt.Sprintf("/networks/%s/floating-ips", url.PathEscape(networkName))
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetNetworkFloatingIPs returns a list of network floating IP structs.
+func (r *ProtocolIncus) GetNetworkFloatingIPs(networkName string) ([]api.NetworkFloatingIP, error) {
+	if !r.HasExtension("network_floating_ips") {
+		return nil, errors.New(`The server is missing the required "network_floating_ips" API extension`)
+	}
+
+	floatingIPs := []api.NetworkFloatingIP{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", fmt.Sprintf("/networks/%s/floating-ips?recursion=1", url.PathEscape(networkName)), nil, "", &floatingIPs)
+	if err != nil {
+		return nil, err
+	}
+
+	return floatingIPs, nil
+}
+
+// GetNetworkFloatingIP returns a network floating IP entry for the provided network and address.
+func (r *ProtocolIncus) GetNetworkFloatingIP(networkName string, address string) (*api.NetworkFloatingIP, string, error) {
+	if !r.HasExtension("network_floating_ips") {
+		return nil, "", errors.New(`The server is missing the required "network_floating_ips" API extension`)
+	}
+
+	floatingIP := api.NetworkFloatingIP{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/networks/%s/floating-ips/%s", url.PathEscape(networkName), url.PathEscape(address)), nil, "", &floatingIP)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &floatingIP, etag, nil
+}
+
+// CreateNetworkFloatingIP defines a new network floating IP using the provided struct.
+func (r *ProtocolIncus) CreateNetworkFloatingIP(networkName string, floatingIP api.NetworkFloatingIPsPost) error {
+	if !r.HasExtension("network_floating_ips") {
+		return errors.New(`The server is missing the required "network_floating_ips" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", fmt.Sprintf("/networks/%s/floating-ips", url.PathEscape(networkName)), floatingIP, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateNetworkFloatingIP updates the network floating IP to match the provided struct.
+func (r *ProtocolIncus) UpdateNetworkFloatingIP(networkName string, address string, floatingIP api.NetworkFloatingIPPut, ETag string) error {
+	if !r.HasExtension("network_floating_ips") {
+		return errors.New(`The server is missing the required "network_floating_ips" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("PUT", fmt.Sprintf("/networks/%s/floating-ips/%s", url.PathEscape(networkName), url.PathEscape(address)), floatingIP, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteNetworkFloatingIP deletes an existing network floating IP.
+func (r *ProtocolIncus) DeleteNetworkFloatingIP(networkName string, address string) error {
+	if !r.HasExtension("network_floating_ips") {
+		return errors.New(`The server is missing the required "network_floating_ips" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/networks/%s/floating-ips/%s", url.PathEscape(networkName), url.PathEscape(address)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
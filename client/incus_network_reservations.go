@@ -0,0 +1,106 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetNetworkReservationNames returns a list of network DHCP reservation names.
+func (r *ProtocolIncus) GetNetworkReservationNames(networkName string) ([]string, error) {
+	if !r.HasExtension("network_reservations") {
+		return nil, errors.New(`The server is missing the required "network_reservations" API extension`)
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := fmt.Sprintf("/networks/%s/reservations", url.PathEscape(networkName))
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetNetworkReservations returns a list of Network DHCP reservation structs.
+func (r *ProtocolIncus) GetNetworkReservations(networkName string) ([]api.NetworkReservation, error) {
+	if !r.HasExtension("network_reservations") {
+		return nil, errors.New(`The server is missing the required "network_reservations" API extension`)
+	}
+
+	reservations := []api.NetworkReservation{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", fmt.Sprintf("/networks/%s/reservations?recursion=1", url.PathEscape(networkName)), nil, "", &reservations)
+	if err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// GetNetworkReservation returns a Network DHCP reservation entry for the provided network and reservation name.
+func (r *ProtocolIncus) GetNetworkReservation(networkName string, reservationName string) (*api.NetworkReservation, string, error) {
+	if !r.HasExtension("network_reservations") {
+		return nil, "", errors.New(`The server is missing the required "network_reservations" API extension`)
+	}
+
+	reservation := api.NetworkReservation{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/networks/%s/reservations/%s", url.PathEscape(networkName), url.PathEscape(reservationName)), nil, "", &reservation)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &reservation, etag, nil
+}
+
+// CreateNetworkReservation defines a new network DHCP reservation using the provided struct.
+func (r *ProtocolIncus) CreateNetworkReservation(networkName string, reservation api.NetworkReservationsPost) error {
+	if !r.HasExtension("network_reservations") {
+		return errors.New(`The server is missing the required "network_reservations" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", fmt.Sprintf("/networks/%s/reservations", url.PathEscape(networkName)), reservation, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateNetworkReservation updates the network DHCP reservation to match the provided struct.
+func (r *ProtocolIncus) UpdateNetworkReservation(networkName string, reservationName string, reservation api.NetworkReservationPut, ETag string) error {
+	if !r.HasExtension("network_reservations") {
+		return errors.New(`The server is missing the required "network_reservations" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("PUT", fmt.Sprintf("/networks/%s/reservations/%s", url.PathEscape(networkName), url.PathEscape(reservationName)), reservation, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteNetworkReservation deletes an existing network DHCP reservation.
+func (r *ProtocolIncus) DeleteNetworkReservation(networkName string, reservationName string) error {
+	if !r.HasExtension("network_reservations") {
+		return errors.New(`The server is missing the required "network_reservations" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/networks/%s/reservations/%s", url.PathEscape(networkName), url.PathEscape(reservationName)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -121,6 +121,21 @@ func (r *ProtocolIncus) DeleteNetworkZone(name string) error {
 	return nil
 }
 
+// RetireNetworkZoneKSK removes the previous DNSSEC key signing keys of the zone following a key rollover.
+func (r *ProtocolIncus) RetireNetworkZoneKSK(name string) error {
+	if !r.HasExtension("network_zones_dnssec") {
+		return errors.New(`The server is missing the required "network_zones_dnssec" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", fmt.Sprintf("/network-zones/%s/retire-ksk", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetNetworkZoneRecordNames returns a list of network zone record names.
 func (r *ProtocolIncus) GetNetworkZoneRecordNames(zone string) ([]string, error) {
 	if !r.HasExtension("network_dns_records") {
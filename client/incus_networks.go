@@ -6,6 +6,7 @@ import (
 	"net/url"
 
 	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/ws"
 )
 
 // GetNetworkNames returns a list of network names.
@@ -134,6 +135,23 @@ func (r *ProtocolIncus) GetNetworkLeases(name string) ([]api.NetworkLease, error
 	return leases, nil
 }
 
+// GetNetworkLeaseHistory returns the recorded DHCP lease events of the network.
+func (r *ProtocolIncus) GetNetworkLeaseHistory(name string) ([]api.NetworkLeaseEvent, error) {
+	if !r.HasExtension("network_lease_history") {
+		return nil, errors.New("The server is missing the required \"network_lease_history\" API extension")
+	}
+
+	events := []api.NetworkLeaseEvent{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", fmt.Sprintf("/networks/%s/leases/history", url.PathEscape(name)), nil, "", &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
 // GetNetworkState returns metrics and information on the running network.
 func (r *ProtocolIncus) GetNetworkState(name string) (*api.NetworkState, error) {
 	if !r.HasExtension("network_state") {
@@ -151,6 +169,123 @@ func (r *ProtocolIncus) GetNetworkState(name string) (*api.NetworkState, error)
 	return &state, nil
 }
 
+// CaptureNetwork captures the traffic of a network on the server (or target cluster member).
+func (r *ProtocolIncus) CaptureNetwork(name string, capture api.NetworkCapturePost, args *NetworkCaptureArgs) (Operation, error) {
+	if !r.HasExtension("network_capture") {
+		return nil, errors.New("The server is missing the required \"network_capture\" API extension")
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", fmt.Sprintf("/networks/%s/capture", url.PathEscape(name)), capture, "")
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.captureStream(op, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// TraceNetwork simulates the path of a packet through a network on the server (or target cluster member).
+func (r *ProtocolIncus) TraceNetwork(name string, req api.NetworkTracePost) (*api.NetworkTrace, error) {
+	if !r.HasExtension("network_trace") {
+		return nil, errors.New("The server is missing the required \"network_trace\" API extension")
+	}
+
+	trace := api.NetworkTrace{}
+
+	// Send the request
+	_, err := r.queryStruct("POST", fmt.Sprintf("/networks/%s/trace", url.PathEscape(name)), req, "", &trace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &trace, nil
+}
+
+// GetNetworkTopology returns the graph of the networks of the project and of what is connected to them.
+func (r *ProtocolIncus) GetNetworkTopology() (*api.NetworkTopology, error) {
+	if !r.HasExtension("network_topology") {
+		return nil, errors.New("The server is missing the required \"network_topology\" API extension")
+	}
+
+	topology := api.NetworkTopology{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", "/network-topology", nil, "", &topology)
+	if err != nil {
+		return nil, err
+	}
+
+	return &topology, nil
+}
+
+// GetNetworkTopologyAllProjects returns the graph of the networks of all projects and of what is connected to them.
+func (r *ProtocolIncus) GetNetworkTopologyAllProjects() (*api.NetworkTopology, error) {
+	if !r.HasExtension("network_topology") {
+		return nil, errors.New("The server is missing the required \"network_topology\" API extension")
+	}
+
+	topology := api.NetworkTopology{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", "/network-topology?all-projects=true", nil, "", &topology)
+	if err != nil {
+		return nil, err
+	}
+
+	return &topology, nil
+}
+
+// captureStream connects to the websocket of a capture operation and streams the capture to the output.
+func (r *ProtocolIncus) captureStream(op Operation, args *NetworkCaptureArgs) error {
+	if args == nil || args.Output == nil {
+		return errors.New("An output must be set")
+	}
+
+	opAPI := op.Get()
+
+	// Parse the fds
+	fds := map[string]string{}
+
+	value, ok := opAPI.Metadata["fds"]
+	if ok {
+		values, ok := value.(map[string]any)
+		if ok {
+			for k, v := range values {
+				val, ok := v.(string)
+				if ok {
+					fds[k] = val
+				}
+			}
+		}
+	}
+
+	if fds["0"] == "" {
+		return errors.New("Did not receive a file descriptor for the capture")
+	}
+
+	// Connect to the websocket
+	conn, err := r.GetOperationWebsocket(opAPI.ID, fds["0"])
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ws.MirrorWrite(conn, args.Output)
+		_ = conn.Close()
+
+		if args.DataDone != nil {
+			close(args.DataDone)
+		}
+	}()
+
+	return nil
+}
+
 // CreateNetwork defines a new network using the provided Network struct.
 func (r *ProtocolIncus) CreateNetwork(network api.NetworksPost) error {
 	if !r.HasExtension("network") {
@@ -0,0 +1,91 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetProxyCertificateNames returns a list of proxy certificate names.
+func (r *ProtocolIncus) GetProxyCertificateNames() ([]string, error) {
+	if !r.HasExtension("proxy_tls_http") {
+		return nil, errors.New(`The server is missing the required "proxy_tls_http" API extension`)
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/proxy-certificates"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetProxyCertificates returns a list of proxy certificate structs.
+func (r *ProtocolIncus) GetProxyCertificates() ([]api.ProxyCertificate, error) {
+	if !r.HasExtension("proxy_tls_http") {
+		return nil, errors.New(`The server is missing the required "proxy_tls_http" API extension`)
+	}
+
+	certificates := []api.ProxyCertificate{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", "/proxy-certificates?recursion=1", nil, "", &certificates)
+	if err != nil {
+		return nil, err
+	}
+
+	return certificates, nil
+}
+
+// GetProxyCertificate returns a proxy certificate entry for the provided name.
+func (r *ProtocolIncus) GetProxyCertificate(name string) (*api.ProxyCertificate, string, error) {
+	if !r.HasExtension("proxy_tls_http") {
+		return nil, "", errors.New(`The server is missing the required "proxy_tls_http" API extension`)
+	}
+
+	certificate := api.ProxyCertificate{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/proxy-certificates/%s", url.PathEscape(name)), nil, "", &certificate)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &certificate, etag, nil
+}
+
+// CreateProxyCertificate adds a new proxy certificate using the provided struct.
+func (r *ProtocolIncus) CreateProxyCertificate(certificate api.ProxyCertificatesPost) error {
+	if !r.HasExtension("proxy_tls_http") {
+		return errors.New(`The server is missing the required "proxy_tls_http" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", "/proxy-certificates", certificate, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteProxyCertificate deletes an existing proxy certificate.
+func (r *ProtocolIncus) DeleteProxyCertificate(name string) error {
+	if !r.HasExtension("proxy_tls_http") {
+		return errors.New(`The server is missing the required "proxy_tls_http" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/proxy-certificates/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -173,6 +173,8 @@ type InstanceServer interface {
 
 	GetInstanceDebugMemory(name string, format string) (rc io.ReadCloser, err error)
 
+	CaptureInstanceDevice(instanceName string, deviceName string, capture api.NetworkCapturePost, args *NetworkCaptureArgs) (op Operation, err error)
+
 	// Event handling functions
 	GetEvents() (listener *EventListener, err error)
 	GetEventsByType(eventTypes []string) (listener *EventListener, err error)
@@ -203,7 +205,12 @@ type InstanceServer interface {
 	GetNetworksAllProjectsWithFilter(filters []string) (networks []api.Network, err error)
 	GetNetwork(name string) (network *api.Network, ETag string, err error)
 	GetNetworkLeases(name string) (leases []api.NetworkLease, err error)
+	GetNetworkLeaseHistory(name string) (events []api.NetworkLeaseEvent, err error)
 	GetNetworkState(name string) (state *api.NetworkState, err error)
+	CaptureNetwork(name string, capture api.NetworkCapturePost, args *NetworkCaptureArgs) (op Operation, err error)
+	TraceNetwork(name string, req api.NetworkTracePost) (trace *api.NetworkTrace, err error)
+	GetNetworkTopology() (topology *api.NetworkTopology, err error)
+	GetNetworkTopologyAllProjects() (topology *api.NetworkTopology, err error)
 	CreateNetwork(network api.NetworksPost) (err error)
 	UpdateNetwork(name string, network api.NetworkPut, ETag string) (err error)
 	RenameNetwork(name string, network api.NetworkPost) (err error)
@@ -217,6 +224,22 @@ type InstanceServer interface {
 	UpdateNetworkForward(networkName string, listenAddress string, forward api.NetworkForwardPut, ETag string) (err error)
 	DeleteNetworkForward(networkName string, listenAddress string) (err error)
 
+	// Network DHCP reservation functions ("network_reservations" API extension)
+	GetNetworkReservationNames(networkName string) ([]string, error)
+	GetNetworkReservations(networkName string) ([]api.NetworkReservation, error)
+	GetNetworkReservation(networkName string, reservationName string) (reservation *api.NetworkReservation, ETag string, err error)
+	CreateNetworkReservation(networkName string, reservation api.NetworkReservationsPost) error
+	UpdateNetworkReservation(networkName string, reservationName string, reservation api.NetworkReservationPut, ETag string) (err error)
+	DeleteNetworkReservation(networkName string, reservationName string) (err error)
+
+	// Network floating IP functions ("network_floating_ips" API extension)
+	GetNetworkFloatingIPAddresses(networkName string) ([]string, error)
+	GetNetworkFloatingIPs(networkName string) ([]api.NetworkFloatingIP, error)
+	GetNetworkFloatingIP(networkName string, address string) (floatingIP *api.NetworkFloatingIP, ETag string, err error)
+	CreateNetworkFloatingIP(networkName string, floatingIP api.NetworkFloatingIPsPost) error
+	UpdateNetworkFloatingIP(networkName string, address string, floatingIP api.NetworkFloatingIPPut, ETag string) (err error)
+	DeleteNetworkFloatingIP(networkName string, address string) (err error)
+
 	// Network load balancer functions ("network_load_balancer" API extension)
 	GetNetworkLoadBalancerAddresses(networkName string) ([]string, error)
 	GetNetworkLoadBalancers(networkName string) ([]api.NetworkLoadBalancer, error)
@@ -240,6 +263,7 @@ type InstanceServer interface {
 	GetNetworkACLsAllProjects() (acls []api.NetworkACL, err error)
 	GetNetworkACL(name string) (acl *api.NetworkACL, ETag string, err error)
 	GetNetworkACLLogfile(name string) (log io.ReadCloser, err error)
+	GetNetworkACLState(name string) (aclState *api.NetworkACLState, err error)
 	CreateNetworkACL(acl api.NetworkACLsPost) (err error)
 	UpdateNetworkACL(name string, acl api.NetworkACLPut, ETag string) (err error)
 	RenameNetworkACL(name string, acl api.NetworkACLPost) (err error)
@@ -267,6 +291,7 @@ type InstanceServer interface {
 	CreateNetworkZone(zone api.NetworkZonesPost) (err error)
 	UpdateNetworkZone(name string, zone api.NetworkZonePut, ETag string) (err error)
 	DeleteNetworkZone(name string) (err error)
+	RetireNetworkZoneKSK(name string) (err error)
 
 	GetNetworkZoneRecordNames(zone string) (names []string, err error)
 	GetNetworkZoneRecords(zone string) (records []api.NetworkZoneRecord, err error)
@@ -319,6 +344,13 @@ type InstanceServer interface {
 	DeleteProject(name string) (err error)
 	DeleteProjectForce(name string) (err error)
 
+	// Proxy certificate functions ("proxy_tls_http" API extension)
+	GetProxyCertificateNames() (names []string, err error)
+	GetProxyCertificates() (certificates []api.ProxyCertificate, err error)
+	GetProxyCertificate(name string) (certificate *api.ProxyCertificate, ETag string, err error)
+	CreateProxyCertificate(certificate api.ProxyCertificatesPost) (err error)
+	DeleteProxyCertificate(name string) (err error)
+
 	// Storage pool functions ("storage" API extension)
 	GetStoragePoolNames() (names []string, err error)
 	GetStoragePools() (pools []api.StoragePool, err error)
@@ -666,7 +698,12 @@ type InstanceConsoleArgs struct {
 
 // The InstanceConsoleLogArgs struct is used to pass additional options during a
 // instance console log request.
-type InstanceConsoleLogArgs struct{}
+type InstanceConsoleLogArgs struct {
+	// Serial device to retrieve the ring buffer of
+	//
+	// API extension: device_serial
+	Device string
+}
 
 // The InstanceExecArgs struct is used to pass additional options during instance exec.
 type InstanceExecArgs struct {
@@ -686,6 +723,15 @@ type InstanceExecArgs struct {
 	DataDone chan bool
 }
 
+// The NetworkCaptureArgs struct is used to pass additional options during a packet capture.
+type NetworkCaptureArgs struct {
+	// Destination of the capture (pcap format)
+	Output io.Writer
+
+	// Channel that will be closed when all data operations are done
+	DataDone chan bool
+}
+
 // The InstanceFileArgs struct is used to pass the various options for a instance file upload.
 type InstanceFileArgs struct {
 	// File content
@@ -33,6 +33,7 @@ type cmdConsole struct {
 	flagForce   bool
 	flagShowLog bool
 	flagType    string
+	flagDevice  string
 }
 
 var cmdConsoleUsage = u.Usage{u.Instance.Remote()}
@@ -51,6 +52,7 @@ as well as retrieve past log entries from it.`))
 	cmd.RunE = c.Run
 	cmd.Flags().BoolVarP(&c.flagForce, "force", "f", false, i18n.G("Forces a connection to the console, even if there is already an active session"))
 	cmd.Flags().BoolVar(&c.flagShowLog, "show-log", false, i18n.G("Retrieve the instance's console log"))
+	cmd.Flags().StringVar(&c.flagDevice, "device", "", i18n.G("Serial device to retrieve the log of (with --show-log)")+"``")
 	cmd.Flags().StringVarP(&c.flagType, "type", "t", c.global.defaultConsoleType(), i18n.G("Type of connection to establish: 'console' for serial console, 'vga' for SPICE graphical output")+"``")
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -127,13 +129,20 @@ func (c *cmdConsole) Run(cmd *cobra.Command, args []string) error {
 }
 
 func (c *cmdConsole) console(d incus.InstanceServer, name string) error {
+	if c.flagDevice != "" && !c.flagShowLog {
+		return errors.New(i18n.G("The --device flag can only be used with --show-log"))
+	}
+
 	// Show the current log if requested.
 	if c.flagShowLog {
 		if c.flagType != "console" {
 			return errors.New(i18n.G("The --show-log flag is only supported for by 'console' output type"))
 		}
 
-		console := &incus.InstanceConsoleLogArgs{}
+		console := &incus.InstanceConsoleLogArgs{
+			Device: c.flagDevice,
+		}
+
 		log, err := d.GetInstanceConsoleLog(name, console)
 		if err != nil {
 			return err
@@ -7,8 +7,10 @@ import (
 
 	"github.com/spf13/cobra"
 
+	incus "github.com/lxc/incus/v6/client"
 	u "github.com/lxc/incus/v6/cmd/incus/usage"
 	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
 	cli "github.com/lxc/incus/v6/shared/cmd"
 )
 
@@ -28,6 +30,9 @@ func (c *cmdDebug) Command() *cobra.Command {
 	debugAttachCmd := cmdDebugMemory{global: c.global, debug: c}
 	cmd.AddCommand(debugAttachCmd.Command())
 
+	debugCaptureCmd := cmdDebugCapture{global: c.global, debug: c}
+	cmd.AddCommand(debugCaptureCmd.Command())
+
 	return cmd
 }
 
@@ -86,3 +91,59 @@ func (c *cmdDebugMemory) Run(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+type cmdDebugCapture struct {
+	global *cmdGlobal
+	debug  *cmdDebug
+
+	flagFilter   string
+	flagDuration int
+	flagCount    int
+}
+
+var cmdDebugCaptureUsage = u.Usage{u.Instance.Remote(), u.Device, u.Target(u.File).Optional()}
+
+// Command returns command definition for the capture debug command.
+func (c *cmdDebugCapture) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("capture", cmdDebugCaptureUsage...)
+	cmd.Short = i18n.G("Capture the traffic of an instance network interface")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Capture the traffic of an instance network interface on its host-side interface.
+
+The capture is written in pcap format to the target file, or to standard output if none is provided.
+It runs until the duration or packet count limits are reached, or until interrupted.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus debug capture c1 eth0 capture.pcap --filter="udp port 53" --count=100
+    Captures the first 100 DNS packets of the eth0 device of the c1 instance.`))
+
+	cmd.RunE = c.Run
+	cmd.Flags().StringVar(&c.flagFilter, "filter", "", i18n.G("Packet filter expression (pcap-filter syntax)")+"``")
+	cmd.Flags().IntVar(&c.flagDuration, "duration", 0, i18n.G("Maximum duration of the capture in seconds")+"``")
+	cmd.Flags().IntVar(&c.flagCount, "count", 0, i18n.G("Maximum number of packets to capture")+"``")
+
+	return cmd
+}
+
+// Run executes the capture debug command.
+func (c *cmdDebugCapture) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdDebugCaptureUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	instanceName := parsed[0].RemoteObject.String
+	deviceName := parsed[1].String
+	path := parsed[2].Get("")
+
+	req := api.NetworkCapturePost{
+		Filter:   c.flagFilter,
+		Duration: c.flagDuration,
+		Count:    c.flagCount,
+	}
+
+	return captureRun(path, func(args *incus.NetworkCaptureArgs) (incus.Operation, error) {
+		return d.CaptureInstanceDevice(instanceName, deviceName, req, args)
+	})
+}
@@ -6,14 +6,17 @@ import (
 	"io"
 	"maps"
 	"os"
+	"os/signal"
 	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 
+	incus "github.com/lxc/incus/v6/client"
 	u "github.com/lxc/incus/v6/cmd/incus/usage"
 	"github.com/lxc/incus/v6/internal/i18n"
 	"github.com/lxc/incus/v6/shared/api"
@@ -50,6 +53,10 @@ func (c *cmdNetwork) Command() *cobra.Command {
 	networkAttachProfileCmd := cmdNetworkAttachProfile{global: c.global, network: c}
 	cmd.AddCommand(networkAttachProfileCmd.Command())
 
+	// Capture
+	networkCaptureCmd := cmdNetworkCapture{global: c.global, network: c}
+	cmd.AddCommand(networkCaptureCmd.Command())
+
 	// Create
 	networkCreateCmd := cmdNetworkCreate{global: c.global, network: c}
 	cmd.AddCommand(networkCreateCmd.Command())
@@ -90,6 +97,10 @@ func (c *cmdNetwork) Command() *cobra.Command {
 	networkListLeasesCmd := cmdNetworkListLeases{global: c.global, network: c}
 	cmd.AddCommand(networkListLeasesCmd.Command())
 
+	// Lease history
+	networkLeaseHistoryCmd := cmdNetworkLeaseHistory{global: c.global, network: c}
+	cmd.AddCommand(networkLeaseHistoryCmd.Command())
+
 	// Rename
 	networkRenameCmd := cmdNetworkRename{global: c.global, network: c}
 	cmd.AddCommand(networkRenameCmd.Command())
@@ -102,6 +113,10 @@ func (c *cmdNetwork) Command() *cobra.Command {
 	networkShowCmd := cmdNetworkShow{global: c.global, network: c}
 	cmd.AddCommand(networkShowCmd.Command())
 
+	// Trace
+	networkTraceCmd := cmdNetworkTrace{global: c.global, network: c}
+	cmd.AddCommand(networkTraceCmd.Command())
+
 	// Unset
 	networkUnsetCmd := cmdNetworkUnset{global: c.global, network: c, networkSet: &networkSetCmd}
 	cmd.AddCommand(networkUnsetCmd.Command())
@@ -114,6 +129,10 @@ func (c *cmdNetwork) Command() *cobra.Command {
 	networkAddressSetCmd := cmdNetworkAddressSet{global: c.global}
 	cmd.AddCommand(networkAddressSetCmd.Command())
 
+	// Floating IP
+	networkFloatingIPCmd := cmdNetworkFloatingIP{global: c.global}
+	cmd.AddCommand(networkFloatingIPCmd.Command())
+
 	// Forward
 	networkForwardCmd := cmdNetworkForward{global: c.global}
 	cmd.AddCommand(networkForwardCmd.Command())
@@ -130,6 +149,10 @@ func (c *cmdNetwork) Command() *cobra.Command {
 	networkPeerCmd := cmdNetworkPeer{global: c.global}
 	cmd.AddCommand(networkPeerCmd.Command())
 
+	// Reservation
+	networkReservationCmd := cmdNetworkReservation{global: c.global}
+	cmd.AddCommand(networkReservationCmd.Command())
+
 	// Zone
 	networkZoneCmd := cmdNetworkZone{global: c.global}
 	cmd.AddCommand(networkZoneCmd.Command())
@@ -324,6 +347,131 @@ func (c *cmdNetworkAttachProfile) Run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Capture.
+type cmdNetworkCapture struct {
+	global  *cmdGlobal
+	network *cmdNetwork
+
+	flagFilter   string
+	flagDuration int
+	flagCount    int
+}
+
+var cmdNetworkCaptureUsage = u.Usage{u.Network.Remote(), u.Target(u.File).Optional()}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkCapture) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("capture", cmdNetworkCaptureUsage...)
+	cmd.Short = i18n.G("Capture network traffic")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Capture network traffic
+
+The capture is written in pcap format to the target file, or to standard output if none is provided.
+It runs until the duration or packet count limits are reached, or until interrupted.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus network capture incusbr0 capture.pcap --filter="tcp port 80" --duration=60
+    Captures the HTTP traffic of the incusbr0 network for a minute.
+
+incus network capture incusbr0 --filter=icmp | tcpdump -n -r -
+    Displays the ICMP traffic of the incusbr0 network.`))
+
+	cmd.Flags().StringVar(&c.network.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
+	cmd.Flags().StringVar(&c.flagFilter, "filter", "", i18n.G("Packet filter expression (pcap-filter syntax)")+"``")
+	cmd.Flags().IntVar(&c.flagDuration, "duration", 0, i18n.G("Maximum duration of the capture in seconds")+"``")
+	cmd.Flags().IntVar(&c.flagCount, "count", 0, i18n.G("Maximum number of packets to capture")+"``")
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkCapture) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkCaptureUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	path := parsed[1].Get("")
+
+	// Targeting.
+	if c.network.flagTarget != "" {
+		if !d.IsClustered() {
+			return errors.New(i18n.G("To use --target, the destination remote must be a cluster"))
+		}
+
+		d = d.UseTarget(c.network.flagTarget)
+	}
+
+	req := api.NetworkCapturePost{
+		Filter:   c.flagFilter,
+		Duration: c.flagDuration,
+		Count:    c.flagCount,
+	}
+
+	return captureRun(path, func(args *incus.NetworkCaptureArgs) (incus.Operation, error) {
+		return d.CaptureNetwork(networkName, req, args)
+	})
+}
+
+// captureRun streams a packet capture to the target file (or standard output) until it completes or is interrupted.
+func captureRun(path string, start func(args *incus.NetworkCaptureArgs) (incus.Operation, error)) error {
+	var output io.Writer = os.Stdout
+	if path != "" && path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = file.Close() }()
+
+		output = file
+	} else if termios.IsTerminal(getStdoutFd()) {
+		return errors.New(i18n.G("Refusing to write a packet capture to the terminal, please provide a target file or redirect the output"))
+	}
+
+	args := &incus.NetworkCaptureArgs{
+		Output:   output,
+		DataDone: make(chan bool),
+	}
+
+	op, err := start(args)
+	if err != nil {
+		return err
+	}
+
+	// Stop the capture when interrupted.
+	interrupted := false
+	chSignal := make(chan os.Signal, 1)
+	signal.Notify(chSignal, os.Interrupt)
+	defer signal.Stop(chSignal)
+
+	select {
+	case <-chSignal:
+		interrupted = true
+		_ = op.Cancel()
+		<-args.DataDone
+	case <-args.DataDone:
+	}
+
+	err = op.Wait()
+	if err != nil && !interrupted {
+		return err
+	}
+
+	return nil
+}
+
 // Create.
 type cmdNetworkCreate struct {
 	global  *cmdGlobal
@@ -1011,6 +1159,63 @@ func (c *cmdNetworkInfo) Run(cmd *cobra.Command, args []string) error {
 		if state.OVN.UplinkIPv6 != "" {
 			fmt.Printf("  %s: %s\n", i18n.G("IPv6 uplink address"), state.OVN.UplinkIPv6)
 		}
+
+		if len(state.OVN.UplinkGatewayBFD) > 0 {
+			fmt.Printf("  %s:\n", i18n.G("Uplink gateway BFD"))
+
+			gateways := slices.Sorted(maps.Keys(state.OVN.UplinkGatewayBFD))
+			for _, gateway := range gateways {
+				fmt.Printf("    %s: %s\n", gateway, state.OVN.UplinkGatewayBFD[gateway])
+			}
+		}
+
+		if len(state.OVN.GatewayChassis) > 0 {
+			fmt.Printf("  %s:\n", i18n.G("Gateway chassis"))
+
+			// Show the chassis from highest to lowest priority.
+			chassis := slices.SortedFunc(maps.Keys(state.OVN.GatewayChassis), func(a string, b string) int {
+				return state.OVN.GatewayChassis[b] - state.OVN.GatewayChassis[a]
+			})
+
+			for _, name := range chassis {
+				if name == state.OVN.Chassis {
+					fmt.Printf("    %s: %d (%s)\n", name, state.OVN.GatewayChassis[name], i18n.G("active"))
+					continue
+				}
+
+				fmt.Printf("    %s: %d\n", name, state.OVN.GatewayChassis[name])
+			}
+		}
+	}
+
+	// BGP information.
+	if state.BGP != nil {
+		fmt.Println("")
+		fmt.Println(i18n.G("BGP peers:"))
+
+		for _, peer := range state.BGP.Peers {
+			fmt.Printf("  %s (%s):\n", peer.Name, peer.Address)
+			fmt.Printf("    %s: %s\n", i18n.G("State"), peer.State)
+
+			if peer.BFD != "" {
+				fmt.Printf("    %s: %s\n", i18n.G("BFD"), peer.BFD)
+			}
+		}
+	}
+
+	// QoS information.
+	if len(state.QoS) > 0 {
+		fmt.Println("")
+		fmt.Println(i18n.G("QoS classes:"))
+
+		for _, class := range state.QoS {
+			fmt.Printf("  %s:\n", class.Name)
+			fmt.Printf("    %s: %s\n", i18n.G("Bytes sent"), units.GetByteSizeString(class.BytesSent, 2))
+			fmt.Printf("    %s: %d\n", i18n.G("Packets sent"), class.PacketsSent)
+			fmt.Printf("    %s: %d\n", i18n.G("Packets dropped"), class.PacketsDropped)
+			fmt.Printf("    %s: %d\n", i18n.G("Overlimits"), class.Overlimits)
+			fmt.Printf("    %s: %s\n", i18n.G("Backlog"), units.GetByteSizeString(class.Backlog, 2))
+		}
 	}
 
 	return nil
@@ -1372,6 +1577,184 @@ func (c *cmdNetworkListLeases) Run(cmd *cobra.Command, args []string) error {
 	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, leases)
 }
 
+// Lease history.
+type cmdNetworkLeaseHistory struct {
+	global  *cmdGlobal
+	network *cmdNetwork
+
+	flagFormat  string
+	flagColumns string
+}
+
+type networkLeaseHistoryColumn struct {
+	Name string
+	Data func(api.NetworkLeaseEvent) string
+}
+
+var cmdNetworkLeaseHistoryUsage = u.Usage{u.Network.Remote()}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkLeaseHistory) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("lease-history", cmdNetworkLeaseHistoryUsage...)
+	cmd.Short = i18n.G("Show the DHCP lease history")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show the DHCP lease history
+
+The most recent lease events are shown first.
+
+Default column layout: dehminL
+
+== Columns ==
+The -c option takes a comma separated list of arguments that control
+which lease event attributes to output when displaying in table or csv
+format.
+
+Column arguments are either pre-defined shorthand chars (see below),
+or (extended) config keys.
+
+Commas between consecutive shorthand chars are optional.
+
+Pre-defined column shorthand chars:
+  d - Date
+  e - Event type
+  h - Hostname
+  m - MAC Address
+  i - IP Address
+  p - Project of the instance
+  n - Name of the instance
+  L - Location of the event (e.g. its cluster member)`))
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact|markdown), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", defaultNetworkLeaseHistoryColumns, i18n.G("Columns")+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return c.global.cmpNetworks(toComplete)
+	}
+
+	return cmd
+}
+
+const defaultNetworkLeaseHistoryColumns = "dehmin"
+
+func (c *cmdNetworkLeaseHistory) parseColumns(clustered bool) ([]networkLeaseHistoryColumn, error) {
+	columnsShorthandMap := map[rune]networkLeaseHistoryColumn{
+		'd': {i18n.G("DATE"), c.dateColumnData},
+		'e': {i18n.G("EVENT"), c.typeColumnData},
+		'h': {i18n.G("HOSTNAME"), c.hostnameColumnData},
+		'm': {i18n.G("MAC ADDRESS"), c.macAddressColumnData},
+		'i': {i18n.G("IP ADDRESS"), c.ipAddressColumnData},
+		'p': {i18n.G("PROJECT"), c.projectColumnData},
+		'n': {i18n.G("INSTANCE"), c.instanceColumnData},
+		'L': {i18n.G("LOCATION"), c.locationColumnData},
+	}
+
+	columnList := strings.Split(c.flagColumns, ",")
+	columns := []networkLeaseHistoryColumn{}
+	if c.flagColumns == defaultNetworkLeaseHistoryColumns && clustered {
+		columnList = append(columnList, "L")
+	}
+
+	for _, columnEntry := range columnList {
+		if columnEntry == "" {
+			return nil, fmt.Errorf(i18n.G("Empty column entry (redundant, leading or trailing command) in '%s'"), c.flagColumns)
+		}
+
+		for _, columnRune := range columnEntry {
+			column, ok := columnsShorthandMap[columnRune]
+			if !ok {
+				return nil, fmt.Errorf(i18n.G("Unknown column shorthand char '%c' in '%s'"), columnRune, columnEntry)
+			}
+
+			columns = append(columns, column)
+		}
+	}
+
+	return columns, nil
+}
+
+func (c *cmdNetworkLeaseHistory) dateColumnData(event api.NetworkLeaseEvent) string {
+	return event.Timestamp.Local().Format(dateLayout)
+}
+
+func (c *cmdNetworkLeaseHistory) typeColumnData(event api.NetworkLeaseEvent) string {
+	return strings.ToUpper(event.Type)
+}
+
+func (c *cmdNetworkLeaseHistory) hostnameColumnData(event api.NetworkLeaseEvent) string {
+	return event.Hostname
+}
+
+func (c *cmdNetworkLeaseHistory) macAddressColumnData(event api.NetworkLeaseEvent) string {
+	return event.Hwaddr
+}
+
+func (c *cmdNetworkLeaseHistory) ipAddressColumnData(event api.NetworkLeaseEvent) string {
+	return event.Address
+}
+
+func (c *cmdNetworkLeaseHistory) projectColumnData(event api.NetworkLeaseEvent) string {
+	return event.Project
+}
+
+func (c *cmdNetworkLeaseHistory) instanceColumnData(event api.NetworkLeaseEvent) string {
+	return event.Instance
+}
+
+func (c *cmdNetworkLeaseHistory) locationColumnData(event api.NetworkLeaseEvent) string {
+	return event.Location
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkLeaseHistory) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkLeaseHistoryUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+
+	// Get the lease history.
+	events, err := d.GetNetworkLeaseHistory(networkName)
+	if err != nil {
+		return err
+	}
+
+	// Parse column flags.
+	columns, err := c.parseColumns(d.IsClustered())
+	if err != nil {
+		return err
+	}
+
+	// Keep the most recent first ordering of the server.
+	data := [][]string{}
+	for _, event := range events {
+		line := []string{}
+		for _, column := range columns {
+			line = append(line, column.Data(event))
+		}
+
+		data = append(data, line)
+	}
+
+	header := []string{}
+	for _, column := range columns {
+		header = append(header, column.Name)
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, events)
+}
+
 // Rename.
 type cmdNetworkRename struct {
 	global  *cmdGlobal
@@ -1580,6 +1963,131 @@ func (c *cmdNetworkShow) Run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Trace.
+type cmdNetworkTrace struct {
+	global  *cmdGlobal
+	network *cmdNetwork
+
+	flagSource      string
+	flagDestination string
+	flagProtocol    string
+	flagSourcePort  uint64
+	flagPort        uint64
+	flagICMPType    string
+	flagICMPCode    string
+	flagFormat      string
+}
+
+var cmdNetworkTraceUsage = u.Usage{u.Network.Remote()}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkTrace) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("trace", cmdNetworkTraceUsage...)
+	cmd.Short = i18n.G("Trace the path of a packet through a network")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Trace the path of a packet through a network
+
+The packet goes through the address forwards, floating IPs, load balancers and ACLs of the network
+and the matching rules are shown along with the resulting verdict.
+Nothing is sent on the network.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus network trace incusbr0 --src 198.51.100.10 --dst 192.0.2.1 --proto tcp --port 443
+    Checks whether HTTPS traffic from 198.51.100.10 reaches 192.0.2.1 through the incusbr0 network.
+
+incus network trace ovn0 --src 10.0.0.2 --dst 10.0.0.3 --proto icmp4
+    Checks whether 10.0.0.2 can ping 10.0.0.3 on the ovn0 network.`))
+
+	cmd.Flags().StringVar(&c.network.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
+	cmd.Flags().StringVar(&c.flagSource, "src", "", i18n.G("Source address of the packet")+"``")
+	cmd.Flags().StringVar(&c.flagDestination, "dst", "", i18n.G("Destination address of the packet")+"``")
+	cmd.Flags().StringVar(&c.flagProtocol, "proto", "tcp", i18n.G("Protocol of the packet (tcp, udp, icmp4 or icmp6)")+"``")
+	cmd.Flags().Uint64Var(&c.flagSourcePort, "src-port", 0, i18n.G("Source port of the packet")+"``")
+	cmd.Flags().Uint64Var(&c.flagPort, "port", 0, i18n.G("Destination port of the packet")+"``")
+	cmd.Flags().StringVar(&c.flagICMPType, "icmp-type", "", i18n.G("ICMP message type of the packet")+"``")
+	cmd.Flags().StringVar(&c.flagICMPCode, "icmp-code", "", i18n.G("ICMP message code of the packet")+"``")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact|markdown), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+	cmd.RunE = c.Run
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkTrace) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkTraceUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+
+	if c.flagSource == "" || c.flagDestination == "" {
+		return errors.New(i18n.G("Both --src and --dst must be provided"))
+	}
+
+	// Targeting.
+	if c.network.flagTarget != "" {
+		if !d.IsClustered() {
+			return errors.New(i18n.G("To use --target, the destination remote must be a cluster"))
+		}
+
+		d = d.UseTarget(c.network.flagTarget)
+	}
+
+	req := api.NetworkTracePost{
+		Source:          c.flagSource,
+		Destination:     c.flagDestination,
+		Protocol:        c.flagProtocol,
+		SourcePort:      c.flagSourcePort,
+		DestinationPort: c.flagPort,
+		ICMPType:        c.flagICMPType,
+		ICMPCode:        c.flagICMPCode,
+	}
+
+	trace, err := d.TraceNetwork(networkName, req)
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, step := range trace.Steps {
+		data = append(data, []string{step.Stage, step.Rule, step.Description, strings.ToUpper(step.Verdict)})
+	}
+
+	header := []string{
+		i18n.G("STAGE"),
+		i18n.G("RULE"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("VERDICT"),
+	}
+
+	err = cli.RenderTable(os.Stdout, c.flagFormat, header, data, trace)
+	if err != nil {
+		return err
+	}
+
+	// Structured formats already include the verdict.
+	format, _, _ := strings.Cut(c.flagFormat, ",")
+	if format != cli.TableFormatJSON && format != cli.TableFormatYAML {
+		fmt.Printf(i18n.G("Verdict: %s")+"\n", strings.ToUpper(trace.Verdict))
+	}
+
+	return nil
+}
+
 // Unset.
 type cmdNetworkUnset struct {
 	global     *cmdGlobal
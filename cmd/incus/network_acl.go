@@ -43,6 +43,10 @@ func (c *cmdNetworkACL) Command() *cobra.Command {
 	networkACLShowLogCmd := cmdNetworkACLShowLog{global: c.global, networkACL: c}
 	cmd.AddCommand(networkACLShowLogCmd.Command())
 
+	// Show stats.
+	networkACLShowStatsCmd := cmdNetworkACLShowStats{global: c.global, networkACL: c}
+	cmd.AddCommand(networkACLShowStatsCmd.Command())
+
 	// Get.
 	networkACLGetCmd := cmdNetworkACLGet{global: c.global, networkACL: c}
 	cmd.AddCommand(networkACLGetCmd.Command())
@@ -276,6 +280,99 @@ func (c *cmdNetworkACLShowLog) Run(cmd *cobra.Command, args []string) error {
 	return err
 }
 
+// Show stats.
+type cmdNetworkACLShowStats struct {
+	global     *cmdGlobal
+	networkACL *cmdNetworkACL
+
+	flagFormat string
+}
+
+var cmdNetworkACLShowStatsUsage = u.Usage{u.ACL.Remote()}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkACLShowStats) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("show-stats", cmdNetworkACLShowStatsUsage...)
+	cmd.Short = i18n.G("Show network ACL rule hit counters")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Show network ACL rule hit counters"))
+	cmd.RunE = c.Run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact|markdown), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworkACLs(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkACLShowStats) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkACLShowStatsUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	aclName := parsed[0].RemoteObject.String
+
+	// Get the ACL and its rule counters.
+	netACL, _, err := d.GetNetworkACL(aclName)
+	if err != nil {
+		return err
+	}
+
+	aclState, err := d.GetNetworkACLState(aclName)
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	addRules := func(direction string, rules []api.NetworkACLRule, counters []api.NetworkACLRuleCounters) {
+		for i, rule := range rules {
+			counter := api.NetworkACLRuleCounters{}
+			if i < len(counters) {
+				counter = counters[i]
+			}
+
+			data = append(data, []string{
+				direction,
+				fmt.Sprintf("%d", i),
+				rule.Action,
+				rule.Source,
+				rule.Destination,
+				rule.State,
+				fmt.Sprintf("%d", counter.Packets),
+				fmt.Sprintf("%d", counter.Bytes),
+			})
+		}
+	}
+
+	addRules("ingress", netACL.Ingress, aclState.Ingress)
+	addRules("egress", netACL.Egress, aclState.Egress)
+
+	header := []string{
+		i18n.G("DIRECTION"),
+		i18n.G("INDEX"),
+		i18n.G("ACTION"),
+		i18n.G("SOURCE"),
+		i18n.G("DESTINATION"),
+		i18n.G("STATE"),
+		i18n.G("PACKETS"),
+		i18n.G("BYTES"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, aclState)
+}
+
 // Get.
 type cmdNetworkACLGet struct {
 	global     *cmdGlobal
@@ -0,0 +1,790 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	u "github.com/lxc/incus/v6/cmd/incus/usage"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	cli "github.com/lxc/incus/v6/shared/cmd"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+type cmdNetworkFloatingIP struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkFloatingIP) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("floating-ip")
+	cmd.Short = i18n.G("Manage network floating IPs")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Manage network floating IPs"))
+
+	// List.
+	networkFloatingIPListCmd := cmdNetworkFloatingIPList{global: c.global, networkFloatingIP: c}
+	cmd.AddCommand(networkFloatingIPListCmd.Command())
+
+	// Show.
+	networkFloatingIPShowCmd := cmdNetworkFloatingIPShow{global: c.global, networkFloatingIP: c}
+	cmd.AddCommand(networkFloatingIPShowCmd.Command())
+
+	// Create.
+	networkFloatingIPCreateCmd := cmdNetworkFloatingIPCreate{global: c.global, networkFloatingIP: c}
+	cmd.AddCommand(networkFloatingIPCreateCmd.Command())
+
+	// Get.
+	networkFloatingIPGetCmd := cmdNetworkFloatingIPGet{global: c.global, networkFloatingIP: c}
+	cmd.AddCommand(networkFloatingIPGetCmd.Command())
+
+	// Set.
+	networkFloatingIPSetCmd := cmdNetworkFloatingIPSet{global: c.global, networkFloatingIP: c}
+	cmd.AddCommand(networkFloatingIPSetCmd.Command())
+
+	// Unset.
+	networkFloatingIPUnsetCmd := cmdNetworkFloatingIPUnset{global: c.global, networkFloatingIP: c, networkFloatingIPSet: &networkFloatingIPSetCmd}
+	cmd.AddCommand(networkFloatingIPUnsetCmd.Command())
+
+	// Edit.
+	networkFloatingIPEditCmd := cmdNetworkFloatingIPEdit{global: c.global, networkFloatingIP: c}
+	cmd.AddCommand(networkFloatingIPEditCmd.Command())
+
+	// Delete.
+	networkFloatingIPDeleteCmd := cmdNetworkFloatingIPDelete{global: c.global, networkFloatingIP: c}
+	cmd.AddCommand(networkFloatingIPDeleteCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// List.
+type cmdNetworkFloatingIPList struct {
+	global            *cmdGlobal
+	networkFloatingIP *cmdNetworkFloatingIP
+
+	flagFormat  string
+	flagColumns string
+}
+
+type networkFloatingIPColumn struct {
+	Name string
+	Data func(api.NetworkFloatingIP) string
+}
+
+var cmdNetworkFloatingIPListUsage = u.Usage{u.Network.Remote()}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkFloatingIPList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("list", cmdNetworkFloatingIPListUsage...)
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List available network floating IPs")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List available network floating IPs
+
+Default column layout: aid
+
+== Columns ==
+The -c option takes a comma separated list of arguments that control
+which instance attributes to output when displaying in table or csv
+format.
+
+Column arguments are either pre-defined shorthand chars (see below),
+or (extended) config keys.
+
+Commas between consecutive shorthand chars are optional.
+
+Pre-defined column shorthand chars:
+a - Address
+i - Instance
+p - Project
+d - Description
+L - Location of the instance (e.g. its cluster member)`))
+
+	cmd.RunE = c.Run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact|markdown), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", defaultNetworkFloatingIPColumns, i18n.G("Columns")+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+const defaultNetworkFloatingIPColumns = "aid"
+
+func (c *cmdNetworkFloatingIPList) parseColumns() ([]networkFloatingIPColumn, error) {
+	columnsShorthandMap := map[rune]networkFloatingIPColumn{
+		'a': {i18n.G("ADDRESS"), c.addressColumnData},
+		'i': {i18n.G("INSTANCE"), c.instanceColumnData},
+		'p': {i18n.G("PROJECT"), c.projectColumnData},
+		'd': {i18n.G("DESCRIPTION"), c.descriptionColumnData},
+		'L': {i18n.G("LOCATION"), c.locationColumnData},
+	}
+
+	columnList := strings.Split(c.flagColumns, ",")
+	columns := []networkFloatingIPColumn{}
+
+	for _, columnEntry := range columnList {
+		if columnEntry == "" {
+			return nil, fmt.Errorf(i18n.G("Empty column entry (redundant, leading or trailing command) in '%s'"), c.flagColumns)
+		}
+
+		for _, columnRune := range columnEntry {
+			column, ok := columnsShorthandMap[columnRune]
+			if !ok {
+				return nil, fmt.Errorf(i18n.G("Unknown column shorthand char '%c' in '%s'"), columnRune, columnEntry)
+			}
+
+			columns = append(columns, column)
+		}
+	}
+
+	return columns, nil
+}
+
+func (c *cmdNetworkFloatingIPList) addressColumnData(floatingIP api.NetworkFloatingIP) string {
+	return floatingIP.Address
+}
+
+func (c *cmdNetworkFloatingIPList) instanceColumnData(floatingIP api.NetworkFloatingIP) string {
+	return floatingIP.Instance
+}
+
+func (c *cmdNetworkFloatingIPList) projectColumnData(floatingIP api.NetworkFloatingIP) string {
+	return floatingIP.Project
+}
+
+func (c *cmdNetworkFloatingIPList) locationColumnData(floatingIP api.NetworkFloatingIP) string {
+	return floatingIP.Location
+}
+
+func (c *cmdNetworkFloatingIPList) descriptionColumnData(floatingIP api.NetworkFloatingIP) string {
+	return floatingIP.Description
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkFloatingIPList) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkFloatingIPListUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+
+	floatingIPs, err := d.GetNetworkFloatingIPs(networkName)
+	if err != nil {
+		return err
+	}
+
+	// Parse column flags.
+	columns, err := c.parseColumns()
+	if err != nil {
+		return err
+	}
+
+	data := make([][]string, 0, len(floatingIPs))
+	for _, floatingIP := range floatingIPs {
+		line := []string{}
+		for _, column := range columns {
+			line = append(line, column.Data(floatingIP))
+		}
+
+		data = append(data, line)
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{}
+	for _, column := range columns {
+		header = append(header, column.Name)
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, floatingIPs)
+}
+
+// Show.
+type cmdNetworkFloatingIPShow struct {
+	global            *cmdGlobal
+	networkFloatingIP *cmdNetworkFloatingIP
+}
+
+var cmdNetworkFloatingIPShowUsage = u.Usage{u.Network.Remote(), u.Address}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkFloatingIPShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("show", cmdNetworkFloatingIPShowUsage...)
+	cmd.Short = i18n.G("Show network floating IP configurations")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Show network floating IP configurations"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkFloatingIPs(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkFloatingIPShow) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkFloatingIPShowUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	address := parsed[1].String
+
+	// Show the network floating IP config.
+	floatingIP, _, err := d.GetNetworkFloatingIP(networkName, address)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&floatingIP)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+// Create.
+type cmdNetworkFloatingIPCreate struct {
+	global            *cmdGlobal
+	networkFloatingIP *cmdNetworkFloatingIP
+
+	flagDescription string
+	flagInstance    string
+}
+
+var cmdNetworkFloatingIPCreateUsage = u.Usage{u.Network.Remote(), u.Address, u.KV.List(0)}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkFloatingIPCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("create", cmdNetworkFloatingIPCreateUsage...)
+	cmd.Aliases = []string{"add"}
+	cmd.Short = i18n.G("Create new network floating IPs")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Create new network floating IPs"))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus network floating-ip create n1 192.0.2.10 --instance c1
+    Create a floating IP 192.0.2.10 on network n1 following instance c1
+
+incus network floating-ip create n1 192.0.2.10 < config.yaml
+    Create a new network floating IP for network n1 from config.yaml`))
+
+	cmd.RunE = c.Run
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Network floating IP description")+"``")
+	cmd.Flags().StringVar(&c.flagInstance, "instance", "", i18n.G("Instance the floating IP follows")+"``")
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkFloatingIPCreate) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkFloatingIPCreateUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	address := parsed[1].String
+	keys, err := kvToMap(parsed[2])
+	if err != nil {
+		return err
+	}
+
+	// If stdin isn't a terminal, read yaml from it.
+	var floatingIPPut api.NetworkFloatingIPPut
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.UnmarshalStrict(contents, &floatingIPPut)
+		if err != nil {
+			return err
+		}
+	}
+
+	if floatingIPPut.Config == nil {
+		floatingIPPut.Config = map[string]string{}
+	}
+
+	maps.Copy(floatingIPPut.Config, keys)
+
+	// Create the network floating IP.
+	floatingIP := api.NetworkFloatingIPsPost{
+		Address:              address,
+		NetworkFloatingIPPut: floatingIPPut,
+	}
+
+	if c.flagDescription != "" {
+		floatingIP.Description = c.flagDescription
+	}
+
+	if c.flagInstance != "" {
+		floatingIP.Instance = c.flagInstance
+	}
+
+	floatingIP.Normalise()
+
+	err = d.CreateNetworkFloatingIP(networkName, floatingIP)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Network floating IP %s created")+"\n", floatingIP.Address)
+	}
+
+	return nil
+}
+
+// Get.
+type cmdNetworkFloatingIPGet struct {
+	global            *cmdGlobal
+	networkFloatingIP *cmdNetworkFloatingIP
+
+	flagIsProperty bool
+}
+
+var cmdNetworkFloatingIPGetUsage = u.Usage{u.Network.Remote(), u.Address, u.Key}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkFloatingIPGet) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("get", cmdNetworkFloatingIPGetUsage...)
+	cmd.Short = i18n.G("Get values for network floating IP configuration keys")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Get values for network floating IP configuration keys"))
+
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Get the key as a network floating IP property"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkFloatingIPs(args[0])
+		}
+
+		if len(args) == 2 {
+			return c.global.cmpNetworkFloatingIPConfigs(args[0], args[1])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkFloatingIPGet) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkFloatingIPGetUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	address := parsed[1].String
+	key := parsed[2].String
+
+	// Get the current config.
+	floatingIP, _, err := d.GetNetworkFloatingIP(networkName, address)
+	if err != nil {
+		return err
+	}
+
+	if c.flagIsProperty {
+		w := floatingIP.Writable()
+		res, err := getFieldByJSONTag(&w, key)
+		if err != nil {
+			return fmt.Errorf(i18n.G("The property %q does not exist on the network floating IP %q: %v"), key, address, err)
+		}
+
+		fmt.Printf("%v\n", res)
+	} else {
+		for k, v := range floatingIP.Config {
+			if k == key {
+				fmt.Printf("%s\n", v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Set.
+type cmdNetworkFloatingIPSet struct {
+	global            *cmdGlobal
+	networkFloatingIP *cmdNetworkFloatingIP
+
+	flagIsProperty bool
+}
+
+var cmdNetworkFloatingIPSetUsage = u.Usage{u.Network.Remote(), u.Address, u.LegacyKV.List(1)}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkFloatingIPSet) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("set", cmdNetworkFloatingIPSetUsage...)
+	cmd.Short = i18n.G("Set network floating IP keys")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Set network floating IP keys
+
+For backward compatibility, a single configuration key may still be set with:
+    incus network floating-ip set [<remote>:]<network> <floatingIP> <key> <value>`))
+	cmd.RunE = c.Run
+
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Set the key as a network floating IP property"))
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkFloatingIPs(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// set runs the post-parsing command logic.
+func (c *cmdNetworkFloatingIPSet) set(cmd *cobra.Command, parsed []*u.Parsed) error {
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	address := parsed[1].String
+	keys, err := kvToMap(parsed[2])
+	if err != nil {
+		return err
+	}
+
+	// Get the current config.
+	floatingIP, etag, err := d.GetNetworkFloatingIP(networkName, address)
+	if err != nil {
+		return err
+	}
+
+	if floatingIP.Config == nil {
+		floatingIP.Config = map[string]string{}
+	}
+
+	writable := floatingIP.Writable()
+	if c.flagIsProperty {
+		if cmd.Name() == "unset" {
+			for k := range keys {
+				err := unsetFieldByJSONTag(&writable, k)
+				if err != nil {
+					return fmt.Errorf(i18n.G("Error unsetting property: %v"), err)
+				}
+			}
+		} else {
+			err := unpackKVToWritable(&writable, keys)
+			if err != nil {
+				return fmt.Errorf(i18n.G("Error setting properties: %v"), err)
+			}
+		}
+	} else {
+		maps.Copy(writable.Config, keys)
+	}
+
+	writable.Normalise()
+
+	return d.UpdateNetworkFloatingIP(networkName, floatingIP.Address, writable, etag)
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkFloatingIPSet) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkFloatingIPSetUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	return c.set(cmd, parsed)
+}
+
+// Unset.
+type cmdNetworkFloatingIPUnset struct {
+	global               *cmdGlobal
+	networkFloatingIP    *cmdNetworkFloatingIP
+	networkFloatingIPSet *cmdNetworkFloatingIPSet
+
+	flagIsProperty bool
+}
+
+var cmdNetworkFloatingIPUnsetUsage = u.Usage{u.Network.Remote(), u.Address, u.Key}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkFloatingIPUnset) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("unset", cmdNetworkFloatingIPUnsetUsage...)
+	cmd.Short = i18n.G("Unset network floating IP configuration keys")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Unset network floating IP keys"))
+	cmd.RunE = c.Run
+
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Unset the key as a network floating IP property"))
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkFloatingIPs(args[0])
+		}
+
+		if len(args) == 2 {
+			return c.global.cmpNetworkFloatingIPConfigs(args[0], args[1])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkFloatingIPUnset) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkFloatingIPUnsetUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	c.networkFloatingIPSet.flagIsProperty = c.flagIsProperty
+	return unsetKey(c.networkFloatingIPSet, cmd, parsed)
+}
+
+// Edit.
+type cmdNetworkFloatingIPEdit struct {
+	global            *cmdGlobal
+	networkFloatingIP *cmdNetworkFloatingIP
+}
+
+var cmdNetworkFloatingIPEditUsage = u.Usage{u.Network.Remote(), u.Address}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkFloatingIPEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("edit", cmdNetworkFloatingIPEditUsage...)
+	cmd.Short = i18n.G("Edit network floating IP configurations as YAML")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Edit network floating IP configurations as YAML"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkFloatingIPs(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkFloatingIPEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the network floating IP.
+### Any line starting with a '# will be ignored.
+###
+### A network floating IP is announced by whichever cluster member runs its instance.
+###
+### An example would look like:
+### address: 192.0.2.10
+### description: Web service address
+### instance: c1
+### config:
+###   announce.bgp: "true"
+###
+### Note that the address cannot be changed.`)
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkFloatingIPEdit) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkFloatingIPEditUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	address := parsed[1].String
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		// Allow output of `incus network floating-ip show` command to be passed in here, but only take the
+		// contents of the NetworkFloatingIPPut fields when updating. The other fields are silently discarded.
+		newData := api.NetworkFloatingIP{}
+		err = yaml.UnmarshalStrict(contents, &newData)
+		if err != nil {
+			return err
+		}
+
+		newData.Normalise()
+
+		return d.UpdateNetworkFloatingIP(networkName, address, newData.NetworkFloatingIPPut, "")
+	}
+
+	// Get the current config.
+	floatingIP, etag, err := d.GetNetworkFloatingIP(networkName, address)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&floatingIP)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor.
+	content, err := cli.TextEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor.
+		newData := api.NetworkFloatingIP{} // We show the full info, but only send the writable fields.
+		err = yaml.UnmarshalStrict(content, &newData)
+		if err == nil {
+			newData.Normalise()
+			err = d.UpdateNetworkFloatingIP(networkName, address, newData.Writable(), etag)
+		}
+
+		// Respawn the editor.
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = cli.TextEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdNetworkFloatingIPDelete struct {
+	global            *cmdGlobal
+	networkFloatingIP *cmdNetworkFloatingIP
+}
+
+var cmdNetworkFloatingIPDeleteUsage = u.Usage{u.Network.Remote(), u.Address}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkFloatingIPDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("delete", cmdNetworkFloatingIPDeleteUsage...)
+	cmd.Aliases = []string{"rm", "remove"}
+	cmd.Short = i18n.G("Delete network floating IPs")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Delete network floating IPs"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkFloatingIPs(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkFloatingIPDelete) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkFloatingIPDeleteUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	address := parsed[1].String
+
+	// Delete the network floating IP.
+	err = d.DeleteNetworkFloatingIP(networkName, address)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Network floating IP %s deleted")+"\n", address)
+	}
+
+	return nil
+}
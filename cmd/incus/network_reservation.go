@@ -0,0 +1,816 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	u "github.com/lxc/incus/v6/cmd/incus/usage"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	cli "github.com/lxc/incus/v6/shared/cmd"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+type cmdNetworkReservation struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkReservation) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("reservation")
+	cmd.Short = i18n.G("Manage network DHCP reservations")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Manage network DHCP reservations"))
+
+	// List.
+	networkReservationListCmd := cmdNetworkReservationList{global: c.global, networkReservation: c}
+	cmd.AddCommand(networkReservationListCmd.Command())
+
+	// Show.
+	networkReservationShowCmd := cmdNetworkReservationShow{global: c.global, networkReservation: c}
+	cmd.AddCommand(networkReservationShowCmd.Command())
+
+	// Create.
+	networkReservationCreateCmd := cmdNetworkReservationCreate{global: c.global, networkReservation: c}
+	cmd.AddCommand(networkReservationCreateCmd.Command())
+
+	// Get.
+	networkReservationGetCmd := cmdNetworkReservationGet{global: c.global, networkReservation: c}
+	cmd.AddCommand(networkReservationGetCmd.Command())
+
+	// Set.
+	networkReservationSetCmd := cmdNetworkReservationSet{global: c.global, networkReservation: c}
+	cmd.AddCommand(networkReservationSetCmd.Command())
+
+	// Unset.
+	networkReservationUnsetCmd := cmdNetworkReservationUnset{global: c.global, networkReservation: c, networkReservationSet: &networkReservationSetCmd}
+	cmd.AddCommand(networkReservationUnsetCmd.Command())
+
+	// Edit.
+	networkReservationEditCmd := cmdNetworkReservationEdit{global: c.global, networkReservation: c}
+	cmd.AddCommand(networkReservationEditCmd.Command())
+
+	// Delete.
+	networkReservationDeleteCmd := cmdNetworkReservationDelete{global: c.global, networkReservation: c}
+	cmd.AddCommand(networkReservationDeleteCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// List.
+type cmdNetworkReservationList struct {
+	global             *cmdGlobal
+	networkReservation *cmdNetworkReservation
+
+	flagFormat  string
+	flagColumns string
+}
+
+type networkReservationColumn struct {
+	Name string
+	Data func(api.NetworkReservation) string
+}
+
+var cmdNetworkReservationListUsage = u.Usage{u.Network.Remote()}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkReservationList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("list", cmdNetworkReservationListUsage...)
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List available network DHCP reservations")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List available network DHCP reservations
+
+Default column layout: nm46d
+
+== Columns ==
+The -c option takes a comma separated list of arguments that control
+which instance attributes to output when displaying in table or csv
+format.
+
+Column arguments are either pre-defined shorthand chars (see below),
+or (extended) config keys.
+
+Commas between consecutive shorthand chars are optional.
+
+Pre-defined column shorthand chars:
+n - Name
+m - MAC address
+u - DUID
+4 - IPv4 address
+6 - IPv6 address
+d - Description`))
+
+	cmd.RunE = c.Run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact|markdown), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", defaultNetworkReservationColumns, i18n.G("Columns")+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+const defaultNetworkReservationColumns = "nm46d"
+
+func (c *cmdNetworkReservationList) parseColumns() ([]networkReservationColumn, error) {
+	columnsShorthandMap := map[rune]networkReservationColumn{
+		'n': {i18n.G("NAME"), c.nameColumnData},
+		'm': {i18n.G("MAC ADDRESS"), c.hwaddrColumnData},
+		'u': {i18n.G("DUID"), c.duidColumnData},
+		'4': {i18n.G("IPV4"), c.ipv4AddressColumnData},
+		'6': {i18n.G("IPV6"), c.ipv6AddressColumnData},
+		'd': {i18n.G("DESCRIPTION"), c.descriptionColumnData},
+	}
+
+	columnList := strings.Split(c.flagColumns, ",")
+	columns := []networkReservationColumn{}
+
+	for _, columnEntry := range columnList {
+		if columnEntry == "" {
+			return nil, fmt.Errorf(i18n.G("Empty column entry (redundant, leading or trailing command) in '%s'"), c.flagColumns)
+		}
+
+		for _, columnRune := range columnEntry {
+			column, ok := columnsShorthandMap[columnRune]
+			if !ok {
+				return nil, fmt.Errorf(i18n.G("Unknown column shorthand char '%c' in '%s'"), columnRune, columnEntry)
+			}
+
+			columns = append(columns, column)
+		}
+	}
+
+	return columns, nil
+}
+
+func (c *cmdNetworkReservationList) nameColumnData(reservation api.NetworkReservation) string {
+	return reservation.Name
+}
+
+func (c *cmdNetworkReservationList) hwaddrColumnData(reservation api.NetworkReservation) string {
+	return reservation.Hwaddr
+}
+
+func (c *cmdNetworkReservationList) duidColumnData(reservation api.NetworkReservation) string {
+	return reservation.DUID
+}
+
+func (c *cmdNetworkReservationList) ipv4AddressColumnData(reservation api.NetworkReservation) string {
+	return reservation.IPv4Address
+}
+
+func (c *cmdNetworkReservationList) ipv6AddressColumnData(reservation api.NetworkReservation) string {
+	return reservation.IPv6Address
+}
+
+func (c *cmdNetworkReservationList) descriptionColumnData(reservation api.NetworkReservation) string {
+	return reservation.Description
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkReservationList) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkReservationListUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+
+	reservations, err := d.GetNetworkReservations(networkName)
+	if err != nil {
+		return err
+	}
+
+	// Parse column flags.
+	columns, err := c.parseColumns()
+	if err != nil {
+		return err
+	}
+
+	data := make([][]string, 0, len(reservations))
+	for _, reservation := range reservations {
+		line := []string{}
+		for _, column := range columns {
+			line = append(line, column.Data(reservation))
+		}
+
+		data = append(data, line)
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{}
+	for _, column := range columns {
+		header = append(header, column.Name)
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, reservations)
+}
+
+// Show.
+type cmdNetworkReservationShow struct {
+	global             *cmdGlobal
+	networkReservation *cmdNetworkReservation
+}
+
+var cmdNetworkReservationShowUsage = u.Usage{u.Network.Remote(), u.Reservation}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkReservationShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("show", cmdNetworkReservationShowUsage...)
+	cmd.Short = i18n.G("Show network DHCP reservation configurations")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Show network DHCP reservation configurations"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkReservations(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkReservationShow) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkReservationShowUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	reservationName := parsed[1].String
+
+	// Show the network DHCP reservation config.
+	reservation, _, err := d.GetNetworkReservation(networkName, reservationName)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&reservation)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+// Create.
+type cmdNetworkReservationCreate struct {
+	global             *cmdGlobal
+	networkReservation *cmdNetworkReservation
+
+	flagDescription string
+	flagHwaddr      string
+	flagDUID        string
+	flagIPv4Address string
+	flagIPv6Address string
+}
+
+var cmdNetworkReservationCreateUsage = u.Usage{u.Network.Remote(), u.Reservation, u.KV.List(0)}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkReservationCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("create", cmdNetworkReservationCreateUsage...)
+	cmd.Aliases = []string{"add"}
+	cmd.Short = i18n.G("Create new network DHCP reservations")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Create new network DHCP reservations"))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus network reservation create n1 printer01 --hwaddr 00:16:3e:12:34:56 --ipv4-address 10.0.0.10
+    Reserve 10.0.0.10 for the client with MAC address 00:16:3e:12:34:56 on network n1
+
+incus network reservation create n1 printer01 < config.yaml
+    Create a new network DHCP reservation for network n1 from config.yaml`))
+
+	cmd.RunE = c.Run
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Network DHCP reservation description")+"``")
+	cmd.Flags().StringVar(&c.flagHwaddr, "hwaddr", "", i18n.G("MAC address of the client")+"``")
+	cmd.Flags().StringVar(&c.flagDUID, "duid", "", i18n.G("DHCPv6 unique identifier (DUID) of the client")+"``")
+	cmd.Flags().StringVar(&c.flagIPv4Address, "ipv4-address", "", i18n.G("IPv4 address to reserve")+"``")
+	cmd.Flags().StringVar(&c.flagIPv6Address, "ipv6-address", "", i18n.G("IPv6 address to reserve")+"``")
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkReservationCreate) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkReservationCreateUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	reservationName := parsed[1].String
+	keys, err := kvToMap(parsed[2])
+	if err != nil {
+		return err
+	}
+
+	// If stdin isn't a terminal, read yaml from it.
+	var reservationPut api.NetworkReservationPut
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.UnmarshalStrict(contents, &reservationPut)
+		if err != nil {
+			return err
+		}
+	}
+
+	if reservationPut.Config == nil {
+		reservationPut.Config = map[string]string{}
+	}
+
+	maps.Copy(reservationPut.Config, keys)
+
+	// Create the network DHCP reservation.
+	reservation := api.NetworkReservationsPost{
+		Name:                  reservationName,
+		NetworkReservationPut: reservationPut,
+	}
+
+	if c.flagDescription != "" {
+		reservation.Description = c.flagDescription
+	}
+
+	if c.flagHwaddr != "" {
+		reservation.Hwaddr = c.flagHwaddr
+	}
+
+	if c.flagDUID != "" {
+		reservation.DUID = c.flagDUID
+	}
+
+	if c.flagIPv4Address != "" {
+		reservation.IPv4Address = c.flagIPv4Address
+	}
+
+	if c.flagIPv6Address != "" {
+		reservation.IPv6Address = c.flagIPv6Address
+	}
+
+	reservation.Normalise()
+
+	err = d.CreateNetworkReservation(networkName, reservation)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Network DHCP reservation %s created")+"\n", reservation.Name)
+	}
+
+	return nil
+}
+
+// Get.
+type cmdNetworkReservationGet struct {
+	global             *cmdGlobal
+	networkReservation *cmdNetworkReservation
+
+	flagIsProperty bool
+}
+
+var cmdNetworkReservationGetUsage = u.Usage{u.Network.Remote(), u.Reservation, u.Key}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkReservationGet) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("get", cmdNetworkReservationGetUsage...)
+	cmd.Short = i18n.G("Get values for network DHCP reservation configuration keys")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Get values for network DHCP reservation configuration keys"))
+
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Get the key as a network DHCP reservation property"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkReservations(args[0])
+		}
+
+		if len(args) == 2 {
+			return c.global.cmpNetworkReservationConfigs(args[0], args[1])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkReservationGet) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkReservationGetUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	reservationName := parsed[1].String
+	key := parsed[2].String
+
+	// Get the current config.
+	reservation, _, err := d.GetNetworkReservation(networkName, reservationName)
+	if err != nil {
+		return err
+	}
+
+	if c.flagIsProperty {
+		w := reservation.Writable()
+		res, err := getFieldByJSONTag(&w, key)
+		if err != nil {
+			return fmt.Errorf(i18n.G("The property %q does not exist on the network DHCP reservation %q: %v"), key, reservationName, err)
+		}
+
+		fmt.Printf("%v\n", res)
+	} else {
+		for k, v := range reservation.Config {
+			if k == key {
+				fmt.Printf("%s\n", v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Set.
+type cmdNetworkReservationSet struct {
+	global             *cmdGlobal
+	networkReservation *cmdNetworkReservation
+
+	flagIsProperty bool
+}
+
+var cmdNetworkReservationSetUsage = u.Usage{u.Network.Remote(), u.Reservation, u.LegacyKV.List(1)}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkReservationSet) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("set", cmdNetworkReservationSetUsage...)
+	cmd.Short = i18n.G("Set network DHCP reservation keys")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Set network DHCP reservation keys
+
+For backward compatibility, a single configuration key may still be set with:
+    incus network reservation set [<remote>:]<network> <reservation> <key> <value>`))
+	cmd.RunE = c.Run
+
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Set the key as a network DHCP reservation property"))
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkReservations(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// set runs the post-parsing command logic.
+func (c *cmdNetworkReservationSet) set(cmd *cobra.Command, parsed []*u.Parsed) error {
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	reservationName := parsed[1].String
+	keys, err := kvToMap(parsed[2])
+	if err != nil {
+		return err
+	}
+
+	// Get the current config.
+	reservation, etag, err := d.GetNetworkReservation(networkName, reservationName)
+	if err != nil {
+		return err
+	}
+
+	if reservation.Config == nil {
+		reservation.Config = map[string]string{}
+	}
+
+	writable := reservation.Writable()
+	if c.flagIsProperty {
+		if cmd.Name() == "unset" {
+			for k := range keys {
+				err := unsetFieldByJSONTag(&writable, k)
+				if err != nil {
+					return fmt.Errorf(i18n.G("Error unsetting property: %v"), err)
+				}
+			}
+		} else {
+			err := unpackKVToWritable(&writable, keys)
+			if err != nil {
+				return fmt.Errorf(i18n.G("Error setting properties: %v"), err)
+			}
+		}
+	} else {
+		maps.Copy(writable.Config, keys)
+	}
+
+	writable.Normalise()
+
+	return d.UpdateNetworkReservation(networkName, reservation.Name, writable, etag)
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkReservationSet) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkReservationSetUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	return c.set(cmd, parsed)
+}
+
+// Unset.
+type cmdNetworkReservationUnset struct {
+	global                *cmdGlobal
+	networkReservation    *cmdNetworkReservation
+	networkReservationSet *cmdNetworkReservationSet
+
+	flagIsProperty bool
+}
+
+var cmdNetworkReservationUnsetUsage = u.Usage{u.Network.Remote(), u.Reservation, u.Key}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkReservationUnset) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("unset", cmdNetworkReservationUnsetUsage...)
+	cmd.Short = i18n.G("Unset network DHCP reservation configuration keys")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Unset network DHCP reservation keys"))
+	cmd.RunE = c.Run
+
+	cmd.Flags().BoolVarP(&c.flagIsProperty, "property", "p", false, i18n.G("Unset the key as a network DHCP reservation property"))
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkReservations(args[0])
+		}
+
+		if len(args) == 2 {
+			return c.global.cmpNetworkReservationConfigs(args[0], args[1])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkReservationUnset) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkReservationUnsetUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	c.networkReservationSet.flagIsProperty = c.flagIsProperty
+	return unsetKey(c.networkReservationSet, cmd, parsed)
+}
+
+// Edit.
+type cmdNetworkReservationEdit struct {
+	global             *cmdGlobal
+	networkReservation *cmdNetworkReservation
+}
+
+var cmdNetworkReservationEditUsage = u.Usage{u.Network.Remote(), u.Reservation}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkReservationEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("edit", cmdNetworkReservationEditUsage...)
+	cmd.Short = i18n.G("Edit network DHCP reservation configurations as YAML")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Edit network DHCP reservation configurations as YAML"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkReservations(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdNetworkReservationEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the network DHCP reservation.
+### Any line starting with a '# will be ignored.
+###
+### A network DHCP reservation pins addresses to a client identified by its MAC address or DUID.
+###
+### An example would look like:
+### name: printer01
+### description: Office printer
+### hwaddr: 00:16:3e:12:34:56
+### duid: ""
+### ipv4_address: 10.0.0.10
+### ipv6_address: fd42:4242:4242:1010::10
+### config: {}
+###
+### Note that the name cannot be changed.`)
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkReservationEdit) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkReservationEditUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	reservationName := parsed[1].String
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		// Allow output of `incus network reservation show` command to be passed in here, but only take the
+		// contents of the NetworkReservationPut fields when updating. The other fields are silently discarded.
+		newData := api.NetworkReservation{}
+		err = yaml.UnmarshalStrict(contents, &newData)
+		if err != nil {
+			return err
+		}
+
+		newData.Normalise()
+
+		return d.UpdateNetworkReservation(networkName, reservationName, newData.NetworkReservationPut, "")
+	}
+
+	// Get the current config.
+	reservation, etag, err := d.GetNetworkReservation(networkName, reservationName)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&reservation)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor.
+	content, err := cli.TextEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor.
+		newData := api.NetworkReservation{} // We show the full info, but only send the writable fields.
+		err = yaml.UnmarshalStrict(content, &newData)
+		if err == nil {
+			newData.Normalise()
+			err = d.UpdateNetworkReservation(networkName, reservationName, newData.Writable(), etag)
+		}
+
+		// Respawn the editor.
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = cli.TextEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdNetworkReservationDelete struct {
+	global             *cmdGlobal
+	networkReservation *cmdNetworkReservation
+}
+
+var cmdNetworkReservationDeleteUsage = u.Usage{u.Network.Remote(), u.Reservation}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkReservationDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("delete", cmdNetworkReservationDeleteUsage...)
+	cmd.Aliases = []string{"rm", "remove"}
+	cmd.Short = i18n.G("Delete network DHCP reservations")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G("Delete network DHCP reservations"))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworks(toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpNetworkReservations(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkReservationDelete) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkReservationDeleteUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	networkName := parsed[0].RemoteObject.String
+	reservationName := parsed[1].String
+
+	// Delete the network DHCP reservation.
+	err = d.DeleteNetworkReservation(networkName, reservationName)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Network DHCP reservation %s deleted")+"\n", reservationName)
+	}
+
+	return nil
+}
@@ -68,6 +68,10 @@ func (c *cmdNetworkZone) Command() *cobra.Command {
 	networkZoneDeleteCmd := cmdNetworkZoneDelete{global: c.global, networkZone: c}
 	cmd.AddCommand(networkZoneDeleteCmd.Command())
 
+	// Retire KSK.
+	networkZoneRetireKSKCmd := cmdNetworkZoneRetireKSK{global: c.global, networkZone: c}
+	cmd.AddCommand(networkZoneRetireKSKCmd.Command())
+
 	// Record.
 	networkZoneRecordCmd := cmdNetworkZoneRecord{global: c.global, networkZone: c}
 	cmd.AddCommand(networkZoneRecordCmd.Command())
@@ -759,6 +763,58 @@ func (c *cmdNetworkZoneDelete) Run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Retire KSK.
+type cmdNetworkZoneRetireKSK struct {
+	global      *cmdGlobal
+	networkZone *cmdNetworkZone
+}
+
+var cmdNetworkZoneRetireKSKUsage = u.Usage{u.Zone.Remote()}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdNetworkZoneRetireKSK) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = cli.U("retire-ksk", cmdNetworkZoneRetireKSKUsage...)
+	cmd.Short = i18n.G("Retire the previous DNSSEC key signing key of network zones")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Retire the previous DNSSEC key signing key of network zones
+
+Only run this once the DS record of the new key signing key has been published in the parent zone.`))
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpNetworkZones(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdNetworkZoneRetireKSK) Run(cmd *cobra.Command, args []string) error {
+	parsed, err := cmdNetworkZoneRetireKSKUsage.Parse(c.global.conf, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	d := parsed[0].RemoteServer
+	zoneName := parsed[0].RemoteObject.String
+
+	// Retire the key.
+	err = d.RetireNetworkZoneKSK(zoneName)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Previous key signing key of network zone %s retired")+"\n", formatRemote(c.global.conf, parsed[0]))
+	}
+
+	return nil
+}
+
 // Add/Remove Rule.
 type cmdNetworkZoneRecord struct {
 	global      *cmdGlobal
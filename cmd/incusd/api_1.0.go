@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 
 	incus "github.com/lxc/incus/v6/client"
@@ -19,6 +20,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db"
 	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/network/acl"
 	"github.com/lxc/incus/v6/internal/server/node"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
@@ -71,6 +73,7 @@ var api10 = []APIEndpoint{
 	instanceSnapshotsCmd,
 	instanceStateCmd,
 	instanceAccessCmd,
+	instanceDeviceCaptureCmd,
 	instanceDebugMemoryCmd,
 	instanceDebugRepairCmd,
 	eventsCmd,
@@ -84,24 +87,34 @@ var api10 = []APIEndpoint{
 	metadataConfigurationCmd,
 	networkCmd,
 	networkLeasesCmd,
+	networkLeasesHistoryCmd,
 	networksCmd,
 	networkStateCmd,
+	networkCaptureCmd,
+	networkTraceCmd,
 	networkACLCmd,
 	networkACLsCmd,
 	networkACLLogCmd,
+	networkACLStateCmd,
 	networkAddressSetCmd,
 	networkAddressSetsCmd,
 	networkAllocationsCmd,
+	networkFloatingIPCmd,
+	networkFloatingIPsCmd,
 	networkForwardCmd,
 	networkForwardsCmd,
 	networkIntegrationCmd,
 	networkIntegrationsCmd,
+	networkTopologyCmd,
 	networkLoadBalancerCmd,
 	networkLoadBalancerStateCmd,
 	networkLoadBalancersCmd,
 	networkPeerCmd,
 	networkPeersCmd,
+	networkReservationCmd,
+	networkReservationsCmd,
 	networkZoneCmd,
+	networkZoneRetireKSKCmd,
 	networkZonesCmd,
 	networkZoneRecordCmd,
 	networkZoneRecordsCmd,
@@ -115,6 +128,8 @@ var api10 = []APIEndpoint{
 	projectsCmd,
 	projectStateCmd,
 	projectAccessCmd,
+	proxyCertificateCmd,
+	proxyCertificatesCmd,
 	storagePoolCmd,
 	storagePoolResourcesCmd,
 	storagePoolsCmd,
@@ -765,6 +780,17 @@ func doApi10Update(d *Daemon, r *http.Request, req api.ServerPut, patch bool) re
 		}
 	})
 
+	// Check the host ACLs exist and can be applied on this server.
+	_, ok := clusterChanged["network.host.acls"]
+	if ok {
+		aclNames, _, _ := newClusterConfig.NetworkHostACLs()
+
+		err = acl.HostValidateACLs(s, aclNames)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
 	// Notify the other nodes about changes
 	notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
 	if err != nil {
@@ -813,6 +839,7 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	acmeChanged := false
 	bgpChanged := false
 	dnsChanged := false
+	hostACLsChanged := false
 	oidcChanged := false
 	openFGAChanged := false
 	ovnChanged := false
@@ -854,6 +881,9 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 			// Notify the logging mechanism about changes to the deprecated keys for backward compatibility.
 			loggingChanges["loki"] = struct{}{}
 
+		case "network.host.acls", "network.host.acls.default.ingress.action", "network.host.acls.default.ingress.logged":
+			hostACLsChanged = true
+
 		case "network.ovn.northbound_connection", "network.ovn.ca_cert", "network.ovn.client_cert", "network.ovn.client_key":
 			ovnChanged = true
 
@@ -876,6 +906,11 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	}
 
 	for key := range nodeChanged {
+		// The host ACLs apply to the listen addresses.
+		if slices.Contains([]string{"core.https_address", "cluster.https_address", "core.metrics_address", "core.storage_buckets_address", "core.bgp_address", "core.dns_address"}, key) {
+			hostACLsChanged = true
+		}
+
 		switch key {
 		case "core.bgp_address", "core.bgp_routerid":
 			bgpChanged = true
@@ -995,6 +1030,13 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	if hostACLsChanged {
+		err := acl.FirewallApplyHostACLRules(s)
+		if err != nil {
+			return fmt.Errorf("Failed applying host ACLs: %w", err)
+		}
+	}
+
 	if len(loggingChanges) > 0 {
 		err := d.loggingController.Reconfigure(d.State(), loggingChanges)
 		if err != nil {
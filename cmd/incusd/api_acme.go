@@ -10,6 +10,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/acme"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/device"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/task"
@@ -203,3 +204,14 @@ func autoRenewCertificateTask(d *Daemon) (task.Func, task.Schedule) {
 
 	return f, task.Daily()
 }
+
+func autoRenewProxyCertificatesTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := device.ProxyRenewCertificates(ctx)
+		if err != nil {
+			logger.Error("Failed proxy device certificate renewal", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Daily()
+}
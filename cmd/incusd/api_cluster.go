@@ -1894,8 +1894,8 @@ func updateClusterNode(s *state.State, gateway *cluster.Gateway, r *http.Request
 		return response.SmartError(err)
 	}
 
-	// If cluster roles changed, then distribute the info to all members.
-	if s.Endpoints != nil && clusterRolesChanged(member.Roles, newRoles) {
+	// If cluster roles or groups changed, then distribute the info to all members.
+	if s.Endpoints != nil && (clusterRolesChanged(member.Roles, newRoles) || !slices.Equal(member.Groups, req.Groups)) {
 		cluster.NotifyHeartbeat(s, gateway)
 	}
 
@@ -13,6 +13,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
@@ -623,6 +624,11 @@ func clusterGroupPut(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// If the group members changed, then distribute the info to all members.
+	if s.Endpoints != nil && clusterGroupMembersChanged(dbClusterGroup.Nodes, req.Members) {
+		cluster.NotifyHeartbeat(s, d.gateway)
+	}
+
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterGroupUpdated.Event(name, requestor, logger.Ctx{"description": req.Description, "members": req.Members}))
 
@@ -834,6 +840,11 @@ func clusterGroupPatch(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// If the group members changed, then distribute the info to all members.
+	if s.Endpoints != nil && clusterGroupMembersChanged(dbClusterGroup.Nodes, req.Members) {
+		cluster.NotifyHeartbeat(s, d.gateway)
+	}
+
 	requestor := request.CreateRequestor(r)
 	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterGroupUpdated.Event(name, requestor, logger.Ctx{"description": req.Description, "members": req.Members}))
 
@@ -960,6 +971,11 @@ func clusterGroupValidate(config map[string]string) error {
 	return nil
 }
 
+// clusterGroupMembersChanged checks whether the members of a cluster group have changed.
+func clusterGroupMembersChanged(oldMembers []string, newMembers []string) bool {
+	return !slices.Equal(slices.Sorted(slices.Values(oldMembers)), slices.Sorted(slices.Values(newMembers)))
+}
+
 // clusterGroupFill fills in automatic values.
 func clusterGroupFill(ctx context.Context, s *state.State, servers []string, req *api.ClusterGroupPut) error {
 	// If no config, nothing to fill.
@@ -124,6 +124,9 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 		// Add internal metrics.
 		intMetrics = internalMetrics(ctx, s, tx)
 
+		// Add the project network counters.
+		intMetrics.Merge(projectNetworkMetrics(ctx, tx, projectNames))
+
 		return nil
 	})
 	if err != nil {
@@ -322,6 +325,29 @@ func getFilteredMetrics(s *state.State, r *http.Request, compress bool, metricSe
 	return response.SyncResponsePlain(true, compress, metricSet.String())
 }
 
+// projectNetworkMetrics returns the cumulative network traffic counters of the projects for this member.
+func projectNetworkMetrics(ctx context.Context, tx *db.ClusterTx, projectNames []string) *metrics.MetricSet {
+	out := metrics.NewMetricSet(nil)
+
+	counters, err := tx.GetProjectNetworkCounters(ctx, true)
+	if err != nil {
+		logger.Warn("Failed to get project network counters", logger.Ctx{"err": err})
+		return out
+	}
+
+	for _, projectName := range projectNames {
+		projectCounters := counters[projectName]
+		labels := map[string]string{"project": projectName}
+
+		out.AddSamples(metrics.ProjectNetworkReceiveBytesTotal, metrics.Sample{Labels: labels, Value: float64(projectCounters.BytesReceived)})
+		out.AddSamples(metrics.ProjectNetworkReceivePacketsTotal, metrics.Sample{Labels: labels, Value: float64(projectCounters.PacketsReceived)})
+		out.AddSamples(metrics.ProjectNetworkTransmitBytesTotal, metrics.Sample{Labels: labels, Value: float64(projectCounters.BytesSent)})
+		out.AddSamples(metrics.ProjectNetworkTransmitPacketsTotal, metrics.Sample{Labels: labels, Value: float64(projectCounters.PacketsSent)})
+	}
+
+	return out
+}
+
 func internalMetrics(ctx context.Context, s *state.State, tx *db.ClusterTx) *metrics.MetricSet {
 	out := metrics.NewMetricSet(nil)
 
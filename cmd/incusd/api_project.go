@@ -33,6 +33,7 @@ import (
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
 	"github.com/lxc/incus/v6/shared/validate"
 )
@@ -810,6 +811,15 @@ func projectChange(ctx context.Context, s *state.State, project *api.Project, re
 		return response.SmartError(err)
 	}
 
+	// Apply the network limits to the local instances right away, the other cluster members pick them up
+	// on the next run of their project network task.
+	if slices.ContainsFunc(configChanged, func(key string) bool { return strings.HasPrefix(key, "limits.network.") }) {
+		err = projectNetworkUpdate(ctx, s)
+		if err != nil {
+			logger.Warn("Failed applying project network limits", logger.Ctx{"project": project.Name, "err": err})
+		}
+	}
+
 	return response.EmptySyncResponse
 }
 
@@ -1327,6 +1337,14 @@ func projectStateGet(d *Daemon, r *http.Request) response.Response {
 
 		state.Resources = result
 
+		counters, err := tx.GetProjectNetworkCounters(ctx, false)
+		if err != nil {
+			return err
+		}
+
+		projectCounters := counters[name]
+		state.Network = &projectCounters
+
 		return nil
 	})
 	if err != nil {
@@ -1364,6 +1382,11 @@ func isEitherAllowOrBlockOrManaged(value string) error {
 	return validate.Optional(validate.IsOneOf("block", "allow", "managed"))(value)
 }
 
+func isNetworkRate(value string) error {
+	_, err := units.ParseBitSizeString(value)
+	return err
+}
+
 func projectValidateConfig(s *state.State, config map[string]string) error {
 	// Validate the project configuration.
 	projectConfigKeys := map[string]func(value string) error{
@@ -1521,6 +1544,24 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Maximum number of networks that the project can have
 		"limits.networks": validate.Optional(validate.IsUint32),
 
+		// gendoc:generate(entity=project, group=limits, key=limits.network.ingress)
+		// This value is the maximum aggregate rate of the traffic received by the instances of the project on each cluster member.
+		// It applies to the bridged, routed and p2p NICs, on top of their own {config:option}`devices-nic_bridged:limits.ingress` limit.
+		// It can't be set when instances of the project have OVN NICs.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum bit rate of the incoming traffic of the project's instances
+		"limits.network.ingress": validate.Optional(isNetworkRate),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.network.egress)
+		// This value is the maximum aggregate rate of the traffic sent by the instances of the project on each cluster member.
+		// It applies to the bridged, routed and p2p NICs, on top of their own {config:option}`devices-nic_bridged:limits.egress` limit.
+		// It can't be set when instances of the project have OVN NICs.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum bit rate of the outgoing traffic of the project's instances
+		"limits.network.egress": validate.Optional(isNetworkRate),
+
 		// gendoc:generate(entity=project, group=specific, key=network.hwaddr_pattern)
 		// Specify a MAC address template, e.g. `10:66:6a:xx:xx:xx`, to use within the cluster.
 		// Every `x` in the template will be replaced by a random character in `0`–`f`.
@@ -1710,6 +1751,15 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Whether to prevent using devices of type `proxy`
 		"restricted.devices.proxy": isEitherAllowOrBlock,
 
+		// gendoc:generate(entity=project, group=restricted, key=restricted.devices.serial)
+		// Possible values are `allow` or `block`.
+		// When set to `block`, serial devices can only keep their output in a ring buffer.
+		// ---
+		//  type: string
+		//  defaultdesc: `block`
+		//  shortdesc: Whether to prevent using devices of type `serial` listening on the host
+		"restricted.devices.serial": isEitherAllowOrBlock,
+
 		// gendoc:generate(entity=project, group=restricted, key=restricted.devices.nic)
 		// Possible values are `allow`, `block`, or `managed`.
 		//
@@ -1722,6 +1772,15 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Which network devices can be used
 		"restricted.devices.nic": isEitherAllowOrBlockOrManaged,
 
+		// gendoc:generate(entity=project, group=restricted, key=restricted.devices.nic.mirror)
+		// Possible values are `allow` or `block`.
+		// When set to `block`, NICs can't mirror their traffic to a remote collector through {config:option}`devices-nic_ovn:mirror.remote`.
+		// ---
+		//  type: string
+		//  defaultdesc: `block`
+		//  shortdesc: Whether to prevent NICs from mirroring their traffic to a remote collector
+		"restricted.devices.nic.mirror": isEitherAllowOrBlock,
+
 		// gendoc:generate(entity=project, group=restricted, key=restricted.devices.disk)
 		// Possible values are `allow`, `block`, or `managed`.
 		//
@@ -1738,6 +1797,7 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		// If {config:option}`project-restricted:restricted.devices.disk` is set to `allow`, this option controls which `source` can be used for `disk` devices.
 		// Specify a comma-separated list of path prefixes that restrict the `source` setting.
 		// If this option is left empty, all paths are allowed.
+		// Network sources (NBD, iSCSI and HTTP) are only allowed if this option is left empty.
 		// ---
 		//  type: string
 		//  shortdesc: Which `source` can be used for `disk` devices
@@ -45,14 +45,17 @@ import (
 	"github.com/lxc/incus/v6/internal/server/endpoints"
 	"github.com/lxc/incus/v6/internal/server/events"
 	"github.com/lxc/incus/v6/internal/server/firewall"
+	firewallDrivers "github.com/lxc/incus/v6/internal/server/firewall/drivers"
 	"github.com/lxc/incus/v6/internal/server/fsmonitor"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/logging"
+	"github.com/lxc/incus/v6/internal/server/network/acl"
 	"github.com/lxc/incus/v6/internal/server/network/ovn"
 	"github.com/lxc/incus/v6/internal/server/network/ovs"
 	networkZone "github.com/lxc/incus/v6/internal/server/network/zone"
+	"github.com/lxc/incus/v6/internal/server/nflog"
 	"github.com/lxc/incus/v6/internal/server/node"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
@@ -1465,6 +1468,11 @@ func (d *Daemon) init() error {
 		return err
 	}
 
+	// Keep the address sets selecting instances up to date.
+	d.internalListener.AddHandler("networkAddressSetSelectors", func(event api.Event) {
+		networkAddressSetSelectorsHandler(d.State(), event)
+	})
+
 	// Setup syslog listener.
 	if syslogSocketEnabled {
 		err = d.setupSyslogSocket(true)
@@ -1473,6 +1481,15 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Setup the network ACL flow log listener.
+	err = nflog.Listen(d.shutdownCtx, firewallDrivers.ACLLogGroup, func(packet *nflog.Packet) {
+		projectName, event := acl.FlowLogEvent(d.State(), packet)
+		_ = d.events.Send(projectName, api.EventTypeNetworkACL, event)
+	})
+	if err != nil {
+		logger.Warn("Failed starting network ACL flow log listener", logger.Ctx{"err": err})
+	}
+
 	// Setup OIDC authentication.
 	if oidcIssuer != "" && oidcClientID != "" {
 		d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim)
@@ -1580,6 +1597,12 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Protect the listeners with the host ACLs.
+	err = acl.FirewallApplyHostACLRules(d.State())
+	if err != nil {
+		logger.Error("Failed applying host ACLs", logger.Ctx{"err": err})
+	}
+
 	// Load instance placement scriptlet.
 	if instancePlacementScriptlet != "" {
 		err = scriptletLoad.InstancePlacementSet(instancePlacementScriptlet)
@@ -1709,8 +1732,17 @@ func (d *Daemon) init() error {
 		// Auto-renew server certificate (daily)
 		d.tasks.Add(autoRenewCertificateTask(d))
 
+		// Auto-renew proxy device certificates (daily)
+		d.tasks.Add(autoRenewProxyCertificatesTask(d))
+
 		// Remove expired tokens (hourly)
 		d.tasks.Add(autoRemoveExpiredTokensTask(d))
+
+		// Roll over network zone DNSSEC keys (daily)
+		d.tasks.Add(rotateNetworkZoneKeysTask(d))
+
+		// Account project network traffic and apply project network limits (minutely)
+		d.tasks.Add(projectNetworkTask(d))
 	}
 
 	// Start all background tasks
@@ -2298,7 +2330,9 @@ func (d *Daemon) setupSyslogSocket(enable bool) error {
 
 	logger.Debug("Starting syslog socket")
 
-	err := syslog.Listen(ctx, d.events)
+	err := syslog.Listen(ctx, d.events, func(message string) (string, map[string]string) {
+		return acl.OVNFlowLogContext(d.State(), message)
+	})
 	if err != nil {
 		return err
 	}
@@ -2493,11 +2527,27 @@ func (d *Daemon) nodeRefreshTask(heartbeatData *cluster.APIHeartbeat, isLeader b
 		logger.Error("Error restarting OVN networks", logger.Ctx{"err": err})
 	}
 
+	// Handle potential OVN gateway cluster group changes.
+	err = networkUpdateOVNGatewayChassis(s, d.lastNodeList, heartbeatData)
+	if err != nil {
+		stateChangeTaskFailure = true
+		logger.Error("Error refreshing OVN gateway chassis", logger.Ctx{"err": err})
+	}
+
 	if d.hasMemberStateChanged(heartbeatData) {
 		logger.Info("Cluster status has changed, refreshing")
 
 		// Refresh cluster certificates cached.
 		updateCertificateCache(d)
+
+		// Refresh the cluster member addresses allowed by the host ACLs.
+		hostACLNames, _, _ := s.GlobalConfig.NetworkHostACLs()
+		if len(hostACLNames) > 0 {
+			err := acl.FirewallApplyHostACLRules(s)
+			if err != nil {
+				logger.Error("Failed applying host ACLs", logger.Ctx{"err": err})
+			}
+		}
 	}
 
 	// Refresh event listeners from heartbeat members (after certificates refreshed if needed).
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/device"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// swagger:operation POST /1.0/instances/{name}/devices/{device}/capture instances instance_device_capture_post
+//
+//	Capture instance NIC traffic
+//
+//	Captures the traffic of an instance NIC on its host-side interface.
+//
+//	The returned operation metadata will contain a websocket streaming the capture in pcap format.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: capture
+//	    description: Capture request
+//	    schema:
+//	      $ref: "#/definitions/NetworkCapturePost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceDeviceCapturePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	deviceName, err := url.PathUnescape(mux.Vars(r)["device"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	dev, ok := inst.ExpandedDevices()[deviceName]
+	if !ok {
+		return response.NotFound(fmt.Errorf("Device %q not found", deviceName))
+	}
+
+	if dev["type"] != "nic" {
+		return response.BadRequest(errors.New("Packet captures are only supported on NIC devices"))
+	}
+
+	if !inst.IsRunning() {
+		return response.BadRequest(errors.New("Instance is not running"))
+	}
+
+	// Only capture on the host side interface the NIC created, never on the user editable volatile one.
+	hostName, err := device.NetworkHostInterface(s, inst, deviceName)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	req := api.NetworkCapturePost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
+
+	return captureStart(s, r, projectName, operationtype.InstanceCapture, resources, hostName, req)
+}
@@ -615,6 +615,11 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 //	    enum: [log, vga]
 //	    default: log
 //	    example: vga
+//	  - in: query
+//	    name: device
+//	    description: Serial device to retrieve the ring buffer of (virtual machines only)
+//	    type: string
+//	    example: serial1
 //	responses:
 //	  "200":
 //	     description: |
@@ -672,6 +677,30 @@ func instanceConsoleLogGet(d *Daemon, r *http.Request) response.Response {
 
 	ent := response.FileResponseEntry{}
 
+	// Return the ring buffer of a serial device.
+	devName := request.QueryParam(r, "device")
+	if devName != "" {
+		if consoleLogType == "vga" {
+			return response.BadRequest(errors.New("The device parameter can't be used with the vga type"))
+		}
+
+		v, ok := inst.(instance.VM)
+		if !ok {
+			return response.BadRequest(errors.New("Serial device logs are only available for virtual machines"))
+		}
+
+		logContents, err := v.SerialLog(devName)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		ent.File = bytes.NewReader([]byte(logContents))
+		ent.FileModified = time.Now()
+		ent.FileSize = int64(len(logContents))
+
+		return response.FileResponse(r, []response.FileResponseEntry{ent}, nil)
+	}
+
 	if !inst.IsRunning() {
 		// Check if we have data we can return.
 		consoleBufferLogPath := inst.ConsoleBufferLogPath()
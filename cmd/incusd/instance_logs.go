@@ -614,7 +614,8 @@ func validLogFileName(fname string) bool {
 	 */
 	return slices.Contains([]string{"lxc.log", "qemu.log", "qemu.early.log", "qemu.qmp.log"}, fname) ||
 		strings.HasPrefix(fname, "migration_") ||
-		strings.HasPrefix(fname, "snapshot_")
+		strings.HasPrefix(fname, "snapshot_") ||
+		strings.HasPrefix(fname, "watchdog_")
 }
 
 func validExecOutputFileName(fName string) bool {
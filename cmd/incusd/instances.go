@@ -167,6 +167,13 @@ var instanceAccessCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: instanceAccess, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanView, "name")},
 }
 
+var instanceDeviceCaptureCmd = APIEndpoint{
+	Name: "instanceDeviceCapture",
+	Path: "instances/{name}/devices/{device}/capture",
+
+	Post: APIEndpointAction{Handler: instanceDeviceCapturePost, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
 var instanceDebugMemoryCmd = APIEndpoint{
 	Name: "instanceDebugMemory",
 	Path: "instances/{name}/debug/memory",
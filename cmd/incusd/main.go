@@ -150,6 +150,10 @@ func main() {
 	forkmountCmd := cmdForkmount{global: &globalCmd}
 	app.AddCommand(forkmountCmd.command())
 
+	// forknat64 sub-command
+	forknat64Cmd := cmdForknat64{global: &globalCmd}
+	app.AddCommand(forknat64Cmd.command())
+
 	// forknet sub-command
 	forknetCmd := cmdForknet{global: &globalCmd}
 	app.AddCommand(forknetCmd.command())
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/internal/server/network/dns64"
+	"github.com/lxc/incus/v6/internal/server/network/nat64"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+type cmdForknat64 struct {
+	global *cmdGlobal
+}
+
+func (c *cmdForknat64) command() *cobra.Command {
+	// Main subcommand
+	cmd := &cobra.Command{}
+	cmd.Use = "forknat64 <device> <prefix> <pool> <dns64>"
+	cmd.Short = "Translate traffic between IPv6 clients and IPv4 destinations"
+	cmd.Long = `Description:
+  Translate traffic between IPv6 clients and IPv4 destinations
+
+  This internal command is used to run the NAT64 translator of a network.
+  The translator exchanges packets with the host through a TUN device and
+  the IPv4 destinations are mapped into the NAT64 prefix.
+
+  When dns64 is true, a DNS64 proxy is also served on the UDP and TCP
+  sockets inherited as file descriptors 3 and 4.
+`
+	cmd.RunE = c.run
+	cmd.Hidden = true
+
+	return cmd
+}
+
+func (c *cmdForknat64) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	if len(args) != 4 {
+		_ = cmd.Help()
+
+		if len(args) == 0 {
+			return nil
+		}
+
+		return errors.New("Missing required arguments")
+	}
+
+	// Only root should run this
+	if os.Geteuid() != 0 {
+		return errors.New("This must be run as root")
+	}
+
+	device := args[0]
+
+	_, prefix, err := net.ParseCIDR(args[1])
+	if err != nil {
+		return fmt.Errorf("Invalid NAT64 prefix: %w", err)
+	}
+
+	_, pool, err := net.ParseCIDR(args[2])
+	if err != nil {
+		return fmt.Errorf("Invalid NAT64 pool: %w", err)
+	}
+
+	translator, err := nat64.NewTranslator(nat64.Config{Prefix: prefix, Pool: pool})
+	if err != nil {
+		return err
+	}
+
+	// The device and its routes are removed when this process exits.
+	tun, err := nat64.OpenTUN(device)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = tun.Close() }()
+
+	link := &ip.Link{Name: device}
+	err = link.SetUp()
+	if err != nil {
+		return err
+	}
+
+	for _, route := range []*ip.Route{
+		{DevName: device, Route: pool, Proto: "static", Family: ip.FamilyV4},
+		{DevName: device, Route: prefix, Proto: "static", Family: ip.FamilyV6},
+	} {
+		err = route.Replace()
+		if err != nil {
+			return fmt.Errorf("Failed adding NAT64 route %q: %w", route.Route.String(), err)
+		}
+	}
+
+	errCh := make(chan error, 2)
+
+	if util.IsTrue(args[3]) {
+		proxy, err := dns64.NewProxy(prefix)
+		if err != nil {
+			return err
+		}
+
+		udpConn, err := net.FilePacketConn(os.NewFile(3, "dns64-udp"))
+		if err != nil {
+			return fmt.Errorf("Failed to use DNS64 UDP socket: %w", err)
+		}
+
+		tcpListener, err := net.FileListener(os.NewFile(4, "dns64-tcp"))
+		if err != nil {
+			return fmt.Errorf("Failed to use DNS64 TCP socket: %w", err)
+		}
+
+		go func() {
+			errCh <- proxy.Serve(udpConn, tcpListener)
+		}()
+	}
+
+	go func() {
+		errCh <- translator.Run(tun)
+	}()
+
+	// This line is used by the daemon to check forknat64 has started OK.
+	fmt.Println("Status: Started")
+
+	return <-errCh
+}
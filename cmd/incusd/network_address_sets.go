@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 
 	"github.com/gorilla/mux"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
@@ -302,6 +304,11 @@ func networkAddressSetsPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("The network address set already exists"))
 	}
 
+	err = networkAddressSetSelectorAccessCheck(s, r, projectName, req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	err = addressset.Create(s, projectName, &req)
 	if err != nil {
 		return response.SmartError(err)
@@ -563,6 +570,13 @@ func networkAddressSetPut(d *Daemon, r *http.Request) response.Response {
 
 	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
 
+	if clientType == clusterRequest.ClientTypeNormal {
+		err = networkAddressSetSelectorAccessCheck(s, r, projectName, req.Config)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	err = netAddrSet.Update(&req, clientType)
 	if err != nil {
 		return response.SmartError(err)
@@ -648,3 +662,74 @@ func networkAddressSetPost(d *Daemon, r *http.Request) response.Response {
 
 	return response.SyncResponseLocation(true, nil, lc.Source)
 }
+
+// networkAddressSetSelectorAccessCheck checks that the requestor can view the project whose instances are selected
+// by the address set, so that the set can't be used to list the addresses of instances of other projects.
+func networkAddressSetSelectorAccessCheck(s *state.State, r *http.Request, projectName string, config map[string]string) error {
+	selectorProject := config["selector.project"]
+	if selectorProject == "" || selectorProject == projectName {
+		return nil
+	}
+
+	err := s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectProject(selectorProject), auth.EntitlementCanView)
+	if err != nil {
+		return api.StatusErrorf(http.StatusForbidden, "Not allowed to select the instances of project %q", selectorProject)
+	}
+
+	return nil
+}
+
+// networkAddressSetSelectorActions are the lifecycle actions which can change the instances selected by address sets.
+var networkAddressSetSelectorActions = []string{
+	api.EventLifecycleInstanceCreated,
+	api.EventLifecycleInstanceDeleted,
+	api.EventLifecycleInstanceRenamed,
+	api.EventLifecycleInstanceRestarted,
+	api.EventLifecycleInstanceStarted,
+	api.EventLifecycleInstanceStopped,
+	api.EventLifecycleInstanceUpdated,
+	api.EventLifecycleProfileUpdated,
+}
+
+// networkAddressSetSelectorsHandler refreshes the address sets selecting instances when a local instance changes.
+func networkAddressSetSelectorsHandler(s *state.State, event api.Event) {
+	if event.Type != api.EventTypeLifecycle {
+		return
+	}
+
+	lifecycleEvent := api.EventLifecycle{}
+
+	err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+	if err != nil {
+		return
+	}
+
+	if !slices.Contains(networkAddressSetSelectorActions, lifecycleEvent.Action) {
+		return
+	}
+
+	projectName := lifecycleEvent.Project
+	if projectName == "" {
+		projectName = event.Project
+	}
+
+	if projectName == "" {
+		projectName = api.ProjectDefaultName
+	}
+
+	// Only re-evaluate the instance the event relates to. Profile changes can affect any instance of the project.
+	var instNames []string
+	if lifecycleEvent.Action != api.EventLifecycleProfileUpdated && lifecycleEvent.Name != "" {
+		instNames = append(instNames, lifecycleEvent.Name)
+
+		oldName, ok := lifecycleEvent.Context["old_name"].(string)
+		if ok && oldName != "" {
+			instNames = append(instNames, oldName)
+		}
+	}
+
+	err = addressset.RefreshSelectors(s, projectName, instNames...)
+	if err != nil {
+		logger.Warn("Failed refreshing network address set selectors", logger.Ctx{"project": projectName, "err": err})
+	}
+}
@@ -183,6 +183,17 @@ func networkAllocationsGet(d *Daemon, r *http.Request) response.Response {
 						NAT:     nat,
 					})
 				}
+
+				// Delegated prefixes are routed to the instance, without going through NAT.
+				if lease.Type == "delegated" && lease.Hostname != "" {
+					result = append(result, api.NetworkAllocations{
+						Address: lease.Address,
+						UsedBy:  api.NewURL().Path(version.APIVersion, "instances", lease.Hostname).Project(projectName).String(),
+						Type:    "instance",
+						Hwaddr:  lease.Hwaddr,
+						NAT:     false,
+					})
+				}
 			}
 
 			var forwards map[int64]*api.NetworkForward
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/jmap"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/network"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/ws"
+)
+
+type captureWs struct {
+	// host interface to capture on
+	iface string
+
+	// capture request
+	req api.NetworkCapturePost
+
+	// secret needed to connect to the data websocket
+	secret string
+
+	// websocket connection the capture is streamed to
+	conn *websocket.Conn
+
+	// lock needed to access the "conn" member
+	connLock sync.Mutex
+
+	// channel closed once the websocket is connected
+	connected chan struct{}
+
+	// context of the capture, cancelled when the operation is
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (c *captureWs) metadata() any {
+	return jmap.Map{"fds": jmap.Map{"0": c.secret}}
+}
+
+func (c *captureWs) connect(op *operations.Operation, r *http.Request, w http.ResponseWriter) error {
+	// Check that the user connecting is the same who started the capture.
+	if !op.IsSameRequestor(r) {
+		return api.StatusErrorf(http.StatusForbidden, "Requestor mismatch")
+	}
+
+	secret := r.FormValue("secret")
+	if secret == "" {
+		return errors.New("missing secret")
+	}
+
+	// If we didn't find the right secret, the user provided a bad one,
+	// which 403, not 404, since this operation actually exists.
+	if secret != c.secret {
+		return os.ErrPermission
+	}
+
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+
+	if c.conn != nil {
+		return api.StatusErrorf(http.StatusConflict, "Capture websocket is already connected")
+	}
+
+	conn, err := ws.Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return err
+	}
+
+	c.conn = conn
+	close(c.connected)
+
+	return nil
+}
+
+func (c *captureWs) do(op *operations.Operation) error {
+	defer c.cancel()
+
+	// Wait for the client to connect.
+	select {
+	case <-c.connected:
+	case <-c.ctx.Done():
+		return nil
+	}
+
+	defer func() { _ = c.conn.Close() }()
+
+	ctx := c.ctx
+	if c.req.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(c.req.Duration)*time.Second)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, "tcpdump", captureArgs(c.iface, c.req, "-U", "-w", "-")...)
+	cmd.Cancel = func() error {
+		// Let tcpdump flush the captured packets.
+		return cmd.Process.Signal(unix.SIGTERM)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return fmt.Errorf("Failed starting packet capture: %w", err)
+	}
+
+	logger.Debug("Started packet capture", logger.Ctx{"interface": c.iface, "filter": c.req.Filter})
+
+	// Stream the capture to the client and stop it if the client goes away.
+	err = <-ws.MirrorRead(c.conn, stdout)
+	if err != nil {
+		c.cancel()
+	}
+
+	err = cmd.Wait()
+
+	logger.Debug("Finished packet capture", logger.Ctx{"interface": c.iface, "err": err})
+
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("Failed packet capture: %s", captureError(stderr.String(), err))
+	}
+
+	return nil
+}
+
+func (c *captureWs) cancelCapture(op *operations.Operation) error {
+	c.cancel()
+
+	return nil
+}
+
+// captureArgs returns the tcpdump arguments for the capture request.
+func captureArgs(iface string, req api.NetworkCapturePost, extraArgs ...string) []string {
+	args := []string{"-i", iface}
+	args = append(args, extraArgs...)
+
+	if req.Count > 0 {
+		args = append(args, "-c", strconv.Itoa(req.Count))
+	}
+
+	// Make sure the filter can't be interpreted as tcpdump options.
+	if req.Filter != "" {
+		args = append(args, "--", req.Filter)
+	}
+
+	return args
+}
+
+// captureValidateFilter checks that the capture filter looks like a filter expression rather than tcpdump options.
+func captureValidateFilter(filter string) error {
+	if strings.HasPrefix(strings.TrimSpace(filter), "-") {
+		return fmt.Errorf("Invalid packet capture filter %q", filter)
+	}
+
+	return nil
+}
+
+// captureError extracts the error message printed by tcpdump.
+func captureError(stderr string, err error) string {
+	for _, line := range strings.Split(strings.TrimSpace(stderr), "\n") {
+		msg, ok := strings.CutPrefix(line, "tcpdump: ")
+		if ok && !strings.HasPrefix(msg, "listening on") {
+			return msg
+		}
+	}
+
+	return err.Error()
+}
+
+// captureStart validates the capture request and starts an operation streaming the capture of the interface.
+func captureStart(s *state.State, r *http.Request, projectName string, opType operationtype.Type, resources map[string][]api.URL, iface string, req api.NetworkCapturePost) response.Response {
+	if req.Duration < 0 {
+		return response.BadRequest(errors.New("Capture duration must be positive"))
+	}
+
+	if req.Count < 0 {
+		return response.BadRequest(errors.New("Capture packet count must be positive"))
+	}
+
+	err := captureValidateFilter(req.Filter)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	_, err = exec.LookPath("tcpdump")
+	if err != nil {
+		return response.InternalError(errors.New("Packet captures require tcpdump to be installed on the server"))
+	}
+
+	// Validate the filter against the interface by compiling it.
+	_, err = subprocess.RunCommand("tcpdump", captureArgs(iface, api.NetworkCapturePost{Filter: req.Filter}, "-d")...)
+	if err != nil {
+		var runErr subprocess.RunError
+		if errors.As(err, &runErr) {
+			return response.BadRequest(fmt.Errorf("Invalid packet capture: %s", captureError(runErr.StdErr().String(), err)))
+		}
+
+		return response.SmartError(err)
+	}
+
+	capture := &captureWs{
+		iface:     iface,
+		req:       req,
+		connected: make(chan struct{}),
+	}
+
+	capture.ctx, capture.cancel = context.WithCancel(context.Background())
+
+	capture.secret, err = internalUtil.RandomHexString(32)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassWebsocket, opType, resources, capture.metadata(), capture.do, capture.cancelCapture, capture.connect, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// swagger:operation POST /1.0/networks/{name}/capture networks network_capture_post
+//
+//	Capture network traffic
+//
+//	Captures the traffic of a bridge network on the target cluster member.
+//
+//	The returned operation metadata will contain a websocket streaming the capture in pcap format.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	  - in: body
+//	    name: capture
+//	    description: Capture request
+//	    schema:
+//	      $ref: "#/definitions/NetworkCapturePost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkCapturePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	projectName, reqProject, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	networkName, err := url.PathUnescape(mux.Vars(r)["networkName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	n, err := network.LoadByName(s, projectName, networkName)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading network: %w", err))
+	}
+
+	// Check if project allows access to network.
+	if !project.NetworkAllowed(reqProject.Config, networkName, n.IsManaged()) {
+		return response.SmartError(api.StatusErrorf(http.StatusNotFound, "Network not found"))
+	}
+
+	if n.Type() != "bridge" {
+		return response.BadRequest(errors.New("Packet captures are only supported on bridge networks"))
+	}
+
+	if !util.PathExists(fmt.Sprintf("/sys/class/net/%s", n.Name())) {
+		return response.BadRequest(fmt.Errorf("Network %q isn't available on this server", n.Name()))
+	}
+
+	req := api.NetworkCapturePost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["networks"] = []api.URL{*api.NewURL().Path(version.APIVersion, "networks", n.Name())}
+
+	return captureStart(s, r, projectName, operationtype.NetworkCapture, resources, n.Name(), req)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Filters which look like tcpdump options are rejected.
+func TestCaptureValidateFilter(t *testing.T) {
+	tests := []struct {
+		filter  string
+		wantErr bool
+	}{
+		{filter: ""},
+		{filter: "tcp port 80"},
+		{filter: "len - 4 > 100"},
+		{filter: "-w/etc/cron.d/x", wantErr: true},
+		{filter: "-z /bin/sh", wantErr: true},
+		{filter: "  -Z root", wantErr: true},
+		{filter: "--version", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := captureValidateFilter(tt.filter)
+		if tt.wantErr {
+			assert.Error(t, err, tt.filter)
+		} else {
+			assert.NoError(t, err, tt.filter)
+		}
+	}
+}
+
+// The filter is always passed after the end of the tcpdump options.
+func TestCaptureArgs(t *testing.T) {
+	args := captureArgs("eth0", api.NetworkCapturePost{Filter: "-w/tmp/x", Count: 10}, "-U")
+	assert.Equal(t, []string{"-i", "eth0", "-U", "-c", "10", "--", "-w/tmp/x"}, args)
+
+	args = captureArgs("eth0", api.NetworkCapturePost{})
+	assert.Equal(t, []string{"-i", "eth0"}, args)
+}
@@ -0,0 +1,643 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/filter"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	clusterRequest "github.com/lxc/incus/v6/internal/server/cluster/request"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/network"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+var networkFloatingIPsCmd = APIEndpoint{
+	Path: "networks/{networkName}/floating-ips",
+
+	Get:  APIEndpointAction{Handler: networkFloatingIPsGet, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanView, "networkName")},
+	Post: APIEndpointAction{Handler: networkFloatingIPsPost, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
+}
+
+var networkFloatingIPCmd = APIEndpoint{
+	Path: "networks/{networkName}/floating-ips/{address}",
+
+	Delete: APIEndpointAction{Handler: networkFloatingIPDelete, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
+	Get:    APIEndpointAction{Handler: networkFloatingIPGet, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanView, "networkName")},
+	Put:    APIEndpointAction{Handler: networkFloatingIPPut, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
+	Patch:  APIEndpointAction{Handler: networkFloatingIPPut, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
+}
+
+// networkFloatingIPLoad loads the network from the request and checks it supports floating IPs.
+// It returns the project of the network along with the project of the request, which is the project the
+// instances targeted by floating IPs belong to.
+func networkFloatingIPLoad(d *Daemon, r *http.Request) (string, string, network.Network, error) {
+	s := d.State()
+
+	projectName, reqProject, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	networkName, err := url.PathUnescape(mux.Vars(r)["networkName"])
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	n, err := network.LoadByName(s, projectName, networkName)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("Failed loading network: %w", err)
+	}
+
+	// Check if project allows access to network.
+	if !project.NetworkAllowed(reqProject.Config, networkName, n.IsManaged()) {
+		return "", "", nil, api.StatusErrorf(http.StatusNotFound, "Network not found")
+	}
+
+	if !n.Info().FloatingIPs {
+		return "", "", nil, api.StatusErrorf(http.StatusBadRequest, "Network driver %q does not support floating IPs", n.Type())
+	}
+
+	return projectName, reqProject.Name, n, nil
+}
+
+// API endpoints
+
+// swagger:operation GET /1.0/networks/{networkName}/floating-ips network-floating-ips network_floating_ips_get
+//
+//  Get the network floating IPs
+//
+//  Returns a list of network floating IPs (URLs).
+//
+//  ---
+//  produces:
+//    - application/json
+//  parameters:
+//    - in: query
+//      name: project
+//      description: Project name
+//      type: string
+//      example: default
+//    - in: query
+//      name: filter
+//      description: Collection filter
+//      type: string
+//      example: default
+//  responses:
+//    "200":
+//      description: API endpoints
+//      schema:
+//        type: object
+//        description: Sync response
+//        properties:
+//          type:
+//            type: string
+//            description: Response type
+//            example: sync
+//          status:
+//            type: string
+//            description: Status description
+//            example: Success
+//          status_code:
+//            type: integer
+//            description: Status code
+//            example: 200
+//          metadata:
+//            type: array
+//            description: List of endpoints
+//            items:
+//              type: string
+//            example: |-
+//              [
+//                "/1.0/networks/mybr0/floating-ips/192.0.2.10",
+//                "/1.0/networks/mybr0/floating-ips/2001:db8::10"
+//              ]
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/networks/{networkName}/floating-ips?recursion=1 network-floating-ips network_floating_ips_get_recursion1
+//
+//  Get the network floating IPs
+//
+//  Returns a list of network floating IPs (structs).
+//
+//  ---
+//  produces:
+//    - application/json
+//  parameters:
+//    - in: query
+//      name: project
+//      description: Project name
+//      type: string
+//      example: default
+//    - in: query
+//      name: filter
+//      description: Collection filter
+//      type: string
+//      example: default
+//  responses:
+//    "200":
+//      description: API endpoints
+//      schema:
+//        type: object
+//        description: Sync response
+//        properties:
+//          type:
+//            type: string
+//            description: Response type
+//            example: sync
+//          status:
+//            type: string
+//            description: Status description
+//            example: Success
+//          status_code:
+//            type: integer
+//            description: Status code
+//            example: 200
+//          metadata:
+//            type: array
+//            description: List of network floating IPs
+//            items:
+//              $ref: "#/definitions/NetworkFloatingIP"
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+func networkFloatingIPsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, reqProjectName, n, err := networkFloatingIPLoad(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	// Parse filter value.
+	filterStr := r.FormValue("filter")
+	clauses, err := filter.Parse(filterStr, filter.QueryOperatorSet())
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid filter: %w", err))
+	}
+
+	mustLoadObjects := recursion || (clauses != nil && len(clauses.Clauses) > 0)
+
+	linkResults := make([]string, 0)
+	fullResults := make([]api.NetworkFloatingIP, 0)
+
+	var records []*api.NetworkFloatingIP
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkID := n.ID()
+		dbRecords, err := dbCluster.GetNetworkFloatingIPs(ctx, tx.Tx(), dbCluster.NetworkFloatingIPFilter{
+			NetworkID: &networkID,
+		})
+		if err != nil {
+			return err
+		}
+
+		records = make([]*api.NetworkFloatingIP, 0, len(dbRecords))
+		for _, dbRecord := range dbRecords {
+			// Only show the floating IPs of the request's project when the network is shared.
+			if reqProjectName != projectName && dbRecord.Project != reqProjectName {
+				continue
+			}
+
+			if !mustLoadObjects {
+				records = append(records, &api.NetworkFloatingIP{Address: dbRecord.Address})
+				continue
+			}
+
+			floatingIP, err := dbRecord.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			err = networkFloatingIPFillLocation(ctx, tx, s.ServerClustered, floatingIP)
+			if err != nil {
+				return err
+			}
+
+			records = append(records, floatingIP)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading network floating IPs: %w", err))
+	}
+
+	for _, record := range records {
+		if clauses != nil && len(clauses.Clauses) > 0 {
+			match, err := filter.Match(*record, *clauses)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			if !match {
+				continue
+			}
+		}
+
+		fullResults = append(fullResults, *record)
+		linkResults = append(linkResults, fmt.Sprintf("/%s/networks/%s/floating-ips/%s", version.APIVersion, url.PathEscape(n.Name()), url.PathEscape(record.Address)))
+	}
+
+	if recursion {
+		return response.SyncResponse(true, fullResults)
+	}
+
+	return response.SyncResponse(true, linkResults)
+}
+
+// swagger:operation POST /1.0/networks/{networkName}/floating-ips network-floating-ips network_floating_ips_post
+//
+//	Add a network floating IP
+//
+//	Creates a new network floating IP.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: floatingIP
+//	    description: Floating IP
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NetworkFloatingIPsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkFloatingIPsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	// Parse the request into a record.
+	req := api.NetworkFloatingIPsPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	req.Normalise() // So we handle the request in normalised/canonical form.
+
+	projectName, reqProjectName, n, err := networkFloatingIPLoad(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
+
+	err = n.FloatingIPCreate(reqProjectName, req, clientType)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed creating floating IP: %w", err))
+	}
+
+	lc := lifecycle.NetworkFloatingIPCreated.Event(n, req.Address, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(projectName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/networks/{networkName}/floating-ips/{address} network-floating-ips network_floating_ip_delete
+//
+//	Delete the network floating IP
+//
+//	Removes the network floating IP.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkFloatingIPDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	projectName, reqProjectName, n, err := networkFloatingIPLoad(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	address, err := url.PathUnescape(mux.Vars(r)["address"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	_, err = networkFloatingIPGetByAddress(r.Context(), s, projectName, reqProjectName, n.ID(), address)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
+
+	err = n.FloatingIPDelete(address, clientType)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed deleting floating IP: %w", err))
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.NetworkFloatingIPDeleted.Event(n, address, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/networks/{networkName}/floating-ips/{address} network-floating-ips network_floating_ip_get
+//
+//	Get the network floating IP
+//
+//	Gets a specific network floating IP.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: floating IP
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/NetworkFloatingIP"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkFloatingIPGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	projectName, reqProjectName, n, err := networkFloatingIPLoad(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	address, err := url.PathUnescape(mux.Vars(r)["address"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	floatingIP, err := networkFloatingIPGetByAddress(r.Context(), s, projectName, reqProjectName, n.ID(), address)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, floatingIP, floatingIP.Etag())
+}
+
+// swagger:operation PATCH /1.0/networks/{networkName}/floating-ips/{address} network-floating-ips network_floating_ip_patch
+//
+//  Partially update the network floating IP
+//
+//  Updates a subset of the network floating IP configuration.
+//
+//  ---
+//  consumes:
+//    - application/json
+//  produces:
+//    - application/json
+//  parameters:
+//    - in: query
+//      name: project
+//      description: Project name
+//      type: string
+//      example: default
+//    - in: body
+//      name: floatingIP
+//      description: floating IP configuration
+//      required: true
+//      schema:
+//        $ref: "#/definitions/NetworkFloatingIPPut"
+//  responses:
+//    "200":
+//      $ref: "#/responses/EmptySyncResponse"
+//    "400":
+//      $ref: "#/responses/BadRequest"
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "412":
+//      $ref: "#/responses/PreconditionFailed"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+// swagger:operation PUT /1.0/networks/{networkName}/floating-ips/{address} network-floating-ips network_floating_ip_put
+//
+//	Update the network floating IP
+//
+//	Updates the entire network floating IP configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: floatingIP
+//	    description: floating IP configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NetworkFloatingIPPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkFloatingIPPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	projectName, reqProjectName, n, err := networkFloatingIPLoad(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	address, err := url.PathUnescape(mux.Vars(r)["address"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	floatingIP, err := networkFloatingIPGetByAddress(r.Context(), s, projectName, reqProjectName, n.ID(), address)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Decode the request.
+	req := api.NetworkFloatingIPPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if r.Method == http.MethodPatch {
+		// If config being updated via "patch" method, then merge all existing config with the keys that
+		// are present in the request config.
+		if req.Config == nil {
+			req.Config = map[string]string{}
+		}
+
+		for k, v := range floatingIP.Config {
+			_, ok := req.Config[k]
+			if !ok {
+				req.Config[k] = v
+			}
+		}
+
+		// Keep the existing fields that aren't specified in the request.
+		if req.Description == "" {
+			req.Description = floatingIP.Description
+		}
+
+		if req.Instance == "" {
+			req.Instance = floatingIP.Instance
+		}
+	}
+
+	req.Normalise() // So we handle the request in normalised/canonical form.
+
+	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
+
+	err = n.FloatingIPUpdate(address, req, clientType)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed updating floating IP: %w", err))
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.NetworkFloatingIPUpdated.Event(n, address, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// networkFloatingIPGetByAddress returns the floating IP of the network with the given address.
+// Floating IPs of other projects are hidden when the network is shared with the request's project.
+func networkFloatingIPGetByAddress(ctx context.Context, s *state.State, projectName string, reqProjectName string, networkID int64, address string) (*api.NetworkFloatingIP, error) {
+	var floatingIP *api.NetworkFloatingIP
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbRecord, err := dbCluster.GetNetworkFloatingIP(ctx, tx.Tx(), networkID, address)
+		if err != nil {
+			return err
+		}
+
+		if reqProjectName != projectName && dbRecord.Project != reqProjectName {
+			return api.StatusErrorf(http.StatusNotFound, "Network floating IP not found")
+		}
+
+		floatingIP, err = dbRecord.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		return networkFloatingIPFillLocation(ctx, tx, s.ServerClustered, floatingIP)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return floatingIP, nil
+}
+
+// networkFloatingIPFillLocation sets the location of the floating IP to the cluster member hosting its instance.
+func networkFloatingIPFillLocation(ctx context.Context, tx *db.ClusterTx, clustered bool, floatingIP *api.NetworkFloatingIP) error {
+	if !clustered || floatingIP.Instance == "" {
+		return nil
+	}
+
+	instances, err := dbCluster.GetInstances(ctx, tx.Tx(), dbCluster.InstanceFilter{Project: &floatingIP.Project, Name: &floatingIP.Instance})
+	if err != nil {
+		return err
+	}
+
+	if len(instances) > 0 {
+		floatingIP.Location = instances[0].Node
+	}
+
+	return nil
+}
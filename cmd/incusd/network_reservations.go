@@ -0,0 +1,611 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/filter"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	clusterRequest "github.com/lxc/incus/v6/internal/server/cluster/request"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/network"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+var networkReservationsCmd = APIEndpoint{
+	Path: "networks/{networkName}/reservations",
+
+	Get:  APIEndpointAction{Handler: networkReservationsGet, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanView, "networkName")},
+	Post: APIEndpointAction{Handler: networkReservationsPost, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
+}
+
+var networkReservationCmd = APIEndpoint{
+	Path: "networks/{networkName}/reservations/{reservationName}",
+
+	Delete: APIEndpointAction{Handler: networkReservationDelete, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
+	Get:    APIEndpointAction{Handler: networkReservationGet, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanView, "networkName")},
+	Put:    APIEndpointAction{Handler: networkReservationPut, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
+	Patch:  APIEndpointAction{Handler: networkReservationPut, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
+}
+
+// networkReservationLoad loads the network from the request and checks it supports DHCP reservations.
+func networkReservationLoad(d *Daemon, r *http.Request) (string, network.Network, error) {
+	s := d.State()
+
+	projectName, reqProject, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return "", nil, err
+	}
+
+	networkName, err := url.PathUnescape(mux.Vars(r)["networkName"])
+	if err != nil {
+		return "", nil, err
+	}
+
+	n, err := network.LoadByName(s, projectName, networkName)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed loading network: %w", err)
+	}
+
+	// Check if project allows access to network.
+	if !project.NetworkAllowed(reqProject.Config, networkName, n.IsManaged()) {
+		return "", nil, api.StatusErrorf(http.StatusNotFound, "Network not found")
+	}
+
+	if !n.Info().DHCPReservations {
+		return "", nil, api.StatusErrorf(http.StatusBadRequest, "Network driver %q does not support DHCP reservations", n.Type())
+	}
+
+	return projectName, n, nil
+}
+
+// API endpoints
+
+// swagger:operation GET /1.0/networks/{networkName}/reservations network-reservations network_reservations_get
+//
+//  Get the network DHCP reservations
+//
+//  Returns a list of network DHCP reservations (URLs).
+//
+//  ---
+//  produces:
+//    - application/json
+//  parameters:
+//    - in: query
+//      name: project
+//      description: Project name
+//      type: string
+//      example: default
+//    - in: query
+//      name: filter
+//      description: Collection filter
+//      type: string
+//      example: default
+//  responses:
+//    "200":
+//      description: API endpoints
+//      schema:
+//        type: object
+//        description: Sync response
+//        properties:
+//          type:
+//            type: string
+//            description: Response type
+//            example: sync
+//          status:
+//            type: string
+//            description: Status description
+//            example: Success
+//          status_code:
+//            type: integer
+//            description: Status code
+//            example: 200
+//          metadata:
+//            type: array
+//            description: List of endpoints
+//            items:
+//              type: string
+//            example: |-
+//              [
+//                "/1.0/networks/mybr0/reservations/printer01",
+//                "/1.0/networks/mybr0/reservations/nas01"
+//              ]
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/networks/{networkName}/reservations?recursion=1 network-reservations network_reservations_get_recursion1
+//
+//  Get the network DHCP reservations
+//
+//  Returns a list of network DHCP reservations (structs).
+//
+//  ---
+//  produces:
+//    - application/json
+//  parameters:
+//    - in: query
+//      name: project
+//      description: Project name
+//      type: string
+//      example: default
+//    - in: query
+//      name: filter
+//      description: Collection filter
+//      type: string
+//      example: default
+//  responses:
+//    "200":
+//      description: API endpoints
+//      schema:
+//        type: object
+//        description: Sync response
+//        properties:
+//          type:
+//            type: string
+//            description: Response type
+//            example: sync
+//          status:
+//            type: string
+//            description: Status description
+//            example: Success
+//          status_code:
+//            type: integer
+//            description: Status code
+//            example: 200
+//          metadata:
+//            type: array
+//            description: List of network DHCP reservations
+//            items:
+//              $ref: "#/definitions/NetworkReservation"
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+func networkReservationsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	_, n, err := networkReservationLoad(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	// Parse filter value.
+	filterStr := r.FormValue("filter")
+	clauses, err := filter.Parse(filterStr, filter.QueryOperatorSet())
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid filter: %w", err))
+	}
+
+	mustLoadObjects := recursion || (clauses != nil && len(clauses.Clauses) > 0)
+
+	linkResults := make([]string, 0)
+	fullResults := make([]api.NetworkReservation, 0)
+
+	var records []*api.NetworkReservation
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkID := n.ID()
+		dbRecords, err := dbCluster.GetNetworkReservations(ctx, tx.Tx(), dbCluster.NetworkReservationFilter{
+			NetworkID: &networkID,
+		})
+		if err != nil {
+			return err
+		}
+
+		records = make([]*api.NetworkReservation, 0, len(dbRecords))
+		for _, dbRecord := range dbRecords {
+			if !mustLoadObjects {
+				records = append(records, &api.NetworkReservation{Name: dbRecord.Name})
+				continue
+			}
+
+			reservation, err := dbRecord.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			records = append(records, reservation)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading network DHCP reservations: %w", err))
+	}
+
+	for _, record := range records {
+		if clauses != nil && len(clauses.Clauses) > 0 {
+			match, err := filter.Match(*record, *clauses)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			if !match {
+				continue
+			}
+		}
+
+		fullResults = append(fullResults, *record)
+		linkResults = append(linkResults, fmt.Sprintf("/%s/networks/%s/reservations/%s", version.APIVersion, url.PathEscape(n.Name()), url.PathEscape(record.Name)))
+	}
+
+	if recursion {
+		return response.SyncResponse(true, fullResults)
+	}
+
+	return response.SyncResponse(true, linkResults)
+}
+
+// swagger:operation POST /1.0/networks/{networkName}/reservations network-reservations network_reservations_post
+//
+//	Add a network DHCP reservation
+//
+//	Creates a new network DHCP reservation.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: reservation
+//	    description: Reservation
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NetworkReservationsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkReservationsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	// Parse the request into a record.
+	req := api.NetworkReservationsPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	req.Normalise() // So we handle the request in normalised/canonical form.
+
+	projectName, n, err := networkReservationLoad(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
+
+	err = n.ReservationCreate(req, clientType)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed creating DHCP reservation: %w", err))
+	}
+
+	lc := lifecycle.NetworkReservationCreated.Event(n, req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(projectName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/networks/{networkName}/reservations/{reservationName} network-reservations network_reservation_delete
+//
+//	Delete the network DHCP reservation
+//
+//	Removes the network DHCP reservation.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkReservationDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	projectName, n, err := networkReservationLoad(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	reservationName, err := url.PathUnescape(mux.Vars(r)["reservationName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
+
+	err = n.ReservationDelete(reservationName, clientType)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed deleting DHCP reservation: %w", err))
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.NetworkReservationDeleted.Event(n, reservationName, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/networks/{networkName}/reservations/{reservationName} network-reservations network_reservation_get
+//
+//	Get the network DHCP reservation
+//
+//	Gets a specific network DHCP reservation.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: DHCP reservation
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/NetworkReservation"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkReservationGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	_, n, err := networkReservationLoad(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	reservationName, err := url.PathUnescape(mux.Vars(r)["reservationName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	reservation, err := networkReservationGetByName(r.Context(), s.DB.Cluster, n.ID(), reservationName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, reservation, reservation.Etag())
+}
+
+// swagger:operation PATCH /1.0/networks/{networkName}/reservations/{reservationName} network-reservations network_reservation_patch
+//
+//  Partially update the network DHCP reservation
+//
+//  Updates a subset of the network DHCP reservation configuration.
+//
+//  ---
+//  consumes:
+//    - application/json
+//  produces:
+//    - application/json
+//  parameters:
+//    - in: query
+//      name: project
+//      description: Project name
+//      type: string
+//      example: default
+//    - in: body
+//      name: reservation
+//      description: DHCP reservation configuration
+//      required: true
+//      schema:
+//        $ref: "#/definitions/NetworkReservationPut"
+//  responses:
+//    "200":
+//      $ref: "#/responses/EmptySyncResponse"
+//    "400":
+//      $ref: "#/responses/BadRequest"
+//    "403":
+//      $ref: "#/responses/Forbidden"
+//    "412":
+//      $ref: "#/responses/PreconditionFailed"
+//    "500":
+//      $ref: "#/responses/InternalServerError"
+
+// swagger:operation PUT /1.0/networks/{networkName}/reservations/{reservationName} network-reservations network_reservation_put
+//
+//	Update the network DHCP reservation
+//
+//	Updates the entire network DHCP reservation configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: reservation
+//	    description: DHCP reservation configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NetworkReservationPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkReservationPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	projectName, n, err := networkReservationLoad(d, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	reservationName, err := url.PathUnescape(mux.Vars(r)["reservationName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Decode the request.
+	req := api.NetworkReservationPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if r.Method == http.MethodPatch {
+		reservation, err := networkReservationGetByName(r.Context(), s.DB.Cluster, n.ID(), reservationName)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// If config being updated via "patch" method, then merge all existing config with the keys that
+		// are present in the request config.
+		if req.Config == nil {
+			req.Config = map[string]string{}
+		}
+
+		for k, v := range reservation.Config {
+			_, ok := req.Config[k]
+			if !ok {
+				req.Config[k] = v
+			}
+		}
+
+		// Keep the existing fields that aren't specified in the request.
+		if req.Description == "" {
+			req.Description = reservation.Description
+		}
+
+		if req.Hwaddr == "" {
+			req.Hwaddr = reservation.Hwaddr
+		}
+
+		if req.DUID == "" {
+			req.DUID = reservation.DUID
+		}
+
+		if req.IPv4Address == "" {
+			req.IPv4Address = reservation.IPv4Address
+		}
+
+		if req.IPv6Address == "" {
+			req.IPv6Address = reservation.IPv6Address
+		}
+	}
+
+	req.Normalise() // So we handle the request in normalised/canonical form.
+
+	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
+
+	err = n.ReservationUpdate(reservationName, req, clientType)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed updating DHCP reservation: %w", err))
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.NetworkReservationUpdated.Event(n, reservationName, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// networkReservationGetByName returns the DHCP reservation of the network with the given name.
+func networkReservationGetByName(ctx context.Context, cluster *db.Cluster, networkID int64, name string) (*api.NetworkReservation, error) {
+	var reservation *api.NetworkReservation
+
+	err := cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbRecord, err := dbCluster.GetNetworkReservation(ctx, tx.Tx(), networkID, name)
+		if err != nil {
+			return err
+		}
+
+		reservation, err = dbRecord.ToAPI(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
@@ -0,0 +1,501 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	clusterRequest "github.com/lxc/incus/v6/internal/server/cluster/request"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/network"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+var networkTopologyCmd = APIEndpoint{
+	Path: "network-topology",
+
+	Get: APIEndpointAction{Handler: networkTopologyGet, AccessHandler: allowAuthenticated},
+}
+
+// networkTopologyNetwork holds the records of a network and of its objects.
+type networkTopologyNetwork struct {
+	info          *api.Network
+	peers         []*api.NetworkPeer
+	forwards      []*api.NetworkForward
+	loadBalancers []*api.NetworkLoadBalancer
+}
+
+// networkTopologyNIC holds an instance NIC connected to a network.
+type networkTopologyNIC struct {
+	id             string
+	instanceID     string
+	instance       db.InstanceArgs
+	name           string
+	config         map[string]string
+	networkProject string
+}
+
+// swagger:operation GET /1.0/network-topology networks network_topology_get
+//
+//	Get the network topology
+//
+//	Returns a graph of the networks, their uplinks, peers, integrations, forwards and load balancers, as well as
+//	the instances and NICs connected to them, along with their addresses.
+//
+//	The graph is built from the database (and the network leases for the NIC addresses), so the status of the
+//	networks and peers is the one recorded in the database rather than their live state on each cluster member,
+//	which is available from the network state endpoint.
+//
+//	It isn't served below /1.0/networks as it would conflict with a network named "topology".
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: all-projects
+//	    description: Retrieve the topology of all projects
+//	    type: boolean
+//	responses:
+//	  "200":
+//	    description: Network topology
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/NetworkTopology"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkTopologyGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, reqProject, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	allProjects := util.IsTrue(r.FormValue("all-projects"))
+
+	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), r, auth.EntitlementCanView, auth.ObjectTypeInstance)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	networks := map[string]*networkTopologyNetwork{}
+	integrationTypes := map[string]string{}
+	nics := []*networkTopologyNIC{}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var networkNames map[string][]string
+		if allProjects {
+			networkNames, err = tx.GetNetworksAllProjects(ctx)
+			if err != nil {
+				return err
+			}
+		} else {
+			names, err := tx.GetNetworks(ctx, projectName)
+			if err != nil {
+				return err
+			}
+
+			networkNames = map[string][]string{projectName: names}
+		}
+
+		for networkProject, names := range networkNames {
+			for _, networkName := range names {
+				networkID, info, _, err := tx.GetNetworkInAnyState(ctx, networkProject, networkName)
+				if err != nil {
+					return err
+				}
+
+				info.Project = networkProject
+				entry := &networkTopologyNetwork{info: info}
+
+				peers, err := dbCluster.GetNetworkPeers(ctx, tx.Tx(), dbCluster.NetworkPeerFilter{NetworkID: &networkID})
+				if err != nil {
+					return err
+				}
+
+				for _, peer := range peers {
+					apiPeer, err := peer.ToAPI(ctx, tx.Tx())
+					if err != nil {
+						return err
+					}
+
+					entry.peers = append(entry.peers, apiPeer)
+				}
+
+				forwards, err := dbCluster.GetNetworkForwards(ctx, tx.Tx(), dbCluster.NetworkForwardFilter{NetworkID: &networkID})
+				if err != nil {
+					return err
+				}
+
+				for _, forward := range forwards {
+					apiForward, err := forward.ToAPI(ctx, tx.Tx())
+					if err != nil {
+						return err
+					}
+
+					entry.forwards = append(entry.forwards, apiForward)
+				}
+
+				loadBalancers, err := dbCluster.GetNetworkLoadBalancers(ctx, tx.Tx(), dbCluster.NetworkLoadBalancerFilter{NetworkID: &networkID})
+				if err != nil {
+					return err
+				}
+
+				for _, loadBalancer := range loadBalancers {
+					apiLoadBalancer, err := loadBalancer.ToAPI(ctx, tx.Tx())
+					if err != nil {
+						return err
+					}
+
+					entry.loadBalancers = append(entry.loadBalancers, apiLoadBalancer)
+				}
+
+				networks[networkTopologyNetworkID(networkProject, networkName)] = entry
+			}
+		}
+
+		integrations, err := dbCluster.GetNetworkIntegrations(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, integration := range integrations {
+			integrationTypes[integration.Name] = dbCluster.NetworkIntegrationTypeNames[integration.Type]
+		}
+
+		var filters []dbCluster.InstanceFilter
+		if !allProjects {
+			filters = append(filters, dbCluster.InstanceFilter{Project: &reqProject.Name})
+		}
+
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			if !userHasPermission(auth.ObjectInstance(inst.Project, inst.Name)) {
+				return nil
+			}
+
+			instanceID := api.NewURL().Path(version.APIVersion, "instances", inst.Name).Project(inst.Project).String()
+			networkProject := project.NetworkProjectFromRecord(&p)
+
+			devices := db.ExpandInstanceDevices(inst.Devices.Clone(), inst.Profiles)
+			for _, dev := range devices.Sorted() {
+				if dev.Config["type"] != "nic" {
+					continue
+				}
+
+				nics = append(nics, &networkTopologyNIC{
+					id:             instanceID + "#" + dev.Name,
+					instanceID:     instanceID,
+					instance:       inst,
+					name:           dev.Name,
+					config:         dev.Config,
+					networkProject: networkProject,
+				})
+			}
+
+			return nil
+		}, filters...)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	topology := api.NetworkTopology{
+		Nodes: []api.NetworkTopologyNode{},
+		Edges: []api.NetworkTopologyEdge{},
+	}
+
+	nodeIDs := map[string]bool{}
+	addNode := func(node api.NetworkTopologyNode) {
+		if !nodeIDs[node.ID] {
+			nodeIDs[node.ID] = true
+			topology.Nodes = append(topology.Nodes, node)
+		}
+	}
+
+	// Add the networks the user can access, along with their objects.
+	for id, entry := range networks {
+		ok, err := canAccessNetwork(s, r, entry.info.Project, reqProject.Config, entry.info.Name, true)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if !ok {
+			delete(networks, id)
+			continue
+		}
+
+		addresses := []string{}
+		for _, key := range []string{"ipv4.address", "ipv6.address", "volatile.network.ipv4.address", "volatile.network.ipv6.address"} {
+			address := entry.info.Config[key]
+			if address != "" && address != "none" && address != "auto" {
+				addresses = append(addresses, address)
+			}
+		}
+
+		addNode(api.NetworkTopologyNode{
+			ID:        id,
+			Kind:      "network",
+			Name:      entry.info.Name,
+			Project:   entry.info.Project,
+			Type:      entry.info.Type,
+			Status:    entry.info.Status,
+			Addresses: addresses,
+		})
+
+		if entry.info.Config["network"] != "" {
+			topology.Edges = append(topology.Edges, api.NetworkTopologyEdge{Source: id, Target: networkTopologyNetworkID(api.ProjectDefaultName, entry.info.Config["network"]), Kind: "uplink"})
+		}
+
+		for _, peer := range entry.peers {
+			peerID := api.NewURL().Path(version.APIVersion, "networks", entry.info.Name, "peers", peer.Name).Project(entry.info.Project).String()
+			addNode(api.NetworkTopologyNode{
+				ID:        peerID,
+				Kind:      "network-peer",
+				Name:      peer.Name,
+				Project:   entry.info.Project,
+				Type:      peer.Type,
+				Status:    peer.Status,
+				Addresses: []string{},
+			})
+
+			topology.Edges = append(topology.Edges, api.NetworkTopologyEdge{Source: id, Target: peerID, Kind: "peer"})
+
+			if peer.TargetIntegration != "" {
+				integrationID := api.NewURL().Path(version.APIVersion, "network-integrations", peer.TargetIntegration).String()
+				addNode(api.NetworkTopologyNode{
+					ID:        integrationID,
+					Kind:      "network-integration",
+					Name:      peer.TargetIntegration,
+					Type:      integrationTypes[peer.TargetIntegration],
+					Addresses: []string{},
+				})
+
+				topology.Edges = append(topology.Edges, api.NetworkTopologyEdge{Source: peerID, Target: integrationID, Kind: "integration"})
+			} else if peer.TargetNetwork != "" {
+				topology.Edges = append(topology.Edges, api.NetworkTopologyEdge{Source: peerID, Target: networkTopologyNetworkID(peer.TargetProject, peer.TargetNetwork), Kind: "peer"})
+			}
+		}
+
+		for _, forward := range entry.forwards {
+			forwardURL := api.NewURL().Path(version.APIVersion, "networks", entry.info.Name, "forwards", forward.ListenAddress).Project(entry.info.Project)
+			if forward.Location != "" {
+				forwardURL = forwardURL.Target(forward.Location)
+			}
+
+			addNode(api.NetworkTopologyNode{
+				ID:        forwardURL.String(),
+				Kind:      "network-forward",
+				Name:      forward.ListenAddress,
+				Project:   entry.info.Project,
+				Location:  forward.Location,
+				Addresses: []string{forward.ListenAddress},
+			})
+
+			topology.Edges = append(topology.Edges, api.NetworkTopologyEdge{Source: id, Target: forwardURL.String(), Kind: "forward"})
+		}
+
+		for _, loadBalancer := range entry.loadBalancers {
+			loadBalancerID := api.NewURL().Path(version.APIVersion, "networks", entry.info.Name, "load-balancers", loadBalancer.ListenAddress).Project(entry.info.Project).String()
+			addNode(api.NetworkTopologyNode{
+				ID:        loadBalancerID,
+				Kind:      "network-load-balancer",
+				Name:      loadBalancer.ListenAddress,
+				Project:   entry.info.Project,
+				Addresses: []string{loadBalancer.ListenAddress},
+			})
+
+			topology.Edges = append(topology.Edges, api.NetworkTopologyEdge{Source: id, Target: loadBalancerID, Kind: "load-balancer"})
+		}
+	}
+
+	// Add the instances and their NICs, using the leases of their networks to find their addresses.
+	clientType := clusterRequest.UserAgentClientType(r.Header.Get("User-Agent"))
+	leases := map[string][]api.NetworkLease{}
+	nicAddresses := map[string]map[string]string{}
+
+	for _, nic := range nics {
+		networkName := nic.config["network"]
+		networkID := networkTopologyNetworkID(nic.networkProject, networkName)
+
+		// Only connect the NICs to the managed networks the user can access.
+		entry := networks[networkID]
+		if entry == nil {
+			networkName = ""
+		}
+
+		nicType := nic.config["nictype"]
+		if nicType == "" && entry != nil {
+			nicType = entry.info.Type
+		}
+
+		addresses := []string{}
+		for _, key := range []string{"ipv4.address", "ipv6.address"} {
+			if nic.config[key] != "" {
+				addresses = append(addresses, util.SplitNTrimSpace(nic.config[key], ",", -1, true)...)
+			}
+		}
+
+		hwaddr := nic.config["hwaddr"]
+		if hwaddr == "" {
+			hwaddr = nic.instance.Config["volatile."+nic.name+".hwaddr"]
+		}
+
+		if networkName != "" && hwaddr != "" {
+			leaseKey := networkID + "|" + nic.instance.Project
+			networkLeases, found := leases[leaseKey]
+			if !found {
+				n, err := network.LoadByName(s, nic.networkProject, networkName)
+				if err == nil {
+					networkLeases, err = n.Leases(nic.instance.Project, clientType)
+				}
+
+				if err != nil {
+					logger.Debug("Failed getting network leases for topology", logger.Ctx{"project": nic.networkProject, "network": networkName, "err": err})
+				}
+
+				leases[leaseKey] = networkLeases
+			}
+
+			for _, lease := range networkLeases {
+				if strings.EqualFold(lease.Hwaddr, hwaddr) && !slices.Contains(addresses, lease.Address) {
+					addresses = append(addresses, lease.Address)
+				}
+			}
+		}
+
+		addNode(api.NetworkTopologyNode{
+			ID:        nic.instanceID,
+			Kind:      "instance",
+			Name:      nic.instance.Name,
+			Project:   nic.instance.Project,
+			Type:      nic.instance.Type.String(),
+			Location:  nic.instance.Node,
+			Addresses: []string{},
+		})
+
+		addNode(api.NetworkTopologyNode{
+			ID:        nic.id,
+			Kind:      "nic",
+			Name:      nic.name,
+			Project:   nic.instance.Project,
+			Type:      nicType,
+			Location:  nic.instance.Node,
+			Addresses: addresses,
+		})
+
+		topology.Edges = append(topology.Edges, api.NetworkTopologyEdge{Source: nic.instanceID, Target: nic.id, Kind: "device"})
+
+		if networkName != "" {
+			topology.Edges = append(topology.Edges, api.NetworkTopologyEdge{Source: nic.id, Target: networkID, Kind: "nic"})
+
+			if nicAddresses[networkID] == nil {
+				nicAddresses[networkID] = map[string]string{}
+			}
+
+			for _, address := range addresses {
+				nicAddresses[networkID][address] = nic.id
+			}
+		}
+	}
+
+	// Connect the forwards and load balancers to the NICs they target.
+	for id, entry := range networks {
+		for _, forward := range entry.forwards {
+			forwardURL := api.NewURL().Path(version.APIVersion, "networks", entry.info.Name, "forwards", forward.ListenAddress).Project(entry.info.Project)
+			if forward.Location != "" {
+				forwardURL = forwardURL.Target(forward.Location)
+			}
+
+			targets := []string{forward.Config["target_address"]}
+			for _, port := range forward.Ports {
+				targets = append(targets, port.TargetAddress)
+			}
+
+			topology.Edges = networkTopologyTargetEdges(topology.Edges, forwardURL.String(), targets, nicAddresses[id])
+		}
+
+		for _, loadBalancer := range entry.loadBalancers {
+			loadBalancerID := api.NewURL().Path(version.APIVersion, "networks", entry.info.Name, "load-balancers", loadBalancer.ListenAddress).Project(entry.info.Project).String()
+
+			targets := []string{}
+			for _, backend := range loadBalancer.Backends {
+				targets = append(targets, backend.TargetAddress)
+			}
+
+			topology.Edges = networkTopologyTargetEdges(topology.Edges, loadBalancerID, targets, nicAddresses[id])
+		}
+	}
+
+	// Drop the edges leading to nodes the user can't see.
+	topology.Edges = slices.DeleteFunc(topology.Edges, func(edge api.NetworkTopologyEdge) bool {
+		return !nodeIDs[edge.Target]
+	})
+
+	return response.SyncResponse(true, topology)
+}
+
+// networkTopologyNetworkID returns the identifier of the node of a network.
+func networkTopologyNetworkID(projectName string, networkName string) string {
+	return api.NewURL().Path(version.APIVersion, "networks", networkName).Project(projectName).String()
+}
+
+// networkTopologyTargetEdges adds the edges from a forward or load balancer to the NICs owning its target addresses.
+func networkTopologyTargetEdges(edges []api.NetworkTopologyEdge, source string, targets []string, nicAddresses map[string]string) []api.NetworkTopologyEdge {
+	for _, target := range targets {
+		targetIP := net.ParseIP(target)
+		if targetIP == nil {
+			continue
+		}
+
+		for address, nicID := range nicAddresses {
+			ip := net.ParseIP(address)
+			if ip == nil {
+				ip, _, _ = net.ParseCIDR(address)
+			}
+
+			if !targetIP.Equal(ip) {
+				continue
+			}
+
+			edge := api.NetworkTopologyEdge{Source: source, Target: nicID, Kind: "target"}
+			if !slices.Contains(edges, edge) {
+				edges = append(edges, edge)
+			}
+		}
+	}
+
+	return edges
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type networkTopologyTestSuite struct {
+	daemonTestSuite
+}
+
+// topology queries the network topology endpoint over the local unix socket.
+func (suite *networkTopologyTestSuite) topology(query string) *api.NetworkTopology {
+	req := httptest.NewRequest(http.MethodGet, "/1.0/network-topology"+query, nil)
+	ctx := context.WithValue(req.Context(), request.CtxUsername, "")
+	ctx = context.WithValue(ctx, request.CtxProtocol, "unix")
+
+	resp := networkTopologyGet(suite.d, req.WithContext(ctx))
+
+	recorder := httptest.NewRecorder()
+	suite.Req.NoError(resp.Render(recorder))
+	suite.Req.Equal(http.StatusOK, recorder.Code)
+
+	raw := api.ResponseRaw{}
+	suite.Req.NoError(json.Unmarshal(recorder.Body.Bytes(), &raw))
+
+	metadata, err := json.Marshal(raw.Metadata)
+	suite.Req.NoError(err)
+
+	topology := api.NetworkTopology{}
+	suite.Req.NoError(json.Unmarshal(metadata, &topology))
+
+	return &topology
+}
+
+func (suite *networkTopologyTestSuite) TestNetworkTopology_NetworksAndNICs() {
+	err := suite.d.db.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.CreateNetwork(ctx, api.ProjectDefaultName, "topology", "", db.NetworkTypeBridge, map[string]string{"ipv4.address": "10.0.0.1/24"})
+		if err != nil {
+			return err
+		}
+
+		instanceID, err := cluster.CreateInstance(ctx, tx.Tx(), cluster.Instance{
+			Project:      api.ProjectDefaultName,
+			Name:         "c1",
+			Node:         "none",
+			Type:         instancetype.Container,
+			Architecture: 1,
+			CreationDate: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+
+		return cluster.CreateInstanceDevices(ctx, tx.Tx(), instanceID, map[string]cluster.Device{
+			"eth0": {Name: "eth0", Type: cluster.TypeNIC, Config: map[string]string{"type": "nic", "network": "topology", "ipv4.address": "10.0.0.10"}},
+			"eth1": {Name: "eth1", Type: cluster.TypeNIC, Config: map[string]string{"type": "nic", "nictype": "macvlan", "parent": "eth0"}},
+		})
+	})
+	suite.Req.NoError(err)
+
+	topology := suite.topology("")
+
+	nodes := map[string]api.NetworkTopologyNode{}
+	for _, node := range topology.Nodes {
+		nodes[node.ID] = node
+	}
+
+	networkID := "/1.0/networks/topology"
+	instanceID := "/1.0/instances/c1"
+
+	suite.Req.Contains(nodes, networkID)
+	suite.Equal("network", nodes[networkID].Kind)
+	suite.Equal([]string{"10.0.0.1/24"}, nodes[networkID].Addresses)
+
+	suite.Req.Contains(nodes, instanceID)
+	suite.Equal("instance", nodes[instanceID].Kind)
+
+	suite.Req.Contains(nodes, instanceID+"#eth0")
+	suite.Equal("bridge", nodes[instanceID+"#eth0"].Type)
+	suite.Equal([]string{"10.0.0.10"}, nodes[instanceID+"#eth0"].Addresses)
+
+	// Unmanaged NICs are shown but not connected to any network.
+	suite.Req.Contains(nodes, instanceID+"#eth1")
+	suite.Equal("macvlan", nodes[instanceID+"#eth1"].Type)
+
+	suite.Contains(topology.Edges, api.NetworkTopologyEdge{Source: instanceID, Target: instanceID + "#eth0", Kind: "device"})
+	suite.Contains(topology.Edges, api.NetworkTopologyEdge{Source: instanceID + "#eth0", Target: networkID, Kind: "nic"})
+
+	for _, edge := range topology.Edges {
+		suite.NotEqual(instanceID+"#eth1", edge.Source)
+	}
+}
+
+func (suite *networkTopologyTestSuite) TestNetworkTopology_Empty() {
+	topology := suite.topology("?all-projects=true")
+
+	suite.Empty(topology.Nodes)
+	suite.Empty(topology.Edges)
+}
+
+func TestNetworkTopologyTestSuite(t *testing.T) {
+	suite.Run(t, &networkTopologyTestSuite{})
+}
+
+func Test_networkTopologyTargetEdges(t *testing.T) {
+	nicAddresses := map[string]string{
+		"10.0.0.10":    "/1.0/instances/c1#eth0",
+		"fd00::10/64":  "/1.0/instances/c1#eth0",
+		"10.0.0.11/24": "/1.0/instances/c2#eth0",
+	}
+
+	edges := networkTopologyTargetEdges(nil, "/1.0/networks/net1/forwards/192.0.2.1", []string{"10.0.0.10", "fd00::10", "10.0.0.11", "10.0.0.12", ""}, nicAddresses)
+
+	assert.ElementsMatch(t, []api.NetworkTopologyEdge{
+		{Source: "/1.0/networks/net1/forwards/192.0.2.1", Target: "/1.0/instances/c1#eth0", Kind: "target"},
+		{Source: "/1.0/networks/net1/forwards/192.0.2.1", Target: "/1.0/instances/c2#eth0", Kind: "target"},
+	}, edges)
+}
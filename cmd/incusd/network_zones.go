@@ -20,6 +20,8 @@ import (
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
@@ -43,6 +45,12 @@ var networkZoneCmd = APIEndpoint{
 	Patch:  APIEndpointAction{Handler: networkZonePut, AccessHandler: allowPermission(auth.ObjectTypeNetworkZone, auth.EntitlementCanEdit, "zone")},
 }
 
+var networkZoneRetireKSKCmd = APIEndpoint{
+	Path: "network-zones/{zone}/retire-ksk",
+
+	Post: APIEndpointAction{Handler: networkZoneRetireKSKPost, AccessHandler: allowPermission(auth.ObjectTypeNetworkZone, auth.EntitlementCanEdit, "zone")},
+}
+
 // API endpoints.
 
 // swagger:operation GET /1.0/network-zones network-zones network_zones_get
@@ -230,7 +238,8 @@ func networkZonesGet(d *Daemon, r *http.Request) response.Response {
 			}
 
 			netzoneInfo := netzone.Info()
-			netzoneInfo.UsedBy, _ = netzone.UsedBy() // Ignore errors in UsedBy, will return nil.
+			netzoneInfo.UsedBy, _ = netzone.UsedBy()       // Ignore errors in UsedBy, will return nil.
+			netzoneInfo.DSRecords, _ = netzone.DSRecords() // Ignore errors in DSRecords, will return nil.
 			netzoneInfo.Project = projectName
 
 			if clauses != nil && len(clauses.Clauses) > 0 {
@@ -453,6 +462,11 @@ func networkZoneGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	info.DSRecords, err = netzone.DSRecords()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	return response.SyncResponseETag(true, info, netzone.Etag())
 }
 
@@ -580,3 +594,110 @@ func networkZonePut(d *Daemon, r *http.Request) response.Response {
 
 	return response.EmptySyncResponse
 }
+
+// swagger:operation POST /1.0/network-zones/{zone}/retire-ksk network-zones network_zone_retire_ksk_post
+//
+//	Retire the previous key signing key
+//
+//	Removes the previous DNSSEC key signing keys of the zone following a key rollover.
+//	This must only be done once the DS record of the new key has been published in the parent zone.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkZoneRetireKSKPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, _, err := project.NetworkZoneProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	zoneName, err := url.PathUnescape(mux.Vars(r)["zone"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	netzone, err := zone.LoadByNameAndProject(s, projectName, zoneName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = netzone.RetireKSK()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(projectName, lifecycle.NetworkZoneUpdated.Event(netzone, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// rotateNetworkZoneKeys performs the scheduled DNSSEC key rollovers of all network zones.
+func rotateNetworkZoneKeys(ctx context.Context, s *state.State) error {
+	// If we are clustered, let the leader handle the key rollovers.
+	if s.ServerClustered {
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil {
+			return err
+		}
+
+		if s.LocalConfig.ClusterAddress() != leader {
+			return nil
+		}
+	}
+
+	var dbZones []dbCluster.NetworkZone
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbZones, err = dbCluster.GetNetworkZones(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, dbZone := range dbZones {
+		netzone, err := zone.LoadByNameAndProject(s, dbZone.Project, dbZone.Name)
+		if err != nil {
+			logger.Warn("Failed loading network zone", logger.Ctx{"project": dbZone.Project, "zone": dbZone.Name, "err": err})
+			continue
+		}
+
+		err = netzone.RotateKeys()
+		if err != nil {
+			logger.Warn("Failed rotating network zone DNSSEC keys", logger.Ctx{"project": dbZone.Project, "zone": dbZone.Name, "err": err})
+			continue
+		}
+	}
+
+	return nil
+}
+
+func rotateNetworkZoneKeysTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := rotateNetworkZoneKeys(ctx, d.State())
+		if err != nil {
+			logger.Error("Failed rotating network zone DNSSEC keys", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Daily()
+}
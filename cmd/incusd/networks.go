@@ -70,12 +70,24 @@ var networkLeasesCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: networkLeasesGet, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanView, "networkName")},
 }
 
+var networkLeasesHistoryCmd = APIEndpoint{
+	Path: "networks/{networkName}/leases/history",
+
+	Get: APIEndpointAction{Handler: networkLeasesHistoryGet, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanView, "networkName")},
+}
+
 var networkStateCmd = APIEndpoint{
 	Path: "networks/{networkName}/state",
 
 	Get: APIEndpointAction{Handler: networkStateGet, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanView, "networkName")},
 }
 
+var networkCaptureCmd = APIEndpoint{
+	Path: "networks/{networkName}/capture",
+
+	Post: APIEndpointAction{Handler: networkCapturePost, AccessHandler: allowPermission(auth.ObjectTypeNetwork, auth.EntitlementCanEdit, "networkName")},
+}
+
 // API endpoints
 
 // swagger:operation GET /1.0/networks networks networks_get
@@ -1662,6 +1674,126 @@ func networkLeasesGet(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponse(true, leases)
 }
 
+// swagger:operation GET /1.0/networks/{name}/leases/history networks networks_leases_history_get
+//
+//	Get the DHCP lease history
+//
+//	Returns the recorded DHCP lease events for the network, most recent first.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: hwaddr
+//	    description: Only return events for this MAC address
+//	    type: string
+//	    example: 10:66:6a:2c:89:d9
+//	  - in: query
+//	    name: address
+//	    description: Only return events for this IP address
+//	    type: string
+//	    example: 10.0.0.98
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of DHCP lease events
+//	          items:
+//	            $ref: "#/definitions/NetworkLeaseEvent"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func networkLeasesHistoryGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, reqProject, err := project.NetworkProject(s.DB.Cluster, request.ProjectParam(r))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	networkName, err := url.PathUnescape(mux.Vars(r)["networkName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Attempt to load the network.
+	n, err := network.LoadByName(s, projectName, networkName)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading network: %w", err))
+	}
+
+	// Check if project allows access to network.
+	if !project.NetworkAllowed(reqProject.Config, networkName, n.IsManaged()) {
+		return response.SmartError(api.StatusErrorf(http.StatusNotFound, "Network not found"))
+	}
+
+	filter := db.NetworkLeaseEventFilter{}
+
+	// Only show the events of the requested project's instances when the network lives in another project.
+	if reqProject.Name != n.Project() {
+		filter.Project = &reqProject.Name
+	}
+
+	hwaddr := request.QueryParam(r, "hwaddr")
+	if hwaddr != "" {
+		mac, err := net.ParseMAC(hwaddr)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid MAC address %q", hwaddr))
+		}
+
+		hwaddr = mac.String()
+		filter.Hwaddr = &hwaddr
+	}
+
+	address := request.QueryParam(r, "address")
+	if address != "" {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			return response.BadRequest(fmt.Errorf("Invalid IP address %q", address))
+		}
+
+		address = ip.String()
+		filter.Address = &address
+	}
+
+	var events []api.NetworkLeaseEvent
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		events, err = tx.GetNetworkLeaseEvents(ctx, n.ID(), filter)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, events)
+}
+
 func networkStartup(s *state.State) error {
 	var err error
 
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"slices"
 
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/network"
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 var networkOVNChassis *bool
@@ -38,3 +43,99 @@ func networkUpdateOVNChassis(s *state.State, heartbeatData *cluster.APIHeartbeat
 	networkOVNChassis = &runChassis
 	return nil
 }
+
+// networkClusterGroupMembers returns the sorted names of the members of each cluster group in the heartbeat.
+func networkClusterGroupMembers(heartbeatData *cluster.APIHeartbeat) map[string][]string {
+	groupMembers := map[string][]string{}
+	for _, member := range heartbeatData.Members {
+		for _, group := range member.Groups {
+			groupMembers[group] = append(groupMembers[group], member.Name)
+		}
+	}
+
+	for _, members := range groupMembers {
+		slices.Sort(members)
+	}
+
+	return groupMembers
+}
+
+// networkUpdateOVNGatewayChassis gets called on heartbeats to restart the OVN networks using a cluster group as
+// gateway chassis when the members of that group have changed.
+func networkUpdateOVNGatewayChassis(s *state.State, lastHeartbeatData *cluster.APIHeartbeat, heartbeatData *cluster.APIHeartbeat) error {
+	// The networks were set up from the current groups on startup.
+	if lastHeartbeatData == nil {
+		return nil
+	}
+
+	// Find the cluster groups whose members have changed.
+	lastGroupMembers := networkClusterGroupMembers(lastHeartbeatData)
+	groupMembers := networkClusterGroupMembers(heartbeatData)
+
+	changedGroups := []string{}
+	for group, members := range groupMembers {
+		if !slices.Equal(members, lastGroupMembers[group]) {
+			changedGroups = append(changedGroups, group)
+		}
+	}
+
+	for group := range lastGroupMembers {
+		_, ok := groupMembers[group]
+		if !ok {
+			changedGroups = append(changedGroups, group)
+		}
+	}
+
+	if len(changedGroups) == 0 {
+		return nil
+	}
+
+	// Get the created networks of every project.
+	projectNetworks := map[string][]string{}
+	err := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		projectNames, err := dbCluster.GetProjectNames(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed to load projects: %w", err)
+		}
+
+		for _, projectName := range projectNames {
+			projectNetworks[projectName], err = tx.GetCreatedNetworkNamesByProject(ctx, projectName)
+			if err != nil {
+				return fmt.Errorf("Failed to load networks for project %q: %w", projectName, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for projectName, networkNames := range projectNetworks {
+		for _, networkName := range networkNames {
+			n, err := network.LoadByName(s, projectName, networkName)
+			if err != nil {
+				return fmt.Errorf("Failed to load network %q in project %q: %w", networkName, projectName, err)
+			}
+
+			// Skip non-OVN networks.
+			if n.DBType() != db.NetworkTypeOVN {
+				continue
+			}
+
+			// Skip the networks which don't use one of the changed groups.
+			gatewayChassis := util.SplitNTrimSpace(n.Config()["ovn.gateway.chassis"], ",", -1, true)
+			if !slices.ContainsFunc(changedGroups, func(group string) bool { return slices.Contains(gatewayChassis, "@"+group) }) {
+				continue
+			}
+
+			// Restart the network to refresh the local chassis group entry.
+			err = n.Start()
+			if err != nil {
+				return fmt.Errorf("Failed to restart network %q in project %q: %w", networkName, projectName, err)
+			}
+		}
+	}
+
+	return nil
+}
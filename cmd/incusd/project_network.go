@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/device"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/network"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// projectNetworkTask accounts the network traffic of the local instances to their project and applies the project
+// network limits, picking up the changes made through other cluster members.
+// The traffic of the NICs which stop in between is accounted for when they stop.
+func projectNetworkTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := projectNetworkUpdate(ctx, d.State())
+		if err != nil {
+			logger.Error("Failed updating project network counters and limits", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
+
+// projectNetworkUpdate adds the traffic of the host side interfaces of the running local NICs since they were last
+// accounted for to the cumulative counters of their project and applies the project network limits.
+func projectNetworkUpdate(ctx context.Context, s *state.State) error {
+	insts, err := instance.LoadNodeAll(s, instancetype.Any)
+	if err != nil {
+		return fmt.Errorf("Failed loading instances: %w", err)
+	}
+
+	projects := map[string]api.Project{}
+	hostNames := map[string]string{}
+
+	for _, inst := range insts {
+		if !inst.IsRunning() {
+			continue
+		}
+
+		projectName := inst.Project().Name
+		projects[projectName] = inst.Project()
+
+		for devName, devConfig := range inst.ExpandedDevices() {
+			if devConfig["type"] != "nic" {
+				continue
+			}
+
+			// Only account the host side interfaces the NICs created, never the user editable volatile ones.
+			hostName := device.NetworkHostInterfaceName(projectName, inst.Name(), devName)
+			if hostName == "" {
+				continue
+			}
+
+			hostNames[hostName] = projectName
+		}
+	}
+
+	err = network.ProjectCountersUpdate(s, hostNames)
+	if err != nil {
+		return err
+	}
+
+	var projectNames []string
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		projectNames, err = dbCluster.GetProjectNames(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for projectName, project := range projects {
+		err := projectNetworkLimitsSetup(s, projectName, project.Config)
+		if err != nil {
+			logger.Warn("Failed applying project network limits", logger.Ctx{"project": projectName, "err": err})
+		}
+	}
+
+	return network.ProjectLimitsCleanup(projectNames)
+}
+
+// projectNetworkLimitsSetup applies the project network limits to the shaping devices of this server and makes the
+// running local NICs of the project redirect their traffic to them, or stop doing so, when shaping got enabled or
+// disabled.
+func projectNetworkLimitsSetup(s *state.State, projectName string, config map[string]string) error {
+	changed, err := network.ProjectLimitsSetup(projectName, config)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		return device.NetworkRefreshProjectLimits(s, projectName)
+	}
+
+	return nil
+}
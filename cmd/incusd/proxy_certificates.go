@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/device"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+var proxyCertificatesCmd = APIEndpoint{
+	Path: "proxy-certificates",
+
+	Get:  APIEndpointAction{Handler: proxyCertificatesGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: proxyCertificatesPost, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+}
+
+var proxyCertificateCmd = APIEndpoint{
+	Path: "proxy-certificates/{name}",
+
+	Delete: APIEndpointAction{Handler: proxyCertificateDelete, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: proxyCertificateGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView)},
+}
+
+// proxyCertificateToAPI converts a proxy certificate record into its API representation, leaving out its key.
+func proxyCertificateToAPI(projectName string, certificate *db.ProxyCertificate) *api.ProxyCertificate {
+	fingerprint, _ := localtls.CertFingerprintStr(certificate.Certificate)
+
+	return &api.ProxyCertificate{
+		Name:        certificate.Name,
+		Description: certificate.Description,
+		Certificate: certificate.Certificate,
+		Fingerprint: fingerprint,
+		Project:     projectName,
+	}
+}
+
+// swagger:operation GET /1.0/proxy-certificates proxy-certificates proxy_certificates_get
+//
+//	Get the proxy certificates
+//
+//	Returns a list of the certificates of the proxy devices of the project (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/proxy-certificates/web",
+//	              "/1.0/proxy-certificates/api"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/proxy-certificates?recursion=1 proxy-certificates proxy_certificates_get_recursion1
+//
+//	Get the proxy certificates
+//
+//	Returns a list of the certificates of the proxy devices of the project (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of proxy certificates
+//	          items:
+//	            $ref: "#/definitions/ProxyCertificate"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func proxyCertificatesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var certificates []db.ProxyCertificate
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		certificates, err = tx.GetProxyCertificates(ctx, projectName)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	linkResults := make([]string, 0, len(certificates))
+	fullResults := make([]api.ProxyCertificate, 0, len(certificates))
+
+	for _, certificate := range certificates {
+		if recursion {
+			fullResults = append(fullResults, *proxyCertificateToAPI(projectName, &certificate))
+		}
+
+		linkResults = append(linkResults, api.NewURL().Path(version.APIVersion, "proxy-certificates", certificate.Name).String())
+	}
+
+	if !recursion {
+		return response.SyncResponse(true, linkResults)
+	}
+
+	return response.SyncResponse(true, fullResults)
+}
+
+// swagger:operation POST /1.0/proxy-certificates proxy-certificates proxy_certificates_post
+//
+//	Add a proxy certificate
+//
+//	Adds a certificate and its private key to the project, for use by its proxy devices terminating TLS.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: certificate
+//	    description: Proxy certificate
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProxyCertificatesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func proxyCertificatesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	req := api.ProxyCertificatesPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = device.ProxyValidCertificateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	_, err = tls.X509KeyPair([]byte(req.Certificate), []byte(req.Key))
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid certificate or key: %w", err))
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		return tx.CreateProxyCertificate(ctx, projectName, db.ProxyCertificate{
+			Name:        req.Name,
+			Description: req.Description,
+			Certificate: req.Certificate,
+			Key:         req.Key,
+		})
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseLocation(true, nil, api.NewURL().Path(version.APIVersion, "proxy-certificates", req.Name).Project(projectName).String())
+}
+
+// swagger:operation GET /1.0/proxy-certificates/{name} proxy-certificates proxy_certificate_get
+//
+//	Get the proxy certificate
+//
+//	Gets a specific proxy certificate, without its private key.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Proxy certificate
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ProxyCertificate"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func proxyCertificateGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var certificate *db.ProxyCertificate
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		certificate, err = tx.GetProxyCertificate(ctx, projectName, name)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, proxyCertificateToAPI(projectName, certificate))
+}
+
+// swagger:operation DELETE /1.0/proxy-certificates/{name} proxy-certificates proxy_certificate_delete
+//
+//	Delete the proxy certificate
+//
+//	Removes the proxy certificate. Certificates still used by proxy devices can't be removed.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func proxyCertificateDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Refuse removing certificates still used by the proxy devices of the project.
+		err := tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			devices := db.ExpandInstanceDevices(inst.Devices.Clone(), inst.Profiles)
+			for devName, devConfig := range devices {
+				if devConfig["type"] == "proxy" && devConfig["listen.tls.certificate"] == name {
+					return api.StatusErrorf(http.StatusBadRequest, "Proxy certificate is used by device %q of instance %q", devName, inst.Name)
+				}
+			}
+
+			return nil
+		}, dbCluster.InstanceFilter{Project: &projectName})
+		if err != nil {
+			return err
+		}
+
+		return tx.DeleteProxyCertificate(ctx, projectName, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
@@ -1496,6 +1496,14 @@ func storagePoolVolumePost(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Check if a network is serving files from it.
+	err = storagePools.VolumeUsedByNetworks(s, srcPoolName, projectName, volumeName, func(networkProject string, network api.Network) error {
+		return api.StatusErrorf(http.StatusBadRequest, "Volume is still in use by network %q in project %q", network.Name, networkProject)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Detect a rename request.
 	if (req.Pool == "" || req.Pool == srcPoolName) && (projectName == targetProjectName) {
 		return storagePoolVolumeTypePostRename(s, r, srcPoolName, projectName, &dbVolume.StorageVolume, req)
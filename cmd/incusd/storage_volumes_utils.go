@@ -137,6 +137,15 @@ func storagePoolVolumeUsedByGet(s *state.State, requestProjectName string, poolN
 		return []string{}, err
 	}
 
+	// Look for networks serving files from this volume.
+	err = storagePools.VolumeUsedByNetworks(s, poolName, vol.Project, vol.Name, func(networkProject string, network api.Network) error {
+		volumeUsedBy = append(volumeUsedBy, api.NewURL().Path(version.APIVersion, "networks", network.Name).Project(networkProject).String())
+		return nil
+	})
+	if err != nil {
+		return []string{}, err
+	}
+
 	return volumeUsedBy, nil
 }
 
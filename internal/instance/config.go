@@ -1294,6 +1294,15 @@ func ConfigKeyChecker(key string, instanceType api.InstanceType) (func(value str
 			return validate.IsAny, nil
 		}
 
+		// gendoc:generate(entity=instance, group=volatile, key=volatile.<name>.bond_name)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: Host bond interface name for bond network devices
+		if strings.HasSuffix(key, ".bond_name") {
+			return validate.IsAny, nil
+		}
+
 		// gendoc:generate(entity=instance, group=volatile, key=volatile.<name>.ceph_rbd)
 		//
 		// ---
@@ -1303,6 +1312,15 @@ func ConfigKeyChecker(key string, instanceType api.InstanceType) (func(value str
 			return validate.IsAny, nil
 		}
 
+		// gendoc:generate(entity=instance, group=volatile, key=volatile.<name>.nbd_device)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: NBD device path for NBD disk devices
+		if strings.HasSuffix(key, ".nbd_device") {
+			return validate.Optional(ValidNBDDevicePath), nil
+		}
+
 		// gendoc:generate(entity=instance, group=volatile, key=volatile.<name>.host_name)
 		//
 		// ---
@@ -2,6 +2,8 @@ package instance
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -55,3 +57,26 @@ func SplitVolumeSource(source string) (string, string) {
 
 	return volFields[0], volFields[1]
 }
+
+// IsNetworkDiskSource returns true if the given disk source is a network block device URL (NBD, iSCSI or HTTP).
+func IsNetworkDiskSource(source string) bool {
+	for _, prefix := range []string{"nbd://", "iscsi://", "http://", "https://"} {
+		if strings.HasPrefix(source, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nbdDevicePath matches the path of a kernel NBD device.
+var nbdDevicePath = regexp.MustCompile(`^/dev/nbd[0-9]+$`)
+
+// ValidNBDDevicePath validates the path of a kernel NBD device.
+func ValidNBDDevicePath(value string) error {
+	if !nbdDevicePath.MatchString(value) {
+		return fmt.Errorf("Invalid NBD device path %q", value)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package ports
 
 // Default ports for common services.
 const (
+	BFDDefaultPort                 = 3784
 	BGPDefaultPort                 = 179
 	DNSDefaultPort                 = 53
 	HTTPDebugDefaultPort           = 8080
@@ -4,6 +4,8 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -86,6 +88,33 @@ func UpdateCertificate(s *state.State, challengeType string, clustered bool, dom
 		return nil, nil
 	}
 
+	return runChallenge(context.TODO(), s, challengeType, domain, email, caURL)
+}
+
+// IssueCertificate issues a certificate for the domain (a comma-separated list of names) through the ACME server
+// configured on the server. The current certificate is kept, and nil returned, if it's still valid for the domain
+// for more than 30 days.
+func IssueCertificate(ctx context.Context, s *state.State, domain string, current []byte) (*CertKeyPair, error) {
+	_, email, caURL, agreeToS, challengeType := s.GlobalConfig.ACME()
+	if email == "" || !agreeToS || challengeType == "" {
+		return nil, errors.New(`Issuing certificates through ACME requires "acme.email" and "acme.agree_tos" to be set`)
+	}
+
+	if current != nil {
+		block, _ := pem.Decode(current)
+		if block != nil {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err == nil && !incustls.CertificateNeedsUpdate(domain, cert, 30*24*time.Hour) {
+				return nil, nil
+			}
+		}
+	}
+
+	return runChallenge(ctx, s, challengeType, domain, email, caURL)
+}
+
+// runChallenge runs the ACME challenge for the domain with the ACME settings of the server.
+func runChallenge(ctx context.Context, s *state.State, challengeType string, domain string, email string, caURL string) (*CertKeyPair, error) {
 	port := s.GlobalConfig.ACMEHTTP()
 	provider, environment, resolvers := s.GlobalConfig.ACMEDNS()
 	proxy := s.GlobalConfig.ProxyHTTPS()
@@ -102,7 +131,7 @@ func UpdateCertificate(s *state.State, challengeType string, clustered bool, dom
 		}
 	}()
 
-	certBytes, keyBytes, err := incustls.RunACMEChallenge(context.TODO(), tmpDir, caURL, domain, email, challengeType, provider, port, proxy, resolvers, environment)
+	certBytes, keyBytes, err := incustls.RunACMEChallenge(ctx, tmpDir, caURL, domain, email, challengeType, provider, port, proxy, resolvers, environment)
 	if err != nil {
 		return nil, err
 	}
@@ -6,10 +6,17 @@ import (
 
 	"github.com/lxc/incus/v6/internal/server/sys"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 // dnsmasqProfile generates the AppArmor profile template from the given network.
 func dnsmasqProfile(sysOS *sys.OS, n network) (string, error) {
+	// Storage volume serving the TFTP boot files.
+	var tftpPool, tftpVolume string
+	if util.IsTrueOrEmpty(n.Config()["boot.tftp"]) {
+		tftpPool, tftpVolume, _ = strings.Cut(n.Config()["boot.volume"], "/")
+	}
+
 	// Render the profile.
 	var sb *strings.Builder = &strings.Builder{}
 	err := dnsmasqProfileTpl.Execute(sb, map[string]any{
@@ -17,6 +24,8 @@ func dnsmasqProfile(sysOS *sys.OS, n network) (string, error) {
 		"networkName": n.Name(),
 		"logPath":     internalUtil.LogPath(""),
 		"varPath":     internalUtil.VarPath(""),
+		"tftpPool":    tftpPool,
+		"tftpVolume":  tftpVolume,
 	})
 	if err != nil {
 		return "", err
@@ -30,6 +30,16 @@ profile "{{ .name }}" flags=(attach_disconnected,mediate_deleted) {
   {{ .varPath }}/networks/{{ .networkName }}/dnsmasq.hosts/{,*} r,
   {{ .varPath }}/networks/{{ .networkName }}/dnsmasq.leases rw,
   {{ .varPath }}/networks/{{ .networkName }}/dnsmasq.raw r,
+  {{ .varPath }}/networks/{{ .networkName }}/dnsmasq.script rix,
+
+  # Lease change notifications
+  /{,usr/}bin/{,ba,da}sh mrix,
+  {{ .varPath }}/networks/{{ .networkName }}/dnsmasq.events w,
+{{- if .tftpVolume }}
+
+  # TFTP boot files
+  {{ .varPath }}/storage-pools/{{ .tftpPool }}/custom/*_{{ .tftpVolume }}/{,**} r,
+{{- end }}
 
   # Allow to restart dnsmasq
   signal (receive) set=("hup","kill"),
@@ -0,0 +1,163 @@
+package bfd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// State represents the state of a BFD session (RFC 5880 section 4.1).
+type State uint8
+
+// All BFD session states.
+const (
+	StateAdminDown State = 0
+	StateDown      State = 1
+	StateInit      State = 2
+	StateUp        State = 3
+)
+
+// String returns the lowercase name of the state, matching the naming used by OVN.
+func (s State) String() string {
+	switch s {
+	case StateAdminDown:
+		return "admin_down"
+	case StateDown:
+		return "down"
+	case StateInit:
+		return "init"
+	case StateUp:
+		return "up"
+	}
+
+	return "unknown"
+}
+
+// Diagnostic represents the reason for the last BFD state change (RFC 5880 section 4.1).
+type Diagnostic uint8
+
+// Diagnostic codes used by this implementation.
+const (
+	DiagNone                 Diagnostic = 0
+	DiagControlTimeExpired   Diagnostic = 1
+	DiagNeighborSignaledDown Diagnostic = 3
+	DiagAdminDown            Diagnostic = 7
+)
+
+// Protocol constants.
+const (
+	version = 1
+
+	// packetLength is the size of a control packet without authentication section.
+	packetLength = 24
+
+	flagPoll        = 0x20
+	flagFinal       = 0x10
+	flagAuthPresent = 0x04
+	flagDemand      = 0x02
+	flagMultipoint  = 0x01
+	stateShift      = 6
+	diagnosticMask  = 0x1f
+	versionShift    = 5
+)
+
+// ErrInvalidPacket is returned when a control packet fails the RFC 5880 section 6.8.6 validation.
+var ErrInvalidPacket = errors.New("Invalid BFD control packet")
+
+// ControlPacket represents a BFD control packet (RFC 5880 section 4.1).
+type ControlPacket struct {
+	Diagnostic Diagnostic
+	State      State
+	Poll       bool
+	Final      bool
+	Demand     bool
+	DetectMult uint8
+
+	MyDiscriminator   uint32
+	YourDiscriminator uint32
+
+	// Intervals are expressed in microseconds.
+	DesiredMinTxInterval  uint32
+	RequiredMinRxInterval uint32
+	RequiredMinEchoRx     uint32
+}
+
+// Marshal encodes the control packet for the wire.
+func (p *ControlPacket) Marshal() []byte {
+	buf := make([]byte, packetLength)
+
+	buf[0] = version<<versionShift | uint8(p.Diagnostic)&diagnosticMask
+	buf[1] = uint8(p.State) << stateShift
+
+	if p.Poll {
+		buf[1] |= flagPoll
+	}
+
+	if p.Final {
+		buf[1] |= flagFinal
+	}
+
+	if p.Demand {
+		buf[1] |= flagDemand
+	}
+
+	buf[2] = p.DetectMult
+	buf[3] = packetLength
+	binary.BigEndian.PutUint32(buf[4:8], p.MyDiscriminator)
+	binary.BigEndian.PutUint32(buf[8:12], p.YourDiscriminator)
+	binary.BigEndian.PutUint32(buf[12:16], p.DesiredMinTxInterval)
+	binary.BigEndian.PutUint32(buf[16:20], p.RequiredMinRxInterval)
+	binary.BigEndian.PutUint32(buf[20:24], p.RequiredMinEchoRx)
+
+	return buf
+}
+
+// Unmarshal decodes and validates a control packet received from the wire.
+func (p *ControlPacket) Unmarshal(buf []byte) error {
+	if len(buf) < packetLength {
+		return fmt.Errorf("%w: Packet too short (%d bytes)", ErrInvalidPacket, len(buf))
+	}
+
+	if buf[0]>>versionShift != version {
+		return fmt.Errorf("%w: Unsupported version %d", ErrInvalidPacket, buf[0]>>versionShift)
+	}
+
+	length := int(buf[3])
+	if length < packetLength || length > len(buf) {
+		return fmt.Errorf("%w: Bad length field (%d)", ErrInvalidPacket, length)
+	}
+
+	if buf[1]&flagAuthPresent != 0 {
+		return fmt.Errorf("%w: Authentication isn't supported", ErrInvalidPacket)
+	}
+
+	if buf[1]&flagMultipoint != 0 {
+		return fmt.Errorf("%w: Multipoint bit set", ErrInvalidPacket)
+	}
+
+	if buf[2] == 0 {
+		return fmt.Errorf("%w: Detection multiplier is zero", ErrInvalidPacket)
+	}
+
+	p.Diagnostic = Diagnostic(buf[0] & diagnosticMask)
+	p.State = State(buf[1] >> stateShift)
+	p.Poll = buf[1]&flagPoll != 0
+	p.Final = buf[1]&flagFinal != 0
+	p.Demand = buf[1]&flagDemand != 0
+	p.DetectMult = buf[2]
+	p.MyDiscriminator = binary.BigEndian.Uint32(buf[4:8])
+	p.YourDiscriminator = binary.BigEndian.Uint32(buf[8:12])
+	p.DesiredMinTxInterval = binary.BigEndian.Uint32(buf[12:16])
+	p.RequiredMinRxInterval = binary.BigEndian.Uint32(buf[16:20])
+	p.RequiredMinEchoRx = binary.BigEndian.Uint32(buf[20:24])
+
+	if p.MyDiscriminator == 0 {
+		return fmt.Errorf("%w: Zero discriminator", ErrInvalidPacket)
+	}
+
+	if p.YourDiscriminator == 0 && p.State != StateDown && p.State != StateAdminDown {
+		return fmt.Errorf("%w: Missing remote discriminator in state %q", ErrInvalidPacket, p.State)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package bfd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A marshaled control packet can be decoded back.
+func TestControlPacket_RoundTrip(t *testing.T) {
+	packet := ControlPacket{
+		Diagnostic:            DiagControlTimeExpired,
+		State:                 StateUp,
+		Final:                 true,
+		DetectMult:            3,
+		MyDiscriminator:       0x11223344,
+		YourDiscriminator:     0x55667788,
+		DesiredMinTxInterval:  300000,
+		RequiredMinRxInterval: 300000,
+	}
+
+	buf := packet.Marshal()
+	require.Len(t, buf, packetLength)
+	assert.Equal(t, byte(0x21), buf[0])
+	assert.Equal(t, byte(0xd0), buf[1])
+
+	decoded := ControlPacket{}
+	err := decoded.Unmarshal(buf)
+	require.NoError(t, err)
+	assert.Equal(t, packet, decoded)
+}
+
+// Packets violating RFC 5880 section 6.8.6 are rejected.
+func TestControlPacket_Invalid(t *testing.T) {
+	valid := ControlPacket{
+		State:             StateInit,
+		DetectMult:        3,
+		MyDiscriminator:   1,
+		YourDiscriminator: 2,
+	}
+
+	cases := map[string]func(buf []byte) []byte{
+		"short":              func(buf []byte) []byte { return buf[:20] },
+		"version":            func(buf []byte) []byte { buf[0] = 2 << versionShift; return buf },
+		"length":             func(buf []byte) []byte { buf[3] = 12; return buf },
+		"authentication":     func(buf []byte) []byte { buf[1] |= flagAuthPresent; return buf },
+		"multipoint":         func(buf []byte) []byte { buf[1] |= flagMultipoint; return buf },
+		"detect multiplier":  func(buf []byte) []byte { buf[2] = 0; return buf },
+		"my discriminator":   func(buf []byte) []byte { copy(buf[4:8], []byte{0, 0, 0, 0}); return buf },
+		"your discriminator": func(buf []byte) []byte { copy(buf[8:12], []byte{0, 0, 0, 0}); return buf },
+	}
+
+	for name, mangle := range cases {
+		t.Run(name, func(t *testing.T) {
+			decoded := ControlPacket{}
+			err := decoded.Unmarshal(mangle(valid.Marshal()))
+			assert.True(t, errors.Is(err, ErrInvalidPacket))
+		})
+	}
+}
+
+// A session walks through the three-way handshake and goes down when the peer does.
+func TestSession_StateMachine(t *testing.T) {
+	s := &Session{state: StateDown, wake: make(chan struct{}, 1)}
+
+	s.receive(&ControlPacket{State: StateDown, DetectMult: 3, MyDiscriminator: 1})
+	assert.Equal(t, StateInit, s.State())
+
+	s.receive(&ControlPacket{State: StateUp, DetectMult: 3, MyDiscriminator: 1, YourDiscriminator: 2})
+	assert.Equal(t, StateUp, s.State())
+
+	s.receive(&ControlPacket{State: StateDown, DetectMult: 3, MyDiscriminator: 1})
+	assert.Equal(t, StateDown, s.State())
+	assert.Equal(t, DiagNeighborSignaledDown, s.diag)
+}
@@ -0,0 +1,201 @@
+package bfd
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"sync"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/ports"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// ErrSessionNotFound is returned when no session exists for the requested peer.
+var ErrSessionNotFound = errors.New("BFD session not found")
+
+// Server handles single-hop BFD sessions (RFC 5881) sharing a common control packet listener.
+type Server struct {
+	listeners map[string]*net.UDPConn
+	sessions  map[string]*Session
+
+	mu sync.Mutex
+}
+
+// NewServer returns a new server instance.
+func NewServer() *Server {
+	return &Server{
+		listeners: map[string]*net.UDPConn{},
+		sessions:  map[string]*Session{},
+	}
+}
+
+// AddSession starts a new session with the peer, calling the handler on every state change.
+func (s *Server) AddSession(peer net.IP, handler func(State)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.sessions[peer.String()]
+	if ok {
+		return fmt.Errorf("BFD session already exists for peer %q", peer.String())
+	}
+
+	network := "udp4"
+	if peer.To4() == nil {
+		network = "udp6"
+	}
+
+	// Start the listener for the address family if needed.
+	if s.listeners[network] == nil {
+		err := s.listen(network)
+		if err != nil {
+			return fmt.Errorf("Failed starting BFD listener: %w", err)
+		}
+	}
+
+	session, err := newSession(peer, s.newDiscriminator(), handler)
+	if err != nil {
+		return err
+	}
+
+	s.sessions[peer.String()] = session
+
+	return nil
+}
+
+// RemoveSession stops the session with the peer.
+func (s *Server) RemoveSession(peer net.IP) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[peer.String()]
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session.close()
+	delete(s.sessions, peer.String())
+
+	// Stop the listeners once no session is left.
+	if len(s.sessions) == 0 {
+		for network, conn := range s.listeners {
+			_ = conn.Close()
+			delete(s.listeners, network)
+		}
+	}
+
+	return nil
+}
+
+// SessionState returns the local state of the session with the peer.
+func (s *Server) SessionState(peer net.IP) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[peer.String()]
+	if !ok {
+		return StateAdminDown, ErrSessionNotFound
+	}
+
+	return session.State(), nil
+}
+
+// newDiscriminator returns a random non-zero discriminator not used by any other session.
+func (s *Server) newDiscriminator() uint32 {
+	for {
+		disc := rand.Uint32()
+		if disc == 0 {
+			continue
+		}
+
+		inUse := false
+		for _, session := range s.sessions {
+			if session.localDisc == disc {
+				inUse = true
+				break
+			}
+		}
+
+		if !inUse {
+			return disc
+		}
+	}
+}
+
+// listen sets up the control packet listener for the given network.
+// Only packets with a TTL (or hop limit) of 255 are accepted (RFC 5881 section 5).
+func (s *Server) listen(network string) error {
+	conn, err := net.ListenUDP(network, &net.UDPAddr{Port: ports.BFDDefaultPort})
+	if err != nil {
+		return err
+	}
+
+	err = setSocketTTL(conn, network, unix.IP_MINTTL, unix.IPV6_MINHOPCOUNT)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	s.listeners[network] = conn
+
+	go s.serve(conn)
+
+	return nil
+}
+
+func (s *Server) serve(conn *net.UDPConn) {
+	buf := make([]byte, 1500)
+
+	for {
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			logger.Debug("Failed reading BFD control packet", logger.Ctx{"err": err})
+			continue
+		}
+
+		packet := &ControlPacket{}
+		err = packet.Unmarshal(buf[:n])
+		if err != nil {
+			logger.Debug("Dropping BFD control packet", logger.Ctx{"source": addr.IP.String(), "err": err})
+			continue
+		}
+
+		session := s.lookupSession(packet, addr.IP)
+		if session == nil {
+			continue
+		}
+
+		session.receive(packet)
+	}
+}
+
+// lookupSession finds the session for a received packet, using the discriminator when set
+// and the source address otherwise (RFC 5880 section 6.3).
+func (s *Server) lookupSession(packet *ControlPacket, source net.IP) *Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if packet.YourDiscriminator != 0 {
+		for _, session := range s.sessions {
+			if session.localDisc == packet.YourDiscriminator {
+				return session
+			}
+		}
+
+		return nil
+	}
+
+	for _, session := range s.sessions {
+		if session.peer.Equal(source) {
+			return session
+		}
+	}
+
+	return nil
+}
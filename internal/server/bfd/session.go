@@ -0,0 +1,344 @@
+package bfd
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/ports"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// Timers used for all sessions (expressed in microseconds as on the wire).
+const (
+	// desiredMinTxInterval is the interval at which we want to send packets once the session is up.
+	desiredMinTxInterval = 300000
+
+	// requiredMinRxInterval is the minimum interval at which we can receive packets.
+	requiredMinRxInterval = 300000
+
+	// slowTxInterval is used while the session isn't up (RFC 5880 section 6.8.3).
+	slowTxInterval = 1000000
+
+	// detectMult is the detection time multiplier advertised to the peer.
+	detectMult = 3
+)
+
+// Source port range for single-hop sessions (RFC 5881 section 4).
+const (
+	sourcePortMin = 49152
+	sourcePortMax = 65535
+)
+
+// Session represents a single-hop asynchronous BFD session with a peer.
+type Session struct {
+	peer    net.IP
+	conn    *net.UDPConn
+	handler func(State)
+
+	// Local state.
+	state     State
+	diag      Diagnostic
+	localDisc uint32
+	sendFinal bool
+
+	// Remote state.
+	remoteState                State
+	remoteDisc                 uint32
+	remoteMinRxInterval        uint32
+	remoteDesiredMinTxInterval uint32
+	remoteDetectMult           uint8
+	lastReceived               time.Time
+
+	wake chan struct{}
+	stop chan struct{}
+
+	mu sync.Mutex
+}
+
+func newSession(peer net.IP, localDisc uint32, handler func(State)) (*Session, error) {
+	conn, err := listenSourcePort(peer)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Session{
+		peer:                peer,
+		conn:                conn,
+		handler:             handler,
+		state:               StateDown,
+		localDisc:           localDisc,
+		remoteMinRxInterval: 1,
+		wake:                make(chan struct{}, 1),
+		stop:                make(chan struct{}),
+	}
+
+	go s.run()
+
+	return s, nil
+}
+
+// listenSourcePort binds a UDP socket within the RFC 5881 source port range with a TTL of 255.
+func listenSourcePort(peer net.IP) (*net.UDPConn, error) {
+	network := "udp4"
+	if peer.To4() == nil {
+		network = "udp6"
+	}
+
+	start := rand.IntN(sourcePortMax - sourcePortMin + 1)
+	for i := range sourcePortMax - sourcePortMin + 1 {
+		port := sourcePortMin + (start+i)%(sourcePortMax-sourcePortMin+1)
+
+		conn, err := net.ListenUDP(network, &net.UDPAddr{Port: port})
+		if err != nil {
+			continue
+		}
+
+		err = setSocketTTL(conn, network, unix.IP_TTL, unix.IPV6_UNICAST_HOPS)
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+
+		return conn, nil
+	}
+
+	return nil, fmt.Errorf("No available BFD source port for peer %q", peer.String())
+}
+
+// setSocketTTL sets the IPv4 or IPv6 socket option to 255 depending on the socket family.
+func setSocketTTL(conn *net.UDPConn, network string, ipv4Option int, ipv6Option int) error {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var sockErr error
+	err = rawConn.Control(func(fd uintptr) {
+		if network == "udp4" {
+			sockErr = unix.SetsockoptInt(int(fd), syscall.IPPROTO_IP, ipv4Option, 255)
+		} else {
+			sockErr = unix.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, ipv6Option, 255)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return sockErr
+}
+
+// State returns the current local state of the session.
+func (s *Session) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state
+}
+
+// close stops the session, telling the peer that it's being administratively disabled.
+func (s *Session) close() {
+	s.mu.Lock()
+	s.state = StateAdminDown
+	s.diag = DiagAdminDown
+	s.mu.Unlock()
+
+	s.send()
+
+	close(s.stop)
+	_ = s.conn.Close()
+}
+
+// run sends periodic control packets and handles detection timeouts.
+func (s *Session) run() {
+	for {
+		s.checkDetectionTime()
+		s.send()
+
+		timer := time.NewTimer(s.txInterval())
+
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return
+		case <-s.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// txInterval returns the jittered interval until the next control packet (RFC 5880 section 6.8.7).
+func (s *Session) txInterval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	interval := uint32(desiredMinTxInterval)
+	if s.state != StateUp {
+		interval = slowTxInterval
+	}
+
+	interval = max(interval, s.remoteMinRxInterval)
+
+	// Apply a 0-25% reduction as jitter.
+	jitter := 75 + rand.IntN(26)
+
+	return time.Duration(interval) * time.Microsecond * time.Duration(jitter) / 100
+}
+
+// detectionTime returns the time after which the session is declared down (RFC 5880 section 6.8.4).
+func (s *Session) detectionTime() time.Duration {
+	interval := max(uint32(requiredMinRxInterval), s.remoteDesiredMinTxInterval)
+
+	return time.Duration(s.remoteDetectMult) * time.Duration(interval) * time.Microsecond
+}
+
+func (s *Session) checkDetectionTime() {
+	s.mu.Lock()
+
+	if s.state != StateInit && s.state != StateUp {
+		s.mu.Unlock()
+		return
+	}
+
+	if time.Since(s.lastReceived) <= s.detectionTime() {
+		s.mu.Unlock()
+		return
+	}
+
+	s.remoteDisc = 0
+	s.diag = DiagControlTimeExpired
+	changed := s.setState(StateDown)
+	s.mu.Unlock()
+
+	if changed {
+		s.notify(StateDown)
+	}
+}
+
+// send transmits a control packet reflecting the current session state.
+func (s *Session) send() {
+	s.mu.Lock()
+
+	// The peer asked us to stop sending packets.
+	if s.remoteMinRxInterval == 0 && s.state != StateAdminDown {
+		s.mu.Unlock()
+		return
+	}
+
+	packet := ControlPacket{
+		Diagnostic:            s.diag,
+		State:                 s.state,
+		Final:                 s.sendFinal,
+		DetectMult:            detectMult,
+		MyDiscriminator:       s.localDisc,
+		YourDiscriminator:     s.remoteDisc,
+		DesiredMinTxInterval:  desiredMinTxInterval,
+		RequiredMinRxInterval: requiredMinRxInterval,
+	}
+
+	if s.state != StateUp {
+		packet.DesiredMinTxInterval = slowTxInterval
+	}
+
+	s.sendFinal = false
+	s.mu.Unlock()
+
+	_, err := s.conn.WriteToUDP(packet.Marshal(), &net.UDPAddr{IP: s.peer, Port: ports.BFDDefaultPort})
+	if err != nil {
+		logger.Debug("Failed sending BFD control packet", logger.Ctx{"peer": s.peer.String(), "err": err})
+	}
+}
+
+// receive applies a received control packet to the session (RFC 5880 section 6.8.6).
+func (s *Session) receive(packet *ControlPacket) {
+	s.mu.Lock()
+
+	s.remoteDisc = packet.MyDiscriminator
+	s.remoteState = packet.State
+	s.remoteMinRxInterval = packet.RequiredMinRxInterval
+	s.remoteDesiredMinTxInterval = packet.DesiredMinTxInterval
+	s.remoteDetectMult = packet.DetectMult
+	s.lastReceived = time.Now()
+
+	if packet.Poll {
+		s.sendFinal = true
+	}
+
+	if s.state == StateAdminDown {
+		s.mu.Unlock()
+		return
+	}
+
+	newState := s.state
+	if packet.State == StateAdminDown {
+		if s.state != StateDown {
+			s.diag = DiagNeighborSignaledDown
+			newState = StateDown
+		}
+	} else {
+		switch s.state {
+		case StateDown:
+			if packet.State == StateDown {
+				newState = StateInit
+			} else if packet.State == StateInit {
+				newState = StateUp
+			}
+
+		case StateInit:
+			if packet.State == StateInit || packet.State == StateUp {
+				newState = StateUp
+			}
+
+		case StateUp:
+			if packet.State == StateDown {
+				s.diag = DiagNeighborSignaledDown
+				newState = StateDown
+			}
+		}
+	}
+
+	changed := s.setState(newState)
+	wake := changed || packet.Poll
+	s.mu.Unlock()
+
+	if changed {
+		s.notify(newState)
+	}
+
+	// Send an immediate packet to speed up the state transition or answer the poll.
+	if wake {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// setState updates the local state and returns whether it changed. Must be called with the lock held.
+func (s *Session) setState(state State) bool {
+	if s.state == state {
+		return false
+	}
+
+	if state == StateUp {
+		s.diag = DiagNone
+	}
+
+	s.state = state
+
+	return true
+}
+
+func (s *Session) notify(state State) {
+	logger.Debug("BFD session state changed", logger.Ctx{"peer": s.peer.String(), "state": state.String()})
+
+	if s.handler != nil {
+		go s.handler(state)
+	}
+}
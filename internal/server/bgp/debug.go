@@ -29,6 +29,8 @@ type DebugInfoPeer struct {
 	Password string `json:"password" yaml:"password"`
 	Count    int    `json:"count" yaml:"count"`
 	HoldTime uint64 `json:"holdtime" yaml:"holdtime"`
+	BFD      bool   `json:"bfd" yaml:"bfd"`
+	BFDState string `json:"bfd_state" yaml:"bfd_state"`
 }
 
 // Debug returns a dump of the current configuration.
@@ -58,6 +60,14 @@ func (s *Server) debug() DebugInfo {
 		entry.Password = peer.password
 		entry.Count = peer.count
 		entry.HoldTime = peer.holdtime
+		entry.BFD = peer.bfd
+
+		if peer.bfd {
+			bfdState, err := s.bfd.SessionState(peer.address)
+			if err == nil {
+				entry.BFDState = bfdState.String()
+			}
+		}
 
 		debug.Peers = append(debug.Peers, entry)
 	}
@@ -7,6 +7,7 @@ import (
 	"maps"
 	"net"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/google/uuid"
@@ -16,6 +17,7 @@ import (
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/lxc/incus/v6/internal/ports"
+	"github.com/lxc/incus/v6/internal/server/bfd"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
 )
@@ -23,6 +25,7 @@ import (
 // Server represents a BGP server instance.
 type Server struct {
 	bgp *bgpServer.BgpServer
+	bfd *bfd.Server
 
 	// Internal state (to handle reconfiguration)
 	address  string
@@ -31,9 +34,15 @@ type Server struct {
 	paths    map[string]path
 	peers    map[string]peer
 
+	// BFD session state change handlers.
+	bfdHandlers map[string]BFDHandler
+
 	mu sync.Mutex
 }
 
+// BFDHandler is called whenever the BFD session of a peer changes state.
+type BFDHandler func(address net.IP, state string)
+
 type path struct {
 	owner   string
 	prefix  net.IPNet
@@ -45,15 +54,27 @@ type peer struct {
 	asn      uint32
 	password string
 	holdtime uint64
+	bfd      bool
 	count    int
 }
 
+// PeerState represents the live state of a BGP peer.
+type PeerState struct {
+	// State is the BGP session state (e.g. "established").
+	State string
+
+	// BFD is the BFD session state, empty when BFD is disabled.
+	BFD string
+}
+
 // NewServer returns a new server instance.
 func NewServer() *Server {
 	// Setup new struct.
 	s := &Server{
-		paths: map[string]path{},
-		peers: map[string]peer{},
+		bfd:         bfd.NewServer(),
+		paths:       map[string]path{},
+		peers:       map[string]peer{},
+		bfdHandlers: map[string]BFDHandler{},
 	}
 
 	return s
@@ -133,7 +154,7 @@ func (s *Server) start(address string, asn uint32, routerID net.IP) error {
 	// Add existing peers.
 	s.peers = map[string]peer{}
 	for _, peer := range oldPeers {
-		err := s.addPeer(peer.address, peer.asn, peer.password, peer.holdtime)
+		err := s.addPeer(peer.address, peer.asn, peer.password, peer.holdtime, peer.bfd)
 		if err != nil {
 			return err
 		}
@@ -169,6 +190,18 @@ func (s *Server) stop() error {
 	// Restore peer list.
 	s.peers = oldPeers
 
+	// Stop any remaining BFD session.
+	for _, peer := range s.peers {
+		if !peer.bfd {
+			continue
+		}
+
+		err := s.bfd.RemoveSession(peer.address)
+		if err != nil && !errors.Is(err, bfd.ErrSessionNotFound) {
+			return err
+		}
+	}
+
 	// Stop the listener.
 	err := s.bgp.StopBgp(context.Background(), &bgpAPI.StopBgpRequest{})
 	if err != nil {
@@ -391,15 +424,15 @@ func (s *Server) removePrefixByUUID(pathUUID string) error {
 }
 
 // AddPeer adds a new BGP peer.
-func (s *Server) AddPeer(address net.IP, asn uint32, password string, holdTime uint64) error {
+func (s *Server) AddPeer(address net.IP, asn uint32, password string, holdTime uint64, bfdEnabled bool) error {
 	// Locking.
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	return s.addPeer(address, asn, password, holdTime)
+	return s.addPeer(address, asn, password, holdTime, bfdEnabled)
 }
 
-func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime uint64) error {
+func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime uint64, bfdEnabled bool) error {
 	// Look for an existing peer.
 	bgpPeer, bgpPeerExists := s.peers[address.String()]
 	if bgpPeerExists {
@@ -411,6 +444,10 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 			return fmt.Errorf("Peer %q already used but with a different password", address)
 		}
 
+		if bgpPeer.bfd != bfdEnabled {
+			return fmt.Errorf("Peer %q already used but with a different BFD setting", address)
+		}
+
 		// Reuse the existing entry.
 		bgpPeer.count++
 		s.peers[address.String()] = bgpPeer
@@ -478,6 +515,15 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 		if err != nil {
 			return err
 		}
+
+		// Start the BFD session to quickly detect the peer going away.
+		if bfdEnabled {
+			err := s.bfd.AddSession(address, func(state bfd.State) { s.bfdStateChanged(address, state) })
+			if err != nil {
+				_ = s.bgp.DeletePeer(context.Background(), &bgpAPI.DeletePeerRequest{Address: address.String()})
+				return err
+			}
+		}
 	}
 
 	// Add the peer to the list.
@@ -490,6 +536,7 @@ func (s *Server) addPeer(address net.IP, asn uint32, password string, holdTime u
 			asn:      asn,
 			password: password,
 			holdtime: holdTime,
+			bfd:      bfdEnabled,
 			count:    1,
 		}
 	}
@@ -521,6 +568,14 @@ func (s *Server) removePeer(address net.IP) error {
 		}
 	}
 
+	// Stop the BFD session.
+	if bgpPeer.bfd && bgpPeer.count == 1 {
+		err := s.bfd.RemoveSession(address)
+		if err != nil && !errors.Is(err, bfd.ErrSessionNotFound) {
+			return err
+		}
+	}
+
 	// Update peer list.
 	if bgpPeer.count == 1 {
 		// Delete the peer.
@@ -533,3 +588,79 @@ func (s *Server) removePeer(address net.IP) error {
 
 	return nil
 }
+
+// GetPeerState returns the current BGP session state and BFD state of a peer.
+func (s *Server) GetPeerState(address net.IP) (*PeerState, error) {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bgpPeer, bgpPeerExists := s.peers[address.String()]
+	if !bgpPeerExists {
+		return nil, ErrPeerNotFound
+	}
+
+	state := PeerState{State: "idle"}
+
+	// Get the session state from the BGP server.
+	if s.bgp != nil {
+		err := s.bgp.ListPeer(context.Background(), &bgpAPI.ListPeerRequest{Address: address.String()}, func(p *bgpAPI.Peer) {
+			if p.State != nil {
+				state.State = strings.ToLower(p.State.SessionState.String())
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Get the BFD session state.
+	if bgpPeer.bfd {
+		bfdState, err := s.bfd.SessionState(address)
+		if err != nil && !errors.Is(err, bfd.ErrSessionNotFound) {
+			return nil, err
+		}
+
+		state.BFD = bfdState.String()
+	}
+
+	return &state, nil
+}
+
+// AddBFDHandler registers a new handler for BFD session state changes.
+func (s *Server) AddBFDHandler(name string, handler BFDHandler) {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bfdHandlers[name] = handler
+}
+
+// RemoveBFDHandler removes a currently registered BFD handler.
+func (s *Server) RemoveBFDHandler(name string) {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.bfdHandlers, name)
+}
+
+// bfdStateChanged tears down the BGP session when BFD detects the peer going away and notifies the handlers.
+func (s *Server) bfdStateChanged(address net.IP, state bfd.State) {
+	// Locking.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state == bfd.StateDown && s.bgp != nil {
+		logger.Warn("BFD session went down, resetting BGP peer", logger.Ctx{"peer": address.String()})
+
+		err := s.bgp.ResetPeer(context.Background(), &bgpAPI.ResetPeerRequest{Address: address.String(), Communication: "BFD session down"})
+		if err != nil {
+			logger.Error("Failed resetting BGP peer", logger.Ctx{"peer": address.String(), "err": err})
+		}
+	}
+
+	for _, handler := range s.bfdHandlers {
+		go handler(address, state.String())
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/config"
 	"github.com/lxc/incus/v6/internal/server/db"
 	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/shared/util"
 	"github.com/lxc/incus/v6/shared/validate"
 )
 
@@ -329,6 +330,12 @@ func (c *Config) NetworkHWAddrPattern() string {
 	return c.m.GetString("network.hwaddr_pattern")
 }
 
+// NetworkHostACLs returns the names of the ACLs protecting the listeners of the servers, along with the action and
+// logging mode of their default rule.
+func (c *Config) NetworkHostACLs() ([]string, string, bool) {
+	return util.SplitNTrimSpace(c.m.GetString("network.host.acls"), ",", -1, true), c.m.GetString("network.host.acls.default.ingress.action"), c.m.GetBool("network.host.acls.default.ingress.logged")
+}
+
 // Loggers returns a map where the key is the logger name and the value is its type.
 func (c *Config) Loggers() (map[string]string, error) {
 	result := make(map[string]string)
@@ -920,6 +927,34 @@ var ConfigSchema = config.Schema{
 	// shortdesc: MAC address template
 	"network.hwaddr_pattern": {Default: "10:66:6a:xx:xx:xx", Validator: validate.Optional(validate.IsMACPattern)},
 
+	// gendoc:generate(entity=server, group=network, key=network.host.acls)
+	// Specify a comma-separated list of network ACLs of the default project.
+	// Their ingress rules are applied to the traffic sent to the listeners of the servers (`core.https_address`, `cluster.https_address`, `core.metrics_address`, `core.storage_buckets_address`, `core.bgp_address` and `core.dns_address`).
+	// See {ref}`network-acls-host`.
+	// ---
+	// type: string
+	// scope: global
+	// shortdesc: Network ACLs protecting the listeners of the servers
+	"network.host.acls": {Validator: validate.Optional(validate.IsListOf(validate.IsAny))},
+
+	// gendoc:generate(entity=server, group=network, key=network.host.acls.default.ingress.action)
+	//
+	// ---
+	// type: string
+	// scope: global
+	// defaultdesc: `reject`
+	// shortdesc: Action to use for the traffic sent to the listeners of the servers that doesn't match any ACL rule
+	"network.host.acls.default.ingress.action": {Default: "reject", Validator: validate.IsOneOf("allow", "reject", "drop")},
+
+	// gendoc:generate(entity=server, group=network, key=network.host.acls.default.ingress.logged)
+	//
+	// ---
+	// type: bool
+	// scope: global
+	// defaultdesc: `false`
+	// shortdesc: Whether to log the traffic sent to the listeners of the servers that doesn't match any ACL rule
+	"network.host.acls.default.ingress.logged": {Type: config.Bool, Default: "false"},
+
 	// gendoc:generate(entity=server, group=openfga, key=openfga.api.token)
 	//
 	// ---
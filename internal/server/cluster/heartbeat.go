@@ -54,6 +54,7 @@ type APIHeartbeatMember struct {
 	LastHeartbeat time.Time        // Last time we received a successful response from node.
 	Online        bool             // Calculated from offline threshold and LastHeatbeat time.
 	Roles         []db.ClusterRole // Supplementary non-database roles the member has.
+	Groups        []string         // Cluster groups the member belongs to.
 	updated       bool             // Has node been updated during this heartbeat run. Not sent to nodes.
 }
 
@@ -112,6 +113,7 @@ func (hbState *APIHeartbeat) Update(fullStateList bool, raftNodes []db.RaftNode,
 			LastHeartbeat: node.Heartbeat,
 			Online:        !node.IsOffline(offlineThreshold),
 			Roles:         node.Roles,
+			Groups:        node.Groups,
 		}
 
 		raftNode, exists := raftNodeMap[member.Address]
@@ -0,0 +1,73 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target networks_floating_ips.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//generate-database:mapper stmt -e network_floating_ip objects table=networks_floating_ips
+//generate-database:mapper stmt -e network_floating_ip objects-by-NetworkID table=networks_floating_ips
+//generate-database:mapper stmt -e network_floating_ip objects-by-NetworkID-and-Address table=networks_floating_ips
+//generate-database:mapper stmt -e network_floating_ip objects-by-NetworkID-and-Project-and-Instance table=networks_floating_ips
+//generate-database:mapper stmt -e network_floating_ip id table=networks_floating_ips
+//generate-database:mapper stmt -e network_floating_ip create table=networks_floating_ips
+//generate-database:mapper stmt -e network_floating_ip update table=networks_floating_ips
+//generate-database:mapper stmt -e network_floating_ip delete-by-NetworkID-and-Address table=networks_floating_ips
+//
+//generate-database:mapper method -i -e network_floating_ip GetMany references=Config table=networks_floating_ips
+//generate-database:mapper method -i -e network_floating_ip GetOne table=networks_floating_ips
+//generate-database:mapper method -i -e network_floating_ip ID table=networks_floating_ips
+//generate-database:mapper method -i -e network_floating_ip Create references=Config table=networks_floating_ips
+//generate-database:mapper method -i -e network_floating_ip Update references=Config table=networks_floating_ips
+//generate-database:mapper method -i -e network_floating_ip DeleteOne-by-NetworkID-and-Address table=networks_floating_ips
+
+// NetworkFloatingIP is the generated entity backing the networks_floating_ips table.
+type NetworkFloatingIP struct {
+	ID          int64
+	NetworkID   int64  `db:"primary=yes&column=network_id"`
+	Address     string `db:"primary=yes"`
+	Description string
+	Project     string
+	Instance    string
+}
+
+// NetworkFloatingIPFilter defines the optional WHERE-clause fields.
+type NetworkFloatingIPFilter struct {
+	ID        *int64
+	NetworkID *int64
+	Address   *string
+	Project   *string
+	Instance  *string
+}
+
+// ToAPI converts the DB record into the external API type.
+func (n *NetworkFloatingIP) ToAPI(ctx context.Context, tx *sql.Tx) (*api.NetworkFloatingIP, error) {
+	// Get the config.
+	cfg, err := GetNetworkFloatingIPConfig(ctx, tx, int(n.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill in the struct.
+	out := api.NetworkFloatingIP{
+		NetworkFloatingIPPut: api.NetworkFloatingIPPut{
+			Description: n.Description,
+			Instance:    n.Instance,
+			Config:      cfg,
+		},
+
+		Address: n.Address,
+		Project: n.Project,
+	}
+
+	return &out, nil
+}
@@ -0,0 +1,44 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// NetworkFloatingIPGenerated is an interface of generated methods for NetworkFloatingIP.
+type NetworkFloatingIPGenerated interface {
+	// GetNetworkFloatingIPConfig returns all available NetworkFloatingIP Config
+	// generator: network_floating_ip GetMany
+	GetNetworkFloatingIPConfig(ctx context.Context, db tx, networkFloatingIPID int, filters ...ConfigFilter) (map[string]string, error)
+
+	// GetNetworkFloatingIPs returns all available network_floating_ips.
+	// generator: network_floating_ip GetMany
+	GetNetworkFloatingIPs(ctx context.Context, db dbtx, filters ...NetworkFloatingIPFilter) ([]NetworkFloatingIP, error)
+
+	// GetNetworkFloatingIP returns the network_floating_ip with the given key.
+	// generator: network_floating_ip GetOne
+	GetNetworkFloatingIP(ctx context.Context, db dbtx, networkID int64, address string) (*NetworkFloatingIP, error)
+
+	// GetNetworkFloatingIPID return the ID of the network_floating_ip with the given key.
+	// generator: network_floating_ip ID
+	GetNetworkFloatingIPID(ctx context.Context, db tx, networkID int64, address string) (int64, error)
+
+	// CreateNetworkFloatingIPConfig adds new network_floating_ip Config to the database.
+	// generator: network_floating_ip Create
+	CreateNetworkFloatingIPConfig(ctx context.Context, db dbtx, networkFloatingIPID int64, config map[string]string) error
+
+	// CreateNetworkFloatingIP adds a new network_floating_ip to the database.
+	// generator: network_floating_ip Create
+	CreateNetworkFloatingIP(ctx context.Context, db dbtx, object NetworkFloatingIP) (int64, error)
+
+	// UpdateNetworkFloatingIPConfig updates the network_floating_ip Config matching the given key parameters.
+	// generator: network_floating_ip Update
+	UpdateNetworkFloatingIPConfig(ctx context.Context, db tx, networkFloatingIPID int64, config map[string]string) error
+
+	// UpdateNetworkFloatingIP updates the network_floating_ip matching the given key parameters.
+	// generator: network_floating_ip Update
+	UpdateNetworkFloatingIP(ctx context.Context, db tx, networkID int64, address string, object NetworkFloatingIP) error
+
+	// DeleteNetworkFloatingIP deletes the network_floating_ip matching the given key parameters.
+	// generator: network_floating_ip DeleteOne-by-NetworkID-and-Address
+	DeleteNetworkFloatingIP(ctx context.Context, db dbtx, networkID int64, address string) error
+}
@@ -0,0 +1,74 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target networks_reservations.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//generate-database:mapper stmt -e network_reservation objects table=networks_reservations
+//generate-database:mapper stmt -e network_reservation objects-by-NetworkID table=networks_reservations
+//generate-database:mapper stmt -e network_reservation objects-by-NetworkID-and-Name table=networks_reservations
+//generate-database:mapper stmt -e network_reservation id table=networks_reservations
+//generate-database:mapper stmt -e network_reservation create table=networks_reservations
+//generate-database:mapper stmt -e network_reservation update table=networks_reservations
+//generate-database:mapper stmt -e network_reservation delete-by-NetworkID-and-Name table=networks_reservations
+//
+//generate-database:mapper method -i -e network_reservation GetMany references=Config table=networks_reservations
+//generate-database:mapper method -i -e network_reservation GetOne table=networks_reservations
+//generate-database:mapper method -i -e network_reservation ID table=networks_reservations
+//generate-database:mapper method -i -e network_reservation Create references=Config table=networks_reservations
+//generate-database:mapper method -i -e network_reservation Update references=Config table=networks_reservations
+//generate-database:mapper method -i -e network_reservation DeleteOne-by-NetworkID-and-Name table=networks_reservations
+
+// NetworkReservation is the generated entity backing the networks_reservations table.
+type NetworkReservation struct {
+	ID          int64
+	NetworkID   int64  `db:"primary=yes&column=network_id"`
+	Name        string `db:"primary=yes"`
+	Description string
+	Hwaddr      string
+	DUID        string `db:"column=duid"`
+	IPv4Address string `db:"column=ipv4_address"`
+	IPv6Address string `db:"column=ipv6_address"`
+}
+
+// NetworkReservationFilter defines the optional WHERE-clause fields.
+type NetworkReservationFilter struct {
+	ID        *int64
+	NetworkID *int64
+	Name      *string
+}
+
+// ToAPI converts the DB record into the external API type.
+func (n *NetworkReservation) ToAPI(ctx context.Context, tx *sql.Tx) (*api.NetworkReservation, error) {
+	// Get the config.
+	cfg, err := GetNetworkReservationConfig(ctx, tx, int(n.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill in the struct.
+	out := api.NetworkReservation{
+		NetworkReservationPut: api.NetworkReservationPut{
+			Description: n.Description,
+			Hwaddr:      n.Hwaddr,
+			DUID:        n.DUID,
+			IPv4Address: n.IPv4Address,
+			IPv6Address: n.IPv6Address,
+			Config:      cfg,
+		},
+
+		Name: n.Name,
+	}
+
+	return &out, nil
+}
@@ -0,0 +1,44 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// NetworkReservationGenerated is an interface of generated methods for NetworkReservation.
+type NetworkReservationGenerated interface {
+	// GetNetworkReservationConfig returns all available NetworkReservation Config
+	// generator: network_reservation GetMany
+	GetNetworkReservationConfig(ctx context.Context, db tx, networkReservationID int, filters ...ConfigFilter) (map[string]string, error)
+
+	// GetNetworkReservations returns all available network_reservations.
+	// generator: network_reservation GetMany
+	GetNetworkReservations(ctx context.Context, db dbtx, filters ...NetworkReservationFilter) ([]NetworkReservation, error)
+
+	// GetNetworkReservation returns the network_reservation with the given key.
+	// generator: network_reservation GetOne
+	GetNetworkReservation(ctx context.Context, db dbtx, networkID int64, name string) (*NetworkReservation, error)
+
+	// GetNetworkReservationID return the ID of the network_reservation with the given key.
+	// generator: network_reservation ID
+	GetNetworkReservationID(ctx context.Context, db tx, networkID int64, name string) (int64, error)
+
+	// CreateNetworkReservationConfig adds new network_reservation Config to the database.
+	// generator: network_reservation Create
+	CreateNetworkReservationConfig(ctx context.Context, db dbtx, networkReservationID int64, config map[string]string) error
+
+	// CreateNetworkReservation adds a new network_reservation to the database.
+	// generator: network_reservation Create
+	CreateNetworkReservation(ctx context.Context, db dbtx, object NetworkReservation) (int64, error)
+
+	// UpdateNetworkReservationConfig updates the network_reservation Config matching the given key parameters.
+	// generator: network_reservation Update
+	UpdateNetworkReservationConfig(ctx context.Context, db tx, networkReservationID int64, config map[string]string) error
+
+	// UpdateNetworkReservation updates the network_reservation matching the given key parameters.
+	// generator: network_reservation Update
+	UpdateNetworkReservation(ctx context.Context, db tx, networkID int64, name string, object NetworkReservation) error
+
+	// DeleteNetworkReservation deletes the network_reservation matching the given key parameters.
+	// generator: network_reservation DeleteOne-by-NetworkID-and-Name
+	DeleteNetworkReservation(ctx context.Context, db dbtx, networkID int64, name string) error
+}
@@ -0,0 +1,42 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"time"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target networks_zones_keys.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+// Statements:
+//generate-database:mapper stmt -e NetworkZoneKey objects table=networks_zones_keys
+//generate-database:mapper stmt -e NetworkZoneKey objects-by-ID table=networks_zones_keys
+//generate-database:mapper stmt -e NetworkZoneKey objects-by-NetworkZoneID table=networks_zones_keys
+//generate-database:mapper stmt -e NetworkZoneKey create table=networks_zones_keys
+//generate-database:mapper stmt -e NetworkZoneKey delete-by-ID table=networks_zones_keys
+//
+// Methods:
+//generate-database:mapper method -i -e NetworkZoneKey GetMany table=networks_zones_keys
+//generate-database:mapper method -i -e NetworkZoneKey Create table=networks_zones_keys
+//generate-database:mapper method -i -e NetworkZoneKey DeleteOne-by-ID table=networks_zones_keys
+
+// NetworkZoneKey is a value object holding db-related details about a DNSSEC key of a network zone.
+type NetworkZoneKey struct {
+	ID             int `db:"order=yes"`
+	NetworkZoneID  int `db:"primary=yes"`
+	Flags          int
+	Algorithm      int
+	PublicKey      string
+	PrivateKey     string
+	CreationDate   time.Time
+	ActivationDate time.Time
+}
+
+// NetworkZoneKeyFilter defines the optional WHERE-clause fields.
+type NetworkZoneKeyFilter struct {
+	ID            *int
+	NetworkZoneID *int
+}
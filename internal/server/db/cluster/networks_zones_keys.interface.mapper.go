@@ -0,0 +1,20 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// NetworkZoneKeyGenerated is an interface of generated methods for NetworkZoneKey.
+type NetworkZoneKeyGenerated interface {
+	// GetNetworkZoneKeys returns all available NetworkZoneKeys.
+	// generator: NetworkZoneKey GetMany
+	GetNetworkZoneKeys(ctx context.Context, db dbtx, filters ...NetworkZoneKeyFilter) ([]NetworkZoneKey, error)
+
+	// CreateNetworkZoneKey adds a new NetworkZoneKey to the database.
+	// generator: NetworkZoneKey Create
+	CreateNetworkZoneKey(ctx context.Context, db dbtx, object NetworkZoneKey) (int64, error)
+
+	// DeleteNetworkZoneKey deletes the NetworkZoneKey matching the given key parameters.
+	// generator: NetworkZoneKey DeleteOne-by-ID
+	DeleteNetworkZoneKey(ctx context.Context, db dbtx, id int) error
+}
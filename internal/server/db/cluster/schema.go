@@ -266,6 +266,24 @@ CREATE TABLE "networks_config" (
     FOREIGN KEY (network_id) REFERENCES "networks" (id) ON DELETE CASCADE,
     FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
 );
+CREATE TABLE "networks_floating_ips" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_id INTEGER NOT NULL,
+    address TEXT NOT NULL,
+    description TEXT NOT NULL,
+    project TEXT NOT NULL,
+    instance TEXT NOT NULL,
+    UNIQUE (network_id, address),
+    FOREIGN KEY (network_id) REFERENCES "networks" (id) ON DELETE CASCADE
+);
+CREATE TABLE "networks_floating_ips_config" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_floating_ip_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (network_floating_ip_id, key),
+    FOREIGN KEY (network_floating_ip_id) REFERENCES "networks_floating_ips" (id) ON DELETE CASCADE
+);
 CREATE TABLE "networks_forwards" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     network_id INTEGER NOT NULL,
@@ -300,6 +318,20 @@ CREATE TABLE networks_integrations_config (
     UNIQUE (network_integration_id, key),
     FOREIGN KEY (network_integration_id) REFERENCES networks_integrations (id) ON DELETE CASCADE
 );
+CREATE TABLE "networks_leases_history" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_id INTEGER NOT NULL,
+    date DATETIME NOT NULL,
+    type TEXT NOT NULL,
+    hostname TEXT NOT NULL,
+    hwaddr TEXT NOT NULL,
+    address TEXT NOT NULL,
+    project TEXT NOT NULL,
+    instance TEXT NOT NULL,
+    location TEXT NOT NULL,
+    FOREIGN KEY (network_id) REFERENCES "networks" (id) ON DELETE CASCADE
+);
+CREATE INDEX networks_leases_history_network_id_idx ON networks_leases_history (network_id);
 CREATE TABLE "networks_load_balancers" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     network_id INTEGER NOT NULL,
@@ -352,6 +384,41 @@ CREATE TABLE "networks_peers_config" (
     UNIQUE (network_peer_id, key),
     FOREIGN KEY (network_peer_id) REFERENCES "networks_peers" (id) ON DELETE CASCADE
 );
+CREATE TABLE "networks_prefix_delegations" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    prefix TEXT NOT NULL,
+    duid TEXT NOT NULL,
+    iaid INTEGER NOT NULL,
+    hwaddr TEXT NOT NULL,
+    next_hop TEXT NOT NULL,
+    expiry DATETIME,
+    UNIQUE (network_id, prefix),
+    UNIQUE (network_id, duid, iaid),
+    FOREIGN KEY (network_id) REFERENCES "networks" (id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
+);
+CREATE TABLE "networks_reservations" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    hwaddr TEXT NOT NULL,
+    duid TEXT NOT NULL,
+    ipv4_address TEXT NOT NULL,
+    ipv6_address TEXT NOT NULL,
+    UNIQUE (network_id, name),
+    FOREIGN KEY (network_id) REFERENCES "networks" (id) ON DELETE CASCADE
+);
+CREATE TABLE "networks_reservations_config" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_reservation_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (network_reservation_id, key),
+    FOREIGN KEY (network_reservation_id) REFERENCES "networks_reservations" (id) ON DELETE CASCADE
+);
 CREATE UNIQUE INDEX networks_unique_network_id_node_id_key ON "networks_config" (network_id, IFNULL(node_id, -1), key);
 CREATE TABLE "networks_zones" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
@@ -369,6 +436,17 @@ CREATE TABLE "networks_zones_config" (
     UNIQUE (network_zone_id, key),
     FOREIGN KEY (network_zone_id) REFERENCES "networks_zones" (id) ON DELETE CASCADE
 );
+CREATE TABLE "networks_zones_keys" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_zone_id INTEGER NOT NULL,
+    flags INTEGER NOT NULL,
+    algorithm INTEGER NOT NULL,
+    public_key TEXT NOT NULL,
+    private_key TEXT NOT NULL,
+    creation_date DATETIME NOT NULL,
+    activation_date DATETIME NOT NULL,
+    FOREIGN KEY (network_zone_id) REFERENCES networks_zones (id) ON DELETE CASCADE
+);
 CREATE TABLE "networks_zones_records" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     network_zone_id INTEGER NOT NULL,
@@ -484,6 +562,28 @@ CREATE TABLE "projects_config" (
     FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
     UNIQUE (project_id, key)
 );
+CREATE TABLE "projects_network_counters" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    bytes_received INTEGER NOT NULL DEFAULT 0,
+    bytes_sent INTEGER NOT NULL DEFAULT 0,
+    packets_received INTEGER NOT NULL DEFAULT 0,
+    packets_sent INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (project_id, node_id),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
+);
+CREATE TABLE "proxy_certificates" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    certificate TEXT NOT NULL,
+    key TEXT NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
 CREATE TABLE "storage_buckets" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     name TEXT NOT NULL,
@@ -674,5 +774,5 @@ CREATE TABLE "warnings" (
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
 
-INSERT INTO schema (version, updated_at) VALUES (76, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (83, strftime("%s"))
 `
@@ -113,6 +113,197 @@ var updates = map[int]schema.Update{
 	74: updateFromV73,
 	75: updateFromV74,
 	76: updateFromV75,
+	77: updateFromV76,
+	78: updateFromV77,
+	79: updateFromV78,
+	80: updateFromV79,
+	81: updateFromV80,
+	82: updateFromV81,
+	83: updateFromV82,
+}
+
+// updateFromV82 adds a table to store the certificates of the proxy devices of projects.
+func updateFromV82(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "proxy_certificates" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    certificate TEXT NOT NULL,
+    key TEXT NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding proxy_certificates table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV81 adds a table to store the IPv6 prefixes delegated to instances.
+func updateFromV81(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "networks_prefix_delegations" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    prefix TEXT NOT NULL,
+    duid TEXT NOT NULL,
+    iaid INTEGER NOT NULL,
+    hwaddr TEXT NOT NULL,
+    next_hop TEXT NOT NULL,
+    expiry DATETIME,
+    UNIQUE (network_id, prefix),
+    UNIQUE (network_id, duid, iaid),
+    FOREIGN KEY (network_id) REFERENCES "networks" (id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding networks_prefix_delegations table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV80 adds a table to store the cumulative network traffic counters of projects.
+func updateFromV80(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "projects_network_counters" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    bytes_received INTEGER NOT NULL DEFAULT 0,
+    bytes_sent INTEGER NOT NULL DEFAULT 0,
+    packets_received INTEGER NOT NULL DEFAULT 0,
+    packets_sent INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (project_id, node_id),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding projects_network_counters table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV79 adds tables to store the floating IPs of networks.
+func updateFromV79(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "networks_floating_ips" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_id INTEGER NOT NULL,
+    address TEXT NOT NULL,
+    description TEXT NOT NULL,
+    project TEXT NOT NULL,
+    instance TEXT NOT NULL,
+    UNIQUE (network_id, address),
+    FOREIGN KEY (network_id) REFERENCES "networks" (id) ON DELETE CASCADE
+);
+CREATE TABLE "networks_floating_ips_config" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_floating_ip_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (network_floating_ip_id, key),
+    FOREIGN KEY (network_floating_ip_id) REFERENCES "networks_floating_ips" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding networks_floating_ips tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV78 adds a table to store the DHCP lease history of networks.
+func updateFromV78(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "networks_leases_history" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_id INTEGER NOT NULL,
+    date DATETIME NOT NULL,
+    type TEXT NOT NULL,
+    hostname TEXT NOT NULL,
+    hwaddr TEXT NOT NULL,
+    address TEXT NOT NULL,
+    project TEXT NOT NULL,
+    instance TEXT NOT NULL,
+    location TEXT NOT NULL,
+    FOREIGN KEY (network_id) REFERENCES "networks" (id) ON DELETE CASCADE
+);
+CREATE INDEX networks_leases_history_network_id_idx ON networks_leases_history (network_id);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding networks_leases_history table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV77 adds tables to store the DHCP reservations of networks.
+func updateFromV77(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "networks_reservations" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    hwaddr TEXT NOT NULL,
+    duid TEXT NOT NULL,
+    ipv4_address TEXT NOT NULL,
+    ipv6_address TEXT NOT NULL,
+    UNIQUE (network_id, name),
+    FOREIGN KEY (network_id) REFERENCES "networks" (id) ON DELETE CASCADE
+);
+CREATE TABLE "networks_reservations_config" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_reservation_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (network_reservation_id, key),
+    FOREIGN KEY (network_reservation_id) REFERENCES "networks_reservations" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding networks_reservations tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV76 adds a table to store the DNSSEC keys of network zones.
+func updateFromV76(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "networks_zones_keys" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    network_zone_id INTEGER NOT NULL,
+    flags INTEGER NOT NULL,
+    algorithm INTEGER NOT NULL,
+    public_key TEXT NOT NULL,
+    private_key TEXT NOT NULL,
+    creation_date DATETIME NOT NULL,
+    activation_date DATETIME NOT NULL,
+    FOREIGN KEY (network_zone_id) REFERENCES networks_zones (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding networks_zones_keys table: %w", err)
+	}
+
+	return nil
 }
 
 func updateFromV75(ctx context.Context, tx *sql.Tx) error {
@@ -0,0 +1,83 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// NetworkLeaseHistoryMax is the number of DHCP lease events retained per network.
+const NetworkLeaseHistoryMax = 1000
+
+// NetworkLeaseEventFilter can be used to filter the results of GetNetworkLeaseEvents.
+type NetworkLeaseEventFilter struct {
+	Project *string
+	Hwaddr  *string
+	Address *string
+}
+
+// CreateNetworkLeaseEvent records a DHCP lease event for the given network.
+//
+// The oldest events are pruned so that at most NetworkLeaseHistoryMax events are kept for the network.
+func (c *ClusterTx) CreateNetworkLeaseEvent(ctx context.Context, networkID int64, event api.NetworkLeaseEvent) error {
+	q := `INSERT INTO networks_leases_history (network_id, date, type, hostname, hwaddr, address, project, instance, location) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := c.tx.ExecContext(ctx, q, networkID, event.Timestamp, event.Type, event.Hostname, event.Hwaddr, event.Address, event.Project, event.Instance, event.Location)
+	if err != nil {
+		return fmt.Errorf("Failed recording network lease event: %w", err)
+	}
+
+	q = `DELETE FROM networks_leases_history WHERE network_id = ? AND id NOT IN (SELECT id FROM networks_leases_history WHERE network_id = ? ORDER BY id DESC LIMIT ?)`
+	_, err = c.tx.ExecContext(ctx, q, networkID, networkID, NetworkLeaseHistoryMax)
+	if err != nil {
+		return fmt.Errorf("Failed pruning network lease history: %w", err)
+	}
+
+	return nil
+}
+
+// GetNetworkLeaseEvents returns the recorded DHCP lease events of the given network, most recent first.
+func (c *ClusterTx) GetNetworkLeaseEvents(ctx context.Context, networkID int64, filter NetworkLeaseEventFilter) ([]api.NetworkLeaseEvent, error) {
+	where := []string{"network_id = ?"}
+	args := []any{networkID}
+
+	if filter.Project != nil {
+		where = append(where, "project = ?")
+		args = append(args, *filter.Project)
+	}
+
+	if filter.Hwaddr != nil {
+		where = append(where, "hwaddr = ?")
+		args = append(args, *filter.Hwaddr)
+	}
+
+	if filter.Address != nil {
+		where = append(where, "address = ?")
+		args = append(args, *filter.Address)
+	}
+
+	q := fmt.Sprintf(`SELECT date, type, hostname, hwaddr, address, project, instance, location FROM networks_leases_history WHERE %s ORDER BY id DESC`, strings.Join(where, " AND "))
+
+	events := []api.NetworkLeaseEvent{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		event := api.NetworkLeaseEvent{}
+
+		err := scan(&event.Timestamp, &event.Type, &event.Hostname, &event.Hwaddr, &event.Address, &event.Project, &event.Instance, &event.Location)
+		if err != nil {
+			return err
+		}
+
+		events = append(events, event)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
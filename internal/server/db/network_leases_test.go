@@ -0,0 +1,75 @@
+//go:build linux && cgo && !agent
+
+package db_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Lease events are returned most recent first and can be filtered.
+func TestGetNetworkLeaseEvents(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	networkID, err := tx.CreateNetwork(context.Background(), api.ProjectDefaultName, "incusbr0", "", db.NetworkTypeBridge, nil)
+	require.NoError(t, err)
+
+	for _, event := range []api.NetworkLeaseEvent{
+		{Type: "acquired", Hwaddr: "10:66:6a:00:00:01", Address: "10.0.0.10", Project: "default", Instance: "c1"},
+		{Type: "acquired", Hwaddr: "10:66:6a:00:00:02", Address: "10.0.0.11", Project: "p1", Instance: "c2"},
+		{Type: "released", Hwaddr: "10:66:6a:00:00:01", Address: "10.0.0.10", Project: "default", Instance: "c1"},
+	} {
+		event.Timestamp = time.Now().UTC()
+		err = tx.CreateNetworkLeaseEvent(context.Background(), networkID, event)
+		require.NoError(t, err)
+	}
+
+	events, err := tx.GetNetworkLeaseEvents(context.Background(), networkID, db.NetworkLeaseEventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "released", events[0].Type)
+	assert.Equal(t, "c1", events[0].Instance)
+
+	hwaddr := "10:66:6a:00:00:01"
+	events, err = tx.GetNetworkLeaseEvents(context.Background(), networkID, db.NetworkLeaseEventFilter{Hwaddr: &hwaddr})
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	projectName := "p1"
+	events, err = tx.GetNetworkLeaseEvents(context.Background(), networkID, db.NetworkLeaseEventFilter{Project: &projectName})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "10.0.0.11", events[0].Address)
+}
+
+// Only the most recent lease events are retained.
+func TestCreateNetworkLeaseEvent_Prune(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	networkID, err := tx.CreateNetwork(context.Background(), api.ProjectDefaultName, "incusbr0", "", db.NetworkTypeBridge, nil)
+	require.NoError(t, err)
+
+	for i := range db.NetworkLeaseHistoryMax + 10 {
+		err = tx.CreateNetworkLeaseEvent(context.Background(), networkID, api.NetworkLeaseEvent{
+			Type:      "acquired",
+			Timestamp: time.Now().UTC(),
+			Address:   fmt.Sprintf("10.0.%d.%d", i/250, i%250+1),
+		})
+		require.NoError(t, err)
+	}
+
+	events, err := tx.GetNetworkLeaseEvents(context.Background(), networkID, db.NetworkLeaseEventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, db.NetworkLeaseHistoryMax)
+	assert.Equal(t, fmt.Sprintf("10.0.%d.%d", (db.NetworkLeaseHistoryMax+9)/250, (db.NetworkLeaseHistoryMax+9)%250+1), events[0].Address)
+}
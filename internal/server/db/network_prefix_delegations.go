@@ -0,0 +1,121 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+)
+
+// NetworkPrefixDelegation represents an IPv6 prefix delegated to a client of a network.
+type NetworkPrefixDelegation struct {
+	Prefix   string
+	DUID     string
+	IAID     uint32
+	Hwaddr   string
+	NextHop  string
+	Expiry   time.Time // Zero for delegations which don't expire.
+	Location string
+}
+
+// GetNetworkPrefixDelegations returns the IPv6 prefixes delegated on the network.
+// If local is true, only the delegations handled by this member are returned.
+func (c *ClusterTx) GetNetworkPrefixDelegations(ctx context.Context, networkID int64, local bool) ([]NetworkPrefixDelegation, error) {
+	q := `
+SELECT networks_prefix_delegations.prefix, networks_prefix_delegations.duid, networks_prefix_delegations.iaid, networks_prefix_delegations.hwaddr, networks_prefix_delegations.next_hop, networks_prefix_delegations.expiry, nodes.name
+  FROM networks_prefix_delegations
+  JOIN nodes ON nodes.id = networks_prefix_delegations.node_id
+  WHERE networks_prefix_delegations.network_id = ?
+`
+	args := []any{networkID}
+
+	if local {
+		q += "  AND networks_prefix_delegations.node_id = ?\n"
+		args = append(args, c.nodeID)
+	}
+
+	q += "  ORDER BY networks_prefix_delegations.id"
+
+	delegations := []NetworkPrefixDelegation{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var delegation NetworkPrefixDelegation
+		var expiry sql.NullTime
+
+		err := scan(&delegation.Prefix, &delegation.DUID, &delegation.IAID, &delegation.Hwaddr, &delegation.NextHop, &expiry, &delegation.Location)
+		if err != nil {
+			return err
+		}
+
+		if expiry.Valid {
+			delegation.Expiry = expiry.Time
+		}
+
+		delegations = append(delegations, delegation)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return delegations, nil
+}
+
+// UpsertNetworkPrefixDelegation records the prefix delegated to a client identity association of the network,
+// handled by this member.
+func (c *ClusterTx) UpsertNetworkPrefixDelegation(ctx context.Context, networkID int64, delegation NetworkPrefixDelegation) error {
+	var expiry any
+	if !delegation.Expiry.IsZero() {
+		expiry = delegation.Expiry.UTC()
+	}
+
+	q := `
+INSERT INTO networks_prefix_delegations (network_id, node_id, prefix, duid, iaid, hwaddr, next_hop, expiry)
+  VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+  ON CONFLICT (network_id, duid, iaid) DO UPDATE SET
+    node_id = excluded.node_id,
+    prefix = excluded.prefix,
+    hwaddr = excluded.hwaddr,
+    next_hop = excluded.next_hop,
+    expiry = excluded.expiry
+`
+	_, err := c.tx.ExecContext(ctx, q, networkID, c.nodeID, delegation.Prefix, delegation.DUID, delegation.IAID, delegation.Hwaddr, delegation.NextHop, expiry)
+	if err != nil {
+		return fmt.Errorf("Failed recording delegated prefix %q: %w", delegation.Prefix, err)
+	}
+
+	return nil
+}
+
+// DeleteNetworkPrefixDelegation removes the prefix delegated to a client identity association of the network.
+func (c *ClusterTx) DeleteNetworkPrefixDelegation(ctx context.Context, networkID int64, duid string, iaid uint32) error {
+	_, err := c.tx.ExecContext(ctx, `DELETE FROM networks_prefix_delegations WHERE network_id = ? AND duid = ? AND iaid = ?`, networkID, duid, iaid)
+	if err != nil {
+		return fmt.Errorf("Failed removing delegated prefix: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteNetworkPrefixDelegations removes the prefixes delegated on the network.
+// If local is true, only the delegations handled by this member are removed.
+func (c *ClusterTx) DeleteNetworkPrefixDelegations(ctx context.Context, networkID int64, local bool) error {
+	q := `DELETE FROM networks_prefix_delegations WHERE network_id = ?`
+	args := []any{networkID}
+
+	if local {
+		q += " AND node_id = ?"
+		args = append(args, c.nodeID)
+	}
+
+	_, err := c.tx.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("Failed removing delegated prefixes: %w", err)
+	}
+
+	return nil
+}
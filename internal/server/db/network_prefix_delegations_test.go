@@ -0,0 +1,52 @@
+//go:build linux && cgo && !agent
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Delegated prefixes are unique per network and updated per client identity association.
+func TestNetworkPrefixDelegations(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	networkID, err := tx.CreateNetwork(context.Background(), api.ProjectDefaultName, "incusbr0", "", db.NetworkTypeBridge, nil)
+	require.NoError(t, err)
+
+	expiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	delegation := db.NetworkPrefixDelegation{Prefix: "2001:db8:100::/64", DUID: "00030001aabbccddeeff", IAID: 1, Hwaddr: "aa:bb:cc:dd:ee:ff", NextHop: "fe80::1", Expiry: expiry}
+	err = tx.UpsertNetworkPrefixDelegation(context.Background(), networkID, delegation)
+	require.NoError(t, err)
+
+	// The same prefix can't be delegated to another client.
+	other := db.NetworkPrefixDelegation{Prefix: "2001:db8:100::/64", DUID: "00030001aabbccddee00", IAID: 1, Hwaddr: "aa:bb:cc:dd:ee:00", NextHop: "fe80::2"}
+	err = tx.UpsertNetworkPrefixDelegation(context.Background(), networkID, other)
+	assert.Error(t, err)
+
+	// Renewing updates the existing delegation.
+	delegation.NextHop = "fe80::3"
+	err = tx.UpsertNetworkPrefixDelegation(context.Background(), networkID, delegation)
+	require.NoError(t, err)
+
+	delegations, err := tx.GetNetworkPrefixDelegations(context.Background(), networkID, true)
+	require.NoError(t, err)
+	require.Len(t, delegations, 1)
+	assert.Equal(t, "fe80::3", delegations[0].NextHop)
+	assert.True(t, expiry.Equal(delegations[0].Expiry))
+
+	err = tx.DeleteNetworkPrefixDelegation(context.Background(), networkID, delegation.DUID, delegation.IAID)
+	require.NoError(t, err)
+
+	delegations, err = tx.GetNetworkPrefixDelegations(context.Background(), networkID, false)
+	require.NoError(t, err)
+	assert.Empty(t, delegations)
+}
@@ -0,0 +1,51 @@
+//go:build linux && cgo && !agent
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Floating IPs can be filtered by the instance they target.
+func TestGetNetworkFloatingIPs_Instance(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	networkID, err := tx.CreateNetwork(ctx, api.ProjectDefaultName, "incusbr0", "", db.NetworkTypeBridge, nil)
+	require.NoError(t, err)
+
+	floatingIPs := []cluster.NetworkFloatingIP{
+		{NetworkID: networkID, Address: "192.0.2.10", Project: api.ProjectDefaultName, Instance: "c1"},
+		{NetworkID: networkID, Address: "2001:db8::10", Project: api.ProjectDefaultName, Instance: "c1"},
+		{NetworkID: networkID, Address: "192.0.2.11", Project: api.ProjectDefaultName, Instance: "c2"},
+		{NetworkID: networkID, Address: "192.0.2.12", Project: "other", Instance: "c1"},
+	}
+
+	for _, floatingIP := range floatingIPs {
+		_, err := cluster.CreateNetworkFloatingIP(ctx, tx.Tx(), floatingIP)
+		require.NoError(t, err)
+	}
+
+	projectName := api.ProjectDefaultName
+	instanceName := "c1"
+
+	records, err := cluster.GetNetworkFloatingIPs(ctx, tx.Tx(), cluster.NetworkFloatingIPFilter{NetworkID: &networkID, Project: &projectName, Instance: &instanceName})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "192.0.2.10", records[0].Address)
+	assert.Equal(t, "2001:db8::10", records[1].Address)
+
+	records, err = cluster.GetNetworkFloatingIPs(ctx, tx.Tx(), cluster.NetworkFloatingIPFilter{NetworkID: &networkID})
+	require.NoError(t, err)
+	assert.Len(t, records, 4)
+}
@@ -79,6 +79,8 @@ const (
 	BucketBackupRemove
 	BucketBackupRename
 	BucketBackupRestore
+	InstanceCapture
+	NetworkCapture
 )
 
 // Description return a human-readable description of the operation type.
@@ -210,6 +212,10 @@ func (t Type) Description() string {
 		return "Renaming bucket backup"
 	case BucketBackupRestore:
 		return "Restoring bucket backup"
+	case InstanceCapture:
+		return "Capturing instance traffic"
+	case NetworkCapture:
+		return "Capturing network traffic"
 	default:
 		return "Executing operation"
 	}
@@ -267,6 +273,11 @@ func (t Type) Permission() (auth.ObjectType, auth.Entitlement) {
 		return auth.ObjectTypeInstance, auth.EntitlementCanEdit
 	case SnapshotRestore:
 		return auth.ObjectTypeInstance, auth.EntitlementCanEdit
+	case InstanceCapture:
+		return auth.ObjectTypeInstance, auth.EntitlementCanEdit
+
+	case NetworkCapture:
+		return auth.ObjectTypeNetwork, auth.EntitlementCanEdit
 
 	case ImageDownload:
 		return auth.ObjectTypeImage, auth.EntitlementCanEdit
@@ -0,0 +1,69 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// AddProjectNetworkCounters adds the network traffic of the instances of the project on this member to its
+// cumulative counters.
+func (c *ClusterTx) AddProjectNetworkCounters(ctx context.Context, projectName string, counters api.ProjectStateNetwork) error {
+	q := `
+INSERT INTO projects_network_counters (project_id, node_id, bytes_received, bytes_sent, packets_received, packets_sent)
+  VALUES ((SELECT id FROM projects WHERE name = ?), ?, ?, ?, ?, ?)
+  ON CONFLICT (project_id, node_id) DO UPDATE SET
+    bytes_received = bytes_received + excluded.bytes_received,
+    bytes_sent = bytes_sent + excluded.bytes_sent,
+    packets_received = packets_received + excluded.packets_received,
+    packets_sent = packets_sent + excluded.packets_sent
+`
+	_, err := c.tx.ExecContext(ctx, q, projectName, c.nodeID, counters.BytesReceived, counters.BytesSent, counters.PacketsReceived, counters.PacketsSent)
+	if err != nil {
+		return fmt.Errorf("Failed updating project network counters: %w", err)
+	}
+
+	return nil
+}
+
+// GetProjectNetworkCounters returns the cumulative network traffic counters of the projects, indexed by project name.
+// If local is true, only the traffic of the instances of this member is accounted for, otherwise the whole cluster's.
+func (c *ClusterTx) GetProjectNetworkCounters(ctx context.Context, local bool) (map[string]api.ProjectStateNetwork, error) {
+	q := `
+SELECT projects.name, SUM(bytes_received), SUM(bytes_sent), SUM(packets_received), SUM(packets_sent)
+  FROM projects_network_counters
+  JOIN projects ON projects.id = projects_network_counters.project_id
+`
+	args := []any{}
+
+	if local {
+		q += "  WHERE node_id = ?\n"
+		args = append(args, c.nodeID)
+	}
+
+	q += "  GROUP BY projects.name"
+
+	result := map[string]api.ProjectStateNetwork{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var projectName string
+		counters := api.ProjectStateNetwork{}
+
+		err := scan(&projectName, &counters.BytesReceived, &counters.BytesSent, &counters.PacketsReceived, &counters.PacketsSent)
+		if err != nil {
+			return err
+		}
+
+		result[projectName] = counters
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
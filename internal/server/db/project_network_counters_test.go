@@ -0,0 +1,37 @@
+//go:build linux && cgo && !agent
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Project network counters accumulate across updates.
+func TestGetProjectNetworkCounters(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	counters, err := tx.GetProjectNetworkCounters(context.Background(), false)
+	require.NoError(t, err)
+	assert.Empty(t, counters)
+
+	for range 2 {
+		err = tx.AddProjectNetworkCounters(context.Background(), api.ProjectDefaultName, api.ProjectStateNetwork{BytesReceived: 1000, BytesSent: 200, PacketsReceived: 10, PacketsSent: 2})
+		require.NoError(t, err)
+	}
+
+	counters, err = tx.GetProjectNetworkCounters(context.Background(), false)
+	require.NoError(t, err)
+	assert.Equal(t, api.ProjectStateNetwork{BytesReceived: 2000, BytesSent: 400, PacketsReceived: 20, PacketsSent: 4}, counters[api.ProjectDefaultName])
+
+	counters, err = tx.GetProjectNetworkCounters(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2000), counters[api.ProjectDefaultName].BytesReceived)
+}
@@ -0,0 +1,117 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	dqliteDriver "github.com/cowsql/go-cowsql/driver"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// ProxyCertificate represents a certificate and its private key, presented by the proxy devices of a project.
+type ProxyCertificate struct {
+	Name        string
+	Description string
+	Certificate string
+	Key         string
+}
+
+// GetProxyCertificates returns the proxy certificates of the project.
+func (c *ClusterTx) GetProxyCertificates(ctx context.Context, projectName string) ([]ProxyCertificate, error) {
+	q := `
+SELECT proxy_certificates.name, proxy_certificates.description, proxy_certificates.certificate, proxy_certificates.key
+  FROM proxy_certificates
+  JOIN projects ON projects.id = proxy_certificates.project_id
+  WHERE projects.name = ?
+  ORDER BY proxy_certificates.name
+`
+	certificates := []ProxyCertificate{}
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var certificate ProxyCertificate
+
+		err := scan(&certificate.Name, &certificate.Description, &certificate.Certificate, &certificate.Key)
+		if err != nil {
+			return err
+		}
+
+		certificates = append(certificates, certificate)
+
+		return nil
+	}, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	return certificates, nil
+}
+
+// GetProxyCertificate returns the proxy certificate of the project with the given name.
+func (c *ClusterTx) GetProxyCertificate(ctx context.Context, projectName string, name string) (*ProxyCertificate, error) {
+	q := `
+SELECT proxy_certificates.description, proxy_certificates.certificate, proxy_certificates.key
+  FROM proxy_certificates
+  JOIN projects ON projects.id = proxy_certificates.project_id
+  WHERE projects.name = ? AND proxy_certificates.name = ?
+`
+	certificate := ProxyCertificate{Name: name}
+	err := c.tx.QueryRowContext(ctx, q, projectName, name).Scan(&certificate.Description, &certificate.Certificate, &certificate.Key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Proxy certificate not found")
+		}
+
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+// CreateProxyCertificate adds a proxy certificate to the project.
+func (c *ClusterTx) CreateProxyCertificate(ctx context.Context, projectName string, certificate ProxyCertificate) error {
+	q := `
+INSERT INTO proxy_certificates (project_id, name, description, certificate, key)
+  VALUES ((SELECT id FROM projects WHERE name = ?), ?, ?, ?, ?)
+`
+	_, err := c.tx.ExecContext(ctx, q, projectName, certificate.Name, certificate.Description, certificate.Certificate, certificate.Key)
+	if err != nil {
+		var dqliteErr dqliteDriver.Error
+		// Detect SQLITE_CONSTRAINT_UNIQUE (2067) errors.
+		if errors.As(err, &dqliteErr) && dqliteErr.Code == 2067 {
+			return api.StatusErrorf(http.StatusConflict, "A proxy certificate for that name already exists")
+		}
+
+		return fmt.Errorf("Failed adding proxy certificate %q: %w", certificate.Name, err)
+	}
+
+	return nil
+}
+
+// DeleteProxyCertificate removes a proxy certificate from the project.
+func (c *ClusterTx) DeleteProxyCertificate(ctx context.Context, projectName string, name string) error {
+	q := `
+DELETE FROM proxy_certificates
+  WHERE project_id = (SELECT id FROM projects WHERE name = ?) AND name = ?
+`
+	result, err := c.tx.ExecContext(ctx, q, projectName, name)
+	if err != nil {
+		return fmt.Errorf("Failed removing proxy certificate %q: %w", name, err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Proxy certificate not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+//go:build linux && cgo && !agent
+
+package db_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Proxy certificates are scoped to their project.
+func TestProxyCertificates(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	project1 := cluster.Project{}
+	project1.Name = "p1"
+	_, err := cluster.CreateProject(context.Background(), tx.Tx(), project1)
+	require.NoError(t, err)
+
+	certificate := db.ProxyCertificate{Name: "web", Description: "Web", Certificate: "cert", Key: "key"}
+	err = tx.CreateProxyCertificate(context.Background(), api.ProjectDefaultName, certificate)
+	require.NoError(t, err)
+
+	// The name is unique within the project.
+	err = tx.CreateProxyCertificate(context.Background(), api.ProjectDefaultName, certificate)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusConflict))
+
+	// The certificate isn't visible from other projects.
+	_, err = tx.GetProxyCertificate(context.Background(), "p1", "web")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+	certificates, err := tx.GetProxyCertificates(context.Background(), "p1")
+	require.NoError(t, err)
+	assert.Empty(t, certificates)
+
+	// Other projects can use the same name.
+	certificate.Key = "other"
+	err = tx.CreateProxyCertificate(context.Background(), "p1", certificate)
+	require.NoError(t, err)
+
+	got, err := tx.GetProxyCertificate(context.Background(), api.ProjectDefaultName, "web")
+	require.NoError(t, err)
+	assert.Equal(t, "key", got.Key)
+
+	err = tx.DeleteProxyCertificate(context.Background(), api.ProjectDefaultName, "web")
+	require.NoError(t, err)
+
+	err = tx.DeleteProxyCertificate(context.Background(), api.ProjectDefaultName, "web")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+	certificates, err = tx.GetProxyCertificates(context.Background(), "p1")
+	require.NoError(t, err)
+	assert.Equal(t, []db.ProxyCertificate{certificate}, certificates)
+}
@@ -66,6 +66,8 @@ type RunConfig struct {
 	USBDevice        []USBDeviceItem  // USB device configuration settings.
 	TPMDevice        []RunConfigItem  // TPM device configuration settings.
 	PCIDevice        []RunConfigItem  // PCI device configuration settings.
+	SerialDevice     []RunConfigItem  // Serial device configuration settings.
+	WatchdogDevice   []RunConfigItem  // Watchdog device configuration settings.
 	Revert           revert.Hook      // Revert setup of device on post-setup error.
 	UseUSBBus        bool             // Whether to use a USB bus for the device.
 }
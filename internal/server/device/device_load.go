@@ -44,6 +44,8 @@ func newByType(state *state.State, projectName string, conf deviceConfig.Device)
 			dev = &nicSRIOV{}
 		case "ovn":
 			dev = &nicOVN{}
+		case "bond":
+			dev = &nicBond{}
 		}
 
 	case "infiniband":
@@ -88,6 +90,10 @@ func newByType(state *state.State, projectName string, conf deviceConfig.Device)
 		dev = &tpm{}
 	case "pci":
 		dev = &pci{}
+	case "serial":
+		dev = &serial{}
+	case "watchdog":
+		dev = &watchdog{}
 	}
 
 	// Check a valid device type has been found.
@@ -1,20 +1,26 @@
 package device
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/sys/unix"
 
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/linux"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
@@ -256,6 +262,209 @@ again:
 	goto again
 }
 
+// DiskNetworkSource represents a parsed network disk source.
+type DiskNetworkSource struct {
+	Protocol string // Protocol of the source (nbd, iscsi, http or https).
+	Host     string // Host of the server.
+	Port     string // Port of the server.
+	Export   string // Export name (NBD only).
+	Target   string // Target IQN (iSCSI only).
+	LUN      int    // Logical unit number (iSCSI only).
+	URL      string // Full URL of the source.
+}
+
+// DiskParseNetworkSource parses a network disk source URL (`nbd://<host>[:<port>]/<export>`,
+// `iscsi://<host>[:<port>]/<target>/<lun>` or `http(s)://...`).
+func DiskParseNetworkSource(source string) (*DiskNetworkSource, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid network disk source %q: %w", source, err)
+	}
+
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("Network disk source %q is missing a host", source)
+	}
+
+	if u.User != nil {
+		return nil, fmt.Errorf("Network disk source %q can't include credentials", source)
+	}
+
+	// Don't allow hosts which could be mistaken for command line options.
+	if strings.HasPrefix(u.Hostname(), "-") {
+		return nil, fmt.Errorf("Invalid host %q in network disk source %q", u.Hostname(), source)
+	}
+
+	src := DiskNetworkSource{
+		Protocol: u.Scheme,
+		Host:     u.Hostname(),
+		Port:     u.Port(),
+		URL:      source,
+	}
+
+	switch u.Scheme {
+	case "nbd":
+		if src.Port == "" {
+			src.Port = "10809"
+		}
+
+		src.Export = strings.TrimPrefix(u.Path, "/")
+	case "iscsi":
+		if src.Port == "" {
+			src.Port = "3260"
+		}
+
+		target, lun, ok := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+		if !ok || target == "" {
+			return nil, fmt.Errorf("Network disk source %q must be in the form iscsi://<host>[:<port>]/<target>/<lun>", source)
+		}
+
+		src.Target = target
+		src.LUN, err = strconv.Atoi(lun)
+		if err != nil || src.LUN < 0 {
+			return nil, fmt.Errorf("Invalid iSCSI LUN %q", lun)
+		}
+
+	case "http", "https":
+	default:
+		return nil, fmt.Errorf("Unsupported network disk source protocol %q", u.Scheme)
+	}
+
+	if src.Port != "" {
+		port, err := strconv.ParseUint(src.Port, 10, 16)
+		if err != nil || port == 0 {
+			return nil, fmt.Errorf("Invalid port %q in network disk source %q", src.Port, source)
+		}
+	}
+
+	return &src, nil
+}
+
+// diskNBDMu serializes the allocation of the kernel NBD devices.
+var diskNBDMu sync.Mutex
+
+// diskNBDDevices records the disk device (project/instance/device) which connected each kernel NBD device.
+var diskNBDDevices = map[string]string{}
+
+// diskNBDConnect connects a free kernel NBD device to the given export on behalf of owner and returns its path.
+func diskNBDConnect(src *DiskNetworkSource, owner string) (string, error) {
+	diskNBDMu.Lock()
+	defer diskNBDMu.Unlock()
+
+	err := linux.LoadModule("nbd")
+	if err != nil {
+		return "", fmt.Errorf("Failed loading nbd module: %w", err)
+	}
+
+	devices, err := filepath.Glob("/sys/block/nbd*")
+	if err != nil {
+		return "", err
+	}
+
+	for _, device := range devices {
+		// Devices in use have a pid file.
+		if util.PathExists(filepath.Join(device, "pid")) {
+			continue
+		}
+
+		devPath := filepath.Join("/dev", filepath.Base(device))
+		if diskNBDDevices[devPath] != "" {
+			continue
+		}
+
+		_, err = subprocess.RunCommand("nbd-client", diskNBDClientArgs(src, devPath)...)
+		if err != nil {
+			return "", err
+		}
+
+		diskNBDDevices[devPath] = owner
+
+		return devPath, nil
+	}
+
+	return "", errors.New("No free NBD device available")
+}
+
+// diskNBDClientArgs returns the nbd-client arguments connecting the kernel NBD device to the export.
+// The positional arguments come after "--" so they can't be interpreted as options.
+func diskNBDClientArgs(src *DiskNetworkSource, devPath string) []string {
+	args := []string{}
+	if src.Export != "" {
+		args = append(args, "-N", src.Export)
+	}
+
+	return append(args, "--", src.Host, src.Port, devPath)
+}
+
+// diskNBDOwned returns whether the kernel NBD device was connected by owner.
+// Devices connected before a restart of the daemon aren't recorded, those are only considered owned when
+// nobody else claims them and they are mounted on mntPath.
+func diskNBDOwned(devPath string, owner string, mntPath string) bool {
+	if internalInstance.ValidNBDDevicePath(devPath) != nil {
+		return false
+	}
+
+	diskNBDMu.Lock()
+	defer diskNBDMu.Unlock()
+
+	recorded, found := diskNBDDevices[devPath]
+	if found {
+		return recorded == owner
+	}
+
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return false
+	}
+
+	defer func() { _ = f.Close() }()
+
+	return diskMountSource(f, mntPath) == devPath
+}
+
+// diskMountSource returns the source of the file system mounted on mntPath according to the mountinfo content.
+func diskMountSource(mountinfo io.Reader, mntPath string) string {
+	source := ""
+
+	scanner := bufio.NewScanner(mountinfo)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[4] != mntPath {
+			continue
+		}
+
+		// The optional fields end with a "-" separator, followed by the file system type and the source.
+		sep := slices.Index(fields, "-")
+		if sep < 0 || len(fields) < sep+3 {
+			continue
+		}
+
+		// The last matching entry is the one on top.
+		source = fields[sep+2]
+	}
+
+	return source
+}
+
+// diskNBDDisconnect disconnects the given kernel NBD device.
+// The "-d" option takes the device as its argument, the strict validation ensures it can't be read as an option.
+func diskNBDDisconnect(devPath string) error {
+	err := internalInstance.ValidNBDDevicePath(devPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = subprocess.RunCommand("nbd-client", "-d", devPath)
+	if err != nil {
+		return err
+	}
+
+	diskNBDMu.Lock()
+	delete(diskNBDDevices, devPath)
+	diskNBDMu.Unlock()
+
+	return nil
+}
+
 // diskCephfsOptions returns the mntSrcPath and fsOptions to use for mounting a cephfs share.
 func diskCephfsOptions(clusterName string, userName string, fsName string, fsPath string) (string, []string, error) {
 	// Get the FSID.
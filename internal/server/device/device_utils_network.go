@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/netip"
 	"os"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
@@ -17,13 +18,16 @@ import (
 	"github.com/mdlayher/ndp"
 	"golang.org/x/sys/unix"
 
+	"github.com/lxc/incus/v6/internal/netutils"
 	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
 	pcidev "github.com/lxc/incus/v6/internal/server/device/pci"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/ip"
 	"github.com/lxc/incus/v6/internal/server/network"
+	"github.com/lxc/incus/v6/internal/server/network/acl"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/units"
@@ -34,6 +38,36 @@ import (
 // Instances can be started in parallel, so lock the creation of VLANs.
 var networkCreateSharedDeviceLock sync.Mutex
 
+// networkMirrorSource represents a running local NIC mirroring its traffic to another NIC.
+type networkMirrorSource struct {
+	project        api.Project
+	config         deviceConfig.Device
+	projectShaping bool
+}
+
+// networkMirrorSources indexes the running local NICs mirroring their traffic by host side interface, so that the
+// NICs mirroring to a NIC can be pointed at it when it starts without going through all the instances.
+var networkMirrorSources = map[string]networkMirrorSource{}
+var networkMirrorSourcesMu sync.Mutex
+
+// networkHostInterface represents the host side interface created by a running local NIC.
+type networkHostInterface struct {
+	name    string
+	nicType string
+	master  string
+	hwaddr  string
+}
+
+// networkHostInterfaces indexes the host side interfaces created by the running local NICs by project, instance and
+// device name, so that they can be acted upon without trusting the user editable volatile host_name keys.
+var networkHostInterfaces = map[string]networkHostInterface{}
+var networkHostInterfacesMu sync.Mutex
+
+func init() {
+	// Expose networkHostInterfaceNIC to the acl package, to avoid circular imports.
+	acl.HostInterfaceNIC = networkHostInterfaceNIC
+}
+
 // NetworkSetDevMTU sets the MTU setting for a named network device if different from current.
 func NetworkSetDevMTU(devName string, mtu uint32) error {
 	curMTU, err := network.GetDevMTU(devName)
@@ -404,6 +438,261 @@ func networkVethFillFromVolatile(device deviceConfig.Device, volatile map[string
 	}
 }
 
+// networkHostInterfaceKey returns the key of the NIC in the host side interfaces index.
+func networkHostInterfaceKey(projectName string, instName string, devName string) string {
+	return fmt.Sprintf("%s/%s/%s", projectName, instName, devName)
+}
+
+// networkHostInterfaceMaster returns the device the host side interface of a NIC is attached to.
+func networkHostInterfaceMaster(nicType string, config deviceConfig.Device) string {
+	switch nicType {
+	case "bridged":
+		if network.IsNativeBridge(config["parent"]) {
+			return config["parent"]
+		}
+
+		return "ovs-system"
+	case "ovn":
+		return "ovs-system"
+	}
+
+	return config["vrf"]
+}
+
+// networkTrackHostInterface records the host side interface the NIC created when starting.
+func networkTrackHostInterface(d *deviceCommon, nicType string, hostName string) {
+	networkHostInterfacesMu.Lock()
+	defer networkHostInterfacesMu.Unlock()
+
+	networkHostInterfaces[networkHostInterfaceKey(d.inst.Project().Name, d.inst.Name(), d.name)] = networkHostInterface{
+		name:    hostName,
+		nicType: nicType,
+		master:  networkHostInterfaceMaster(nicType, d.config),
+		hwaddr:  d.config["hwaddr"],
+	}
+}
+
+// networkTrackedHostInterface returns the host side interface the running local NIC created, if any.
+func networkTrackedHostInterface(projectName string, instName string, devName string) (networkHostInterface, bool) {
+	networkHostInterfacesMu.Lock()
+	defer networkHostInterfacesMu.Unlock()
+
+	iface, found := networkHostInterfaces[networkHostInterfaceKey(projectName, instName, devName)]
+
+	return iface, found
+}
+
+// networkHostInterfaceNIC returns the project, instance and device name of the running local NIC which created the
+// host side interface.
+func networkHostInterfaceNIC(name string) (string, string, string, bool) {
+	networkHostInterfacesMu.Lock()
+	defer networkHostInterfacesMu.Unlock()
+
+	for key, iface := range networkHostInterfaces {
+		if iface.name != name {
+			continue
+		}
+
+		fields := strings.SplitN(key, "/", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		return fields[0], fields[1], fields[2], true
+	}
+
+	return "", "", "", false
+}
+
+// networkUntrackHostInterface forgets about the host side interface of the NIC being stopped.
+func networkUntrackHostInterface(d *deviceCommon) {
+	networkHostInterfacesMu.Lock()
+	defer networkHostInterfacesMu.Unlock()
+
+	delete(networkHostInterfaces, networkHostInterfaceKey(d.inst.Project().Name, d.inst.Name(), d.name))
+}
+
+// networkAdoptHostInterface records the host side interface of a NIC started before the daemon, as long as it is
+// still connected to the instance.
+func networkAdoptHostInterface(d *deviceCommon, nicType string) {
+	if !d.inst.IsRunning() {
+		return
+	}
+
+	key := networkHostInterfaceKey(d.inst.Project().Name, d.inst.Name(), d.name)
+
+	networkHostInterfacesMu.Lock()
+	_, found := networkHostInterfaces[key]
+	networkHostInterfacesMu.Unlock()
+
+	if found {
+		return
+	}
+
+	// The volatile getter isn't available when registering devices.
+	localConfig := d.inst.LocalConfig()
+	config := d.config.Clone()
+	networkVethFillFromVolatile(config, map[string]string{
+		"host_name": localConfig[fmt.Sprintf("volatile.%s.host_name", d.name)],
+		"hwaddr":    localConfig[fmt.Sprintf("volatile.%s.hwaddr", d.name)],
+	})
+
+	if config["host_name"] == "" {
+		return
+	}
+
+	iface := networkHostInterface{
+		name:    config["host_name"],
+		nicType: nicType,
+		master:  networkHostInterfaceMaster(nicType, config),
+		hwaddr:  config["hwaddr"],
+	}
+
+	err := networkCheckHostInterface(d.state, d.inst, iface)
+	if err != nil {
+		d.logger.Warn("Ignoring NIC host side interface", logger.Ctx{"interface": iface.name, "err": err})
+		return
+	}
+
+	networkHostInterfacesMu.Lock()
+	defer networkHostInterfacesMu.Unlock()
+
+	networkHostInterfaces[key] = iface
+}
+
+// networkCheckHostInterface checks that the host side interface is still a veth or tap device attached where the NIC
+// attached it and connected to the instance, with the NIC's MAC address on the instance side.
+func networkCheckHostInterface(s *state.State, inst instance.Instance, iface networkHostInterface) error {
+	link, err := ip.LinkByName(iface.name)
+	if err != nil {
+		return fmt.Errorf("Failed getting host interface %q: %w", iface.name, err)
+	}
+
+	if link.Master != iface.master {
+		return fmt.Errorf("Host interface %q isn't attached to the NIC's network", iface.name)
+	}
+
+	pid := inst.InitPID()
+	if pid < 1 {
+		return errors.New("Instance is not running")
+	}
+
+	switch inst.Type() {
+	case instancetype.Container:
+		if link.Kind != "veth" {
+			return fmt.Errorf("Host interface %q isn't a veth device", iface.name)
+		}
+
+		if !s.OS.NetnsGetifaddrs {
+			return errors.New("Listing the instance interfaces isn't supported by the kernel")
+		}
+
+		hostInterfaces, err := net.Interfaces()
+		if err != nil {
+			return fmt.Errorf("Failed listing host interfaces: %w", err)
+		}
+
+		// The kernel resolves the host side peer of each instance interface.
+		nics, err := netutils.NetnsGetifaddrs(int32(pid), hostInterfaces)
+		if err != nil {
+			return fmt.Errorf("Failed listing instance interfaces: %w", err)
+		}
+
+		for _, nic := range nics {
+			if nic.HostName == iface.name && networkSameHwaddr(nic.Hwaddr, iface.hwaddr) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Host interface %q isn't connected to an instance interface with MAC address %q", iface.name, iface.hwaddr)
+	case instancetype.VM:
+		if link.Kind != "tuntap" {
+			return fmt.Errorf("Host interface %q isn't a tap device", iface.name)
+		}
+
+		// The MAC address of the instance side is set by QEMU, so check the tap device is held by its process.
+		opened, err := networkTapOpenedBy(fmt.Sprintf("/proc/%d/fdinfo", pid), iface.name)
+		if err != nil {
+			return err
+		}
+
+		if !opened {
+			return fmt.Errorf("Host interface %q isn't connected to the instance", iface.name)
+		}
+
+		return nil
+	}
+
+	return errors.New("Unsupported instance type")
+}
+
+// networkSameHwaddr returns whether two MAC addresses are the same, regardless of their formatting.
+func networkSameHwaddr(a string, b string) bool {
+	macA, err := net.ParseMAC(a)
+	if err != nil {
+		return false
+	}
+
+	macB, err := net.ParseMAC(b)
+	if err != nil {
+		return false
+	}
+
+	return slices.Equal(macA, macB)
+}
+
+// networkTapOpenedBy returns whether one of the file descriptors listed in the fdinfo directory of a process is
+// attached to the named tap device.
+func networkTapOpenedBy(fdinfoPath string, name string) (bool, error) {
+	entries, err := os.ReadDir(fdinfoPath)
+	if err != nil {
+		return false, fmt.Errorf("Failed listing file descriptors: %w", err)
+	}
+
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(fdinfoPath, entry.Name()))
+		if err != nil {
+			// File descriptors can be closed while going through them.
+			continue
+		}
+
+		for _, line := range strings.Split(string(content), "\n") {
+			key, value, found := strings.Cut(line, ":")
+			if found && key == "iff" && strings.TrimSpace(value) == name {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// NetworkHostInterface returns the host side interface created by the running local NIC of the instance, once
+// checked to still be connected to the instance.
+func NetworkHostInterface(s *state.State, inst instance.Instance, devName string) (string, error) {
+	iface, found := networkTrackedHostInterface(inst.Project().Name, inst.Name(), devName)
+	if !found {
+		return "", fmt.Errorf("Device %q doesn't have a host side interface on this server", devName)
+	}
+
+	err := networkCheckHostInterface(s, inst, iface)
+	if err != nil {
+		return "", err
+	}
+
+	return iface.name, nil
+}
+
+// NetworkHostInterfaceName returns the host side interface the running local NIC of the instance created, if any.
+func NetworkHostInterfaceName(projectName string, instName string, devName string) string {
+	iface, found := networkTrackedHostInterface(projectName, instName, devName)
+	if !found || !network.InterfaceExists(iface.name) {
+		return ""
+	}
+
+	return iface.name
+}
+
 // networkNICRouteAdd applies any static host-side routes configured for an instance NIC.
 func networkNICRouteAdd(routeDev string, routes ...string) error {
 	if !network.InterfaceExists(routeDev) {
@@ -500,10 +789,10 @@ func networkNICRouteDelete(routeDev string, routes ...string) {
 func networkSetupHostVethLimits(d *deviceCommon, oldConfig deviceConfig.Device, bridged bool) error {
 	var err error
 
-	veth := d.config["host_name"]
-
-	if veth == "" || !network.InterfaceExists(veth) {
-		return fmt.Errorf("Unknown or missing host side veth device %q", veth)
+	// Only apply the rules to the host side interface the NIC created, never to the user editable volatile one.
+	veth := NetworkHostInterfaceName(d.inst.Project().Name, d.inst.Name(), d.name)
+	if veth == "" {
+		return errors.New("Unknown or missing host side veth device")
 	}
 
 	// Apply max limit
@@ -512,23 +801,90 @@ func networkSetupHostVethLimits(d *deviceCommon, oldConfig deviceConfig.Device,
 		d.config["limits.egress"] = d.config["limits.max"]
 	}
 
+	err = networkSetupHostVethQdiscs(d.state, d.inst.Project(), veth, d.config, true)
+	if err != nil {
+		return err
+	}
+
+	var networkPriority uint64
+	if d.config["limits.priority"] != "" {
+		networkPriority, err = strconv.ParseUint(d.config["limits.priority"], 10, 32)
+		if err != nil {
+			return fmt.Errorf("Failed to parse limits.priority %q: %w", d.config["limits.priority"], err)
+		}
+	}
+
+	if oldConfig != nil && oldConfig["limits.priority"] != d.config["limits.priority"] {
+		err = d.state.Firewall.InstanceClearNetPrio(d.inst.Project().Name, d.inst.Name(), veth)
+		if err != nil {
+			return err
+		}
+	}
+
+	if oldConfig == nil || oldConfig["limits.priority"] != d.config["limits.priority"] {
+		if networkPriority != 0 {
+			if bridged && d.state.Firewall.String() == "xtables" {
+				return errors.New("Failed to setup instance device network priority. The xtables firewall driver does not support required functionality.")
+			}
+
+			err = d.state.Firewall.InstanceSetupNetPrio(d.inst.Project().Name, d.inst.Name(), veth, uint32(networkPriority))
+			if err != nil {
+				return fmt.Errorf("Failed to setup instance device network priority: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// networkSetupHostVethQdiscs applies the traffic control rules (rate limits, mirroring and optionally project
+// shaping) of the NIC config to the veth device.
+func networkSetupHostVethQdiscs(s *state.State, project api.Project, veth string, config deviceConfig.Device, projectShaping bool) error {
+	var err error
+
+	projectName := project.Name
+
 	// Parse the values
 	var ingressInt int64
-	if d.config["limits.ingress"] != "" {
-		ingressInt, err = units.ParseBitSizeString(d.config["limits.ingress"])
+	if config["limits.ingress"] != "" {
+		ingressInt, err = units.ParseBitSizeString(config["limits.ingress"])
 		if err != nil {
 			return err
 		}
 	}
 
 	var egressInt int64
-	if d.config["limits.egress"] != "" {
-		egressInt, err = units.ParseBitSizeString(d.config["limits.egress"])
+	if config["limits.egress"] != "" {
+		egressInt, err = units.ParseBitSizeString(config["limits.egress"])
 		if err != nil {
 			return err
 		}
 	}
 
+	// Resolve the mirroring target.
+	var mirrorDev string
+	if config["mirror.target"] != "" {
+		mirrorDev, err = networkMirrorTargetHostName(s, projectName, config["mirror.target"])
+		if err != nil {
+			logger.Warn("Skipping NIC traffic mirroring", logger.Ctx{"project": projectName, "dev": veth, "target": config["mirror.target"], "err": err})
+		}
+	}
+
+	// Resolve the devices shaping the aggregate traffic of the project.
+	var ingressShaping string
+	var egressShaping string
+	if projectShaping {
+		_, err = network.ProjectLimitsSetup(projectName, project.Config)
+		if err != nil {
+			return fmt.Errorf("Failed setting up project network limits: %w", err)
+		}
+
+		ingressShaping = network.ProjectLimitsDevice(projectName, project.Config, "ingress")
+		egressShaping = network.ProjectLimitsDevice(projectName, project.Config, "egress")
+	}
+
+	ingressActions, egressActions := networkHostVethActions(mirrorDev, config["mirror.direction"], egressInt, ingressShaping, egressShaping)
+
 	// Clean any existing entry
 	qdiscIngress := &ip.QdiscIngress{Qdisc: ip.Qdisc{Dev: veth, Handle: "ffff:0"}}
 	err = qdiscIngress.Delete()
@@ -543,65 +899,218 @@ func networkSetupHostVethLimits(d *deviceCommon, oldConfig deviceConfig.Device,
 	}
 
 	// Apply new limits
-	if d.config["limits.ingress"] != "" {
+	if config["limits.ingress"] != "" || len(ingressActions) > 0 {
 		qdiscHTB = &ip.QdiscHTB{Qdisc: ip.Qdisc{Dev: veth, Handle: "1:0", Parent: "root"}, Default: 0x10}
 		err := qdiscHTB.Add()
 		if err != nil {
 			return fmt.Errorf("Failed to create root tc qdisc: %s", err)
 		}
 
-		classHTB := &ip.ClassHTB{Class: ip.Class{Dev: veth, Parent: "1:0", Classid: "1:10"}, Rate: fmt.Sprintf("%dbit", ingressInt)}
-		err = classHTB.Add()
-		if err != nil {
-			return fmt.Errorf("Failed to create limit tc class: %s", err)
+		filter := &ip.U32Filter{Filter: ip.Filter{Dev: veth, Parent: "1:0", Protocol: "all"}, Value: 0, Mask: 0, Actions: ingressActions}
+		if config["limits.ingress"] != "" {
+			classHTB := &ip.ClassHTB{Class: ip.Class{Dev: veth, Parent: "1:0", Classid: "1:10"}, Rate: fmt.Sprintf("%dbit", ingressInt)}
+			err = classHTB.Add()
+			if err != nil {
+				return fmt.Errorf("Failed to create limit tc class: %s", err)
+			}
+
+			filter.Flowid = "1:1"
 		}
 
-		filter := &ip.U32Filter{Filter: ip.Filter{Dev: veth, Parent: "1:0", Protocol: "all", Flowid: "1:1"}, Value: 0, Mask: 0}
 		err = filter.Add()
 		if err != nil {
 			return fmt.Errorf("Failed to create tc filter: %s", err)
 		}
 	}
 
-	if d.config["limits.egress"] != "" {
+	if len(egressActions) > 0 {
 		qdiscIngress = &ip.QdiscIngress{Qdisc: ip.Qdisc{Dev: veth, Handle: "ffff:0"}}
 		err := qdiscIngress.Add()
 		if err != nil {
 			return fmt.Errorf("Failed to create ingress tc qdisc: %s", err)
 		}
 
-		police := &ip.ActionPolice{Rate: uint32(egressInt / 8), Burst: uint32(egressInt / 40), Mtu: 65535, Drop: true}
-		filter := &ip.U32Filter{Filter: ip.Filter{Dev: veth, Parent: "ffff:0", Protocol: "all"}, Value: 0, Mask: 0, Actions: []ip.Action{police}}
+		filter := &ip.U32Filter{Filter: ip.Filter{Dev: veth, Parent: "ffff:0", Protocol: "all"}, Value: 0, Mask: 0, Actions: egressActions}
 		err = filter.Add()
 		if err != nil {
 			return fmt.Errorf("Failed to create ingress tc filter: %s", err)
 		}
 	}
 
-	var networkPriority uint64
-	if d.config["limits.priority"] != "" {
-		networkPriority, err = strconv.ParseUint(d.config["limits.priority"], 10, 32)
+	// Keep track of the NICs mirroring their traffic.
+	networkMirrorSourcesMu.Lock()
+	defer networkMirrorSourcesMu.Unlock()
+
+	if config["mirror.target"] != "" {
+		networkMirrorSources[veth] = networkMirrorSource{project: project, config: config.Clone(), projectShaping: projectShaping}
+	} else {
+		delete(networkMirrorSources, veth)
+	}
+
+	return nil
+}
+
+// networkHostVethActions returns the traffic control actions applied to the traffic sent (what the instance receives)
+// and received (what the instance sends) by the host side interface of a NIC.
+// The traffic is mirrored first so the monitor sees everything, then policed according to the NIC's egress limit and
+// finally redirected to the devices shaping the aggregate traffic of the project.
+func networkHostVethActions(mirrorDev string, mirrorDirection string, egressRate int64, ingressShaping string, egressShaping string) ([]ip.Action, []ip.Action) {
+	var ingressActions []ip.Action
+	var egressActions []ip.Action
+
+	if mirrorDev != "" {
+		if mirrorDirection != "egress" {
+			ingressActions = append(ingressActions, &ip.ActionMirred{Dev: mirrorDev})
+		}
+
+		if mirrorDirection != "ingress" {
+			egressActions = append(egressActions, &ip.ActionMirred{Dev: mirrorDev})
+		}
+	}
+
+	if egressRate > 0 {
+		egressActions = append(egressActions, &ip.ActionPolice{Rate: uint32(egressRate / 8), Burst: uint32(egressRate / 40), Mtu: 65535, Drop: true, Pipe: egressShaping != ""})
+	}
+
+	if ingressShaping != "" {
+		ingressActions = append(ingressActions, &ip.ActionMirred{Dev: ingressShaping, Redirect: true})
+	}
+
+	if egressShaping != "" {
+		egressActions = append(egressActions, &ip.ActionMirred{Dev: egressShaping, Redirect: true})
+	}
+
+	return ingressActions, egressActions
+}
+
+// networkClearHostVethMirror forgets about the traffic mirroring of a NIC being stopped.
+func networkClearHostVethMirror(veth string) {
+	networkMirrorSourcesMu.Lock()
+	defer networkMirrorSourcesMu.Unlock()
+
+	delete(networkMirrorSources, veth)
+}
+
+// networkValidateMirrorTarget returns a validator for the mirror.target NIC setting, which references another NIC
+// of the form "<instance>/<device>".
+func networkValidateMirrorTarget(instName string, devName string) func(value string) error {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+
+		targetInstName, targetDevName, found := strings.Cut(value, "/")
+		if !found || targetDevName == "" {
+			return errors.New("Mirror target must be of the form <instance>/<device>")
+		}
+
+		err := validate.IsHostname(targetInstName)
 		if err != nil {
-			return fmt.Errorf("Failed to parse limits.priority %q: %w", d.config["limits.priority"], err)
+			return fmt.Errorf("Invalid mirror target instance name: %w", err)
+		}
+
+		if targetInstName == instName && targetDevName == devName {
+			return errors.New("A NIC cannot mirror its traffic to itself")
 		}
+
+		return nil
 	}
+}
 
-	if oldConfig != nil && oldConfig["limits.priority"] != d.config["limits.priority"] {
-		err = d.state.Firewall.InstanceClearNetPrio(d.inst.Project().Name, d.inst.Name(), veth)
+// networkMirrorTargetHostName returns the host side interface of the running NIC referenced by a mirror.target
+// value of the form "<instance>/<device>".
+func networkMirrorTargetHostName(s *state.State, projectName string, target string) (string, error) {
+	instName, devName, found := strings.Cut(target, "/")
+	if !found {
+		return "", fmt.Errorf("Invalid mirror target %q", target)
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, instName)
+	if err != nil {
+		return "", fmt.Errorf("Failed loading mirror target instance %q: %w", instName, err)
+	}
+
+	devConfig, found := inst.ExpandedDevices()[devName]
+	if !found || devConfig["type"] != "nic" {
+		return "", fmt.Errorf("Mirror target instance %q has no NIC device %q", instName, devName)
+	}
+
+	// Avoid mirroring loops between NICs.
+	if devConfig["mirror.target"] != "" {
+		return "", fmt.Errorf("Mirror target NIC %q is itself mirroring traffic", target)
+	}
+
+	// Only mirror to the host side interface the target NIC created, never to the user editable volatile one.
+	hostName := NetworkHostInterfaceName(projectName, instName, devName)
+	if hostName == "" {
+		return "", fmt.Errorf("Mirror target NIC %q isn't running on this server", target)
+	}
+
+	return hostName, nil
+}
+
+// networkRefreshHostVethMirrors re-applies the traffic control rules of the running local NICs which mirror their
+// traffic to the specified NIC, so that they pick up its new host side interface.
+func networkRefreshHostVethMirrors(s *state.State, inst instance.Instance, devName string) {
+	target := fmt.Sprintf("%s/%s", inst.Name(), devName)
+
+	networkMirrorSourcesMu.Lock()
+	sources := map[string]networkMirrorSource{}
+	for veth, source := range networkMirrorSources {
+		if source.project.Name == inst.Project().Name && source.config["mirror.target"] == target {
+			sources[veth] = source
+		}
+	}
+
+	networkMirrorSourcesMu.Unlock()
+
+	for veth, source := range sources {
+		if !network.InterfaceExists(veth) {
+			networkClearHostVethMirror(veth)
+			continue
+		}
+
+		err := networkSetupHostVethQdiscs(s, source.project, veth, source.config, source.projectShaping)
 		if err != nil {
-			return err
+			logger.Warn("Failed refreshing NIC traffic mirroring", logger.Ctx{"project": source.project.Name, "dev": veth, "target": target, "err": err})
 		}
 	}
+}
 
-	if oldConfig == nil || oldConfig["limits.priority"] != d.config["limits.priority"] {
-		if networkPriority != 0 {
-			if bridged && d.state.Firewall.String() == "xtables" {
-				return errors.New("Failed to setup instance device network priority. The xtables firewall driver does not support required functionality.")
+// NetworkRefreshProjectLimits re-applies the traffic control rules of the running local NICs of the project, so that
+// they redirect their traffic to the devices shaping the aggregate traffic of the project.
+func NetworkRefreshProjectLimits(s *state.State, projectName string) error {
+	insts, err := instance.LoadNodeAll(s, instancetype.Any)
+	if err != nil {
+		return fmt.Errorf("Failed loading instances: %w", err)
+	}
+
+	for _, inst := range insts {
+		if inst.Project().Name != projectName || !inst.IsRunning() {
+			continue
+		}
+
+		for devName, devConfig := range inst.ExpandedDevices() {
+			if devConfig["type"] != "nic" {
+				continue
 			}
 
-			err = d.state.Firewall.InstanceSetupNetPrio(d.inst.Project().Name, d.inst.Name(), veth, uint32(networkPriority))
+			// Only the host side interfaces the NICs created are touched, and only the NIC types applying traffic
+			// control rules to them are shaped.
+			iface, found := networkTrackedHostInterface(projectName, inst.Name(), devName)
+			if !found || !slices.Contains([]string{"bridged", "p2p", "routed"}, iface.nicType) || !network.InterfaceExists(iface.name) {
+				continue
+			}
+
+			config := devConfig.Clone()
+			if config["limits.max"] != "" {
+				config["limits.ingress"] = config["limits.max"]
+				config["limits.egress"] = config["limits.max"]
+			}
+
+			err = networkSetupHostVethQdiscs(s, inst.Project(), iface.name, config, true)
 			if err != nil {
-				return fmt.Errorf("Failed to setup instance device network priority: %w", err)
+				logger.Warn("Failed refreshing NIC project network limits", logger.Ctx{"project": projectName, "instance": inst.Name(), "device": devName, "err": err})
 			}
 		}
 	}
@@ -609,6 +1118,20 @@ func networkSetupHostVethLimits(d *deviceCommon, oldConfig deviceConfig.Device,
 	return nil
 }
 
+// networkFlushProjectCounters accounts the traffic of the host side interface of the NIC to the project of the
+// instance before the NIC stops, so that the traffic since the last periodic update isn't lost.
+func networkFlushProjectCounters(d *deviceCommon) {
+	hostName := NetworkHostInterfaceName(d.inst.Project().Name, d.inst.Name(), d.name)
+	if hostName == "" {
+		return
+	}
+
+	err := network.ProjectCountersFlush(d.state, d.inst.Project().Name, hostName)
+	if err != nil {
+		d.logger.Warn("Failed accounting NIC traffic to project", logger.Ctx{"interface": hostName, "err": err})
+	}
+}
+
 // networkClearHostVethLimits clears any network rate limits to the veth device specified in the config.
 func networkClearHostVethLimits(d *deviceCommon) error {
 	// Detached NICs cannot be cleaned up this way.
@@ -0,0 +1,134 @@
+package device
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/ip"
+)
+
+func Test_networkTapOpenedBy(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0"), []byte("pos:\t0\nflags:\t02\nmnt_id:\t25\nino:\t1\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "12"), []byte("pos:\t0\nflags:\t04002\nmnt_id:\t25\nino:\t164\niff:\ttap1a2b3c4d\n"), 0o600))
+
+	opened, err := networkTapOpenedBy(dir, "tap1a2b3c4d")
+	require.NoError(t, err)
+	assert.True(t, opened)
+
+	opened, err = networkTapOpenedBy(dir, "tap1a2b3c4")
+	require.NoError(t, err)
+	assert.False(t, opened)
+
+	_, err = networkTapOpenedBy(filepath.Join(dir, "missing"), "tap1a2b3c4d")
+	assert.Error(t, err)
+}
+
+func Test_networkSameHwaddr(t *testing.T) {
+	assert.True(t, networkSameHwaddr("10:66:6a:00:00:01", "10:66:6A:00:00:01"))
+	assert.True(t, networkSameHwaddr("10-66-6a-00-00-01", "10:66:6a:00:00:01"))
+	assert.False(t, networkSameHwaddr("10:66:6a:00:00:01", "10:66:6a:00:00:02"))
+	assert.False(t, networkSameHwaddr("", ""))
+}
+
+func Test_networkValidateMirrorTarget(t *testing.T) {
+	validator := networkValidateMirrorTarget("c1", "eth0")
+
+	tests := []struct {
+		value   string
+		wantErr string
+	}{
+		{value: ""},
+		{value: "c2/eth0"},
+		{value: "c1/eth1"},
+		{value: "c2", wantErr: "Mirror target must be of the form <instance>/<device>"},
+		{value: "c2/", wantErr: "Mirror target must be of the form <instance>/<device>"},
+		{value: "/eth0", wantErr: "Invalid mirror target instance name: Name must be 1-63 characters long"},
+		{value: "c_2/eth0", wantErr: `Invalid mirror target instance name: Name can only contain alphanumeric and hyphen characters`},
+		{value: "c1/eth0", wantErr: "A NIC cannot mirror its traffic to itself"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			err := validator(tt.value)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_networkHostVethActions(t *testing.T) {
+	police := func(pipe bool) *ip.ActionPolice {
+		return &ip.ActionPolice{Rate: 1000000 / 8, Burst: 1000000 / 40, Mtu: 65535, Drop: true, Pipe: pipe}
+	}
+
+	tests := []struct {
+		name            string
+		mirrorDev       string
+		mirrorDirection string
+		egressRate      int64
+		ingressShaping  string
+		egressShaping   string
+		wantIngress     []ip.Action
+		wantEgress      []ip.Action
+	}{
+		{
+			name: "nothing",
+		},
+		{
+			name:        "mirroring",
+			mirrorDev:   "veth2",
+			wantIngress: []ip.Action{&ip.ActionMirred{Dev: "veth2"}},
+			wantEgress:  []ip.Action{&ip.ActionMirred{Dev: "veth2"}},
+		},
+		{
+			name:            "mirroring the received traffic",
+			mirrorDev:       "veth2",
+			mirrorDirection: "ingress",
+			wantIngress:     []ip.Action{&ip.ActionMirred{Dev: "veth2"}},
+		},
+		{
+			name:            "mirroring the sent traffic",
+			mirrorDev:       "veth2",
+			mirrorDirection: "egress",
+			wantEgress:      []ip.Action{&ip.ActionMirred{Dev: "veth2"}},
+		},
+		{
+			name:       "policing",
+			egressRate: 1000000,
+			wantEgress: []ip.Action{police(false)},
+		},
+		{
+			name:           "project shaping",
+			ingressShaping: "incin-1c8a7bf2",
+			egressShaping:  "incout-1c8a7bf2",
+			wantIngress:    []ip.Action{&ip.ActionMirred{Dev: "incin-1c8a7bf2", Redirect: true}},
+			wantEgress:     []ip.Action{&ip.ActionMirred{Dev: "incout-1c8a7bf2", Redirect: true}},
+		},
+		{
+			name:           "mirroring, policing and project shaping",
+			mirrorDev:      "veth2",
+			egressRate:     1000000,
+			ingressShaping: "incin-1c8a7bf2",
+			egressShaping:  "incout-1c8a7bf2",
+			wantIngress:    []ip.Action{&ip.ActionMirred{Dev: "veth2"}, &ip.ActionMirred{Dev: "incin-1c8a7bf2", Redirect: true}},
+			wantEgress:     []ip.Action{&ip.ActionMirred{Dev: "veth2"}, police(true), &ip.ActionMirred{Dev: "incout-1c8a7bf2", Redirect: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingress, egress := networkHostVethActions(tt.mirrorDev, tt.mirrorDirection, tt.egressRate, tt.ingressShaping, tt.egressShaping)
+			assert.Equal(t, tt.wantIngress, ingress)
+			assert.Equal(t, tt.wantEgress, egress)
+		})
+	}
+}
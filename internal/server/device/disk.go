@@ -127,6 +127,11 @@ func (d *disk) CanMigrate() bool {
 		return true
 	}
 
+	// Network disks are reachable from any cluster member.
+	if d.sourceIsNetwork() {
+		return true
+	}
+
 	return false
 }
 
@@ -140,6 +145,11 @@ func (d *disk) sourceIsCeph() bool {
 	return strings.HasPrefix(d.config["source"], "ceph:")
 }
 
+// sourceIsNetwork returns true if the disks source config setting is a network block device (NBD, iSCSI or HTTP).
+func (d *disk) sourceIsNetwork() bool {
+	return d.config["pool"] == "" && internalInstance.IsNetworkDiskSource(d.config["source"])
+}
+
 // CanHotPlug returns whether the device can be managed whilst the instance is running.
 func (d *disk) CanHotPlug() bool {
 	if d.config["source"] == diskSourceTmpfs || d.config["source"] == diskSourceTmpfsOverlay {
@@ -232,7 +242,7 @@ func (d *disk) sourceIsLocalPath(source string) bool {
 		return false
 	}
 
-	if d.sourceIsCeph() || d.sourceIsCephFs() {
+	if d.sourceIsCeph() || d.sourceIsCephFs() || d.sourceIsNetwork() {
 		return false
 	}
 
@@ -385,6 +395,16 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 		//  shortdesc: The user name of the Ceph cluster (required for Ceph or CephFS sources)
 		"ceph.user_name": validate.IsAny,
 
+		// gendoc:generate(entity=devices, group=disk, key=nbd.kernel_mount)
+		// The file system served by the remote NBD server is mounted by the host kernel.
+		// Only enable this for NBD servers that are trusted as much as the host itself.
+		// ---
+		//  type: bool
+		//  default: `false`
+		//  required: no
+		//  shortdesc: Only for containers: Allow mounting an `nbd://` source through the host kernel NBD client
+		"nbd.kernel_mount": validate.Optional(validate.IsBool),
+
 		// gendoc:generate(entity=devices, group=disk, key=boot.priority)
 		//
 		// ---
@@ -525,6 +545,27 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 		return fmt.Errorf("Invalid options ceph.cluster_name/ceph.user_name for source %q", d.config["source"])
 	}
 
+	// Check network sources are well formed.
+	if d.sourceIsNetwork() {
+		src, err := DiskParseNetworkSource(d.config["source"])
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains([]string{"http", "https"}, src.Protocol) && util.IsFalse(d.config["readonly"]) {
+			return errors.New("HTTP disk sources are always read-only")
+		}
+
+		// Containers mount the file system in the host kernel, only do that when explicitly requested.
+		if instConf.Type() == instancetype.Container && src.Protocol == "nbd" && !util.IsTrue(d.config["nbd.kernel_mount"]) {
+			return errors.New(`NBD disk sources require "nbd.kernel_mount" to be enabled on containers`)
+		}
+	}
+
+	if d.config["nbd.kernel_mount"] != "" && !strings.HasPrefix(d.config["source"], "nbd://") {
+		return fmt.Errorf("Invalid option nbd.kernel_mount for source %q", d.config["source"])
+	}
+
 	if (d.config["source"] == diskSourceTmpfs || d.config["source"] == diskSourceTmpfsOverlay) && d.config["path"] == "" {
 		return errors.New(`Missing mount "path" setting`)
 	}
@@ -846,6 +887,17 @@ func (d *disk) validateEnvironment() error {
 		return fmt.Errorf("disks with source=%s are only supported by containers", d.config["source"])
 	}
 
+	if d.inst.Type() != instancetype.VM && d.sourceIsNetwork() && !strings.HasPrefix(d.config["source"], "nbd://") {
+		return errors.New("iSCSI and HTTP disk sources are only supported by virtual machines")
+	}
+
+	if d.inst.Type() == instancetype.Container && d.sourceIsNetwork() {
+		_, err := exec.LookPath("nbd-client")
+		if err != nil {
+			return fmt.Errorf("Required tool '%s' is missing", "nbd-client")
+		}
+	}
+
 	err := d.validateEnvironmentSourcePath()
 	if err != nil {
 		return err
@@ -1410,6 +1462,37 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 		runConf.Mounts = []deviceConfig.MountEntryItem{mount}
 		reverter.Success()
 
+		return &runConf, nil
+	} else if d.sourceIsNetwork() {
+		// Network sources are accessed directly by QEMU's block layer.
+		mount := deviceConfig.MountEntryItem{
+			DevPath: d.config["source"],
+			DevName: d.name,
+			Opts:    opts,
+			Limits:  diskLimits,
+		}
+
+		src, err := DiskParseNetworkSource(d.config["source"])
+		if err != nil {
+			return nil, err
+		}
+
+		if util.IsTrue(d.config["readonly"]) || slices.Contains([]string{"http", "https"}, src.Protocol) {
+			mount.Opts = append(mount.Opts, "ro")
+		}
+
+		// Installer media served over HTTP is attached as a CD-ROM.
+		if slices.Contains([]string{"http", "https"}, src.Protocol) && strings.HasSuffix(strings.ToLower(src.URL), ".iso") {
+			mount.FSType = "iso9660"
+		}
+
+		err = d.setBus(&mount)
+		if err != nil {
+			return nil, err
+		}
+
+		runConf.Mounts = []deviceConfig.MountEntryItem{mount}
+
 		return &runConf, nil
 	} else if d.config["source"] != "" {
 		if d.sourceIsCeph() {
@@ -2118,6 +2201,33 @@ func (d *disk) createDevice(srcPath string) (func(), string, bool, error) {
 
 			srcPath = rbdPath
 			isFile = false
+		} else if d.sourceIsNetwork() {
+			src, err := DiskParseNetworkSource(d.config["source"])
+			if err != nil {
+				return nil, "", false, err
+			}
+
+			// Connect the export to a kernel NBD device.
+			nbdPath, err := diskNBDConnect(src, d.nbdOwner())
+			if err != nil {
+				return nil, "", false, diskSourceNotFoundError{msg: "Failed connecting NBD export", err: err}
+			}
+
+			// Record the device path.
+			err = d.volatileSet(map[string]string{"nbd_device": nbdPath})
+			if err != nil {
+				_ = diskNBDDisconnect(nbdPath)
+				return nil, "", false, err
+			}
+
+			fsName, err = BlockFsDetect(nbdPath)
+			if err != nil {
+				_ = diskNBDDisconnect(nbdPath)
+				return nil, "", false, fmt.Errorf("Failed detecting source path %q block device filesystem: %w", nbdPath, err)
+			}
+
+			srcPath = nbdPath
+			isFile = false
 		} else {
 			fileInfo, err := os.Stat(srcPath)
 			if err != nil {
@@ -2497,8 +2607,16 @@ func (d *disk) stopVM() (*deviceConfig.RunConfig, error) {
 
 // postStop is run after the device is removed from the instance.
 func (d *disk) postStop() error {
+	devPath := d.getDevicePath(d.name, d.config)
+
+	// Check the NBD device was connected by this disk while it's still mounted.
+	nbdOwned := false
+	if d.sourceIsNetwork() {
+		nbdOwned = diskNBDOwned(d.volatileGet()["nbd_device"], d.nbdOwner(), devPath)
+	}
+
 	// Clean any existing device mount entry. Should occur first before custom volume unmounts.
-	err := DiskMountClear(d.getDevicePath(d.name, d.config))
+	err := DiskMountClear(devPath)
 	if err != nil {
 		return err
 	}
@@ -2528,9 +2646,33 @@ func (d *disk) postStop() error {
 		}
 	}
 
+	if d.sourceIsNetwork() {
+		v := d.volatileGet()
+		if v["nbd_device"] != "" {
+			if nbdOwned {
+				err := diskNBDDisconnect(v["nbd_device"])
+				if err != nil {
+					d.logger.Error("Failed to disconnect NBD device", logger.Ctx{"nbd": v["nbd_device"], "err": err})
+				}
+			} else {
+				d.logger.Warn("Not disconnecting NBD device not connected by this disk", logger.Ctx{"nbd": v["nbd_device"]})
+			}
+
+			err = d.volatileSet(map[string]string{"nbd_device": ""})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
+// nbdOwner returns the identifier recorded for the kernel NBD device connected by this disk.
+func (d *disk) nbdOwner() string {
+	return fmt.Sprintf("%s/%s/%s", d.inst.Project().Name, d.inst.Name(), d.name)
+}
+
 // getDiskLimits calculates Block I/O limits.
 func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 	result := map[string]diskBlockLimit{}
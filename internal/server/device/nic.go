@@ -63,6 +63,11 @@ func nicValidationRules(requiredFields []string, optionalFields []string, instCo
 		"pci":                                  validate.IsPCIAddress,
 		"attached":                             validate.Optional(validate.IsBool),
 		"connected":                            validate.Optional(validate.IsBool),
+		"mirror.target":                        validate.IsAny,
+		"mirror.direction":                     validate.Optional(validate.IsOneOf("both", "ingress", "egress")),
+		"mirror.type":                          validate.Optional(validate.IsOneOf("gre", "erspan")),
+		"mirror.index":                         validate.Optional(validate.IsUint32),
+		"qos.class":                            validate.IsAny,
 	}
 
 	validators := map[string]func(value string) error{}
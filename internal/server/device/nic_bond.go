@@ -0,0 +1,505 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/internal/server/network"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+type nicBond struct {
+	deviceCommon
+}
+
+// CanHotPlug returns whether the device can be managed whilst the instance is running. Returns true.
+func (d *nicBond) CanHotPlug() bool {
+	return true
+}
+
+// validateConfig checks the supplied config for correctness.
+func (d *nicBond) validateConfig(instConf instance.ConfigReader) error {
+	if !instanceSupported(instConf.Type(), instancetype.Container, instancetype.VM) {
+		return ErrUnsupportedDevType
+	}
+
+	requiredFields := []string{
+		// gendoc:generate(entity=devices, group=nic_bond, key=parent)
+		//
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: Comma-separated list of the host devices to bond (at least two)
+		"parent",
+	}
+
+	optionalFields := []string{
+		// gendoc:generate(entity=devices, group=nic_bond, key=name)
+		//
+		// ---
+		//  type: string
+		//  default: kernel assigned
+		//  managed: no
+		//  shortdesc: The name of the interface inside the instance
+		"name",
+
+		// gendoc:generate(entity=devices, group=nic_bond, key=mtu)
+		//
+		// ---
+		//  type: integer
+		//  default: MTU of the first parent device
+		//  managed: no
+		//  shortdesc: The Maximum Transmit Unit (MTU) of the bond and of the new interface
+		"mtu",
+
+		// gendoc:generate(entity=devices, group=nic_bond, key=hwaddr)
+		//
+		// ---
+		//  type: string
+		//  default: randomly assigned
+		//  managed: no
+		//  shortdesc: The MAC address of the new interface
+		"hwaddr",
+
+		// gendoc:generate(entity=devices, group=nic_bond, key=boot.priority)
+		//
+		// ---
+		//  type: integer
+		//  managed: no
+		//  shortdesc: Boot priority for VMs (higher value boots first)
+		"boot.priority",
+
+		// gendoc:generate(entity=devices, group=nic_bond, key=io.bus)
+		//
+		// ---
+		//  type: string
+		//  default: `virtio`
+		//  managed: no
+		//  shortdesc: Override the bus for the device (can be `virtio` or `usb`) (VM only)
+		"io.bus",
+
+		// gendoc:generate(entity=devices, group=nic_bond, key=attached)
+		//
+		// ---
+		//  type: bool
+		//  default: `true`
+		//  required: no
+		//  shortdesc: Whether the NIC is plugged in or not
+		"attached",
+
+		// gendoc:generate(entity=devices, group=nic_bond, key=connected)
+		//
+		// ---
+		//  type: bool
+		//  default: `true`
+		//  required: no
+		//  shortdesc: Whether the NIC is connected to the host network (VM only)
+		"connected",
+	}
+
+	if instConf.Type() != instancetype.VM && d.config["connected"] != "" {
+		return errors.New("The \"connected\" option is only supported on virtual machines for bond NICs")
+	}
+
+	rules := nicValidationRules(requiredFields, optionalFields, instConf)
+	rules["parent"] = func(value string) error {
+		parents := util.SplitNTrimSpace(value, ",", -1, true)
+		if len(parents) < 2 {
+			return errors.New("At least two parent devices are required")
+		}
+
+		for i, parent := range parents {
+			err := validate.IsInterfaceName(parent)
+			if err != nil {
+				return fmt.Errorf("Invalid parent device %q: %w", parent, err)
+			}
+
+			if slices.Contains(parents[i+1:], parent) {
+				return fmt.Errorf("Parent device %q is specified more than once", parent)
+			}
+		}
+
+		return nil
+	}
+
+	// gendoc:generate(entity=devices, group=nic_bond, key=bond.mode)
+	//
+	// ---
+	//  type: string
+	//  default: `active-backup`
+	//  managed: no
+	//  shortdesc: The bonding mode (one of `balance-rr`, `active-backup`, `balance-xor`, `broadcast`, `802.3ad`, `balance-tlb` or `balance-alb`)
+	rules["bond.mode"] = validate.Optional(validate.IsOneOf("balance-rr", "active-backup", "balance-xor", "broadcast", "802.3ad", "balance-tlb", "balance-alb"))
+
+	// gendoc:generate(entity=devices, group=nic_bond, key=bond.miimon)
+	//
+	// ---
+	//  type: integer
+	//  default: `100`
+	//  managed: no
+	//  shortdesc: The link monitoring interval in milliseconds (`0` disables link monitoring)
+	rules["bond.miimon"] = validate.Optional(validate.IsUint32)
+
+	// gendoc:generate(entity=devices, group=nic_bond, key=bond.xmit_hash_policy)
+	//
+	// ---
+	//  type: string
+	//  default: `layer2`
+	//  managed: no
+	//  shortdesc: The transmit hash policy for the `balance-xor`, `802.3ad` and `balance-tlb` modes (one of `layer2`, `layer2+3`, `layer3+4`, `encap2+3`, `encap3+4` or `vlan+srcmac`)
+	rules["bond.xmit_hash_policy"] = validate.Optional(validate.IsOneOf("layer2", "layer2+3", "layer3+4", "encap2+3", "encap3+4", "vlan+srcmac"))
+
+	// gendoc:generate(entity=devices, group=nic_bond, key=bond.lacp_rate)
+	//
+	// ---
+	//  type: string
+	//  default: `slow`
+	//  managed: no
+	//  shortdesc: The rate at which LACPDUs are requested from the link partner in the `802.3ad` mode (`slow` or `fast`)
+	rules["bond.lacp_rate"] = validate.Optional(validate.IsOneOf("slow", "fast"))
+
+	err := d.config.Validate(rules)
+	if err != nil {
+		return err
+	}
+
+	mode := d.bondMode()
+
+	if d.config["bond.xmit_hash_policy"] != "" && !slices.Contains([]string{"balance-xor", "802.3ad", "balance-tlb"}, mode) {
+		return fmt.Errorf("The \"bond.xmit_hash_policy\" option can't be used with the %q bonding mode", mode)
+	}
+
+	if d.config["bond.lacp_rate"] != "" && mode != "802.3ad" {
+		return fmt.Errorf("The \"bond.lacp_rate\" option can't be used with the %q bonding mode", mode)
+	}
+
+	return nil
+}
+
+// bondMode returns the bonding mode of the device.
+func (d *nicBond) bondMode() string {
+	if d.config["bond.mode"] == "" {
+		return "active-backup"
+	}
+
+	return d.config["bond.mode"]
+}
+
+// validateEnvironment checks the runtime environment for correctness.
+func (d *nicBond) validateEnvironment() error {
+	if d.inst.Type() == instancetype.Container && d.config["name"] == "" {
+		return errors.New("Requires name property to start")
+	}
+
+	for _, parent := range util.SplitNTrimSpace(d.config["parent"], ",", -1, true) {
+		link, err := ip.LinkByName(parent)
+		if err != nil {
+			return fmt.Errorf("Parent device %q doesn't exist", parent)
+		}
+
+		if link.Master != "" {
+			return fmt.Errorf("Parent device %q is already attached to %q", parent, link.Master)
+		}
+	}
+
+	return nil
+}
+
+// Start is run when the device is added to a running instance or instance is starting up.
+func (d *nicBond) Start() (*deviceConfig.RunConfig, error) {
+	// Ignore detached NICs.
+	if !util.IsTrueOrEmpty(d.config["attached"]) {
+		return nil, nil
+	}
+
+	// Lock to avoid issues with instances starting in parallel.
+	networkCreateSharedDeviceLock.Lock()
+	defer networkCreateSharedDeviceLock.Unlock()
+
+	err := d.validateEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	saveData := make(map[string]string)
+	saveData["bond_name"] = network.RandomDevName("bnd")
+
+	// Record the temporary device name used for deletion later.
+	saveData["host_name"], err = d.generateHostName("mac", d.config["hwaddr"])
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the bond.
+	bond := &ip.Bond{
+		Link: ip.Link{
+			Name: saveData["bond_name"],
+		},
+		Mode:           d.bondMode(),
+		Miimon:         100,
+		XmitHashPolicy: d.config["bond.xmit_hash_policy"],
+		LACPRate:       d.config["bond.lacp_rate"],
+	}
+
+	if d.config["bond.miimon"] != "" {
+		bond.Miimon, err = strconv.Atoi(d.config["bond.miimon"])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid link monitoring interval %q: %w", d.config["bond.miimon"], err)
+		}
+	}
+
+	var mtu uint32
+	if d.config["mtu"] != "" {
+		mtu64, err := strconv.ParseUint(d.config["mtu"], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid MTU specified %q: %w", d.config["mtu"], err)
+		}
+
+		mtu = uint32(mtu64)
+		bond.MTU = mtu
+	} else {
+		// Otherwise the parent devices would get the default MTU of the bond.
+		parents := util.SplitNTrimSpace(d.config["parent"], ",", -1, true)
+		parentLink, err := ip.LinkByName(parents[0])
+		if err != nil {
+			return nil, err
+		}
+
+		bond.MTU = parentLink.MTU
+	}
+
+	err = bond.Add()
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating bond %q: %w", bond.Name, err)
+	}
+
+	// Deleting the bond releases the parent devices.
+	reverter.Add(func() { _ = network.InterfaceRemove(saveData["bond_name"]) })
+
+	// Attach the parent devices to the bond, recording their MTU so it can be restored on stop.
+	parentMTUs := []string{}
+	for _, parent := range util.SplitNTrimSpace(d.config["parent"], ",", -1, true) {
+		parentLink, err := ip.LinkByName(parent)
+		if err != nil {
+			return nil, err
+		}
+
+		parentMTUs = append(parentMTUs, strconv.FormatUint(uint64(parentLink.MTU), 10))
+
+		// The parent devices must be down to be attached.
+		err = parentLink.SetDown()
+		if err != nil {
+			return nil, fmt.Errorf("Failed bringing down parent device %q: %w", parent, err)
+		}
+
+		err = parentLink.SetMaster(bond.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed attaching parent device %q to bond %q: %w", parent, bond.Name, err)
+		}
+	}
+
+	saveData["last_state.mtu"] = strings.Join(parentMTUs, ",")
+
+	err = bond.SetUp()
+	if err != nil {
+		return nil, fmt.Errorf("Failed bringing up bond %q: %w", bond.Name, err)
+	}
+
+	// Create the MACVLAN interface on top of the bond.
+	link := &ip.Macvlan{
+		Link: ip.Link{
+			Name:   saveData["host_name"],
+			Parent: bond.Name,
+			MTU:    mtu,
+		},
+		Mode: "bridge",
+	}
+
+	// Set the MAC address.
+	if d.config["hwaddr"] != "" {
+		hwaddr, err := net.ParseMAC(d.config["hwaddr"])
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing MAC address %q: %w", d.config["hwaddr"], err)
+		}
+
+		link.Address = hwaddr
+	}
+
+	if d.inst.Type() == instancetype.VM {
+		// Enable all multicast processing which is required for IPv6 NDP functionality.
+		link.AllMulticast = true
+
+		// Bring the interface up on host side.
+		link.Up = true
+
+		// Create macvtap interface using common macvlan settings.
+		link := &ip.Macvtap{
+			Macvlan: *link,
+		}
+
+		err = link.Add()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Create macvlan interface.
+		err = link.Add()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reverter.Add(func() { _ = network.InterfaceRemove(saveData["host_name"]) })
+
+	if d.inst.Type() == instancetype.VM {
+		// Disable IPv6 on host interface to avoid getting IPv6 link-local addresses unnecessarily.
+		err = localUtil.SysctlSet(fmt.Sprintf("net/ipv6/conf/%s/disable_ipv6", link.Name), "1")
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("Failed to disable IPv6 on host interface %q: %w", link.Name, err)
+		}
+	}
+
+	err = d.volatileSet(saveData)
+	if err != nil {
+		return nil, err
+	}
+
+	runConf := deviceConfig.RunConfig{}
+	runConf.NetworkInterface = []deviceConfig.RunConfigItem{
+		{Key: "type", Value: "phys"},
+		{Key: "name", Value: d.config["name"]},
+		{Key: "flags", Value: "up"},
+		{Key: "link", Value: saveData["host_name"]},
+		{Key: "hwaddr", Value: d.config["hwaddr"]},
+		{Key: "connected", Value: d.config["connected"]},
+	}
+
+	if d.config["io.bus"] == "usb" {
+		runConf.UseUSBBus = true
+	}
+
+	if d.inst.Type() == instancetype.VM {
+		runConf.NetworkInterface = append(runConf.NetworkInterface,
+			[]deviceConfig.RunConfigItem{
+				{Key: "devName", Value: d.name},
+				{Key: "mtu", Value: d.config["mtu"]},
+			}...)
+	}
+
+	reverter.Success()
+
+	return &runConf, nil
+}
+
+// Stop is run when the device is removed from the instance.
+func (d *nicBond) Stop() (*deviceConfig.RunConfig, error) {
+	v := d.volatileGet()
+	runConf := deviceConfig.RunConfig{
+		PostHooks: []func() error{d.postStop},
+	}
+
+	if util.IsTrueOrEmpty(d.config["attached"]) {
+		runConf.NetworkInterface = []deviceConfig.RunConfigItem{
+			{Key: "link", Value: v["host_name"]},
+		}
+	}
+
+	return &runConf, nil
+}
+
+// postStop is run after the device is removed from the instance.
+func (d *nicBond) postStop() error {
+	defer func() {
+		_ = d.volatileSet(map[string]string{
+			"host_name":      "",
+			"bond_name":      "",
+			"last_state.mtu": "",
+		})
+	}()
+
+	errs := []error{}
+	v := d.volatileGet()
+
+	// Delete the detached device.
+	if network.InterfaceExists(v["host_name"]) {
+		err := network.InterfaceRemove(v["host_name"])
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Delete the bond, which releases the parent devices.
+	if network.InterfaceExists(v["bond_name"]) {
+		err := network.InterfaceRemove(v["bond_name"])
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Restore the MTU of the parent devices and bring them back up.
+	parentMTUs := util.SplitNTrimSpace(v["last_state.mtu"], ",", -1, true)
+	for i, parent := range util.SplitNTrimSpace(d.config["parent"], ",", -1, true) {
+		if !network.InterfaceExists(parent) {
+			continue
+		}
+
+		link := &ip.Link{Name: parent}
+
+		if i < len(parentMTUs) {
+			mtu, err := strconv.ParseUint(parentMTUs[i], 10, 32)
+			if err == nil {
+				err = link.SetMTU(uint32(mtu))
+			}
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed restoring MTU of parent device %q: %w", parent, err))
+			}
+		}
+
+		err := link.SetUp()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%v", errs)
+	}
+
+	return nil
+}
+
+// UpdatableFields returns a list of fields that can be updated without triggering a device remove & add.
+func (d *nicBond) UpdatableFields(oldDevice Type) []string {
+	// Check old and new device types match.
+	_, match := oldDevice.(*nicBond)
+	if !match {
+		return []string{}
+	}
+
+	return []string{"connected"}
+}
+
+// Update applies configuration changes to a started device.
+func (d *nicBond) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
+	if isRunning {
+		return d.setNICLink()
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/osarch"
+)
+
+// nicBondTestInstance is a minimal instance.ConfigReader for the bond NIC validation tests.
+type nicBondTestInstance struct {
+	instanceType instancetype.Type
+	devices      deviceConfig.Devices
+}
+
+func (i *nicBondTestInstance) Project() api.Project                  { return api.Project{Name: api.ProjectDefaultName} }
+func (i *nicBondTestInstance) Type() instancetype.Type               { return i.instanceType }
+func (i *nicBondTestInstance) Architecture() int                     { return osarch.ARCH_64BIT_INTEL_X86 }
+func (i *nicBondTestInstance) ID() int                               { return 1 }
+func (i *nicBondTestInstance) Name() string                          { return "c1" }
+func (i *nicBondTestInstance) ExpandedConfig() map[string]string     { return map[string]string{} }
+func (i *nicBondTestInstance) ExpandedDevices() deviceConfig.Devices { return i.devices }
+func (i *nicBondTestInstance) LocalConfig() map[string]string        { return map[string]string{} }
+func (i *nicBondTestInstance) LocalDevices() deviceConfig.Devices    { return i.devices }
+
+func Test_nicBondValidateConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		instanceType instancetype.Type
+		config       deviceConfig.Device
+		wantErr      string
+	}{
+		{
+			name:   "minimal",
+			config: deviceConfig.Device{"parent": "eth0,eth1"},
+		},
+		{
+			name:   "all options",
+			config: deviceConfig.Device{"parent": "eth0, eth1, eth2", "name": "bond0", "mtu": "9000", "hwaddr": "10:66:6a:00:00:01", "bond.mode": "802.3ad", "bond.miimon": "50", "bond.xmit_hash_policy": "layer3+4", "bond.lacp_rate": "fast"},
+		},
+		{
+			name:         "connected on VM",
+			instanceType: instancetype.VM,
+			config:       deviceConfig.Device{"parent": "eth0,eth1", "connected": "false", "io.bus": "usb"},
+		},
+		{
+			name:    "missing parent",
+			config:  deviceConfig.Device{},
+			wantErr: `Invalid value for device option "parent": At least two parent devices are required`,
+		},
+		{
+			name:    "single parent",
+			config:  deviceConfig.Device{"parent": "eth0"},
+			wantErr: `Invalid value for device option "parent": At least two parent devices are required`,
+		},
+		{
+			name:    "duplicate parent",
+			config:  deviceConfig.Device{"parent": "eth0,eth1,eth0"},
+			wantErr: `Invalid value for device option "parent": Parent device "eth0" is specified more than once`,
+		},
+		{
+			name:    "invalid parent",
+			config:  deviceConfig.Device{"parent": "eth0,eth/1"},
+			wantErr: `Invalid value for device option "parent": Invalid parent device "eth/1": Network interface contains invalid characters`,
+		},
+		{
+			name:    "invalid mode",
+			config:  deviceConfig.Device{"parent": "eth0,eth1", "bond.mode": "balance-foo"},
+			wantErr: `Invalid value for device option "bond.mode": Invalid value "balance-foo" (not one of [balance-rr active-backup balance-xor broadcast 802.3ad balance-tlb balance-alb])`,
+		},
+		{
+			name:    "invalid link monitoring interval",
+			config:  deviceConfig.Device{"parent": "eth0,eth1", "bond.miimon": "-1"},
+			wantErr: `Invalid value for device option "bond.miimon": Invalid value for uint32 "-1": strconv.ParseUint: parsing "-1": invalid syntax`,
+		},
+		{
+			name:    "hash policy with the default mode",
+			config:  deviceConfig.Device{"parent": "eth0,eth1", "bond.xmit_hash_policy": "layer2+3"},
+			wantErr: `The "bond.xmit_hash_policy" option can't be used with the "active-backup" bonding mode`,
+		},
+		{
+			name:    "LACP rate without 802.3ad",
+			config:  deviceConfig.Device{"parent": "eth0,eth1", "bond.mode": "balance-xor", "bond.lacp_rate": "fast"},
+			wantErr: `The "bond.lacp_rate" option can't be used with the "balance-xor" bonding mode`,
+		},
+		{
+			name:    "connected on container",
+			config:  deviceConfig.Device{"parent": "eth0,eth1", "connected": "false"},
+			wantErr: `The "connected" option is only supported on virtual machines for bond NICs`,
+		},
+		{
+			name:    "unknown option",
+			config:  deviceConfig.Device{"parent": "eth0,eth1", "vlan": "10"},
+			wantErr: `Invalid device option "vlan"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instanceType := tt.instanceType
+			if instanceType == instancetype.Any {
+				instanceType = instancetype.Container
+			}
+
+			config := deviceConfig.Device{"type": "nic", "nictype": "bond"}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+
+			inst := &nicBondTestInstance{
+				instanceType: instanceType,
+				devices:      deviceConfig.Devices{"eth0": config},
+			}
+
+			d := &nicBond{deviceCommon{name: "eth0", config: config}}
+			err := d.validateConfig(inst)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_nicBondMode(t *testing.T) {
+	d := &nicBond{deviceCommon{config: deviceConfig.Device{}}}
+	assert.Equal(t, "active-backup", d.bondMode())
+
+	d.config["bond.mode"] = "802.3ad"
+	assert.Equal(t, "802.3ad", d.bondMode())
+}
@@ -46,6 +46,7 @@ import (
 
 type bridgeNetwork interface {
 	UsesDNSMasq() bool
+	FloatingIPRefresh(projectName string, instanceName string) error
 }
 
 type nicBridged struct {
@@ -164,6 +165,31 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader) error {
 		//  shortdesc: The priority for outgoing traffic, to be used by the kernel queuing discipline to prioritize network packets
 		"limits.priority",
 
+		// gendoc:generate(entity=devices, group=nic_bridged, key=mirror.target)
+		//
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: NIC to mirror the traffic to, as `<instance>/<device>` (a bridged NIC of a running instance in the same project on the same server)
+		"mirror.target",
+
+		// gendoc:generate(entity=devices, group=nic_bridged, key=mirror.direction)
+		//
+		// ---
+		//  type: string
+		//  default: `both`
+		//  managed: no
+		//  shortdesc: Direction of the traffic to mirror, from the instance's point of view (`both`, `ingress` or `egress`)
+		"mirror.direction",
+
+		// gendoc:generate(entity=devices, group=nic_bridged, key=qos.class)
+		//
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: QoS class of the parent network to classify the outgoing traffic into (see {ref}`network-bridge-qos`)
+		"qos.class",
+
 		// gendoc:generate(entity=devices, group=nic_bridged, key=ipv4.address)
 		//
 		// ---
@@ -525,6 +551,19 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader) error {
 		if err != nil {
 			return err
 		}
+
+		// Check the static addresses aren't reserved for another client by a DHCP reservation of the network.
+		if d.network != nil {
+			hwaddr := d.config["hwaddr"]
+			if hwaddr == "" {
+				hwaddr = d.volatileGet()["hwaddr"]
+			}
+
+			err = d.network.ReservationConflict(hwaddr, net.ParseIP(d.config["ipv4.address"]), net.ParseIP(d.config["ipv6.address"]))
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// Check if security ACL(s) are configured.
@@ -545,6 +584,17 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader) error {
 		}
 	}
 
+	// Check the QoS class is defined on the network.
+	if d.config["qos.class"] != "" {
+		if d.state.Firewall.String() != "nftables" {
+			return errors.New("QoS classes are only supported when using nftables firewall")
+		}
+
+		if d.network == nil || !network.QoSClassExists(d.network.Config(), d.config["qos.class"]) {
+			return fmt.Errorf("QoS class %q isn't defined on the parent network", d.config["qos.class"])
+		}
+	}
+
 	rules := nicValidationRules(requiredFields, optionalFields, instConf)
 
 	// Add bridge specific vlan validation.
@@ -584,6 +634,8 @@ func (d *nicBridged) validateConfig(instConf instance.ConfigReader) error {
 		return nil
 	}
 
+	rules["mirror.target"] = networkValidateMirrorTarget(instConf.Name(), d.name)
+
 	// Add bridge specific ipv4/ipv6 validation rules
 	rules["ipv4.address"] = func(value string) error {
 		if value == "" || value == "none" {
@@ -711,7 +763,7 @@ func (d *nicBridged) UpdatableFields(oldDevice Type) []string {
 		return []string{}
 	}
 
-	return []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority", "ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external", "ipv4.address", "ipv6.address", "security.mac_filtering", "security.ipv4_filtering", "security.ipv6_filtering", "security.acls", "security.acls.default.egress.action", "security.acls.default.egress.logged", "security.acls.default.ingress.action", "security.acls.default.ingress.logged", "connected"}
+	return []string{"limits.ingress", "limits.egress", "limits.max", "limits.priority", "mirror.target", "mirror.direction", "qos.class", "ipv4.routes", "ipv6.routes", "ipv4.routes.external", "ipv6.routes.external", "ipv4.address", "ipv6.address", "security.mac_filtering", "security.ipv4_filtering", "security.ipv6_filtering", "security.acls", "security.acls.default.egress.action", "security.acls.default.egress.logged", "security.acls.default.ingress.action", "security.acls.default.ingress.logged", "connected"}
 }
 
 // Add is run when a device is added to a non-snapshot instance whether or not the instance is running.
@@ -789,6 +841,9 @@ func (d *nicBridged) Start() (*deviceConfig.RunConfig, error) {
 
 	reverter.Add(func() { _ = network.InterfaceRemove(saveData["host_name"]) })
 
+	networkTrackHostInterface(&d.deviceCommon, "bridged", saveData["host_name"])
+	reverter.Add(func() { networkUntrackHostInterface(&d.deviceCommon) })
+
 	// Populate device config with volatile fields if needed.
 	networkVethFillFromVolatile(d.config, saveData)
 
@@ -822,6 +877,14 @@ func (d *nicBridged) Start() (*deviceConfig.RunConfig, error) {
 		return nil, err
 	}
 
+	// Classify the outgoing traffic into its QoS class.
+	if d.config["qos.class"] != "" {
+		err = d.setupQoS()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Disable IPv6 on host-side veth interface (prevents host-side interface getting link-local address)
 	// which isn't needed because the host-side interface is connected to a bridge.
 	err = localUtil.SysctlSet(fmt.Sprintf("net/ipv6/conf/%s/disable_ipv6", saveData["host_name"]), "1")
@@ -966,9 +1029,28 @@ func (d *nicBridged) postStart() error {
 		return err
 	}
 
+	// Point any NIC mirroring its traffic to this one at the new host side interface.
+	networkRefreshHostVethMirrors(d.state, d.inst, d.name)
+
+	d.refreshFloatingIPs()
+
 	return nil
 }
 
+// refreshFloatingIPs re-applies the floating IPs of the managed network targeting the instance on this member
+// so that they follow it.
+func (d *nicBridged) refreshFloatingIPs() {
+	bridgeNet, ok := d.network.(bridgeNetwork)
+	if !ok {
+		return
+	}
+
+	err := bridgeNet.FloatingIPRefresh(d.inst.Project().Name, d.inst.Name())
+	if err != nil {
+		d.logger.Warn("Failed refreshing network floating IPs", logger.Ctx{"err": err})
+	}
+}
+
 // Update applies configuration changes to a started device.
 func (d *nicBridged) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 	oldConfig := oldDevices[d.name]
@@ -1031,6 +1113,14 @@ func (d *nicBridged) Update(oldDevices deviceConfig.Devices, isRunning bool) err
 			return err
 		}
 
+		// Classify the outgoing traffic into its new QoS class.
+		if d.config["qos.class"] != oldConfig["qos.class"] {
+			err = d.setupQoS()
+			if err != nil {
+				return err
+			}
+		}
+
 		// Apply and host-side network filters (uses enriched host_name from networkVethFillFromVolatile).
 		r, err := d.setupHostFilters(oldConfig)
 		if err != nil {
@@ -1087,6 +1177,9 @@ func (d *nicBridged) Update(oldDevices deviceConfig.Devices, isRunning bool) err
 
 // Stop is run when the device is removed from the instance.
 func (d *nicBridged) Stop() (*deviceConfig.RunConfig, error) {
+	// Account the traffic of the NIC to the project before its host side interface goes away.
+	networkFlushProjectCounters(&d.deviceCommon)
+
 	// Remove BGP announcements.
 	err := bgpRemovePrefix(&d.deviceCommon, d.config)
 	if err != nil {
@@ -1101,6 +1194,13 @@ func (d *nicBridged) Stop() (*deviceConfig.RunConfig, error) {
 		return nil, err
 	}
 
+	if d.config["qos.class"] != "" {
+		err = d.state.Firewall.InstanceClearQoS(d.inst.Project().Name, d.inst.Name(), d.config["host_name"])
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Setup post-stop actions.
 	runConf := deviceConfig.RunConfig{
 		PostHooks: []func() error{d.postStop},
@@ -1126,6 +1226,11 @@ func (d *nicBridged) postStop() error {
 	v := d.volatileGet()
 
 	networkVethFillFromVolatile(d.config, v)
+	networkUntrackHostInterface(&d.deviceCommon)
+
+	if d.config["host_name"] != "" {
+		networkClearHostVethMirror(d.config["host_name"])
+	}
 
 	if d.config["host_name"] != "" && network.InterfaceExists(d.config["host_name"]) {
 		// Detach host-side end of veth pair from bridge (required for openvswitch particularly).
@@ -1153,9 +1258,21 @@ func (d *nicBridged) postStop() error {
 		d.removeFilters(d.config)
 	}
 
+	d.refreshFloatingIPs()
+
 	return nil
 }
 
+// setupQoS classifies the outgoing traffic of the NIC into its QoS class, clearing the classification if unset.
+func (d *nicBridged) setupQoS() error {
+	var networkConfig map[string]string
+	if d.network != nil {
+		networkConfig = d.network.Config()
+	}
+
+	return network.QoSNICSetup(d.state, d.inst.Project().Name, d.inst.Name(), d.config["host_name"], networkConfig, d.config["qos.class"])
+}
+
 // Remove is run when the device is removed from the instance or the instance is deleted.
 func (d *nicBridged) Remove() error {
 	// Handle the case where validation fails but the device still must be removed.
@@ -2134,6 +2251,8 @@ func (d *nicBridged) getHostMTU() (int, error) {
 
 // Register sets up anything needed on startup.
 func (d *nicBridged) Register() error {
+	networkAdoptHostInterface(&d.deviceCommon, "bridged")
+
 	// Skip when not using a managed network.
 	if d.config["network"] == "" {
 		return nil
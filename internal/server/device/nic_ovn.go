@@ -48,6 +48,7 @@ type ovnNet interface {
 	InstanceDevicePortAdd(instanceUUID string, deviceName string, deviceConfig deviceConfig.Device) error
 	InstanceDevicePortStart(opts *network.OVNInstanceNICSetupOpts, securityACLsRemove []string) (ovn.OVNSwitchPort, []net.IP, error)
 	InstanceDevicePortStop(ovsExternalOVNPort ovn.OVNSwitchPort, opts *network.OVNInstanceNICStopOpts) error
+	InstanceDevicePortMirrors(instanceUUID string, deviceName string, deviceConfig deviceConfig.Device) error
 	InstanceDevicePortRemove(instanceUUID string, devName string, devConfig deviceConfig.Device, hasDuplicate bool) error
 	InstanceDevicePortIPs(instanceUUID string, deviceName string) ([]net.IP, error)
 }
@@ -79,7 +80,7 @@ func (d *nicOVN) UpdatableFields(oldDevice Type) []string {
 		return []string{}
 	}
 
-	return []string{"security.acls", "limits.ingress", "limits.egress", "limits.max", "limits.priority", "connected"}
+	return []string{"security.acls", "limits.ingress", "limits.egress", "limits.max", "limits.priority", "mirror.target", "mirror.remote", "mirror.direction", "mirror.type", "mirror.index", "connected"}
 }
 
 // validateConfig checks the supplied config for correctness.
@@ -318,6 +319,49 @@ func (d *nicOVN) validateConfig(instConf instance.ConfigReader) error {
 		//  shortdesc: The priority for outgoing traffic, to be used by the kernel queuing discipline to prioritize network packets
 		"limits.priority",
 
+		// gendoc:generate(entity=devices, group=nic_ovn, key=mirror.target)
+		//
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: NIC to mirror the traffic to, as `<instance>/<device>` (a NIC of a running instance in the same project on the same server, requires `acceleration` set to `none`)
+		"mirror.target",
+
+		// gendoc:generate(entity=devices, group=nic_ovn, key=mirror.remote)
+		//
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: IP address of a remote collector to mirror the traffic to (through a GRE or ERSPAN tunnel)
+		"mirror.remote",
+
+		// gendoc:generate(entity=devices, group=nic_ovn, key=mirror.direction)
+		//
+		// ---
+		//  type: string
+		//  default: `both`
+		//  managed: no
+		//  shortdesc: Direction of the traffic to mirror, from the instance's point of view (`both`, `ingress` or `egress`)
+		"mirror.direction",
+
+		// gendoc:generate(entity=devices, group=nic_ovn, key=mirror.type)
+		//
+		// ---
+		//  type: string
+		//  default: `gre`
+		//  managed: no
+		//  shortdesc: Encapsulation used to send the mirrored traffic to the remote collector (`gre` or `erspan`)
+		"mirror.type",
+
+		// gendoc:generate(entity=devices, group=nic_ovn, key=mirror.index)
+		//
+		// ---
+		//  type: integer
+		//  default: `0`
+		//  managed: no
+		//  shortdesc: GRE key or ERSPAN session ID used for the traffic mirrored to the remote collector
+		"mirror.index",
+
 		// gendoc:generate(entity=devices, group=nic_ovn, key=attached)
 		//
 		// ---
@@ -475,10 +519,26 @@ func (d *nicOVN) validateConfig(instConf instance.ConfigReader) error {
 		if err != nil {
 			return err
 		}
+
+		// Check the static addresses aren't reserved for another client by a DHCP reservation of the network.
+		if d.network != nil {
+			hwaddr := d.config["hwaddr"]
+			if hwaddr == "" {
+				hwaddr = d.volatileGet()["hwaddr"]
+			}
+
+			err = d.network.ReservationConflict(hwaddr, net.ParseIP(d.config["ipv4.address"]), net.ParseIP(d.config["ipv6.address"]))
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	rules := nicValidationRules(requiredFields, optionalFields, instConf)
 
+	rules["mirror.target"] = networkValidateMirrorTarget(instConf.Name(), d.name)
+	rules["mirror.remote"] = validate.Optional(validate.IsNetworkAddress)
+
 	// Override ipv4.address and ipv6.address to allow none value.
 	rules["ipv4.address"] = validate.Optional(func(value string) error {
 		if value == "none" {
@@ -614,6 +674,11 @@ func (d *nicOVN) validateConfig(instConf instance.ConfigReader) error {
 		}
 	}
 
+	// Mirroring to another NIC relies on the host side interface.
+	if d.config["mirror.target"] != "" && (!d.isVirtualNIC() || d.config["nested"] != "") {
+		return errors.New("The \"mirror.target\" option requires setting acceleration=none for OVN NICs and isn't supported on nested NICs")
+	}
+
 	return nil
 }
 
@@ -929,6 +994,9 @@ func (d *nicOVN) Start() (*deviceConfig.RunConfig, error) {
 			}
 
 			reverter.Add(func() { _ = network.InterfaceRemove(saveData["host_name"]) })
+
+			networkTrackHostInterface(&d.deviceCommon, "ovn", saveData["host_name"])
+			reverter.Add(func() { networkUntrackHostInterface(&d.deviceCommon) })
 		}
 	}
 
@@ -955,6 +1023,7 @@ func (d *nicOVN) Start() (*deviceConfig.RunConfig, error) {
 		DeviceConfig: d.config,
 		UplinkConfig: uplinkConfig,
 		LastStateIPs: lastStateIPs, // Pass in volatile last state IPs for use with sticky DHCPv4 hint.
+		BIOS:         d.inst.Type() == instancetype.VM && util.IsTrue(d.inst.ExpandedConfig()["security.csm"]),
 	}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("Failed setting up OVN port: %w", err)
@@ -982,6 +1051,14 @@ func (d *nicOVN) Start() (*deviceConfig.RunConfig, error) {
 		})
 	})
 
+	// Setup port mirroring (only touching the mirrors if configured, as older OVN versions lack them).
+	if d.config["mirror.remote"] != "" {
+		err = d.network.InstanceDevicePortMirrors(d.inst.LocalConfig()["volatile.uuid"], d.name, d.config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Associated host side interface to OVN logical switch port (if not nested).
 	if integrationBridgeNICName != "" {
 		cleanup, err := d.setupHostNIC(integrationBridgeNICName, logicalPortName)
@@ -1090,9 +1167,39 @@ func (d *nicOVN) postStart() error {
 		return err
 	}
 
+	networkVethFillFromVolatile(d.config, d.volatileGet())
+
+	if d.config["host_name"] != "" && d.isVirtualNIC() {
+		if d.config["mirror.target"] != "" {
+			err = d.setupHostMirror()
+			if err != nil {
+				return err
+			}
+		}
+
+		// Point any NIC mirroring its traffic to this one at the new host side interface.
+		networkRefreshHostVethMirrors(d.state, d.inst, d.name)
+	}
+
 	return nil
 }
 
+// setupHostMirror mirrors the traffic of the NIC to another local NIC through traffic control rules on the host
+// side interface, as the OVN port mirrors only support remote collectors.
+func (d *nicOVN) setupHostMirror() error {
+	hostName := NetworkHostInterfaceName(d.inst.Project().Name, d.inst.Name(), d.name)
+	if hostName == "" {
+		return errors.New("Unknown or missing host side interface")
+	}
+
+	config := deviceConfig.Device{
+		"mirror.target":    d.config["mirror.target"],
+		"mirror.direction": d.config["mirror.direction"],
+	}
+
+	return networkSetupHostVethQdiscs(d.state, d.inst.Project(), hostName, config, false)
+}
+
 // Update applies configuration changes to a started device.
 func (d *nicOVN) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 	oldConfig := oldDevices[d.name]
@@ -1163,6 +1270,7 @@ func (d *nicOVN) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 				DeviceName:   d.name,
 				DeviceConfig: d.config,
 				UplinkConfig: uplinkConfig,
+				BIOS:         d.inst.Type() == instancetype.VM && util.IsTrue(d.inst.ExpandedConfig()["security.csm"]),
 			}, removedACLs)
 			if err != nil {
 				return fmt.Errorf("Failed updating OVN port: %w", err)
@@ -1182,6 +1290,29 @@ func (d *nicOVN) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 		}
 	}
 
+	// Apply any changes to the port mirroring.
+	if isRunning {
+		if (d.config["mirror.target"] != oldConfig["mirror.target"] || d.config["mirror.direction"] != oldConfig["mirror.direction"]) && d.config["host_name"] != "" && d.isVirtualNIC() {
+			err := d.setupHostMirror()
+			if err != nil {
+				return err
+			}
+		}
+
+		if d.config["mirror.remote"] != "" || oldConfig["mirror.remote"] != "" {
+			for _, key := range []string{"mirror.remote", "mirror.direction", "mirror.type", "mirror.index"} {
+				if d.config[key] != oldConfig[key] {
+					err := d.network.InstanceDevicePortMirrors(d.inst.LocalConfig()["volatile.uuid"], d.name, d.config)
+					if err != nil {
+						return err
+					}
+
+					break
+				}
+			}
+		}
+	}
+
 	// If an external address changed, update the BGP advertisements.
 	err := bgpRemovePrefix(&d.deviceCommon, oldConfig)
 	if err != nil {
@@ -1228,6 +1359,9 @@ func (d *nicOVN) findRepresentorPort(volatile map[string]string) (string, error)
 
 // Stop is run when the device is removed from the instance.
 func (d *nicOVN) Stop() (*deviceConfig.RunConfig, error) {
+	// Account the traffic of the NIC to the project before its host side interface goes away.
+	networkFlushProjectCounters(&d.deviceCommon)
+
 	runConf := deviceConfig.RunConfig{
 		PostHooks: []func() error{d.postStop},
 	}
@@ -1318,6 +1452,11 @@ func (d *nicOVN) postStop() error {
 	v := d.volatileGet()
 
 	networkVethFillFromVolatile(d.config, v)
+	networkUntrackHostInterface(&d.deviceCommon)
+
+	if d.config["host_name"] != "" {
+		networkClearHostVethMirror(d.config["host_name"])
+	}
 
 	if d.config["acceleration"] == "sriov" {
 		// Restoring host-side interface.
@@ -1519,6 +1658,10 @@ func (d *nicOVN) State() (*api.InstanceStateNetwork, error) {
 
 // Register sets up anything needed on startup.
 func (d *nicOVN) Register() error {
+	if d.config["nested"] == "" && d.isVirtualNIC() {
+		networkAdoptHostInterface(&d.deviceCommon, "ovn")
+	}
+
 	// Skip when not using a managed network.
 	if d.config["network"] == "" {
 		return nil
@@ -222,6 +222,9 @@ func (d *nicP2P) Start() (*deviceConfig.RunConfig, error) {
 
 	reverter.Add(func() { _ = network.InterfaceRemove(saveData["host_name"]) })
 
+	networkTrackHostInterface(&d.deviceCommon, "p2p", saveData["host_name"])
+	reverter.Add(func() { networkUntrackHostInterface(&d.deviceCommon) })
+
 	// Attempt to disable router advertisement acceptance.
 	err = localUtil.SysctlSet(fmt.Sprintf("net/ipv6/conf/%s/accept_ra", saveData["host_name"]), "0")
 	if err != nil && !errors.Is(err, fs.ErrNotExist) {
@@ -313,6 +316,9 @@ func (d *nicP2P) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 
 // Stop is run when the device is removed from the instance.
 func (d *nicP2P) Stop() (*deviceConfig.RunConfig, error) {
+	// Account the traffic of the NIC to the project before its host side interface goes away.
+	networkFlushProjectCounters(&d.deviceCommon)
+
 	// Populate device config with volatile fields (hwaddr and host_name) if needed.
 	networkVethFillFromVolatile(d.config, d.volatileGet())
 
@@ -339,6 +345,7 @@ func (d *nicP2P) postStop() error {
 	v := d.volatileGet()
 
 	networkVethFillFromVolatile(d.config, v)
+	networkUntrackHostInterface(&d.deviceCommon)
 
 	if d.config["host_name"] != "" && network.InterfaceExists(d.config["host_name"]) {
 		// Removing host-side end of veth pair will delete the peer end too.
@@ -350,3 +357,10 @@ func (d *nicP2P) postStop() error {
 
 	return nil
 }
+
+// Register sets up anything needed on startup.
+func (d *nicP2P) Register() error {
+	networkAdoptHostInterface(&d.deviceCommon, "p2p")
+
+	return nil
+}
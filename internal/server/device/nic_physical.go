@@ -19,17 +19,36 @@ import (
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/resources"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/util"
 )
 
+type physicalNetwork interface {
+	FloatingIPRefresh(projectName string, instanceName string) error
+}
+
 type nicPhysical struct {
 	deviceCommon
 
 	network network.Network // Populated in validateConfig().
 }
 
+// refreshFloatingIPs re-applies the floating IPs of the managed network targeting the instance on this member
+// so that they follow it.
+func (d *nicPhysical) refreshFloatingIPs() {
+	physicalNet, ok := d.network.(physicalNetwork)
+	if !ok {
+		return
+	}
+
+	err := physicalNet.FloatingIPRefresh(d.inst.Project().Name, d.inst.Name())
+	if err != nil {
+		d.logger.Warn("Failed refreshing network floating IPs", logger.Ctx{"err": err})
+	}
+}
+
 // CanHotPlug returns whether the device can be managed whilst the instance is running. Returns true.
 func (d *nicPhysical) CanHotPlug() bool {
 	return true
@@ -350,6 +369,8 @@ func (d *nicPhysical) Start() (*deviceConfig.RunConfig, error) {
 		return nil, err
 	}
 
+	d.refreshFloatingIPs()
+
 	runConf := deviceConfig.RunConfig{}
 	runConf.NetworkInterface = []deviceConfig.RunConfigItem{
 		{Key: "type", Value: "phys"},
@@ -479,6 +500,9 @@ func (d *nicPhysical) Stop() (*deviceConfig.RunConfig, error) {
 
 // postStop is run after the device is removed from the instance.
 func (d *nicPhysical) postStop() error {
+	// Withdraw the floating IPs once the NIC is marked as stopped.
+	defer d.refreshFloatingIPs()
+
 	defer func() {
 		_ = d.volatileSet(map[string]string{
 			"host_name":                "",
@@ -567,6 +567,9 @@ func (d *nicRouted) Start() (*deviceConfig.RunConfig, error) {
 
 	reverter.Add(func() { _ = network.InterfaceRemove(saveData["host_name"]) })
 
+	networkTrackHostInterface(&d.deviceCommon, "routed", saveData["host_name"])
+	reverter.Add(func() { networkUntrackHostInterface(&d.deviceCommon) })
+
 	// Populate device config with volatile fields if needed.
 	networkVethFillFromVolatile(d.config, saveData)
 
@@ -889,6 +892,9 @@ func (d *nicRouted) Update(oldDevices deviceConfig.Devices, isRunning bool) erro
 
 // Stop is run when the device is removed from the instance.
 func (d *nicRouted) Stop() (*deviceConfig.RunConfig, error) {
+	// Account the traffic of the NIC to the project before its host side interface goes away.
+	networkFlushProjectCounters(&d.deviceCommon)
+
 	// Populate device config with volatile fields (hwaddr and host_name) if needed.
 	networkVethFillFromVolatile(d.config, d.volatileGet())
 
@@ -918,6 +924,7 @@ func (d *nicRouted) postStop() error {
 	v := d.volatileGet()
 
 	networkVethFillFromVolatile(d.config, v)
+	networkUntrackHostInterface(&d.deviceCommon)
 
 	if d.config["parent"] != "" {
 		d.effectiveParentName = network.GetHostDevice(d.config["parent"], d.config["vlan"])
@@ -967,6 +974,13 @@ func (d *nicRouted) postStop() error {
 	return nil
 }
 
+// Register sets up anything needed on startup.
+func (d *nicRouted) Register() error {
+	networkAdoptHostInterface(&d.deviceCommon, "routed")
+
+	return nil
+}
+
 func (d *nicRouted) ipHostAddress(ipFamily string) net.IP {
 	key := fmt.Sprintf("%s.host_address", ipFamily)
 	if d.config[key] != "" {
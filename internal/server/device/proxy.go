@@ -3,11 +3,15 @@ package device
 import (
 	"bufio"
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
@@ -29,6 +33,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/network"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/warnings"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/subprocess"
@@ -36,6 +41,15 @@ import (
 	"github.com/lxc/incus/v6/shared/validate"
 )
 
+// proxyHostLabel matches a label of a host name.
+var proxyHostLabel = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// proxyCertificateName matches the name of a proxy certificate.
+var proxyCertificateName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// proxyACMETimeout is how long issuing an ACME certificate for a proxy device may take.
+const proxyACMETimeout = 5 * time.Minute
+
 type proxy struct {
 	deviceCommon
 }
@@ -170,6 +184,39 @@ func (d *proxy) validateConfig(instConf instance.ConfigReader) error {
 		// default: `false`
 		// shortdesc: Whether to use the HAProxy PROXY protocol to transmit sender information
 		"proxy_protocol": validate.Optional(validate.IsBool),
+
+		// gendoc:generate(entity=devices, group=proxy, key=listen.protocol)
+		//
+		// ---
+		// type: string
+		// required: no
+		// shortdesc: Protocol to handle on the listen side: `tls` to terminate TLS, `http` or `https` to route HTTP requests by host name (see {ref}`devices-proxy-tls-http`)
+		"listen.protocol": validate.Optional(validate.IsOneOf("tls", "http", "https")),
+
+		// gendoc:generate(entity=devices, group=proxy, key=listen.hosts)
+		//
+		// ---
+		// type: string
+		// required: no
+		// shortdesc: Comma-separated list of host names routed to the instance (`*.` prefix for wildcards), when several devices share the listen address
+		"listen.hosts": validate.Optional(validate.IsListOf(proxyValidHost)),
+
+		// gendoc:generate(entity=devices, group=proxy, key=listen.tls.certificate)
+		//
+		// ---
+		// type: string
+		// required: no
+		// shortdesc: Name of the proxy certificate of the project presented to the clients when terminating TLS (see {ref}`devices-proxy-tls-http`)
+		"listen.tls.certificate": validate.Optional(ProxyValidCertificateName),
+
+		// gendoc:generate(entity=devices, group=proxy, key=listen.tls.acme)
+		//
+		// ---
+		// type: bool
+		// required: no
+		// default: `false`
+		// shortdesc: Whether to issue the certificate for `listen.hosts` through ACME, using the server's `acme.*` settings
+		"listen.tls.acme": validate.Optional(validate.IsBool),
 	}
 
 	err := d.config.Validate(rules)
@@ -210,6 +257,15 @@ func (d *proxy) validateConfig(instConf instance.ConfigReader) error {
 		return errors.New("Only proxy devices for non-abstract unix sockets can carry uid, gid, or mode properties")
 	}
 
+	if d.config["listen.protocol"] != "" {
+		err = d.validateListenProtocol(listenAddr, connectAddr)
+		if err != nil {
+			return err
+		}
+	} else if d.config["listen.hosts"] != "" || d.config["listen.tls.certificate"] != "" || util.IsTrue(d.config["listen.tls.acme"]) {
+		return errors.New(`"listen.hosts" and "listen.tls.*" require "listen.protocol" to be set`)
+	}
+
 	if util.IsTrue(d.config["nat"]) {
 		if d.inst != nil {
 			// Default project always has networks feature so don't bother loading the project config
@@ -259,6 +315,84 @@ func (d *proxy) validateConfig(instConf instance.ConfigReader) error {
 	return nil
 }
 
+// validateListenProtocol checks the settings of the proxies terminating TLS or routing HTTP on the listen side.
+func (d *proxy) validateListenProtocol(listenAddr *deviceConfig.ProxyAddress, connectAddr *deviceConfig.ProxyAddress) error {
+	if util.IsTrue(d.config["nat"]) {
+		return errors.New(`"listen.protocol" cannot be used in NAT mode`)
+	}
+
+	if d.config["bind"] != "" && d.config["bind"] != "host" {
+		return errors.New(`Only host-bound proxies can use "listen.protocol"`)
+	}
+
+	if listenAddr.ConnType != "tcp" || len(listenAddr.Ports) != 1 {
+		return errors.New(`"listen.protocol" requires listening on a single TCP port`)
+	}
+
+	if connectAddr.ConnType == "udp" {
+		return errors.New(`"listen.protocol" cannot be used to connect to UDP servers`)
+	}
+
+	if util.IsTrue(d.config["proxy_protocol"]) && d.config["listen.protocol"] != "tls" {
+		return errors.New(`The PROXY header can only be sent with the "tls" listen protocol`)
+	}
+
+	if d.config["listen.protocol"] == "http" {
+		if d.config["listen.tls.certificate"] != "" || util.IsTrue(d.config["listen.tls.acme"]) {
+			return errors.New(`"listen.tls.*" cannot be used with the "http" listen protocol`)
+		}
+
+		return nil
+	}
+
+	if util.IsTrue(d.config["listen.tls.acme"]) {
+		if d.config["listen.tls.certificate"] != "" {
+			return errors.New(`"listen.tls.acme" cannot be used together with "listen.tls.certificate"`)
+		}
+
+		hosts := util.SplitNTrimSpace(d.config["listen.hosts"], ",", -1, true)
+		if len(hosts) != 1 || strings.Contains(hosts[0], "*") {
+			return errors.New(`"listen.tls.acme" requires "listen.hosts" to be set to a single host name without wildcard`)
+		}
+
+		return nil
+	}
+
+	if d.config["listen.tls.certificate"] == "" {
+		return fmt.Errorf(`The %q listen protocol requires "listen.tls.certificate" or "listen.tls.acme"`, d.config["listen.protocol"])
+	}
+
+	return nil
+}
+
+// ProxyValidCertificateName checks the value is a valid proxy certificate name.
+func ProxyValidCertificateName(value string) error {
+	if !proxyCertificateName.MatchString(value) {
+		return fmt.Errorf("Invalid certificate name %q", value)
+	}
+
+	return nil
+}
+
+// proxyValidHost checks the value is a host name, optionally starting with a wildcard label.
+func proxyValidHost(value string) error {
+	if len(value) > 253 {
+		return fmt.Errorf("Invalid host name %q: Too long", value)
+	}
+
+	for i, label := range strings.Split(value, ".") {
+		if i == 0 && label == "*" {
+			continue
+		}
+
+		if !proxyHostLabel.MatchString(label) {
+			return fmt.Errorf("Invalid host name %q", value)
+		}
+	}
+
+	return nil
+}
+
 // validateEnvironment checks the runtime environment for correctness.
 func (d *proxy) validateEnvironment() error {
 	if d.name == "" {
@@ -414,6 +548,14 @@ func (d *proxy) Start() (*deviceConfig.RunConfig, error) {
 						return fmt.Errorf("Failed to start device %q: Failed saving subprocess details: %w", d.name, err)
 					}
 
+					if d.config["listen.protocol"] != "" {
+						err = d.setupRoute()
+						if err != nil {
+							_ = d.killProxyProc(pidPath)
+							return fmt.Errorf("Failed to start device %q: %w", d.name, err)
+						}
+					}
+
 					return nil
 				}
 
@@ -467,6 +609,13 @@ func (d *proxy) Stop() (*deviceConfig.RunConfig, error) {
 		logger.Errorf("Failed to remove proxy NAT filters: %v", err)
 	}
 
+	if d.config["listen.protocol"] != "" {
+		address, err := d.routeAddress()
+		if err == nil {
+			proxyRouterRemove(address, d.routeKey())
+		}
+	}
+
 	devFileName := fmt.Sprintf("proxy.%s", d.name)
 	devPath := filepath.Join(d.inst.DevicesPath(), devFileName)
 
@@ -633,6 +782,27 @@ func (d *proxy) setupProxyProcInfo() (*proxyProcInfo, error) {
 	connectAddr := d.config["connect"]
 	listenAddr := d.config["listen"]
 
+	listenAddrGID := d.config["gid"]
+	listenAddrUID := d.config["uid"]
+	listenAddrMode := "0644"
+	if d.config["mode"] != "" {
+		listenAddrMode = d.config["mode"]
+	}
+
+	// The proxies terminating TLS or routing HTTP get their connections from the router of the listen address,
+	// through a socket only accessible to root.
+	if d.config["listen.protocol"] != "" {
+		err := os.MkdirAll(internalUtil.RunPath("proxy"), 0o700)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create proxy socket directory: %w", err)
+		}
+
+		listenAddr = fmt.Sprintf("unix:%s", d.routeSocket())
+		listenAddrGID = "0"
+		listenAddrUID = "0"
+		listenAddrMode = "0600"
+	}
+
 	switch d.config["bind"] {
 	case "host", "":
 		listenPid = daemonPid
@@ -650,11 +820,6 @@ func (d *proxy) setupProxyProcInfo() (*proxyProcInfo, error) {
 		return nil, errors.New("Invalid binding side given. Must be \"host\" or \"instance\"")
 	}
 
-	listenAddrMode := "0644"
-	if d.config["mode"] != "" {
-		listenAddrMode = d.config["mode"]
-	}
-
 	p := &proxyProcInfo{
 		listenPid:      listenPid,
 		listenPidFd:    listenPidFd,
@@ -662,8 +827,8 @@ func (d *proxy) setupProxyProcInfo() (*proxyProcInfo, error) {
 		connectPidFd:   connectPidFd,
 		connectAddr:    connectAddr,
 		listenAddr:     listenAddr,
-		listenAddrGID:  d.config["gid"],
-		listenAddrUID:  d.config["uid"],
+		listenAddrGID:  listenAddrGID,
+		listenAddrUID:  listenAddrUID,
 		listenAddrMode: listenAddrMode,
 		securityGID:    d.config["security.gid"],
 		securityUID:    d.config["security.uid"],
@@ -671,9 +836,114 @@ func (d *proxy) setupProxyProcInfo() (*proxyProcInfo, error) {
 		inheritFds:     inheritFd,
 	}
 
+	// The router sends the PROXY header itself as forkproxy would only see the router as the client.
+	if d.config["listen.protocol"] != "" {
+		p.proxyProtocol = "false"
+	}
+
 	return p, nil
 }
 
+// routeKey returns the key of the route of the device in the router of its listen address.
+func (d *proxy) routeKey() string {
+	return fmt.Sprintf("%s/%s", project.Instance(d.inst.Project().Name, d.inst.Name()), d.name)
+}
+
+// routeSocket returns the path of the socket the forkproxy of the device listens on for the connections of the router.
+func (d *proxy) routeSocket() string {
+	hash := sha256.Sum256([]byte(d.routeKey()))
+
+	return internalUtil.RunPath("proxy", fmt.Sprintf("%s.sock", hex.EncodeToString(hash[:])[:32]))
+}
+
+// routeAddress returns the address the router of the device listens on.
+func (d *proxy) routeAddress() (string, error) {
+	listenAddr, err := network.ProxyParseAddr(d.config["listen"])
+	if err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(listenAddr.Address, strconv.FormatUint(listenAddr.Ports[0], 10)), nil
+}
+
+// setupRoute adds the route of the device to the router of its listen address, relaying the connections or requests
+// to the forkproxy of the device.
+func (d *proxy) setupRoute() error {
+	address, err := d.routeAddress()
+	if err != nil {
+		return err
+	}
+
+	route := &proxyRoute{
+		hosts:         util.SplitNTrimSpace(d.config["listen.hosts"], ",", -1, true),
+		socket:        d.routeSocket(),
+		proxyProtocol: util.IsTrue(d.config["proxy_protocol"]),
+	}
+
+	for i, host := range route.hosts {
+		route.hosts[i] = strings.ToLower(host)
+	}
+
+	if util.IsTrue(d.config["listen.tls.acme"]) {
+		route.acmeDomain = route.hosts[0]
+		route.certPath = filepath.Join(d.inst.DevicesPath(), fmt.Sprintf("proxy.%s.crt", d.name))
+		route.keyPath = filepath.Join(d.inst.DevicesPath(), fmt.Sprintf("proxy.%s.key", d.name))
+
+		// Use the previously issued certificate until a new one is issued.
+		if util.PathExists(route.certPath) {
+			certificate, err := tls.LoadX509KeyPair(route.certPath, route.keyPath)
+			if err == nil {
+				route.certificate = &certificate
+			}
+		}
+	} else if d.config["listen.tls.certificate"] != "" {
+		route.certificate, err = proxyLoadCertificate(d.state, d.inst.Project().Name, d.config["listen.tls.certificate"])
+		if err != nil {
+			return err
+		}
+	}
+
+	key := d.routeKey()
+
+	err = proxyRouterAdd(d.state, address, d.inst.Project().Name, d.config["listen.protocol"], key, route)
+	if err != nil {
+		return err
+	}
+
+	// Issue the ACME certificate in the background as it can take a while.
+	if route.acmeDomain != "" {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), proxyACMETimeout)
+			defer cancel()
+
+			certificate, err := proxyACMECertificate(ctx, d.state, route.acmeDomain, route.certPath, route.keyPath)
+			if err != nil {
+				d.logger.Error("Failed to get ACME certificate", logger.Ctx{"domain": route.acmeDomain, "err": err})
+				return
+			}
+
+			proxyRouterUpdateCertificate(address, key, certificate)
+		}()
+	}
+
+	return nil
+}
+
+// Register re-adds the route of a running device to the router of its listen address after a daemon restart,
+// the forkproxy of the device outliving the daemon.
+func (d *proxy) Register() error {
+	if d.config["listen.protocol"] == "" {
+		return nil
+	}
+
+	devPath := filepath.Join(d.inst.DevicesPath(), fmt.Sprintf("proxy.%s", d.name))
+	if !util.PathExists(devPath) {
+		return nil
+	}
+
+	return d.setupRoute()
+}
+
 func (d *proxy) killProxyProc(pidPath string) error {
 	// If the pid file doesn't exist, there is no process to kill.
 	if !util.PathExists(pidPath) {
@@ -0,0 +1,490 @@
+package device
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/acme"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// proxyRoute routes the connections or requests of a proxy router to the forkproxy of a proxy device.
+type proxyRoute struct {
+	// Host names routed to the device, none for a catch-all route.
+	hosts []string
+
+	// Unix socket the forkproxy of the device listens on.
+	socket string
+
+	// Whether to send the HAProxy PROXY header to the device (TLS routers only).
+	proxyProtocol bool
+
+	// Certificate presented to the clients (TLS routers only).
+	certificate *tls.Certificate
+
+	// Domain and files of the certificate when it's issued through ACME.
+	acmeDomain string
+	certPath   string
+	keyPath    string
+
+	// Handler relaying the requests to the device (HTTP routers only).
+	handler http.Handler
+}
+
+// proxyRouter is a listener shared by the proxy devices terminating TLS or routing HTTP on the same address.
+type proxyRouter struct {
+	state    *state.State
+	project  string
+	protocol string
+	listener net.Listener
+	server   *http.Server
+
+	// Routes keyed by device.
+	routes   map[string]*proxyRoute
+	routesMu sync.RWMutex
+}
+
+// proxyRouters are the active proxy routers, keyed by listen address.
+// A listen address is only ever shared by the devices of a single project.
+var proxyRouters = map[string]*proxyRouter{}
+
+var proxyRoutersMu sync.Mutex
+
+// proxyRouterAdd adds the route of a device of the project to the router of the listen address, starting the router
+// if needed.
+func proxyRouterAdd(s *state.State, address string, projectName string, protocol string, key string, route *proxyRoute) error {
+	proxyRoutersMu.Lock()
+	defer proxyRoutersMu.Unlock()
+
+	router := proxyRouters[address]
+	if router == nil {
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			return fmt.Errorf("Failed to listen on %q: %w", address, err)
+		}
+
+		router = &proxyRouter{
+			state:    s,
+			project:  projectName,
+			protocol: protocol,
+			listener: listener,
+			routes:   map[string]*proxyRoute{},
+		}
+
+		router.start()
+		proxyRouters[address] = router
+	} else if router.project != projectName {
+		return fmt.Errorf("Listen address %q is already used by another project", address)
+	} else if router.protocol != protocol {
+		return fmt.Errorf("Listen address %q is already used with the %q protocol", address, router.protocol)
+	}
+
+	if protocol == "http" || protocol == "https" {
+		socket := route.socket
+		route.handler = &httputil.ReverseProxy{
+			Rewrite: func(r *httputil.ProxyRequest) {
+				r.SetXForwarded()
+				r.Out.URL.Scheme = "http"
+				r.Out.URL.Host = r.In.Host
+			},
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socket)
+				},
+			},
+		}
+	}
+
+	router.routesMu.Lock()
+	defer router.routesMu.Unlock()
+
+	for otherKey, other := range router.routes {
+		if otherKey == key {
+			continue
+		}
+
+		if len(route.hosts) == 0 && len(other.hosts) == 0 {
+			return fmt.Errorf("Listen address %q already has a proxy device without host names", address)
+		}
+
+		for _, host := range route.hosts {
+			if slices.Contains(other.hosts, host) {
+				return fmt.Errorf("Host name %q is already routed on listen address %q", host, address)
+			}
+		}
+	}
+
+	router.routes[key] = route
+
+	return nil
+}
+
+// proxyRouterRemove removes the route of a device from the router of the listen address, stopping the router when
+// no route is left.
+func proxyRouterRemove(address string, key string) {
+	proxyRoutersMu.Lock()
+	defer proxyRoutersMu.Unlock()
+
+	router := proxyRouters[address]
+	if router == nil {
+		return
+	}
+
+	router.routesMu.Lock()
+	delete(router.routes, key)
+	empty := len(router.routes) == 0
+	router.routesMu.Unlock()
+
+	if empty {
+		router.stop()
+		delete(proxyRouters, address)
+	}
+}
+
+// proxyRouterUpdateCertificate replaces the certificate of the route of a device.
+func proxyRouterUpdateCertificate(address string, key string, certificate *tls.Certificate) {
+	proxyRoutersMu.Lock()
+	router := proxyRouters[address]
+	proxyRoutersMu.Unlock()
+
+	if router == nil {
+		return
+	}
+
+	router.routesMu.Lock()
+	defer router.routesMu.Unlock()
+
+	route := router.routes[key]
+	if route != nil {
+		route.certificate = certificate
+	}
+}
+
+// ProxyRenewCertificates renews the ACME certificates of the proxy devices which expire within 30 days.
+func ProxyRenewCertificates(ctx context.Context) error {
+	type renewal struct {
+		address string
+		key     string
+		route   proxyRoute
+		state   *state.State
+	}
+
+	renewals := []renewal{}
+
+	proxyRoutersMu.Lock()
+	for address, router := range proxyRouters {
+		router.routesMu.RLock()
+		for key, route := range router.routes {
+			if route.acmeDomain != "" {
+				renewals = append(renewals, renewal{address: address, key: key, route: *route, state: router.state})
+			}
+		}
+
+		router.routesMu.RUnlock()
+	}
+
+	proxyRoutersMu.Unlock()
+
+	errs := []error{}
+	for _, renewal := range renewals {
+		certificate, err := proxyACMECertificate(ctx, renewal.state, renewal.route.acmeDomain, renewal.route.certPath, renewal.route.keyPath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed renewing certificate for %q: %w", renewal.route.acmeDomain, err))
+			continue
+		}
+
+		proxyRouterUpdateCertificate(renewal.address, renewal.key, certificate)
+	}
+
+	return errors.Join(errs...)
+}
+
+// proxyLoadCertificate loads a certificate and its key from the proxy certificates of the project.
+func proxyLoadCertificate(s *state.State, projectName string, name string) (*tls.Certificate, error) {
+	var record *db.ProxyCertificate
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		record, err = tx.GetProxyCertificate(ctx, projectName, name)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading proxy certificate %q: %w", name, err)
+	}
+
+	certificate, err := tls.X509KeyPair([]byte(record.Certificate), []byte(record.Key))
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing proxy certificate %q: %w", name, err)
+	}
+
+	return &certificate, nil
+}
+
+// proxyACMECertificate returns the certificate of the domain stored in the given files, issuing a new one through
+// ACME if it's missing or expires within 30 days.
+func proxyACMECertificate(ctx context.Context, s *state.State, domain string, certPath string, keyPath string) (*tls.Certificate, error) {
+	current, err := os.ReadFile(certPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	pair, err := acme.IssueCertificate(ctx, s, domain, current)
+	if err != nil {
+		return nil, err
+	}
+
+	if pair != nil {
+		err = os.WriteFile(keyPath, pair.PrivateKey, 0o600)
+		if err != nil {
+			return nil, err
+		}
+
+		err = os.WriteFile(certPath, pair.Certificate, 0o644)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	certificate, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+// start serves the connections accepted on the listener of the router.
+func (r *proxyRouter) start() {
+	listener := r.listener
+	if r.protocol == "tls" || r.protocol == "https" {
+		listener = tls.NewListener(listener, &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: r.getCertificate,
+		})
+	}
+
+	if r.protocol == "http" || r.protocol == "https" {
+		r.server = &http.Server{
+			Handler:           r,
+			ReadHeaderTimeout: 30 * time.Second,
+		}
+
+		go func() { _ = r.server.Serve(listener) }()
+
+		return
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if errors.Is(err, net.ErrClosed) {
+					return
+				}
+
+				logger.Warn("Failed to accept proxy connection", logger.Ctx{"address": r.listener.Addr().String(), "err": err})
+				continue
+			}
+
+			go r.relay(conn)
+		}
+	}()
+}
+
+// stop closes the listener of the router.
+func (r *proxyRouter) stop() {
+	if r.server != nil {
+		_ = r.server.Close()
+	}
+
+	_ = r.listener.Close()
+}
+
+// route returns the route of the host name, falling back to the catch-all route.
+func (r *proxyRouter) route(host string) *proxyRoute {
+	r.routesMu.RLock()
+	defer r.routesMu.RUnlock()
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	var wildcard *proxyRoute
+	var catchAll *proxyRoute
+
+	for _, route := range r.routes {
+		if len(route.hosts) == 0 {
+			catchAll = route
+			continue
+		}
+
+		for _, routeHost := range route.hosts {
+			if routeHost == host {
+				return route
+			}
+
+			suffix, ok := strings.CutPrefix(routeHost, "*")
+			if ok && strings.HasSuffix(host, suffix) && !strings.Contains(strings.TrimSuffix(host, suffix), ".") {
+				wildcard = route
+			}
+		}
+	}
+
+	if wildcard != nil {
+		return wildcard
+	}
+
+	return catchAll
+}
+
+// getCertificate returns the certificate of the route of the host name requested by the client.
+func (r *proxyRouter) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	route := r.route(hello.ServerName)
+	if route == nil || route.certificate == nil {
+		return nil, fmt.Errorf("No certificate for %q", hello.ServerName)
+	}
+
+	return route.certificate, nil
+}
+
+// relay relays a decrypted TLS connection to the device routed by its server name.
+func (r *proxyRouter) relay(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	err := tlsConn.HandshakeContext(ctx)
+	cancel()
+	if err != nil {
+		return
+	}
+
+	route := r.route(tlsConn.ConnectionState().ServerName)
+	if route == nil {
+		return
+	}
+
+	target, err := net.Dial("unix", route.socket)
+	if err != nil {
+		logger.Warn("Failed to connect to proxy device", logger.Ctx{"socket": route.socket, "err": err})
+		return
+	}
+
+	defer func() { _ = target.Close() }()
+
+	if route.proxyProtocol {
+		_, err = target.Write([]byte(proxyProtocolHeader(conn.RemoteAddr(), conn.LocalAddr())))
+		if err != nil {
+			return
+		}
+	}
+
+	done := make(chan struct{}, 2)
+
+	go func() {
+		_, _ = io.Copy(target, tlsConn)
+
+		unixConn, ok := target.(*net.UnixConn)
+		if ok {
+			_ = unixConn.CloseWrite()
+		}
+
+		done <- struct{}{}
+	}()
+
+	go func() {
+		_, _ = io.Copy(tlsConn, target)
+		_ = tlsConn.CloseWrite()
+		done <- struct{}{}
+	}()
+
+	<-done
+	<-done
+}
+
+// ServeHTTP relays a request to the device routed by its host name.
+func (r *proxyRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// Let the ACME HTTP-01 challenges of the proxy certificates go through.
+	if r.protocol == "http" && strings.HasPrefix(req.URL.Path, "/.well-known/acme-challenge/") {
+		r.serveACMEChallenge(w, req)
+		return
+	}
+
+	host, _, err := net.SplitHostPort(req.Host)
+	if err != nil {
+		host = req.Host
+	}
+
+	route := r.route(host)
+	if route == nil || route.handler == nil {
+		http.Error(w, "Unknown host", http.StatusNotFound)
+		return
+	}
+
+	route.handler.ServeHTTP(w, req)
+}
+
+// serveACMEChallenge relays an ACME HTTP-01 challenge request to the listener of lego.
+func (r *proxyRouter) serveACMEChallenge(w http.ResponseWriter, req *http.Request) {
+	addr := r.state.GlobalConfig.ACMEHTTP()
+	if strings.HasPrefix(addr, ":") {
+		addr = "127.0.0.1" + addr
+	}
+
+	// Don't relay the challenges to ourselves if lego is configured to listen on the same port.
+	_, port, _ := net.SplitHostPort(addr)
+	_, listenPort, _ := net.SplitHostPort(r.listener.Addr().String())
+	if port == listenPort {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.Out.URL.Scheme = "http"
+			r.Out.URL.Host = addr
+			r.Out.Host = r.In.Host
+		},
+	}
+
+	proxy.ServeHTTP(w, req)
+}
+
+// proxyProtocolHeader returns the HAProxy PROXY protocol header of a connection.
+func proxyProtocolHeader(remote net.Addr, local net.Addr) string {
+	cHost, cPort, err := net.SplitHostPort(remote.String())
+	if err != nil {
+		return "PROXY UNKNOWN\r\n"
+	}
+
+	dHost, dPort, err := net.SplitHostPort(local.String())
+	if err != nil {
+		return "PROXY UNKNOWN\r\n"
+	}
+
+	proto := "TCP4"
+	if strings.Contains(cHost, ":") {
+		proto = "TCP6"
+	}
+
+	return fmt.Sprintf("PROXY %s %s %s %s %s\r\n", proto, cHost, dHost, cPort, dPort)
+}
@@ -0,0 +1,135 @@
+package device
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_proxyRouterRoute(t *testing.T) {
+	exact := &proxyRoute{hosts: []string{"www.example.com", "example.com"}, certificate: &tls.Certificate{}}
+	wildcard := &proxyRoute{hosts: []string{"*.example.com"}, certificate: &tls.Certificate{}}
+	catchAll := &proxyRoute{certificate: &tls.Certificate{}}
+
+	router := &proxyRouter{
+		protocol: "https",
+		routes: map[string]*proxyRoute{
+			"c1/https": exact,
+			"c2/https": wildcard,
+			"c3/https": catchAll,
+		},
+	}
+
+	tests := []struct {
+		host string
+		want *proxyRoute
+	}{
+		{host: "www.example.com", want: exact},
+		{host: "WWW.Example.com.", want: exact},
+		{host: "example.com", want: exact},
+		{host: "blog.example.com", want: wildcard},
+		{host: "a.blog.example.com", want: catchAll},
+		{host: "example.org", want: catchAll},
+		{host: "", want: catchAll},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			assert.Same(t, tt.want, router.route(tt.host))
+
+			// TLS connections are routed by the requested server name (SNI).
+			certificate, err := router.getCertificate(&tls.ClientHelloInfo{ServerName: tt.host})
+			require.NoError(t, err)
+			assert.Same(t, tt.want.certificate, certificate)
+		})
+	}
+
+	delete(router.routes, "c3/https")
+	assert.Nil(t, router.route("example.org"))
+
+	_, err := router.getCertificate(&tls.ClientHelloInfo{ServerName: "example.org"})
+	assert.Error(t, err)
+}
+
+func Test_proxyRouterAdd(t *testing.T) {
+	address := "127.0.0.1:0"
+
+	err := proxyRouterAdd(nil, address, "default", "http", "c1/http", &proxyRoute{hosts: []string{"www.example.com"}})
+	require.NoError(t, err)
+
+	defer proxyRouterRemove(address, "c1/http")
+
+	tests := []struct {
+		name     string
+		project  string
+		protocol string
+		route    *proxyRoute
+		wantErr  bool
+	}{
+		{name: "Different project", project: "p1", protocol: "http", route: &proxyRoute{hosts: []string{"blog.example.com"}}, wantErr: true},
+		{name: "Different project wildcard", project: "p1", protocol: "http", route: &proxyRoute{hosts: []string{"*.example.com"}}, wantErr: true},
+		{name: "Different protocol", project: "default", protocol: "https", route: &proxyRoute{hosts: []string{"blog.example.com"}}, wantErr: true},
+		{name: "Host already routed", project: "default", protocol: "http", route: &proxyRoute{hosts: []string{"blog.example.com", "www.example.com"}}, wantErr: true},
+		{name: "Distinct host", project: "default", protocol: "http", route: &proxyRoute{hosts: []string{"blog.example.com"}}},
+		{name: "Catch-all", project: "default", protocol: "http", route: &proxyRoute{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := proxyRouterAdd(nil, address, tt.project, tt.protocol, "c2/http", tt.route)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+
+	// A second catch-all route is refused.
+	err = proxyRouterAdd(nil, address, "default", "http", "c3/http", &proxyRoute{})
+	assert.Error(t, err)
+
+	proxyRouterRemove(address, "c2/http")
+	assert.NotNil(t, proxyRouters[address])
+
+	proxyRouterRemove(address, "c1/http")
+	assert.Nil(t, proxyRouters[address])
+}
+
+func Test_proxyValidHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		wantErr bool
+	}{
+		{host: "example.com"},
+		{host: "*.example.com"},
+		{host: "xn--bcher-kva.example"},
+		{host: "www.*.example.com", wantErr: true},
+		{host: "-www.example.com", wantErr: true},
+		{host: "www..example.com", wantErr: true},
+		{host: "www_1.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := proxyValidHost(tt.host)
+		if tt.wantErr {
+			assert.Error(t, err, tt.host)
+		} else {
+			assert.NoError(t, err, tt.host)
+		}
+	}
+}
+
+func Test_proxyProtocolHeader(t *testing.T) {
+	remote := &net.TCPAddr{IP: net.ParseIP("192.0.2.10"), Port: 51000}
+	local := &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 443}
+	assert.Equal(t, "PROXY TCP4 192.0.2.10 192.0.2.1 51000 443\r\n", proxyProtocolHeader(remote, local))
+
+	remote = &net.TCPAddr{IP: net.ParseIP("2001:db8::10"), Port: 51000}
+	local = &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 443}
+	assert.Equal(t, "PROXY TCP6 2001:db8::10 2001:db8::1 51000 443\r\n", proxyProtocolHeader(remote, local))
+}
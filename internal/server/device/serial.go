@@ -0,0 +1,413 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/lxc/incus/v6/internal/linux"
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/termios"
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// serialRelays tracks the listeners relaying the container ttys, keyed by instance and device name.
+var (
+	serialRelays   = map[string]net.Listener{}
+	serialRelaysMu sync.Mutex
+)
+
+type serial struct {
+	deviceCommon
+}
+
+// CanHotPlug returns whether the device can be managed whilst the instance is running.
+func (d *serial) CanHotPlug() bool {
+	return false
+}
+
+// CanMigrate returns whether the device can be migrated to any other cluster member.
+func (d *serial) CanMigrate() bool {
+	return true
+}
+
+// validateConfig checks the supplied config for correctness.
+func (d *serial) validateConfig(instConf instance.ConfigReader) error {
+	if !instanceSupported(instConf.Type(), instancetype.Container, instancetype.VM) {
+		return ErrUnsupportedDevType
+	}
+
+	rules := map[string]func(string) error{
+		// gendoc:generate(entity=devices, group=serial, key=listen)
+		//
+		// ---
+		//  type: string
+		//  required: for containers
+		//  shortdesc: Host address to expose the serial port on (`unix` or `tcp:<address>:<port>`), the output is kept in a ring buffer if unset
+		"listen": validate.Optional(validateSerialListen),
+
+		// gendoc:generate(entity=devices, group=serial, key=security.listen_remote)
+		//
+		// ---
+		//  type: bool
+		//  default: `false`
+		//  required: no
+		//  shortdesc: Whether `listen` can expose the serial port on addresses other than loopback ones
+		"security.listen_remote": validate.Optional(validate.IsBool),
+	}
+
+	if instConf.Type() == instancetype.Container {
+		rules["listen"] = validateSerialListen
+	} else {
+		// gendoc:generate(entity=devices, group=serial, key=bus)
+		//
+		// ---
+		//  type: string
+		//  default: `virtio`
+		//  required: no
+		//  shortdesc: Only for VMs: Type of serial port to create (`virtio` for a virtio console, `isa` for a 16550 UART on x86_64)
+		rules["bus"] = validate.Optional(validate.IsOneOf("virtio", "isa"))
+
+		// gendoc:generate(entity=devices, group=serial, key=name)
+		//
+		// ---
+		//  type: string
+		//  required: no
+		//  shortdesc: Only for VMs: Name of the virtio port (exposed in `/dev/virtio-ports/` in the instance)
+		rules["name"] = validate.IsAny
+	}
+
+	err := d.config.Validate(rules)
+	if err != nil {
+		return fmt.Errorf("Failed to validate config: %w", err)
+	}
+
+	if d.config["name"] != "" && d.config["bus"] == "isa" {
+		return errors.New("The name option can only be used with the virtio bus")
+	}
+
+	// Connecting to the TCP port gives access to the serial port without any authentication.
+	if d.config["listen"] != "" && !serialListenLoopback(d.config["listen"]) && !util.IsTrue(d.config["security.listen_remote"]) {
+		return errors.New(`Listening on addresses other than loopback ones requires "security.listen_remote" to be enabled`)
+	}
+
+	return nil
+}
+
+// validateSerialListen validates a serial device listen address.
+// Unix sockets are always created in the instance's devices directory, so no path can be provided.
+func validateSerialListen(value string) error {
+	if value == "unix" {
+		return nil
+	}
+
+	listenType, listenAddr, _ := strings.Cut(value, ":")
+
+	switch listenType {
+	case "unix":
+		return errors.New("The unix socket path can't be set (the socket is created in the instance's devices directory)")
+	case "tcp":
+		host, port, err := net.SplitHostPort(listenAddr)
+		if err != nil {
+			return err
+		}
+
+		if host != "" {
+			err = validate.IsNetworkAddress(host)
+			if err != nil {
+				return err
+			}
+		}
+
+		return validate.IsNetworkPort(port)
+	}
+
+	return fmt.Errorf("Invalid listen address %q (must be `unix` or start with `tcp:`)", value)
+}
+
+// serialListenLoopback returns whether a valid serial device listen address only accepts local connections.
+func serialListenLoopback(value string) bool {
+	listenType, listenAddr, _ := strings.Cut(value, ":")
+	if listenType != "tcp" {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(listenAddr)
+	if err != nil {
+		return false
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// SerialSocketRemove removes a leftover serial device socket, refusing to remove anything which isn't a socket.
+func SerialSocketRemove(path string) error {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	if fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("Refusing to remove %q as it isn't a socket", path)
+	}
+
+	return os.Remove(path)
+}
+
+// Start is run when the device is added to the instance.
+func (d *serial) Start() (*deviceConfig.RunConfig, error) {
+	runConf := deviceConfig.RunConfig{}
+
+	if d.inst.Type() == instancetype.VM {
+		listen, err := d.listenAddress()
+		if err != nil {
+			return nil, err
+		}
+
+		runConf.SerialDevice = []deviceConfig.RunConfigItem{
+			{Key: "devName", Value: d.name},
+			{Key: "bus", Value: d.config["bus"]},
+			{Key: "name", Value: d.config["name"]},
+			{Key: "listen", Value: listen},
+		}
+
+		return &runConf, nil
+	}
+
+	// Relay the container tty once the container is running.
+	runConf.PostHooks = []func() error{d.startRelay}
+
+	return &runConf, nil
+}
+
+// Register restores the relay of the container tty after a restart of the daemon.
+func (d *serial) Register() error {
+	if d.inst.Type() != instancetype.Container {
+		return nil
+	}
+
+	return d.startRelay()
+}
+
+// Stop is run when the device is removed from the instance.
+func (d *serial) Stop() (*deviceConfig.RunConfig, error) {
+	runConf := deviceConfig.RunConfig{}
+
+	if d.inst.Type() == instancetype.Container {
+		d.stopRelay()
+	}
+
+	// Remove the socket once nothing listens on it anymore.
+	if d.config["listen"] == "unix" {
+		err := SerialSocketRemove(d.socketPath())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &runConf, nil
+}
+
+// socketPath returns the path of the unix socket exposing the serial port.
+func (d *serial) socketPath() string {
+	return filepath.Join(d.inst.DevicesPath(), fmt.Sprintf("serial.%s.sock", linux.PathNameEncode(d.name)))
+}
+
+// listenAddress returns the address to listen on, creating the devices directory holding the unix socket.
+func (d *serial) listenAddress() (string, error) {
+	if d.config["listen"] != "unix" {
+		return d.config["listen"], nil
+	}
+
+	err := os.MkdirAll(d.inst.DevicesPath(), 0o711)
+	if err != nil {
+		return "", err
+	}
+
+	return "unix:" + d.socketPath(), nil
+}
+
+// relayKey returns the key identifying the relay of the device.
+func (d *serial) relayKey() string {
+	return fmt.Sprintf("%s/%s", project.Instance(d.inst.Project().Name, d.inst.Name()), d.name)
+}
+
+// ttyNumber returns the container tty used by the device.
+// Each serial device gets its own tty, numbered in the order of the device names.
+func (d *serial) ttyNumber() int {
+	names := []string{}
+	for name, dev := range d.inst.ExpandedDevices() {
+		if dev["type"] == "serial" {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return slices.Index(names, d.name) + 1
+}
+
+// startRelay starts listening on the host and relays each connection to the container tty.
+func (d *serial) startRelay() error {
+	serialRelaysMu.Lock()
+	defer serialRelaysMu.Unlock()
+
+	key := d.relayKey()
+	_, ok := serialRelays[key]
+	if ok {
+		return nil
+	}
+
+	listen, err := d.listenAddress()
+	if err != nil {
+		return err
+	}
+
+	listenType, listenAddr, _ := strings.Cut(listen, ":")
+	if listenType == "unix" {
+		// Remove any leftover socket.
+		err = SerialSocketRemove(listenAddr)
+		if err != nil {
+			return err
+		}
+	}
+
+	listener, err := net.Listen(listenType, listenAddr)
+	if err != nil {
+		return fmt.Errorf("Failed listening on %q: %w", listen, err)
+	}
+
+	if listenType == "unix" {
+		// Only root can connect to the socket.
+		err = os.Chmod(listenAddr, 0o600)
+		if err != nil {
+			_ = listener.Close()
+			return err
+		}
+	}
+
+	serialRelays[key] = listener
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				err := d.relay(conn)
+				if err != nil {
+					d.logger.Warn("Failed relaying serial connection", logger.Ctx{"err": err})
+				}
+			}()
+		}
+	}()
+
+	return nil
+}
+
+// stopRelay stops listening on the host.
+func (d *serial) stopRelay() {
+	serialRelaysMu.Lock()
+	defer serialRelaysMu.Unlock()
+
+	key := d.relayKey()
+	listener, ok := serialRelays[key]
+	if !ok {
+		return
+	}
+
+	_ = listener.Close()
+	delete(serialRelays, key)
+}
+
+// relay attaches a connection to the container tty until either side goes away.
+func (d *serial) relay(conn net.Conn) error {
+	defer func() { _ = conn.Close() }()
+
+	c, ok := d.inst.(instance.Container)
+	if !ok {
+		return errors.New("Instance isn't a container")
+	}
+
+	idmapset, err := c.CurrentIdmap()
+	if err != nil {
+		return err
+	}
+
+	var rootUID, rootGID int64
+	if idmapset != nil {
+		rootUID, rootGID = idmapset.ShiftIntoNS(0, 0)
+	}
+
+	// Create a PTS pair for the console process.
+	ptx, pty, err := linux.OpenPty(rootUID, rootGID)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = ptx.Close() }()
+
+	_, err = termios.MakeRaw(int(ptx.Fd()))
+	if err != nil {
+		_ = pty.Close()
+		return err
+	}
+
+	cmd := exec.Command(
+		d.state.OS.ExecPath,
+		"forkconsole",
+		project.Instance(d.inst.Project().Name, d.inst.Name()),
+		d.state.OS.LxcPath,
+		filepath.Join(d.inst.RunPath(), "lxc.conf"),
+		fmt.Sprintf("tty=%d", d.ttyNumber()),
+		"escape=-1")
+	cmd.Stdin = pty
+	cmd.Stdout = pty
+	cmd.Stderr = pty
+
+	err = cmd.Start()
+	_ = pty.Close()
+	if err != nil {
+		return err
+	}
+
+	// Copy the data until either side is closed.
+	done := make(chan struct{}, 2)
+
+	go func() {
+		_, _ = io.Copy(ptx, conn)
+		done <- struct{}{}
+	}()
+
+	go func() {
+		_, _ = io.Copy(conn, ptx)
+		done <- struct{}{}
+	}()
+
+	<-done
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package device
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateSerialListen(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{value: "unix"},
+		{value: "tcp:127.0.0.1:5000"},
+		{value: "tcp:[::1]:5000"},
+		{value: "tcp::5000"},
+		{value: "unix:/etc/passwd", wantErr: true},
+		{value: "unix:", wantErr: true},
+		{value: "tcp:127.0.0.1", wantErr: true},
+		{value: "tcp:127.0.0.1:99999", wantErr: true},
+		{value: "udp:127.0.0.1:5000", wantErr: true},
+		{value: "/run/serial.sock", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			err := validateSerialListen(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_serialListenLoopback(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{value: "unix", want: true},
+		{value: "tcp:127.0.0.1:5000", want: true},
+		{value: "tcp:127.0.0.2:5000", want: true},
+		{value: "tcp:[::1]:5000", want: true},
+		{value: "tcp::5000", want: false},
+		{value: "tcp:0.0.0.0:5000", want: false},
+		{value: "tcp:[::]:5000", want: false},
+		{value: "tcp:10.0.0.1:5000", want: false},
+		{value: "tcp:[2001:db8::1]:5000", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, serialListenLoopback(tt.value))
+		})
+	}
+}
+
+func Test_SerialSocketRemove(t *testing.T) {
+	dir := t.TempDir()
+
+	// Missing paths are ignored.
+	assert.NoError(t, SerialSocketRemove(filepath.Join(dir, "missing.sock")))
+
+	// Regular files are kept.
+	filePath := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(filePath, []byte("data"), 0o600))
+	assert.Error(t, SerialSocketRemove(filePath))
+	assert.FileExists(t, filePath)
+
+	// Symlinks to sockets are kept.
+	sockPath := filepath.Join(dir, "serial.sock")
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: sockPath, Net: "unix"})
+	require.NoError(t, err)
+
+	listener.SetUnlinkOnClose(false)
+	require.NoError(t, listener.Close())
+
+	linkPath := filepath.Join(dir, "link.sock")
+	require.NoError(t, os.Symlink(sockPath, linkPath))
+	assert.Error(t, SerialSocketRemove(linkPath))
+
+	// Sockets are removed.
+	assert.NoError(t, SerialSocketRemove(sockPath))
+	assert.NoFileExists(t, sockPath)
+}
@@ -0,0 +1,98 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/shared/osarch"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+type watchdog struct {
+	deviceCommon
+}
+
+// CanHotPlug returns whether the device can be managed whilst the instance is running.
+func (d *watchdog) CanHotPlug() bool {
+	return false
+}
+
+// CanMigrate returns whether the device can be migrated to any other cluster member.
+func (d *watchdog) CanMigrate() bool {
+	return true
+}
+
+// validateConfig checks the supplied config for correctness.
+func (d *watchdog) validateConfig(instConf instance.ConfigReader) error {
+	if !instanceSupported(instConf.Type(), instancetype.VM) {
+		return ErrUnsupportedDevType
+	}
+
+	rules := map[string]func(string) error{
+		// gendoc:generate(entity=devices, group=watchdog, key=model)
+		//
+		// ---
+		//  type: string
+		//  default: `i6300esb` (`diag288` on `s390x`)
+		//  required: no
+		//  shortdesc: Watchdog device to emulate (`i6300esb`, `ib700` or `diag288`)
+		"model": validate.Optional(validate.IsOneOf("i6300esb", "ib700", "diag288")),
+
+		// gendoc:generate(entity=devices, group=watchdog, key=action)
+		//
+		// ---
+		//  type: string
+		//  default: `reset`
+		//  required: no
+		//  shortdesc: Action to take when the watchdog fires (`reset`, `poweroff`, `pause`, `dump` or `none`)
+		"action": validate.Optional(validate.IsOneOf("reset", "poweroff", "pause", "dump", "none")),
+	}
+
+	err := d.config.Validate(rules)
+	if err != nil {
+		return fmt.Errorf("Failed to validate config: %w", err)
+	}
+
+	model := d.config["model"]
+	if instConf.Architecture() == osarch.ARCH_64BIT_S390_BIG_ENDIAN {
+		if model != "" && model != "diag288" {
+			return errors.New("Only the diag288 watchdog model is supported on s390x")
+		}
+	} else if model == "diag288" {
+		return errors.New("The diag288 watchdog model is only supported on s390x")
+	} else if model == "ib700" && instConf.Architecture() != osarch.ARCH_64BIT_INTEL_X86 {
+		return errors.New("The ib700 watchdog model is only supported on x86_64")
+	}
+
+	// QEMU only supports a single watchdog per VM.
+	for name, dev := range instConf.ExpandedDevices() {
+		if name != d.name && dev["type"] == "watchdog" {
+			return errors.New("Only one watchdog device can be added to an instance")
+		}
+	}
+
+	return nil
+}
+
+// Start is run when the device is added to the instance.
+func (d *watchdog) Start() (*deviceConfig.RunConfig, error) {
+	runConf := deviceConfig.RunConfig{}
+
+	runConf.WatchdogDevice = []deviceConfig.RunConfigItem{
+		{Key: "devName", Value: d.name},
+		{Key: "model", Value: d.config["model"]},
+		{Key: "action", Value: d.config["action"]},
+	}
+
+	return &runConf, nil
+}
+
+// Stop is run when the device is removed from the instance.
+func (d *watchdog) Stop() (*deviceConfig.RunConfig, error) {
+	runConf := deviceConfig.RunConfig{}
+
+	return &runConf, nil
+}
@@ -3,6 +3,7 @@ package dns
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"strings"
 	"time"
 
@@ -45,18 +46,6 @@ func (d dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
-	// Check that it's a supported request type.
-	if r.Question[0].Qtype != dns.TypeAXFR && r.Question[0].Qtype != dns.TypeIXFR && r.Question[0].Qtype != dns.TypeSOA {
-		m := &dns.Msg{}
-		m.SetRcode(r, dns.RcodeNotImplemented)
-		err := w.WriteMsg(m)
-		if err != nil {
-			logger.Error("Unable to write message", logger.Ctx{"err": err})
-		}
-
-		return
-	}
-
 	// Extract the request information.
 	name := strings.TrimSuffix(r.Question[0].Name, ".")
 	ip, _, err := net.SplitHostPort(w.RemoteAddr().String())
@@ -76,55 +65,94 @@ func (d dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	m.SetReply(r)
 	m.Authoritative = true
 
-	// Load the zone.
-	zone, err := d.server.zoneRetriever(name, r.Question[0].Qtype != dns.TypeSOA)
-	if err != nil {
-		// On failure, return NXDOMAIN.
-		m := &dns.Msg{}
-		m.SetRcode(r, dns.RcodeNameError)
-		err := w.WriteMsg(m)
-		if err != nil {
-			logger.Error("Unable to write message", logger.Ctx{"err": err})
-		}
+	qtype := r.Question[0].Qtype
+	if qtype == dns.TypeAXFR || qtype == dns.TypeIXFR {
+		// Zone transfers are only allowed to the zone peers.
+		zone, records, err := d.server.getZone(name, true)
+		if zone == nil || !isAllowed(zone.Info, ip, r.IsTsig(), w.TsigStatus() == nil) {
+			// On failure, return NXDOMAIN to avoid information leaks.
+			m := &dns.Msg{}
+			m.SetRcode(r, dns.RcodeNameError)
+			err := w.WriteMsg(m)
+			if err != nil {
+				logger.Error("Unable to write message", logger.Ctx{"err": err})
+			}
 
-		return
-	}
+			return
+		}
 
-	// Check access.
-	if !isAllowed(zone.Info, ip, r.IsTsig(), w.TsigStatus() == nil) {
-		// On auth failure, return NXDOMAIN to avoid information leaks.
-		m := &dns.Msg{}
-		m.SetRcode(r, dns.RcodeNameError)
-		err := w.WriteMsg(m)
 		if err != nil {
-			logger.Error("Unable to write message", logger.Ctx{"err": err})
+			logger.Errorf("Bad DNS record in zone %q: %v", name, err)
+
+			m := &dns.Msg{}
+			m.SetRcode(r, dns.RcodeFormatError)
+			err := w.WriteMsg(m)
+			if err != nil {
+				logger.Error("Unable to write message", logger.Ctx{"err": err})
+			}
+
+			return
 		}
 
-		return
-	}
+		m.Answer = records
+	} else {
+		// Regular queries are answered from the most specific zone containing the name.
+		zone, records, err := d.findZone(name, qtype)
+		if zone == nil && err != nil {
+			logger.Error("Failed looking up DNS zone", logger.Ctx{"name": name, "err": err})
 
-	zoneRR := dns.NewZoneParser(strings.NewReader(zone.Content), "", "")
-	for {
-		rr, ok := zoneRR.Next()
-		if !ok {
-			err := zoneRR.Err()
+			m := &dns.Msg{}
+			m.SetRcode(r, dns.RcodeServerFailure)
+			err := w.WriteMsg(m)
 			if err != nil {
-				logger.Errorf("Bad DNS record in zone %q: %v", name, err)
+				logger.Error("Unable to write message", logger.Ctx{"err": err})
+			}
 
-				m := &dns.Msg{}
-				m.SetRcode(r, dns.RcodeFormatError)
-				err := w.WriteMsg(m)
-				if err != nil {
-					logger.Error("Unable to write message", logger.Ctx{"err": err})
-				}
+			return
+		}
 
-				return
+		if zone == nil || (!isAllowed(zone.Info, ip, r.IsTsig(), w.TsigStatus() == nil) && !isQueryAllowed(zone.Info, ip)) {
+			// Refuse queries for unknown zones or from clients which aren't allowed to query the zone.
+			m := &dns.Msg{}
+			m.SetRcode(r, dns.RcodeRefused)
+			err := w.WriteMsg(m)
+			if err != nil {
+				logger.Error("Unable to write message", logger.Ctx{"err": err})
+			}
+
+			return
+		}
+
+		if err != nil {
+			logger.Errorf("Bad DNS record in zone %q: %v", zone.Info.Name, err)
+
+			m := &dns.Msg{}
+			m.SetRcode(r, dns.RcodeServerFailure)
+			err := w.WriteMsg(m)
+			if err != nil {
+				logger.Error("Unable to write message", logger.Ctx{"err": err})
 			}
 
-			break
+			return
 		}
 
-		m.Answer = append(m.Answer, rr)
+		// Include the DNSSEC records if requested by the client.
+		opt := r.IsEdns0()
+		dnssec := opt != nil && opt.Do()
+
+		m.Answer, m.Ns, m.Rcode = answerQuery(zone.Info.Name, records, r.Question[0].Name, qtype, dnssec)
+
+		// Advertise EDNS support back and truncate large UDP responses so the client retries over TCP.
+		size := dns.MinMsgSize
+		if opt != nil {
+			size = int(max(opt.UDPSize(), dns.MinMsgSize))
+			m.SetEdns0(uint16(size), dnssec)
+		}
+
+		_, isUDP := w.LocalAddr().(*net.UDPAddr)
+		if isUDP {
+			m.Truncate(size)
+		}
 	}
 
 	tsig := r.IsTsig()
@@ -138,6 +166,26 @@ func (d dnsHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	}
 }
 
+// findZone returns the most specific zone containing the name (or nil if none) along with its records.
+// Only the SOA record is rendered when it is requested for the zone itself.
+// Failing to look up a zone is returned rather than falling back to its parent, which could be served by another
+// project.
+func (d dnsHandler) findZone(name string, qtype uint16) (*Zone, []dns.RR, error) {
+	labels := dns.SplitDomainName(name)
+	for i := range labels {
+		zone, records, err := d.server.getZone(strings.Join(labels[i:], "."), i > 0 || qtype != dns.TypeSOA)
+		if zone != nil {
+			return zone, records, err
+		}
+
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, nil, err
+		}
+	}
+
+	return nil, nil, nil
+}
+
 func isAllowed(zone api.NetworkZone, ip string, tsig *dns.TSIG, tsigStatus bool) bool {
 	type peer struct {
 		address string
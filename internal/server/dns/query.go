@@ -0,0 +1,245 @@
+package dns
+
+import (
+	"net"
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// maxCNAMEChain is the maximum number of CNAME records followed within a zone.
+const maxCNAMEChain = 8
+
+// zoneRecords parses the zone content into a list of records.
+func zoneRecords(content string) ([]dns.RR, error) {
+	records := []dns.RR{}
+
+	zoneRR := dns.NewZoneParser(strings.NewReader(content), "", "")
+	for {
+		rr, ok := zoneRR.Next()
+		if !ok {
+			err := zoneRR.Err()
+			if err != nil {
+				return nil, err
+			}
+
+			break
+		}
+
+		records = append(records, rr)
+	}
+
+	return records, nil
+}
+
+// answerQuery looks up the records matching the query in the zone records.
+// It follows CNAME records within the zone and returns the answer and authority sections along with the response code.
+// When dnssec is set, the signatures and the proofs of non-existence are included.
+func answerQuery(zoneName string, records []dns.RR, qname string, qtype uint16, dnssec bool) ([]dns.RR, []dns.RR, int) {
+	zoneName = dns.CanonicalName(zoneName)
+	qname = dns.CanonicalName(qname)
+
+	answer := []dns.RR{}
+
+	for range maxCNAMEChain {
+		matches, cname := lookupName(records, qname, qtype, dnssec)
+		if len(matches) > 0 {
+			return append(answer, matches...), nil, dns.RcodeSuccess
+		}
+
+		if cname == nil {
+			break
+		}
+
+		// Follow the alias if it points within the zone.
+		answer = append(answer, cname...)
+		qname = dns.CanonicalName(cname[0].(*dns.CNAME).Target)
+		if !dns.IsSubDomain(zoneName, qname) {
+			return answer, nil, dns.RcodeSuccess
+		}
+	}
+
+	// Add the SOA record to negative answers.
+	authority := []dns.RR{}
+	for _, rr := range records {
+		if rr.Header().Rrtype == dns.TypeSOA && dns.CanonicalName(rr.Header().Name) == zoneName {
+			authority = append(authority, rr)
+			break
+		}
+	}
+
+	if dnssec {
+		authority = append(authority, signatures(records, zoneName, dns.TypeSOA)...)
+	}
+
+	if nameExists(records, qname) {
+		// The name exists but doesn't have records of the requested type.
+		if dnssec {
+			authority = append(authority, denial(records, qname, true)...)
+		}
+
+		return answer, authority, dns.RcodeSuccess
+	}
+
+	if dnssec {
+		// Prove that neither the name nor a wildcard at its closest encloser exist.
+		authority = append(authority, denial(records, qname, false)...)
+
+		encloser := qname
+		for encloser != zoneName && !nameExists(records, encloser) {
+			_, parent, _ := strings.Cut(encloser, ".")
+			encloser = dns.CanonicalName(parent)
+		}
+
+		for _, rr := range denial(records, "*."+encloser, false) {
+			if !containsRecord(authority, rr) {
+				authority = append(authority, rr)
+			}
+		}
+	}
+
+	return answer, authority, dns.RcodeNameError
+}
+
+// lookupName returns the records of the given type at the name, or the CNAME record of the name if any.
+// When dnssec is set, the matching signatures are included.
+func lookupName(records []dns.RR, name string, qtype uint16, dnssec bool) ([]dns.RR, []dns.RR) {
+	matches := []dns.RR{}
+	var cname []dns.RR
+
+	for _, rr := range records {
+		if dns.CanonicalName(rr.Header().Name) != name {
+			continue
+		}
+
+		// The SOA record is repeated at the end of the zone.
+		if rr.Header().Rrtype == dns.TypeSOA && containsRecord(matches, rr) {
+			continue
+		}
+
+		if rr.Header().Rrtype == qtype || qtype == dns.TypeANY {
+			matches = append(matches, rr)
+		} else if rr.Header().Rrtype == dns.TypeCNAME {
+			cname = []dns.RR{rr}
+		}
+	}
+
+	if !dnssec || qtype == dns.TypeANY || qtype == dns.TypeRRSIG {
+		return matches, cname
+	}
+
+	if len(matches) > 0 {
+		matches = append(matches, signatures(records, name, qtype)...)
+	}
+
+	if cname != nil {
+		cname = append(cname, signatures(records, name, dns.TypeCNAME)...)
+	}
+
+	return matches, cname
+}
+
+// nameExists checks whether there are records at or below the name.
+func nameExists(records []dns.RR, name string) bool {
+	for _, rr := range records {
+		if dns.IsSubDomain(name, dns.CanonicalName(rr.Header().Name)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// signatures returns the RRSIG records covering the given record set.
+func signatures(records []dns.RR, name string, rrtype uint16) []dns.RR {
+	rrsigs := []dns.RR{}
+	for _, rr := range records {
+		rrsig, ok := rr.(*dns.RRSIG)
+		if ok && rrsig.TypeCovered == rrtype && dns.CanonicalName(rrsig.Hdr.Name) == name {
+			rrsigs = append(rrsigs, rrsig)
+		}
+	}
+
+	return rrsigs
+}
+
+// denial returns the NSEC record (and its signatures) matching the name if exact is set, or covering it otherwise.
+func denial(records []dns.RR, name string, exact bool) []dns.RR {
+	for _, rr := range records {
+		nsec, ok := rr.(*dns.NSEC)
+		if !ok {
+			continue
+		}
+
+		owner := dns.CanonicalName(nsec.Hdr.Name)
+		next := dns.CanonicalName(nsec.NextDomain)
+
+		if exact && owner != name {
+			continue
+		}
+
+		// The last record of the chain points back to the zone apex.
+		if !exact && (CompareNames(owner, name) >= 0 || (CompareNames(name, next) >= 0 && CompareNames(next, owner) > 0)) {
+			continue
+		}
+
+		return append([]dns.RR{nsec}, signatures(records, owner, dns.TypeNSEC)...)
+	}
+
+	// Empty non-terminals don't have their own record and are covered by the previous one.
+	if exact {
+		return denial(records, name, false)
+	}
+
+	return nil
+}
+
+// CompareNames compares two domain names in canonical DNS order (RFC 4034 section 6.1).
+func CompareNames(a string, b string) int {
+	labelsA := dns.SplitDomainName(dns.CanonicalName(a))
+	labelsB := dns.SplitDomainName(dns.CanonicalName(b))
+
+	for i := 1; i <= len(labelsA) && i <= len(labelsB); i++ {
+		cmp := strings.Compare(labelsA[len(labelsA)-i], labelsB[len(labelsB)-i])
+		if cmp != 0 {
+			return cmp
+		}
+	}
+
+	return len(labelsA) - len(labelsB)
+}
+
+// containsRecord checks whether an identical record is already in the list.
+func containsRecord(records []dns.RR, record dns.RR) bool {
+	for _, rr := range records {
+		if dns.IsDuplicate(rr, record) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isQueryAllowed checks whether the client is allowed to send regular queries for the zone.
+func isQueryAllowed(zone api.NetworkZone, ip string) bool {
+	clientIP := net.ParseIP(ip)
+	if clientIP == nil {
+		return false
+	}
+
+	for _, subnet := range util.SplitNTrimSpace(zone.Config["dns.query.subnets"], ",", -1, true) {
+		_, ipNet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			continue
+		}
+
+		if ipNet.Contains(clientIP) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,125 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+const testZone = `
+incus.example.net. 3600 IN SOA ns1.example.net. hostmaster.incus.example.net. 1 120 60 86400 30
+incus.example.net. 300 IN NS ns1.example.net.
+c1.incus.example.net. 300 IN A 10.0.0.2
+c1.incus.example.net. 300 IN AAAA fd42::2
+www.incus.example.net. 300 IN CNAME c1.incus.example.net.
+_http._tcp.c1.incus.example.net. 300 IN SRV 10 5 80 c1.incus.example.net.
+incus.example.net. 3600 IN SOA ns1.example.net. hostmaster.incus.example.net. 1 120 60 86400 30
+`
+
+// Queries are answered from the matching records of the zone.
+func TestAnswerQuery(t *testing.T) {
+	records, err := zoneRecords(testZone)
+	require.NoError(t, err)
+
+	answer, authority, rcode := answerQuery("incus.example.net", records, "C1.incus.example.net.", dns.TypeA, false)
+	assert.Equal(t, dns.RcodeSuccess, rcode)
+	assert.Empty(t, authority)
+	require.Len(t, answer, 1)
+	assert.Equal(t, "10.0.0.2", answer[0].(*dns.A).A.String())
+
+	// CNAME records are followed within the zone.
+	answer, _, rcode = answerQuery("incus.example.net", records, "www.incus.example.net.", dns.TypeAAAA, false)
+	assert.Equal(t, dns.RcodeSuccess, rcode)
+	require.Len(t, answer, 2)
+	assert.Equal(t, dns.TypeCNAME, answer[0].Header().Rrtype)
+	assert.Equal(t, dns.TypeAAAA, answer[1].Header().Rrtype)
+
+	// The SOA record is only returned once.
+	answer, _, _ = answerQuery("incus.example.net", records, "incus.example.net.", dns.TypeSOA, false)
+	assert.Len(t, answer, 1)
+}
+
+// Negative answers carry the SOA record and distinguish missing names from missing types.
+func TestAnswerQuery_Negative(t *testing.T) {
+	records, err := zoneRecords(testZone)
+	require.NoError(t, err)
+
+	answer, authority, rcode := answerQuery("incus.example.net", records, "c2.incus.example.net.", dns.TypeA, false)
+	assert.Equal(t, dns.RcodeNameError, rcode)
+	assert.Empty(t, answer)
+	require.Len(t, authority, 1)
+	assert.Equal(t, dns.TypeSOA, authority[0].Header().Rrtype)
+
+	answer, authority, rcode = answerQuery("incus.example.net", records, "c1.incus.example.net.", dns.TypeTXT, false)
+	assert.Equal(t, dns.RcodeSuccess, rcode)
+	assert.Empty(t, answer)
+	assert.Len(t, authority, 1)
+
+	// Empty non-terminals exist.
+	_, _, rcode = answerQuery("incus.example.net", records, "_tcp.c1.incus.example.net.", dns.TypeA, false)
+	assert.Equal(t, dns.RcodeSuccess, rcode)
+}
+
+// Regular queries are only allowed from the configured subnets.
+func TestIsQueryAllowed(t *testing.T) {
+	zone := api.NetworkZone{}
+	zone.Config = map[string]string{"dns.query.subnets": "10.0.0.0/24, fd42::/64"}
+
+	assert.True(t, isQueryAllowed(zone, "10.0.0.5"))
+	assert.True(t, isQueryAllowed(zone, "fd42::5"))
+	assert.False(t, isQueryAllowed(zone, "10.0.1.5"))
+	assert.False(t, isQueryAllowed(api.NetworkZone{}, "10.0.0.5"))
+}
+
+const testSignedZone = `
+incus.example.net. 3600 IN SOA ns1.example.net. hostmaster.incus.example.net. 1 120 60 86400 30
+incus.example.net. 3600 IN RRSIG SOA 13 3 3600 20261101000000 20261018000000 12345 incus.example.net. AAAA
+incus.example.net. 30 IN NSEC c1.incus.example.net. NS SOA RRSIG NSEC DNSKEY
+c1.incus.example.net. 300 IN A 10.0.0.2
+c1.incus.example.net. 300 IN RRSIG A 13 4 300 20261101000000 20261018000000 12345 incus.example.net. AAAA
+c1.incus.example.net. 30 IN NSEC _http._tcp.c1.incus.example.net. A RRSIG NSEC
+_http._tcp.c1.incus.example.net. 300 IN SRV 10 5 80 c1.incus.example.net.
+_http._tcp.c1.incus.example.net. 30 IN NSEC www.incus.example.net. SRV RRSIG NSEC
+www.incus.example.net. 300 IN CNAME c1.incus.example.net.
+www.incus.example.net. 30 IN NSEC incus.example.net. CNAME RRSIG NSEC
+incus.example.net. 3600 IN SOA ns1.example.net. hostmaster.incus.example.net. 1 120 60 86400 30
+`
+
+// Signatures and proofs of non-existence are only included when requested.
+func TestAnswerQuery_DNSSEC(t *testing.T) {
+	records, err := zoneRecords(testSignedZone)
+	require.NoError(t, err)
+
+	answer, _, _ := answerQuery("incus.example.net", records, "c1.incus.example.net.", dns.TypeA, false)
+	assert.Len(t, answer, 1)
+
+	answer, _, rcode := answerQuery("incus.example.net", records, "c1.incus.example.net.", dns.TypeA, true)
+	assert.Equal(t, dns.RcodeSuccess, rcode)
+	require.Len(t, answer, 2)
+	assert.Equal(t, dns.TypeRRSIG, answer[1].Header().Rrtype)
+
+	// Missing types are proven by the NSEC record of the name.
+	_, authority, rcode := answerQuery("incus.example.net", records, "c1.incus.example.net.", dns.TypeTXT, true)
+	assert.Equal(t, dns.RcodeSuccess, rcode)
+	require.Len(t, authority, 3)
+	assert.Equal(t, "c1.incus.example.net.", authority[2].Header().Name)
+
+	// Missing names are proven by the NSEC records covering the name and the wildcard.
+	_, authority, rcode = answerQuery("incus.example.net", records, "c2.incus.example.net.", dns.TypeA, true)
+	assert.Equal(t, dns.RcodeNameError, rcode)
+	require.Len(t, authority, 4)
+	assert.Equal(t, "_http._tcp.c1.incus.example.net.", authority[2].Header().Name)
+	assert.Equal(t, "incus.example.net.", authority[3].Header().Name)
+}
+
+// Names are sorted in canonical DNS order.
+func TestCompareNames(t *testing.T) {
+	assert.Negative(t, CompareNames("incus.example.net.", "c1.incus.example.net."))
+	assert.Negative(t, CompareNames("c1.incus.example.net.", "_http._tcp.c1.incus.example.net."))
+	assert.Negative(t, CompareNames("_http._tcp.c1.incus.example.net.", "www.incus.example.net."))
+	assert.Zero(t, CompareNames("WWW.incus.example.net", "www.incus.example.net."))
+}
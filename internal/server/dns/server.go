@@ -34,6 +34,10 @@ type Server struct {
 	cmd chan serverCmdInfo
 
 	mu sync.Mutex
+
+	// Rendered zones.
+	zoneCache map[zoneCacheKey]*zoneCacheEntry
+	cacheMu   sync.Mutex
 }
 
 type serverCmd int
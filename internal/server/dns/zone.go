@@ -1,11 +1,94 @@
 package dns
 
 import (
+	"net/http"
+	"time"
+
+	"github.com/miekg/dns"
+
 	"github.com/lxc/incus/v6/shared/api"
 )
 
+// zoneCacheTTL is how long a rendered zone is served before being rendered again.
+// This bounds the staleness of records which change without an explicit invalidation (leases, other cluster members).
+const zoneCacheTTL = 10 * time.Second
+
+// zoneCacheSize is the number of cache entries above which expired entries get pruned.
+const zoneCacheSize = 1024
+
 // Zone represents a DNS zone configuration and its content.
 type Zone struct {
 	Info    api.NetworkZone
 	Content string
 }
+
+type zoneCacheKey struct {
+	name string
+	full bool
+}
+
+type zoneCacheEntry struct {
+	zone    *Zone
+	records []dns.RR
+	err     error
+	expiry  time.Time
+}
+
+// getZone returns the zone and its parsed records, rendering it through the zone retriever if not cached.
+// Missing zones are cached too so that queries for names outside of any zone don't hit the database each time, but
+// other failures aren't so that the zone is retried on the next query.
+func (s *Server) getZone(name string, full bool) (*Zone, []dns.RR, error) {
+	key := zoneCacheKey{name: name, full: full}
+	now := time.Now()
+
+	s.cacheMu.Lock()
+	entry, ok := s.zoneCache[key]
+	s.cacheMu.Unlock()
+
+	if ok && now.Before(entry.expiry) {
+		return entry.zone, entry.records, entry.err
+	}
+
+	entry = &zoneCacheEntry{expiry: now.Add(zoneCacheTTL)}
+	entry.zone, entry.err = s.zoneRetriever(name, full)
+	if entry.err == nil {
+		entry.records, entry.err = zoneRecords(entry.zone.Content)
+	}
+
+	if entry.zone == nil && entry.err != nil && !api.StatusErrorCheck(entry.err, http.StatusNotFound) {
+		return nil, nil, entry.err
+	}
+
+	s.cacheMu.Lock()
+	if s.zoneCache == nil {
+		s.zoneCache = map[zoneCacheKey]*zoneCacheEntry{}
+	} else if len(s.zoneCache) >= zoneCacheSize {
+		for k, v := range s.zoneCache {
+			if !now.Before(v.expiry) {
+				delete(s.zoneCache, k)
+			}
+		}
+
+		if len(s.zoneCache) >= zoneCacheSize {
+			s.zoneCache = map[zoneCacheKey]*zoneCacheEntry{}
+		}
+	}
+
+	s.zoneCache[key] = entry
+	s.cacheMu.Unlock()
+
+	return entry.zone, entry.records, entry.err
+}
+
+// InvalidateZones drops all rendered zones from the cache.
+// It should be called whenever the content of the zones may have changed.
+func (s *Server) InvalidateZones() {
+	if s == nil {
+		return
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	s.zoneCache = nil
+}
@@ -0,0 +1,99 @@
+package dns
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Rendered zones are cached until invalidated.
+func TestServerGetZone(t *testing.T) {
+	renders := map[string]int{}
+	s := NewServer(nil, func(name string, full bool) (*Zone, error) {
+		renders[name]++
+		if name == "broken.example.net" {
+			return nil, errors.New("Database unavailable")
+		}
+
+		if name != "incus.example.net" {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Network zone not found")
+		}
+
+		return &Zone{Info: api.NetworkZone{Name: name}, Content: testZone}, nil
+	})
+
+	zone, records, err := s.getZone("incus.example.net", true)
+	require.NoError(t, err)
+	require.NotNil(t, zone)
+	assert.Len(t, records, 7)
+
+	_, _, err = s.getZone("incus.example.net", true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, renders["incus.example.net"])
+
+	// Missing zones are cached too.
+	for range 2 {
+		zone, _, err = s.getZone("example.net", true)
+		assert.Error(t, err)
+		assert.Nil(t, zone)
+	}
+
+	assert.Equal(t, 1, renders["example.net"])
+
+	// Other failures aren't.
+	for range 2 {
+		zone, _, err = s.getZone("broken.example.net", true)
+		assert.Error(t, err)
+		assert.Nil(t, zone)
+	}
+
+	assert.Equal(t, 2, renders["broken.example.net"])
+
+	// Invalidating the cache renders the zones again.
+	s.InvalidateZones()
+
+	_, _, err = s.getZone("incus.example.net", true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, renders["incus.example.net"])
+}
+
+// Names are answered from the most specific zone containing them, unless looking up a zone fails.
+func TestFindZone(t *testing.T) {
+	s := NewServer(nil, func(name string, full bool) (*Zone, error) {
+		switch name {
+		case "incus.example.net", "example.org":
+			return &Zone{Info: api.NetworkZone{Name: name}, Content: testZone}, nil
+		case "broken.incus.example.net":
+			return nil, errors.New("Database unavailable")
+		}
+
+		return nil, api.StatusErrorf(http.StatusNotFound, "Network zone not found")
+	})
+
+	d := dnsHandler{server: s}
+
+	zone, _, err := d.findZone("c1.incus.example.net", dns.TypeA)
+	require.NoError(t, err)
+	require.NotNil(t, zone)
+	assert.Equal(t, "incus.example.net", zone.Info.Name)
+
+	zone, _, err = d.findZone("incus.example.net", dns.TypeSOA)
+	require.NoError(t, err)
+	require.NotNil(t, zone)
+	assert.Equal(t, "incus.example.net", zone.Info.Name)
+
+	zone, _, err = d.findZone("c1.example.com", dns.TypeA)
+	require.NoError(t, err)
+	assert.Nil(t, zone)
+
+	// A failed lookup doesn't fall back to the parent zone.
+	zone, _, err = d.findZone("c1.broken.incus.example.net", dns.TypeA)
+	assert.Error(t, err)
+	assert.Nil(t, zone)
+}
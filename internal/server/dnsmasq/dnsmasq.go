@@ -21,6 +21,10 @@ import (
 
 const staticAllocationDeviceSeparator = "."
 
+// staticReservationPrefix is the file name prefix of network DHCP reservation static allocations.
+// Instance and project names cannot contain "@" so it doesn't conflict with instance device allocations.
+const staticReservationPrefix = "@reservation."
+
 // DHCPAllocation represents an IP allocation from dnsmasq.
 type DHCPAllocation struct {
 	IP             net.IP
@@ -62,6 +66,52 @@ func UpdateStaticEntry(network string, projectName string, instanceName string,
 	return nil
 }
 
+// UpdateReservationEntry writes the dhcp-host lines for a network DHCP reservation.
+// The MAC address line carries both addresses, a separate DUID line is added to match DHCPv6 clients by DUID.
+func UpdateReservationEntry(network string, name string, netConfig map[string]string, hwaddr string, duid string, ipv4Address string, ipv6Address string) error {
+	hostname := ""
+	if netConfig["dns.mode"] == "" || netConfig["dns.mode"] == "managed" {
+		hostname = name
+	}
+
+	lines := []string{}
+	for _, id := range []string{strings.ToLower(hwaddr), "id:" + strings.ToLower(duid)} {
+		if id == "" || id == "id:" {
+			continue
+		}
+
+		fields := []string{id}
+		if ipv4Address != "" && !strings.HasPrefix(id, "id:") {
+			fields = append(fields, ipv4Address)
+		}
+
+		if ipv6Address != "" {
+			fields = append(fields, fmt.Sprintf("[%s]", ipv6Address))
+		}
+
+		if hostname != "" {
+			fields = append(fields, hostname)
+		}
+
+		if len(fields) == 1 {
+			continue
+		}
+
+		lines = append(lines, strings.Join(fields, ","))
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	err := os.WriteFile(DHCPStaticAllocationPath(network, ReservationFileName(name)), []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // RemoveStaticEntry removes a single dhcp-host line for a network/instance combination.
 func RemoveStaticEntry(network string, projectName string, instanceName string, deviceName string) error {
 	deviceStaticFileName := StaticAllocationFileName(projectName, instanceName, deviceName)
@@ -140,6 +190,11 @@ func DHCPStaticAllocation(network string, deviceStaticFileName string) (net.Hard
 	for scanner.Scan() {
 		fields := strings.SplitN(scanner.Text(), ",", -1)
 		for _, field := range fields {
+			// Skip DHCPv6 client identifiers (used by reservations).
+			if strings.HasPrefix(field, "id:") {
+				continue
+			}
+
 			// Check if field is IPv4 or IPv6 address.
 			if strings.Count(field, ".") == 3 {
 				IP := net.ParseIP(field)
@@ -281,3 +336,8 @@ func StaticAllocationFileName(projectName string, instanceName string, deviceNam
 
 	return strings.Join([]string{project.Instance(projectName, instanceName), escapedDeviceName}, staticAllocationDeviceSeparator)
 }
+
+// ReservationFileName returns the file name to use for a dnsmasq network DHCP reservation static allocation.
+func ReservationFileName(name string) string {
+	return staticReservationPrefix + name
+}
@@ -1,9 +1,16 @@
 package dnsmasq
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	internalUtil "github.com/lxc/incus/v6/internal/util"
 )
 
 func Test_staticAllocationFileName(t *testing.T) {
@@ -13,3 +20,82 @@ func Test_staticAllocationFileName(t *testing.T) {
 	fileName := StaticAllocationFileName(projectName, instanceName, deviceName)
 	assert.Equal(t, "test.project_test-instance.test-.--_----.device", fileName)
 }
+
+func Test_reservationEntry(t *testing.T) {
+	t.Setenv("INCUS_DIR", t.TempDir())
+
+	err := os.MkdirAll(internalUtil.VarPath("networks", "incusbr0", "dnsmasq.hosts"), 0o755)
+	require.NoError(t, err)
+
+	err = UpdateReservationEntry("incusbr0", "printer01", map[string]string{}, "00:16:3E:12:34:56", "00:01:00:01:2c:d3:4e:5f:00:16:3e:12:34:56", "10.0.0.10", "fd42::10")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(DHCPStaticAllocationPath("incusbr0", ReservationFileName("printer01")))
+	require.NoError(t, err)
+	assert.Equal(t, "00:16:3e:12:34:56,10.0.0.10,[fd42::10],printer01\nid:00:01:00:01:2c:d3:4e:5f:00:16:3e:12:34:56,[fd42::10],printer01\n", string(content))
+
+	mac, ipv4, ipv6, err := DHCPStaticAllocation("incusbr0", ReservationFileName("printer01"))
+	require.NoError(t, err)
+	assert.Equal(t, "00:16:3e:12:34:56", mac.String())
+	assert.Equal(t, "10.0.0.10", ipv4.IP.String())
+	assert.Equal(t, "fd42::10", ipv6.IP.String())
+}
+
+func Test_parseEvent(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    *LeaseEvent
+		wantErr bool
+	}{
+		{line: "add 00:16:3e:12:34:56 10.0.0.10 c1", want: &LeaseEvent{Type: "acquired", Hwaddr: "00:16:3e:12:34:56", Address: "10.0.0.10", Hostname: "c1"}},
+		{line: "old 00:16:3e:12:34:56 10.0.0.10 -", want: &LeaseEvent{Type: "renewed", Hwaddr: "00:16:3e:12:34:56", Address: "10.0.0.10"}},
+		{line: "del - fd42::10 c1", want: &LeaseEvent{Type: "released", Address: "fd42::10", Hostname: "c1"}},
+		{line: "init - - -", wantErr: true},
+		{line: "add 00:16:3e:12:34:56 10.0.0.10", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			event, err := ParseEvent(tt.line)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, event)
+		})
+	}
+}
+
+func Test_eventScript(t *testing.T) {
+	t.Setenv("INCUS_DIR", t.TempDir())
+
+	err := os.MkdirAll(internalUtil.VarPath("networks", "incusbr0"), 0o755)
+	require.NoError(t, err)
+
+	events := make(chan LeaseEvent, 1)
+	err = ListenEvents("incusbr0", func(event LeaseEvent) { events <- event })
+	require.NoError(t, err)
+
+	defer StopEvents("incusbr0")
+
+	script := filepath.Join(t.TempDir(), "dnsmasq.script")
+	err = os.WriteFile(script, []byte(EventScript("incusbr0")), 0o700)
+	require.NoError(t, err)
+
+	// DHCPv6 lease, the DUID is passed instead of the MAC address.
+	cmd := exec.Command(script, "add", "00:01:00:01:2c:d3:4e:5f:00:16:3e:12:34:56", "fd42::10", "c1")
+	cmd.Env = []string{"DNSMASQ_IAID=1"}
+	require.NoError(t, cmd.Run())
+
+	// Unrelated notifications are ignored.
+	require.NoError(t, exec.Command(script, "tftp", "0", "10.0.0.10", "/boot").Run())
+
+	select {
+	case event := <-events:
+		assert.Equal(t, LeaseEvent{Type: "acquired", Address: "fd42::10", Hostname: "c1"}, event)
+	case <-time.After(5 * time.Second):
+		t.Fatal("No lease event received")
+	}
+}
@@ -0,0 +1,130 @@
+package dnsmasq
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// LeaseEvent represents a DHCP lease change reported by dnsmasq.
+type LeaseEvent struct {
+	Type     string // Type of event (acquired, renewed or released).
+	Hwaddr   string // MAC address of the client, empty if only its DUID is known.
+	Address  string
+	Hostname string
+}
+
+var eventListeners = map[string]*os.File{}
+var eventListenersMu sync.Mutex
+
+// EventsPath returns the path of the FIFO dnsmasq reports the DHCP lease changes of the network to.
+func EventsPath(network string) string {
+	return internalUtil.VarPath("networks", network, "dnsmasq.events")
+}
+
+// EventScript returns the --dhcp-script for dnsmasq reporting the DHCP lease changes of the network.
+// The script only relies on shell builtins and writes a single line per change to the events FIFO, so dnsmasq
+// doesn't need any access to the daemon. Writes block until the daemon listens on the FIFO again.
+func EventScript(network string) string {
+	return fmt.Sprintf(`#!/bin/sh
+case "$1" in
+    add|old|del) ;;
+    *) exit 0 ;;
+esac
+
+# For DHCPv6 leases, the client DUID is passed instead of the MAC address.
+hwaddr="${DNSMASQ_MAC}"
+if [ -z "${hwaddr}" ] && [ -z "${DNSMASQ_IAID}" ]; then
+    hwaddr="$2"
+fi
+
+printf '%%s %%s %%s %%s\n' "$1" "${hwaddr:--}" "$3" "${4:--}" > '%s'
+`, EventsPath(network))
+}
+
+// ParseEvent parses a line written by the lease script.
+func ParseEvent(line string) (*LeaseEvent, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("Invalid lease event %q", line)
+	}
+
+	event := &LeaseEvent{}
+
+	switch fields[0] {
+	case "add":
+		event.Type = "acquired"
+	case "old":
+		event.Type = "renewed"
+	case "del":
+		event.Type = "released"
+	default:
+		return nil, fmt.Errorf("Invalid lease event type %q", fields[0])
+	}
+
+	for i, value := range []*string{&event.Hwaddr, &event.Address, &event.Hostname} {
+		if fields[i+1] != "-" {
+			*value = fields[i+1]
+		}
+	}
+
+	return event, nil
+}
+
+// ListenEvents creates the events FIFO of the network (if missing) and calls handler for each DHCP lease change
+// reported by dnsmasq, until StopEvents is called. Any existing listener for the network is stopped first.
+func ListenEvents(network string, handler func(event LeaseEvent)) error {
+	StopEvents(network)
+
+	path := EventsPath(network)
+
+	err := unix.Mkfifo(path, 0o600)
+	if err != nil && !errors.Is(err, unix.EEXIST) {
+		return fmt.Errorf("Failed creating DHCP lease events FIFO: %w", err)
+	}
+
+	// Open for both reading and writing so the FIFO doesn't report EOF when the lease script exits.
+	fifo, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("Failed opening DHCP lease events FIFO: %w", err)
+	}
+
+	eventListenersMu.Lock()
+	eventListeners[network] = fifo
+	eventListenersMu.Unlock()
+
+	go func() {
+		scanner := bufio.NewScanner(fifo)
+		for scanner.Scan() {
+			event, err := ParseEvent(scanner.Text())
+			if err != nil {
+				logger.Warn("Ignoring DHCP lease event", logger.Ctx{"network": network, "err": err})
+				continue
+			}
+
+			handler(*event)
+		}
+	}()
+
+	return nil
+}
+
+// StopEvents stops listening for the DHCP lease changes of the network.
+func StopEvents(network string) {
+	eventListenersMu.Lock()
+	fifo, found := eventListeners[network]
+	delete(eventListeners, network)
+	eventListenersMu.Unlock()
+
+	if found {
+		_ = fifo.Close()
+	}
+}
@@ -27,6 +27,9 @@ type Opts struct {
 	AddressSet bool         // Enable address sets, only for netfilter.
 }
 
+// ACLLogGroup is the netfilter log group that logged ACL rules send packets to.
+const ACLLogGroup = 8754
+
 // ACLRule represents an ACL rule that can be added to a firewall.
 type ACLRule struct {
 	Direction       string // Either "ingress" or "egress.
@@ -40,6 +43,13 @@ type ACLRule struct {
 	DestinationPort string
 	ICMPType        string
 	ICMPCode        string
+	Counter         string // Name used to track the hit counters of the rule (optional).
+}
+
+// ACLRuleCounters represents the hit counters of an ACL rule.
+type ACLRuleCounters struct {
+	Packets uint64 `json:"packets"`
+	Bytes   uint64 `json:"bytes"`
 }
 
 // AddressForward represents a NAT address forward.
@@ -52,6 +62,13 @@ type AddressForward struct {
 	SNAT          bool
 }
 
+// HostListener represents a listener of the host protected by the host ACLs.
+type HostListener struct {
+	Protocol string
+	Address  net.IP
+	Port     uint64
+}
+
 // AddressSet represent an address set.
 type AddressSet struct {
 	Name      string
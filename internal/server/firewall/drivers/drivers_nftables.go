@@ -932,6 +932,45 @@ func (d Nftables) InstanceClearNetPrio(projectName string, instanceName string,
 	return nil
 }
 
+// InstanceSetupQoS activates the classification of the traffic sent by the specified instance device into a QoS
+// class of its network, setting the DSCP field of its IP packets if dscp isn't negative.
+func (d Nftables) InstanceSetupQoS(projectName string, instanceName string, deviceName string, classID uint32, dscp int) error {
+	deviceLabel := d.instanceDeviceLabel(projectName, instanceName, deviceName)
+	tplFields := map[string]any{
+		"namespace":      nftablesNamespace,
+		"family":         "netdev",
+		"chainSeparator": nftablesChainSeparator,
+		"deviceLabel":    deviceLabel,
+		"deviceName":     deviceName,
+		"classID":        fmt.Sprintf("%x:%x", classID>>16, classID&0xFFFF),
+		"dscp":           dscp,
+	}
+
+	err := d.applyNftConfig(nftablesInstanceQoS, tplFields)
+	if err != nil {
+		return fmt.Errorf("Failed adding QoS rules for instance device %q: %w", deviceLabel, err)
+	}
+
+	return nil
+}
+
+// InstanceClearQoS removes the classification of the traffic sent by the specified instance device.
+func (d Nftables) InstanceClearQoS(projectName string, instanceName string, deviceName string) error {
+	if deviceName == "" {
+		return fmt.Errorf("Failed clearing QoS rules for instance %q in project %q: device name is empty", projectName, instanceName)
+	}
+
+	deviceLabel := d.instanceDeviceLabel(projectName, instanceName, deviceName)
+	chainLabel := fmt.Sprintf("qos%s%s", nftablesChainSeparator, deviceLabel)
+
+	err := d.removeChains([]string{"netdev"}, chainLabel, "ingress")
+	if err != nil {
+		return fmt.Errorf("Failed clearing QoS rules for instance device %q: %w", deviceLabel, err)
+	}
+
+	return nil
+}
+
 // NetworkApplyACLRules applies ACL rules to the existing firewall chains.
 func (d Nftables) NetworkApplyACLRules(networkName string, rules []ACLRule) error {
 	completeNftRules := make([]string, 0)
@@ -986,6 +1025,151 @@ func (d Nftables) NetworkApplyACLRules(networkName string, rules []ACLRule) erro
 	return nil
 }
 
+// HostApplyACLRules applies the ingress ACL rules to the traffic sent to the listeners of the host.
+// The traffic coming from the trusted addresses is always allowed.
+func (d Nftables) HostApplyACLRules(listeners []HostListener, trustedAddresses []net.IP, rules []ACLRule) error {
+	config, err := d.hostACLConfig(listeners, trustedAddresses, rules)
+	if err != nil {
+		return err
+	}
+
+	err = subprocess.RunCommandWithFds(context.TODO(), strings.NewReader(config), nil, "nft", "-f", "-")
+	if err != nil {
+		return fmt.Errorf("Failed applying host ACL rules: %w", err)
+	}
+
+	return nil
+}
+
+// hostACLConfig returns the nftables configuration applying the ingress ACL rules to the listeners of the host.
+func (d Nftables) hostACLConfig(listeners []HostListener, trustedAddresses []net.IP, rules []ACLRule) (string, error) {
+	listenerMatches := make([]string, 0, len(listeners))
+	for _, listener := range listeners {
+		args := []string{}
+		if listener.Address != nil && !listener.Address.IsUnspecified() {
+			if listener.Address.To4() != nil {
+				args = append(args, "ip", "daddr", listener.Address.String())
+			} else {
+				args = append(args, "ip6", "daddr", listener.Address.String())
+			}
+		}
+
+		args = append(args, "meta", "l4proto", listener.Protocol, "th", "dport", fmt.Sprintf("%d", listener.Port))
+		listenerMatches = append(listenerMatches, strings.Join(args, " "))
+	}
+
+	trustedMatches := make([]string, 0, len(trustedAddresses))
+	for _, address := range trustedAddresses {
+		if address.To4() != nil {
+			trustedMatches = append(trustedMatches, fmt.Sprintf("ip saddr %s", address.String()))
+		} else {
+			trustedMatches = append(trustedMatches, fmt.Sprintf("ip6 saddr %s", address.String()))
+		}
+	}
+
+	completeNftRules := make([]string, 0)
+	for _, rule := range rules {
+		if rule.Direction != "ingress" {
+			continue
+		}
+
+		nftRules, partial, err := d.aclRuleCriteriaToRules("", 4, &rule)
+		if err != nil {
+			return "", err
+		}
+
+		completeNftRules = append(completeNftRules, nftRules...)
+
+		if partial {
+			nftRules, _, err = d.aclRuleCriteriaToRules("", 6, &rule)
+			if err != nil {
+				return "", err
+			}
+
+			completeNftRules = append(completeNftRules, nftRules...)
+		}
+	}
+
+	tplFields := map[string]any{
+		"namespace": nftablesNamespace,
+		"family":    "inet",
+		"listeners": listenerMatches,
+		"trusted":   trustedMatches,
+		"rules":     completeNftRules,
+	}
+
+	config := &strings.Builder{}
+	err := nftablesHostACL.Execute(config, tplFields)
+	if err != nil {
+		return "", fmt.Errorf("Failed running %q template: %w", nftablesHostACL.Name(), err)
+	}
+
+	return config.String(), nil
+}
+
+// HostClearACLRules removes the ACL rules applied to the listeners of the host.
+func (d Nftables) HostClearACLRules() error {
+	err := d.removeChains([]string{"inet"}, "", "hostin", "hostacl")
+	if err != nil {
+		return fmt.Errorf("Failed clearing host ACL rules: %w", err)
+	}
+
+	return nil
+}
+
+// NetworkACLRuleCounters returns the hit counters of all ACL rules, indexed by counter name.
+// Counters of rules sharing the same name (such as the IPv4 and IPv6 variants of a rule) are summed.
+func (d Nftables) NetworkACLRuleCounters() (map[string]ACLRuleCounters, error) {
+	// Dump ruleset as JSON. Use -nn flags to avoid doing DNS lookups of IPs mentioned in any rules.
+	output, err := subprocess.RunCommand("nft", "--json", "-nn", "list", "ruleset")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to execute nft command: %w", err)
+	}
+
+	return nftablesParseACLRuleCounters(output)
+}
+
+// nftablesParseACLRuleCounters parses the hit counters of the ACL rules from the JSON dump of the ruleset.
+func nftablesParseACLRuleCounters(output string) (map[string]ACLRuleCounters, error) {
+	// This only extracts the parts of the rules needed to read the counters, see man libnftables-json for more info.
+	v := &struct {
+		Nftables []struct {
+			Rule *struct {
+				Table   string `json:"table"`
+				Comment string `json:"comment"`
+				Expr    []struct {
+					Counter *ACLRuleCounters `json:"counter"`
+				} `json:"expr"`
+			} `json:"rule"`
+		} `json:"nftables"`
+	}{}
+
+	err := json.Unmarshal([]byte(output), v)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse nft command output: %w", err)
+	}
+
+	counters := map[string]ACLRuleCounters{}
+	for _, item := range v.Nftables {
+		if item.Rule == nil || item.Rule.Table != nftablesNamespace || item.Rule.Comment == "" {
+			continue
+		}
+
+		for _, expr := range item.Rule.Expr {
+			if expr.Counter == nil {
+				continue
+			}
+
+			entry := counters[item.Rule.Comment]
+			entry.Packets += expr.Counter.Packets
+			entry.Bytes += expr.Counter.Bytes
+			counters[item.Rule.Comment] = entry
+		}
+	}
+
+	return counters, nil
+}
+
 // buildRemainingRuleParts is a helper that returns the protocol, port, logging, and action parts of a rule.
 func (d Nftables) buildRemainingRuleParts(rule *ACLRule, ipVersion uint) (string, error) {
 	args := []string{}
@@ -1022,12 +1206,16 @@ func (d Nftables) buildRemainingRuleParts(rule *ACLRule, ipVersion uint) (string
 		}
 	}
 
-	// Handle logging.
+	// Handle hit counters.
+	if rule.Counter != "" {
+		args = append(args, "counter")
+	}
+
+	// Handle logging (sent to the netlink log group rather than the kernel log).
 	if rule.Log {
-		args = append(args, "log")
+		args = append(args, "log", "group", fmt.Sprintf("%d", ACLLogGroup))
 		if rule.LogName != "" {
-			// Append a trailing space for readability in logs.
-			args = append(args, "prefix", fmt.Sprintf(`"%s "`, rule.LogName))
+			args = append(args, "prefix", fmt.Sprintf(`"%s"`, rule.LogName))
 		}
 	}
 
@@ -1039,6 +1227,11 @@ func (d Nftables) buildRemainingRuleParts(rule *ACLRule, ipVersion uint) (string
 
 	args = append(args, action)
 
+	// Tag the rule so its counters can be retrieved later.
+	if rule.Counter != "" {
+		args = append(args, "comment", fmt.Sprintf(`"%s"`, rule.Counter))
+	}
+
 	return strings.Join(args, " "), nil
 }
 
@@ -1046,15 +1239,17 @@ func (d Nftables) buildRemainingRuleParts(rule *ACLRule, ipVersion uint) (string
 // It uses aclRuleSubjectToACLMatch to generate separate fragments for subject criteria.
 // The function returns a slice of complete rule strings, a partial flag, and an error.
 func (d Nftables) aclRuleCriteriaToRules(networkName string, ipVersion uint, rule *ACLRule) ([]string, bool, error) {
-	// Build a base argument list with the interface name.
+	// Build a base argument list with the interface name (none for the host ACL rules).
 	baseArgs := []string{}
 	var useAddressSets bool
-	if rule.Direction == "ingress" {
-		// For ingress, the rule applies to packets coming from the host into the network's interface.
-		baseArgs = append(baseArgs, "oifname", networkName)
-	} else {
-		// For egress, packets leaving the network's interface toward the host.
-		baseArgs = append(baseArgs, "iifname", networkName)
+	if networkName != "" {
+		if rule.Direction == "ingress" {
+			// For ingress, the rule applies to packets coming from the host into the network's interface.
+			baseArgs = append(baseArgs, "oifname", networkName)
+		} else {
+			// For egress, packets leaving the network's interface toward the host.
+			baseArgs = append(baseArgs, "iifname", networkName)
+		}
 	}
 
 	// We'll accumulate rule fragments in this slice.
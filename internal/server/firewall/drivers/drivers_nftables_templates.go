@@ -180,6 +180,38 @@ table {{.family}} {{.namespace}} {
 }
 `))
 
+// nftablesHostACL defines the rules applying the host ACLs to the traffic sent to the listeners of the host.
+// Traffic from the loopback interface or the trusted addresses and to other ports of the host isn't filtered.
+var nftablesHostACL = template.Must(template.New("nftablesHostACL").Parse(`
+add table {{.family}} {{.namespace}}
+add chain {{.family}} {{.namespace}} hostin {type filter hook input priority filter; policy accept;}
+add chain {{.family}} {{.namespace}} hostacl
+flush chain {{.family}} {{.namespace}} hostin
+flush chain {{.family}} {{.namespace}} hostacl
+
+table {{.family}} {{.namespace}} {
+	chain hostin {
+		iifname "lo" accept
+
+		{{- range .listeners}}
+		{{.}} jump hostacl
+		{{- end}}
+	}
+
+	chain hostacl {
+		ct state established,related accept
+
+		{{- range .trusted}}
+		{{.}} accept
+		{{- end}}
+
+		{{- range .rules}}
+		{{.}}
+		{{- end}}
+	}
+}
+`))
+
 // nftablesInstanceBridgeFilter defines the rules needed for MAC, IPv4 and IPv6 bridge security filtering.
 // To prevent instances from using IPs that are different from their assigned IPs we use ARP and NDP filtering
 // to prevent neighbour advertisements that are not allowed. However in order for DHCPv4 & DHCPv6 to work back to
@@ -381,3 +413,15 @@ chain egress{{.chainSeparator}}netprio{{.chainSeparator}}{{.deviceLabel}} {
 	meta priority set "{{.netPrio}}"
 }
 `))
+
+// nftablesInstanceQoS defines the rules to classify and mark the traffic sent by an instance device.
+var nftablesInstanceQoS = template.Must(template.New("nftablesInstanceQoS").Parse(`
+chain ingress{{.chainSeparator}}qos{{.chainSeparator}}{{.deviceLabel}} {
+	type filter hook ingress device "{{.deviceName}}" priority 0 ;
+	meta priority set "{{.classID}}"
+	{{- if ge .dscp 0}}
+	ip dscp set {{.dscp}}
+	ip6 dscp set {{.dscp}}
+	{{- end}}
+}
+`))
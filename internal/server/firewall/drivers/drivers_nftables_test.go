@@ -0,0 +1,94 @@
+package drivers
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_hostACLConfig(t *testing.T) {
+	listeners := []HostListener{
+		{Protocol: "tcp", Address: net.ParseIP("192.0.2.1"), Port: 8443},
+		{Protocol: "udp", Address: net.ParseIP("::"), Port: 53},
+	}
+
+	trustedAddresses := []net.IP{net.ParseIP("192.0.2.2"), net.ParseIP("2001:db8::2")}
+
+	rules := []ACLRule{
+		{Direction: "ingress", Action: "allow", Source: "198.51.100.0/24", Protocol: "tcp", DestinationPort: "8443"},
+		{Direction: "egress", Action: "allow", Destination: "203.0.113.0/24"},
+		{Direction: "ingress", Action: "reject"},
+	}
+
+	config, err := Nftables{}.hostACLConfig(listeners, trustedAddresses, rules)
+	require.NoError(t, err)
+
+	// Only the traffic sent to the listeners goes through the ACLs, an unspecified address matching any address.
+	assert.Contains(t, config, "ip daddr 192.0.2.1 meta l4proto tcp th dport 8443 jump hostacl\n")
+	assert.Contains(t, config, "\t\tmeta l4proto udp th dport 53 jump hostacl\n")
+
+	// The cluster members are allowed before any ACL rule is evaluated.
+	assert.Contains(t, config, "ip saddr 192.0.2.2 accept\n")
+	assert.Contains(t, config, "ip6 saddr 2001:db8::2 accept\n")
+	assert.Less(t, strings.Index(config, "ip6 saddr 2001:db8::2 accept"), strings.Index(config, "198.51.100.0/24"))
+
+	// Egress rules are ignored.
+	assert.Contains(t, config, "198.51.100.0/24")
+	assert.NotContains(t, config, "203.0.113.0/24")
+	assert.Contains(t, config, "reject")
+}
+
+func Test_nftablesParseACLRuleCounters(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    map[string]ACLRuleCounters
+		wantErr bool
+	}{
+		{
+			name: "Rules of both IP versions are summed",
+			output: `{"nftables": [
+{"metainfo": {"version": "1.0.9", "json_schema_version": 1}},
+{"table": {"family": "inet", "name": "incus", "handle": 1}},
+{"chain": {"family": "inet", "table": "incus", "name": "aclin.incusbr0", "handle": 2}},
+{"rule": {"family": "inet", "table": "incus", "chain": "aclin.incusbr0", "handle": 3, "comment": "incus_acl12-ingress-0", "expr": [{"match": {"op": "==", "left": {"payload": {"protocol": "ip", "field": "saddr"}}, "right": "192.0.2.1"}}, {"counter": {"packets": 10, "bytes": 840}}, {"accept": null}]}},
+{"rule": {"family": "inet", "table": "incus", "chain": "aclin.incusbr0", "handle": 4, "comment": "incus_acl12-ingress-0", "expr": [{"counter": {"packets": 2, "bytes": 208}}, {"accept": null}]}},
+{"rule": {"family": "inet", "table": "incus", "chain": "aclin.incusbr0", "handle": 5, "comment": "incus_acl12-egress-1", "expr": [{"counter": {"packets": 0, "bytes": 0}}, {"drop": null}]}}
+]}`,
+			want: map[string]ACLRuleCounters{
+				"incus_acl12-ingress-0": {Packets: 12, Bytes: 1048},
+				"incus_acl12-egress-1":  {},
+			},
+		},
+		{
+			name: "Rules of other tables and without comment are ignored",
+			output: `{"nftables": [
+{"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 3, "comment": "incus_acl12-ingress-0", "expr": [{"counter": {"packets": 10, "bytes": 840}}]}},
+{"rule": {"family": "inet", "table": "incus", "chain": "fwd.incusbr0", "handle": 4, "expr": [{"counter": {"packets": 5, "bytes": 400}}]}},
+{"rule": {"family": "inet", "table": "incus", "chain": "aclin.incusbr0", "handle": 5, "comment": "incus_acl12-ingress-1", "expr": [{"accept": null}]}}
+]}`,
+			want: map[string]ACLRuleCounters{},
+		},
+		{
+			name:    "Invalid output",
+			output:  "table inet incus {",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nftablesParseACLRuleCounters(tt.output)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
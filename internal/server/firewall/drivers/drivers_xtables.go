@@ -684,20 +684,74 @@ func (d Xtables) aclRuleCriteriaToArgs(networkName string, ipVersion uint, rule
 
 	actionArgs := append(args, "-j", strings.ToUpper(action))
 
+	// Tag the rule so its counters can be retrieved later.
+	if rule.Counter != "" {
+		actionArgs = append(actionArgs, "-m", "comment", "--comment", rule.Counter)
+	}
+
 	// Handle logging.
 	var logArgs []string
 	if rule.Log {
-		logArgs = append(args, "-j", "LOG")
+		logArgs = append(args, "-j", "NFLOG", "--nflog-group", fmt.Sprintf("%d", ACLLogGroup))
 
 		if rule.LogName != "" {
-			// Add a trailing space to prefix for readability in logs.
-			logArgs = append(logArgs, "--log-prefix", fmt.Sprintf("%s ", rule.LogName))
+			logArgs = append(logArgs, "--nflog-prefix", rule.LogName)
 		}
 	}
 
 	return actionArgs, logArgs, nil
 }
 
+// NetworkACLRuleCounters returns the hit counters of all ACL rules, indexed by counter name.
+// Counters of rules sharing the same name (such as the IPv4 and IPv6 variants of a rule) are summed.
+func (d Xtables) NetworkACLRuleCounters() (map[string]ACLRuleCounters, error) {
+	counters := map[string]ACLRuleCounters{}
+
+	for _, cmd := range []string{"iptables-save", "ip6tables-save"} {
+		output, err := subprocess.RunCommand(cmd, "-c", "-t", "filter")
+		if err != nil {
+			return nil, fmt.Errorf("Failed dumping %q rules: %w", cmd, err)
+		}
+
+		xtablesParseACLRuleCounters(output, counters)
+	}
+
+	return counters, nil
+}
+
+// xtablesParseACLRuleCounters adds the hit counters of the ACL rules from the output of iptables-save -c to counters.
+func xtablesParseACLRuleCounters(output string, counters map[string]ACLRuleCounters) {
+	for _, line := range strings.Split(output, "\n") {
+		// Only consider rules in the ACL chains, formatted as "[packets:bytes] -A chain ...".
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[1] != "-A" || !strings.HasPrefix(fields[2], iptablesChainACLFilterPrefix) {
+			continue
+		}
+
+		commentIndex := slices.Index(fields, "--comment")
+		if commentIndex < 0 || commentIndex+1 >= len(fields) {
+			continue
+		}
+
+		var packets, bytes uint64
+		_, err := fmt.Sscanf(fields[0], "[%d:%d]", &packets, &bytes)
+		if err != nil {
+			continue
+		}
+
+		// Comments are only quoted when they contain special characters, which counter names don't.
+		name := fields[commentIndex+1]
+		if strings.HasPrefix(name, "\"") {
+			continue
+		}
+
+		entry := counters[name]
+		entry.Packets += packets
+		entry.Bytes += bytes
+		counters[name] = entry
+	}
+}
+
 // aclRuleSubjectToACLMatch converts direction (source/destination) and subject criteria list into xtables args.
 // Returns nil if none of the subjects are appropriate for the ipVersion.
 func (d Xtables) aclRuleSubjectToACLMatch(direction string, ipVersion uint, subjectCriteria ...string) ([]string, error) {
@@ -1436,6 +1490,30 @@ func (d Xtables) InstanceClearNetPrio(projectName string, instanceName string, d
 	return nil
 }
 
+// HostApplyACLRules applies ACL rules to the traffic sent to the listeners of the host.
+// This isn't supported under xtables.
+func (d Xtables) HostApplyACLRules(listeners []HostListener, trustedAddresses []net.IP, rules []ACLRule) error {
+	return errors.New("Host ACLs are not supported under xtables")
+}
+
+// HostClearACLRules removes the ACL rules applied to the listeners of the host.
+// This isn't supported under xtables so there is nothing to remove.
+func (d Xtables) HostClearACLRules() error {
+	return nil
+}
+
+// InstanceSetupQoS activates the classification of the traffic sent by the specified instance device into a QoS
+// class of its network. This isn't supported under xtables.
+func (d Xtables) InstanceSetupQoS(projectName string, instanceName string, deviceName string, classID uint32, dscp int) error {
+	return errors.New("QoS classes are not supported under xtables")
+}
+
+// InstanceClearQoS removes the classification of the traffic sent by the specified instance device.
+// This isn't supported under xtables so there is nothing to remove.
+func (d Xtables) InstanceClearQoS(projectName string, instanceName string, deviceName string) error {
+	return nil
+}
+
 // iptablesChainExists checks whether a chain exists in a table, and whether it has any rules.
 func (d Xtables) iptablesChainExists(ipVersion uint, table string, chain string) (bool, bool, error) {
 	var cmd string
@@ -0,0 +1,67 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_xtablesParseACLRuleCounters(t *testing.T) {
+	ipv4 := `# Generated by iptables-save v1.8.10 on Sun Oct 18 12:00:00 2026
+*filter
+:INPUT ACCEPT [1200:96000]
+:incus_acl_incusbr0 - [0:0]
+[10:840] -A incus_acl_incusbr0 -s 192.0.2.1/32 -m comment --comment incus_acl12-ingress-0 -j ACCEPT
+[3:180] -A incus_acl_incusbr0 -m comment --comment incus_acl12-egress-1 -j REJECT --reject-with icmp-port-unreachable
+[7:420] -A incus_acl_incusbr0 -j REJECT
+[5:300] -A INPUT -i incusbr0 -m comment --comment "generated for Incus network incusbr0" -j ACCEPT
+[9:540] -A incus_acl_incusbr0 -m comment --comment "generated for Incus network incusbr0" -j ACCEPT
+COMMIT
+`
+
+	ipv6 := `*filter
+:incus_acl_incusbr0 - [0:0]
+[2:208] -A incus_acl_incusbr0 -s 2001:db8::1/128 -m comment --comment incus_acl12-ingress-0 -j ACCEPT
+[x:1] -A incus_acl_incusbr0 -m comment --comment incus_acl12-ingress-2 -j ACCEPT
+COMMIT
+`
+
+	tests := []struct {
+		name    string
+		outputs []string
+		want    map[string]ACLRuleCounters
+	}{
+		{
+			name:    "IPv4",
+			outputs: []string{ipv4},
+			want: map[string]ACLRuleCounters{
+				"incus_acl12-ingress-0": {Packets: 10, Bytes: 840},
+				"incus_acl12-egress-1":  {Packets: 3, Bytes: 180},
+			},
+		},
+		{
+			name:    "Rules of both IP versions are summed",
+			outputs: []string{ipv4, ipv6},
+			want: map[string]ACLRuleCounters{
+				"incus_acl12-ingress-0": {Packets: 12, Bytes: 1048},
+				"incus_acl12-egress-1":  {Packets: 3, Bytes: 180},
+			},
+		},
+		{
+			name:    "Empty",
+			outputs: []string{""},
+			want:    map[string]ACLRuleCounters{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counters := map[string]ACLRuleCounters{}
+			for _, output := range tt.outputs {
+				xtablesParseACLRuleCounters(output, counters)
+			}
+
+			assert.Equal(t, tt.want, counters)
+		})
+	}
+}
@@ -23,10 +23,14 @@ type Firewall interface {
 	NetworkSetup(networkName string, opts drivers.Opts) error
 	NetworkClear(networkName string, removeChains bool, ipVersions []uint) error
 	NetworkApplyACLRules(networkName string, rules []drivers.ACLRule) error
+	NetworkACLRuleCounters() (map[string]drivers.ACLRuleCounters, error)
 	NetworkApplyForwards(networkName string, rules []drivers.AddressForward) error
 	NetworkApplyAddressSets(sets []drivers.AddressSet, nftTable string) error
 	NetworkDeleteAddressSetsIfUnused(nftTable string) error
 
+	HostApplyACLRules(listeners []drivers.HostListener, trustedAddresses []net.IP, rules []drivers.ACLRule) error
+	HostClearACLRules() error
+
 	InstanceSetupBridgeFilter(projectName string, instanceName string, deviceName string, parentName string, hostName string, hwAddr string, IPv4Nets []*net.IPNet, IPv6Nets []*net.IPNet, IPv4DNS []string, IPv6DNS []string, parentManaged bool, macFiltering bool, aclRules []drivers.ACLRule) error
 	InstanceClearBridgeFilter(projectName string, instanceName string, deviceName string, parentName string, hostName string, hwAddr string, IPv4Nets []*net.IPNet, IPv6Nets []*net.IPNet) error
 
@@ -38,4 +42,7 @@ type Firewall interface {
 
 	InstanceSetupNetPrio(projectName string, instanceName string, deviceName string, netPrio uint32) error
 	InstanceClearNetPrio(projectName string, instanceName string, deviceName string) error
+
+	InstanceSetupQoS(projectName string, instanceName string, deviceName string, classID uint32, dscp int) error
+	InstanceClearQoS(projectName string, instanceName string, deviceName string) error
 }
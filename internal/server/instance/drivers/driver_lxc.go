@@ -965,8 +965,15 @@ func (d *lxc) initLXC(config bool) (*liblxc.Container, error) {
 		return nil, err
 	}
 
-	// Setup the console
-	err = lxcSetConfigItem(cc, "lxc.tty.max", "0")
+	// Setup the console and a tty for each serial device.
+	ttyCount := 0
+	for _, dev := range d.expandedDevices {
+		if dev["type"] == "serial" {
+			ttyCount++
+		}
+	}
+
+	err = lxcSetConfigItem(cc, "lxc.tty.max", strconv.Itoa(ttyCount))
 	if err != nil {
 		return nil, err
 	}
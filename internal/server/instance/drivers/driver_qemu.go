@@ -483,7 +483,7 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 	state := d.state
 
 	return func(event string, data map[string]any) {
-		if !slices.Contains([]string{qmp.EventVMShutdown, qmp.EventVMReset, qmp.EventAgentStarted, qmp.EventAgentStopped, qmp.EventRTCChange}, event) {
+		if !slices.Contains([]string{qmp.EventVMShutdown, qmp.EventVMReset, qmp.EventAgentStarted, qmp.EventAgentStopped, qmp.EventRTCChange, qmp.EventWatchdog}, event) {
 			return // Don't bother loading the instance from DB if we aren't going to handle the event.
 		}
 
@@ -571,6 +571,12 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 			if err != nil {
 				d.logger.Error("Failed to apply rtc change", logger.Ctx{"offset": val, "err": err})
 			}
+
+		case qmp.EventWatchdog:
+			err = d.onWatchdog()
+			if err != nil {
+				d.logger.Error("Failed to handle watchdog", logger.Ctx{"err": err})
+			}
 		}
 	}
 }
@@ -3745,6 +3751,58 @@ func (d *qemu) onRTCChange(change int) error {
 	return nil
 }
 
+// onWatchdog handles the guest watchdog firing.
+func (d *qemu) onWatchdog() error {
+	var devName, action string
+	for name, dev := range d.expandedDevices {
+		if dev["type"] == "watchdog" {
+			devName = name
+			action = dev["action"]
+			break
+		}
+	}
+
+	if action == "" {
+		action = "reset"
+	}
+
+	d.logger.Warn("Instance watchdog fired", logger.Ctx{"device": devName, "action": action})
+	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceWatchdog.Event(d, logger.Ctx{"device": devName, "action": action}))
+
+	if action != "dump" {
+		return nil
+	}
+
+	// QEMU paused the guest, dump its memory to the log directory.
+	// Only the latest dump is kept, so that a guest which keeps hanging can't fill up the disk.
+	err := removeWatchdogDumps(d.LogPath())
+	if err != nil {
+		return fmt.Errorf("Failed removing previous memory dumps: %w", err)
+	}
+
+	dumpPath := filepath.Join(d.LogPath(), fmt.Sprintf("watchdog_%s.dump", time.Now().UTC().Format("20060102T150405Z")))
+	f, err := os.OpenFile(dumpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed creating memory dump file: %w", err)
+	}
+
+	err = d.DumpGuestMemory(f, "elf")
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("Failed dumping guest memory: %w", err)
+	}
+
+	d.logger.Info("Dumped guest memory after watchdog fired", logger.Ctx{"path": dumpPath})
+
+	// Power off the instance, boot.autorestart then decides whether it comes back.
+	monitor, err := d.qmpConnect()
+	if err != nil {
+		return err
+	}
+
+	return monitor.Quit()
+}
+
 // generateQemuConfig generates the QEMU configuration.
 func (d *qemu) generateQemuConfig(machineDefinition string, cpuType string, cpuInfo *cpuTopology, mountInfo *storagePools.MountInfo, busName string, vsockFD int, devConfs []*deviceConfig.RunConfig, fdFiles *[]*os.File) ([]monitorHook, error) {
 	var monHooks []monitorHook
@@ -4182,6 +4240,24 @@ func (d *qemu) generateQemuConfig(machineDefinition string, cpuType string, cpuI
 				return nil, err
 			}
 		}
+
+		// Add serial device.
+		if len(runConf.SerialDevice) > 0 {
+			err = d.addSerialDeviceConfig(&conf, runConf.SerialDevice, fdFiles)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Add watchdog device.
+		if len(runConf.WatchdogDevice) > 0 {
+			monHook, err := d.addWatchdogDeviceConfig(&conf, bus, runConf.WatchdogDevice)
+			if err != nil {
+				return nil, err
+			}
+
+			monHooks = append(monHooks, monHook)
+		}
 	}
 
 	// VM generation ID is only available on x86.
@@ -4691,6 +4767,7 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 	cacheMode := "none" // Bypass host cache, use O_DIRECT semantics by default.
 	media := "disk"
 	isRBDImage := strings.HasPrefix(driveConf.DevPath, device.RBDFormatPrefix)
+	isNetworkImage := internalInstance.IsNetworkDiskSource(driveConf.DevPath)
 
 	// Check supported features.
 	// Use io_uring over native for added performance (if supported by QEMU and kernel is recent enough).
@@ -4709,6 +4786,9 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 	if isRBDImage {
 		// For RBD, we want writeback to allow for the system-configured "rbd cache" to take effect if present.
 		cacheMode = "writeback"
+	} else if isNetworkImage {
+		// Network sources have no local file to use direct I/O on.
+		cacheMode = "writeback"
 	} else {
 		srcDevPath = driveConf.DevPath // This should not be used for passing to QEMU, only for probing.
 
@@ -4905,6 +4985,38 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 
 		// The aio option isn't available when using the rbd driver.
 		delete(blockDev, "aio")
+	} else if isNetworkImage {
+		src, err := device.DiskParseNetworkSource(driveConf.DevPath)
+		if err != nil {
+			return nil, err
+		}
+
+		// The protocol names match the QEMU block drivers.
+		blockDev["driver"] = src.Protocol
+
+		switch src.Protocol {
+		case "nbd":
+			blockDev["server"] = map[string]any{
+				"type": "inet",
+				"host": src.Host,
+				"port": src.Port,
+			}
+
+			if src.Export != "" {
+				blockDev["export"] = src.Export
+			}
+
+		case "iscsi":
+			blockDev["transport"] = "tcp"
+			blockDev["portal"] = net.JoinHostPort(src.Host, src.Port)
+			blockDev["target"] = src.Target
+			blockDev["lun"] = src.LUN
+		case "http", "https":
+			blockDev["url"] = src.URL
+		}
+
+		// The aio option is only available when using local files.
+		delete(blockDev, "aio")
 	}
 
 	readonly := slices.Contains(driveConf.Opts, "ro") || media == "cdrom"
@@ -4913,7 +5025,7 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 		blockDev["read-only"] = true
 	}
 
-	if !isRBDImage {
+	if !isRBDImage && !isNetworkImage {
 		blockDev["locking"] = "off"
 	}
 
@@ -4997,7 +5109,7 @@ func (d *qemu) addDriveConfig(qemuDev map[string]any, bootIndexes map[string]int
 			}
 
 			blockDev["key-secret"] = secretID
-		} else {
+		} else if !isNetworkImage {
 			permissions := unix.O_RDWR
 
 			if readonly {
@@ -5635,6 +5747,160 @@ func (d *qemu) addTPMDeviceConfig(conf *[]cfg.Section, tpmConfig []deviceConfig.
 	return nil
 }
 
+func (d *qemu) addSerialDeviceConfig(conf *[]cfg.Section, serialConfig []deviceConfig.RunConfigItem, fdFiles *[]*os.File) error {
+	opts := qemuSerialPortOpts{
+		ringbufSizeBytes: 1048576,
+	}
+
+	var listen string
+	for _, serialItem := range serialConfig {
+		switch serialItem.Key {
+		case "devName":
+			opts.devName = serialItem.Value
+		case "bus":
+			opts.bus = serialItem.Value
+		case "name":
+			opts.portName = serialItem.Value
+		case "listen":
+			listen = serialItem.Value
+		}
+	}
+
+	if opts.bus == "isa" && d.architecture != osarch.ARCH_64BIT_INTEL_X86 {
+		return fmt.Errorf("Serial device %q: The isa bus is only supported on x86_64", opts.devName)
+	}
+
+	// Without a listen address, the output is kept in a ring buffer.
+	if listen == "" {
+		*conf = append(*conf, qemuSerialPort(&opts)...)
+
+		return nil
+	}
+
+	// Create the listening socket here, which will be passed via file descriptor to qemu.
+	listenType, listenAddr, _ := strings.Cut(listen, ":")
+
+	var listenFile *os.File
+	switch listenType {
+	case "unix":
+		// Only create sockets in the instance's devices directory.
+		if filepath.Dir(listenAddr) != d.DevicesPath() {
+			return fmt.Errorf("Serial device %q: Socket %q isn't in the instance devices directory", opts.devName, listenAddr)
+		}
+
+		// Remove any leftover socket.
+		err := device.SerialSocketRemove(listenAddr)
+		if err != nil {
+			return fmt.Errorf("Serial device %q: %w", opts.devName, err)
+		}
+
+		listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: listenAddr, Net: "unix"})
+		if err != nil {
+			return fmt.Errorf("Serial device %q: Failed listening on %q: %w", opts.devName, listenAddr, err)
+		}
+
+		listener.SetUnlinkOnClose(false)
+		defer func() { _ = listener.Close() }()
+
+		// Only root can connect to the socket.
+		err = os.Chmod(listenAddr, 0o600)
+		if err != nil {
+			return err
+		}
+
+		listenFile, err = listener.File()
+		if err != nil {
+			return err
+		}
+
+	case "tcp":
+		tcpAddr, err := net.ResolveTCPAddr("tcp", listenAddr)
+		if err != nil {
+			return err
+		}
+
+		listener, err := net.ListenTCP("tcp", tcpAddr)
+		if err != nil {
+			return fmt.Errorf("Serial device %q: Failed listening on %q: %w", opts.devName, listenAddr, err)
+		}
+
+		defer func() { _ = listener.Close() }()
+
+		listenFile, err = listener.File()
+		if err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("Serial device %q: Invalid listen address %q", opts.devName, listen)
+	}
+
+	opts.listenFD = d.addFileDescriptor(fdFiles, listenFile)
+	*conf = append(*conf, qemuSerialPort(&opts)...)
+
+	return nil
+}
+
+// addWatchdogDeviceConfig adds the qemu config required for adding a watchdog device.
+func (d *qemu) addWatchdogDeviceConfig(conf *[]cfg.Section, bus *qemuBus, watchdogConfig []deviceConfig.RunConfigItem) (monitorHook, error) {
+	var devName, model, action string
+	for _, watchdogItem := range watchdogConfig {
+		switch watchdogItem.Key {
+		case "devName":
+			devName = watchdogItem.Value
+		case "model":
+			model = watchdogItem.Value
+		case "action":
+			action = watchdogItem.Value
+		}
+	}
+
+	if model == "" {
+		model = "i6300esb"
+		if d.architecture == osarch.ARCH_64BIT_S390_BIG_ENDIAN {
+			model = "diag288"
+		}
+	}
+
+	opts := qemuWatchdogOpts{
+		devName: devName,
+		model:   model,
+	}
+
+	if model == "i6300esb" {
+		devBus, devAddr, multi := bus.allocate(fmt.Sprintf("incus_%s", devName))
+		opts.dev = qemuDevOpts{
+			busName:       bus.name,
+			devBus:        devBus,
+			devAddr:       devAddr,
+			multifunction: multi,
+		}
+	}
+
+	*conf = append(*conf, qemuWatchdog(&opts)...)
+
+	// Configure what QEMU itself does when the watchdog fires.
+	// When dumping, the guest is paused so the memory can be dumped in a consistent state.
+	qemuAction := action
+	switch action {
+	case "":
+		qemuAction = "reset"
+	case "dump":
+		qemuAction = "pause"
+	}
+
+	monHook := func(m *qmp.Monitor) error {
+		err := m.SetWatchdogAction(qemuAction)
+		if err != nil {
+			return fmt.Errorf("Failed setting watchdog action: %w", err)
+		}
+
+		return nil
+	}
+
+	return monHook, nil
+}
+
 func (d *qemu) addVmgenDeviceConfig(conf *[]cfg.Section, guid string) error {
 	vmgenIDOpts := qemuVmgenIDOpts{
 		guid: guid,
@@ -10658,6 +10924,65 @@ func (d *qemu) ConsoleLog() (string, error) {
 	return string(fullLog), nil
 }
 
+// SerialLog returns all output sent to the ring buffer of a serial device since startup.
+func (d *qemu) SerialLog(devName string) (string, error) {
+	devConfig, ok := d.expandedDevices[devName]
+	if !ok || devConfig["type"] != "serial" {
+		return "", fmt.Errorf("Serial device %q not found", devName)
+	}
+
+	if devConfig["listen"] != "" {
+		return "", fmt.Errorf("Serial device %q isn't using a ring buffer", devName)
+	}
+
+	logPath := filepath.Join(d.LogPath(), fmt.Sprintf("serial.%s.log", devName))
+
+	if d.IsRunning() {
+		// Check if the agent is running.
+		monitor, err := d.qmpConnect()
+		if err != nil {
+			return "", err
+		}
+
+		logString, err := monitor.RingbufRead(qemuSerialPortChardevName(devName))
+		if err != nil {
+			// The device may have been switched to a socket since the VM was started.
+			if errors.Is(err, qmp.ErrNotARingbuf) {
+				return "", nil
+			}
+
+			return "", err
+		}
+
+		// If we got data back, append it to the log file for this device.
+		if logString != "" {
+			logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
+			if err != nil {
+				return "", err
+			}
+
+			defer logFile.Close()
+
+			_, err = logFile.WriteString(logString)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// Read and return the complete log for this device.
+	fullLog, err := os.ReadFile(logPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return string(fullLog), nil
+}
+
 // consoleSwapRBWithSocket swaps the qemu backend for the instance's console to a unix socket.
 func (d *qemu) consoleSwapRBWithSocket() error {
 	// This will wipe out anything in the existing ring buffer; save any buffered data to log file first.
@@ -1090,6 +1090,90 @@ func TestQemuConfigTemplates(t *testing.T) {
 		}
 	})
 
+	t.Run("qemu_serial_port", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuSerialPortOpts
+			expected string
+		}{{
+			qemuSerialPortOpts{
+				devName:          "console1",
+				ringbufSizeBytes: 1048576,
+			},
+			`[chardev "qemu_serial-port-chardev_console1"]
+			backend = "ringbuf"
+			size = "1048576B"
+
+			[device "dev-incus_console1"]
+			bus = "dev-qemu_serial.0"
+			chardev = "qemu_serial-port-chardev_console1"
+			driver = "virtconsole"`,
+		}, {
+			qemuSerialPortOpts{
+				devName:  "mgmt",
+				portName: "org.example.mgmt",
+				listenFD: 5,
+			},
+			`[chardev "qemu_serial-port-chardev_mgmt"]
+			backend = "socket"
+			fd = "5"
+			server = "on"
+			wait = "off"
+
+			[device "dev-incus_mgmt"]
+			bus = "dev-qemu_serial.0"
+			chardev = "qemu_serial-port-chardev_mgmt"
+			driver = "virtconsole"
+			name = "org.example.mgmt"`,
+		}, {
+			qemuSerialPortOpts{
+				devName:  "ttyS1",
+				bus:      "isa",
+				listenFD: 6,
+			},
+			`[chardev "qemu_serial-port-chardev_ttyS1"]
+			backend = "socket"
+			fd = "6"
+			server = "on"
+			wait = "off"
+
+			[device "dev-incus_ttyS1"]
+			chardev = "qemu_serial-port-chardev_ttyS1"
+			driver = "isa-serial"`,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuSerialPort(&tc.opts))
+		}
+	})
+
+	t.Run("qemu_watchdog", func(t *testing.T) {
+		testCases := []struct {
+			opts     qemuWatchdogOpts
+			expected string
+		}{{
+			qemuWatchdogOpts{
+				dev:     qemuDevOpts{"pcie", "qemu_pcie5", "00.0", false},
+				devName: "wd0",
+				model:   "i6300esb",
+			},
+			`# Watchdog
+			[device "dev-incus_wd0"]
+			addr = "00.0"
+			bus = "qemu_pcie5"
+			driver = "i6300esb"`,
+		}, {
+			qemuWatchdogOpts{
+				devName: "wd0",
+				model:   "diag288",
+			},
+			`# Watchdog
+			[device "dev-incus_wd0"]
+			driver = "diag288"`,
+		}}
+		for _, tc := range testCases {
+			runTest(tc.expected, qemuWatchdog(&tc.opts))
+		}
+	})
+
 	t.Run("qemu_raw_cfg_override", func(t *testing.T) {
 		conf := []cfg.Section{{
 			Name: "global",
@@ -983,6 +983,83 @@ func qemuTPM(opts *qemuTPMOpts) []cfg.Section {
 	}}
 }
 
+type qemuSerialPortOpts struct {
+	devName          string
+	bus              string
+	portName         string
+	listenFD         int
+	ringbufSizeBytes int
+}
+
+// qemuSerialPortChardevName returns the name of the chardev backing a serial device.
+func qemuSerialPortChardevName(devName string) string {
+	return fmt.Sprintf("qemu_serial-port-chardev_%s", devName)
+}
+
+func qemuSerialPort(opts *qemuSerialPortOpts) []cfg.Section {
+	chardev := qemuSerialPortChardevName(opts.devName)
+
+	chardevEntries := map[string]string{}
+	if opts.listenFD > 0 {
+		chardevEntries["backend"] = "socket"
+		chardevEntries["fd"] = fmt.Sprintf("%d", opts.listenFD)
+		chardevEntries["server"] = "on"
+		chardevEntries["wait"] = "off"
+	} else {
+		chardevEntries["backend"] = "ringbuf"
+		chardevEntries["size"] = fmt.Sprintf("%dB", opts.ringbufSizeBytes)
+	}
+
+	deviceEntries := map[string]string{
+		"chardev": chardev,
+	}
+
+	if opts.bus == "isa" {
+		deviceEntries["driver"] = "isa-serial"
+	} else {
+		deviceEntries["driver"] = "virtconsole"
+		deviceEntries["bus"] = "dev-qemu_serial.0"
+
+		if opts.portName != "" {
+			deviceEntries["name"] = opts.portName
+		}
+	}
+
+	return []cfg.Section{{
+		Name:    fmt.Sprintf(`chardev "%s"`, chardev),
+		Entries: chardevEntries,
+	}, {
+		Name:    fmt.Sprintf(`device "%s%s"`, qemuDeviceIDPrefix, opts.devName),
+		Entries: deviceEntries,
+	}}
+}
+
+type qemuWatchdogOpts struct {
+	dev     qemuDevOpts
+	devName string
+	model   string
+}
+
+func qemuWatchdog(opts *qemuWatchdogOpts) []cfg.Section {
+	entries := map[string]string{
+		"driver": opts.model,
+	}
+
+	// The i6300esb is a PCI device, the other models sit on the system bus.
+	if opts.model == "i6300esb" {
+		entries = qemuDeviceEntries(&qemuDevEntriesOpts{
+			dev:     opts.dev,
+			pciName: "i6300esb",
+		})
+	}
+
+	return []cfg.Section{{
+		Name:    fmt.Sprintf(`device "%s%s"`, qemuDeviceIDPrefix, opts.devName),
+		Comment: "Watchdog",
+		Entries: entries,
+	}}
+}
+
 type qemuVmgenIDOpts struct {
 	guid string
 }
@@ -1634,6 +1634,17 @@ func (m *Monitor) DumpGuestMemory(path string, format string) error {
 	return m.Run("dump-guest-memory", args, &queryResp)
 }
 
+// SetWatchdogAction sets the action taken by QEMU when the guest watchdog fires.
+func (m *Monitor) SetWatchdogAction(action string) error {
+	var args struct {
+		Action string `json:"action"`
+	}
+
+	args.Action = action
+
+	return m.Run("watchdog-set-action", args, nil)
+}
+
 // SetNICLink sets the link status of the given device.
 func (m *Monitor) SetNICLink(id string, connected bool) error {
 	var args struct {
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"sort"
@@ -378,3 +379,20 @@ func hashValue(value string, maxLength int) string {
 
 	return value
 }
+
+// removeWatchdogDumps removes the guest memory dumps taken when the watchdog fired from the log directory.
+func removeWatchdogDumps(logPath string) error {
+	dumps, err := filepath.Glob(filepath.Join(logPath, "watchdog_*.dump"))
+	if err != nil {
+		return err
+	}
+
+	for _, dump := range dumps {
+		err := os.Remove(dump)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
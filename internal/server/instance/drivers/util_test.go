@@ -1,6 +1,8 @@
 package drivers
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -82,3 +84,30 @@ func TestHashValue(t *testing.T) {
 	value = hashValue("test12345678", 11)
 	assert.Equal(t, "9fvG_oTDZTF", value)
 }
+
+// Test removeWatchdogDumps.
+func TestRemoveWatchdogDumps(t *testing.T) {
+	logPath := t.TempDir()
+
+	for _, name := range []string{"watchdog_20260101T000000Z.dump", "watchdog_20260102T000000Z.dump", "qemu.log", "watchdog.dump"} {
+		err := os.WriteFile(filepath.Join(logPath, name), nil, 0o600)
+		assert.NoError(t, err)
+	}
+
+	err := removeWatchdogDumps(logPath)
+	assert.NoError(t, err)
+
+	entries, err := os.ReadDir(logPath)
+	assert.NoError(t, err)
+
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	assert.Equal(t, []string{"qemu.log", "watchdog.dump"}, names)
+
+	// Nothing to remove.
+	err = removeWatchdogDumps(logPath)
+	assert.NoError(t, err)
+}
@@ -203,6 +203,7 @@ type VM interface {
 	AgentCertificate() *x509.Certificate
 	ConsoleLog() (string, error)
 	ConsoleScreenshot(screenshotFile *os.File) error
+	SerialLog(devName string) (string, error)
 	DumpGuestMemory(w *os.File, format string) error
 }
 
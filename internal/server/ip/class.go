@@ -18,7 +18,18 @@ type Class struct {
 // ClassHTB represents htb qdisc class object.
 type ClassHTB struct {
 	Class
-	Rate string
+	Rate     string
+	Ceil     string
+	Priority uint32
+}
+
+// ClassStats represents the statistics of a qdisc class.
+type ClassStats struct {
+	Bytes      uint64
+	Packets    uint64
+	Drops      uint64
+	Overlimits uint64
+	Backlog    uint64
 }
 
 // Add adds class to a node.
@@ -59,6 +70,17 @@ func (class *ClassHTB) Add() error {
 		htbClassAttrs.Rate = uint64(rate)
 	}
 
+	if class.Ceil != "" {
+		ceil, err := units.ParseBitSizeString(class.Ceil)
+		if err != nil {
+			return fmt.Errorf("Invalid ceil %q: %w", class.Ceil, err)
+		}
+
+		htbClassAttrs.Ceil = uint64(ceil)
+	}
+
+	htbClassAttrs.Prio = class.Priority
+
 	err = netlink.ClassAdd(netlink.NewHtbClass(classAttrs, htbClassAttrs))
 	if err != nil {
 		return fmt.Errorf("Failed to add htb class: %w", err)
@@ -66,3 +88,44 @@ func (class *ClassHTB) Add() error {
 
 	return nil
 }
+
+// Stats returns the statistics of the class.
+func (class *Class) Stats() (*ClassStats, error) {
+	link, err := linkByName(class.Dev)
+	if err != nil {
+		return nil, err
+	}
+
+	handle, err := parseHandle(class.Classid)
+	if err != nil {
+		return nil, err
+	}
+
+	classes, err := netlink.ClassList(link, 0)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list classes of %q: %w", class.Dev, err)
+	}
+
+	for _, c := range classes {
+		attrs := c.Attrs()
+		if attrs.Handle != handle {
+			continue
+		}
+
+		stats := &ClassStats{}
+		if attrs.Statistics != nil && attrs.Statistics.Basic != nil {
+			stats.Bytes = attrs.Statistics.Basic.Bytes
+			stats.Packets = uint64(attrs.Statistics.Basic.Packets)
+		}
+
+		if attrs.Statistics != nil && attrs.Statistics.Queue != nil {
+			stats.Drops = uint64(attrs.Statistics.Queue.Drops)
+			stats.Overlimits = uint64(attrs.Statistics.Queue.Overlimits)
+			stats.Backlog = uint64(attrs.Statistics.Queue.Backlog)
+		}
+
+		return stats, nil
+	}
+
+	return nil, fmt.Errorf("Class %q not found on %q", class.Classid, class.Dev)
+}
@@ -18,6 +18,7 @@ type ActionPolice struct {
 	Burst uint32 // in byte
 	Mtu   uint32 // in byte
 	Drop  bool
+	Pipe  bool // Continue with the following actions when within the rate.
 }
 
 func (a *ActionPolice) toNetlink() (netlink.Action, error) {
@@ -33,6 +34,37 @@ func (a *ActionPolice) toNetlink() (netlink.Action, error) {
 		action.ExceedAction = netlink.TC_POLICE_RECLASSIFY
 	}
 
+	if a.Pipe {
+		action.NotExceedAction = netlink.TC_POLICE_PIPE
+	}
+
+	return action, nil
+}
+
+// ActionMirred represents an action of 'mirred' type, mirroring or redirecting the packets to another device.
+type ActionMirred struct {
+	Dev      string
+	Redirect bool
+}
+
+func (a *ActionMirred) toNetlink() (netlink.Action, error) {
+	link, err := linkByName(a.Dev)
+	if err != nil {
+		return nil, err
+	}
+
+	action := netlink.NewMirredAction(link.Attrs().Index)
+
+	// Redirected packets are consumed by the target device.
+	if a.Redirect {
+		return action, nil
+	}
+
+	action.MirredAction = netlink.TCA_EGRESS_MIRROR
+
+	// Let the original packet continue through the remaining actions.
+	action.Action = netlink.TC_ACT_PIPE
+
 	return action, nil
 }
 
@@ -42,6 +74,14 @@ type Filter struct {
 	Parent   string
 	Protocol string
 	Flowid   string
+	Priority uint16 // Filters with a lower priority are evaluated first.
+}
+
+// U32Key represents a match of a universal 32bit traffic control filter.
+type U32Key struct {
+	Offset int32 // In bytes from the start of the network header, must be a multiple of 4.
+	Value  uint32
+	Mask   uint32
 }
 
 // U32Filter represents universal 32bit traffic control filter.
@@ -49,6 +89,7 @@ type U32Filter struct {
 	Filter
 	Value   uint32
 	Mask    uint32
+	Keys    []U32Key // Matched instead of Value and Mask when set.
 	Actions []Action
 }
 
@@ -56,6 +97,12 @@ func parseProtocol(proto string) (uint16, error) {
 	switch proto {
 	case "all":
 		return unix.ETH_P_ALL, nil
+	case "arp":
+		return unix.ETH_P_ARP, nil
+	case "ip":
+		return unix.ETH_P_IP, nil
+	case "ipv6":
+		return unix.ETH_P_IPV6, nil
 	default:
 		return 0, fmt.Errorf("Unknown protocol %q", proto)
 	}
@@ -73,21 +120,31 @@ func (u32 *U32Filter) Add() error {
 		return err
 	}
 
+	keys := []netlink.TcU32Key{
+		{
+			Mask: u32.Mask,
+			Val:  u32.Value,
+		},
+	}
+
+	if len(u32.Keys) > 0 {
+		keys = make([]netlink.TcU32Key, 0, len(u32.Keys))
+		for _, key := range u32.Keys {
+			keys = append(keys, netlink.TcU32Key{Mask: key.Mask, Val: key.Value, Off: key.Offset})
+		}
+	}
+
 	filter := &netlink.U32{
 		FilterAttrs: netlink.FilterAttrs{
 			LinkIndex: link.Attrs().Index,
 			Protocol:  proto,
+			Priority:  u32.Priority,
 			Chain:     nil,
 		},
 		Sel: &netlink.TcU32Sel{
 			Flags: netlink.TC_U32_TERMINAL,
-			Nkeys: 1,
-			Keys: []netlink.TcU32Key{
-				{
-					Mask: u32.Mask,
-					Val:  u32.Value,
-				},
-			},
+			Nkeys: uint8(len(keys)),
+			Keys:  keys,
 		},
 	}
 
@@ -125,3 +182,46 @@ func (u32 *U32Filter) Add() error {
 
 	return nil
 }
+
+// GetFilterRedirects returns the names of the devices the filters attached to the parent of a device redirect the
+// packets to.
+func GetFilterRedirects(dev string, parent string) ([]string, error) {
+	link, err := linkByName(dev)
+	if err != nil {
+		return nil, err
+	}
+
+	parentHandle, err := parseHandle(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	filters, err := netlink.FilterList(link, parentHandle)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list filters of %q: %w", dev, err)
+	}
+
+	devNames := []string{}
+	for _, filter := range filters {
+		u32, ok := filter.(*netlink.U32)
+		if !ok {
+			continue
+		}
+
+		for _, action := range u32.Actions {
+			mirred, ok := action.(*netlink.MirredAction)
+			if !ok || mirred.MirredAction != netlink.TCA_EGRESS_REDIR {
+				continue
+			}
+
+			target, err := netlink.LinkByIndex(mirred.Ifindex)
+			if err != nil {
+				continue
+			}
+
+			devNames = append(devNames, target.Attrs().Name)
+		}
+	}
+
+	return devNames, nil
+}
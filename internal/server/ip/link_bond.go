@@ -0,0 +1,36 @@
+package ip
+
+import (
+	"github.com/vishvananda/netlink"
+)
+
+// Bond represents arguments for link of type bond.
+type Bond struct {
+	Link
+	Mode           string
+	Miimon         int
+	XmitHashPolicy string
+	LACPRate       string
+}
+
+// Add adds new virtual link.
+func (bond *Bond) Add() error {
+	attrs, err := bond.netlinkAttrs()
+	if err != nil {
+		return err
+	}
+
+	link := netlink.NewLinkBond(attrs)
+	link.Mode = netlink.StringToBondMode(bond.Mode)
+	link.Miimon = bond.Miimon
+
+	if bond.XmitHashPolicy != "" {
+		link.XmitHashPolicy = netlink.StringToBondXmitHashPolicy(bond.XmitHashPolicy)
+	}
+
+	if bond.LACPRate != "" {
+		link.LacpRate = netlink.StringToBondLacpRate(bond.LACPRate)
+	}
+
+	return bond.addLink(link)
+}
@@ -2,6 +2,7 @@ package ip
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/vishvananda/netlink"
@@ -51,3 +52,35 @@ func mapQdiscErr(err error) error {
 
 	return err
 }
+
+// GetRootQdisc returns the root qdisc of a device along with its kind.
+// The returned qdisc is nil when the device uses the default qdisc attached by the kernel.
+func GetRootQdisc(dev string) (*Qdisc, string, error) {
+	link, err := linkByName(dev)
+	if err != nil {
+		return nil, "", err
+	}
+
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed to list qdiscs of %q: %w", dev, err)
+	}
+
+	for _, qdisc := range qdiscs {
+		attrs := qdisc.Attrs()
+		if attrs.Parent != netlink.HANDLE_ROOT {
+			continue
+		}
+
+		// Default qdiscs don't have a handle.
+		if attrs.Handle == netlink.HANDLE_NONE {
+			return nil, qdisc.Type(), nil
+		}
+
+		major, minor := netlink.MajorMinor(attrs.Handle)
+
+		return &Qdisc{Dev: dev, Handle: fmt.Sprintf("%x:%x", major, minor), Parent: "root"}, qdisc.Type(), nil
+	}
+
+	return nil, "", nil
+}
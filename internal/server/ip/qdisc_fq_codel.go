@@ -0,0 +1,29 @@
+package ip
+
+import (
+	"fmt"
+
+	"github.com/vishvananda/netlink"
+)
+
+// QdiscFqCodel represents the fair queuing controlled delay qdisc object.
+type QdiscFqCodel struct {
+	Qdisc
+}
+
+// Add adds a fq_codel qdisc to a device.
+func (q *QdiscFqCodel) Add() error {
+	attrs, err := q.netlinkAttrs()
+	if err != nil {
+		return err
+	}
+
+	fqCodel := netlink.NewFqCodel(attrs)
+
+	err = netlink.QdiscAdd(fqCodel)
+	if err != nil {
+		return fmt.Errorf("Failed to add qdisc fq_codel %v: %w", fqCodel, mapQdiscErr(err))
+	}
+
+	return nil
+}
@@ -40,6 +40,7 @@ const (
 	InstanceStarted          = InstanceAction(api.EventLifecycleInstanceStarted)
 	InstanceStopped          = InstanceAction(api.EventLifecycleInstanceStopped)
 	InstanceUpdated          = InstanceAction(api.EventLifecycleInstanceUpdated)
+	InstanceWatchdog         = InstanceAction(api.EventLifecycleInstanceWatchdog)
 )
 
 // Event creates the lifecycle event for an action on an instance.
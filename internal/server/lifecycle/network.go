@@ -20,6 +20,9 @@ const (
 	NetworkDeleted = NetworkAction(api.EventLifecycleNetworkDeleted)
 	NetworkUpdated = NetworkAction(api.EventLifecycleNetworkUpdated)
 	NetworkRenamed = NetworkAction(api.EventLifecycleNetworkRenamed)
+
+	NetworkBFDSessionDown = NetworkAction(api.EventLifecycleNetworkBFDSessionDown)
+	NetworkBFDSessionUp   = NetworkAction(api.EventLifecycleNetworkBFDSessionUp)
 )
 
 // Event creates the lifecycle event for an action on a network device.
@@ -0,0 +1,28 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// NetworkFloatingIPAction represents a lifecycle event action for network floating IPs.
+type NetworkFloatingIPAction string
+
+// All supported lifecycle events for network floating IPs.
+const (
+	NetworkFloatingIPCreated = NetworkFloatingIPAction(api.EventLifecycleNetworkFloatingIPCreated)
+	NetworkFloatingIPDeleted = NetworkFloatingIPAction(api.EventLifecycleNetworkFloatingIPDeleted)
+	NetworkFloatingIPUpdated = NetworkFloatingIPAction(api.EventLifecycleNetworkFloatingIPUpdated)
+)
+
+// Event creates the lifecycle event for an action on a network floating IP.
+func (a NetworkFloatingIPAction) Event(n network, address string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "networks", n.Name(), "floating-ips", address).Project(n.Project())
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
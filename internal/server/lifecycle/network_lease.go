@@ -0,0 +1,27 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// NetworkLeaseAction represents a lifecycle event action for network DHCP leases.
+type NetworkLeaseAction string
+
+// All supported lifecycle events for network DHCP leases.
+const (
+	NetworkLeaseAcquired = NetworkLeaseAction(api.EventLifecycleNetworkLeaseAcquired)
+	NetworkLeaseReleased = NetworkLeaseAction(api.EventLifecycleNetworkLeaseReleased)
+	NetworkLeaseRenewed  = NetworkLeaseAction(api.EventLifecycleNetworkLeaseRenewed)
+)
+
+// Event creates the lifecycle event for an action on a network DHCP lease.
+func (a NetworkLeaseAction) Event(n network, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "networks", n.Name(), "leases").Project(n.Project())
+
+	return api.EventLifecycle{
+		Action:  string(a),
+		Source:  u.String(),
+		Context: ctx,
+	}
+}
@@ -0,0 +1,28 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// NetworkReservationAction represents a lifecycle event action for network DHCP reservations.
+type NetworkReservationAction string
+
+// All supported lifecycle events for network DHCP reservations.
+const (
+	NetworkReservationCreated = NetworkReservationAction(api.EventLifecycleNetworkReservationCreated)
+	NetworkReservationDeleted = NetworkReservationAction(api.EventLifecycleNetworkReservationDeleted)
+	NetworkReservationUpdated = NetworkReservationAction(api.EventLifecycleNetworkReservationUpdated)
+)
+
+// Event creates the lifecycle event for an action on a network DHCP reservation.
+func (a NetworkReservationAction) Event(n network, reservationName string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "networks", n.Name(), "reservations", reservationName).Project(n.Project())
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
@@ -23,15 +23,38 @@ func FirewallApplyACLRules(s *state.State, logger logger.Logger, aclProjectName
 	return s.Firewall.NetworkApplyACLRules(aclNet.Name, rules)
 }
 
+// firewallNamedACLRule is a firewall ACL rule along with the name of the ACL rule it was generated from.
+type firewallNamedACLRule struct {
+	firewallDrivers.ACLRule
+
+	Name string
+}
+
 // FirewallACLRules returns ACL rules for network firewall.
 func FirewallACLRules(s *state.State, aclDeviceName string, aclProjectName string, config map[string]string) ([]firewallDrivers.ACLRule, error) {
-	var dropRules []firewallDrivers.ACLRule
-	var rejectRules []firewallDrivers.ACLRule
-	var allowRules []firewallDrivers.ACLRule
-	var allowStatelessRules []firewallDrivers.ACLRule
+	namedRules, err := firewallNamedACLRules(s, aclDeviceName, aclProjectName, config)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]firewallDrivers.ACLRule, 0, len(namedRules))
+	for _, namedRule := range namedRules {
+		rules = append(rules, namedRule.ACLRule)
+	}
+
+	return rules, nil
+}
+
+// firewallNamedACLRules returns ACL rules for network firewall in the order they are applied, along with the
+// name of the ACL rule each of them was generated from.
+func firewallNamedACLRules(s *state.State, aclDeviceName string, aclProjectName string, config map[string]string) ([]firewallNamedACLRule, error) {
+	var dropRules []firewallNamedACLRule
+	var rejectRules []firewallNamedACLRule
+	var allowRules []firewallNamedACLRule
+	var allowStatelessRules []firewallNamedACLRule
 
 	// convertACLRules converts the ACL rules to Firewall ACL rules.
-	convertACLRules := func(direction string, logPrefix string, rules ...api.NetworkACLRule) error {
+	convertACLRules := func(aclID int64, aclName string, direction string, logPrefix string, rules ...api.NetworkACLRule) error {
 		for ruleIndex, rule := range rules {
 			if rule.State == "disabled" {
 				continue
@@ -47,23 +70,29 @@ func FirewallACLRules(s *state.State, aclDeviceName string, aclProjectName strin
 				DestinationPort: rule.DestinationPort,
 				ICMPType:        rule.ICMPType,
 				ICMPCode:        rule.ICMPCode,
+				Counter:         ruleCounterName(aclID, direction, ruleIndex),
 			}
 
 			if rule.State == "logged" {
 				firewallACLRule.Log = true
-				// Max 29 chars.
-				firewallACLRule.LogName = fmt.Sprintf("%s-%s-%d", logPrefix, direction, ruleIndex)
+				// Include the counter name so that the flow logs can be tied back to the ACL rule (max 63 chars).
+				firewallACLRule.LogName = fmt.Sprintf("%s-%s", logPrefix, firewallACLRule.Counter)
+			}
+
+			namedRule := firewallNamedACLRule{
+				ACLRule: firewallACLRule,
+				Name:    fmt.Sprintf("%s (%s rule %d)", aclName, direction, ruleIndex),
 			}
 
 			switch {
 			case rule.Action == "drop":
-				dropRules = append(dropRules, firewallACLRule)
+				dropRules = append(dropRules, namedRule)
 			case rule.Action == "reject":
-				rejectRules = append(rejectRules, firewallACLRule)
+				rejectRules = append(rejectRules, namedRule)
 			case rule.Action == "allow":
-				allowRules = append(allowRules, firewallACLRule)
+				allowRules = append(allowRules, namedRule)
 			case rule.Action == "allow-stateless": // TODO: add NOTRACK support
-				allowStatelessRules = append(allowStatelessRules, firewallACLRule)
+				allowStatelessRules = append(allowStatelessRules, namedRule)
 			default:
 				return fmt.Errorf("Unrecognised action %q", rule.Action)
 			}
@@ -76,12 +105,13 @@ func FirewallACLRules(s *state.State, aclDeviceName string, aclProjectName strin
 
 	// Load ACLs specified by network.
 	for _, aclName := range util.SplitNTrimSpace(config["security.acls"], ",", -1, true) {
+		var aclID int
 		var aclInfo *api.NetworkACL
 
 		err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 			var err error
 
-			_, aclInfo, err = dbCluster.GetNetworkACLAPI(ctx, tx.Tx(), aclProjectName, aclName)
+			aclID, aclInfo, err = dbCluster.GetNetworkACLAPI(ctx, tx.Tx(), aclProjectName, aclName)
 
 			return err
 		})
@@ -89,18 +119,18 @@ func FirewallACLRules(s *state.State, aclDeviceName string, aclProjectName strin
 			return nil, fmt.Errorf("Failed loading ACL %q for network %q: %w", aclName, aclDeviceName, err)
 		}
 
-		err = convertACLRules("ingress", logPrefix, aclInfo.Ingress...)
+		err = convertACLRules(int64(aclID), aclInfo.Name, "ingress", logPrefix, aclInfo.Ingress...)
 		if err != nil {
 			return nil, fmt.Errorf("Failed converting ACL %q ingress rules for network %q: %w", aclInfo.Name, aclDeviceName, err)
 		}
 
-		err = convertACLRules("egress", logPrefix, aclInfo.Egress...)
+		err = convertACLRules(int64(aclID), aclInfo.Name, "egress", logPrefix, aclInfo.Egress...)
 		if err != nil {
 			return nil, fmt.Errorf("Failed converting ACL %q egress rules for network %q: %w", aclInfo.Name, aclDeviceName, err)
 		}
 	}
 
-	var rules []firewallDrivers.ACLRule
+	var rules []firewallNamedACLRule
 	rules = append(rules, dropRules...)
 	rules = append(rules, rejectRules...)
 	rules = append(rules, allowRules...)
@@ -110,18 +140,24 @@ func FirewallACLRules(s *state.State, aclDeviceName string, aclProjectName strin
 	egressAction, egressLogged := firewallACLDefaults(config, "egress")
 	ingressAction, ingressLogged := firewallACLDefaults(config, "ingress")
 
-	rules = append(rules, firewallDrivers.ACLRule{
-		Direction: "egress",
-		Action:    egressAction,
-		Log:       egressLogged,
-		LogName:   fmt.Sprintf("%s-egress", logPrefix),
+	rules = append(rules, firewallNamedACLRule{
+		ACLRule: firewallDrivers.ACLRule{
+			Direction: "egress",
+			Action:    egressAction,
+			Log:       egressLogged,
+			LogName:   fmt.Sprintf("%s-egress", logPrefix),
+		},
+		Name: "default (egress rule)",
 	})
 
-	rules = append(rules, firewallDrivers.ACLRule{
-		Direction: "ingress",
-		Action:    ingressAction,
-		Log:       ingressLogged,
-		LogName:   fmt.Sprintf("%s-ingress", logPrefix),
+	rules = append(rules, firewallNamedACLRule{
+		ACLRule: firewallDrivers.ACLRule{
+			Direction: "ingress",
+			Action:    ingressAction,
+			Log:       ingressLogged,
+			LogName:   fmt.Sprintf("%s-ingress", logPrefix),
+		},
+		Name: "default (ingress rule)",
 	})
 
 	return rules, nil
@@ -0,0 +1,364 @@
+package acl
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/nflog"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// flowLogCacheExpiry is how long the ACL and instance details used to enrich the flow logs are cached for.
+const flowLogCacheExpiry = time.Minute
+
+// flowLogOVNRuleName matches the log name of OVN rules generated from a network ACL, made of the port group name
+// (ACL ID and port group suffix, which includes the network ID for network specific port groups), the direction
+// and the rule index.
+var flowLogOVNRuleName = regexp.MustCompile(`^` + ovnACLPortGroupPrefix + `(\d+)_(?:all|ingress|egress|ingress_reversed|egress_reversed|net(\d+))-(ingress|egress)-(\d+)$`)
+
+// flowLogFirewallRuleName matches the log name of firewall rules generated from a network ACL, made of the network
+// name (which can contain dashes) and the rule counter name.
+var flowLogFirewallRuleName = regexp.MustCompile(`^(.+)-` + ovnACLPortGroupPrefix + `(\d+)-(ingress|egress)-(\d+)$`)
+
+// flowLogNICDefaultRuleName matches the log name of the default rules of an OVN instance NIC, made of the instance
+// UUID and the NIC name.
+var flowLogNICDefaultRuleName = regexp.MustCompile(`^([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})-(.+)-(ingress|egress)$`)
+
+// flowLogDefaultRuleName matches the log name of the default rules of a network.
+var flowLogDefaultRuleName = regexp.MustCompile(`^(.+)-(ingress|egress)$`)
+
+// HostInterfaceNIC is linked from device.networkHostInterfaceNIC to tie the host side interfaces created by the
+// running local NICs back to their instance, to avoid circular imports.
+var HostInterfaceNIC func(name string) (projectName string, instName string, devName string, found bool)
+
+// flowLogName represents the details found in the log name of a rule.
+type flowLogName struct {
+	network      string
+	networkID    int64
+	aclID        int64
+	direction    string
+	rule         string
+	instanceUUID string
+	device       string
+}
+
+// flowLogParseName parses the log name of an ACL or default rule.
+// Returns false if the log name isn't one generated for network ACLs.
+func flowLogParseName(logName string) (flowLogName, bool) {
+	match := flowLogOVNRuleName.FindStringSubmatch(logName)
+	if match != nil {
+		aclID, _ := strconv.ParseInt(match[1], 10, 64)
+		networkID, _ := strconv.ParseInt(match[2], 10, 64)
+
+		return flowLogName{aclID: aclID, networkID: networkID, direction: match[3], rule: match[4]}, true
+	}
+
+	match = flowLogFirewallRuleName.FindStringSubmatch(logName)
+	if match != nil {
+		aclID, _ := strconv.ParseInt(match[2], 10, 64)
+
+		return flowLogName{network: match[1], aclID: aclID, direction: match[3], rule: match[4]}, true
+	}
+
+	match = flowLogNICDefaultRuleName.FindStringSubmatch(logName)
+	if match != nil {
+		return flowLogName{instanceUUID: match[1], device: match[2], direction: match[3], rule: "default"}, true
+	}
+
+	match = flowLogDefaultRuleName.FindStringSubmatch(logName)
+	if match != nil {
+		return flowLogName{network: match[1], direction: match[2], rule: "default"}, true
+	}
+
+	return flowLogName{}, false
+}
+
+// flowLogInstance identifies the instance NIC a flow belongs to.
+type flowLogInstance struct {
+	name    string
+	project string
+	device  string
+}
+
+// flowLogResolver caches the details needed to tie a logged flow back to its ACL rule and instance.
+type flowLogResolver struct {
+	mu        sync.Mutex
+	expiry    time.Time
+	acls      map[int64]dbCluster.NetworkACL
+	networks  map[int64]string
+	instances map[string]flowLogInstance // Keyed by MAC address and instance UUID.
+}
+
+var flowLogs = &flowLogResolver{}
+
+// load returns the cached ACLs, network names and instances, refreshing them if expired.
+func (r *flowLogResolver) load(s *state.State) (map[int64]dbCluster.NetworkACL, map[int64]string, map[string]flowLogInstance) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Now().Before(r.expiry) {
+		return r.acls, r.networks, r.instances
+	}
+
+	acls := map[int64]dbCluster.NetworkACL{}
+	networks := map[int64]string{}
+	instances := map[string]flowLogInstance{}
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbACLs, err := dbCluster.GetNetworkACLs(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, dbACL := range dbACLs {
+			acls[int64(dbACL.ID)] = dbACL
+		}
+
+		projectNetworks, err := tx.GetCreatedNetworks(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, nets := range projectNetworks {
+			for networkID, network := range nets {
+				networks[networkID] = network.Name
+			}
+		}
+
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			if inst.Config["volatile.uuid"] != "" {
+				instances[inst.Config["volatile.uuid"]] = flowLogInstance{name: inst.Name, project: inst.Project}
+			}
+
+			for devName, dev := range inst.Devices {
+				if dev["type"] != "nic" {
+					continue
+				}
+
+				instNIC := flowLogInstance{name: inst.Name, project: inst.Project, device: devName}
+
+				for _, hwaddr := range []string{dev["hwaddr"], inst.Config[fmt.Sprintf("volatile.%s.hwaddr", devName)]} {
+					mac, err := net.ParseMAC(hwaddr)
+					if err == nil {
+						instances[mac.String()] = instNIC
+					}
+				}
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		// Keep using the stale details and retry on the next flow.
+		logger.Warn("Failed loading network ACL flow log details", logger.Ctx{"err": err})
+		return r.acls, r.networks, r.instances
+	}
+
+	r.acls = acls
+	r.networks = networks
+	r.instances = instances
+	r.expiry = time.Now().Add(flowLogCacheExpiry)
+
+	return r.acls, r.networks, r.instances
+}
+
+// flowLogContext adds the ACL rule and instance details matching the log name to the flow log context and
+// returns the project the flow belongs to.
+// The instance is looked up using the MAC addresses of the sending and receiving ends of the flow and the host
+// interfaces it went through, starting with the receiving end for ingress rules.
+func flowLogContext(s *state.State, flowContext map[string]string, logName string, from []string, to []string) string {
+	acls, networks, instances := flowLogs.load(s)
+
+	projectName := ""
+
+	name, ok := flowLogParseName(logName)
+	if !ok {
+		return projectName
+	}
+
+	flowContext["direction"] = name.direction
+	flowContext["rule"] = name.rule
+
+	if name.network != "" {
+		flowContext["network"] = name.network
+	} else if name.networkID > 0 && networks[name.networkID] != "" {
+		flowContext["network"] = networks[name.networkID]
+	}
+
+	if name.rule != "default" {
+		aclInfo, ok := acls[name.aclID]
+		if ok {
+			flowContext["acl"] = aclInfo.Name
+			flowContext["project"] = aclInfo.Project
+			projectName = aclInfo.Project
+
+			rules := aclInfo.Ingress
+			if name.direction == "egress" {
+				rules = aclInfo.Egress
+			}
+
+			ruleIndex, _ := strconv.Atoi(name.rule)
+			if ruleIndex < len(rules) && flowContext["action"] == "" {
+				flowContext["action"] = rules[ruleIndex].Action
+			}
+		}
+	}
+
+	// The default rules of an OVN instance NIC identify the NIC they belong to.
+	if name.instanceUUID != "" {
+		inst, ok := instances[name.instanceUUID]
+		if ok {
+			inst.device = name.device
+			flowLogSetInstance(flowContext, inst)
+
+			if projectName == "" {
+				projectName = inst.project
+			}
+
+			return projectName
+		}
+	}
+
+	lookups := append(from, to...)
+	if name.direction == "ingress" {
+		lookups = append(to, from...)
+	}
+
+	for _, lookup := range lookups {
+		if lookup == "" {
+			continue
+		}
+
+		inst, ok := instances[lookup]
+		if !ok && HostInterfaceNIC != nil {
+			inst.project, inst.name, inst.device, ok = HostInterfaceNIC(lookup)
+		}
+
+		if !ok {
+			continue
+		}
+
+		flowLogSetInstance(flowContext, inst)
+
+		if projectName == "" {
+			projectName = inst.project
+		}
+
+		break
+	}
+
+	return projectName
+}
+
+// flowLogSetInstance adds the instance NIC details to the flow log context.
+func flowLogSetInstance(flowContext map[string]string, inst flowLogInstance) {
+	flowContext["instance"] = inst.name
+	flowContext["instance_project"] = inst.project
+	if inst.device != "" {
+		flowContext["device"] = inst.device
+	}
+}
+
+// FlowLogEvent returns the network ACL event for a packet logged by the firewall and the project it belongs to.
+func FlowLogEvent(s *state.State, packet *nflog.Packet) (string, api.EventLogging) {
+	flowContext := map[string]string{
+		"name":        packet.Prefix,
+		"protocol":    packet.Protocol,
+		"source":      packet.Source.String(),
+		"destination": packet.Destination.String(),
+	}
+
+	switch packet.Protocol {
+	case "tcp", "udp":
+		flowContext["source_port"] = strconv.Itoa(int(packet.SourcePort))
+		flowContext["destination_port"] = strconv.Itoa(int(packet.DestinationPort))
+	case "icmp4", "icmp6":
+		flowContext["icmp_type"] = strconv.Itoa(int(packet.ICMPType))
+		flowContext["icmp_code"] = strconv.Itoa(int(packet.ICMPCode))
+	}
+
+	// Resolve the interfaces the packet went through.
+	var inName, outName string
+
+	iface, err := net.InterfaceByIndex(packet.InIndex)
+	if err == nil {
+		inName = iface.Name
+		flowContext["in_interface"] = inName
+	}
+
+	iface, err = net.InterfaceByIndex(packet.OutIndex)
+	if err == nil {
+		outName = iface.Name
+		flowContext["out_interface"] = outName
+	}
+
+	// Traffic from the instance carries its MAC address and comes in through its host interface, traffic towards it
+	// leaves through its host interface.
+	projectName := flowLogContext(s, flowContext, packet.Prefix, []string{packet.HardwareAddress.String(), inName}, []string{outName})
+
+	event := api.EventLogging{
+		Level:   "info",
+		Message: fmt.Sprintf("Network ACL flow %s %s -> %s", packet.Protocol, flowLogAddress(flowContext, "source"), flowLogAddress(flowContext, "destination")),
+		Context: flowContext,
+	}
+
+	return projectName, event
+}
+
+// OVNFlowLogContext returns the structured context of an OVN ACL log message and the project it belongs to.
+func OVNFlowLogContext(s *state.State, message string) (string, map[string]string) {
+	aclEntry := ovnParseLogFields(message)
+	if aclEntry["name"] == "" {
+		return "", nil
+	}
+
+	flowContext := map[string]string{
+		"name":   aclEntry["name"],
+		"action": aclEntry["verdict"],
+	}
+
+	directionFields := strings.Split(aclEntry["direction"], " ")
+	if len(directionFields) == 2 {
+		flowContext["protocol"] = directionFields[1]
+	}
+
+	for key, fields := range map[string][]string{
+		"source":           {"nw_src", "ipv6_src"},
+		"destination":      {"nw_dst", "ipv6_dst"},
+		"source_port":      {"tp_src"},
+		"destination_port": {"tp_dst"},
+		"icmp_type":        {"icmp_type"},
+		"icmp_code":        {"icmp_code"},
+	} {
+		for _, field := range fields {
+			if aclEntry[field] != "" {
+				flowContext[key] = aclEntry[field]
+				break
+			}
+		}
+	}
+
+	projectName := flowLogContext(s, flowContext, aclEntry["name"], []string{aclEntry["dl_src"]}, []string{aclEntry["dl_dst"]})
+
+	return projectName, flowContext
+}
+
+// flowLogAddress returns the address and port (if any) of one end of the flow.
+func flowLogAddress(flowContext map[string]string, end string) string {
+	port := flowContext[end+"_port"]
+	if port == "" {
+		return flowContext[end]
+	}
+
+	return net.JoinHostPort(flowContext[end], port)
+}
@@ -0,0 +1,190 @@
+package acl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func Test_flowLogParseName(t *testing.T) {
+	tests := []struct {
+		name    string
+		logName string
+		want    flowLogName
+		wantOK  bool
+	}{
+		{
+			name:    "Firewall rule",
+			logName: "incusbr0-incus_acl12-ingress-3",
+			want:    flowLogName{network: "incusbr0", aclID: 12, direction: "ingress", rule: "3"},
+			wantOK:  true,
+		},
+		{
+			name:    "Firewall rule on network with dashes",
+			logName: "my-br-0-incus_acl7-egress-0",
+			want:    flowLogName{network: "my-br-0", aclID: 7, direction: "egress", rule: "0"},
+			wantOK:  true,
+		},
+		{
+			name:    "Firewall rule on network named after a direction",
+			logName: "br-ingress-incus_acl7-egress-12",
+			want:    flowLogName{network: "br-ingress", aclID: 7, direction: "egress", rule: "12"},
+			wantOK:  true,
+		},
+		{
+			name:    "OVN rule in all port group",
+			logName: "incus_acl12_all-ingress-0",
+			want:    flowLogName{aclID: 12, direction: "ingress", rule: "0"},
+			wantOK:  true,
+		},
+		{
+			name:    "OVN rule in reversed port group",
+			logName: "incus_acl3_ingress_reversed-egress-2",
+			want:    flowLogName{aclID: 3, direction: "egress", rule: "2"},
+			wantOK:  true,
+		},
+		{
+			name:    "OVN rule in network port group",
+			logName: "incus_acl3_net15-egress-1",
+			want:    flowLogName{aclID: 3, networkID: 15, direction: "egress", rule: "1"},
+			wantOK:  true,
+		},
+		{
+			name:    "OVN instance NIC default rule",
+			logName: "0e1f6ee5-7a0a-4d8c-9a49-9a1f6f6e9b52-eth-0-ingress",
+			want:    flowLogName{instanceUUID: "0e1f6ee5-7a0a-4d8c-9a49-9a1f6f6e9b52", device: "eth-0", direction: "ingress", rule: "default"},
+			wantOK:  true,
+		},
+		{
+			name:    "Network default rule",
+			logName: "my-br-egress",
+			want:    flowLogName{network: "my-br", direction: "egress", rule: "default"},
+			wantOK:  true,
+		},
+		{
+			name:    "Unknown port group suffix",
+			logName: "incus_acl3_foo-egress-1",
+			wantOK:  false,
+		},
+		{
+			name:    "Unknown prefix",
+			logName: "something-else",
+			wantOK:  false,
+		},
+		{
+			name:    "Missing direction",
+			logName: "incus_acl3_all-0",
+			wantOK:  false,
+		},
+		{
+			name:    "Empty",
+			logName: "",
+			wantOK:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := flowLogParseName(tt.logName)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_flowLogContext(t *testing.T) {
+	flowLogs = &flowLogResolver{
+		expiry: time.Now().Add(time.Hour),
+		acls: map[int64]dbCluster.NetworkACL{
+			12: {
+				Project: "foo",
+				Name:    "web",
+				Ingress: []api.NetworkACLRule{{Action: "allow"}, {Action: "drop"}},
+				Egress:  []api.NetworkACLRule{{Action: "reject"}},
+			},
+		},
+		networks: map[int64]string{15: "ovn-net"},
+		instances: map[string]flowLogInstance{
+			"0e1f6ee5-7a0a-4d8c-9a49-9a1f6f6e9b52": {name: "c1", project: "foo"},
+			"00:16:3e:00:00:01":                    {name: "c1", project: "foo", device: "eth0"},
+		},
+	}
+
+	HostInterfaceNIC = func(name string) (string, string, string, bool) {
+		if name == "veth1234" {
+			return "bar", "c2", "eth1", true
+		}
+
+		return "", "", "", false
+	}
+
+	defer func() {
+		flowLogs = &flowLogResolver{}
+		HostInterfaceNIC = nil
+	}()
+
+	tests := []struct {
+		name        string
+		logName     string
+		from        []string
+		to          []string
+		wantProject string
+		want        map[string]string
+	}{
+		{
+			name:        "Firewall rule towards host interface",
+			logName:     "my-br-incus_acl12-ingress-1",
+			from:        []string{"00:16:3e:00:00:02", "eth0"},
+			to:          []string{"veth1234"},
+			wantProject: "foo",
+			want:        map[string]string{"network": "my-br", "acl": "web", "project": "foo", "direction": "ingress", "rule": "1", "action": "drop", "instance": "c2", "instance_project": "bar", "device": "eth1"},
+		},
+		{
+			name:        "OVN network rule from MAC",
+			logName:     "incus_acl12_net15-egress-0",
+			from:        []string{"00:16:3e:00:00:01"},
+			to:          []string{"00:16:3e:00:00:02"},
+			wantProject: "foo",
+			want:        map[string]string{"network": "ovn-net", "acl": "web", "project": "foo", "direction": "egress", "rule": "0", "action": "reject", "instance": "c1", "instance_project": "foo", "device": "eth0"},
+		},
+		{
+			name:        "OVN instance NIC default rule",
+			logName:     "0e1f6ee5-7a0a-4d8c-9a49-9a1f6f6e9b52-eth-0-ingress",
+			from:        []string{"00:16:3e:00:00:02"},
+			to:          []string{"00:16:3e:00:00:01"},
+			wantProject: "foo",
+			want:        map[string]string{"direction": "ingress", "rule": "default", "instance": "c1", "instance_project": "foo", "device": "eth-0"},
+		},
+		{
+			name:        "Network default rule from host interface",
+			logName:     "incusbr0-egress",
+			from:        []string{"00:16:3e:00:00:03", "veth1234"},
+			wantProject: "bar",
+			want:        map[string]string{"network": "incusbr0", "direction": "egress", "rule": "default", "instance": "c2", "instance_project": "bar", "device": "eth1"},
+		},
+		{
+			name:    "Unknown ACL",
+			logName: "incusbr0-incus_acl99-egress-0",
+			want:    map[string]string{"network": "incusbr0", "direction": "egress", "rule": "0"},
+		},
+		{
+			name:    "Unknown prefix",
+			logName: "something-else",
+			from:    []string{"00:16:3e:00:00:01"},
+			want:    map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flowContext := map[string]string{}
+			projectName := flowLogContext(nil, flowContext, tt.logName, tt.from, tt.to)
+			assert.Equal(t, tt.wantProject, projectName)
+			assert.Equal(t, tt.want, flowContext)
+		})
+	}
+}
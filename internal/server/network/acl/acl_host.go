@@ -0,0 +1,185 @@
+package acl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/ports"
+	"github.com/lxc/incus/v6/internal/server/db"
+	firewallDrivers "github.com/lxc/incus/v6/internal/server/firewall/drivers"
+	addressset "github.com/lxc/incus/v6/internal/server/network/address-set"
+	"github.com/lxc/incus/v6/internal/server/state"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// HostUsesACL returns whether the ACL of the project is one of the ACLs protecting the listeners of the server.
+func HostUsesACL(s *state.State, projectName string, aclName string) bool {
+	if projectName != api.ProjectDefaultName {
+		return false
+	}
+
+	aclNames, _, _ := s.GlobalConfig.NetworkHostACLs()
+
+	return slices.Contains(aclNames, aclName)
+}
+
+// FirewallApplyHostACLRules applies the ingress rules of the ACLs set in "network.host.acls" to the traffic sent to
+// the listeners of this server, or clears them if no ACL is set.
+func FirewallApplyHostACLRules(s *state.State) error {
+	aclNames, defaultAction, defaultLogged := s.GlobalConfig.NetworkHostACLs()
+	if len(aclNames) == 0 {
+		return s.Firewall.HostClearACLRules()
+	}
+
+	err := Exists(s, api.ProjectDefaultName, aclNames...)
+	if err != nil {
+		return err
+	}
+
+	err = addressset.FirewallApplyAddressSetsForACLRules(s, "inet", api.ProjectDefaultName, aclNames)
+	if err != nil {
+		return err
+	}
+
+	rules, err := FirewallACLRules(s, "host", api.ProjectDefaultName, map[string]string{
+		"security.acls":                        strings.Join(aclNames, ","),
+		"security.acls.default.ingress.action": defaultAction,
+		"security.acls.default.ingress.logged": strconv.FormatBool(defaultLogged),
+	})
+	if err != nil {
+		return err
+	}
+
+	trustedAddresses, err := hostTrustedAddresses(s)
+	if err != nil {
+		return err
+	}
+
+	return s.Firewall.HostApplyACLRules(hostListeners(s), trustedAddresses, rules)
+}
+
+// HostValidateACLs checks the ACLs can be used to protect the listeners of this server.
+func HostValidateACLs(s *state.State, aclNames []string) error {
+	if len(aclNames) == 0 {
+		return nil
+	}
+
+	if s.Firewall.String() != "nftables" {
+		return errors.New("Host ACLs require the nftables firewall driver")
+	}
+
+	return Exists(s, api.ProjectDefaultName, aclNames...)
+}
+
+// hostTrustedAddresses returns the addresses of the cluster members, whose traffic is always allowed by the host ACLs
+// so that they can't cut off the communication within the cluster.
+func hostTrustedAddresses(s *state.State) ([]net.IP, error) {
+	if !s.ServerClustered {
+		return nil, nil
+	}
+
+	var memberAddresses []string
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		members, err := tx.GetNodes(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			memberAddresses = append(memberAddresses, member.Address)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading cluster members: %w", err)
+	}
+
+	return hostParseAddresses(memberAddresses), nil
+}
+
+// hostParseAddresses returns the IP addresses of the hosts of the given network addresses, resolving the host names.
+func hostParseAddresses(addresses []string) []net.IP {
+	ips := []net.IP{}
+
+	for _, address := range addresses {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+
+		hostIPs := []net.IP{net.ParseIP(host)}
+		if hostIPs[0] == nil {
+			hostIPs, err = net.LookupIP(host)
+			if err != nil {
+				continue
+			}
+		}
+
+		for _, ip := range hostIPs {
+			if !slices.ContainsFunc(ips, ip.Equal) {
+				ips = append(ips, ip)
+			}
+		}
+	}
+
+	return ips
+}
+
+// hostListeners returns the listeners of this server protected by the host ACLs.
+func hostListeners(s *state.State) []firewallDrivers.HostListener {
+	addresses := []struct {
+		address     string
+		defaultPort int
+		protocols   []string
+	}{
+		{address: s.LocalConfig.HTTPSAddress(), defaultPort: ports.HTTPSDefaultPort, protocols: []string{"tcp"}},
+		{address: s.LocalConfig.ClusterAddress(), defaultPort: ports.HTTPSDefaultPort, protocols: []string{"tcp"}},
+		{address: s.LocalConfig.MetricsAddress(), defaultPort: ports.HTTPSMetricsDefaultPort, protocols: []string{"tcp"}},
+		{address: s.LocalConfig.StorageBucketsAddress(), defaultPort: ports.HTTPSStorageBucketsDefaultPort, protocols: []string{"tcp"}},
+		{address: s.LocalConfig.BGPAddress(), defaultPort: ports.BGPDefaultPort, protocols: []string{"tcp"}},
+		{address: s.LocalConfig.DNSAddress(), defaultPort: ports.DNSDefaultPort, protocols: []string{"tcp", "udp"}},
+	}
+
+	listeners := []firewallDrivers.HostListener{}
+	for _, entry := range addresses {
+		if entry.address == "" {
+			continue
+		}
+
+		address := internalUtil.CanonicalNetworkAddress(entry.address, entry.defaultPort)
+
+		host, portStr, err := net.SplitHostPort(address)
+		if err != nil {
+			continue
+		}
+
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil {
+			continue
+		}
+
+		for _, protocol := range entry.protocols {
+			listener := firewallDrivers.HostListener{
+				Protocol: protocol,
+				Address:  net.ParseIP(host),
+				Port:     port,
+			}
+
+			if !slices.ContainsFunc(listeners, func(l firewallDrivers.HostListener) bool {
+				return l.Protocol == listener.Protocol && l.Port == listener.Port && l.Address.Equal(listener.Address)
+			}) {
+				listeners = append(listeners, listener)
+			}
+		}
+	}
+
+	return listeners
+}
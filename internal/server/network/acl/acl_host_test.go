@@ -0,0 +1,21 @@
+package acl
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_hostParseAddresses(t *testing.T) {
+	addresses := []string{
+		"192.0.2.1:8443",
+		"[2001:db8::1]:8443",
+		"192.0.2.2",
+		"192.0.2.1:9443",
+	}
+
+	want := []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.2")}
+	assert.Equal(t, want, hostParseAddresses(addresses))
+	assert.Empty(t, hostParseAddresses(nil))
+}
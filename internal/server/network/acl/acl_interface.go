@@ -21,6 +21,9 @@ type NetworkACL interface {
 	// GetLog.
 	GetLog(clientType request.ClientType) (string, error)
 
+	// GetState.
+	GetState(clientType request.ClientType) (*api.NetworkACLState, error)
+
 	// Internal validation.
 	validateName(name string) error
 	validateConfig(config *api.NetworkACLPut) error
@@ -572,6 +572,8 @@ func ovnApplyToPortGroup(s *state.State, l logger.Logger, client *ovn.NB, aclInf
 				return err
 			}
 
+			ovnACLRule.Counter = ruleCounterName(aclNameIDs[aclInfo.Name], direction, ruleIndex)
+
 			if rule.State == "logged" {
 				ovnACLRule.Log = true
 				ovnACLRule.LogName = fmt.Sprintf("%s-%s-%d", portGroupName, direction, ruleIndex)
@@ -1344,15 +1346,7 @@ func ovnParseLogEntry(input string, prefix string) string {
 	}
 
 	// Parse the ACL log entry.
-	aclEntry := map[string]string{}
-	for _, entry := range util.SplitNTrimSpace(fields[4], ",", -1, true) {
-		pair := strings.Split(entry, "=")
-		if len(pair) != 2 {
-			continue
-		}
-
-		aclEntry[strings.Trim(pair[0], "\"")] = strings.Trim(pair[1], "\"")
-	}
+	aclEntry := ovnParseLogFields(fields[4])
 
 	// Filter for our ACL.
 	if !strings.HasPrefix(aclEntry["name"], prefix) {
@@ -1420,6 +1414,21 @@ func ovnParseLogEntry(input string, prefix string) string {
 	return string(out)
 }
 
+// ovnParseLogFields parses the key/value pairs of an OVN ACL log message.
+func ovnParseLogFields(message string) map[string]string {
+	aclEntry := map[string]string{}
+	for _, entry := range util.SplitNTrimSpace(message, ",", -1, true) {
+		pair := strings.Split(entry, "=")
+		if len(pair) != 2 {
+			continue
+		}
+
+		aclEntry[strings.Trim(pair[0], "\"")] = strings.Trim(pair[1], "\"")
+	}
+
+	return aclEntry
+}
+
 func addPortGroupDefaultAction(portGroupName ovn.OVNPortGroup, portGroupRules []ovn.OVNACLRule) []ovn.OVNACLRule {
 	// Add default rule to port group ACL.
 	// This is a failsafe to drop unmatched traffic if the per-NIC default rule has unexpectedly not kicked in.
@@ -0,0 +1,266 @@
+package acl
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	firewallDrivers "github.com/lxc/incus/v6/internal/server/firewall/drivers"
+	addressset "github.com/lxc/incus/v6/internal/server/network/address-set"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// FirewallTracePacket describes a packet evaluated against the firewall ACL rules of a network.
+type FirewallTracePacket struct {
+	Direction       string
+	Source          net.IP
+	Destination     net.IP
+	Protocol        string
+	SourcePort      uint64
+	DestinationPort uint64
+	ICMPType        string
+	ICMPCode        string
+}
+
+// FirewallTraceResult describes the firewall ACL rule matching a traced packet.
+type FirewallTraceResult struct {
+	Rule        firewallDrivers.ACLRule
+	Name        string
+	Description string
+	Verdict     string
+}
+
+// FirewallTraceRules evaluates the firewall ACL rules generated from the config, in the order the firewall applies
+// them, and returns the rule matching the packet.
+// Address set references in the rule subjects are resolved within the ACL project.
+func FirewallTraceRules(s *state.State, aclDeviceName string, aclProjectName string, config map[string]string, packet FirewallTracePacket) (*FirewallTraceResult, error) {
+	rules, err := firewallNamedACLRules(s, aclDeviceName, aclProjectName, config)
+	if err != nil {
+		return nil, err
+	}
+
+	addressSets := map[string][]string{}
+
+	// resolveAddressSet returns the addresses of an address set.
+	resolveAddressSet := func(setName string) ([]string, error) {
+		addresses, ok := addressSets[setName]
+		if !ok {
+			addrSet, err := addressset.LoadByName(s, aclProjectName, setName)
+			if err != nil {
+				return nil, fmt.Errorf("Failed loading address set %q: %w", setName, err)
+			}
+
+			addresses = addressset.Addresses(addrSet.Info())
+			addressSets[setName] = addresses
+		}
+
+		return addresses, nil
+	}
+
+	return firewallTraceMatch(config, rules, resolveAddressSet, packet)
+}
+
+// firewallTraceMatch returns the first rule matching the packet. If none do, the default action of the packet's
+// direction from the config is returned.
+func firewallTraceMatch(config map[string]string, rules []firewallNamedACLRule, resolveAddressSet func(setName string) ([]string, error), packet FirewallTracePacket) (*FirewallTraceResult, error) {
+	// resolveSubject expands the address set references of a rule subject.
+	resolveSubject := func(subject string) ([]string, error) {
+		var entries []string
+
+		for _, entry := range util.SplitNTrimSpace(subject, ",", -1, true) {
+			setName, ok := strings.CutPrefix(entry, "$")
+			if !ok {
+				entries = append(entries, entry)
+				continue
+			}
+
+			addresses, err := resolveAddressSet(setName)
+			if err != nil {
+				return nil, err
+			}
+
+			entries = append(entries, addresses...)
+		}
+
+		return entries, nil
+	}
+
+	for _, rule := range rules {
+		if rule.Direction != packet.Direction {
+			continue
+		}
+
+		if !firewallTraceProtocolMatch(rule.ACLRule, packet) {
+			continue
+		}
+
+		if rule.Source != "" {
+			entries, err := resolveSubject(rule.Source)
+			if err != nil {
+				return nil, err
+			}
+
+			if !firewallTraceSubjectMatch(entries, packet.Source) {
+				continue
+			}
+		}
+
+		if rule.Destination != "" {
+			entries, err := resolveSubject(rule.Destination)
+			if err != nil {
+				return nil, err
+			}
+
+			if !firewallTraceSubjectMatch(entries, packet.Destination) {
+				continue
+			}
+		}
+
+		return firewallTraceResult(rule), nil
+	}
+
+	action, logged := firewallACLDefaults(config, packet.Direction)
+
+	return firewallTraceResult(firewallNamedACLRule{
+		ACLRule: firewallDrivers.ACLRule{Direction: packet.Direction, Action: action, Log: logged},
+		Name:    fmt.Sprintf("default (%s rule)", packet.Direction),
+	}), nil
+}
+
+// firewallTraceResult returns the trace result of a matching rule.
+func firewallTraceResult(rule firewallNamedACLRule) *FirewallTraceResult {
+	result := &FirewallTraceResult{
+		Rule:        rule.ACLRule,
+		Name:        rule.Name,
+		Description: firewallTraceRuleDescription(rule.ACLRule),
+		Verdict:     rule.Action,
+	}
+
+	if rule.Action == "allow-stateless" {
+		result.Verdict = "allow"
+	}
+
+	return result
+}
+
+// firewallTraceProtocolMatch returns true if the protocol, ports and ICMP criteria of the rule match the packet.
+func firewallTraceProtocolMatch(rule firewallDrivers.ACLRule, packet FirewallTracePacket) bool {
+	if rule.Protocol == "" {
+		return true
+	}
+
+	if rule.Protocol != packet.Protocol {
+		return false
+	}
+
+	if slices.Contains([]string{"tcp", "udp"}, rule.Protocol) {
+		if rule.SourcePort != "" && !firewallTracePortMatch(rule.SourcePort, packet.SourcePort) {
+			return false
+		}
+
+		if rule.DestinationPort != "" && !firewallTracePortMatch(rule.DestinationPort, packet.DestinationPort) {
+			return false
+		}
+
+		return true
+	}
+
+	if rule.ICMPType != "" && rule.ICMPType != packet.ICMPType {
+		return false
+	}
+
+	if rule.ICMPCode != "" && rule.ICMPCode != packet.ICMPCode {
+		return false
+	}
+
+	return true
+}
+
+// firewallTracePortMatch returns true if the port is part of the comma separated list of ports and port ranges.
+func firewallTracePortMatch(ports string, port uint64) bool {
+	for _, entry := range util.SplitNTrimSpace(ports, ",", -1, true) {
+		start, end, found := strings.Cut(entry, "-")
+		if !found {
+			end = start
+		}
+
+		startPort, err := strconv.ParseUint(start, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		endPort, err := strconv.ParseUint(end, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if port >= startPort && port <= endPort {
+			return true
+		}
+	}
+
+	return false
+}
+
+// firewallTraceSubjectMatch returns true if the address is part of one of the IPs, CIDR subnets or IP ranges.
+func firewallTraceSubjectMatch(entries []string, address net.IP) bool {
+	for _, entry := range entries {
+		start, end, found := strings.Cut(entry, "-")
+		if found {
+			startIP := net.ParseIP(start)
+			endIP := net.ParseIP(end)
+			if startIP == nil || endIP == nil || (startIP.To4() == nil) != (address.To4() == nil) {
+				continue
+			}
+
+			if slices.Compare(address.To16(), startIP.To16()) >= 0 && slices.Compare(address.To16(), endIP.To16()) <= 0 {
+				return true
+			}
+
+			continue
+		}
+
+		_, subnet, err := net.ParseCIDR(entry)
+		if err == nil {
+			if subnet.Contains(address) {
+				return true
+			}
+
+			continue
+		}
+
+		ip := net.ParseIP(entry)
+		if ip != nil && ip.Equal(address) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// firewallTraceRuleDescription returns a human readable representation of the firewall ACL rule criteria.
+func firewallTraceRuleDescription(rule firewallDrivers.ACLRule) string {
+	parts := []string{fmt.Sprintf("action=%s", rule.Action)}
+
+	for _, field := range []struct {
+		key   string
+		value string
+	}{
+		{"source", rule.Source},
+		{"destination", rule.Destination},
+		{"protocol", rule.Protocol},
+		{"source_port", rule.SourcePort},
+		{"destination_port", rule.DestinationPort},
+		{"icmp_type", rule.ICMPType},
+		{"icmp_code", rule.ICMPCode},
+	} {
+		if field.value != "" {
+			parts = append(parts, fmt.Sprintf("%s=%s", field.key, field.value))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,144 @@
+package acl
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	firewallDrivers "github.com/lxc/incus/v6/internal/server/firewall/drivers"
+)
+
+func Test_firewallTraceMatch(t *testing.T) {
+	rules := []firewallNamedACLRule{
+		{
+			ACLRule: firewallDrivers.ACLRule{Direction: "ingress", Action: "drop", Source: "$blocked"},
+			Name:    "web (ingress rule 1)",
+		},
+		{
+			ACLRule: firewallDrivers.ACLRule{Direction: "ingress", Action: "allow", Protocol: "tcp", DestinationPort: "80,443,8000-8080"},
+			Name:    "web (ingress rule 0)",
+		},
+		{
+			ACLRule: firewallDrivers.ACLRule{Direction: "ingress", Action: "allow-stateless", Protocol: "icmp4", ICMPType: "8"},
+			Name:    "web (ingress rule 2)",
+		},
+		{
+			ACLRule: firewallDrivers.ACLRule{Direction: "egress", Action: "allow", Destination: "10.0.0.0/8,192.0.2.1-192.0.2.10"},
+			Name:    "web (egress rule 0)",
+		},
+	}
+
+	addressSets := map[string][]string{
+		"blocked": {"198.51.100.0/24"},
+	}
+
+	resolveAddressSet := func(setName string) ([]string, error) {
+		addresses, ok := addressSets[setName]
+		if !ok {
+			return nil, errors.New("Address set not found")
+		}
+
+		return addresses, nil
+	}
+
+	config := map[string]string{
+		"security.acls.default.egress.action": "drop",
+	}
+
+	tests := []struct {
+		name        string
+		packet      FirewallTracePacket
+		wantName    string
+		wantVerdict string
+	}{
+		{
+			name:        "Address set match",
+			packet:      FirewallTracePacket{Direction: "ingress", Source: net.ParseIP("198.51.100.10"), Destination: net.ParseIP("10.0.0.2"), Protocol: "tcp", DestinationPort: 443},
+			wantName:    "web (ingress rule 1)",
+			wantVerdict: "drop",
+		},
+		{
+			name:        "Port list match",
+			packet:      FirewallTracePacket{Direction: "ingress", Source: net.ParseIP("203.0.113.10"), Destination: net.ParseIP("10.0.0.2"), Protocol: "tcp", DestinationPort: 443},
+			wantName:    "web (ingress rule 0)",
+			wantVerdict: "allow",
+		},
+		{
+			name:        "Port range match",
+			packet:      FirewallTracePacket{Direction: "ingress", Source: net.ParseIP("203.0.113.10"), Destination: net.ParseIP("10.0.0.2"), Protocol: "tcp", DestinationPort: 8008},
+			wantName:    "web (ingress rule 0)",
+			wantVerdict: "allow",
+		},
+		{
+			name:        "Stateless allow",
+			packet:      FirewallTracePacket{Direction: "ingress", Source: net.ParseIP("203.0.113.10"), Destination: net.ParseIP("10.0.0.2"), Protocol: "icmp4", ICMPType: "8", ICMPCode: "0"},
+			wantName:    "web (ingress rule 2)",
+			wantVerdict: "allow",
+		},
+		{
+			name:        "Default ingress action",
+			packet:      FirewallTracePacket{Direction: "ingress", Source: net.ParseIP("203.0.113.10"), Destination: net.ParseIP("10.0.0.2"), Protocol: "udp", DestinationPort: 53},
+			wantName:    "default (ingress rule)",
+			wantVerdict: "reject",
+		},
+		{
+			name:        "IP range match",
+			packet:      FirewallTracePacket{Direction: "egress", Source: net.ParseIP("10.0.0.2"), Destination: net.ParseIP("192.0.2.5"), Protocol: "udp", DestinationPort: 53},
+			wantName:    "web (egress rule 0)",
+			wantVerdict: "allow",
+		},
+		{
+			name:        "Configured default egress action",
+			packet:      FirewallTracePacket{Direction: "egress", Source: net.ParseIP("10.0.0.2"), Destination: net.ParseIP("192.0.2.11"), Protocol: "udp", DestinationPort: 53},
+			wantName:    "default (egress rule)",
+			wantVerdict: "drop",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := firewallTraceMatch(config, rules, resolveAddressSet, tt.packet)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, result.Name)
+			assert.Equal(t, tt.wantVerdict, result.Verdict)
+		})
+	}
+}
+
+func Test_firewallTraceMatchAddressSetError(t *testing.T) {
+	rules := []firewallNamedACLRule{
+		{
+			ACLRule: firewallDrivers.ACLRule{Direction: "ingress", Action: "drop", Source: "$missing"},
+			Name:    "web (ingress rule 0)",
+		},
+	}
+
+	resolveAddressSet := func(setName string) ([]string, error) {
+		return nil, errors.New("Address set not found")
+	}
+
+	_, err := firewallTraceMatch(nil, rules, resolveAddressSet, FirewallTracePacket{Direction: "ingress", Source: net.ParseIP("203.0.113.10"), Destination: net.ParseIP("10.0.0.2")})
+	assert.Error(t, err)
+}
+
+func Test_firewallTraceSubjectMatch(t *testing.T) {
+	entries := []string{"192.0.2.1", "198.51.100.0/24", "2001:db8::1-2001:db8::ff"}
+
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{address: "192.0.2.1", want: true},
+		{address: "192.0.2.2", want: false},
+		{address: "198.51.100.200", want: true},
+		{address: "2001:db8::10", want: true},
+		{address: "2001:db8::100", want: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, firewallTraceSubjectMatch(entries, net.ParseIP(tt.address)), tt.address)
+	}
+}
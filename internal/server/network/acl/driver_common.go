@@ -132,6 +132,14 @@ func (d *common) Info() *api.NetworkACL {
 func (d *common) usedBy(firstOnly bool) ([]string, error) {
 	usedBy := []string{}
 
+	// Check whether the ACL protects the listeners of the servers.
+	if HostUsesACL(d.state, d.projectName, d.info.Name) {
+		usedBy = append(usedBy, fmt.Sprintf("/%s", version.APIVersion))
+		if firstOnly {
+			return usedBy, nil
+		}
+	}
+
 	// Find all networks, profiles and instance NICs that use this Network ACL.
 	err := UsedBy(d.state, d.projectName, func(ctx context.Context, tx *db.ClusterTx, _ []string, usageType any, _ string, _ map[string]string) error {
 		switch u := usageType.(type) {
@@ -692,6 +700,15 @@ func (d *common) Update(config *api.NetworkACLPut, clientType request.ClientType
 		}
 	}
 
+	// Apply ACL changes to the listeners of this member.
+	hostUsed := HostUsesACL(d.state, d.projectName, d.info.Name)
+	if hostUsed {
+		err = FirewallApplyHostACLRules(d.state)
+		if err != nil {
+			return fmt.Errorf("Failed updating host ACL rules: %w", err)
+		}
+	}
+
 	// If there are affected bridge NICs, apply the ACL changes to the bridge interface filter.
 	if len(aclBridgeNICs) > 0 {
 		err = addressset.FirewallApplyAddressSetsForACLRules(d.state, "bridge", d.projectName, []string{d.info.Name})
@@ -762,8 +779,8 @@ func (d *common) Update(config *api.NetworkACLPut, clientType request.ClientType
 		}
 	}
 
-	// Apply ACL changes to non-OVN networks on cluster members.
-	if clientType == request.ClientTypeNormal && len(aclNets) > 0 {
+	// Apply ACL changes to non-OVN networks and to the listeners on cluster members.
+	if clientType == request.ClientTypeNormal && (len(aclNets) > 0 || hostUsed) {
 		// Notify all other nodes to update the network if no target specified.
 		notifier, err := cluster.NewNotifier(d.state, d.state.Endpoints.NetworkCert(), d.state.ServerCert(), cluster.NotifyAll)
 		if err != nil {
@@ -930,3 +947,166 @@ func (d *common) GetLog(clientType request.ClientType) (string, error) {
 
 	return strings.Join(logEntries, "\n") + "\n", nil
 }
+
+// ruleCounterName returns the name used to track the hit counters of an ACL rule.
+func ruleCounterName(aclID int64, direction string, ruleIndex int) string {
+	return fmt.Sprintf("%s-%s-%d", OVNACLPortGroupNamePrefix(aclID), direction, ruleIndex)
+}
+
+// GetState gets the hit counters of the ACL rules.
+func (d *common) GetState(clientType request.ClientType) (*api.NetworkACLState, error) {
+	aclState := &api.NetworkACLState{
+		Ingress: make([]api.NetworkACLRuleCounters, len(d.info.Ingress)),
+		Egress:  make([]api.NetworkACLRuleCounters, len(d.info.Egress)),
+	}
+
+	// Get the counters from this member.
+	counters, err := d.localRuleCounters()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range aclState.Ingress {
+		aclState.Ingress[i] = counters[ruleCounterName(d.id, string(ruleDirectionIngress), i)]
+	}
+
+	for i := range aclState.Egress {
+		aclState.Egress[i] = counters[ruleCounterName(d.id, string(ruleDirectionEgress), i)]
+	}
+
+	// Aggregates the counters from the rest of the cluster.
+	if clientType == request.ClientTypeNormal {
+		// Setup notifier to reach the rest of the cluster.
+		notifier, err := cluster.NewNotifier(d.state, d.state.Endpoints.NetworkCert(), d.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return nil, err
+		}
+
+		mu := sync.Mutex{}
+		err = notifier(func(client incus.InstanceServer) error {
+			memberState, err := client.UseProject(d.projectName).GetNetworkACLState(d.info.Name)
+			if err != nil {
+				return err
+			}
+
+			// Prevent concurrent writes to the counters.
+			mu.Lock()
+			defer mu.Unlock()
+
+			for i := range min(len(aclState.Ingress), len(memberState.Ingress)) {
+				aclState.Ingress[i].Packets += memberState.Ingress[i].Packets
+				aclState.Ingress[i].Bytes += memberState.Ingress[i].Bytes
+			}
+
+			for i := range min(len(aclState.Egress), len(memberState.Egress)) {
+				aclState.Egress[i].Packets += memberState.Egress[i].Packets
+				aclState.Egress[i].Bytes += memberState.Egress[i].Bytes
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return aclState, nil
+}
+
+// localRuleCounters returns the hit counters of the ACL rules applied on this member, indexed by counter name.
+func (d *common) localRuleCounters() (map[string]api.NetworkACLRuleCounters, error) {
+	counters := map[string]api.NetworkACLRuleCounters{}
+
+	// Get a list of networks that are using this ACL (either directly or indirectly via a NIC).
+	aclNets := map[string]NetworkACLUsage{}
+	err := NetworkUsage(d.state, d.projectName, []string{d.info.Name}, aclNets)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting ACL network usage: %w", err)
+	}
+
+	hasOVN := false
+	hasFirewall := false
+	for _, aclNet := range aclNets {
+		if aclNet.Type == "ovn" {
+			hasOVN = true
+		} else {
+			hasFirewall = true
+		}
+	}
+
+	// Rules applied through the firewall are tagged with their counter name.
+	if hasFirewall {
+		firewallCounters, err := d.state.Firewall.NetworkACLRuleCounters()
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting firewall ACL counters: %w", err)
+		}
+
+		prefix := OVNACLPortGroupNamePrefix(d.id) + "-"
+		for name, counter := range firewallCounters {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+
+			entry := counters[name]
+			entry.Packets += counter.Packets
+			entry.Bytes += counter.Bytes
+			counters[name] = entry
+		}
+	}
+
+	// OVN rules are tracked through the OpenFlow flows generated from them on the local chassis.
+	if hasOVN {
+		ovnnb, ovnsb, err := d.state.OVN()
+		if err != nil {
+			return nil, err
+		}
+
+		vswitch, err := d.state.OVS()
+		if err != nil {
+			return nil, err
+		}
+
+		counterNames, err := ovnnb.GetACLRuleCounterNames(context.TODO(), OVNACLPortGroupNamePrefix(d.id)+"-")
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting OVN ACL rules: %w", err)
+		}
+
+		aclUUIDs := make([]string, 0, len(counterNames))
+		for aclUUID := range counterNames {
+			aclUUIDs = append(aclUUIDs, aclUUID)
+		}
+
+		aclCookies, err := ovnsb.GetLogicalFlowCookies(context.TODO(), aclUUIDs)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting OVN logical flows: %w", err)
+		}
+
+		cookieNames := map[uint64]string{}
+		for aclUUID, cookies := range aclCookies {
+			for _, cookie := range cookies {
+				cookieNames[uint64(cookie)] = counterNames[aclUUID]
+			}
+		}
+
+		if len(cookieNames) > 0 {
+			flowCounters, err := vswitch.GetBridgeFlowCounters(context.TODO(), d.state.GlobalConfig.NetworkOVNIntegrationBridge())
+			if err != nil {
+				return nil, fmt.Errorf("Failed getting OVS flow counters: %w", err)
+			}
+
+			for cookie, counter := range flowCounters {
+				name, ok := cookieNames[cookie]
+				if !ok {
+					continue
+				}
+
+				entry := counters[name]
+				entry.Packets += counter.Packets
+				entry.Bytes += counter.Bytes
+				counters[name] = entry
+			}
+		}
+	}
+
+	return counters, nil
+}
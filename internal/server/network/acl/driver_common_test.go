@@ -0,0 +1,29 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ruleCounterName(t *testing.T) {
+	tests := []struct {
+		aclID     int64
+		direction string
+		ruleIndex int
+		want      string
+	}{
+		{aclID: 12, direction: "ingress", ruleIndex: 0, want: "incus_acl12-ingress-0"},
+		{aclID: 12, direction: "egress", ruleIndex: 3, want: "incus_acl12-egress-3"},
+		{aclID: 1, direction: "ingress", ruleIndex: 12, want: "incus_acl1-ingress-12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, ruleCounterName(tt.aclID, tt.direction, tt.ruleIndex))
+		})
+	}
+
+	// The counters of an ACL can't be mistaken for those of an ACL whose ID starts with the same digits.
+	assert.NotContains(t, ruleCounterName(123, "ingress", 0), OVNACLPortGroupNamePrefix(12)+"-")
+}
@@ -41,7 +41,7 @@ func FirewallApplyAddressSetsForACLRules(s *state.State, nftTable string, projec
 		for _, set := range apiSets {
 			firewallAddressSet := firewallDrivers.AddressSet{
 				Name:      set.Name,
-				Addresses: set.Addresses,
+				Addresses: Addresses(set),
 			}
 
 			fwSets = append(fwSets, firewallAddressSet)
@@ -87,7 +87,7 @@ func FirewallAddressSets(s *state.State, addrSetProjectName string) ([]firewallD
 		for _, set := range sets {
 			firewallAddressSet := firewallDrivers.AddressSet{
 				Name:      set.Name,
-				Addresses: set.Addresses,
+				Addresses: Addresses(set),
 			}
 
 			addressSets = append(addressSets, firewallAddressSet)
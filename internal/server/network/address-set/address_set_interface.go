@@ -24,6 +24,7 @@ type NetworkAddressSet interface {
 
 	// Modifications.
 	Update(config *api.NetworkAddressSetPut, clientType request.ClientType) error
+	update(config *api.NetworkAddressSetPut, clientType request.ClientType, selectInstances bool) error
 	Rename(newName string) error
 	Delete() error
 }
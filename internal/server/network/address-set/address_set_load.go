@@ -61,6 +61,12 @@ func Create(s *state.State, projectName string, asInfo *api.NetworkAddressSetsPo
 		return err
 	}
 
+	// Record the addresses of the instances matching the selector.
+	asInfo.Config, err = selectorUpdateConfig(s, projectName, asInfo.Config)
+	if err != nil {
+		return err
+	}
+
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Insert DB record.
 		id, err := dbCluster.CreateNetworkAddressSet(ctx, tx.Tx(), dbCluster.NetworkAddressSet{
@@ -75,7 +75,7 @@ func OVNEnsureAddressSets(s *state.State, l logger.Logger, client *ovn.NB, proje
 
 		// Convert addresses into net.IPNet slices.
 		var ipNets []net.IPNet
-		for _, addr := range asInfo.Addresses {
+		for _, addr := range Addresses(asInfo) {
 			// Try to parse as IP or CIDR.
 			if strings.Contains(addr, "/") {
 				_, ipnet, err := net.ParseCIDR(addr)
@@ -233,7 +233,7 @@ func OVNAddressSetDeleteIfUnused(s *state.State, l logger.Logger, client *ovn.NB
 
 	// Get a list of networks that indirectly reference this address set via ACLs.
 	asNets := map[string]AddressSetUsage{}
-	err = AddressSetNetworkUsage(s, projectName, setName, addrSet.Info().Addresses, asNets)
+	err = AddressSetNetworkUsage(s, projectName, setName, Addresses(addrSet.Info()), asNets)
 	if err != nil {
 		return fmt.Errorf("Failed getting address set network usage: %w", err)
 	}
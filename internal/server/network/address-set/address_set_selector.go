@@ -0,0 +1,311 @@
+package addressset
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/lxc/incus/v6/internal/server/cluster/request"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// selectorRefreshMu serializes the selector refreshes triggered by concurrent instance events.
+var selectorRefreshMu sync.Mutex
+
+// Addresses returns the addresses of the address set, including those of the instances matching its selector.
+func Addresses(info *api.NetworkAddressSet) []string {
+	addresses := slices.Clone(info.Addresses)
+
+	for _, address := range util.SplitNTrimSpace(info.Config["volatile.addresses"], ",", -1, true) {
+		if !slices.Contains(addresses, address) {
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses
+}
+
+// hasSelector returns true if the address set configuration selects instances.
+func hasSelector(config map[string]string) bool {
+	return config["selector.config"] != "" || config["selector.profile"] != ""
+}
+
+// selectorProject returns the project whose instances are selected by the address set.
+func selectorProject(projectName string, config map[string]string) string {
+	if config["selector.project"] != "" {
+		return config["selector.project"]
+	}
+
+	return projectName
+}
+
+// selectorCacheEntry holds the addresses of each instance selected by an address set, as last computed on this
+// member for the selector configuration identified by key. The entry is only valid as long as the recorded
+// addresses of the set match, as another cluster member may have updated them since.
+type selectorCacheEntry struct {
+	key       string
+	addresses string
+	instances map[string][]string
+}
+
+// selectorCache holds the selected instance addresses of the address sets, keyed by address set ID.
+// It lets instance events only re-evaluate the instance they relate to rather than listing all instances again.
+var selectorCache = map[int]*selectorCacheEntry{}
+
+// selectorCacheKey returns the key identifying the selector configuration of an address set.
+func selectorCacheKey(projectName string, config map[string]string) string {
+	return strings.Join([]string{selectorProject(projectName, config), config["selector.profile"], config["selector.config"]}, "\n")
+}
+
+// selectorMatch returns the configured and last known addresses of the NICs of the instance if it matches the
+// selector of the address set, and nil otherwise.
+func selectorMatch(config map[string]string, inst db.InstanceArgs) []string {
+	if config["selector.profile"] != "" && !slices.ContainsFunc(inst.Profiles, func(profile api.Profile) bool {
+		return profile.Name == config["selector.profile"]
+	}) {
+		return nil
+	}
+
+	expandedConfig := db.ExpandInstanceConfig(inst.Config, inst.Profiles)
+	for _, entry := range util.SplitNTrimSpace(config["selector.config"], ",", -1, true) {
+		key, value, _ := strings.Cut(entry, "=")
+		if expandedConfig[strings.TrimSpace(key)] != strings.TrimSpace(value) {
+			return nil
+		}
+	}
+
+	addresses := []string{}
+	for devName, dev := range db.ExpandInstanceDevices(inst.Devices, inst.Profiles) {
+		if dev["type"] != "nic" {
+			continue
+		}
+
+		entries := util.SplitNTrimSpace(dev["ipv4.address"], ",", -1, true)
+		entries = append(entries, util.SplitNTrimSpace(dev["ipv6.address"], ",", -1, true)...)
+		entries = append(entries, util.SplitNTrimSpace(inst.Config[fmt.Sprintf("volatile.%s.last_state.ip_addresses", devName)], ",", -1, true)...)
+
+		for _, entry := range entries {
+			ip := net.ParseIP(entry)
+			if ip == nil || slices.Contains(addresses, ip.String()) {
+				continue
+			}
+
+			addresses = append(addresses, ip.String())
+		}
+	}
+
+	return addresses
+}
+
+// selectorMergeAddresses returns the sorted and deduplicated addresses of all the selected instances.
+func selectorMergeAddresses(instances map[string][]string) []string {
+	addresses := []string{}
+	for _, instAddresses := range instances {
+		for _, address := range instAddresses {
+			if !slices.Contains(addresses, address) {
+				addresses = append(addresses, address)
+			}
+		}
+	}
+
+	slices.Sort(addresses)
+
+	return addresses
+}
+
+// selectorInstances returns the instances of the project with the given names, or all of them if no name is given.
+func selectorInstances(s *state.State, projectName string, instNames ...string) ([]db.InstanceArgs, error) {
+	var filters []dbCluster.InstanceFilter
+	for _, instName := range instNames {
+		filters = append(filters, dbCluster.InstanceFilter{Project: &projectName, Name: &instName})
+	}
+
+	if len(filters) == 0 {
+		filters = append(filters, dbCluster.InstanceFilter{Project: &projectName})
+	}
+
+	var instances []db.InstanceArgs
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			instances = append(instances, inst)
+
+			return nil
+		}, filters...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading instances matching address set selector: %w", err)
+	}
+
+	return instances, nil
+}
+
+// selectorInstanceAddresses returns the addresses of each instance matching the selector of the address set.
+func selectorInstanceAddresses(config map[string]string, instances []db.InstanceArgs) map[string][]string {
+	selected := map[string][]string{}
+	for _, inst := range instances {
+		addresses := selectorMatch(config, inst)
+		if addresses != nil {
+			selected[inst.Name] = addresses
+		}
+	}
+
+	return selected
+}
+
+// selectorUpdateConfig returns a copy of the address set configuration with the addresses of the instances
+// matching its selector recorded in "volatile.addresses".
+func selectorUpdateConfig(s *state.State, projectName string, config map[string]string) (map[string]string, error) {
+	newConfig := localUtil.CopyConfig(config)
+
+	if !hasSelector(newConfig) {
+		delete(newConfig, "volatile.addresses")
+		return newConfig, nil
+	}
+
+	instances, err := selectorInstances(s, selectorProject(projectName, newConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	selectorSetAddresses(newConfig, selectorMergeAddresses(selectorInstanceAddresses(newConfig, instances)))
+
+	return newConfig, nil
+}
+
+// selectorSetAddresses records the selected addresses in the address set configuration.
+func selectorSetAddresses(config map[string]string, addresses []string) {
+	if len(addresses) > 0 {
+		config["volatile.addresses"] = strings.Join(addresses, ",")
+	} else {
+		delete(config, "volatile.addresses")
+	}
+}
+
+// RefreshSelectors recomputes the addresses of the address sets selecting instances of the given project and
+// applies those which changed to the networks using them.
+// If instance names are given, only those instances are re-evaluated for the address sets already known to this
+// member. Otherwise all the instances of the project are.
+func RefreshSelectors(s *state.State, instProjectName string, instNames ...string) error {
+	selectorRefreshMu.Lock()
+	defer selectorRefreshMu.Unlock()
+
+	type selectorSet struct {
+		id  int
+		set *api.NetworkAddressSet
+	}
+
+	var sets []selectorSet
+	selectorSetIDs := map[int]bool{}
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbSets, err := dbCluster.GetNetworkAddressSets(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, dbSet := range dbSets {
+			set, err := dbSet.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			set.Project = dbSet.Project
+
+			if !hasSelector(set.Config) {
+				continue
+			}
+
+			selectorSetIDs[dbSet.ID] = true
+
+			if selectorProject(set.Project, set.Config) == instProjectName {
+				sets = append(sets, selectorSet{id: dbSet.ID, set: set})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading address sets: %w", err)
+	}
+
+	// Forget the address sets which were deleted or don't select instances anymore.
+	for id := range selectorCache {
+		if !selectorSetIDs[id] {
+			delete(selectorCache, id)
+		}
+	}
+
+	if len(sets) == 0 {
+		return nil
+	}
+
+	// Load the instances once for all the address sets, only listing the whole project when needed.
+	var changedInstances []db.InstanceArgs
+	var allInstances []db.InstanceArgs
+
+	if len(instNames) > 0 {
+		changedInstances, err = selectorInstances(s, instProjectName, instNames...)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range sets {
+		set := entry.set
+		key := selectorCacheKey(set.Project, set.Config)
+
+		cached := selectorCache[entry.id]
+		if cached != nil && cached.key == key && cached.addresses == set.Config["volatile.addresses"] && len(instNames) > 0 {
+			for _, instName := range instNames {
+				delete(cached.instances, instName)
+			}
+
+			for instName, addresses := range selectorInstanceAddresses(set.Config, changedInstances) {
+				cached.instances[instName] = addresses
+			}
+		} else {
+			if allInstances == nil {
+				allInstances, err = selectorInstances(s, instProjectName)
+				if err != nil {
+					return err
+				}
+			}
+
+			cached = &selectorCacheEntry{key: key, instances: selectorInstanceAddresses(set.Config, allInstances)}
+			selectorCache[entry.id] = cached
+		}
+
+		newConfig := localUtil.CopyConfig(set.Config)
+		selectorSetAddresses(newConfig, selectorMergeAddresses(cached.instances))
+		cached.addresses = newConfig["volatile.addresses"]
+
+		if newConfig["volatile.addresses"] == set.Config["volatile.addresses"] {
+			continue
+		}
+
+		addrSet, err := LoadByName(s, set.Project, set.Name)
+		if err != nil {
+			return fmt.Errorf("Failed loading address set %q: %w", set.Name, err)
+		}
+
+		err = addrSet.update(&api.NetworkAddressSetPut{
+			Addresses:   set.Addresses,
+			Config:      newConfig,
+			Description: set.Description,
+		}, request.ClientTypeNormal, false)
+		if err != nil {
+			return fmt.Errorf("Failed updating address set %q: %w", set.Name, err)
+		}
+	}
+
+	return nil
+}
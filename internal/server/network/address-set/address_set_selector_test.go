@@ -0,0 +1,111 @@
+package addressset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func Test_selectorMatch(t *testing.T) {
+	webProfile := api.Profile{
+		Name: "web",
+		ProfilePut: api.ProfilePut{
+			Config: map[string]string{"user.role": "web"},
+			Devices: map[string]map[string]string{
+				"eth1": {"type": "nic", "network": "incusbr1", "ipv4.address": "10.0.1.2"},
+			},
+		},
+	}
+
+	inst := db.InstanceArgs{
+		Name: "c1",
+		Config: map[string]string{
+			"user.tier":                             "frontend",
+			"volatile.eth0.last_state.ip_addresses": "10.0.0.2,fd00::2",
+		},
+		Devices: deviceConfig.Devices{
+			"eth0": {"type": "nic", "network": "incusbr0"},
+			"root": {"type": "disk", "path": "/", "pool": "default"},
+		},
+		Profiles: []api.Profile{webProfile},
+	}
+
+	tests := []struct {
+		name   string
+		config map[string]string
+		want   []string
+	}{
+		{
+			name:   "Profile match",
+			config: map[string]string{"selector.profile": "web"},
+			want:   []string{"10.0.0.2", "10.0.1.2", "fd00::2"},
+		},
+		{
+			name:   "Profile mismatch",
+			config: map[string]string{"selector.profile": "db"},
+			want:   nil,
+		},
+		{
+			name:   "Config match including profile config",
+			config: map[string]string{"selector.config": "user.tier=frontend, user.role=web"},
+			want:   []string{"10.0.0.2", "10.0.1.2", "fd00::2"},
+		},
+		{
+			name:   "Config mismatch",
+			config: map[string]string{"selector.config": "user.tier=backend"},
+			want:   nil,
+		},
+		{
+			name:   "Profile and config must both match",
+			config: map[string]string{"selector.profile": "web", "selector.config": "user.tier=backend"},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectorMatch(tt.config, inst)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_selectorMergeAddresses(t *testing.T) {
+	instances := map[string][]string{
+		"c1": {"10.0.0.3", "fd00::3"},
+		"c2": {"10.0.0.2", "10.0.0.3"},
+		"c3": {},
+	}
+
+	assert.Equal(t, []string{"10.0.0.2", "10.0.0.3", "fd00::3"}, selectorMergeAddresses(instances))
+	assert.Equal(t, []string{}, selectorMergeAddresses(nil))
+}
+
+func Test_selectorSetAddresses(t *testing.T) {
+	config := map[string]string{"selector.profile": "web"}
+
+	selectorSetAddresses(config, []string{"10.0.0.2", "10.0.0.3"})
+	assert.Equal(t, "10.0.0.2,10.0.0.3", config["volatile.addresses"])
+
+	selectorSetAddresses(config, []string{})
+	_, found := config["volatile.addresses"]
+	assert.False(t, found)
+}
+
+func Test_selectorCacheKey(t *testing.T) {
+	config := map[string]string{"selector.profile": "web"}
+
+	// The selected project defaults to the project of the address set.
+	assert.Equal(t, selectorCacheKey("p1", config), selectorCacheKey("p2", map[string]string{"selector.profile": "web", "selector.project": "p1"}))
+	assert.NotEqual(t, selectorCacheKey("p1", config), selectorCacheKey("p2", config))
+	assert.NotEqual(t, selectorCacheKey("p1", config), selectorCacheKey("p1", map[string]string{"selector.profile": "web", "selector.config": "user.tier=frontend"}))
+}
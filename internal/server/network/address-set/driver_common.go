@@ -19,6 +19,7 @@ import (
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/validate"
 )
 
 // common represents a network address set.
@@ -164,7 +165,44 @@ func (d *common) validateConfig(config *api.NetworkAddressSetPut) error {
 	}
 
 	// Validate the configuration.
-	configKeys := map[string]func(value string) error{}
+	configKeys := map[string]func(value string) error{
+		// gendoc:generate(entity=network_address_set, group=common, key=selector.config)
+		// Comma-separated list of `KEY=VALUE` instance configuration pairs.
+		// The addresses of the NICs of the instances matching all of them are added to the set.
+		// ---
+		//  type: string
+		//  shortdesc: Instance configuration selecting the instances whose addresses are in the set
+		"selector.config": validate.Optional(validate.IsListOf(func(value string) error {
+			key, _, found := strings.Cut(value, "=")
+			if !found || strings.TrimSpace(key) == "" {
+				return fmt.Errorf("Invalid selector %q, must be KEY=VALUE", value)
+			}
+
+			return nil
+		})),
+
+		// gendoc:generate(entity=network_address_set, group=common, key=selector.profile)
+		// The addresses of the NICs of the instances using this profile are added to the set.
+		// ---
+		//  type: string
+		//  shortdesc: Profile selecting the instances whose addresses are in the set
+		"selector.profile": validate.IsAny,
+
+		// gendoc:generate(entity=network_address_set, group=common, key=selector.project)
+		//
+		// ---
+		//  type: string
+		//  defaultdesc: Project of the address set
+		//  shortdesc: Project of the instances selected by `selector.config` and `selector.profile`
+		"selector.project": validate.IsAny,
+
+		// gendoc:generate(entity=network_address_set, group=common, key=volatile.addresses)
+		// This is managed by Incus and refreshed on instance lifecycle events.
+		// ---
+		//  type: string
+		//  shortdesc: Addresses of the instances matching the selector
+		"volatile.addresses": validate.Optional(validate.IsListOf(validate.IsNetworkAddress)),
+	}
 
 	for k, v := range config.Config {
 		// User keys are free for all.
@@ -194,6 +232,12 @@ func (d *common) validateConfig(config *api.NetworkAddressSetPut) error {
 
 // Update method is used to update an address set and apply to concerned networks.
 func (d *common) Update(config *api.NetworkAddressSetPut, clientType request.ClientType) error {
+	return d.update(config, clientType, true)
+}
+
+// update updates the address set and applies it to the concerned networks. If selectInstances is false, the
+// addresses of the selected instances provided in the config are used as is rather than being looked up again.
+func (d *common) update(config *api.NetworkAddressSetPut, clientType request.ClientType, selectInstances bool) error {
 	reverter := revert.New()
 	defer reverter.Fail()
 
@@ -204,6 +248,14 @@ func (d *common) Update(config *api.NetworkAddressSetPut, clientType request.Cli
 	}
 
 	if clientType == request.ClientTypeNormal {
+		// Record the addresses of the instances matching the selector.
+		if selectInstances {
+			config.Config, err = selectorUpdateConfig(d.state, d.projectName, config.Config)
+			if err != nil {
+				return err
+			}
+		}
+
 		var dbRecord *dbCluster.NetworkAddressSet
 		oldConfig := d.info.NetworkAddressSetPut
 
@@ -270,7 +322,7 @@ func (d *common) Update(config *api.NetworkAddressSetPut, clientType request.Cli
 
 	// Get a list of networks that indirectly reference this address set via ACLs.
 	asNets := map[string]AddressSetUsage{}
-	err = AddressSetNetworkUsage(d.state, d.projectName, d.info.Name, d.info.Addresses, asNets)
+	err = AddressSetNetworkUsage(d.state, d.projectName, d.info.Name, Addresses(d.info), asNets)
 	if err != nil {
 		return fmt.Errorf("Failed getting address set network usage: %w", err)
 	}
@@ -303,6 +355,26 @@ func (d *common) Update(config *api.NetworkAddressSetPut, clientType request.Cli
 		}
 	}
 
+	// Apply address set changes to the host ACLs protecting the listeners of this member.
+	hostUsed := false
+	if d.projectName == api.ProjectDefaultName {
+		hostACLNames, _, _ := d.state.GlobalConfig.NetworkHostACLs()
+
+		setNames, err := GetAddressSetsForACLs(d.state, d.projectName, hostACLNames)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(setNames, d.info.Name) {
+			hostUsed = true
+
+			err = FirewallApplyAddressSetsForACLRules(d.state, "inet", d.projectName, hostACLNames)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	// If there are affected OVN networks, then apply changes if request type is normal.
 	if len(asOVNNets) > 0 && clientType == request.ClientTypeNormal {
 		// Check that OVN is available.
@@ -320,8 +392,8 @@ func (d *common) Update(config *api.NetworkAddressSetPut, clientType request.Cli
 		reverter.Add(cleanup)
 	}
 
-	// If normal request and asNets is not empty, notify other cluster members.
-	if clientType == request.ClientTypeNormal && len(asNets) > 0 {
+	// If normal request and asNets is not empty or the host ACLs use the set, notify other cluster members.
+	if clientType == request.ClientTypeNormal && (len(asNets) > 0 || hostUsed) {
 		notifier, err := cluster.NewNotifier(d.state, d.state.Endpoints.NetworkCert(), d.state.ServerCert(), cluster.NotifyAll)
 		if err != nil {
 			return err
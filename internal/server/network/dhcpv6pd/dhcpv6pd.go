@@ -0,0 +1,507 @@
+// Package dhcpv6pd implements a DHCPv6 server delegating prefixes (IA_PD) to instances.
+//
+// dnsmasq doesn't support prefix delegation, so on managed bridges using it this server replaces the DHCPv6
+// server of dnsmasq (which then only sends router advertisements). It answers the stateless configuration
+// requests and delegates prefixes from a pool, routing each of them to the link-local address of the
+// requesting instance. The delegations are recorded through a Store, which allocates the prefixes.
+package dhcpv6pd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/dhcpv6/server6"
+	"github.com/insomniacslk/dhcp/iana"
+
+	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// Config represents the configuration of a prefix delegation server.
+type Config struct {
+	Pool         *net.IPNet    // Pool of prefixes to delegate from.
+	PrefixLen    int           // Length of the delegated prefixes.
+	Lifetime     time.Duration // Valid lifetime of the delegated prefixes.
+	DNSServers   []net.IP      // DNS servers sent to the clients.
+	DomainSearch []string      // DNS search domains sent to the clients.
+	Store        Store         // Store recording the delegated prefixes.
+
+	Events func(eventType string, lease Lease) // Called when a prefix is acquired, renewed or released (optional).
+}
+
+// Lease represents a delegated prefix.
+type Lease struct {
+	DUID    string
+	IAID    uint32
+	Prefix  string
+	NextHop string
+	Hwaddr  string
+	Expiry  time.Time
+}
+
+// Store records the delegated prefixes and allocates them without conflicts with the other servers
+// delegating from the same pool.
+type Store interface {
+	// Leases returns the delegations handled by the server.
+	Leases() ([]Lease, error)
+
+	// Lease returns the delegation of the client's identity association, allocating a prefix if needed.
+	// The delegation is only recorded when commit is true.
+	Lease(lease Lease, commit bool) (*Lease, error)
+
+	// Release removes the delegation of the client's identity association.
+	Release(duid string, iaid uint32) error
+}
+
+type server struct {
+	iface  string
+	config Config
+	duid   dhcpv6.DUID
+	srv    *server6.Server
+	done   chan struct{}
+
+	mu     sync.Mutex
+	leases []Lease
+}
+
+var servers = map[string]*server{}
+var serversMu sync.Mutex
+
+// Start starts a prefix delegation server on the interface, replacing any existing one.
+// The unexpired delegations of the store are routed again.
+func Start(iface string, config Config) error {
+	ones, _ := config.Pool.Mask.Size()
+	if config.PrefixLen < ones || config.PrefixLen > 128 {
+		return fmt.Errorf("Invalid delegated prefix length %d for pool %q", config.PrefixLen, config.Pool.String())
+	}
+
+	Stop(iface)
+
+	netIface, err := net.InterfaceByName(iface)
+	if err != nil {
+		return err
+	}
+
+	s := &server{
+		iface:  iface,
+		config: config,
+		duid:   &dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: netIface.HardwareAddr},
+		done:   make(chan struct{}),
+	}
+
+	leases, err := config.Store.Leases()
+	if err != nil {
+		return fmt.Errorf("Failed loading delegated prefixes: %w", err)
+	}
+
+	// Restore the delegations still valid for the current configuration.
+	now := time.Now()
+	for _, lease := range leases {
+		_, prefix, err := net.ParseCIDR(lease.Prefix)
+		if err != nil || lease.Expiry.Before(now) || !s.inPool(prefix) {
+			s.removeRoute(lease)
+
+			err = config.Store.Release(lease.DUID, lease.IAID)
+			if err != nil {
+				logger.Warn("Failed removing delegated prefix", logger.Ctx{"interface": iface, "prefix": lease.Prefix, "err": err})
+			}
+
+			continue
+		}
+
+		err = s.addRoute(lease)
+		if err != nil {
+			logger.Warn("Failed restoring delegated prefix route", logger.Ctx{"interface": iface, "prefix": lease.Prefix, "err": err})
+			continue
+		}
+
+		s.leases = append(s.leases, lease)
+	}
+
+	s.srv, err = server6.NewServer(iface, nil, s.handle)
+	if err != nil {
+		return fmt.Errorf("Failed starting DHCPv6 prefix delegation server: %w", err)
+	}
+
+	go func() { _ = s.srv.Serve() }()
+	go s.expireLoop()
+
+	serversMu.Lock()
+	servers[iface] = s
+	serversMu.Unlock()
+
+	return nil
+}
+
+// Stop stops the prefix delegation server of the interface (if running) and removes the routes it set up.
+// The delegations are kept in the store so that they can be restored when the server is started again.
+func Stop(iface string) {
+	serversMu.Lock()
+	s, found := servers[iface]
+	delete(servers, iface)
+	serversMu.Unlock()
+
+	if !found {
+		return
+	}
+
+	close(s.done)
+	_ = s.srv.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, lease := range s.leases {
+		s.removeRoute(lease)
+	}
+}
+
+// event reports a change of a delegation through the Events function of the configuration.
+func (s *server) event(eventType string, lease Lease) {
+	if s.config.Events != nil {
+		s.config.Events(eventType, lease)
+	}
+}
+
+// inPool returns whether the prefix can be delegated with the current configuration.
+func (s *server) inPool(prefix *net.IPNet) bool {
+	ones, _ := prefix.Mask.Size()
+
+	return ones == s.config.PrefixLen && s.config.Pool.Contains(prefix.IP)
+}
+
+func (s *server) addRoute(lease Lease) error {
+	_, prefix, err := net.ParseCIDR(lease.Prefix)
+	if err != nil {
+		return err
+	}
+
+	r := &ip.Route{
+		DevName: s.iface,
+		Route:   prefix,
+		Via:     net.ParseIP(lease.NextHop),
+		Proto:   "static",
+		Family:  ip.FamilyV6,
+	}
+
+	return r.Replace()
+}
+
+func (s *server) removeRoute(lease Lease) {
+	_, prefix, err := net.ParseCIDR(lease.Prefix)
+	if err != nil {
+		return
+	}
+
+	r := &ip.Route{
+		DevName: s.iface,
+		Route:   prefix,
+		Family:  ip.FamilyV6,
+	}
+
+	_ = r.Delete()
+}
+
+// expireLoop removes the delegations which haven't been renewed in time.
+func (s *server) expireLoop() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+
+		now := time.Now()
+		leases := make([]Lease, 0, len(s.leases))
+		for _, lease := range s.leases {
+			if !lease.Expiry.Before(now) {
+				leases = append(leases, lease)
+				continue
+			}
+
+			logger.Debug("Delegated prefix expired", logger.Ctx{"interface": s.iface, "prefix": lease.Prefix})
+			s.removeRoute(lease)
+
+			err := s.config.Store.Release(lease.DUID, lease.IAID)
+			if err != nil {
+				logger.Warn("Failed removing delegated prefix", logger.Ctx{"interface": s.iface, "prefix": lease.Prefix, "err": err})
+			}
+
+			s.event("released", lease)
+		}
+
+		s.leases = leases
+		s.mu.Unlock()
+	}
+}
+
+// handle answers the DHCPv6 messages of the clients.
+// As the network is stateless, only the configuration options and the delegated prefixes are provided.
+func (s *server) handle(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6) {
+	// Relayed messages aren't supported.
+	msg, ok := m.(*dhcpv6.Message)
+	if !ok {
+		return
+	}
+
+	clientID := msg.Options.ClientID()
+	peerAddr, ok := peer.(*net.UDPAddr)
+	if clientID == nil || !ok {
+		return
+	}
+
+	// Only answer messages meant for any server or for this one.
+	serverID := msg.Options.ServerID()
+	switch msg.Type() {
+	case dhcpv6.MessageTypeSolicit, dhcpv6.MessageTypeRebind:
+		if serverID != nil {
+			return
+		}
+
+	case dhcpv6.MessageTypeInformationRequest:
+		if serverID != nil && !serverID.Equal(s.duid) {
+			return
+		}
+
+	case dhcpv6.MessageTypeRequest, dhcpv6.MessageTypeRenew, dhcpv6.MessageTypeRelease, dhcpv6.MessageTypeDecline:
+		if serverID == nil || !serverID.Equal(s.duid) {
+			return
+		}
+
+	default:
+		return
+	}
+
+	iapds := msg.Options.IAPD()
+
+	// There are no addresses to offer, so only advertise to the clients asking for prefixes.
+	if msg.Type() == dhcpv6.MessageTypeSolicit && len(iapds) == 0 {
+		return
+	}
+
+	modifiers := []dhcpv6.Modifier{dhcpv6.WithServerID(s.duid)}
+	if msg.Type() != dhcpv6.MessageTypeRelease && msg.Type() != dhcpv6.MessageTypeDecline {
+		if len(s.config.DNSServers) > 0 {
+			modifiers = append(modifiers, dhcpv6.WithDNS(s.config.DNSServers...))
+		}
+
+		if len(s.config.DomainSearch) > 0 {
+			modifiers = append(modifiers, dhcpv6.WithDomainSearchList(s.config.DomainSearch...))
+		}
+	}
+
+	var reply *dhcpv6.Message
+	var err error
+	commit := true
+	if msg.Type() == dhcpv6.MessageTypeSolicit && msg.GetOneOption(dhcpv6.OptionRapidCommit) == nil {
+		commit = false
+		reply, err = dhcpv6.NewAdvertiseFromSolicit(msg, modifiers...)
+	} else {
+		reply, err = dhcpv6.NewReplyFromMessage(msg, modifiers...)
+	}
+
+	if err != nil {
+		return
+	}
+
+	// Addresses are handed out through SLAAC only.
+	if msg.Type() != dhcpv6.MessageTypeInformationRequest {
+		for _, ia := range msg.Options.IANA() {
+			reply.AddOption(&dhcpv6.OptIANA{
+				IaId:    ia.IaId,
+				Options: dhcpv6.IdentityOptions{Options: dhcpv6.Options{&dhcpv6.OptStatusCode{StatusCode: iana.StatusNoAddrsAvail, StatusMessage: "No addresses available"}}},
+			})
+		}
+
+		duid := hex.EncodeToString(clientID.ToBytes())
+		hwaddr := clientHwaddr(clientID, peerAddr.IP)
+
+		for _, iapd := range iapds {
+			iaid := iaidToUint32(iapd.IaId)
+
+			if msg.Type() == dhcpv6.MessageTypeRelease || msg.Type() == dhcpv6.MessageTypeDecline {
+				s.release(duid, iaid)
+				continue
+			}
+
+			lease, err := s.lease(duid, iaid, peerAddr.IP, hwaddr, commit)
+			if err != nil {
+				reply.AddOption(&dhcpv6.OptIAPD{
+					IaId:    iapd.IaId,
+					Options: dhcpv6.PDOptions{Options: dhcpv6.Options{&dhcpv6.OptStatusCode{StatusCode: iana.StatusNoPrefixAvail, StatusMessage: err.Error()}}},
+				})
+
+				continue
+			}
+
+			_, prefix, _ := net.ParseCIDR(lease.Prefix)
+			reply.AddOption(&dhcpv6.OptIAPD{
+				IaId: iapd.IaId,
+				T1:   s.config.Lifetime / 2,
+				T2:   s.config.Lifetime * 4 / 5,
+				Options: dhcpv6.PDOptions{Options: dhcpv6.Options{&dhcpv6.OptIAPrefix{
+					PreferredLifetime: s.config.Lifetime,
+					ValidLifetime:     s.config.Lifetime,
+					Prefix:            prefix,
+				}}},
+			})
+		}
+	}
+
+	if msg.Type() == dhcpv6.MessageTypeRelease || msg.Type() == dhcpv6.MessageTypeDecline {
+		reply.AddOption(&dhcpv6.OptStatusCode{StatusCode: iana.StatusSuccess})
+	}
+
+	_, err = conn.WriteTo(reply.ToBytes(), peer)
+	if err != nil {
+		logger.Warn("Failed sending DHCPv6 reply", logger.Ctx{"interface": s.iface, "peer": peer.String(), "err": err})
+	}
+}
+
+// lease returns the delegation of the client's identity association, allocating a new prefix if needed.
+// When commit is true, the delegation is recorded and routed to the client.
+func (s *server) lease(duid string, iaid uint32, nextHop net.IP, hwaddr string, commit bool) (*Lease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lease, err := s.config.Store.Lease(Lease{
+		DUID:    duid,
+		IAID:    iaid,
+		NextHop: nextHop.String(),
+		Hwaddr:  hwaddr,
+		Expiry:  time.Now().Add(s.config.Lifetime),
+	}, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	if !commit {
+		return lease, nil
+	}
+
+	err = s.addRoute(*lease)
+	if err != nil {
+		return nil, fmt.Errorf("Failed routing delegated prefix: %w", err)
+	}
+
+	for i, existing := range s.leases {
+		if existing.DUID != duid || existing.IAID != iaid {
+			continue
+		}
+
+		eventType := "renewed"
+		if existing.Prefix != lease.Prefix {
+			s.removeRoute(existing)
+			s.event("released", existing)
+			eventType = "acquired"
+		}
+
+		s.leases[i] = *lease
+		s.event(eventType, *lease)
+
+		return lease, nil
+	}
+
+	s.leases = append(s.leases, *lease)
+	logger.Debug("Delegated prefix", logger.Ctx{"interface": s.iface, "prefix": lease.Prefix, "nexthop": lease.NextHop})
+	s.event("acquired", *lease)
+
+	return lease, nil
+}
+
+// release removes the delegation of the client's identity association.
+func (s *server) release(duid string, iaid uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.config.Store.Release(duid, iaid)
+	if err != nil {
+		logger.Warn("Failed removing delegated prefix", logger.Ctx{"interface": s.iface, "err": err})
+	}
+
+	for i, lease := range s.leases {
+		if lease.DUID != duid || lease.IAID != iaid {
+			continue
+		}
+
+		s.removeRoute(lease)
+		s.leases = append(s.leases[:i], s.leases[i+1:]...)
+		s.event("released", lease)
+
+		return
+	}
+}
+
+// AllocatePrefix returns the first prefix of the pool which isn't in use.
+func AllocatePrefix(pool *net.IPNet, prefixLen int, used []string) (*net.IPNet, error) {
+	poolLen, bits := pool.Mask.Size()
+
+	inUse := make(map[string]bool, len(used))
+	for _, prefix := range used {
+		inUse[prefix] = true
+	}
+
+	// There can't be more candidates in use than existing delegations.
+	count := new(big.Int).Lsh(big.NewInt(1), uint(prefixLen-poolLen))
+	maxCandidates := big.NewInt(int64(len(used) + 1))
+	if count.Cmp(maxCandidates) > 0 {
+		count = maxCandidates
+	}
+
+	base := new(big.Int).SetBytes(pool.IP.Mask(pool.Mask).To16())
+	step := new(big.Int).Lsh(big.NewInt(1), uint(bits-prefixLen))
+	mask := net.CIDRMask(prefixLen, bits)
+
+	for i := int64(0); i < count.Int64(); i++ {
+		addr := new(big.Int).Add(base, new(big.Int).Mul(step, big.NewInt(i)))
+
+		buf := make([]byte, net.IPv6len)
+		addr.FillBytes(buf)
+
+		prefix := &net.IPNet{IP: net.IP(buf), Mask: mask}
+		if !inUse[prefix.String()] {
+			return prefix, nil
+		}
+	}
+
+	return nil, errors.New("No prefix available for delegation")
+}
+
+// ClientDUID returns the link-layer based DUID (hex encoded) identifying a client by its MAC address.
+// It is used for the delegations which aren't requested over DHCPv6.
+func ClientDUID(hwaddr net.HardwareAddr) string {
+	return hex.EncodeToString((&dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: hwaddr}).ToBytes())
+}
+
+// clientHwaddr returns the MAC address of the client, taken from its link-layer based DUID or from its
+// EUI-64 link-local address.
+func clientHwaddr(clientID dhcpv6.DUID, linkLocal net.IP) string {
+	switch duid := clientID.(type) {
+	case *dhcpv6.DUIDLL:
+		return duid.LinkLayerAddr.String()
+	case *dhcpv6.DUIDLLT:
+		return duid.LinkLayerAddr.String()
+	}
+
+	addr := linkLocal.To16()
+	if addr == nil || !addr.IsLinkLocalUnicast() || addr[11] != 0xff || addr[12] != 0xfe {
+		return ""
+	}
+
+	return net.HardwareAddr{addr[8] ^ 0x02, addr[9], addr[10], addr[13], addr[14], addr[15]}.String()
+}
+
+func iaidToUint32(iaid [4]byte) uint32 {
+	return uint32(iaid[0])<<24 | uint32(iaid[1])<<16 | uint32(iaid[2])<<8 | uint32(iaid[3])
+}
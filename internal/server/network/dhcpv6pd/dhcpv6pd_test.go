@@ -0,0 +1,78 @@
+package dhcpv6pd
+
+import (
+	"encoding/hex"
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocatePrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		pool      string
+		prefixLen int
+		used      []string
+		expected  string
+	}{
+		{name: "empty pool", pool: "2001:db8:100::/48", prefixLen: 56, expected: "2001:db8:100::/56"},
+		{name: "first in use", pool: "2001:db8:100::/48", prefixLen: 56, used: []string{"2001:db8:100::/56"}, expected: "2001:db8:100:100::/56"},
+		{name: "gap reused", pool: "2001:db8:100::/48", prefixLen: 64, used: []string{"2001:db8:100::/64", "2001:db8:100:2::/64"}, expected: "2001:db8:100:1::/64"},
+		{name: "whole pool", pool: "2001:db8:100::/56", prefixLen: 56, expected: "2001:db8:100::/56"},
+		{name: "exhausted", pool: "2001:db8:100::/63", prefixLen: 64, used: []string{"2001:db8:100::/64", "2001:db8:100:1::/64"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, pool, err := net.ParseCIDR(test.pool)
+			require.NoError(t, err)
+
+			prefix, err := AllocatePrefix(pool, test.prefixLen, test.used)
+			if test.expected == "" {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, prefix.String())
+		})
+	}
+}
+
+func Test_clientHwaddr(t *testing.T) {
+	mac, _ := net.ParseMAC("00:16:3e:12:34:56")
+
+	tests := []struct {
+		name      string
+		clientID  dhcpv6.DUID
+		linkLocal string
+		expected  string
+	}{
+		{name: "DUID-LL", clientID: &dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: mac}, linkLocal: "fe80::1", expected: "00:16:3e:12:34:56"},
+		{name: "DUID-LLT", clientID: &dhcpv6.DUIDLLT{HWType: iana.HWTypeEthernet, Time: 1, LinkLayerAddr: mac}, linkLocal: "fe80::1", expected: "00:16:3e:12:34:56"},
+		{name: "EUI-64 link-local", clientID: &dhcpv6.DUIDUUID{}, linkLocal: "fe80::216:3eff:fe12:3456", expected: "00:16:3e:12:34:56"},
+		{name: "Random link-local", clientID: &dhcpv6.DUIDUUID{}, linkLocal: "fe80::1234:5678:9abc:def0", expected: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, clientHwaddr(test.clientID, net.ParseIP(test.linkLocal)))
+		})
+	}
+}
+
+func TestClientDUID(t *testing.T) {
+	mac, _ := net.ParseMAC("00:16:3e:12:34:56")
+
+	duid := ClientDUID(mac)
+	assert.Equal(t, "0003000100163e123456", duid)
+
+	// The DUID built for the NIC matches the one sent by a client using its MAC address.
+	clientID := &dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: mac}
+	assert.Equal(t, duid, hex.EncodeToString(clientID.ToBytes()))
+	assert.Equal(t, mac.String(), clientHwaddr(clientID, nil))
+}
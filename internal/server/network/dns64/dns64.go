@@ -0,0 +1,146 @@
+package dns64
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// resolvConf is the resolver configuration the upstream servers are taken from.
+const resolvConf = "/etc/resolv.conf"
+
+// Proxy represents a DNS64 proxy forwarding requests to the host's upstream DNS servers.
+type Proxy struct {
+	prefix    *net.IPNet
+	upstreams []string
+}
+
+// NewProxy returns a DNS64 proxy synthesizing AAAA records within prefix for names that only resolve to
+// IPv4 addresses.
+func NewProxy(prefix *net.IPNet) (*Proxy, error) {
+	ones, bits := prefix.Mask.Size()
+	if ones != 96 || bits != 128 {
+		return nil, fmt.Errorf("Invalid DNS64 prefix %q, must be a /96", prefix.String())
+	}
+
+	clientConfig, err := dns.ClientConfigFromFile(resolvConf)
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading upstream DNS servers: %w", err)
+	}
+
+	upstreams := make([]string, 0, len(clientConfig.Servers))
+	for _, server := range clientConfig.Servers {
+		upstreams = append(upstreams, net.JoinHostPort(server, clientConfig.Port))
+	}
+
+	if len(upstreams) == 0 {
+		return nil, errors.New("No upstream DNS servers found for DNS64")
+	}
+
+	return &Proxy{prefix: prefix, upstreams: upstreams}, nil
+}
+
+// Serve answers the requests received on the provided sockets until one of them fails.
+func (p *Proxy) Serve(udpConn net.PacketConn, tcpListener net.Listener) error {
+	handler := dns.HandlerFunc(p.handle)
+
+	errCh := make(chan error, 2)
+	for _, server := range []*dns.Server{{PacketConn: udpConn, Handler: handler}, {Listener: tcpListener, Handler: handler}} {
+		go func(server *dns.Server) {
+			errCh <- server.ActivateAndServe()
+		}(server)
+	}
+
+	return <-errCh
+}
+
+// exchange forwards the request to the upstream servers, returning the first answer.
+func (p *Proxy) exchange(r *dns.Msg, network string) (*dns.Msg, error) {
+	client := &dns.Client{Net: network, Timeout: 5 * time.Second}
+
+	var err error
+	for _, upstream := range p.upstreams {
+		var resp *dns.Msg
+
+		resp, _, err = client.Exchange(r, upstream)
+		if err == nil {
+			return resp, nil
+		}
+	}
+
+	return nil, err
+}
+
+func (p *Proxy) handle(w dns.ResponseWriter, r *dns.Msg) {
+	network := "udp"
+	_, ok := w.RemoteAddr().(*net.TCPAddr)
+	if ok {
+		network = "tcp"
+	}
+
+	resp, err := p.exchange(r, network)
+	if err != nil {
+		m := new(dns.Msg)
+		m.SetRcode(r, dns.RcodeServerFailure)
+		_ = w.WriteMsg(m)
+		return
+	}
+
+	// Synthesize the AAAA records from the A records when the name has none.
+	if len(r.Question) == 1 && r.Question[0].Qtype == dns.TypeAAAA && resp.Rcode == dns.RcodeSuccess && !hasType(resp.Answer, dns.TypeAAAA) {
+		req := r.Copy()
+		req.Question[0].Qtype = dns.TypeA
+
+		respA, err := p.exchange(req, network)
+		if err == nil && respA.Rcode == dns.RcodeSuccess && hasType(respA.Answer, dns.TypeA) {
+			resp.Answer = synthesize(respA.Answer, p.prefix)
+			resp.Ns = nil
+		}
+	}
+
+	_ = w.WriteMsg(resp)
+}
+
+// hasType returns whether any of the records is of the given type.
+func hasType(records []dns.RR, rrType uint16) bool {
+	for _, rr := range records {
+		if rr.Header().Rrtype == rrType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// synthesize converts the A records into AAAA records embedding the IPv4 addresses in the /96 prefix.
+// Other records (such as CNAMEs) are kept as-is.
+func synthesize(records []dns.RR, prefix *net.IPNet) []dns.RR {
+	answer := make([]dns.RR, 0, len(records))
+	for _, rr := range records {
+		a, ok := rr.(*dns.A)
+		if !ok {
+			answer = append(answer, rr)
+			continue
+		}
+
+		ipv4 := a.A.To4()
+		if ipv4 == nil {
+			continue
+		}
+
+		addr := make(net.IP, net.IPv6len)
+		copy(addr, prefix.IP.To16()[:12])
+		copy(addr[12:], ipv4)
+
+		hdr := a.Hdr
+		hdr.Rrtype = dns.TypeAAAA
+		hdr.Rdlength = 0
+
+		answer = append(answer, &dns.AAAA{Hdr: hdr, AAAA: addr})
+	}
+
+	return answer
+}
@@ -0,0 +1,42 @@
+package dns64
+
+import (
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_synthesize(t *testing.T) {
+	_, prefix, err := net.ParseCIDR("64:ff9b::/96")
+	require.NoError(t, err)
+
+	cname, err := dns.NewRR("www.example.com. 300 IN CNAME example.com.")
+	require.NoError(t, err)
+
+	a, err := dns.NewRR("example.com. 60 IN A 192.0.2.33")
+	require.NoError(t, err)
+
+	answer := synthesize([]dns.RR{cname, a}, prefix)
+	require.Len(t, answer, 2)
+
+	assert.Equal(t, cname, answer[0])
+
+	aaaa, ok := answer[1].(*dns.AAAA)
+	require.True(t, ok)
+	assert.Equal(t, "example.com.", aaaa.Hdr.Name)
+	assert.Equal(t, uint32(60), aaaa.Hdr.Ttl)
+	assert.Equal(t, dns.TypeAAAA, aaaa.Hdr.Rrtype)
+	assert.Equal(t, "64:ff9b::c000:221", aaaa.AAAA.String())
+}
+
+func Test_hasType(t *testing.T) {
+	a, err := dns.NewRR("example.com. 60 IN A 192.0.2.33")
+	require.NoError(t, err)
+
+	assert.True(t, hasType([]dns.RR{a}, dns.TypeA))
+	assert.False(t, hasType([]dns.RR{a}, dns.TypeAAAA))
+	assert.False(t, hasType(nil, dns.TypeA))
+}
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/mdlayher/netx/eui64"
+	"golang.org/x/sys/unix"
 
 	incus "github.com/lxc/incus/v6/client"
 	"github.com/lxc/incus/v6/internal/server/apparmor"
@@ -30,8 +31,12 @@ import (
 	"github.com/lxc/incus/v6/internal/server/dnsmasq/dhcpalloc"
 	firewallDrivers "github.com/lxc/incus/v6/internal/server/firewall/drivers"
 	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/internal/server/locking"
 	"github.com/lxc/incus/v6/internal/server/network/acl"
 	addressset "github.com/lxc/incus/v6/internal/server/network/address-set"
+	"github.com/lxc/incus/v6/internal/server/network/dhcpv6pd"
+	"github.com/lxc/incus/v6/internal/server/network/httpboot"
+	"github.com/lxc/incus/v6/internal/server/network/nat64"
 	"github.com/lxc/incus/v6/internal/server/project"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/internal/server/warnings"
@@ -41,6 +46,7 @@ import (
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
 	"github.com/lxc/incus/v6/shared/validate"
 )
@@ -62,6 +68,8 @@ func (n *bridge) DBType() db.NetworkType {
 func (n *bridge) Info() Info {
 	info := n.common.Info()
 	info.AddressForwards = true
+	info.DHCPReservations = true
+	info.FloatingIPs = true
 
 	return info
 }
@@ -331,6 +339,42 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		//  shortdesc: Static routes to provide via DHCP option 121, as a comma-separated list of alternating subnets (CIDR) and gateway addresses (same syntax as dnsmasq)
 		"ipv4.dhcp.routes": validate.Optional(validate.IsDHCPRouteList),
 
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.dhcp.boot.server)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  default: bridge address if the boot files are served over TFTP
+		//  shortdesc: Address of the TFTP server to network boot from
+		"ipv4.dhcp.boot.server": validate.Optional(validate.IsNetworkAddressV4),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.dhcp.boot.filename.bios)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  default: -
+		//  shortdesc: Boot file for BIOS clients
+		"ipv4.dhcp.boot.filename.bios": validate.Optional(validateBootFilename),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.dhcp.boot.filename.uefi)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  default: -
+		//  shortdesc: Boot file for UEFI clients
+		"ipv4.dhcp.boot.filename.uefi": validate.Optional(validateBootFilename),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.dhcp.boot.filename.ipxe)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  default: -
+		//  shortdesc: Boot file or URL for iPXE clients (to chainload a script)
+		"ipv4.dhcp.boot.filename.ipxe": validate.Optional(validateBootFilename),
+
 		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.routes)
 		//
 		// ---
@@ -409,6 +453,33 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		//  shortdesc: The source address used for outbound traffic from the bridge
 		"ipv6.nat.address": validate.Optional(validate.IsNetworkAddressV6),
 
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv6.nat64)
+		//
+		// ---
+		//  type: bool
+		//  condition: IPv6 address
+		//  default: `false`
+		//  shortdesc: Whether to translate traffic to IPv4 destinations (NAT64 and DNS64)
+		"ipv6.nat64": validate.Optional(validate.IsBool),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv6.nat64.prefix)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv6 NAT64
+		//  default: `64:ff9b::/96`
+		//  shortdesc: IPv6 prefix IPv4 addresses are mapped into (CIDR notation, must be a /96)
+		"ipv6.nat64.prefix": validate.Optional(validate.IsNetworkV6),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv6.nat64.pool)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv6 NAT64
+		//  default: a /24 from `100.64.0.0/10`
+		//  shortdesc: IPv4 subnet the instances are mapped to by the translator (CIDR notation)
+		"ipv6.nat64.pool": validate.Optional(validate.IsNetworkV4),
+
 		// gendoc:generate(entity=network_bridge, group=common, key=ipv6.dhcp)
 		//
 		// ---
@@ -445,6 +516,23 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		//  shortdesc: Comma-separated list of IPv6 ranges to use for DHCP (FIRST-LAST format)
 		"ipv6.dhcp.ranges": validate.Optional(validate.IsListOf(validate.IsNetworkRangeV6)),
 
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv6.dhcp.pd.prefix)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv6 address
+		//  shortdesc: Pool of IPv6 prefixes to delegate to instances through DHCPv6 prefix delegation (CIDR notation)
+		"ipv6.dhcp.pd.prefix": validate.Optional(validate.IsNetworkV6),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv6.dhcp.pd.size)
+		//
+		// ---
+		//  type: integer
+		//  condition: IPv6 prefix delegation
+		//  default: `64`
+		//  shortdesc: Length of the prefixes delegated to instances
+		"ipv6.dhcp.pd.size": validate.Optional(validate.IsInRange(1, 128)),
+
 		// gendoc:generate(entity=network_bridge, group=common, key=ipv6.routes)
 		//
 		// ---
@@ -535,6 +623,42 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		//  shortdesc: DNS zone name for IPv6 reverse DNS records
 		"dns.zone.reverse.ipv6": validate.IsAny,
 
+		// gendoc:generate(entity=network_bridge, group=common, key=boot.volume)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 address
+		//  default: -
+		//  shortdesc: Custom storage volume to serve the boot files from (`<pool>/<volume>`)
+		"boot.volume": validate.Optional(validateVolumeSource),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=boot.tftp)
+		//
+		// ---
+		//  type: bool
+		//  condition: boot volume
+		//  default: `true`
+		//  shortdesc: Whether to serve the boot files over TFTP
+		"boot.tftp": validate.Optional(validate.IsBool),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=boot.http)
+		//
+		// ---
+		//  type: bool
+		//  condition: boot volume
+		//  default: `false`
+		//  shortdesc: Whether to serve the boot files over HTTP
+		"boot.http": validate.Optional(validate.IsBool),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=boot.http.port)
+		//
+		// ---
+		//  type: integer
+		//  condition: boot volume
+		//  default: `80`
+		//  shortdesc: Port to serve the boot files over HTTP on
+		"boot.http.port": validate.Optional(validate.IsNetworkPort),
+
 		// gendoc:generate(entity=network_bridge, group=common, key=raw.dnsmasq)
 		//
 		// ---
@@ -587,10 +711,72 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		//  default: `false`
 		//  shortdesc: Whether to log egress traffic that doesn't match any ACL rule
 		"security.acls.default.egress.logged": validate.Optional(validate.IsBool),
+
+		// gendoc:generate(entity=network_bridge, group=common, key=qos.rate)
+		//
+		// ---
+		//  type: string
+		//  condition: -
+		//  default: -
+		//  shortdesc: Total bit rate shared by the QoS classes of the network (enables QoS, see {ref}`network-bridge-qos`)
+		"qos.rate": validate.Optional(qosValidRate),
 	}
 
 	// Add dynamic validation rules.
 	for k := range config {
+		// QoS class keys have the class name in their name, extract the suffix.
+		if strings.HasPrefix(k, "qos.class.") {
+			fields := strings.Split(k, ".")
+			if len(fields) != 4 {
+				return fmt.Errorf("Invalid network configuration key: %s", k)
+			}
+
+			err := validate.IsDeviceName(fields[2])
+			if err != nil {
+				return fmt.Errorf("Invalid QoS class name %q: %w", fields[2], err)
+			}
+
+			// Add the correct validation rule for the dynamic field based on last part of key.
+			switch fields[3] {
+			case "guaranteed":
+				// gendoc:generate(entity=network_bridge, group=common, key=qos.class.NAME.guaranteed)
+				//
+				// ---
+				//  type: string
+				//  condition: `qos.rate`
+				//  default: `8kbit`
+				//  shortdesc: Bit rate guaranteed to the traffic of the class
+				rules[k] = validate.Optional(qosValidRate)
+			case "max":
+				// gendoc:generate(entity=network_bridge, group=common, key=qos.class.NAME.max)
+				//
+				// ---
+				//  type: string
+				//  condition: `qos.rate`
+				//  default: `qos.rate`
+				//  shortdesc: Maximum bit rate the traffic of the class can use by borrowing from the other classes
+				rules[k] = validate.Optional(qosValidRate)
+			case "priority":
+				// gendoc:generate(entity=network_bridge, group=common, key=qos.class.NAME.priority)
+				//
+				// ---
+				//  type: integer
+				//  condition: `qos.rate`
+				//  default: `0`
+				//  shortdesc: Priority of the class when borrowing unused bandwidth (`0` is the highest, `7` the lowest)
+				rules[k] = validate.Optional(validate.IsInRange(0, 7))
+			case "dscp":
+				// gendoc:generate(entity=network_bridge, group=common, key=qos.class.NAME.dscp)
+				//
+				// ---
+				//  type: integer
+				//  condition: `qos.rate`
+				//  default: -
+				//  shortdesc: DSCP value to mark the IP packets of the class with (`0` to `63`)
+				rules[k] = validate.Optional(validate.IsInRange(0, 63))
+			}
+		}
+
 		// Tunnel keys have the remote name in their name, extract the suffix.
 		if strings.HasPrefix(k, "tunnel.") {
 			// Validate remote name in key.
@@ -715,6 +901,14 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 	// defaultdesc: `180`
 	// shortdesc: Peer session hold time (in seconds; optional)
 
+	// gendoc:generate(entity=network_bridge, group=bgp, key=bgp.peers.NAME.bfd)
+	//
+	// ---
+	// type: bool
+	// condition: BGP server
+	// defaultdesc: `false`
+	// shortdesc: Whether to use BFD to detect the peer going down
+
 	// Add the BGP validation rules.
 	bgpRules, err := n.bgpValidationRules(config)
 	if err != nil {
@@ -842,6 +1036,71 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 		}
 	}
 
+	// Check the IPv6 prefix delegation pool.
+	err = prefixDelegationValidate(config)
+	if err != nil {
+		return err
+	}
+
+	// The DHCPv6 server delegating the prefixes replaces the one of dnsmasq, which only supports stateless DHCPv6.
+	if config["ipv6.dhcp.pd.prefix"] != "" && util.IsTrue(config["ipv6.dhcp.stateful"]) {
+		return errors.New(`"ipv6.dhcp.pd.prefix" cannot be used with "ipv6.dhcp.stateful"`)
+	}
+
+	// Check the boot file services.
+	if config["boot.volume"] != "" && util.IsNoneOrEmpty(config["ipv4.address"]) {
+		return errors.New(`"boot.volume" requires "ipv4.address" to be set`)
+	}
+
+	if config["boot.volume"] == "" && util.IsTrue(config["boot.http"]) {
+		return errors.New(`"boot.http" requires "boot.volume" to be set`)
+	}
+
+	// Check the NAT64 configuration.
+	if util.IsTrue(config["ipv6.nat64"]) {
+		if util.IsNoneOrEmpty(config["ipv6.address"]) {
+			return errors.New(`"ipv6.nat64" requires "ipv6.address" to be set`)
+		}
+
+		if util.IsTrue(config["ipv4.nat"]) {
+			return errors.New(`"ipv6.nat64" cannot be used together with "ipv4.nat"`)
+		}
+
+		if util.IsFalse(config["ipv6.routing"]) {
+			return errors.New(`"ipv6.nat64" requires "ipv6.routing" to be enabled`)
+		}
+
+		if len(bridgeNAT64Device(n.name)) > 15 {
+			return errors.New(`The network name is too long to be used with "ipv6.nat64"`)
+		}
+
+		prefix, pool, err := bridgeNAT64Subnets(n.id, config)
+		if err != nil {
+			return err
+		}
+
+		ones, _ := prefix.Mask.Size()
+		if ones != 96 {
+			return errors.New(`"ipv6.nat64.prefix" must be a /96`)
+		}
+
+		ones, _ = pool.Mask.Size()
+		if ones > 30 {
+			return errors.New(`"ipv6.nat64.pool" must be at least a /30`)
+		}
+
+		ipv4Net, _ := ParseIPCIDRToNet(config["ipv4.address"])
+		if ipv4Net != nil && (SubnetContains(pool, ipv4Net) || SubnetContains(ipv4Net, pool)) {
+			return errors.New(`"ipv6.nat64.pool" cannot overlap with the "ipv4.address" subnet`)
+		}
+	}
+
+	// Check the QoS classes.
+	err = n.qosValidate(config)
+	if err != nil {
+		return err
+	}
+
 	// Check Security ACLs are supported and exist.
 	if config["security.acls"] != "" {
 		err = acl.Exists(n.state, n.Project(), util.SplitNTrimSpace(config["security.acls"], ",", -1, true)...)
@@ -853,6 +1112,75 @@ func (n *bridge) Validate(config map[string]string, clientType request.ClientTyp
 	return nil
 }
 
+// bridgeBootOptions returns the dnsmasq options providing the network boot files for each type of client.
+func bridgeBootOptions(config map[string]string) []string {
+	if config["ipv4.dhcp.boot.filename.bios"] == "" && config["ipv4.dhcp.boot.filename.uefi"] == "" && config["ipv4.dhcp.boot.filename.ipxe"] == "" {
+		return nil
+	}
+
+	server := ""
+	if config["ipv4.dhcp.boot.server"] != "" {
+		server = fmt.Sprintf(",,%s", config["ipv4.dhcp.boot.server"])
+	}
+
+	// Tag the clients by firmware (client architecture, option 93) and iPXE (user class, option 77).
+	options := []string{
+		"--dhcp-match=set:efi,option:client-arch,7",
+		"--dhcp-match=set:efi,option:client-arch,9",
+		"--dhcp-match=set:efi,option:client-arch,11",
+		"--dhcp-userclass=set:ipxe,iPXE",
+	}
+
+	if config["ipv4.dhcp.boot.filename.bios"] != "" {
+		options = append(options, fmt.Sprintf("--dhcp-boot=tag:!ipxe,tag:!efi,%s%s", config["ipv4.dhcp.boot.filename.bios"], server))
+	}
+
+	if config["ipv4.dhcp.boot.filename.uefi"] != "" {
+		options = append(options, fmt.Sprintf("--dhcp-boot=tag:!ipxe,tag:efi,%s%s", config["ipv4.dhcp.boot.filename.uefi"], server))
+	}
+
+	if config["ipv4.dhcp.boot.filename.ipxe"] != "" {
+		options = append(options, fmt.Sprintf("--dhcp-boot=tag:ipxe,%s%s", config["ipv4.dhcp.boot.filename.ipxe"], server))
+	}
+
+	return options
+}
+
+// bridgeNAT64Device returns the name of the NAT64 translator device of the network.
+func bridgeNAT64Device(networkName string) string {
+	return fmt.Sprintf("%s-nat64", networkName)
+}
+
+// bridgeNAT64Subnets returns the NAT64 prefix and IPv4 pool from the network config.
+// If no pool is configured, a /24 is picked from 100.64.0.0/10 based on the network ID.
+func bridgeNAT64Subnets(networkID int64, config map[string]string) (*net.IPNet, *net.IPNet, error) {
+	prefixStr := config["ipv6.nat64.prefix"]
+	if prefixStr == "" {
+		prefixStr = "64:ff9b::/96"
+	}
+
+	_, prefix, err := net.ParseCIDR(prefixStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed parsing ipv6.nat64.prefix: %w", err)
+	}
+
+	var pool *net.IPNet
+	if config["ipv6.nat64.pool"] != "" {
+		_, pool, err = net.ParseCIDR(config["ipv6.nat64.pool"])
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed parsing ipv6.nat64.pool: %w", err)
+		}
+	} else {
+		index := networkID % 16384
+		pool = &net.IPNet{
+			IP:   net.IPv4(100, byte(64+index/256), byte(index%256), 0).To4(),
+			Mask: net.CIDRMask(24, 32),
+		}
+	}
+
+	return prefix, pool, nil
+}
+
 // Create checks whether the bridge interface name is used already.
 func (n *bridge) Create(clientType request.ClientType) error {
 	n.logger.Debug("Create", logger.Ctx{"clientType": clientType, "config": n.config})
@@ -1142,6 +1470,17 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 	}
 
+	// Remove the QoS device.
+	err = n.qosClearExternalInterfaces(n.config)
+	if err != nil {
+		return err
+	}
+
+	err = n.qosClear()
+	if err != nil {
+		return err
+	}
+
 	err = n.deleteChildren()
 	if err != nil {
 		return fmt.Errorf("Failed to delete bridge children interfaces: %w", err)
@@ -1449,6 +1788,8 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 				dnsmasqCmd = append(dnsmasqCmd, fmt.Sprintf("--dhcp-option-force=121,%s", strings.ReplaceAll(n.config["ipv4.dhcp.routes"], " ", "")))
 			}
 
+			dnsmasqCmd = append(dnsmasqCmd, bridgeBootOptions(n.config)...)
+
 			expiry := "1h"
 			if n.config["ipv4.dhcp.expiry"] != "" {
 				expiry = n.config["ipv4.dhcp.expiry"]
@@ -1599,7 +1940,14 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 
 		// Update the dnsmasq config.
 		dnsmasqCmd = append(dnsmasqCmd, []string{fmt.Sprintf("--listen-address=%s", ipAddress.String()), "--enable-ra"}...)
-		if n.DHCPv6Subnet() != nil {
+		if n.config["ipv6.dhcp.pd.prefix"] != "" {
+			// DHCPv6 is served by the prefix delegation server, dnsmasq only sends router advertisements.
+			if n.hasIPv6Firewall() {
+				fwOpts.FeaturesV6.ICMPDHCPDNSAccess = true
+			}
+
+			dnsmasqCmd = append(dnsmasqCmd, []string{"--dhcp-range", fmt.Sprintf("::,constructor:%s,ra-only", n.name)}...)
+		} else if n.DHCPv6Subnet() != nil {
 			if n.hasIPv6Firewall() {
 				fwOpts.FeaturesV6.ICMPDHCPDNSAccess = true
 			}
@@ -1721,6 +2069,23 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 			}
 		}
 
+		// Configure NAT64, masquerading the translated traffic.
+		if util.IsTrue(n.config["ipv6.nat64"]) {
+			_, pool, err := bridgeNAT64Subnets(n.id, n.config)
+			if err != nil {
+				return err
+			}
+
+			err = localUtil.SysctlSet("net/ipv4/ip_forward", "1")
+			if err != nil {
+				return err
+			}
+
+			fwOpts.SNATV4 = &firewallDrivers.SNATOpts{
+				Subnet: pool,
+			}
+		}
+
 		// Add additional routes.
 		if n.config["ipv6.routes"] != "" {
 			for _, route := range strings.Split(n.config["ipv6.routes"], ",") {
@@ -1874,12 +2239,75 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		return err
 	}
 
+	// Kill any existing NAT64 translator and DNS64 proxy for this network.
+	err = nat64.Kill(n.name, bridgeNAT64Device(n.name))
+	if err != nil {
+		return err
+	}
+
+	// Configure NAT64. The DNS64 proxy runs in the translator process, so it isn't affected by daemon restarts.
+	dns64Address := ""
+	if util.IsTrue(n.config["ipv6.nat64"]) && !util.IsNoneOrEmpty(n.config["ipv6.address"]) {
+		prefix, pool, err := bridgeNAT64Subnets(n.id, n.config)
+		if err != nil {
+			return err
+		}
+
+		dns64Address, err = nat64.Start(n.state.OS.ExecPath, n.name, bridgeNAT64Device(n.name), prefix, pool, n.config["dns.mode"] != "none")
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { _ = nat64.Kill(n.name, bridgeNAT64Device(n.name)) })
+	}
+
 	// Kill any existing dnsmasq daemon for this network.
 	err = dnsmasq.Kill(n.name, false)
 	if err != nil {
 		return err
 	}
 
+	dnsmasq.StopEvents(n.name)
+
+	// Stop the HTTP boot server and unmount the boot storage volume if no longer used.
+	httpboot.Stop(n.name)
+
+	bootTFTP := n.config["boot.volume"] != "" && util.IsTrueOrEmpty(n.config["boot.tftp"]) && n.UsesDNSMasq()
+	bootHTTP := n.config["boot.volume"] != "" && util.IsTrue(n.config["boot.http"])
+	if !bootTFTP && !bootHTTP {
+		err = bridgeBootUnmount(n.state, n.project, n.name)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Serve the boot files over HTTP on the bridge address.
+	if bootHTTP {
+		bootRoot, err := bridgeBootMount(n.state, n.project, n.name, n.config["boot.volume"])
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { _ = bridgeBootUnmount(n.state, n.project, n.name) })
+
+		bridgeIPv4, _, err := net.ParseCIDR(n.config["ipv4.address"])
+		if err != nil {
+			return err
+		}
+
+		port := n.config["boot.http.port"]
+		if port == "" {
+			port = "80"
+		}
+
+		err = httpboot.Start(n.name, net.JoinHostPort(bridgeIPv4.String(), port), bootRoot)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { httpboot.Stop(n.name) })
+	}
+
 	// Configure dnsmasq.
 	if n.UsesDNSMasq() {
 		// Setup the dnsmasq domain.
@@ -1894,6 +2322,16 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 			dnsmasqCmd = append(dnsmasqCmd, "-S", fmt.Sprintf("/%s/", dnsDomain))
 		}
 
+		// Resolve external names through the DNS64 proxy.
+		if dns64Address != "" {
+			host, port, err := net.SplitHostPort(dns64Address)
+			if err != nil {
+				return err
+			}
+
+			dnsmasqCmd = append(dnsmasqCmd, "--no-resolv", fmt.Sprintf("--server=%s#%s", host, port))
+		}
+
 		// Create a config file to contain additional config (and to prevent dnsmasq from reading /etc/dnsmasq.conf)
 		err = os.WriteFile(internalUtil.VarPath("networks", n.name, "dnsmasq.raw"), fmt.Appendf(nil, "%s\n", n.config["raw.dnsmasq"]), 0o644)
 		if err != nil {
@@ -1902,22 +2340,61 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 
 		dnsmasqCmd = append(dnsmasqCmd, fmt.Sprintf("--conf-file=%s", internalUtil.VarPath("networks", n.name, "dnsmasq.raw")))
 
-		// Attempt to drop privileges.
-		if n.state.OS.UnprivUser != "" {
-			dnsmasqCmd = append(dnsmasqCmd, []string{"-u", n.state.OS.UnprivUser}...)
+		// Report DHCP lease changes back to the daemon (DHCPv6 prefix delegations are reported by their server).
+		err = dnsmasq.ListenEvents(n.name, func(event dnsmasq.LeaseEvent) {
+			err := n.recordLease(event.Type, event.Hwaddr, event.Address, event.Hostname)
+			if err != nil {
+				n.logger.Warn("Failed recording lease event", logger.Ctx{"type": event.Type, "address": event.Address, "err": err})
+			}
+		})
+		if err != nil {
+			return err
 		}
 
-		if n.state.OS.UnprivGroup != "" {
-			dnsmasqCmd = append(dnsmasqCmd, []string{"-g", n.state.OS.UnprivGroup}...)
+		reverter.Add(func() { dnsmasq.StopEvents(n.name) })
+
+		scriptPath := internalUtil.VarPath("networks", n.name, "dnsmasq.script")
+		err = os.WriteFile(scriptPath, []byte(dnsmasq.EventScript(n.name)), 0o700)
+		if err != nil {
+			return err
 		}
 
-		// Create DHCP hosts directory.
-		if !util.PathExists(internalUtil.VarPath("networks", n.name, "dnsmasq.hosts")) {
-			err = os.MkdirAll(internalUtil.VarPath("networks", n.name, "dnsmasq.hosts"), 0o755)
+		dnsmasqCmd = append(dnsmasqCmd, fmt.Sprintf("--dhcp-script=%s", scriptPath))
+
+		// --script-on-renewal option is only supported on >2.83.
+		minVer, _ := version.NewDottedVersion("2.83")
+		if dnsmasqVersion.Compare(minVer) > 0 {
+			dnsmasqCmd = append(dnsmasqCmd, "--script-on-renewal")
+		}
+
+		// Serve the boot files over TFTP.
+		if bootTFTP {
+			bootRoot, err := bridgeBootMount(n.state, n.project, n.name, n.config["boot.volume"])
 			if err != nil {
 				return err
 			}
-		}
+
+			reverter.Add(func() { _ = bridgeBootUnmount(n.state, n.project, n.name) })
+
+			dnsmasqCmd = append(dnsmasqCmd, "--enable-tftp", fmt.Sprintf("--tftp-root=%s", bootRoot))
+		}
+
+		// Attempt to drop privileges.
+		if n.state.OS.UnprivUser != "" {
+			dnsmasqCmd = append(dnsmasqCmd, []string{"-u", n.state.OS.UnprivUser}...)
+		}
+
+		if n.state.OS.UnprivGroup != "" {
+			dnsmasqCmd = append(dnsmasqCmd, []string{"-g", n.state.OS.UnprivGroup}...)
+		}
+
+		// Create DHCP hosts directory.
+		if !util.PathExists(internalUtil.VarPath("networks", n.name, "dnsmasq.hosts")) {
+			err = os.MkdirAll(internalUtil.VarPath("networks", n.name, "dnsmasq.hosts"), 0o755)
+			if err != nil {
+				return err
+			}
+		}
 
 		// Check for dnsmasq.
 		_, err := exec.LookPath("dnsmasq")
@@ -2005,6 +2482,77 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 	}
 
+	// Configure DHCPv6 prefix delegation.
+	if n.config["ipv6.dhcp.pd.prefix"] != "" && !util.IsNoneOrEmpty(n.config["ipv6.address"]) {
+		store, err := newPrefixDelegationStore(n.state, n.id, n.config)
+		if err != nil {
+			return err
+		}
+
+		expiry := "1h"
+		if n.config["ipv6.dhcp.expiry"] != "" {
+			expiry = n.config["ipv6.dhcp.expiry"]
+		}
+
+		lifetime, err := time.ParseDuration(expiry)
+		if err != nil {
+			return fmt.Errorf("Failed parsing ipv6.dhcp.expiry: %w", err)
+		}
+
+		// Send the same DNS configuration as dnsmasq would.
+		var dnsServers []net.IP
+		if n.config["dns.nameservers"] != "" {
+			for _, dnsServer := range dnsIPv6 {
+				dnsServers = append(dnsServers, net.ParseIP(dnsServer))
+			}
+		} else {
+			bridgeIP, _, err := net.ParseCIDR(n.config["ipv6.address"])
+			if err == nil {
+				dnsServers = append(dnsServers, bridgeIP)
+			}
+		}
+
+		var dnsSearch []string
+		if n.config["dns.mode"] != "none" {
+			dnsDomain := n.config["dns.domain"]
+			if dnsDomain == "" {
+				dnsDomain = "incus"
+			}
+
+			dnsSearch = append(dnsSearch, dnsDomain)
+		}
+
+		err = dhcpv6pd.Start(n.name, dhcpv6pd.Config{
+			Pool:         store.pool,
+			PrefixLen:    store.prefixLen,
+			Lifetime:     lifetime,
+			DNSServers:   dnsServers,
+			DomainSearch: dnsSearch,
+			Store:        store,
+			Events: func(eventType string, lease dhcpv6pd.Lease) {
+				err := n.recordLease(eventType, lease.Hwaddr, lease.Prefix, "")
+				if err != nil {
+					n.logger.Warn("Failed recording lease event", logger.Ctx{"type": eventType, "prefix": lease.Prefix, "err": err})
+				}
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { dhcpv6pd.Stop(n.name) })
+	} else {
+		dhcpv6pd.Stop(n.name)
+
+		// Clean up old delegated prefixes.
+		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.DeleteNetworkPrefixDelegations(ctx, n.id, true)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed removing delegated prefixes: %w", err)
+		}
+	}
+
 	// Setup firewall.
 	n.logger.Debug("Setting up firewall")
 
@@ -2044,14 +2592,20 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 	}
 
-	// Setup network address forwards.
-	err = n.forwardSetupFirewall()
+	// Setup BGP.
+	err = n.bgpSetup(oldConfig)
 	if err != nil {
 		return err
 	}
 
-	// Setup BGP.
-	err = n.bgpSetup(oldConfig)
+	// Setup network address forwards and floating IPs.
+	err = n.floatingIPSetup()
+	if err != nil {
+		return err
+	}
+
+	// Setup QoS classes.
+	err = n.qosSetup(oldConfig)
 	if err != nil {
 		return err
 	}
@@ -2075,6 +2629,18 @@ func (n *bridge) Stop() error {
 		return err
 	}
 
+	// Withdraw floating IPs.
+	floatingIPs, err := n.floatingIPs()
+	if err != nil {
+		return err
+	}
+
+	for _, floatingIP := range floatingIPs {
+		n.floatingIPWithdraw(floatingIP.Address)
+	}
+
+	floatingIPTargetsDelete(n.id)
+
 	err = n.deleteChildren()
 	if err != nil {
 		return fmt.Errorf("Failed to delete bridge children interfaces: %w", err)
@@ -2118,12 +2684,31 @@ func (n *bridge) Stop() error {
 		}
 	}
 
+	// Stop the DHCPv6 prefix delegation server.
+	dhcpv6pd.Stop(n.name)
+
+	// Stop the NAT64 translator and DNS64 proxy.
+	err = nat64.Kill(n.name, bridgeNAT64Device(n.name))
+	if err != nil {
+		return err
+	}
+
 	// Kill any existing dnsmasq daemon for this network
 	err = dnsmasq.Kill(n.name, false)
 	if err != nil {
 		return err
 	}
 
+	dnsmasq.StopEvents(n.name)
+
+	// Stop the HTTP boot server and unmount the boot storage volume.
+	httpboot.Stop(n.name)
+
+	err = bridgeBootUnmount(n.state, n.project, n.name)
+	if err != nil {
+		return err
+	}
+
 	// Unload apparmor profiles.
 	err = apparmor.NetworkUnload(n.state.OS, n)
 	if err != nil {
@@ -2152,6 +2737,19 @@ func (n *bridge) Update(newNetwork api.NetworkPut, targetNode string, clientType
 		return nil // Nothing changed.
 	}
 
+	// Check the DHCP reservations still fit the new subnets and DHCP ranges.
+	if clientType == request.ClientTypeNormal && slices.ContainsFunc(changedKeys, func(key string) bool { return slices.Contains(reservationConfigKeys, key) }) {
+		newNet := &bridge{common: n.common}
+		newNet.config = newNetwork.Config
+
+		err = newNet.reservationsRevalidate(func(reservation *api.NetworkReservationPut) error {
+			return newNet.reservationValidateAddresses(reservation, newNet.DHCPv4Subnet(), newNet.DHCPv6Subnet())
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	// If the network as a whole has not had any previous creation attempts, or the node itself is still
 	// pending, then don't apply the new settings to the node, just to the database record (ready for the
 	// actual global create request to be initiated).
@@ -2566,6 +3164,7 @@ func (n *bridge) getExternalSubnetInUse() ([]externalSubnetUsage, error) {
 	var err error
 	var projectNetworks map[string]map[int64]api.Network
 	var projectNetworksForwardsOnUplink map[string]map[int64][]string
+	var floatingIPs []dbCluster.NetworkFloatingIP
 	var externalSubnets []externalSubnetUsage
 
 	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -2610,6 +3209,12 @@ func (n *bridge) getExternalSubnetInUse() ([]externalSubnetUsage, error) {
 			}
 		}
 
+		// Get all floating IPs as they can be announced from any cluster member.
+		floatingIPs, err = dbCluster.GetNetworkFloatingIPs(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed loading floating IPs: %w", err)
+		}
+
 		externalSubnets, err = n.common.getExternalSubnetInUse(ctx, tx, n.name, true)
 		if err != nil {
 			return fmt.Errorf("Failed getting external subnets in use: %w", err)
@@ -2698,6 +3303,28 @@ func (n *bridge) getExternalSubnetInUse() ([]externalSubnetUsage, error) {
 		}
 	}
 
+	// Add floating IP addresses to this list.
+	for _, floatingIP := range floatingIPs {
+		floatingIPNet, err := ParseIPToNet(floatingIP.Address)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid existing floating IP address %q", floatingIP.Address)
+		}
+
+		for projectName, networks := range projectNetworks {
+			network, ok := networks[floatingIP.NetworkID]
+			if !ok {
+				continue
+			}
+
+			externalSubnets = append(externalSubnets, externalSubnetUsage{
+				subnet:         *floatingIPNet,
+				networkProject: projectName,
+				networkName:    network.Name,
+				usageType:      subnetUsageNetworkFloatingIP,
+			})
+		}
+	}
+
 	return externalSubnets, nil
 }
 
@@ -3173,139 +3800,1273 @@ func (n *bridge) ForwardDelete(listenAddress string, clientType request.ClientTy
 	return nil
 }
 
-// forwardSetupFirewall applies all network address forwards defined for this network and this member.
-func (n *bridge) forwardSetupFirewall() error {
-	var forwards map[int64]*api.NetworkForward
+// ReservationCreate creates a DHCP reservation.
+func (n *bridge) ReservationCreate(reservation api.NetworkReservationsPost, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
 
-	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-		var err error
+	if clientType == request.ClientTypeNormal {
+		err := n.reservationValidate(reservation.Name, &reservation.NetworkReservationPut, n.DHCPv4Subnet(), n.DHCPv6Subnet())
+		if err != nil {
+			return err
+		}
 
-		networkID := n.ID()
-		dbRecords, err := dbCluster.GetNetworkForwards(ctx, tx.Tx(), dbCluster.NetworkForwardFilter{
-			NetworkID: &networkID,
-		})
+		err = n.reservationCreate(reservation)
 		if err != nil {
 			return err
 		}
 
-		forwards = make(map[int64]*api.NetworkForward)
-		for _, dbRecord := range dbRecords {
-			if !dbRecord.NodeID.Valid || (dbRecord.NodeID.Int64 == tx.GetNodeID()) {
-				forward, err := dbRecord.ToAPI(ctx, tx.Tx())
-				if err != nil {
-					return err
-				}
+		reverter.Add(func() { _ = n.reservationDelete(reservation.Name) })
 
-				forwards[dbRecord.ID] = forward
-			}
+		// Notify all other members to refresh their DHCP host entries.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
 		}
 
-		return err
-	})
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(n.project).CreateNetworkReservation(n.name, reservation)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Refresh DHCP host entries on local member.
+	err := UpdateDNSMasqStatic(n.state, n.name)
 	if err != nil {
-		return fmt.Errorf("Failed loading network forwards: %w", err)
+		return fmt.Errorf("Failed applying DHCP reservations: %w", err)
 	}
 
-	var fwForwards []firewallDrivers.AddressForward
-	ipVersions := make(map[uint]struct{})
+	reverter.Success()
 
-	for _, forward := range forwards {
-		// Convert listen address to subnet so we can check its valid and can be used.
-		listenAddressNet, err := ParseIPToNet(forward.ListenAddress)
+	return nil
+}
+
+// ReservationUpdate updates a DHCP reservation.
+func (n *bridge) ReservationUpdate(name string, req api.NetworkReservationPut, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		curReservationID, curReservation, err := n.reservationGet(name)
 		if err != nil {
-			return fmt.Errorf("Failed parsing address forward listen address %q: %w", forward.ListenAddress, err)
+			return err
 		}
 
-		// Track which IP versions we are using.
-		if listenAddressNet.IP.To4() == nil {
-			ipVersions[6] = struct{}{}
-		} else {
-			ipVersions[4] = struct{}{}
+		err = n.reservationValidate(name, &req, n.DHCPv4Subnet(), n.DHCPv6Subnet())
+		if err != nil {
+			return err
 		}
 
-		portMaps, err := n.forwardValidate(listenAddressNet.IP, &forward.NetworkForwardPut)
+		curReservationEtagHash, err := localUtil.EtagHash(curReservation.Etag())
 		if err != nil {
-			return fmt.Errorf("Failed validating firewall address forward for listen address %q: %w", forward.ListenAddress, err)
+			return err
 		}
 
-		fwForwards = append(fwForwards, n.forwardConvertToFirewallForwards(listenAddressNet.IP, net.ParseIP(forward.Config["target_address"]), portMaps)...)
-	}
+		newReservation := api.NetworkReservation{
+			Name:                  curReservation.Name,
+			NetworkReservationPut: req,
+		}
 
-	if len(forwards) > 0 {
-		// Check if br_netfilter is enabled to, and warn if not.
-		brNetfilterWarning := false
-		for ipVersion := range ipVersions {
-			err = BridgeNetfilterEnabled(ipVersion)
-			if err != nil {
-				brNetfilterWarning = true
-				msg := fmt.Sprintf("IPv%d bridge netfilter not enabled. Instances using the bridge will not be able to connect to the forward listen IPs", ipVersion)
-				n.logger.Warn(msg, logger.Ctx{"err": err})
-				err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-					return tx.UpsertWarningLocalNode(ctx, n.project, dbCluster.TypeNetwork, int(n.id), warningtype.ProxyBridgeNetfilterNotEnabled, fmt.Sprintf("%s: %v", msg, err))
-				})
-				if err != nil {
-					n.logger.Warn("Failed to create warning", logger.Ctx{"err": err})
-				}
-			}
+		newReservationEtagHash, err := localUtil.EtagHash(newReservation.Etag())
+		if err != nil {
+			return err
 		}
 
-		if !brNetfilterWarning {
-			err = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(n.state.DB.Cluster, n.project, warningtype.ProxyBridgeNetfilterNotEnabled, dbCluster.TypeNetwork, int(n.id))
-			if err != nil {
-				n.logger.Warn("Failed to resolve warning", logger.Ctx{"err": err})
-			}
+		if curReservationEtagHash == newReservationEtagHash {
+			return nil // Nothing has changed.
+		}
+
+		err = n.reservationUpdate(curReservationID, name, req)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { _ = n.reservationUpdate(curReservationID, name, curReservation.Writable()) })
+
+		// Notify all other members to refresh their DHCP host entries.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(n.project).UpdateNetworkReservation(n.name, name, req, "")
+		})
+		if err != nil {
+			return err
 		}
 	}
 
-	err = n.state.Firewall.NetworkApplyForwards(n.name, fwForwards)
+	// Refresh DHCP host entries on local member.
+	err := UpdateDNSMasqStatic(n.state, n.name)
 	if err != nil {
-		return fmt.Errorf("Failed applying firewall address forwards: %w", err)
+		return fmt.Errorf("Failed applying DHCP reservations: %w", err)
 	}
 
+	reverter.Success()
+
 	return nil
 }
 
-// Leases returns a list of leases for the bridged network. It will reach out to other cluster members as needed.
-// The projectName passed here refers to the initial project from the API request which may differ from the network's project.
-func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]api.NetworkLease, error) {
-	var err error
-	var projectMacs []string
-	leases := []api.NetworkLease{}
+// ReservationDelete deletes a DHCP reservation.
+func (n *bridge) ReservationDelete(name string, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
 
-	// Get all static leases.
 	if clientType == request.ClientTypeNormal {
-		// If requested project matches network's project then include gateway and downstream uplink IPs.
-		if projectName == n.project {
-			// Add our own gateway IPs.
-			for _, addr := range []string{n.config["ipv4.address"], n.config["ipv6.address"]} {
-				ip, _, _ := net.ParseCIDR(addr)
-				if ip != nil {
-					leases = append(leases, api.NetworkLease{
-						Hostname: fmt.Sprintf("%s.gw", n.Name()),
-						Address:  ip.String(),
-						Type:     "gateway",
-					})
-				}
-			}
+		_, reservation, err := n.reservationGet(name)
+		if err != nil {
+			return err
+		}
 
-			// Include downstream OVN routers using the network as an uplink.
-			var projectNetworks map[string]map[int64]api.Network
-			err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-				projectNetworks, err = tx.GetCreatedNetworks(ctx)
-				return err
+		err = n.reservationDelete(name)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() {
+			_ = n.reservationCreate(api.NetworkReservationsPost{
+				Name:                  reservation.Name,
+				NetworkReservationPut: reservation.Writable(),
 			})
-			if err != nil {
-				return nil, err
-			}
+		})
 
-			// Look for networks using the current network as an uplink.
-			for projectName, networks := range projectNetworks {
-				for _, network := range networks {
-					if network.Config["network"] != n.name {
-						continue
-					}
+		// Notify all other members to refresh their DHCP host entries.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
 
-					// Found a network, add leases.
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(n.project).DeleteNetworkReservation(n.name, name)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Refresh DHCP host entries on local member.
+	err := UpdateDNSMasqStatic(n.state, n.name)
+	if err != nil {
+		return fmt.Errorf("Failed applying DHCP reservations: %w", err)
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// floatingIPValidate validates a floating IP and checks it doesn't conflict with other addresses in use.
+func (n *bridge) floatingIPValidate(projectName string, address string, req *api.NetworkFloatingIPPut) error {
+	floatingIPAddress := net.ParseIP(address)
+	if floatingIPAddress == nil || !floatingIPAddress.IsGlobalUnicast() {
+		return fmt.Errorf("Invalid floating IP address %q", address)
+	}
+
+	// The floating IP must be external to the network.
+	for _, key := range []string{"ipv4.address", "ipv6.address"} {
+		_, subnet, _ := net.ParseCIDR(n.config[key])
+		if subnet != nil && subnet.Contains(floatingIPAddress) {
+			return fmt.Errorf("Floating IP address %q is within the network's subnet %q", address, subnet.String())
+		}
+	}
+
+	// Check the instance exists and is connected to the network.
+	if req.Instance != "" {
+		found := false
+
+		err := UsedByInstanceDevices(n.state, n.project, n.name, n.netType, func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
+			found = true
+
+			return nil
+		}, dbCluster.InstanceFilter{Project: &projectName, Name: &req.Instance})
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			return api.StatusErrorf(http.StatusBadRequest, "Instance %q in project %q isn't connected to the network", req.Instance, projectName)
+		}
+	}
+
+	err := floatingIPValidateConfig(req.Config)
+	if err != nil {
+		return err
+	}
+
+	// Bridge networks route the floating IPs to their instance themselves.
+	if req.Config["target.address"] != "" {
+		return errors.New("The \"target.address\" option is only supported on physical networks")
+	}
+
+	externalSubnetsInUse, err := n.getExternalSubnetInUse()
+	if err != nil {
+		return err
+	}
+
+	floatingIPNet, err := ParseIPToNet(address)
+	if err != nil {
+		return err
+	}
+
+	// Check the address doesn't fall within any existing network external subnets.
+	for _, externalSubnetUser := range externalSubnetsInUse {
+		// Skip our own floating IP when updating it.
+		if externalSubnetUser.usageType == subnetUsageNetworkFloatingIP && externalSubnetUser.networkProject == n.project && externalSubnetUser.networkName == n.name && externalSubnetUser.subnet.IP.Equal(floatingIPAddress) {
+			continue
+		}
+
+		if SubnetContains(&externalSubnetUser.subnet, floatingIPNet) || SubnetContains(floatingIPNet, &externalSubnetUser.subnet) {
+			// This error is purposefully vague so that it doesn't reveal any names of
+			// resources potentially outside of the network.
+			return fmt.Errorf("Floating IP address %q overlaps with another network or NIC", address)
+		}
+	}
+
+	return nil
+}
+
+// floatingIPTargets returns the instance address each floating IP of the network currently maps to.
+// Only floating IPs whose instance is running on this member with a known address are returned.
+func (n *bridge) floatingIPTargets(floatingIPs []api.NetworkFloatingIP, filters ...dbCluster.InstanceFilter) (map[string]net.IP, error) {
+	targets := make(map[string]net.IP)
+
+	if len(floatingIPs) == 0 {
+		return targets, nil
+	}
+
+	err := UsedByInstanceDevices(n.state, n.project, n.name, n.netType, func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
+		if inst.Node != n.state.ServerName {
+			return nil
+		}
+
+		// Skip NICs that aren't currently active on this member.
+		hostName := inst.Config[fmt.Sprintf("volatile.%s.host_name", nicName)]
+		if hostName == "" || !InterfaceExists(hostName) {
+			return nil
+		}
+
+		hwaddr := nicConfig["hwaddr"]
+		if hwaddr == "" {
+			hwaddr = inst.Config[fmt.Sprintf("volatile.%s.hwaddr", nicName)]
+		}
+
+		mac, _ := net.ParseMAC(hwaddr)
+
+		for _, floatingIP := range floatingIPs {
+			if floatingIP.Project != inst.Project || floatingIP.Instance != inst.Name || targets[floatingIP.Address] != nil {
+				continue
+			}
+
+			ipVersion := 4
+			if net.ParseIP(floatingIP.Address).To4() == nil {
+				ipVersion = 6
+			}
+
+			// Prefer a statically configured address over a DHCP lease.
+			targetAddress := net.ParseIP(nicConfig[fmt.Sprintf("ipv%d.address", ipVersion)])
+			if targetAddress == nil && mac != nil {
+				leaseAddresses, _ := GetLeaseAddresses(n.name, mac.String())
+				for _, leaseAddress := range leaseAddresses {
+					if (leaseAddress.To4() != nil) == (ipVersion == 4) && leaseAddress.IsGlobalUnicast() {
+						targetAddress = leaseAddress
+						break
+					}
+				}
+			}
+
+			if targetAddress != nil {
+				targets[floatingIP.Address] = targetAddress
+			}
+		}
+
+		return nil
+	}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// floatingIPAnnounceInterface returns the host interface a floating IP should be announced on.
+func (n *bridge) floatingIPAnnounceInterface(floatingIP api.NetworkFloatingIP) string {
+	if floatingIP.Config["announce.interface"] != "" {
+		return floatingIP.Config["announce.interface"]
+	}
+
+	ifaceName, err := interfaceForSubnetIP(net.ParseIP(floatingIP.Address))
+	if err != nil {
+		n.logger.Warn("Failed finding interface for floating IP", logger.Ctx{"address": floatingIP.Address, "err": err})
+	}
+
+	return ifaceName
+}
+
+// floatingIPWithdraw stops attracting traffic for a floating IP on this member.
+// Neighbour proxy entries are removed from all interfaces as the announce interface may have changed since.
+func (n *bridge) floatingIPWithdraw(address string) {
+	floatingIPNet, err := ParseIPToNet(address)
+	if err != nil {
+		return
+	}
+
+	ifaces, err := net.Interfaces()
+	if err == nil {
+		for _, iface := range ifaces {
+			neighProxy := ip.NeighProxy{DevName: iface.Name, Addr: floatingIPNet.IP}
+			_ = neighProxy.Delete()
+		}
+	}
+
+	if InterfaceExists(n.name) {
+		route := ip.Route{DevName: n.name, Route: floatingIPNet, Proto: "boot", Family: ip.FamilyV4}
+		if floatingIPNet.IP.To4() == nil {
+			route.Family = ip.FamilyV6
+		}
+
+		_ = route.Delete()
+	}
+
+	_ = n.state.BGP.RemovePrefixByOwner(floatingIPBGPOwner(n.id, address))
+}
+
+// floatingIPAnnounce attracts traffic for the floating IPs whose instance runs on this member and withdraws
+// the others. Addresses are announced to neighbours through gratuitous ARP or unsolicited neighbour
+// advertisements and, if requested, exported through the BGP server.
+func (n *bridge) floatingIPAnnounce(floatingIPs []api.NetworkFloatingIP, targets map[string]net.IP) error {
+	for _, floatingIP := range floatingIPs {
+		if targets[floatingIP.Address] == nil {
+			n.floatingIPWithdraw(floatingIP.Address)
+			continue
+		}
+
+		address := net.ParseIP(floatingIP.Address)
+
+		floatingIPNet, err := ParseIPToNet(floatingIP.Address)
+		if err != nil {
+			return err
+		}
+
+		ipVersion := uint(4)
+		route := ip.Route{DevName: n.name, Route: floatingIPNet, Proto: "boot", Family: ip.FamilyV4}
+		if address.To4() == nil {
+			ipVersion = 6
+			route.Family = ip.FamilyV6
+		}
+
+		// Route the address towards the bridge so that the host answers neighbour requests for it.
+		err = route.Replace()
+		if err != nil {
+			return fmt.Errorf("Failed adding route for floating IP %q: %w", floatingIP.Address, err)
+		}
+
+		ifaceName := n.floatingIPAnnounceInterface(floatingIP)
+		if ifaceName != "" {
+			// Neighbour proxy entries are only used for NDP when proxy_ndp is enabled on the interface.
+			if ipVersion == 6 {
+				ipv6ProxyNdpPath := fmt.Sprintf("net/ipv6/conf/%s/proxy_ndp", ifaceName)
+				err = localUtil.SysctlSet(ipv6ProxyNdpPath, "1")
+				if err != nil {
+					return fmt.Errorf("Error setting net sysctl %s: %w", ipv6ProxyNdpPath, err)
+				}
+			}
+
+			neighProxy := ip.NeighProxy{DevName: ifaceName, Addr: address}
+			_ = neighProxy.Delete()
+
+			err = neighProxy.Add()
+			if err != nil {
+				return fmt.Errorf("Failed adding neighbour proxy for floating IP %q on %q: %w", floatingIP.Address, ifaceName, err)
+			}
+
+			err = announceAddress(ifaceName, address)
+			if err != nil {
+				n.logger.Warn("Failed announcing floating IP", logger.Ctx{"address": floatingIP.Address, "interface": ifaceName, "err": err})
+			}
+		}
+
+		bgpOwner := floatingIPBGPOwner(n.id, floatingIP.Address)
+		err = n.state.BGP.RemovePrefixByOwner(bgpOwner)
+		if err != nil {
+			return err
+		}
+
+		if util.IsTrue(floatingIP.Config["announce.bgp"]) {
+			err = n.state.BGP.AddPrefix(*floatingIPNet, n.bgpNextHopAddress(ipVersion), bgpOwner)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// floatingIPSetup applies the firewall rules and announcements for all the floating IPs of the network.
+func (n *bridge) floatingIPSetup() error {
+	unlock, err := locking.Lock(context.TODO(), floatingIPLockName(n.id))
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	floatingIPs, err := n.floatingIPs()
+	if err != nil {
+		return fmt.Errorf("Failed loading floating IPs: %w", err)
+	}
+
+	targets, err := n.floatingIPTargets(floatingIPs)
+	if err != nil {
+		return fmt.Errorf("Failed getting floating IP targets: %w", err)
+	}
+
+	floatingIPTargetsSet(n.id, targets)
+
+	err = n.forwardSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	err = n.floatingIPAnnounce(floatingIPs, targets)
+	if err != nil {
+		return fmt.Errorf("Failed announcing floating IPs: %w", err)
+	}
+
+	return nil
+}
+
+// FloatingIPRefresh re-applies the floating IPs of the network targeting the instance on this member.
+// It is called whenever an instance NIC connected to the network starts or stops, or its DHCP lease changes,
+// so that the floating IPs follow their instance as it moves between cluster members.
+// The firewall is only re-applied if the target of one of those floating IPs changed.
+func (n *bridge) FloatingIPRefresh(projectName string, instanceName string) error {
+	if !n.isRunning() {
+		return nil
+	}
+
+	floatingIPs, err := n.instanceFloatingIPs(projectName, instanceName)
+	if err != nil {
+		return fmt.Errorf("Failed loading floating IPs: %w", err)
+	}
+
+	if len(floatingIPs) == 0 {
+		return nil
+	}
+
+	// Apply all the floating IPs if none were applied yet.
+	_, found := floatingIPTargetsGet(n.id)
+	if !found {
+		return n.floatingIPSetup()
+	}
+
+	unlock, err := locking.Lock(context.TODO(), floatingIPLockName(n.id))
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	targets, err := n.floatingIPTargets(floatingIPs, dbCluster.InstanceFilter{Project: &projectName, Name: &instanceName})
+	if err != nil {
+		return fmt.Errorf("Failed getting floating IP targets: %w", err)
+	}
+
+	changed := floatingIPTargetsUpdate(n.id, floatingIPs, targets)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	err = n.forwardSetupFirewall()
+	if err != nil {
+		return err
+	}
+
+	err = n.floatingIPAnnounce(changed, targets)
+	if err != nil {
+		return fmt.Errorf("Failed announcing floating IPs: %w", err)
+	}
+
+	return nil
+}
+
+// FloatingIPCreate creates a floating IP.
+func (n *bridge) FloatingIPCreate(projectName string, floatingIP api.NetworkFloatingIPsPost, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		err := n.floatingIPValidate(projectName, floatingIP.Address, &floatingIP.NetworkFloatingIPPut)
+		if err != nil {
+			return err
+		}
+
+		err = n.floatingIPCreate(projectName, floatingIP)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { _ = n.floatingIPDelete(floatingIP.Address) })
+
+		// Notify all other members so that whichever hosts the instance announces the address.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(projectName).CreateNetworkFloatingIP(n.name, floatingIP)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Apply the floating IPs on local member.
+	err := n.floatingIPSetup()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// FloatingIPUpdate updates a floating IP.
+func (n *bridge) FloatingIPUpdate(address string, req api.NetworkFloatingIPPut, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		curFloatingIPID, curFloatingIP, err := n.floatingIPGet(address)
+		if err != nil {
+			return err
+		}
+
+		err = n.floatingIPValidate(curFloatingIP.Project, address, &req)
+		if err != nil {
+			return err
+		}
+
+		curFloatingIPEtagHash, err := localUtil.EtagHash(curFloatingIP.Etag())
+		if err != nil {
+			return err
+		}
+
+		newFloatingIP := api.NetworkFloatingIP{
+			Address:              curFloatingIP.Address,
+			NetworkFloatingIPPut: req,
+		}
+
+		newFloatingIPEtagHash, err := localUtil.EtagHash(newFloatingIP.Etag())
+		if err != nil {
+			return err
+		}
+
+		if curFloatingIPEtagHash == newFloatingIPEtagHash {
+			return nil // Nothing has changed.
+		}
+
+		err = n.floatingIPUpdate(curFloatingIPID, curFloatingIP.Project, address, req)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() {
+			_ = n.floatingIPUpdate(curFloatingIPID, curFloatingIP.Project, address, curFloatingIP.Writable())
+			_ = n.floatingIPSetup()
+		})
+
+		// Notify all other members so that the announcements move with the change.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(curFloatingIP.Project).UpdateNetworkFloatingIP(n.name, address, req, "")
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Withdraw the previous announcement in case the instance or announce interface changed.
+	n.floatingIPWithdraw(address)
+
+	// Apply the floating IPs on local member.
+	err := n.floatingIPSetup()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// FloatingIPDelete deletes a floating IP.
+func (n *bridge) FloatingIPDelete(address string, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		_, floatingIP, err := n.floatingIPGet(address)
+		if err != nil {
+			return err
+		}
+
+		err = n.floatingIPDelete(address)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() {
+			_ = n.floatingIPCreate(floatingIP.Project, api.NetworkFloatingIPsPost{
+				Address:              floatingIP.Address,
+				NetworkFloatingIPPut: floatingIP.Writable(),
+			})
+			_ = n.floatingIPSetup()
+		})
+
+		// Notify all other members so that the address is withdrawn everywhere.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(floatingIP.Project).DeleteNetworkFloatingIP(n.name, address)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	n.floatingIPWithdraw(address)
+
+	// Apply the remaining floating IPs on local member.
+	err := n.floatingIPSetup()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// forwardFirewallForwards returns the firewall address forwards of the network's forwards and of the floating IPs
+// whose instance is running on this member, along with the IP versions they use.
+func (n *bridge) forwardFirewallForwards() ([]firewallDrivers.AddressForward, map[uint]struct{}, error) {
+	var forwards map[int64]*api.NetworkForward
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		networkID := n.ID()
+		dbRecords, err := dbCluster.GetNetworkForwards(ctx, tx.Tx(), dbCluster.NetworkForwardFilter{
+			NetworkID: &networkID,
+		})
+		if err != nil {
+			return err
+		}
+
+		forwards = make(map[int64]*api.NetworkForward)
+		for _, dbRecord := range dbRecords {
+			if !dbRecord.NodeID.Valid || (dbRecord.NodeID.Int64 == tx.GetNodeID()) {
+				forward, err := dbRecord.ToAPI(ctx, tx.Tx())
+				if err != nil {
+					return err
+				}
+
+				forwards[dbRecord.ID] = forward
+			}
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed loading network forwards: %w", err)
+	}
+
+	var fwForwards []firewallDrivers.AddressForward
+	ipVersions := make(map[uint]struct{})
+
+	for _, forward := range forwards {
+		// Convert listen address to subnet so we can check its valid and can be used.
+		listenAddressNet, err := ParseIPToNet(forward.ListenAddress)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed parsing address forward listen address %q: %w", forward.ListenAddress, err)
+		}
+
+		// Track which IP versions we are using.
+		if listenAddressNet.IP.To4() == nil {
+			ipVersions[6] = struct{}{}
+		} else {
+			ipVersions[4] = struct{}{}
+		}
+
+		portMaps, err := n.forwardValidate(listenAddressNet.IP, &forward.NetworkForwardPut)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed validating firewall address forward for listen address %q: %w", forward.ListenAddress, err)
+		}
+
+		fwForwards = append(fwForwards, n.forwardConvertToFirewallForwards(listenAddressNet.IP, net.ParseIP(forward.Config["target_address"]), portMaps)...)
+	}
+
+	// Add the floating IPs whose instance is running on this member.
+	floatingIPTargets, found := floatingIPTargetsGet(n.id)
+	if !found {
+		floatingIPs, err := n.floatingIPs()
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed loading floating IPs: %w", err)
+		}
+
+		floatingIPTargets, err = n.floatingIPTargets(floatingIPs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed getting floating IP targets: %w", err)
+		}
+	}
+
+	for address, targetAddress := range floatingIPTargets {
+		listenAddress := net.ParseIP(address)
+
+		if listenAddress.To4() == nil {
+			ipVersions[6] = struct{}{}
+		} else {
+			ipVersions[4] = struct{}{}
+		}
+
+		fwForwards = append(fwForwards, firewallDrivers.AddressForward{
+			ListenAddress: listenAddress,
+			TargetAddress: targetAddress,
+		})
+	}
+
+	return fwForwards, ipVersions, nil
+}
+
+// forwardSetupFirewall applies all network address forwards defined for this network and this member, as well
+// as the floating IPs whose instance is running on this member.
+func (n *bridge) forwardSetupFirewall() error {
+	fwForwards, ipVersions, err := n.forwardFirewallForwards()
+	if err != nil {
+		return err
+	}
+
+	if len(ipVersions) > 0 {
+		// Check if br_netfilter is enabled to, and warn if not.
+		brNetfilterWarning := false
+		for ipVersion := range ipVersions {
+			err = BridgeNetfilterEnabled(ipVersion)
+			if err != nil {
+				brNetfilterWarning = true
+				msg := fmt.Sprintf("IPv%d bridge netfilter not enabled. Instances using the bridge will not be able to connect to the forward listen IPs", ipVersion)
+				n.logger.Warn(msg, logger.Ctx{"err": err})
+				err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+					return tx.UpsertWarningLocalNode(ctx, n.project, dbCluster.TypeNetwork, int(n.id), warningtype.ProxyBridgeNetfilterNotEnabled, fmt.Sprintf("%s: %v", msg, err))
+				})
+				if err != nil {
+					n.logger.Warn("Failed to create warning", logger.Ctx{"err": err})
+				}
+			}
+		}
+
+		if !brNetfilterWarning {
+			err = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(n.state.DB.Cluster, n.project, warningtype.ProxyBridgeNetfilterNotEnabled, dbCluster.TypeNetwork, int(n.id))
+			if err != nil {
+				n.logger.Warn("Failed to resolve warning", logger.Ctx{"err": err})
+			}
+		}
+	}
+
+	err = n.state.Firewall.NetworkApplyForwards(n.name, fwForwards)
+	if err != nil {
+		return fmt.Errorf("Failed applying firewall address forwards: %w", err)
+	}
+
+	return nil
+}
+
+// qosValidate checks the QoS classes of the network fit within its total rate.
+func (n *bridge) qosValidate(config map[string]string) error {
+	classNames := QoSClassNames(config)
+
+	if config["qos.rate"] == "" {
+		if len(classNames) > 0 {
+			return errors.New(`"qos.class.*" requires "qos.rate" to be set`)
+		}
+
+		return nil
+	}
+
+	if len(bridgeQoSDevice(n.name)) > 15 {
+		return errors.New(`The network name is too long to be used with "qos.rate"`)
+	}
+
+	totalRate, err := units.ParseBitSizeString(config["qos.rate"])
+	if err != nil {
+		return fmt.Errorf("Failed parsing qos.rate: %w", err)
+	}
+
+	var guaranteedTotal int64
+	minors := map[uint32]string{}
+
+	for _, className := range classNames {
+		guaranteed, maxRate, err := qosClassRates(config, className, totalRate)
+		if err != nil {
+			return fmt.Errorf("Invalid rate for QoS class %q: %w", className, err)
+		}
+
+		if maxRate > totalRate {
+			return fmt.Errorf(`The maximum rate of QoS class %q cannot exceed "qos.rate"`, className)
+		}
+
+		if guaranteed > maxRate {
+			return fmt.Errorf("The guaranteed rate of QoS class %q cannot exceed its maximum rate", className)
+		}
+
+		guaranteedTotal += guaranteed
+
+		minor := qosClassMinor(className)
+		if minors[minor] != "" {
+			return fmt.Errorf("QoS classes %q and %q cannot be used together, rename one of them", minors[minor], className)
+		}
+
+		minors[minor] = className
+	}
+
+	if guaranteedTotal > totalRate {
+		return errors.New(`The guaranteed rates of the QoS classes cannot add up to more than "qos.rate"`)
+	}
+
+	return nil
+}
+
+// qosExternalInterfaces returns the names of the external interfaces of the network config.
+func (n *bridge) qosExternalInterfaces(config map[string]string) []string {
+	ifNames := []string{}
+
+	for _, entry := range util.SplitNTrimSpace(config["bridge.external_interfaces"], ",", -1, true) {
+		ifNames = append(ifNames, strings.TrimSpace(strings.Split(entry, "/")[0]))
+	}
+
+	return ifNames
+}
+
+// qosSetup sets up the shaping of the traffic leaving the network through the host or its external interfaces
+// according to its QoS classes. The traffic is redirected to a dedicated device whose HTB hierarchy has one class
+// per QoS class, each with its own fq_codel queue.
+func (n *bridge) qosSetup(oldConfig map[string]string) error {
+	devName := bridgeQoSDevice(n.name)
+
+	// Stop redirecting the traffic of the previous external interfaces, those still in use are set up again below.
+	if oldConfig != nil {
+		err := n.qosClearExternalInterfaces(oldConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	if n.config["qos.rate"] == "" {
+		return n.qosClear()
+	}
+
+	totalRate, err := units.ParseBitSizeString(n.config["qos.rate"])
+	if err != nil {
+		return fmt.Errorf("Failed parsing qos.rate: %w", err)
+	}
+
+	// Check the external interfaces can be used before changing anything.
+	externalInterfaces := []string{}
+	ownedInterfaces := map[string]bool{}
+	for _, ifName := range n.qosExternalInterfaces(n.config) {
+		if !InterfaceExists(ifName) {
+			continue
+		}
+
+		owned, err := n.qosExternalInterfaceOwned(ifName)
+		if err != nil {
+			return err
+		}
+
+		externalInterfaces = append(externalInterfaces, ifName)
+		ownedInterfaces[ifName] = owned
+	}
+
+	if !InterfaceExists(devName) {
+		ifb := &ip.Ifb{Link: ip.Link{Name: devName}}
+		err := ifb.Add()
+		if err != nil {
+			return fmt.Errorf("Failed to create QoS device %q: %w", devName, err)
+		}
+	}
+
+	link := &ip.Link{Name: devName}
+	err = link.SetUp()
+	if err != nil {
+		return fmt.Errorf("Failed to bring up QoS device %q: %w", devName, err)
+	}
+
+	// Rebuild the class hierarchy.
+	qdiscHTB := &ip.QdiscHTB{Qdisc: ip.Qdisc{Dev: devName, Handle: "1:0", Parent: "root"}}
+	err = qdiscHTB.Delete()
+	if err != nil && !errors.Is(err, unix.ENOENT) {
+		return err
+	}
+
+	classNames := QoSClassNames(n.config)
+
+	defaultMinor := uint32(qosDefaultClassMinor)
+	if slices.Contains(classNames, "default") {
+		defaultMinor = qosClassMinor("default")
+	}
+
+	qdiscHTB = &ip.QdiscHTB{Qdisc: ip.Qdisc{Dev: devName, Handle: "1:0", Parent: "root"}, Default: defaultMinor}
+	err = qdiscHTB.Add()
+	if err != nil {
+		return fmt.Errorf("Failed to create QoS root tc qdisc: %w", err)
+	}
+
+	classHTB := &ip.ClassHTB{Class: ip.Class{Dev: devName, Parent: "1:0", Classid: "1:1"}, Rate: fmt.Sprintf("%dbit", totalRate)}
+	err = classHTB.Add()
+	if err != nil {
+		return fmt.Errorf("Failed to create QoS tc class: %w", err)
+	}
+
+	addClass := func(minor uint32, guaranteed int64, maxRate int64, priority uint32) error {
+		classHTB := &ip.ClassHTB{
+			Class:    ip.Class{Dev: devName, Parent: "1:1", Classid: fmt.Sprintf("1:%x", minor)},
+			Rate:     fmt.Sprintf("%dbit", guaranteed),
+			Ceil:     fmt.Sprintf("%dbit", maxRate),
+			Priority: priority,
+		}
+
+		err := classHTB.Add()
+		if err != nil {
+			return fmt.Errorf("Failed to create QoS tc class: %w", err)
+		}
+
+		qdiscFqCodel := &ip.QdiscFqCodel{Qdisc: ip.Qdisc{Dev: devName, Handle: fmt.Sprintf("%x:0", minor), Parent: fmt.Sprintf("1:%x", minor)}}
+		err = qdiscFqCodel.Add()
+		if err != nil {
+			return fmt.Errorf("Failed to create QoS tc qdisc: %w", err)
+		}
+
+		return nil
+	}
+
+	var guaranteedTotal int64
+	for _, className := range classNames {
+		guaranteed, maxRate, err := qosClassRates(n.config, className, totalRate)
+		if err != nil {
+			return err
+		}
+
+		var priority uint64
+		if n.config[fmt.Sprintf("qos.class.%s.priority", className)] != "" {
+			priority, err = strconv.ParseUint(n.config[fmt.Sprintf("qos.class.%s.priority", className)], 10, 32)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = addClass(qosClassMinor(className), guaranteed, maxRate, uint32(priority))
+		if err != nil {
+			return err
+		}
+
+		guaranteedTotal += guaranteed
+	}
+
+	// The traffic of the NICs not selecting a class gets what's left, at the lowest priority.
+	if defaultMinor == qosDefaultClassMinor {
+		err = addClass(defaultMinor, max(totalRate-guaranteedTotal, qosDefaultClassMinRate), totalRate, 7)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Redirect the traffic entering the host through the bridge.
+	qdiscIngress := &ip.QdiscIngress{Qdisc: ip.Qdisc{Dev: n.name, Handle: "ffff:0"}}
+	err = qdiscIngress.Delete()
+	if err != nil && !errors.Is(err, unix.ENOENT) {
+		return err
+	}
+
+	err = qdiscIngress.Add()
+	if err != nil {
+		return fmt.Errorf("Failed to create QoS ingress tc qdisc: %w", err)
+	}
+
+	err = n.qosRedirect(n.name, "ffff:0", n.qosHostAddresses())
+	if err != nil {
+		return fmt.Errorf("Failed to create QoS ingress tc filter: %w", err)
+	}
+
+	// Redirect the traffic leaving through the external interfaces.
+	for _, ifName := range externalInterfaces {
+		if ownedInterfaces[ifName] {
+			qdiscHTB := &ip.QdiscHTB{Qdisc: ip.Qdisc{Dev: ifName, Handle: "1:0", Parent: "root"}}
+			err := qdiscHTB.Delete()
+			if err != nil && !errors.Is(err, unix.ENOENT) {
+				return err
+			}
+		}
+
+		qdiscHTB := &ip.QdiscHTB{Qdisc: ip.Qdisc{Dev: ifName, Handle: "1:0", Parent: "root"}}
+		err = qdiscHTB.Add()
+		if err != nil {
+			return fmt.Errorf("Failed to create QoS root tc qdisc on %q: %w", ifName, err)
+		}
+
+		err = n.qosRedirect(ifName, "1:0", nil)
+		if err != nil {
+			return fmt.Errorf("Failed to create QoS tc filter on %q: %w", ifName, err)
+		}
+	}
+
+	// Apply the DSCP values of the classes to the running NICs.
+	if oldConfig != nil && !maps.Equal(qosConfig(oldConfig), qosConfig(n.config)) {
+		err = n.qosRefreshNICs()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// qosClearExternalInterfaces stops redirecting the traffic leaving through the external interfaces of the network
+// config to the QoS device.
+func (n *bridge) qosClearExternalInterfaces(config map[string]string) error {
+	if config["qos.rate"] == "" {
+		return nil
+	}
+
+	for _, ifName := range n.qosExternalInterfaces(config) {
+		if !InterfaceExists(ifName) {
+			continue
+		}
+
+		// Leave the root qdisc alone unless it was set up by the network.
+		owned, err := n.qosExternalInterfaceOwned(ifName)
+		if err != nil || !owned {
+			continue
+		}
+
+		qdiscHTB := &ip.QdiscHTB{Qdisc: ip.Qdisc{Dev: ifName, Handle: "1:0", Parent: "root"}}
+		err = qdiscHTB.Delete()
+		if err != nil && !errors.Is(err, unix.ENOENT) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// qosExternalInterfaceOwned returns whether the root qdisc of an external interface was set up by the network.
+// It fails when the interface has a root qdisc set up by something else, which must not be replaced.
+func (n *bridge) qosExternalInterfaceOwned(ifName string) (bool, error) {
+	qdisc, kind, err := ip.GetRootQdisc(ifName)
+	if err != nil {
+		return false, err
+	}
+
+	if qdisc == nil {
+		return false, nil
+	}
+
+	if kind == "htb" && qdisc.Handle == "1:0" {
+		devNames, err := ip.GetFilterRedirects(ifName, qdisc.Handle)
+		if err != nil {
+			return false, err
+		}
+
+		if slices.Contains(devNames, bridgeQoSDevice(n.name)) {
+			return true, nil
+		}
+	}
+
+	return false, fmt.Errorf("External interface %q already has a %s root qdisc (%s) which isn't managed by the network", ifName, kind, qdisc.Handle)
+}
+
+// qosHostAddresses returns the addresses of the host on the network.
+func (n *bridge) qosHostAddresses() []net.IP {
+	addresses := []net.IP{}
+
+	for _, key := range []string{"ipv4.address", "ipv6.address"} {
+		address, _, err := net.ParseCIDR(n.config[key])
+		if err == nil {
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses
+}
+
+// qosRedirect adds the filters to the parent of the device redirecting the traffic, except the control traffic of the
+// network and the traffic sent to the host addresses, to the QoS device of the network.
+func (n *bridge) qosRedirect(dev string, parent string, hostAddresses []net.IP) error {
+	for _, filter := range qosControlFilters(dev, parent, hostAddresses) {
+		err := filter.Add()
+		if err != nil {
+			return err
+		}
+	}
+
+	filter := &ip.U32Filter{Filter: ip.Filter{Dev: dev, Parent: parent, Protocol: "all", Priority: qosRedirectPriority}, Value: 0, Mask: 0, Actions: []ip.Action{&ip.ActionMirred{Dev: bridgeQoSDevice(n.name), Redirect: true}}}
+
+	return filter.Add()
+}
+
+// qosClear removes the QoS device of the network and stops redirecting the traffic entering the host to it.
+func (n *bridge) qosClear() error {
+	devName := bridgeQoSDevice(n.name)
+	if !InterfaceExists(devName) {
+		return nil
+	}
+
+	if InterfaceExists(n.name) {
+		qdiscIngress := &ip.QdiscIngress{Qdisc: ip.Qdisc{Dev: n.name, Handle: "ffff:0"}}
+		err := qdiscIngress.Delete()
+		if err != nil && !errors.Is(err, unix.ENOENT) {
+			return err
+		}
+	}
+
+	err := InterfaceRemove(devName)
+	if err != nil {
+		return fmt.Errorf("Failed to remove QoS device %q: %w", devName, err)
+	}
+
+	return nil
+}
+
+// qosRefreshNICs re-applies the QoS class of the running local NICs connected to the network.
+func (n *bridge) qosRefreshNICs() error {
+	return UsedByInstanceDevices(n.state, n.project, n.name, n.netType, func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
+		if inst.Node != n.state.ServerName || nicConfig["qos.class"] == "" {
+			return nil
+		}
+
+		hostName := inst.Config[fmt.Sprintf("volatile.%s.host_name", nicName)]
+		if hostName == "" || !InterfaceExists(hostName) {
+			return nil
+		}
+
+		className := nicConfig["qos.class"]
+		if !QoSClassExists(n.config, className) {
+			n.logger.Warn("QoS class of NIC isn't defined anymore", logger.Ctx{"project": inst.Project, "instance": inst.Name, "device": nicName, "class": className})
+			className = ""
+		}
+
+		return QoSNICSetup(n.state, inst.Project, inst.Name, hostName, n.config, className)
+	})
+}
+
+// qosState returns the statistics of the QoS classes of the network.
+func (n *bridge) qosState() ([]api.NetworkStateQoSClass, error) {
+	devName := bridgeQoSDevice(n.name)
+	if n.config["qos.rate"] == "" || !InterfaceExists(devName) {
+		return nil, nil
+	}
+
+	classNames := QoSClassNames(n.config)
+
+	minors := map[string]uint32{}
+	for _, className := range classNames {
+		minors[className] = qosClassMinor(className)
+	}
+
+	if !slices.Contains(classNames, "default") {
+		classNames = append(classNames, "default")
+		minors["default"] = qosDefaultClassMinor
+	}
+
+	classes := make([]api.NetworkStateQoSClass, 0, len(classNames))
+	for _, className := range classNames {
+		class := &ip.Class{Dev: devName, Classid: fmt.Sprintf("1:%x", minors[className])}
+		stats, err := class.Stats()
+		if err != nil {
+			return nil, err
+		}
+
+		classes = append(classes, api.NetworkStateQoSClass{
+			Name:           className,
+			BytesSent:      int64(stats.Bytes),
+			PacketsSent:    int64(stats.Packets),
+			PacketsDropped: int64(stats.Drops),
+			Overlimits:     int64(stats.Overlimits),
+			Backlog:        int64(stats.Backlog),
+		})
+	}
+
+	return classes, nil
+}
+
+// State returns the network state, including the statistics of its QoS classes.
+func (n *bridge) State() (*api.NetworkState, error) {
+	state, err := n.common.State()
+	if err != nil {
+		return nil, err
+	}
+
+	state.QoS, err = n.qosState()
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting QoS statistics: %w", err)
+	}
+
+	return state, nil
+}
+
+// Leases returns a list of leases for the bridged network. It will reach out to other cluster members as needed.
+// The projectName passed here refers to the initial project from the API request which may differ from the network's project.
+func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]api.NetworkLease, error) {
+	var err error
+	var projectMacs []string
+	projectHostnames := map[string]string{}
+	leases := []api.NetworkLease{}
+
+	// Get all static leases.
+	if clientType == request.ClientTypeNormal {
+		// If requested project matches network's project then include gateway and downstream uplink IPs.
+		if projectName == n.project {
+			// Add our own gateway IPs.
+			for _, addr := range []string{n.config["ipv4.address"], n.config["ipv6.address"]} {
+				ip, _, _ := net.ParseCIDR(addr)
+				if ip != nil {
+					leases = append(leases, api.NetworkLease{
+						Hostname: fmt.Sprintf("%s.gw", n.Name()),
+						Address:  ip.String(),
+						Type:     "gateway",
+					})
+				}
+			}
+
+			// Include downstream OVN routers using the network as an uplink.
+			var projectNetworks map[string]map[int64]api.Network
+			err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				projectNetworks, err = tx.GetCreatedNetworks(ctx)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			// Look for networks using the current network as an uplink.
+			for projectName, networks := range projectNetworks {
+				for _, network := range networks {
+					if network.Config["network"] != n.name {
+						continue
+					}
+
+					// Found a network, add leases.
 					for _, k := range []string{"volatile.network.ipv4.address", "volatile.network.ipv6.address"} {
 						v := network.Config[k]
 						if v != "" {
@@ -3332,6 +5093,7 @@ func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]ap
 			hwAddr, _ := net.ParseMAC(nicConfig["hwaddr"])
 			if hwAddr != nil {
 				projectMacs = append(projectMacs, hwAddr.String())
+				projectHostnames[hwAddr.String()] = inst.Name
 			}
 
 			// Add the lease.
@@ -3379,6 +5141,32 @@ func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]ap
 		}
 	}
 
+	// Get delegated prefixes (only the local ones, the other members report theirs).
+	var pdLeases []db.NetworkPrefixDelegation
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		pdLeases, err = tx.GetNetworkPrefixDelegations(ctx, n.id, true)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pdLease := range pdLeases {
+		// Skip delegations that don't match any of the instance MACs from the project (see below).
+		if clientType == request.ClientTypeNormal && !slices.Contains(projectMacs, pdLease.Hwaddr) {
+			continue
+		}
+
+		leases = append(leases, api.NetworkLease{
+			Hostname: projectHostnames[pdLease.Hwaddr],
+			Address:  pdLease.Prefix,
+			Hwaddr:   pdLease.Hwaddr,
+			Type:     "delegated",
+			Location: n.state.ServerName,
+		})
+	}
+
 	// Get dynamic leases.
 	leaseFile := internalUtil.VarPath("networks", n.name, "dnsmasq.leases")
 	if !util.PathExists(leaseFile) {
@@ -3454,6 +5242,11 @@ func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]ap
 			// Add local leases from other members, filtering them for MACs that belong to the project.
 			for _, lease := range memberLeases {
 				if lease.Hwaddr != "" && slices.Contains(projectMacs, lease.Hwaddr) {
+					// Delegated prefixes are only tied to instances by the member handling the request.
+					if lease.Type == "delegated" {
+						lease.Hostname = projectHostnames[lease.Hwaddr]
+					}
+
 					leases = append(leases, lease)
 				}
 			}
@@ -3468,6 +5261,287 @@ func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]ap
 	return leases, nil
 }
 
+// recordLease records a DHCP lease event in the lease history of the network.
+func (n *bridge) recordLease(eventType string, hwaddr string, address string, hostname string) error {
+	event, err := n.leaseRecord(eventType, hwaddr, address, hostname)
+	if err != nil {
+		return err
+	}
+
+	// Floating IPs targeting DHCP allocated addresses need to follow lease changes.
+	if eventType != "renewed" && event.Instance != "" {
+		err = n.FloatingIPRefresh(event.Project, event.Instance)
+		if err != nil {
+			return fmt.Errorf("Failed refreshing floating IPs: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Trace simulates the path of a packet through the address forwards, floating IPs and ACLs of the network, using
+// the same rules as the ones applied to the firewall of this member.
+func (n *bridge) Trace(req api.NetworkTracePost) (*api.NetworkTrace, error) {
+	source, destination, err := n.traceValidate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &api.NetworkTrace{Steps: []api.NetworkTraceStep{}}
+
+	// Addresses of the network, excluding the bridge's own addresses which belong to the host.
+	var gateways []net.IP
+	var subnets []*net.IPNet
+	for _, key := range []string{"ipv4.address", "ipv6.address"} {
+		if util.IsNoneOrEmpty(n.config[key]) {
+			continue
+		}
+
+		gateway, subnet, err := net.ParseCIDR(n.config[key])
+		if err != nil {
+			continue
+		}
+
+		gateways = append(gateways, gateway)
+		subnets = append(subnets, subnet)
+	}
+
+	isNetworkAddress := func(address net.IP) bool {
+		for _, gateway := range gateways {
+			if gateway.Equal(address) {
+				return false
+			}
+		}
+
+		for _, subnet := range subnets {
+			if subnet.Contains(address) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// Apply the destination NAT of address forwards and floating IPs.
+	if !isNetworkAddress(destination) {
+		fwForwards, _, err := n.forwardFirewallForwards()
+		if err != nil {
+			return nil, err
+		}
+
+		floatingIPs, err := n.floatingIPs()
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading floating IPs: %w", err)
+		}
+
+		var match *firewallDrivers.AddressForward
+		targetPort := req.DestinationPort
+
+		for i, fwForward := range fwForwards {
+			if !fwForward.ListenAddress.Equal(destination) {
+				continue
+			}
+
+			// Port specific forwards take precedence over the default target address.
+			if fwForward.Protocol == "" {
+				if match == nil {
+					match = &fwForwards[i]
+				}
+
+				continue
+			}
+
+			if fwForward.Protocol != req.Protocol {
+				continue
+			}
+
+			portIndex := slices.Index(fwForward.ListenPorts, req.DestinationPort)
+			if portIndex < 0 {
+				continue
+			}
+
+			match = &fwForwards[i]
+			switch {
+			case len(fwForward.TargetPorts) == 1:
+				targetPort = fwForward.TargetPorts[0]
+			case portIndex < len(fwForward.TargetPorts):
+				targetPort = fwForward.TargetPorts[portIndex]
+			}
+
+			break
+		}
+
+		if match != nil {
+			step := api.NetworkTraceStep{
+				Stage: "forward",
+				Rule:  match.ListenAddress.String(),
+			}
+
+			for _, floatingIP := range floatingIPs {
+				if net.ParseIP(floatingIP.Address).Equal(destination) {
+					step.Stage = "floating-ip"
+					break
+				}
+			}
+
+			if match.Protocol != "" {
+				step.Rule = fmt.Sprintf("%s (%s port %d)", step.Rule, match.Protocol, req.DestinationPort)
+			}
+
+			if req.Protocol == "tcp" || req.Protocol == "udp" {
+				step.Description = fmt.Sprintf("Destination translated to %s", net.JoinHostPort(match.TargetAddress.String(), fmt.Sprintf("%d", targetPort)))
+			} else {
+				step.Description = fmt.Sprintf("Destination translated to %s", match.TargetAddress.String())
+			}
+
+			trace.Steps = append(trace.Steps, step)
+			destination = match.TargetAddress
+			req.DestinationPort = targetPort
+		}
+	}
+
+	// Work out which way the packet crosses the network.
+	var direction string
+	switch {
+	case isNetworkAddress(source) && isNetworkAddress(destination):
+		trace.Steps = append(trace.Steps, api.NetworkTraceStep{
+			Stage:       "route",
+			Description: "Traffic between addresses of the network is bridged and isn't filtered by network ACLs",
+		})
+	case isNetworkAddress(destination):
+		direction = "ingress"
+	case isNetworkAddress(source):
+		direction = "egress"
+	default:
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Neither the source nor the destination address is part of network %q", n.name)
+	}
+
+	packet := acl.FirewallTracePacket{
+		Source:          source,
+		Destination:     destination,
+		Protocol:        req.Protocol,
+		SourcePort:      req.SourcePort,
+		DestinationPort: req.DestinationPort,
+		ICMPType:        req.ICMPType,
+		ICMPCode:        req.ICMPCode,
+	}
+
+	if direction != "" {
+		if n.config["security.acls"] == "" {
+			trace.Steps = append(trace.Steps, api.NetworkTraceStep{
+				Stage:       "acl",
+				Description: fmt.Sprintf("No ACLs applied to the network, %s traffic is allowed", direction),
+				Verdict:     "allow",
+			})
+		} else {
+			packet.Direction = direction
+
+			result, err := acl.FirewallTraceRules(n.state, n.name, n.project, n.config, packet)
+			if err != nil {
+				return nil, err
+			}
+
+			trace.Steps = append(trace.Steps, api.NetworkTraceStep{
+				Stage:       "acl",
+				Rule:        result.Name,
+				Description: result.Description,
+				Verdict:     result.Verdict,
+			})
+		}
+	}
+
+	// Apply the ACLs of the instance NICs sending and receiving the packet, from the point of view of the NIC.
+	for _, nicDirection := range []struct {
+		address   net.IP
+		direction string
+	}{
+		{address: source, direction: "egress"},
+		{address: destination, direction: "ingress"},
+	} {
+		if !isNetworkAddress(nicDirection.address) {
+			continue
+		}
+
+		nic, err := n.traceInstanceNIC(nicDirection.address)
+		if err != nil {
+			return nil, err
+		}
+
+		if nic == nil || nic.config["security.acls"] == "" {
+			continue
+		}
+
+		packet.Direction = nicDirection.direction
+
+		result, err := acl.FirewallTraceRules(n.state, nic.name, n.project, nic.config, packet)
+		if err != nil {
+			return nil, err
+		}
+
+		trace.Steps = append(trace.Steps, api.NetworkTraceStep{
+			Stage:       "acl",
+			Rule:        fmt.Sprintf("%s/%s: %s", nic.instance, nic.name, result.Name),
+			Description: result.Description,
+			Verdict:     result.Verdict,
+		})
+	}
+
+	// The packet goes through unless one of the stages drops or rejects it.
+	trace.Verdict = "allow"
+	for _, step := range trace.Steps {
+		if step.Verdict != "" && step.Verdict != "allow" {
+			trace.Verdict = step.Verdict
+			break
+		}
+	}
+
+	return trace, nil
+}
+
+// bridgeTraceNIC is an instance NIC involved in a packet trace.
+type bridgeTraceNIC struct {
+	instance string
+	name     string
+	config   map[string]string
+}
+
+// traceInstanceNIC returns the instance NIC of the network using the address on this member, or nil if none does.
+func (n *bridge) traceInstanceNIC(address net.IP) (*bridgeTraceNIC, error) {
+	var nic *bridgeTraceNIC
+
+	err := UsedByInstanceDevices(n.state, n.project, n.name, n.netType, func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
+		if nic != nil || inst.Node != n.state.ServerName {
+			return nil
+		}
+
+		addresses := []net.IP{net.ParseIP(nicConfig["ipv4.address"]), net.ParseIP(nicConfig["ipv6.address"])}
+
+		hwaddr := nicConfig["hwaddr"]
+		if hwaddr == "" {
+			hwaddr = inst.Config[fmt.Sprintf("volatile.%s.hwaddr", nicName)]
+		}
+
+		if hwaddr != "" {
+			leaseAddresses, _ := GetLeaseAddresses(n.name, hwaddr)
+			addresses = append(addresses, leaseAddresses...)
+		}
+
+		for _, nicAddress := range addresses {
+			if nicAddress != nil && nicAddress.Equal(address) {
+				nic = &bridgeTraceNIC{instance: inst.Name, name: nicName, config: nicConfig}
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nic, nil
+}
+
 // UsesDNSMasq indicates if network's config indicates if it needs to use dnsmasq.
 func (n *bridge) UsesDNSMasq() bool {
 	// Skip dnsmasq when no connectivity is configured.
@@ -0,0 +1,131 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_bridgeBootOptions(t *testing.T) {
+	clientTags := []string{
+		"--dhcp-match=set:efi,option:client-arch,7",
+		"--dhcp-match=set:efi,option:client-arch,9",
+		"--dhcp-match=set:efi,option:client-arch,11",
+		"--dhcp-userclass=set:ipxe,iPXE",
+	}
+
+	tests := []struct {
+		name   string
+		config map[string]string
+		want   []string
+	}{
+		{
+			name:   "No boot files",
+			config: map[string]string{"ipv4.dhcp.boot.server": "192.0.2.10"},
+			want:   nil,
+		},
+		{
+			name: "All firmwares from the local TFTP server",
+			config: map[string]string{
+				"ipv4.dhcp.boot.filename.bios": "pxelinux.0",
+				"ipv4.dhcp.boot.filename.uefi": "ipxe.efi",
+				"ipv4.dhcp.boot.filename.ipxe": "http://192.0.2.1/boot.ipxe",
+			},
+			want: append(clientTags[:4:4],
+				"--dhcp-boot=tag:!ipxe,tag:!efi,pxelinux.0",
+				"--dhcp-boot=tag:!ipxe,tag:efi,ipxe.efi",
+				"--dhcp-boot=tag:ipxe,http://192.0.2.1/boot.ipxe",
+			),
+		},
+		{
+			name: "UEFI only from a remote TFTP server",
+			config: map[string]string{
+				"ipv4.dhcp.boot.server":        "192.0.2.10",
+				"ipv4.dhcp.boot.filename.uefi": "grubx64.efi",
+			},
+			want: append(clientTags[:4:4],
+				"--dhcp-boot=tag:!ipxe,tag:efi,grubx64.efi,,192.0.2.10",
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, bridgeBootOptions(tt.config))
+		})
+	}
+}
+
+func Test_bridgeQoSValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		network string
+		config  map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "No QoS",
+			network: "incusbr0",
+			config:  map[string]string{},
+		},
+		{
+			name:    "Classes without a rate",
+			network: "incusbr0",
+			config:  map[string]string{"qos.class.web.guaranteed": "10Mbit"},
+			wantErr: true,
+		},
+		{
+			name:    "Network name too long",
+			network: "incusbr0-long",
+			config:  map[string]string{"qos.rate": "100Mbit"},
+			wantErr: true,
+		},
+		{
+			name:    "Valid classes",
+			network: "incusbr0",
+			config: map[string]string{
+				"qos.rate":                 "100Mbit",
+				"qos.class.web.guaranteed": "60Mbit",
+				"qos.class.web.max":        "80Mbit",
+				"qos.class.db.guaranteed":  "40Mbit",
+			},
+		},
+		{
+			name:    "Maximum rate above the total rate",
+			network: "incusbr0",
+			config:  map[string]string{"qos.rate": "100Mbit", "qos.class.web.max": "200Mbit"},
+			wantErr: true,
+		},
+		{
+			name:    "Guaranteed rate above the maximum rate",
+			network: "incusbr0",
+			config:  map[string]string{"qos.rate": "100Mbit", "qos.class.web.guaranteed": "60Mbit", "qos.class.web.max": "50Mbit"},
+			wantErr: true,
+		},
+		{
+			name:    "Guaranteed rates above the total rate",
+			network: "incusbr0",
+			config:  map[string]string{"qos.rate": "100Mbit", "qos.class.web.guaranteed": "60Mbit", "qos.class.db.guaranteed": "50Mbit"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid class rate",
+			network: "incusbr0",
+			config:  map[string]string{"qos.rate": "100Mbit", "qos.class.web.guaranteed": "fast"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &bridge{common: common{name: tt.network}}
+
+			err := n.qosValidate(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
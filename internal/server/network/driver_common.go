@@ -3,15 +3,18 @@ package network
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"maps"
 	"math/rand"
 	"net"
+	"net/http"
 	"os"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	incus "github.com/lxc/incus/v6/client"
@@ -22,6 +25,8 @@ import (
 	"github.com/lxc/incus/v6/internal/server/cluster/request"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/dnsmasq/dhcpalloc"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/network/acl"
 	"github.com/lxc/incus/v6/internal/server/state"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
@@ -40,6 +45,8 @@ type Info struct {
 	AddressForwards    bool // Indicates if driver supports address forwards.
 	LoadBalancers      bool // Indicates if driver supports load balancers.
 	Peering            bool // Indicates if the driver supports network peering.
+	DHCPReservations   bool // Indicates if the driver supports DHCP reservations.
+	FloatingIPs        bool // Indicates if the driver supports floating IPs.
 }
 
 // forwardTarget represents a single port forward target.
@@ -70,6 +77,7 @@ const (
 	subnetUsageNetworkSNAT
 	subnetUsageNetworkForward
 	subnetUsageNetworkLoadBalancer
+	subnetUsageNetworkFloatingIP
 	subnetUsageInstance
 	subnetUsageProxy
 )
@@ -415,6 +423,9 @@ func (n *common) update(applyNetwork api.NetworkPut, targetNode string, clientTy
 		}
 	}
 
+	// The network may have been added to or removed from DNS zones.
+	n.state.DNS.InvalidateZones()
+
 	return nil
 }
 
@@ -628,6 +639,8 @@ func (n *common) bgpValidationRules(config map[string]string) (map[string]func(v
 			rules[k] = validate.Optional(validate.IsAny)
 		case "holdtime":
 			rules[k] = validate.Optional(validate.IsInRange(9, 65535))
+		case "bfd":
+			rules[k] = validate.Optional(validate.IsBool)
 		}
 	}
 
@@ -654,6 +667,9 @@ func (n *common) bgpSetup(oldConfig map[string]string) error {
 		return fmt.Errorf("Failed setting up BGP peers: %w", err)
 	}
 
+	// Emit events on BFD session changes.
+	n.state.BGP.AddBFDHandler(fmt.Sprintf("network_%d", n.id), n.bgpHandleBFD)
+
 	// Export the prefixes.
 	err = n.bgpSetupPrefixes(oldConfig)
 	if err != nil {
@@ -676,6 +692,8 @@ func (n *common) bgpClear(config map[string]string) error {
 		return err
 	}
 
+	n.state.BGP.RemoveBFDHandler(fmt.Sprintf("network_%d", n.id))
+
 	// Clear all prefixes.
 	err = n.state.BGP.RemovePrefixByOwner(fmt.Sprintf("network_%d", n.id))
 	if err != nil {
@@ -688,6 +706,12 @@ func (n *common) bgpClear(config map[string]string) error {
 		return err
 	}
 
+	// Clear existing floating IP prefixes for network.
+	err = n.state.BGP.RemovePrefixByOwner(fmt.Sprintf("network_%d_floating_ip", n.id))
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -747,7 +771,7 @@ func (n *common) bgpSetupPeers(oldConfig map[string]string) error {
 			}
 		}
 
-		err = n.state.BGP.AddPeer(net.ParseIP(fields[0]), uint32(asn), fields[2], holdTime)
+		err = n.state.BGP.AddPeer(net.ParseIP(fields[0]), uint32(asn), fields[2], holdTime, util.IsTrue(fields[4]))
 		if err != nil {
 			return err
 		}
@@ -826,9 +850,8 @@ func (n *common) bgpSetupPrefixes(oldConfig map[string]string) error {
 	return nil
 }
 
-// bgpGetPeers returns a list of strings representing the BGP peers.
-func (n *common) bgpGetPeers(config map[string]string) []string {
-	// Get a list of peer names.
+// bgpGetPeerNames returns the sorted list of BGP peer names in the config.
+func (n *common) bgpGetPeerNames(config map[string]string) []string {
 	peerNames := []string{}
 	for k := range config {
 		if !strings.HasPrefix(k, "bgp.peers.") {
@@ -841,22 +864,83 @@ func (n *common) bgpGetPeers(config map[string]string) []string {
 		}
 	}
 
+	slices.Sort(peerNames)
+
+	return peerNames
+}
+
+// bgpGetPeers returns a list of strings representing the BGP peers.
+func (n *common) bgpGetPeers(config map[string]string) []string {
 	// Build up a list of peer strings.
 	peers := []string{}
-	for _, peerName := range peerNames {
+	for _, peerName := range n.bgpGetPeerNames(config) {
 		peerAddress := config[fmt.Sprintf("bgp.peers.%s.address", peerName)]
 		peerASN := config[fmt.Sprintf("bgp.peers.%s.asn", peerName)]
 		peerPassword := config[fmt.Sprintf("bgp.peers.%s.password", peerName)]
 		peerHoldTime := config[fmt.Sprintf("bgp.peers.%s.holdtime", peerName)]
+		peerBFD := config[fmt.Sprintf("bgp.peers.%s.bfd", peerName)]
 
 		if peerAddress != "" && peerASN != "" {
-			peers = append(peers, fmt.Sprintf("%s,%s,%s,%s", peerAddress, peerASN, peerPassword, peerHoldTime))
+			peers = append(peers, fmt.Sprintf("%s,%s,%s,%s,%s", peerAddress, peerASN, peerPassword, peerHoldTime, peerBFD))
 		}
 	}
 
 	return peers
 }
 
+// bgpHandleBFD emits a lifecycle event when the BFD session of one of the network's peers goes up or down.
+func (n *common) bgpHandleBFD(address net.IP, state string) {
+	var action lifecycle.NetworkAction
+	switch state {
+	case "up":
+		action = lifecycle.NetworkBFDSessionUp
+	case "down":
+		action = lifecycle.NetworkBFDSessionDown
+	default:
+		return
+	}
+
+	for _, peerName := range n.bgpGetPeerNames(n.config) {
+		peerAddress := net.ParseIP(n.config[fmt.Sprintf("bgp.peers.%s.address", peerName)])
+		if !peerAddress.Equal(address) {
+			continue
+		}
+
+		n.state.Events.SendLifecycle(n.project, action.Event(n, nil, map[string]any{"peer": peerName, "address": address.String()}))
+	}
+}
+
+// bgpState returns the live state of the network's BGP peers.
+func (n *common) bgpState() *api.NetworkStateBGP {
+	peers := []api.NetworkStateBGPPeer{}
+	for _, peerName := range n.bgpGetPeerNames(n.config) {
+		peerAddress := n.config[fmt.Sprintf("bgp.peers.%s.address", peerName)]
+		if peerAddress == "" {
+			continue
+		}
+
+		peer := api.NetworkStateBGPPeer{
+			Name:    peerName,
+			Address: peerAddress,
+			State:   "unknown",
+		}
+
+		peerState, err := n.state.BGP.GetPeerState(net.ParseIP(peerAddress))
+		if err == nil {
+			peer.State = peerState.State
+			peer.BFD = peerState.BFD
+		}
+
+		peers = append(peers, peer)
+	}
+
+	if len(peers) == 0 {
+		return nil
+	}
+
+	return &api.NetworkStateBGP{Peers: peers}
+}
+
 // forwardValidate validates the forward request.
 func (n *common) forwardValidate(listenAddress net.IP, forward *api.NetworkForwardPut) ([]*forwardPortMap, error) {
 	if listenAddress == nil {
@@ -1068,6 +1152,645 @@ func (n *common) ForwardDelete(listenAddress string, clientType request.ClientTy
 	return ErrNotImplemented
 }
 
+// ReservationCreate returns ErrNotImplemented for drivers that do not support DHCP reservations.
+func (n *common) ReservationCreate(reservation api.NetworkReservationsPost, clientType request.ClientType) error {
+	return ErrNotImplemented
+}
+
+// ReservationUpdate returns ErrNotImplemented for drivers that do not support DHCP reservations.
+func (n *common) ReservationUpdate(name string, req api.NetworkReservationPut, clientType request.ClientType) error {
+	return ErrNotImplemented
+}
+
+// ReservationDelete returns ErrNotImplemented for drivers that do not support DHCP reservations.
+func (n *common) ReservationDelete(name string, clientType request.ClientType) error {
+	return ErrNotImplemented
+}
+
+// reservations returns the DHCP reservations of the network.
+func (n *common) reservations() ([]api.NetworkReservation, error) {
+	var reservations []api.NetworkReservation
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		networkID := n.ID()
+		dbRecords, err := dbCluster.GetNetworkReservations(ctx, tx.Tx(), dbCluster.NetworkReservationFilter{NetworkID: &networkID})
+		if err != nil {
+			return err
+		}
+
+		reservations = make([]api.NetworkReservation, 0, len(dbRecords))
+		for _, dbRecord := range dbRecords {
+			reservation, err := dbRecord.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			reservations = append(reservations, *reservation)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// reservationConfigKeys are the network config keys affecting the validity of the DHCP reservations.
+var reservationConfigKeys = []string{"ipv4.address", "ipv4.dhcp", "ipv4.dhcp.ranges", "ipv6.address", "ipv6.dhcp", "ipv6.dhcp.stateful", "ipv6.dhcp.ranges"}
+
+// reservationValidate validates a DHCP reservation against the network's DHCP subnets and ranges and checks it
+// doesn't conflict with the other reservations of the network or the static addresses of its instance NICs.
+// The MAC address and DUID of the reservation are normalized.
+func (n *common) reservationValidate(name string, reservation *api.NetworkReservationPut, subnetV4 *net.IPNet, subnetV6 *net.IPNet) error {
+	err := n.reservationValidateFields(name, reservation, subnetV4, subnetV6)
+	if err != nil {
+		return err
+	}
+
+	// Check for conflicts with the other reservations.
+	reservations, err := n.reservations()
+	if err != nil {
+		return err
+	}
+
+	err = reservationConflict(name, reservation, reservations)
+	if err != nil {
+		return err
+	}
+
+	// Check for conflicts with the static addresses of the instance NICs using another MAC address.
+	return UsedByInstanceDevices(n.state, n.project, n.name, n.netType, func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
+		return reservationNICConflict(reservation, inst, nicName, nicConfig)
+	})
+}
+
+// reservationValidateFields validates the name, identifiers, addresses and config of a DHCP reservation.
+// The MAC address and DUID of the reservation are normalized.
+func (n *common) reservationValidateFields(name string, reservation *api.NetworkReservationPut, subnetV4 *net.IPNet, subnetV6 *net.IPNet) error {
+	err := validate.IsHostname(name)
+	if err != nil {
+		return fmt.Errorf("Invalid reservation name %q: %w", name, err)
+	}
+
+	if reservation.Hwaddr == "" && reservation.DUID == "" {
+		return errors.New("Either a MAC address or a DUID must be specified")
+	}
+
+	if reservation.Hwaddr != "" {
+		err = validate.IsNetworkMAC(reservation.Hwaddr)
+		if err != nil {
+			return fmt.Errorf("Invalid MAC address %q: %w", reservation.Hwaddr, err)
+		}
+
+		mac, _ := net.ParseMAC(reservation.Hwaddr)
+		reservation.Hwaddr = mac.String()
+	}
+
+	if reservation.DUID != "" {
+		duid, err := hex.DecodeString(strings.ReplaceAll(reservation.DUID, ":", ""))
+		if err != nil || len(duid) < 3 || len(duid) > 130 || strings.Count(reservation.DUID, ":") != len(duid)-1 {
+			return fmt.Errorf("Invalid DUID %q", reservation.DUID)
+		}
+
+		reservation.DUID = strings.ToLower(reservation.DUID)
+	}
+
+	err = n.reservationValidateAddresses(reservation, subnetV4, subnetV6)
+	if err != nil {
+		return err
+	}
+
+	// Look for any unknown config fields.
+	for k := range reservation.Config {
+		// User keys are not validated.
+
+		// gendoc:generate(entity=network_reservation, group=common, key=user.*)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: User defined key/value configuration
+		if internalInstance.IsUserConfig(k) {
+			continue
+		}
+
+		return fmt.Errorf("Invalid option %q", k)
+	}
+
+	return nil
+}
+
+// reservationConflict returns an api.StatusError with http.StatusConflict if the DHCP reservation uses the MAC
+// address, DUID or addresses of another reservation of the network.
+func reservationConflict(name string, reservation *api.NetworkReservationPut, reservations []api.NetworkReservation) error {
+	for _, other := range reservations {
+		if other.Name == name {
+			continue
+		}
+
+		if reservation.Hwaddr != "" && hwaddrEqual(other.Hwaddr, reservation.Hwaddr) {
+			return api.StatusErrorf(http.StatusConflict, "MAC address %q is already used by reservation %q", reservation.Hwaddr, other.Name)
+		}
+
+		if reservation.DUID != "" && strings.EqualFold(other.DUID, reservation.DUID) {
+			return api.StatusErrorf(http.StatusConflict, "DUID %q is already used by reservation %q", reservation.DUID, other.Name)
+		}
+
+		if reservation.IPv4Address != "" && net.ParseIP(other.IPv4Address).Equal(net.ParseIP(reservation.IPv4Address)) {
+			return api.StatusErrorf(http.StatusConflict, "Address %q is already reserved by reservation %q", reservation.IPv4Address, other.Name)
+		}
+
+		if reservation.IPv6Address != "" && net.ParseIP(other.IPv6Address).Equal(net.ParseIP(reservation.IPv6Address)) {
+			return api.StatusErrorf(http.StatusConflict, "Address %q is already reserved by reservation %q", reservation.IPv6Address, other.Name)
+		}
+	}
+
+	return nil
+}
+
+// reservationNICConflict returns an api.StatusError with http.StatusConflict if an instance NIC using another MAC
+// address than the DHCP reservation has one of its addresses statically set.
+func reservationNICConflict(reservation *api.NetworkReservationPut, inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
+	nicHwaddr := nicConfig["hwaddr"]
+	if nicHwaddr == "" {
+		nicHwaddr = inst.Config[fmt.Sprintf("volatile.%s.hwaddr", nicName)]
+	}
+
+	if reservation.Hwaddr != "" && hwaddrEqual(nicHwaddr, reservation.Hwaddr) {
+		return nil
+	}
+
+	for key, address := range map[string]string{"ipv4.address": reservation.IPv4Address, "ipv6.address": reservation.IPv6Address} {
+		nicIP := net.ParseIP(nicConfig[key])
+		if address != "" && nicIP != nil && nicIP.Equal(net.ParseIP(address)) {
+			return api.StatusErrorf(http.StatusConflict, "Address %q is already used by NIC %q of instance %q in project %q", address, nicName, inst.Name, inst.Project)
+		}
+	}
+
+	return nil
+}
+
+// reservationValidateAddresses checks the addresses of a DHCP reservation are usable within the network's DHCP
+// subnets and ranges.
+func (n *common) reservationValidateAddresses(reservation *api.NetworkReservationPut, subnetV4 *net.IPNet, subnetV6 *net.IPNet) error {
+	if reservation.IPv4Address == "" && reservation.IPv6Address == "" {
+		return errors.New("At least one of an IPv4 or IPv6 address must be specified")
+	}
+
+	// Checks the address is usable within the network's DHCP subnet and ranges.
+	// The address must use the same length as the ranges (4 bytes for IPv4) for them to be compared.
+	checkAddress := func(ip net.IP, subnet *net.IPNet, ranges []iprange.Range, gatewayKey string) error {
+		address := ip.String()
+		if subnet == nil {
+			return fmt.Errorf("Cannot reserve address %q on a network without DHCP for this address family", address)
+		}
+
+		if !dhcpalloc.DHCPValidIP(subnet, ranges, ip) {
+			if len(ranges) > 0 {
+				return fmt.Errorf("Address %q is not within the network's DHCP ranges", address)
+			}
+
+			return fmt.Errorf("Address %q is not within the network's subnet %q", address, subnet.String())
+		}
+
+		gatewayIP, _, _ := net.ParseCIDR(n.config[gatewayKey])
+		if gatewayIP != nil && gatewayIP.Equal(ip) {
+			return fmt.Errorf("Address %q is the network's own address", address)
+		}
+
+		return nil
+	}
+
+	if reservation.IPv4Address != "" {
+		ip := net.ParseIP(reservation.IPv4Address).To4()
+		if ip == nil {
+			return fmt.Errorf("Invalid IPv4 address %q", reservation.IPv4Address)
+		}
+
+		if reservation.Hwaddr == "" {
+			return errors.New("A MAC address must be specified to reserve an IPv4 address")
+		}
+
+		err := checkAddress(ip, subnetV4, n.DHCPv4Ranges(), "ipv4.address")
+		if err != nil {
+			return err
+		}
+
+		if ip.Equal(subnetV4.IP) || ip.Equal(dhcpalloc.GetIP(subnetV4, -1)) {
+			return fmt.Errorf("Address %q is the subnet's network or broadcast address", reservation.IPv4Address)
+		}
+	}
+
+	if reservation.IPv6Address != "" {
+		ip := net.ParseIP(reservation.IPv6Address)
+		if ip == nil || ip.To4() != nil {
+			return fmt.Errorf("Invalid IPv6 address %q", reservation.IPv6Address)
+		}
+
+		err := checkAddress(ip, subnetV6, n.DHCPv6Ranges(), "ipv6.address")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reservationsRevalidate checks the existing DHCP reservations of the network using the validate function.
+// It is used to refuse changes to the network's subnets and DHCP ranges that would leave reservations outside them.
+func (n *common) reservationsRevalidate(validate func(reservation *api.NetworkReservationPut) error) error {
+	reservations, err := n.reservations()
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range reservations {
+		err = validate(&reservation.NetworkReservationPut)
+		if err != nil {
+			return fmt.Errorf("DHCP reservation %q would be invalid: %w", reservation.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ReservationConflict returns an api.StatusError with http.StatusConflict if any of the addresses is reserved
+// by a DHCP reservation of the network for a MAC address other than hwaddr.
+func (n *common) ReservationConflict(hwaddr string, addresses ...net.IP) error {
+	reservations, err := n.reservations()
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range reservations {
+		if reservation.Hwaddr != "" && hwaddrEqual(reservation.Hwaddr, hwaddr) {
+			continue
+		}
+
+		for _, address := range addresses {
+			if address == nil {
+				continue
+			}
+
+			if address.Equal(net.ParseIP(reservation.IPv4Address)) || address.Equal(net.ParseIP(reservation.IPv6Address)) {
+				return api.StatusErrorf(http.StatusConflict, "IP address %q is reserved by DHCP reservation %q", address.String(), reservation.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// reservationCreate stores a new DHCP reservation in the database.
+func (n *common) reservationCreate(reservation api.NetworkReservationsPost) error {
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check if there is an existing reservation using the same name.
+		_, err := dbCluster.GetNetworkReservation(ctx, tx.Tx(), n.ID(), reservation.Name)
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "A reservation with that name already exists")
+		} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		dbRecord := dbCluster.NetworkReservation{
+			NetworkID:   n.ID(),
+			Name:        reservation.Name,
+			Description: reservation.Description,
+			Hwaddr:      reservation.Hwaddr,
+			DUID:        reservation.DUID,
+			IPv4Address: reservation.IPv4Address,
+			IPv6Address: reservation.IPv6Address,
+		}
+
+		reservationID, err := dbCluster.CreateNetworkReservation(ctx, tx.Tx(), dbRecord)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.CreateNetworkReservationConfig(ctx, tx.Tx(), reservationID, reservation.Config)
+	})
+}
+
+// reservationGet returns the stored DHCP reservation and its database ID.
+func (n *common) reservationGet(name string) (int64, *api.NetworkReservation, error) {
+	var reservationID int64
+	var reservation *api.NetworkReservation
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbRecord, err := dbCluster.GetNetworkReservation(ctx, tx.Tx(), n.ID(), name)
+		if err != nil {
+			return err
+		}
+
+		reservationID = dbRecord.ID
+		reservation, err = dbRecord.ToAPI(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return -1, nil, err
+	}
+
+	return reservationID, reservation, nil
+}
+
+// reservationUpdate replaces the stored DHCP reservation in the database.
+func (n *common) reservationUpdate(reservationID int64, name string, req api.NetworkReservationPut) error {
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbRecord := dbCluster.NetworkReservation{
+			NetworkID:   n.ID(),
+			Name:        name,
+			Description: req.Description,
+			Hwaddr:      req.Hwaddr,
+			DUID:        req.DUID,
+			IPv4Address: req.IPv4Address,
+			IPv6Address: req.IPv6Address,
+		}
+
+		err := dbCluster.UpdateNetworkReservation(ctx, tx.Tx(), n.ID(), name, dbRecord)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.UpdateNetworkReservationConfig(ctx, tx.Tx(), reservationID, req.Config)
+	})
+}
+
+// reservationDelete removes the stored DHCP reservation from the database.
+func (n *common) reservationDelete(name string) error {
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.DeleteNetworkReservation(ctx, tx.Tx(), n.ID(), name)
+	})
+}
+
+// FloatingIPCreate returns ErrNotImplemented for drivers that do not support floating IPs.
+func (n *common) FloatingIPCreate(projectName string, floatingIP api.NetworkFloatingIPsPost, clientType request.ClientType) error {
+	return ErrNotImplemented
+}
+
+// FloatingIPUpdate returns ErrNotImplemented for drivers that do not support floating IPs.
+func (n *common) FloatingIPUpdate(address string, req api.NetworkFloatingIPPut, clientType request.ClientType) error {
+	return ErrNotImplemented
+}
+
+// FloatingIPDelete returns ErrNotImplemented for drivers that do not support floating IPs.
+func (n *common) FloatingIPDelete(address string, clientType request.ClientType) error {
+	return ErrNotImplemented
+}
+
+// floatingIPs returns the floating IPs of the network.
+func (n *common) floatingIPs() ([]api.NetworkFloatingIP, error) {
+	networkID := n.ID()
+
+	return n.floatingIPsLoad(dbCluster.NetworkFloatingIPFilter{NetworkID: &networkID})
+}
+
+// instanceFloatingIPs returns the floating IPs of the network targeting the instance.
+func (n *common) instanceFloatingIPs(projectName string, instanceName string) ([]api.NetworkFloatingIP, error) {
+	networkID := n.ID()
+
+	return n.floatingIPsLoad(dbCluster.NetworkFloatingIPFilter{NetworkID: &networkID, Project: &projectName, Instance: &instanceName})
+}
+
+// floatingIPsLoad returns the floating IPs matching the filter.
+func (n *common) floatingIPsLoad(filter dbCluster.NetworkFloatingIPFilter) ([]api.NetworkFloatingIP, error) {
+	var floatingIPs []api.NetworkFloatingIP
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbRecords, err := dbCluster.GetNetworkFloatingIPs(ctx, tx.Tx(), filter)
+		if err != nil {
+			return err
+		}
+
+		floatingIPs = make([]api.NetworkFloatingIP, 0, len(dbRecords))
+		for _, dbRecord := range dbRecords {
+			floatingIP, err := dbRecord.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			floatingIPs = append(floatingIPs, *floatingIP)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return floatingIPs, nil
+}
+
+// floatingIPValidateConfig validates the config of a floating IP.
+// Drivers are responsible for rejecting the keys they don't support.
+func floatingIPValidateConfig(config map[string]string) error {
+	for k, v := range config {
+		switch k {
+		// gendoc:generate(entity=network_floating_ip, group=common, key=announce.interface)
+		//
+		// ---
+		//  type: string
+		//  condition: bridge network
+		//  defaultdesc: interface whose subnet contains the address
+		//  shortdesc: Host interface on which the floating IP is announced through ARP or NDP
+		case "announce.interface":
+			err := validate.IsInterfaceName(v)
+			if err != nil {
+				return fmt.Errorf("Invalid value for %q: %w", k, err)
+			}
+
+		// gendoc:generate(entity=network_floating_ip, group=common, key=announce.bgp)
+		//
+		// ---
+		//  type: bool
+		//  condition: BGP server
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to advertise the floating IP through the BGP server (required on physical networks)
+		case "announce.bgp":
+			err := validate.IsBool(v)
+			if err != nil {
+				return fmt.Errorf("Invalid value for %q: %w", k, err)
+			}
+
+		// gendoc:generate(entity=network_floating_ip, group=common, key=target.address)
+		//
+		// ---
+		//  type: string
+		//  condition: physical network
+		//  shortdesc: Address of the instance on the physical network, used as the BGP next hop of the floating IP
+		case "target.address":
+			err := validate.IsNetworkAddress(v)
+			if err != nil {
+				return fmt.Errorf("Invalid value for %q: %w", k, err)
+			}
+
+		default:
+			// User keys are not validated.
+
+			// gendoc:generate(entity=network_floating_ip, group=common, key=user.*)
+			//
+			// ---
+			//  type: string
+			//  shortdesc: User defined key/value configuration
+			if internalInstance.IsUserConfig(k) {
+				continue
+			}
+
+			return fmt.Errorf("Invalid option %q", k)
+		}
+	}
+
+	return nil
+}
+
+// floatingIPCreate stores a new floating IP in the database.
+func (n *common) floatingIPCreate(projectName string, floatingIP api.NetworkFloatingIPsPost) error {
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check if there is an existing floating IP using the same address.
+		_, err := dbCluster.GetNetworkFloatingIP(ctx, tx.Tx(), n.ID(), floatingIP.Address)
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "A floating IP for that address already exists")
+		} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		dbRecord := dbCluster.NetworkFloatingIP{
+			NetworkID:   n.ID(),
+			Address:     floatingIP.Address,
+			Description: floatingIP.Description,
+			Project:     projectName,
+			Instance:    floatingIP.Instance,
+		}
+
+		floatingIPID, err := dbCluster.CreateNetworkFloatingIP(ctx, tx.Tx(), dbRecord)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.CreateNetworkFloatingIPConfig(ctx, tx.Tx(), floatingIPID, floatingIP.Config)
+	})
+}
+
+// floatingIPGet returns the stored floating IP and its database ID.
+func (n *common) floatingIPGet(address string) (int64, *api.NetworkFloatingIP, error) {
+	var floatingIPID int64
+	var floatingIP *api.NetworkFloatingIP
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbRecord, err := dbCluster.GetNetworkFloatingIP(ctx, tx.Tx(), n.ID(), address)
+		if err != nil {
+			return err
+		}
+
+		floatingIPID = dbRecord.ID
+		floatingIP, err = dbRecord.ToAPI(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return -1, nil, err
+	}
+
+	return floatingIPID, floatingIP, nil
+}
+
+// floatingIPUpdate replaces the stored floating IP in the database.
+func (n *common) floatingIPUpdate(floatingIPID int64, projectName string, address string, req api.NetworkFloatingIPPut) error {
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbRecord := dbCluster.NetworkFloatingIP{
+			NetworkID:   n.ID(),
+			Address:     address,
+			Description: req.Description,
+			Project:     projectName,
+			Instance:    req.Instance,
+		}
+
+		err := dbCluster.UpdateNetworkFloatingIP(ctx, tx.Tx(), n.ID(), address, dbRecord)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.UpdateNetworkFloatingIPConfig(ctx, tx.Tx(), floatingIPID, req.Config)
+	})
+}
+
+// floatingIPDelete removes the stored floating IP from the database.
+func (n *common) floatingIPDelete(address string) error {
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.DeleteNetworkFloatingIP(ctx, tx.Tx(), n.ID(), address)
+	})
+}
+
+// leaseRecord adds a DHCP lease event to the lease history of the network and emits the matching lifecycle event.
+// The instance the lease belongs to is looked up from the MAC address so it can still be identified later on.
+// Returns the recorded event.
+func (n *common) leaseRecord(eventType string, hwaddr string, address string, hostname string) (*api.NetworkLeaseEvent, error) {
+	var action lifecycle.NetworkLeaseAction
+	switch eventType {
+	case "acquired":
+		action = lifecycle.NetworkLeaseAcquired
+	case "renewed":
+		action = lifecycle.NetworkLeaseRenewed
+	case "released":
+		action = lifecycle.NetworkLeaseReleased
+	default:
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid lease event type %q", eventType)
+	}
+
+	event := api.NetworkLeaseEvent{
+		Type:      eventType,
+		Timestamp: time.Now().UTC(),
+		Hostname:  hostname,
+		Address:   address,
+		Location:  n.state.ServerName,
+	}
+
+	mac, err := net.ParseMAC(hwaddr)
+	if err == nil {
+		event.Hwaddr = mac.String()
+
+		// Find the instance the lease was handed to.
+		err = UsedByInstanceDevices(n.state, n.project, n.name, n.netType, func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
+			nicHwaddr := nicConfig["hwaddr"]
+			if nicHwaddr == "" {
+				nicHwaddr = inst.Config[fmt.Sprintf("volatile.%s.hwaddr", nicName)]
+			}
+
+			nicMAC, _ := net.ParseMAC(nicHwaddr)
+			if nicMAC != nil && nicMAC.String() == event.Hwaddr {
+				event.Project = inst.Project
+				event.Instance = inst.Name
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateNetworkLeaseEvent(ctx, n.id, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The lease may be published in DNS zones.
+	n.state.DNS.InvalidateZones()
+
+	n.state.Events.SendLifecycle(n.project, action.Event(n, map[string]any{
+		"address":  event.Address,
+		"hwaddr":   event.Hwaddr,
+		"hostname": event.Hostname,
+		"project":  event.Project,
+		"instance": event.Instance,
+	}))
+
+	return &event, nil
+}
+
 // forwardBGPSetupPrefixes exports external forward addresses as prefixes.
 func (n *common) forwardBGPSetupPrefixes() error {
 	var fwdListenAddresses map[int64]string
@@ -1520,6 +2243,67 @@ func (n *common) Leases(projectName string, clientType request.ClientType) ([]ap
 	return nil, ErrNotImplemented
 }
 
+// Trace returns ErrNotImplemented for drivers that don't support packet traces.
+func (n *common) Trace(req api.NetworkTracePost) (*api.NetworkTrace, error) {
+	return nil, ErrNotImplemented
+}
+
+// traceValidate validates a packet trace request and returns its source and destination addresses.
+func (n *common) traceValidate(req api.NetworkTracePost) (net.IP, net.IP, error) {
+	source := net.ParseIP(req.Source)
+	if source == nil {
+		return nil, nil, api.StatusErrorf(http.StatusBadRequest, "Invalid source address %q", req.Source)
+	}
+
+	destination := net.ParseIP(req.Destination)
+	if destination == nil {
+		return nil, nil, api.StatusErrorf(http.StatusBadRequest, "Invalid destination address %q", req.Destination)
+	}
+
+	isIPv4 := source.To4() != nil
+	if isIPv4 != (destination.To4() != nil) {
+		return nil, nil, api.StatusErrorf(http.StatusBadRequest, "Source and destination addresses must be of the same family")
+	}
+
+	switch req.Protocol {
+	case "tcp", "udp":
+		if req.ICMPType != "" || req.ICMPCode != "" {
+			return nil, nil, api.StatusErrorf(http.StatusBadRequest, "ICMP type and code can only be used with ICMP protocols")
+		}
+
+		if req.DestinationPort == 0 || req.DestinationPort > 65535 {
+			return nil, nil, api.StatusErrorf(http.StatusBadRequest, "A destination port between 1 and 65535 is required for protocol %q", req.Protocol)
+		}
+
+		if req.SourcePort > 65535 {
+			return nil, nil, api.StatusErrorf(http.StatusBadRequest, "Invalid source port %d", req.SourcePort)
+		}
+
+	case "icmp4", "icmp6":
+		if req.SourcePort != 0 || req.DestinationPort != 0 {
+			return nil, nil, api.StatusErrorf(http.StatusBadRequest, "Ports can only be used with TCP and UDP")
+		}
+
+		if (req.Protocol == "icmp4") != isIPv4 {
+			return nil, nil, api.StatusErrorf(http.StatusBadRequest, "Protocol %q doesn't match the address family", req.Protocol)
+		}
+
+		for _, value := range []string{req.ICMPType, req.ICMPCode} {
+			if value != "" {
+				err := validate.IsUint8(value)
+				if err != nil {
+					return nil, nil, api.StatusErrorf(http.StatusBadRequest, "Invalid ICMP type or code %q: %v", value, err)
+				}
+			}
+		}
+
+	default:
+		return nil, nil, api.StatusErrorf(http.StatusBadRequest, "Invalid protocol %q (must be one of tcp, udp, icmp4 or icmp6)", req.Protocol)
+	}
+
+	return source, destination, nil
+}
+
 // PeerCrete returns ErrNotImplemented for drivers that do not support forwards.
 func (n *common) PeerCreate(forward api.NetworkPeersPost) error {
 	return ErrNotImplemented
@@ -1659,11 +2443,22 @@ func (n *common) peerUsedBy(peerName string, firstOnly bool) ([]string, error) {
 }
 
 func (n *common) State() (*api.NetworkState, error) {
+	var state *api.NetworkState
+	var err error
+
 	if n.config["parent"] != "" {
-		return resources.GetNetworkState(n.config["parent"])
+		state, err = resources.GetNetworkState(n.config["parent"])
+	} else {
+		state, err = resources.GetNetworkState(n.name)
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
-	return resources.GetNetworkState(n.name)
+	state.BGP = n.bgpState()
+
+	return state, nil
 }
 
 func (n *common) setUnavailable() {
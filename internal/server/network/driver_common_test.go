@@ -0,0 +1,346 @@
+package network
+
+import (
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func Test_reservationValidateFields(t *testing.T) {
+	_, subnetV4, _ := net.ParseCIDR("10.0.0.0/24")
+	_, subnetV6, _ := net.ParseCIDR("fd42::/64")
+
+	n := &common{config: map[string]string{"ipv4.address": "10.0.0.1/24", "ipv6.address": "fd42::1/64"}}
+
+	tests := []struct {
+		name        string
+		resName     string
+		reservation api.NetworkReservationPut
+		want        api.NetworkReservationPut
+		wantErr     string
+	}{
+		{
+			name:        "MAC address is normalized",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3E:AA:BB:CC", IPv4Address: "10.0.0.10"},
+			want:        api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "10.0.0.10"},
+		},
+		{
+			name:        "DUID is normalized",
+			reservation: api.NetworkReservationPut{DUID: "00:04:AB:CD:EF", IPv6Address: "fd42::10"},
+			want:        api.NetworkReservationPut{DUID: "00:04:ab:cd:ef", IPv6Address: "fd42::10"},
+		},
+		{
+			name:        "User keys",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "10.0.0.10", Config: map[string]string{"user.foo": "bar"}},
+			want:        api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "10.0.0.10", Config: map[string]string{"user.foo": "bar"}},
+		},
+		{
+			name:        "Invalid name",
+			resName:     "host_1",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "10.0.0.10"},
+			wantErr:     "Invalid reservation name",
+		},
+		{
+			name:        "Neither MAC address nor DUID",
+			reservation: api.NetworkReservationPut{IPv4Address: "10.0.0.10"},
+			wantErr:     "Either a MAC address or a DUID must be specified",
+		},
+		{
+			name:        "MAC address with dashes",
+			reservation: api.NetworkReservationPut{Hwaddr: "00-16-3e-aa-bb-cc", IPv4Address: "10.0.0.10"},
+			wantErr:     "Invalid MAC address",
+		},
+		{
+			name:        "DUID without separators",
+			reservation: api.NetworkReservationPut{DUID: "0004abcdef", IPv6Address: "fd42::10"},
+			wantErr:     "Invalid DUID",
+		},
+		{
+			name:        "DUID too short",
+			reservation: api.NetworkReservationPut{DUID: "00:04", IPv6Address: "fd42::10"},
+			wantErr:     "Invalid DUID",
+		},
+		{
+			name:        "IPv4 address with a DUID only",
+			reservation: api.NetworkReservationPut{DUID: "00:04:ab:cd:ef", IPv4Address: "10.0.0.10"},
+			wantErr:     "A MAC address must be specified to reserve an IPv4 address",
+		},
+		{
+			name:        "Unknown config key",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "10.0.0.10", Config: map[string]string{"foo": "bar"}},
+			wantErr:     `Invalid option "foo"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := tt.resName
+			if name == "" {
+				name = "host1"
+			}
+
+			reservation := tt.reservation
+			err := n.reservationValidateFields(name, &reservation, subnetV4, subnetV6)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, reservation)
+		})
+	}
+}
+
+func Test_reservationValidateAddresses(t *testing.T) {
+	_, subnetV4, _ := net.ParseCIDR("10.0.0.0/24")
+	_, subnetV6, _ := net.ParseCIDR("fd42::/64")
+
+	tests := []struct {
+		name        string
+		config      map[string]string
+		noDHCPv4    bool
+		noDHCPv6    bool
+		reservation api.NetworkReservationPut
+		wantErr     string
+	}{
+		{
+			name:        "IPv4 address in subnet",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "10.0.0.10"},
+		},
+		{
+			name:        "IPv4 address in DHCP range",
+			config:      map[string]string{"ipv4.dhcp.ranges": "10.0.0.100-10.0.0.200"},
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "10.0.0.150"},
+		},
+		{
+			name:        "IPv6 address in subnet",
+			reservation: api.NetworkReservationPut{DUID: "00:04:ab:cd:ef", IPv6Address: "fd42::10"},
+		},
+		{
+			name:        "IPv6 address in DHCP range",
+			config:      map[string]string{"ipv6.dhcp.ranges": "fd42::100-fd42::200"},
+			reservation: api.NetworkReservationPut{DUID: "00:04:ab:cd:ef", IPv6Address: "fd42::150"},
+		},
+		{
+			name:        "No address",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc"},
+			wantErr:     "At least one of an IPv4 or IPv6 address must be specified",
+		},
+		{
+			name:        "Invalid IPv4 address",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "fd42::10"},
+			wantErr:     "Invalid IPv4 address",
+		},
+		{
+			name:        "Invalid IPv6 address",
+			reservation: api.NetworkReservationPut{DUID: "00:04:ab:cd:ef", IPv6Address: "10.0.0.10"},
+			wantErr:     "Invalid IPv6 address",
+		},
+		{
+			name:        "IPv4 address outside of subnet",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "10.0.1.10"},
+			wantErr:     "is not within the network's subnet",
+		},
+		{
+			name:        "IPv4 address outside of DHCP ranges",
+			config:      map[string]string{"ipv4.dhcp.ranges": "10.0.0.100-10.0.0.200"},
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "10.0.0.10"},
+			wantErr:     "is not within the network's DHCP ranges",
+		},
+		{
+			name:        "IPv6 address outside of DHCP ranges",
+			config:      map[string]string{"ipv6.dhcp.ranges": "fd42::100-fd42::200"},
+			reservation: api.NetworkReservationPut{DUID: "00:04:ab:cd:ef", IPv6Address: "fd42::10"},
+			wantErr:     "is not within the network's DHCP ranges",
+		},
+		{
+			name:        "IPv4 gateway address",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "10.0.0.1"},
+			wantErr:     "is the network's own address",
+		},
+		{
+			name:        "IPv6 gateway address",
+			reservation: api.NetworkReservationPut{DUID: "00:04:ab:cd:ef", IPv6Address: "fd42::1"},
+			wantErr:     "is the network's own address",
+		},
+		{
+			name:        "IPv4 network address",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "10.0.0.0"},
+			wantErr:     "is the subnet's network or broadcast address",
+		},
+		{
+			name:        "IPv4 broadcast address",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "10.0.0.255"},
+			wantErr:     "is the subnet's network or broadcast address",
+		},
+		{
+			name:        "IPv4 address without DHCPv4",
+			noDHCPv4:    true,
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:aa:bb:cc", IPv4Address: "10.0.0.10"},
+			wantErr:     "on a network without DHCP for this address family",
+		},
+		{
+			name:        "IPv6 address without DHCPv6",
+			noDHCPv6:    true,
+			reservation: api.NetworkReservationPut{DUID: "00:04:ab:cd:ef", IPv6Address: "fd42::10"},
+			wantErr:     "on a network without DHCP for this address family",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]string{"ipv4.address": "10.0.0.1/24", "ipv6.address": "fd42::1/64"}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+
+			// No DHCP subnet is passed for the address families without DHCP.
+			v4, v6 := subnetV4, subnetV6
+			if tt.noDHCPv4 {
+				v4 = nil
+			}
+
+			if tt.noDHCPv6 {
+				v6 = nil
+			}
+
+			n := &common{config: config}
+			err := n.reservationValidateAddresses(&tt.reservation, v4, v6)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_reservationConflict(t *testing.T) {
+	reservations := []api.NetworkReservation{
+		{
+			Name:                  "host1",
+			NetworkReservationPut: api.NetworkReservationPut{Hwaddr: "00:16:3e:00:00:01", DUID: "00:04:ab:cd:01", IPv4Address: "10.0.0.10", IPv6Address: "fd42::10"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		resName     string
+		reservation api.NetworkReservationPut
+		wantErr     string
+	}{
+		{
+			name:        "No conflict",
+			resName:     "host2",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:00:00:02", IPv4Address: "10.0.0.11"},
+		},
+		{
+			name:        "Updating the same reservation",
+			resName:     "host1",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:00:00:01", IPv4Address: "10.0.0.10"},
+		},
+		{
+			name:        "Same MAC address",
+			resName:     "host2",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3E:00:00:01", IPv4Address: "10.0.0.11"},
+			wantErr:     "MAC address",
+		},
+		{
+			name:        "Same DUID",
+			resName:     "host2",
+			reservation: api.NetworkReservationPut{DUID: "00:04:AB:CD:01", IPv6Address: "fd42::11"},
+			wantErr:     "DUID",
+		},
+		{
+			name:        "Same IPv4 address",
+			resName:     "host2",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:00:00:02", IPv4Address: "10.0.0.10"},
+			wantErr:     `Address "10.0.0.10" is already reserved`,
+		},
+		{
+			name:        "Same IPv6 address",
+			resName:     "host2",
+			reservation: api.NetworkReservationPut{DUID: "00:04:ab:cd:02", IPv6Address: "fd42:0::10"},
+			wantErr:     `Address "fd42:0::10" is already reserved`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := reservationConflict(tt.resName, &tt.reservation, reservations)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tt.wantErr)
+			assert.True(t, api.StatusErrorCheck(err, http.StatusConflict))
+		})
+	}
+}
+
+func Test_reservationNICConflict(t *testing.T) {
+	inst := db.InstanceArgs{
+		Name:    "c1",
+		Project: "default",
+		Config:  map[string]string{"volatile.eth0.hwaddr": "00:16:3e:00:00:01"},
+	}
+
+	tests := []struct {
+		name        string
+		reservation api.NetworkReservationPut
+		nicConfig   map[string]string
+		wantErr     bool
+	}{
+		{
+			name:        "NIC without static address",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:00:00:02", IPv4Address: "10.0.0.10"},
+			nicConfig:   map[string]string{},
+		},
+		{
+			name:        "NIC with another static address",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:00:00:02", IPv4Address: "10.0.0.10"},
+			nicConfig:   map[string]string{"ipv4.address": "10.0.0.11"},
+		},
+		{
+			name:        "NIC with the reserved IPv4 address",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:00:00:02", IPv4Address: "10.0.0.10"},
+			nicConfig:   map[string]string{"ipv4.address": "10.0.0.10"},
+			wantErr:     true,
+		},
+		{
+			name:        "NIC with the reserved IPv6 address",
+			reservation: api.NetworkReservationPut{DUID: "00:04:ab:cd:01", IPv6Address: "fd42::10"},
+			nicConfig:   map[string]string{"ipv6.address": "fd42:0::10"},
+			wantErr:     true,
+		},
+		{
+			name:        "NIC using the reserved MAC address from volatile",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:00:00:01", IPv4Address: "10.0.0.10"},
+			nicConfig:   map[string]string{"ipv4.address": "10.0.0.10"},
+		},
+		{
+			name:        "NIC using the reserved MAC address from config",
+			reservation: api.NetworkReservationPut{Hwaddr: "00:16:3e:00:00:03", IPv4Address: "10.0.0.10"},
+			nicConfig:   map[string]string{"hwaddr": "00:16:3E:00:00:03", "ipv4.address": "10.0.0.10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := reservationNICConflict(&tt.reservation, inst, "eth0", tt.nicConfig)
+			if tt.wantErr {
+				assert.True(t, api.StatusErrorCheck(err, http.StatusConflict))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"maps"
 	"math/big"
+	"math/rand"
 	"net"
 	"net/http"
 	"net/netip"
 	"os"
+	"regexp"
 	"slices"
 	"sort"
 	"strconv"
@@ -33,9 +35,11 @@ import (
 	"github.com/lxc/incus/v6/internal/server/dnsmasq/dhcpalloc"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/locking"
 	"github.com/lxc/incus/v6/internal/server/network/acl"
 	addressset "github.com/lxc/incus/v6/internal/server/network/address-set"
+	"github.com/lxc/incus/v6/internal/server/network/dhcpv6pd"
 	networkOVN "github.com/lxc/incus/v6/internal/server/network/ovn"
 	ovnNB "github.com/lxc/incus/v6/internal/server/network/ovn/schema/ovn-nb"
 	ovnSB "github.com/lxc/incus/v6/internal/server/network/ovn/schema/ovn-sb"
@@ -70,6 +74,8 @@ type ovnUplinkVars struct {
 	routerExtPortIPv6Net string
 	routerExtGwIPv4      net.IP
 	routerExtGwIPv6      net.IP
+	routerExtGwIPv4BFD   bool
+	routerExtGwIPv6BFD   bool
 
 	// External Switch.
 	extSwitchProviderName string
@@ -94,6 +100,7 @@ type OVNInstanceNICSetupOpts struct {
 	UplinkConfig map[string]string
 	DNSName      string
 	LastStateIPs []net.IP
+	BIOS         bool // Whether the instance boots with BIOS firmware.
 }
 
 // OVNInstanceNICStopOpts options for stopping an OVN Instance NIC.
@@ -149,6 +156,7 @@ func (n *ovn) Info() Info {
 	info.Projects = true
 	info.NodeSpecificConfig = false
 	info.AddressForwards = true
+	info.DHCPReservations = true
 	info.LoadBalancers = true
 	info.Peering = true
 
@@ -184,6 +192,8 @@ func (n *ovn) State() (*api.NetworkState, error) {
 	var hwaddr string
 	var uplinkIPv4 string
 	var uplinkIPv6 string
+	var uplinkGatewayBFD map[string]string
+	var gatewayChassis map[string]int
 
 	logicalRouterName := n.getRouterName()
 	logicalSwitchName := n.getIntSwitchName()
@@ -204,6 +214,22 @@ func (n *ovn) State() (*api.NetworkState, error) {
 		if n.config[ovnVolatileUplinkIPv6] != "" {
 			uplinkIPv6 = n.config[ovnVolatileUplinkIPv6]
 		}
+
+		// Get the BFD state of the uplink gateways.
+		uplinkGatewayBFD, err = n.ovnnb.GetLogicalRouterPortBFD(context.TODO(), n.getRouterExtPortName())
+		if err != nil {
+			return nil, err
+		}
+
+		if len(uplinkGatewayBFD) == 0 {
+			uplinkGatewayBFD = nil
+		}
+
+		// Get the priorities of the gateway chassis.
+		gatewayChassis, err = n.getGatewayChassis()
+		if err != nil {
+			return nil, err
+		}
 	} else if n.config["ipv4.address"] == "none" && n.config["ipv6.address"] == "none" {
 		// Networks with no uplink and no IP addresses will not have a router.
 		logicalRouterName = ""
@@ -235,11 +261,13 @@ func (n *ovn) State() (*api.NetworkState, error) {
 		State:     "up",
 		Type:      "broadcast",
 		OVN: &api.NetworkStateOVN{
-			Chassis:       chassis,
-			LogicalRouter: string(logicalRouterName),
-			LogicalSwitch: string(logicalSwitchName),
-			UplinkIPv4:    uplinkIPv4,
-			UplinkIPv6:    uplinkIPv6,
+			Chassis:          chassis,
+			LogicalRouter:    string(logicalRouterName),
+			LogicalSwitch:    string(logicalSwitchName),
+			UplinkIPv4:       uplinkIPv4,
+			UplinkIPv6:       uplinkIPv6,
+			UplinkGatewayBFD: uplinkGatewayBFD,
+			GatewayChassis:   gatewayChassis,
 		},
 	}, nil
 }
@@ -360,6 +388,55 @@ func (n *ovn) validateExternalSubnet(uplink *api.Network, projectRestrictedSubne
 	return api.StatusErrorf(http.StatusBadRequest, "Uplink network doesn't contain %q in its routes", ipNet.String())
 }
 
+// validateGatewayECMP checks that the ECMP next hops are distinct addresses within the uplink network's subnets.
+func validateGatewayECMP(uplink *api.Network, value string) error {
+	uplinkIPv4CIDR := uplink.Config["ipv4.address"]
+	if uplinkIPv4CIDR == "" {
+		uplinkIPv4CIDR = uplink.Config["ipv4.gateway"]
+	}
+
+	uplinkIPv6CIDR := uplink.Config["ipv6.address"]
+	if uplinkIPv6CIDR == "" {
+		uplinkIPv6CIDR = uplink.Config["ipv6.gateway"]
+	}
+
+	uplinkIPv4, uplinkIPv4Net, _ := net.ParseCIDR(uplinkIPv4CIDR)
+	uplinkIPv6, uplinkIPv6Net, _ := net.ParseCIDR(uplinkIPv6CIDR)
+
+	nextHops := []string{}
+	for _, entry := range util.SplitNTrimSpace(value, ",", -1, true) {
+		nextHop := net.ParseIP(entry)
+		if nextHop == nil {
+			return fmt.Errorf("Invalid next hop %q", entry)
+		}
+
+		if slices.Contains(nextHops, nextHop.String()) {
+			return fmt.Errorf("Duplicate next hop %q", entry)
+		}
+
+		nextHops = append(nextHops, nextHop.String())
+
+		uplinkIP, uplinkNet := uplinkIPv6, uplinkIPv6Net
+		if nextHop.To4() != nil {
+			uplinkIP, uplinkNet = uplinkIPv4, uplinkIPv4Net
+		}
+
+		if uplinkNet == nil {
+			return fmt.Errorf("Uplink network %q has no gateway subnet for next hop %q", uplink.Name, entry)
+		}
+
+		if !uplinkNet.Contains(nextHop) {
+			return fmt.Errorf("Next hop %q isn't within the uplink subnet %q", entry, uplinkNet.String())
+		}
+
+		if nextHop.Equal(uplinkIP) {
+			return fmt.Errorf("Next hop %q is already the uplink gateway", entry)
+		}
+	}
+
+	return nil
+}
+
 // getExternalSubnetInUse returns information about usage of external subnets by networks and NICs connected to,
 // or used by, the specified uplinkNetworkName.
 func (n *ovn) getExternalSubnetInUse(uplinkNetworkName string) ([]externalSubnetUsage, error) {
@@ -506,6 +583,38 @@ func (n *ovn) Validate(config map[string]string, clientType request.ClientType)
 		//  shortdesc: Static routes to provide via DHCP option 121, as a comma-separated list of alternating subnets (CIDR) and gateway addresses (same syntax as dnsmasq and OVN)
 		"ipv4.dhcp.routes": validate.Optional(validate.IsDHCPRouteList),
 
+		// gendoc:generate(entity=network_ovn, group=common, key=ipv4.dhcp.boot.server)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  shortdesc: Address of the TFTP server to network boot from
+		"ipv4.dhcp.boot.server": validate.Optional(validate.IsNetworkAddressV4),
+
+		// gendoc:generate(entity=network_ovn, group=common, key=ipv4.dhcp.boot.filename.bios)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  shortdesc: Boot file for BIOS clients (virtual machines with `security.csm` enabled)
+		"ipv4.dhcp.boot.filename.bios": validate.Optional(validateBootFilename),
+
+		// gendoc:generate(entity=network_ovn, group=common, key=ipv4.dhcp.boot.filename.uefi)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  shortdesc: Boot file for UEFI clients
+		"ipv4.dhcp.boot.filename.uefi": validate.Optional(validateBootFilename),
+
+		// gendoc:generate(entity=network_ovn, group=common, key=ipv4.dhcp.boot.filename.ipxe)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv4 DHCP
+		//  shortdesc: Boot file or URL for iPXE clients (to chainload a script)
+		"ipv4.dhcp.boot.filename.ipxe": validate.Optional(validateBootFilename),
+
 		// gendoc:generate(entity=network_ovn, group=common, key=ipv6.address)
 		//
 		// ---
@@ -539,6 +648,23 @@ func (n *ovn) Validate(config map[string]string, clientType request.ClientType)
 		//  default: `false`
 		"ipv6.dhcp.stateful": validate.Optional(validate.IsBool),
 
+		// gendoc:generate(entity=network_ovn, group=common, key=ipv6.dhcp.pd.prefix)
+		//
+		// ---
+		//  type: string
+		//  condition: IPv6 address
+		//  shortdesc: Pool of IPv6 prefixes to delegate to instances, routed to their IPv6 address (CIDR notation)
+		"ipv6.dhcp.pd.prefix": validate.Optional(validate.IsNetworkV6),
+
+		// gendoc:generate(entity=network_ovn, group=common, key=ipv6.dhcp.pd.size)
+		//
+		// ---
+		//  type: integer
+		//  condition: IPv6 prefix delegation
+		//  default: `64`
+		//  shortdesc: Length of the prefixes delegated to instances
+		"ipv6.dhcp.pd.size": validate.Optional(validate.IsInRange(1, 128)),
+
 		// gendoc:generate(entity=network_ovn, group=common, key=ipv4.nat)
 		//
 		// ---
@@ -573,6 +699,15 @@ func (n *ovn) Validate(config map[string]string, clientType request.ClientType)
 		//  shortdesc: The source address used for outbound traffic from the network (requires uplink `ovn.ingress_mode=routed`)
 		"ipv6.nat.address": validate.Optional(validate.IsNetworkAddressV6),
 
+		// gendoc:generate(entity=network_ovn, group=common, key=ipv6.nat64)
+		//
+		// ---
+		//  type: bool
+		//  condition: IPv6 address
+		//  default: `false`
+		//  shortdesc: Whether to translate traffic to IPv4 destinations through the NAT64 of the uplink bridge network
+		"ipv6.nat64": validate.Optional(validate.IsBool),
+
 		// gendoc:generate(entity=network_ovn, group=common, key=ipv4.l3only)
 		//
 		// ---
@@ -591,6 +726,23 @@ func (n *ovn) Validate(config map[string]string, clientType request.ClientType)
 		//  default: `false`
 		"ipv6.l3only": validate.Optional(validate.IsBool),
 
+		// gendoc:generate(entity=network_ovn, group=common, key=ovn.gateway.chassis)
+		//
+		// ---
+		//  type: string
+		//  condition: uplink network
+		//  default: all OVN chassis members, in a stable random order
+		//  shortdesc: Comma-separated list of cluster members or `@` groups to use as gateway chassis, in priority order
+		"ovn.gateway.chassis": validate.Optional(validate.IsListOf(validateGatewayChassisEntry)),
+
+		// gendoc:generate(entity=network_ovn, group=common, key=ovn.gateway.ecmp)
+		//
+		// ---
+		//  type: string
+		//  condition: uplink network
+		//  shortdesc: Comma-separated list of additional uplink next hops to balance the active gateway's outbound traffic across
+		"ovn.gateway.ecmp": validate.Optional(validate.IsListOf(validate.IsNetworkAddress)),
+
 		// gendoc:generate(entity=network_ovn, group=common, key=dns.nameservers)
 		//
 		// ---
@@ -900,17 +1052,79 @@ func (n *ovn) Validate(config map[string]string, clientType request.ClientType)
 		}
 	}
 
+	// Check gateway chassis members and groups exist.
+	if config["ovn.gateway.chassis"] != "" && clientType != request.ClientTypeNotifier {
+		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			for _, entry := range util.SplitNTrimSpace(config["ovn.gateway.chassis"], ",", -1, true) {
+				groupName, isGroup := strings.CutPrefix(entry, "@")
+				if isGroup {
+					exists, err := dbCluster.ClusterGroupExists(ctx, tx.Tx(), groupName)
+					if err != nil {
+						return err
+					}
+
+					if !exists {
+						return fmt.Errorf("Cluster group %q doesn't exist", groupName)
+					}
+
+					continue
+				}
+
+				_, err := tx.GetNodeByName(ctx, entry)
+				if err != nil {
+					return fmt.Errorf("Failed loading cluster member %q: %w", entry, err)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("Invalid gateway chassis: %w", err)
+		}
+	}
+
+	// Check the IPv6 prefix delegation pool.
+	err = prefixDelegationValidate(config)
+	if err != nil {
+		return err
+	}
+
 	// Check that ipv6.l3only mode is used with ipvp.dhcp.stateful.
 	// As otherwise the router advertisements will configure an address using the subnet's mask.
 	if util.IsTrue(config["ipv6.l3only"]) && util.IsTrueOrEmpty(config["ipv6.dhcp"]) && util.IsFalseOrEmpty(config["ipv6.dhcp.stateful"]) {
 		return errors.New("The ipv6.dhcp.stateful setting must be enabled when using ipv6.l3only mode with ipv6.dhcp enabled")
 	}
 
+	// NAT64 is provided by the uplink network.
+	if util.IsTrue(config["ipv6.nat64"]) {
+		if util.IsNoneOrEmpty(config["ipv6.address"]) {
+			return errors.New(`"ipv6.nat64" requires "ipv6.address" to be set`)
+		}
+
+		if config["network"] == "none" {
+			return errors.New(`"ipv6.nat64" requires an uplink network`)
+		}
+	}
+
+	if config["ovn.gateway.ecmp"] != "" && config["network"] == "none" {
+		return errors.New(`"ovn.gateway.ecmp" requires an uplink network`)
+	}
+
 	// All tests below are related to the uplink network, skip if we don't have one.
 	if uplink == nil {
 		return nil
 	}
 
+	if util.IsTrue(config["ipv6.nat64"]) && (uplink.Type != "bridge" || util.IsFalseOrEmpty(uplink.Config["ipv6.nat64"])) {
+		return fmt.Errorf(`"ipv6.nat64" requires the uplink network %q to be a bridge network with "ipv6.nat64" enabled`, uplink.Name)
+	}
+
+	// Check the ECMP next hops are on the uplink network.
+	err = validateGatewayECMP(uplink, config["ovn.gateway.ecmp"])
+	if err != nil {
+		return fmt.Errorf("Invalid %q: %w", "ovn.gateway.ecmp", err)
+	}
+
 	// If NAT disabled, parse the external subnets that are being requested.
 	var externalSubnets []*net.IPNet // Subnets to check for conflicts with other networks/NICs.
 	for _, keyPrefix := range []string{"ipv4", "ipv6"} {
@@ -1474,12 +1688,14 @@ func (n *ovn) allocateUplinkPortIPs(uplinkNet Network, routerMAC net.HardwareAdd
 	if err == nil {
 		v.dnsIPv4 = []net.IP{uplinkIPv4}
 		v.routerExtGwIPv4 = uplinkIPv4
+		v.routerExtGwIPv4BFD = util.IsTrue(uplinkNetConf["ipv4.gateway.bfd"])
 	}
 
 	uplinkIPv6, uplinkIPv6Net, err := net.ParseCIDR(uplinkIPv6CIDR)
 	if err == nil {
 		v.dnsIPv6 = []net.IP{uplinkIPv6}
 		v.routerExtGwIPv6 = uplinkIPv6
+		v.routerExtGwIPv6BFD = util.IsTrue(uplinkNetConf["ipv6.gateway.bfd"])
 	}
 
 	// Detect optional DNS server list.
@@ -2407,7 +2623,7 @@ func (n *ovn) validateUplinkNetwork(p *api.Project, uplinkNetworkName string) (s
 	return "", errors.New(`Option "network" is required`)
 }
 
-// getDHCPv4Reservations returns list DHCP IPv4 reservations from NICs connected to this network.
+// getDHCPv4Reservations returns list DHCP IPv4 reservations from NICs and DHCP reservations of this network.
 func (n *ovn) getDHCPv4Reservations() ([]iprange.Range, error) {
 	routerIntPortIPv4, ipv4Net, err := n.parseRouterIntPortIPv4Net()
 	if err != nil {
@@ -2464,6 +2680,19 @@ func (n *ovn) getDHCPv4Reservations() ([]iprange.Range, error) {
 		return nil, err
 	}
 
+	// Exclude the addresses of the network's DHCP reservations from dynamic allocation.
+	reservations, err := n.reservations()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reservation := range reservations {
+		ip := net.ParseIP(reservation.IPv4Address)
+		if ip != nil && !ipInRanges(ip, dhcpReserveIPv4s) {
+			dhcpReserveIPv4s = append(dhcpReserveIPv4s, iprange.Range{Start: ip})
+		}
+	}
+
 	return dhcpReserveIPv4s, nil
 }
 
@@ -2793,10 +3022,10 @@ func (n *ovn) setup(update bool) error {
 			}
 		}
 
-		// Clear default routes (if existing) and re-apply based on current config.
+		// Apply the default routes based on current config and clear the ones no longer configured.
 		defaultIPv4Route := net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, 32)}
 		defaultIPv6Route := net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, 128)}
-		deleteRoutes := []net.IPNet{defaultIPv4Route, defaultIPv6Route}
+		deleteRoutes := []net.IPNet{}
 		defaultRoutes := make([]networkOVN.OVNRouterRoute, 0, 2)
 
 		if routerIntPortIPv4Net != nil {
@@ -2834,6 +3063,7 @@ func (n *ovn) setup(update bool) error {
 				Prefix:  defaultIPv4Route,
 				NextHop: uplinkNet.routerExtGwIPv4,
 				Port:    n.getRouterExtPortName(),
+				BFD:     uplinkNet.routerExtGwIPv4BFD,
 			})
 		}
 
@@ -2842,9 +3072,34 @@ func (n *ovn) setup(update bool) error {
 				Prefix:  defaultIPv6Route,
 				NextHop: uplinkNet.routerExtGwIPv6,
 				Port:    n.getRouterExtPortName(),
+				BFD:     uplinkNet.routerExtGwIPv6BFD,
 			})
 		}
 
+		// Add the additional next hops, OVN balances traffic across routes sharing the same prefix.
+		for _, entry := range util.SplitNTrimSpace(n.config["ovn.gateway.ecmp"], ",", -1, true) {
+			nextHop := net.ParseIP(entry)
+			if nextHop == nil {
+				return fmt.Errorf("Invalid ECMP next hop %q", entry)
+			}
+
+			if nextHop.To4() != nil && uplinkNet.routerExtGwIPv4 != nil {
+				defaultRoutes = append(defaultRoutes, networkOVN.OVNRouterRoute{
+					Prefix:  defaultIPv4Route,
+					NextHop: nextHop,
+					Port:    n.getRouterExtPortName(),
+					BFD:     uplinkNet.routerExtGwIPv4BFD,
+				})
+			} else if nextHop.To4() == nil && uplinkNet.routerExtGwIPv6 != nil {
+				defaultRoutes = append(defaultRoutes, networkOVN.OVNRouterRoute{
+					Prefix:  defaultIPv6Route,
+					NextHop: nextHop,
+					Port:    n.getRouterExtPortName(),
+					BFD:     uplinkNet.routerExtGwIPv6BFD,
+				})
+			}
+		}
+
 		if len(deleteRoutes) > 0 {
 			err = n.ovnnb.DeleteLogicalRouterRoute(context.TODO(), n.getRouterName(), deleteRoutes...)
 			if err != nil {
@@ -2852,6 +3107,35 @@ func (n *ovn) setup(update bool) error {
 			}
 		}
 
+		// Remove the default routes which are no longer configured (such as removed ECMP next hops),
+		// leaving the others in place so the traffic using them isn't disrupted.
+		existingRoutes, err := n.ovnnb.GetLogicalRouterRoutes(context.TODO(), n.getRouterName())
+		if err != nil {
+			return fmt.Errorf("Failed getting router routes: %w", err)
+		}
+
+		staleRoutes := []networkOVN.OVNRouterRoute{}
+		for _, existing := range existingRoutes {
+			if existing.Prefix.String() != defaultIPv4Route.String() && existing.Prefix.String() != defaultIPv6Route.String() {
+				continue
+			}
+
+			configured := slices.ContainsFunc(defaultRoutes, func(route networkOVN.OVNRouterRoute) bool {
+				return route.Prefix.String() == existing.Prefix.String() && route.NextHop.Equal(existing.NextHop) && route.Port == existing.Port && route.BFD == existing.BFD && route.Discard == existing.Discard
+			})
+
+			if !configured {
+				staleRoutes = append(staleRoutes, existing)
+			}
+		}
+
+		if len(staleRoutes) > 0 {
+			err = n.ovnnb.DeleteLogicalRouterRouteNextHop(context.TODO(), n.getRouterName(), staleRoutes...)
+			if err != nil {
+				return fmt.Errorf("Failed removing stale default routes: %w", err)
+			}
+		}
+
 		if len(defaultRoutes) > 0 {
 			err = n.ovnnb.CreateLogicalRouterRoute(context.TODO(), n.getRouterName(), update, defaultRoutes...)
 			if err != nil {
@@ -3066,7 +3350,7 @@ func (n *ovn) setup(update bool) error {
 	}
 
 	// Configure DHCP option sets.
-	var dhcpv4UUID, dhcpv6UUID networkOVN.OVNDHCPOptionsUUID
+	var dhcpv4UUID, dhcpv4BIOSUUID, dhcpv6UUID networkOVN.OVNDHCPOptionsUUID
 	dhcpV4Subnet := n.DHCPv4Subnet()
 	dhcpV6Subnet := n.DHCPv6Subnet()
 
@@ -3076,11 +3360,20 @@ func (n *ovn) setup(update bool) error {
 			return err
 		}
 
+		dhcpv4BIOSUUID, err = n.getDhcpBIOSOptionUUID()
+		if err != nil {
+			return err
+		}
+
 		var deleteDHCPRecords []networkOVN.OVNDHCPOptionsUUID
 		if dhcpV4Subnet == nil && dhcpv4UUID != "" {
 			deleteDHCPRecords = append(deleteDHCPRecords, dhcpv4UUID)
 		}
 
+		if dhcpV4Subnet == nil && dhcpv4BIOSUUID != "" {
+			deleteDHCPRecords = append(deleteDHCPRecords, dhcpv4BIOSUUID)
+		}
+
 		if dhcpV6Subnet == nil && dhcpv6UUID != "" {
 			deleteDHCPRecords = append(deleteDHCPRecords, dhcpv6UUID)
 		}
@@ -3109,6 +3402,11 @@ func (n *ovn) setup(update bool) error {
 		if uplinkNet != nil {
 			dnsIPv4 = uplinkNet.dnsIPv4
 			dnsIPv6 = uplinkNet.dnsIPv6
+
+			// Use the DNS64 server of the uplink bridge so that IPv4-only names resolve through NAT64.
+			if util.IsTrue(n.config["ipv6.nat64"]) && uplinkNet.routerExtGwIPv6 != nil {
+				dnsIPv6 = []net.IP{uplinkNet.routerExtGwIPv6}
+			}
 		}
 
 		if len(dnsIPv4) == 0 {
@@ -3156,6 +3454,9 @@ func (n *ovn) setup(update bool) error {
 			DNSSearchList:      n.getDNSSearchList(),
 			StaticRoutes:       n.config["ipv4.dhcp.routes"],
 			RecursiveDNSServer: dnsIPv4,
+			BootServer:         net.ParseIP(n.config["ipv4.dhcp.boot.server"]),
+			BootFilename:       n.config["ipv4.dhcp.boot.filename.uefi"],
+			BootFilenameIPXE:   n.config["ipv4.dhcp.boot.filename.ipxe"],
 		}
 
 		err = n.ovnnb.UpdateLogicalSwitchDHCPv4Options(context.TODO(), n.getIntSwitchName(), dhcpv4UUID, dhcpV4Subnet, opts)
@@ -3166,6 +3467,19 @@ func (n *ovn) setup(update bool) error {
 		if dhcpv4UUID == "" {
 			dhcpv4Created = true
 		}
+
+		// OVN can't tell the firmware of the clients apart, so the BIOS clients get their own options.
+		// The set is kept once created as instance ports may be using it.
+		if n.config["ipv4.dhcp.boot.filename.bios"] != "" || dhcpv4BIOSUUID != "" {
+			biosOpts := *opts
+			biosOpts.BootFilename = n.config["ipv4.dhcp.boot.filename.bios"]
+			biosOpts.BIOS = true
+
+			err = n.ovnnb.UpdateLogicalSwitchDHCPv4Options(context.TODO(), n.getIntSwitchName(), dhcpv4BIOSUUID, dhcpV4Subnet, &biosOpts)
+			if err != nil {
+				return fmt.Errorf("Failed adding BIOS DHCPv4 settings for internal switch: %w", err)
+			}
+		}
 	}
 
 	// Create DHCPv6 options for internal switch.
@@ -3343,6 +3657,10 @@ func (n *ovn) getDhcpOptionUUIDs() (v4Uuid networkOVN.OVNDHCPOptionsUUID, v6Uuid
 	}
 
 	for _, existingOpt := range existingOpts {
+		if existingOpt.BIOS {
+			continue
+		}
+
 		if existingOpt.CIDR.IP.To4() == nil {
 			if v6Uuid != "" {
 				return "", "", fmt.Errorf("Multiple matching DHCPv6 option sets found for switch %q", n.getIntSwitchName())
@@ -3361,6 +3679,22 @@ func (n *ovn) getDhcpOptionUUIDs() (v4Uuid networkOVN.OVNDHCPOptionsUUID, v6Uuid
 	return v4Uuid, v6Uuid, nil
 }
 
+// getDhcpBIOSOptionUUID returns the DHCPv4 options set used by the clients booting with BIOS firmware (if any).
+func (n *ovn) getDhcpBIOSOptionUUID() (networkOVN.OVNDHCPOptionsUUID, error) {
+	existingOpts, err := n.ovnnb.GetLogicalSwitchDHCPOptions(context.TODO(), n.getIntSwitchName())
+	if err != nil {
+		return "", fmt.Errorf("Failed getting existing DHCP settings for internal switch: %w", err)
+	}
+
+	for _, existingOpt := range existingOpts {
+		if existingOpt.BIOS {
+			return existingOpt.UUID, nil
+		}
+	}
+
+	return "", nil
+}
+
 // logicalRouterPolicySetup applies the security policy to the logical router (clearing any existing policies).
 // Optionally excludePeers takes a list of peer network IDs to exclude from the router policy. This is useful
 // when removing a peer connection as it allows the security policy to be removed from OVN for that peer before the
@@ -3450,7 +3784,9 @@ func (n *ovn) ensureNetworkPortGroup(projectID int64) error {
 // addChassisGroupEntry adds an entry for the local OVS chassis to the OVN logical network's chassis group.
 // The chassis priority value is a stable-random value derived from chassis group name and node ID. This is so we
 // don't end up using the same chassis for the primary uplink chassis for all OVN networks in a cluster.
-func (n *ovn) addChassisGroupEntry() error {
+// If a gateway chassis list is provided, the priority is restricted to the range of the first entry matching the
+// local member and members which aren't listed are removed from the chassis group.
+func (n *ovn) addChassisGroupEntry(gatewayChassis string) error {
 	// Get local chassis ID for chassis group.
 	vswitch, err := n.state.OVS()
 	if err != nil {
@@ -3471,8 +3807,10 @@ func (n *ovn) addChassisGroupEntry() error {
 		return fmt.Errorf("Failed generating stable random chassis group priority: %w", err)
 	}
 
-	// Get all members in cluster.
+	// Get all members in cluster (or in the gateway chassis entry matching the local member).
 	ourMemberID := int(n.state.DB.Cluster.GetNodeID())
+	gatewayEntries := util.SplitNTrimSpace(gatewayChassis, ",", -1, true)
+	gatewayEntry := -1
 	var memberIDs []int
 	err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		members, err := tx.GetNodes(ctx)
@@ -3480,7 +3818,30 @@ func (n *ovn) addChassisGroupEntry() error {
 			return fmt.Errorf("Failed getting cluster members for adding chassis group entry: %w", err)
 		}
 
+		// Find the first gateway chassis entry matching the local member.
+		var memberNames []string
+		for i, entry := range gatewayEntries {
+			groupName, isGroup := strings.CutPrefix(entry, "@")
+			if isGroup {
+				memberNames, err = tx.GetClusterGroupNodes(ctx, groupName)
+				if err != nil {
+					return fmt.Errorf("Failed getting cluster group %q members: %w", groupName, err)
+				}
+			} else {
+				memberNames = []string{entry}
+			}
+
+			if slices.Contains(memberNames, n.state.ServerName) {
+				gatewayEntry = i
+				break
+			}
+		}
+
 		for _, member := range members {
+			if gatewayEntry >= 0 && !slices.Contains(memberNames, member.Name) {
+				continue
+			}
+
 			memberIDs = append(memberIDs, int(member.ID))
 		}
 
@@ -3490,27 +3851,69 @@ func (n *ovn) addChassisGroupEntry() error {
 		return err
 	}
 
-	// Sort the nodes based on ID for stable priority generation.
+	// The local member isn't one of the selected gateway chassis.
+	if len(gatewayEntries) > 0 && gatewayEntry < 0 {
+		return n.deleteChassisGroupEntry()
+	}
+
+	priority := ovnChassisPriority(r, memberIDs, ourMemberID, gatewayEntry, len(gatewayEntries))
+
+	err = n.ovnnb.SetChassisGroupPriority(context.TODO(), chassisGroupName, chassisID, priority)
+	if err != nil {
+		return fmt.Errorf("Failed adding OVS chassis %q with priority %d to chassis group %q: %w", chassisID, priority, chassisGroupName, err)
+	}
+
+	n.logger.Debug("Chassis group entry added", logger.Ctx{"chassisGroup": chassisGroupName, "memberID": ourMemberID, "priority": priority})
+
+	return nil
+}
+
+// ovnChassisPriority returns the chassis priority of ourMemberID, generated from r for each of the memberIDs.
+// In this way the chassis priority for each member is set to a per-member stable random value.
+// When gatewayEntries isn't zero, the priority is restricted to the range of the gatewayEntry index, each
+// gateway chassis entry getting its own range, from highest to lowest priority.
+func ovnChassisPriority(r *rand.Rand, memberIDs []int, ourMemberID int, gatewayEntry int, gatewayEntries int) int {
+	// Sort the members based on ID for stable priority generation.
+	memberIDs = slices.Clone(memberIDs)
 	sort.Ints(memberIDs)
 
-	// Generate a random priority from the seed for each node until we find a match for our node ID.
-	// In this way the chassis priority for this node will be set to a per-node stable random value.
+	// Generate a random priority from the seed for each member until we find a match for our member ID.
 	var priority int
 	for _, memberID := range memberIDs {
-		priority = r.Intn(ovnChassisPriorityMax + 1)
+		if gatewayEntries > 0 {
+			entrySize := max((ovnChassisPriorityMax+1)/gatewayEntries, 1)
+			priority = max(ovnChassisPriorityMax-(gatewayEntry*entrySize)-r.Intn(entrySize), 0)
+		} else {
+			priority = r.Intn(ovnChassisPriorityMax + 1)
+		}
+
 		if memberID == ourMemberID {
 			break
 		}
 	}
 
-	err = n.ovnnb.SetChassisGroupPriority(context.TODO(), chassisGroupName, chassisID, priority)
+	return priority
+}
+
+// refreshChassisGroupEntry adds or removes the local OVS chassis from the OVN logical network's chassis group.
+func (n *ovn) refreshChassisGroupEntry(gatewayChassis string) error {
+	var chassisEnabled bool
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		chassisEnabled, err = n.chassisEnabled(ctx, tx)
+
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("Failed adding OVS chassis %q with priority %d to chassis group %q: %w", chassisID, priority, chassisGroupName, err)
+		return err
 	}
 
-	n.logger.Debug("Chassis group entry added", logger.Ctx{"chassisGroup": chassisGroupName, "memberID": ourMemberID, "priority": priority})
+	if !chassisEnabled {
+		return n.deleteChassisGroupEntry()
+	}
 
-	return nil
+	return n.addChassisGroupEntry(gatewayChassis)
 }
 
 // deleteChassisGroupEntry deletes an entry for the local OVS chassis from the OVN logical network's chassis group.
@@ -3754,7 +4157,7 @@ func (n *ovn) Start() error {
 	// Handle chassis groups.
 	if chassisEnabled {
 		// Add local member's OVS chassis ID to logical chassis group.
-		err = n.addChassisGroupEntry()
+		err = n.addChassisGroupEntry(n.config["ovn.gateway.chassis"])
 		if err != nil {
 			return err
 		}
@@ -3784,7 +4187,7 @@ func (n *ovn) Start() error {
 
 	// Setup event handler for monitored services.
 	handler := networkOVN.EventHandler{
-		Tables: []string{"Service_Monitor", "Port_Binding"},
+		Tables: []string{"Service_Monitor", "Port_Binding", "BFD"},
 		Hook: func(action string, table string, oldObject ovsdbModel.Model, newObject ovsdbModel.Model) {
 			// Skip invalid notifications.
 			if oldObject == nil && newObject == nil {
@@ -3881,6 +4284,34 @@ func (n *ovn) Start() error {
 				if err != nil {
 					return
 				}
+
+			case *ovnSB.BFD:
+				if ovnSBObject.LogicalPort != string(n.getRouterExtPortName()) || action != "update" {
+					return
+				}
+
+				oldBFD, ok := oldObject.(*ovnSB.BFD)
+				if !ok || oldBFD.Status == ovnSBObject.Status {
+					return
+				}
+
+				// Only report the change from the active chassis to avoid duplicate events.
+				chassisName, err := n.getActiveChassisName()
+				if err != nil || (chassisName != n.state.ServerName && chassisName != n.state.OS.Hostname) {
+					return
+				}
+
+				var bfdAction lifecycle.NetworkAction
+				switch ovnSBObject.Status {
+				case ovnSB.BFDStatusUp:
+					bfdAction = lifecycle.NetworkBFDSessionUp
+				case ovnSB.BFDStatusDown:
+					bfdAction = lifecycle.NetworkBFDSessionDown
+				default:
+					return
+				}
+
+				n.state.Events.SendLifecycle(n.project, bfdAction.Event(n, nil, map[string]any{"gateway": ovnSBObject.DstIP}))
 			}
 		},
 	}
@@ -3984,6 +4415,13 @@ func (n *ovn) Update(newNetwork api.NetworkPut, targetNode string, clientType re
 			}
 		}
 
+		if slices.Contains(changedKeys, "ovn.gateway.chassis") && n.LocalStatus() == api.NetworkStatusCreated {
+			err = n.refreshChassisGroupEntry(newNetwork.Config["ovn.gateway.chassis"])
+			if err != nil {
+				return err
+			}
+		}
+
 		return nil
 	}
 
@@ -3991,6 +4429,24 @@ func (n *ovn) Update(newNetwork api.NetworkPut, targetNode string, clientType re
 		return nil // Nothing changed.
 	}
 
+	// Check the DHCP reservations still fit the new subnets and DHCP ranges.
+	if slices.ContainsFunc(changedKeys, func(key string) bool { return slices.Contains(reservationConfigKeys, key) }) {
+		newNet := *n
+		newNet.config = newNetwork.Config
+
+		err = newNet.reservationsRevalidate(func(reservation *api.NetworkReservationPut) error {
+			err := newNet.reservationValidateAddresses(reservation, newNet.DHCPv4Subnet(), newNet.DHCPv6Subnet())
+			if err != nil {
+				return err
+			}
+
+			return newNet.reservationValidateRouterAddress(reservation)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	// If the network as a whole has not had any previous creation attempts, or the node itself is still
 	// pending, then don't apply the new settings to the node, just to the database record (ready for the
 	// actual global create request to be initiated).
@@ -4318,6 +4774,14 @@ func (n *ovn) Update(newNetwork api.NetworkPut, targetNode string, clientType re
 		if err != nil {
 			return err
 		}
+
+		// Apply the gateway chassis changes for the local member.
+		if slices.Contains(changedKeys, "ovn.gateway.chassis") {
+			err = n.refreshChassisGroupEntry(n.config["ovn.gateway.chassis"])
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	err = n.loadBalancerBGPSetupPrefixes()
@@ -4569,8 +5033,32 @@ func (n *ovn) InstanceDevicePortStart(opts *OVNInstanceNICSetupOpts, securityACL
 	ipv4 := opts.DeviceConfig["ipv4.address"]
 	ipv6 := opts.DeviceConfig["ipv6.address"]
 
-	internalRoutes, externalRoutes, err := n.instanceDevicePortRoutesParse(opts.DeviceConfig)
-	if err != nil {
+	// Use the addresses of a DHCP reservation matching the NIC's MAC address if not statically set.
+	if ipv4 == "" || ipv6 == "" {
+		reservations, err := n.reservations()
+		if err != nil {
+			return "", nil, fmt.Errorf("Failed getting DHCP reservations: %w", err)
+		}
+
+		for _, reservation := range reservations {
+			if !hwaddrEqual(reservation.Hwaddr, mac.String()) {
+				continue
+			}
+
+			if ipv4 == "" {
+				ipv4 = reservation.IPv4Address
+			}
+
+			if ipv6 == "" {
+				ipv6 = reservation.IPv6Address
+			}
+
+			break
+		}
+	}
+
+	internalRoutes, externalRoutes, err := n.instanceDevicePortRoutesParse(opts.DeviceConfig)
+	if err != nil {
 		return "", nil, fmt.Errorf("Failed parsing NIC device routes: %w", err)
 	}
 
@@ -4600,9 +5088,21 @@ func (n *ovn) InstanceDevicePortStart(opts *OVNInstanceNICSetupOpts, securityACL
 			return "", nil, fmt.Errorf("Could not find DHCPv4 options for instance port for subnet %q", dhcpv4Subnet.String())
 		}
 
+		// Hand out the BIOS boot file to the instances booting with BIOS firmware.
+		if opts.BIOS {
+			dhcpV4BIOSUUID, err := n.getDhcpBIOSOptionUUID()
+			if err != nil {
+				return "", nil, err
+			}
+
+			if dhcpV4BIOSUUID != "" {
+				dhcpV4UUID = dhcpV4BIOSUUID
+			}
+		}
+
 		// If using dynamic IPv4, look for previously used sticky IPs from the NIC's last state.
 		var dhcpV4StickyIP net.IP
-		if opts.DeviceConfig["ipv4.address"] == "" {
+		if ipv4 == "" {
 			for _, entry := range opts.LastStateIPs {
 				if entry.To4() != nil && SubnetContainsIP(dhcpv4Subnet, entry) {
 					dhcpV4StickyIP = entry
@@ -4870,6 +5370,16 @@ func (n *ovn) InstanceDevicePortStart(opts *OVNInstanceNICSetupOpts, securityACL
 		}
 	}
 
+	// Route the delegated IPv6 prefix to the instance port's IPv6 address.
+	if n.config["ipv6.dhcp.pd.prefix"] != "" && dnsIPv6 != nil {
+		prefix, err := n.instanceDevicePortDelegatePrefix(mac, dnsIPv6)
+		if err != nil {
+			return "", nil, fmt.Errorf("Failed delegating IPv6 prefix: %w", err)
+		}
+
+		internalRoutes = append(internalRoutes, prefix)
+	}
+
 	var routes []networkOVN.OVNRouterRoute
 
 	// In l3only mode we add the instance port's IPs as static routes to the router.
@@ -5233,6 +5743,7 @@ func (n *ovn) InstanceDevicePortStart(opts *OVNInstanceNICSetupOpts, securityACL
 	}
 
 	reverter.Success()
+
 	return instancePortName, dnsIPs, nil
 }
 
@@ -5273,6 +5784,56 @@ func (n *ovn) InstanceDevicePortIPs(instanceUUID string, deviceName string) ([]n
 	return devIPs, nil
 }
 
+// InstanceDevicePortMirrors applies the traffic mirroring settings of an instance device to its logical switch port.
+func (n *ovn) InstanceDevicePortMirrors(instanceUUID string, deviceName string, deviceConfig deviceConfig.Device) error {
+	if instanceUUID == "" {
+		return errors.New("Instance UUID is required")
+	}
+
+	instancePortName := n.getInstanceDevicePortName(instanceUUID, deviceName)
+
+	mirrors := []networkOVN.OVNMirror{}
+	if deviceConfig["mirror.remote"] != "" {
+		sink := net.ParseIP(deviceConfig["mirror.remote"])
+		if sink == nil {
+			return fmt.Errorf("Invalid mirror remote %q", deviceConfig["mirror.remote"])
+		}
+
+		mirrorType := deviceConfig["mirror.type"]
+		if mirrorType == "" {
+			mirrorType = "gre"
+		}
+
+		var mirrorIndex int
+		if deviceConfig["mirror.index"] != "" {
+			index, err := strconv.ParseUint(deviceConfig["mirror.index"], 10, 32)
+			if err != nil {
+				return fmt.Errorf("Invalid mirror index %q: %w", deviceConfig["mirror.index"], err)
+			}
+
+			mirrorIndex = int(index)
+		}
+
+		// OVN filters are from the point of view of the logical switch port, so the instance's egress
+		// traffic is coming from the port and its ingress traffic is going to it.
+		direction := deviceConfig["mirror.direction"]
+		if direction != "ingress" {
+			mirrors = append(mirrors, networkOVN.OVNMirror{Filter: "from-lport", Type: mirrorType, Sink: sink, Index: mirrorIndex})
+		}
+
+		if direction != "egress" {
+			mirrors = append(mirrors, networkOVN.OVNMirror{Filter: "to-lport", Type: mirrorType, Sink: sink, Index: mirrorIndex})
+		}
+	}
+
+	err := n.ovnnb.UpdateLogicalSwitchPortMirrors(context.TODO(), instancePortName, mirrors...)
+	if err != nil {
+		return fmt.Errorf("Failed setting up OVN port mirrors: %w", err)
+	}
+
+	return nil
+}
+
 // InstanceDevicePortStop deletes an instance device port from the internal logical switch.
 func (n *ovn) InstanceDevicePortStop(ovsExternalOVNPort networkOVN.OVNSwitchPort, opts *OVNInstanceNICStopOpts) error {
 	// Decide whether to use OVS provided OVN port name or internally derived OVN port name.
@@ -5324,6 +5885,14 @@ func (n *ovn) InstanceDevicePortStop(ovsExternalOVNPort networkOVN.OVNSwitchPort
 		return err
 	}
 
+	// Remove the port mirrors (only touching them if configured, as older OVN versions lack them).
+	if opts.DeviceConfig["mirror.remote"] != "" {
+		err = n.ovnnb.UpdateLogicalSwitchPortMirrors(context.TODO(), instancePortName)
+		if err != nil {
+			return fmt.Errorf("Failed removing OVN port mirrors: %w", err)
+		}
+	}
+
 	// Cleanup logical switch port and associated config.
 	err = n.ovnnb.CleanupLogicalSwitchPort(context.TODO(), instancePortName, n.getIntSwitchName(), acl.OVNIntSwitchPortGroupName(n.ID()), dnsUUID)
 	if err != nil {
@@ -5344,6 +5913,30 @@ func (n *ovn) InstanceDevicePortStop(ovsExternalOVNPort networkOVN.OVNSwitchPort
 		removeNATIPs = append(removeNATIPs, dnsIPs...)
 	}
 
+	// Delete the route of the delegated IPv6 prefix.
+	mac, err := net.ParseMAC(opts.DeviceConfig["hwaddr"])
+	if err == nil {
+		delegation, err := n.instanceDevicePortDelegation(mac)
+		if err != nil {
+			return err
+		}
+
+		if delegation != nil {
+			_, prefix, err := net.ParseCIDR(delegation.Prefix)
+			if err == nil {
+				internalRoutes = append(internalRoutes, prefix)
+			}
+
+			// Forget about the delegation if prefix delegation got disabled.
+			if n.config["ipv6.dhcp.pd.prefix"] == "" {
+				err = n.instanceDevicePortReleasePrefix(mac)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
 	// Delete internal routes.
 	if len(internalRoutes) > 0 {
 		for _, internalRoute := range internalRoutes {
@@ -5480,10 +6073,77 @@ func (n *ovn) InstanceDevicePortRemove(instanceUUID string, devName string, devC
 		}
 	}
 
+	// Release the delegated IPv6 prefix.
+	mac, err := net.ParseMAC(devConfig["hwaddr"])
+	if err == nil && !hasDuplicate {
+		err = n.instanceDevicePortReleasePrefix(mac)
+		if err != nil {
+			return err
+		}
+	}
+
 	reverter.Success()
 	return nil
 }
 
+// instanceDevicePortDelegatePrefix returns the IPv6 prefix delegated to the NIC from the ipv6.dhcp.pd.prefix pool,
+// allocating it if needed, and records the NIC address it is routed to.
+// As the OVN DHCPv6 server doesn't support prefix delegation, the NIC is identified by its MAC address.
+func (n *ovn) instanceDevicePortDelegatePrefix(mac net.HardwareAddr, nextHop net.IP) (*net.IPNet, error) {
+	store, err := newPrefixDelegationStore(n.state, n.id, n.config)
+	if err != nil {
+		return nil, err
+	}
+
+	lease, err := store.Lease(dhcpv6pd.Lease{
+		DUID:    dhcpv6pd.ClientDUID(mac),
+		Hwaddr:  mac.String(),
+		NextHop: nextHop.String(),
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	_, prefix, err := net.ParseCIDR(lease.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return prefix, nil
+}
+
+// instanceDevicePortDelegation returns the IPv6 prefix delegation of the NIC (if any).
+func (n *ovn) instanceDevicePortDelegation(mac net.HardwareAddr) (*db.NetworkPrefixDelegation, error) {
+	duid := dhcpv6pd.ClientDUID(mac)
+
+	var delegations []db.NetworkPrefixDelegation
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		delegations, err = tx.GetNetworkPrefixDelegations(ctx, n.id, false)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading delegated prefixes: %w", err)
+	}
+
+	for _, delegation := range delegations {
+		if delegation.DUID == duid {
+			return &delegation, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// instanceDevicePortReleasePrefix removes the IPv6 prefix delegation of the NIC.
+func (n *ovn) instanceDevicePortReleasePrefix(mac net.HardwareAddr) error {
+	return n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteNetworkPrefixDelegation(ctx, n.id, dhcpv6pd.ClientDUID(mac), 0)
+	})
+}
+
 // DHCPv4Subnet returns the DHCPv4 subnet (if DHCP is enabled on network).
 func (n *ovn) DHCPv4Subnet() *net.IPNet {
 	// DHCP is disabled on this network (an empty ipv4.dhcp setting indicates enabled by default).
@@ -5720,7 +6380,7 @@ func (n *ovn) handleDependencyChange(uplinkName string, uplinkConfig map[string]
 		break // Only run setup once per notification (all changes will be applied).
 	}
 
-	watchedKeys := []string{"dns.nameservers", "ipv4.gateway", "ipv6.gateway", "ipv4.gateway.hwaddr", "ipv6.gateway.hwaddr"}
+	watchedKeys := []string{"dns.nameservers", "ipv4.gateway", "ipv6.gateway", "ipv4.gateway.hwaddr", "ipv6.gateway.hwaddr", "ipv4.gateway.bfd", "ipv6.gateway.bfd"}
 	for _, k := range append(watchedKeys, uplinkKeys...) {
 		if !slices.Contains(changedKeys, k) {
 			continue
@@ -6309,6 +6969,187 @@ func (n *ovn) loadBalancerFlattenVIPs(listenAddress net.IP, portMaps []*loadBala
 	return vips
 }
 
+// ReservationCreate creates a DHCP reservation.
+func (n *ovn) ReservationCreate(reservation api.NetworkReservationsPost, clientType request.ClientType) error {
+	if clientType != request.ClientTypeNormal {
+		return nil // OVN networks are configured centrally.
+	}
+
+	err := n.reservationValidateOVN(reservation.Name, &reservation.NetworkReservationPut)
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	err = n.reservationCreate(reservation)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _ = n.reservationDelete(reservation.Name) })
+
+	err = n.reservationSetupIPAllocation()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// ReservationUpdate updates a DHCP reservation.
+func (n *ovn) ReservationUpdate(name string, req api.NetworkReservationPut, clientType request.ClientType) error {
+	if clientType != request.ClientTypeNormal {
+		return nil // OVN networks are configured centrally.
+	}
+
+	curReservationID, curReservation, err := n.reservationGet(name)
+	if err != nil {
+		return err
+	}
+
+	err = n.reservationValidateOVN(name, &req)
+	if err != nil {
+		return err
+	}
+
+	curReservationEtagHash, err := localUtil.EtagHash(curReservation.Etag())
+	if err != nil {
+		return err
+	}
+
+	newReservation := api.NetworkReservation{
+		Name:                  curReservation.Name,
+		NetworkReservationPut: req,
+	}
+
+	newReservationEtagHash, err := localUtil.EtagHash(newReservation.Etag())
+	if err != nil {
+		return err
+	}
+
+	if curReservationEtagHash == newReservationEtagHash {
+		return nil // Nothing has changed.
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	err = n.reservationUpdate(curReservationID, name, req)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _ = n.reservationUpdate(curReservationID, name, curReservation.Writable()) })
+
+	err = n.reservationSetupIPAllocation()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// ReservationDelete deletes a DHCP reservation.
+func (n *ovn) ReservationDelete(name string, clientType request.ClientType) error {
+	if clientType != request.ClientTypeNormal {
+		return nil // OVN networks are configured centrally.
+	}
+
+	_, reservation, err := n.reservationGet(name)
+	if err != nil {
+		return err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	err = n.reservationDelete(name)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() {
+		_ = n.reservationCreate(api.NetworkReservationsPost{
+			Name:                  reservation.Name,
+			NetworkReservationPut: reservation.Writable(),
+		})
+	})
+
+	err = n.reservationSetupIPAllocation()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// reservationValidateOVN validates a DHCP reservation, also checking it doesn't use a DUID, as OVN only matches
+// clients by MAC address, nor the address reserved for the OVN router.
+func (n *ovn) reservationValidateOVN(name string, reservation *api.NetworkReservationPut) error {
+	if reservation.DUID != "" {
+		return errors.New("DUID based DHCP reservations aren't supported on OVN networks")
+	}
+
+	err := n.reservationValidate(name, reservation, n.DHCPv4Subnet(), n.DHCPv6Subnet())
+	if err != nil {
+		return err
+	}
+
+	return n.reservationValidateRouterAddress(reservation)
+}
+
+// reservationValidateRouterAddress checks a DHCP reservation doesn't use the address reserved for the OVN router.
+func (n *ovn) reservationValidateRouterAddress(reservation *api.NetworkReservationPut) error {
+	dhcpv4Subnet := n.DHCPv4Subnet()
+	if reservation.IPv4Address != "" && dhcpv4Subnet != nil && dhcpalloc.GetIP(dhcpv4Subnet, -2).Equal(net.ParseIP(reservation.IPv4Address)) {
+		return fmt.Errorf("Address %q is reserved for the OVN router", reservation.IPv4Address)
+	}
+
+	return nil
+}
+
+// reservationSetupIPAllocation refreshes the addresses excluded from dynamic allocation on the internal switch.
+func (n *ovn) reservationSetupIPAllocation() error {
+	// Nothing to do until the network has been created.
+	if n.status != api.NetworkStatusCreated {
+		return nil
+	}
+
+	_, routerIntPortIPv4Net, err := n.parseRouterIntPortIPv4Net()
+	if err != nil {
+		return err
+	}
+
+	_, routerIntPortIPv6Net, err := n.parseRouterIntPortIPv6Net()
+	if err != nil {
+		return err
+	}
+
+	dhcpReserveIPv4s, err := n.getDHCPv4Reservations()
+	if err != nil {
+		return fmt.Errorf("Failed getting DHCPv4 IP reservations: %w", err)
+	}
+
+	err = n.ovnnb.UpdateLogicalSwitchIPAllocation(context.TODO(), n.getIntSwitchName(), &networkOVN.OVNIPAllocationOpts{
+		PrefixIPv4:  routerIntPortIPv4Net,
+		PrefixIPv6:  routerIntPortIPv6Net,
+		ExcludeIPv4: dhcpReserveIPv4s,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed setting IP allocation settings on internal switch: %w", err)
+	}
+
+	return nil
+}
+
 // LoadBalancerCreate creates a network load balancer.
 func (n *ovn) LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) error {
 	if n.config["network"] == "none" {
@@ -6880,6 +7721,26 @@ func (n *ovn) Leases(projectName string, clientType request.ClientType) ([]api.N
 		}
 	}
 
+	// Get the delegated IPv6 prefixes, indexed by MAC address.
+	pdLeases := map[string]db.NetworkPrefixDelegation{}
+	if n.config["ipv6.dhcp.pd.prefix"] != "" {
+		err = n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			delegations, err := tx.GetNetworkPrefixDelegations(ctx, n.id, false)
+			if err != nil {
+				return err
+			}
+
+			for _, delegation := range delegations {
+				pdLeases[delegation.Hwaddr] = delegation
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Get all the instances in the requested project that are connected to this network.
 	filter := dbCluster.InstanceFilter{Project: &projectName}
 	err = UsedByInstanceDevices(n.state, n.Project(), n.Name(), n.Type(), func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
@@ -6918,6 +7779,18 @@ func (n *ovn) Leases(projectName string, clientType request.ClientType) ([]api.N
 			})
 		}
 
+		// Add the delegated prefix.
+		pdLease, found := pdLeases[hwAddr.String()]
+		if found {
+			leases = append(leases, api.NetworkLease{
+				Hostname: inst.Name,
+				Address:  pdLease.Prefix,
+				Hwaddr:   pdLease.Hwaddr,
+				Type:     "delegated",
+				Location: pdLease.Location,
+			})
+		}
+
 		return nil
 	}, filter)
 	if err != nil {
@@ -6927,6 +7800,218 @@ func (n *ovn) Leases(projectName string, clientType request.ClientType) ([]api.N
 	return leases, nil
 }
 
+// ovnTraceFlow matches the logical flows in the detailed output of ovn-trace.
+var ovnTraceFlow = regexp.MustCompile(`^\s*\d+\.\s+(\S+)\s+\([^)]*\):\s*(.*), priority (\d+), uuid (\S+)$`)
+
+// Trace simulates the path of a packet through the logical switch and router of the network using ovn-trace.
+// Packets from addresses outside of the network enter through the uplink port of the logical router.
+func (n *ovn) Trace(req api.NetworkTracePost) (*api.NetworkTrace, error) {
+	source, destination, err := n.traceValidate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	portIPs, err := n.ovnnb.GetLogicalSwitchIPs(ctx, n.getIntSwitchName())
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting logical switch port addresses: %w", err)
+	}
+
+	findPort := func(address net.IP) networkOVN.OVNSwitchPort {
+		for portName, ips := range portIPs {
+			for _, ip := range ips {
+				if ip.Equal(address) {
+					return portName
+				}
+			}
+		}
+
+		return ""
+	}
+
+	routerMAC, err := n.getRouterMAC()
+	if err != nil {
+		return nil, err
+	}
+
+	ethDst := routerMAC.String()
+
+	dstPort := findPort(destination)
+	if dstPort != "" {
+		ethDst, err = n.ovnnb.GetLogicalSwitchPortHardwareAddress(ctx, dstPort)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting MAC address of logical switch port %q: %w", dstPort, err)
+		}
+	}
+
+	var datapath string
+	var match []string
+
+	srcPort := findPort(source)
+	if srcPort != "" {
+		ethSrc, err := n.ovnnb.GetLogicalSwitchPortHardwareAddress(ctx, srcPort)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting MAC address of logical switch port %q: %w", srcPort, err)
+		}
+
+		datapath = string(n.getIntSwitchName())
+		match = append(match, fmt.Sprintf("inport == %q", srcPort), fmt.Sprintf("eth.src == %s", ethSrc))
+	} else {
+		if n.config["network"] == "none" {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Source address isn't part of network %q which has no uplink", n.name)
+		}
+
+		// Use a locally administered address for the uplink side.
+		datapath = string(n.getRouterName())
+		match = append(match, fmt.Sprintf("inport == %q", n.getRouterExtPortName()), "eth.src == 02:00:00:00:00:01")
+	}
+
+	ipFamily := "ip4"
+	if source.To4() == nil {
+		ipFamily = "ip6"
+	}
+
+	match = append(match,
+		fmt.Sprintf("eth.dst == %s", ethDst),
+		fmt.Sprintf("%s.src == %s", ipFamily, source.String()),
+		fmt.Sprintf("%s.dst == %s", ipFamily, destination.String()),
+		"ip.ttl == 64",
+		req.Protocol,
+	)
+
+	switch req.Protocol {
+	case "tcp", "udp":
+		if req.SourcePort != 0 {
+			match = append(match, fmt.Sprintf("%s.src == %d", req.Protocol, req.SourcePort))
+		}
+
+		match = append(match, fmt.Sprintf("%s.dst == %d", req.Protocol, req.DestinationPort))
+	default:
+		icmpType := req.ICMPType
+		if icmpType == "" {
+			// Default to an echo request.
+			icmpType = "8"
+			if req.Protocol == "icmp6" {
+				icmpType = "128"
+			}
+		}
+
+		match = append(match, fmt.Sprintf("%s.type == %s", req.Protocol, icmpType))
+
+		if req.ICMPCode != "" {
+			match = append(match, fmt.Sprintf("%s.code == %s", req.Protocol, req.ICMPCode))
+		}
+	}
+
+	output, err := n.ovnsb.Trace(ctx, datapath, strings.Join(match, " && "))
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &api.NetworkTrace{
+		Steps:  []api.NetworkTraceStep{},
+		Output: output,
+	}
+
+	// Extract the steps from the ACL, load balancer and NAT logical flows.
+	var step *api.NetworkTraceStep
+	var actions []string
+
+	addStep := func() {
+		if step == nil {
+			return
+		}
+
+		step.Description = strings.Join(actions, " ")
+		for _, action := range actions {
+			switch {
+			case strings.HasPrefix(action, "drop;"):
+				step.Verdict = "drop"
+			case strings.HasPrefix(action, "reject"):
+				step.Verdict = "reject"
+			}
+		}
+
+		if step.Stage == "acl" && step.Verdict == "" {
+			step.Verdict = "allow"
+		}
+
+		trace.Steps = append(trace.Steps, *step)
+		step = nil
+		actions = nil
+	}
+
+	outputSeen := false
+	for _, line := range strings.Split(output, "\n") {
+		flow := ovnTraceFlow.FindStringSubmatch(line)
+		if flow != nil {
+			addStep()
+
+			var stage string
+			switch {
+			case strings.Contains(flow[1], "_acl") && !strings.Contains(flow[1], "_pre_acl") && !strings.Contains(flow[1], "_acl_hint"):
+				stage = "acl"
+			case strings.Contains(flow[1], "_lb") && !strings.Contains(flow[1], "_pre_lb"):
+				stage = "load-balancer"
+			case strings.Contains(flow[1], "_dnat"):
+				stage = "forward"
+			}
+
+			if stage != "" {
+				step = &api.NetworkTraceStep{
+					Stage: stage,
+					Rule:  fmt.Sprintf("%s: %s (priority %s)", flow[1], flow[2], flow[3]),
+				}
+			}
+
+			continue
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			addStep()
+			continue
+		}
+
+		if step != nil {
+			actions = append(actions, line)
+		}
+
+		if strings.HasPrefix(line, "/* output to") {
+			outputSeen = true
+		}
+	}
+
+	addStep()
+
+	// Only keep the steps which had an effect on the packet.
+	steps := make([]api.NetworkTraceStep, 0, len(trace.Steps))
+	for _, step := range trace.Steps {
+		if step.Stage != "acl" && step.Description == "next;" {
+			continue
+		}
+
+		steps = append(steps, step)
+	}
+
+	trace.Steps = steps
+
+	trace.Verdict = "drop"
+	if outputSeen {
+		trace.Verdict = "allow"
+	}
+
+	for _, step := range trace.Steps {
+		if step.Verdict == "drop" || step.Verdict == "reject" {
+			trace.Verdict = step.Verdict
+		}
+	}
+
+	return trace, nil
+}
+
 // localPeerCreate creates a network peering with another local network.
 func (n *ovn) localPeerCreate(peer api.NetworkPeersPost) error {
 	ctx := context.TODO()
@@ -8337,3 +9422,28 @@ func (n *ovn) getActiveChassisName() (string, error) {
 
 	return chassis, nil
 }
+
+// getGatewayChassis returns the priority of each chassis in the network's chassis group, keyed by hostname.
+func (n *ovn) getGatewayChassis() (map[string]int, error) {
+	priorities, err := n.ovnnb.GetChassisGroupPriorities(context.TODO(), n.getChassisGroupName())
+	if err != nil {
+		return nil, err
+	}
+
+	hostnames, err := n.ovnsb.GetChassisHostnames(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	gatewayChassis := make(map[string]int, len(priorities))
+	for chassisID, priority := range priorities {
+		name := hostnames[chassisID]
+		if name == "" {
+			name = chassisID
+		}
+
+		gatewayChassis[name] = priority
+	}
+
+	return gatewayChassis, nil
+}
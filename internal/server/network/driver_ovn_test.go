@@ -0,0 +1,146 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func Test_ovnChassisPriority(t *testing.T) {
+	memberIDs := []int{3, 1, 2, 5}
+
+	priority := func(seed string, memberID int, gatewayEntry int, gatewayEntries int) int {
+		r, err := localUtil.GetStableRandomGenerator(seed)
+		require.NoError(t, err)
+
+		return ovnChassisPriority(r, memberIDs, memberID, gatewayEntry, gatewayEntries)
+	}
+
+	for _, seed := range []string{"incus-net1", "incus-net2", "incus-net3"} {
+		for _, memberID := range memberIDs {
+			// Without gateway chassis list, the whole priority range is used.
+			p := priority(seed, memberID, -1, 0)
+			assert.GreaterOrEqual(t, p, 0)
+			assert.LessOrEqual(t, p, ovnChassisPriorityMax)
+
+			// The priority is stable.
+			assert.Equal(t, p, priority(seed, memberID, -1, 0))
+
+			// Each gateway chassis entry gets its own range, from highest to lowest priority.
+			entrySize := (ovnChassisPriorityMax + 1) / 3
+			for gatewayEntry := range 3 {
+				p := priority(seed, memberID, gatewayEntry, 3)
+				assert.LessOrEqual(t, p, ovnChassisPriorityMax-(gatewayEntry*entrySize))
+				assert.Greater(t, p, ovnChassisPriorityMax-((gatewayEntry+1)*entrySize))
+			}
+
+			assert.Greater(t, priority(seed, memberID, 0, 3), priority(seed, memberID, 1, 3))
+			assert.Greater(t, priority(seed, memberID, 1, 3), priority(seed, memberID, 2, 3))
+
+			// A single entry uses the whole priority range.
+			p = priority(seed, memberID, 0, 1)
+			assert.GreaterOrEqual(t, p, 0)
+			assert.LessOrEqual(t, p, ovnChassisPriorityMax)
+
+			// The priority never goes below zero with more entries than priorities.
+			assert.Equal(t, 0, priority(seed, memberID, ovnChassisPriorityMax+10, ovnChassisPriorityMax+20))
+		}
+	}
+
+	// The member order doesn't matter.
+	r, err := localUtil.GetStableRandomGenerator("incus-net1")
+	require.NoError(t, err)
+
+	assert.Equal(t, priority("incus-net1", 5, 1, 2), ovnChassisPriority(r, []int{5, 2, 1, 3}, 5, 1, 2))
+}
+
+func Test_validateGatewayECMP(t *testing.T) {
+	physical := &api.Network{
+		Name: "uplink",
+		Type: "physical",
+		NetworkPut: api.NetworkPut{
+			Config: map[string]string{
+				"ipv4.gateway": "192.0.2.1/24",
+				"ipv6.gateway": "2001:db8::1/64",
+			},
+		},
+	}
+
+	bridge := &api.Network{
+		Name: "incusbr0",
+		Type: "bridge",
+		NetworkPut: api.NetworkPut{
+			Config: map[string]string{
+				"ipv4.address": "198.51.100.1/24",
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		uplink  *api.Network
+		value   string
+		wantErr string
+	}{
+		{
+			name:   "empty",
+			uplink: physical,
+			value:  "",
+		},
+		{
+			name:   "valid",
+			uplink: physical,
+			value:  "192.0.2.2, 192.0.2.3,2001:db8::2",
+		},
+		{
+			name:   "bridge address",
+			uplink: bridge,
+			value:  "198.51.100.2",
+		},
+		{
+			name:    "invalid",
+			uplink:  physical,
+			value:   "192.0.2",
+			wantErr: `Invalid next hop "192.0.2"`,
+		},
+		{
+			name:    "outside the uplink subnet",
+			uplink:  physical,
+			value:   "198.51.100.2",
+			wantErr: `Next hop "198.51.100.2" isn't within the uplink subnet "192.0.2.0/24"`,
+		},
+		{
+			name:    "uplink gateway",
+			uplink:  physical,
+			value:   "192.0.2.1",
+			wantErr: `Next hop "192.0.2.1" is already the uplink gateway`,
+		},
+		{
+			name:    "duplicate",
+			uplink:  physical,
+			value:   "2001:db8::2,2001:db8:0::2",
+			wantErr: `Duplicate next hop "2001:db8:0::2"`,
+		},
+		{
+			name:    "missing uplink family",
+			uplink:  bridge,
+			value:   "2001:db8::2",
+			wantErr: `Uplink network "incusbr0" has no gateway subnet for next hop "2001:db8::2"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateGatewayECMP(tt.uplink, tt.value)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
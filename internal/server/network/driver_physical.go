@@ -6,13 +6,19 @@ import (
 	"fmt"
 	"maps"
 	"net"
+	"net/http"
 	"slices"
 	"strconv"
 
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/cluster/request"
 	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/internal/server/locking"
 	"github.com/lxc/incus/v6/internal/server/network/ovs"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
@@ -25,6 +31,14 @@ type physical struct {
 	common
 }
 
+// Info returns the network driver info.
+func (n *physical) Info() Info {
+	info := n.common.Info()
+	info.FloatingIPs = true
+
+	return info
+}
+
 // DBType returns the network type DB ID.
 func (n *physical) DBType() db.NetworkType {
 	return db.NetworkTypePhysical
@@ -118,6 +132,24 @@ func (n *physical) Validate(config map[string]string, clientType request.ClientT
 		//  shortdesc: MAC address of the gateway (to avoid discovery)
 		"ipv6.gateway.hwaddr": validate.Optional(validate.IsNetworkMAC),
 
+		// gendoc:generate(entity=network_physical, group=ipv4, key=ipv4.gateway.bfd)
+		//
+		// ---
+		//  type: bool
+		//  condition: OVN uplink
+		//  defaultdesc: `false`
+		//  shortdesc: Whether OVN should use BFD to monitor the gateway
+		"ipv4.gateway.bfd": validate.Optional(validate.IsBool),
+
+		// gendoc:generate(entity=network_physical, group=ipv6, key=ipv6.gateway.bfd)
+		//
+		// ---
+		//  type: bool
+		//  condition: OVN uplink
+		//  defaultdesc: `false`
+		//  shortdesc: Whether OVN should use BFD to monitor the gateway
+		"ipv6.gateway.bfd": validate.Optional(validate.IsBool),
+
 		// gendoc:generate(entity=network_physical, group=ipv4, key=ipv4.ovn.ranges)
 		//
 		// ---
@@ -220,6 +252,14 @@ func (n *physical) Validate(config map[string]string, clientType request.ClientT
 	// defaultdesc: `180`
 	// shortdesc: Peer session hold time (in seconds; optional)
 
+	// gendoc:generate(entity=network_physical, group=bgp, key=bgp.peers.NAME.bfd)
+	//
+	// ---
+	// type: bool
+	// condition: BGP server
+	// defaultdesc: `false`
+	// shortdesc: Whether to use BFD to detect the peer going down
+
 	// Add the BGP validation rules.
 	bgpRules, err := n.bgpValidationRules(config)
 	if err != nil {
@@ -398,6 +438,12 @@ func (n *physical) setup(oldConfig map[string]string) error {
 		return err
 	}
 
+	// Advertise the floating IPs of the instances running on this member.
+	err = n.floatingIPSetup()
+	if err != nil {
+		return err
+	}
+
 	reverter.Success()
 	return nil
 }
@@ -412,6 +458,18 @@ func (n *physical) Stop() error {
 		return err
 	}
 
+	// Withdraw the floating IPs.
+	floatingIPs, err := n.floatingIPs()
+	if err != nil {
+		return err
+	}
+
+	for _, floatingIP := range floatingIPs {
+		n.floatingIPWithdraw(floatingIP.Address)
+	}
+
+	floatingIPTargetsDelete(n.id)
+
 	hostName := GetHostDevice(n.config["parent"], n.config["vlan"])
 
 	// Only try and remove created VLAN interfaces.
@@ -575,3 +633,374 @@ func (n *physical) DHCPv6Subnet() *net.IPNet {
 
 	return subnet
 }
+
+// floatingIPValidate validates a floating IP of the physical network.
+func (n *physical) floatingIPValidate(projectName string, address string, req *api.NetworkFloatingIPPut) error {
+	floatingIPAddress := net.ParseIP(address)
+	if floatingIPAddress == nil || !floatingIPAddress.IsGlobalUnicast() {
+		return fmt.Errorf("Invalid floating IP address %q", address)
+	}
+
+	ipVersion := 4
+	if floatingIPAddress.To4() == nil {
+		ipVersion = 6
+	}
+
+	// The floating IP is routed to the instance, so it must be external to the network.
+	_, gatewaySubnet, _ := net.ParseCIDR(n.config[fmt.Sprintf("ipv%d.gateway", ipVersion)])
+	if gatewaySubnet != nil && gatewaySubnet.Contains(floatingIPAddress) {
+		return fmt.Errorf("Floating IP address %q is within the network's subnet %q", address, gatewaySubnet.String())
+	}
+
+	err := floatingIPValidateConfig(req.Config)
+	if err != nil {
+		return err
+	}
+
+	// Physical networks have no host side data path, so the floating IP can only be routed to the
+	// instance's own address through the BGP server.
+	if req.Config["announce.interface"] != "" {
+		return errors.New("The \"announce.interface\" option isn't supported on physical networks")
+	}
+
+	if util.IsFalseOrEmpty(req.Config["announce.bgp"]) {
+		return errors.New("Floating IPs on physical networks require \"announce.bgp\" to be enabled")
+	}
+
+	targetAddress := net.ParseIP(req.Config["target.address"])
+	if targetAddress == nil {
+		return errors.New("Floating IPs on physical networks require \"target.address\" to be set")
+	}
+
+	if (targetAddress.To4() == nil) != (ipVersion == 6) {
+		return fmt.Errorf("Target address %q and floating IP address %q must be of the same IP family", targetAddress.String(), address)
+	}
+
+	if gatewaySubnet != nil && !gatewaySubnet.Contains(targetAddress) {
+		return fmt.Errorf("Target address %q isn't within the network's subnet %q", targetAddress.String(), gatewaySubnet.String())
+	}
+
+	// Check the instance exists and is connected to the network.
+	if req.Instance != "" {
+		found := false
+
+		err := UsedByInstanceDevices(n.state, n.project, n.name, n.netType, func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
+			found = true
+
+			return nil
+		}, dbCluster.InstanceFilter{Project: &projectName, Name: &req.Instance})
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			return api.StatusErrorf(http.StatusBadRequest, "Instance %q in project %q isn't connected to the network", req.Instance, projectName)
+		}
+	}
+
+	return nil
+}
+
+// floatingIPTargets returns the next hop of each floating IP of the network whose instance is running on this member.
+func (n *physical) floatingIPTargets(floatingIPs []api.NetworkFloatingIP, filters ...dbCluster.InstanceFilter) (map[string]net.IP, error) {
+	targets := make(map[string]net.IP)
+
+	if len(floatingIPs) == 0 {
+		return targets, nil
+	}
+
+	err := UsedByInstanceDevices(n.state, n.project, n.name, n.netType, func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
+		if inst.Node != n.state.ServerName {
+			return nil
+		}
+
+		// The parent interface is moved into the instance, so only rely on the NIC being marked as started.
+		if inst.Config[fmt.Sprintf("volatile.%s.host_name", nicName)] == "" {
+			return nil
+		}
+
+		for _, floatingIP := range floatingIPs {
+			if floatingIP.Project != inst.Project || floatingIP.Instance != inst.Name {
+				continue
+			}
+
+			targetAddress := net.ParseIP(floatingIP.Config["target.address"])
+			if targetAddress != nil {
+				targets[floatingIP.Address] = targetAddress
+			}
+		}
+
+		return nil
+	}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// floatingIPWithdraw stops advertising a floating IP from this member.
+func (n *physical) floatingIPWithdraw(address string) {
+	_ = n.state.BGP.RemovePrefixByOwner(floatingIPBGPOwner(n.id, address))
+}
+
+// floatingIPAnnounce advertises the floating IPs whose instance runs on this member through the BGP server and
+// withdraws the others.
+func (n *physical) floatingIPAnnounce(floatingIPs []api.NetworkFloatingIP, targets map[string]net.IP) error {
+	for _, floatingIP := range floatingIPs {
+		n.floatingIPWithdraw(floatingIP.Address)
+
+		if targets[floatingIP.Address] == nil || util.IsFalseOrEmpty(floatingIP.Config["announce.bgp"]) {
+			continue
+		}
+
+		floatingIPNet, err := ParseIPToNet(floatingIP.Address)
+		if err != nil {
+			return err
+		}
+
+		err = n.state.BGP.AddPrefix(*floatingIPNet, targets[floatingIP.Address], floatingIPBGPOwner(n.id, floatingIP.Address))
+		if err != nil {
+			return fmt.Errorf("Failed advertising floating IP %q: %w", floatingIP.Address, err)
+		}
+	}
+
+	return nil
+}
+
+// floatingIPSetup applies the announcements for all the floating IPs of the network.
+func (n *physical) floatingIPSetup() error {
+	unlock, err := locking.Lock(context.TODO(), floatingIPLockName(n.id))
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	floatingIPs, err := n.floatingIPs()
+	if err != nil {
+		return fmt.Errorf("Failed loading floating IPs: %w", err)
+	}
+
+	targets, err := n.floatingIPTargets(floatingIPs)
+	if err != nil {
+		return fmt.Errorf("Failed getting floating IP targets: %w", err)
+	}
+
+	floatingIPTargetsSet(n.id, targets)
+
+	err = n.floatingIPAnnounce(floatingIPs, targets)
+	if err != nil {
+		return fmt.Errorf("Failed announcing floating IPs: %w", err)
+	}
+
+	return nil
+}
+
+// FloatingIPRefresh re-applies the floating IPs of the network targeting the instance on this member.
+// It is called whenever an instance NIC connected to the network starts or stops, so that the floating IPs
+// follow their instance as it moves between cluster members.
+func (n *physical) FloatingIPRefresh(projectName string, instanceName string) error {
+	floatingIPs, err := n.instanceFloatingIPs(projectName, instanceName)
+	if err != nil {
+		return fmt.Errorf("Failed loading floating IPs: %w", err)
+	}
+
+	if len(floatingIPs) == 0 {
+		return nil
+	}
+
+	// Apply all the floating IPs if none were applied yet.
+	_, found := floatingIPTargetsGet(n.id)
+	if !found {
+		return n.floatingIPSetup()
+	}
+
+	unlock, err := locking.Lock(context.TODO(), floatingIPLockName(n.id))
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	targets, err := n.floatingIPTargets(floatingIPs, dbCluster.InstanceFilter{Project: &projectName, Name: &instanceName})
+	if err != nil {
+		return fmt.Errorf("Failed getting floating IP targets: %w", err)
+	}
+
+	changed := floatingIPTargetsUpdate(n.id, floatingIPs, targets)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	err = n.floatingIPAnnounce(changed, targets)
+	if err != nil {
+		return fmt.Errorf("Failed announcing floating IPs: %w", err)
+	}
+
+	return nil
+}
+
+// FloatingIPCreate creates a floating IP.
+func (n *physical) FloatingIPCreate(projectName string, floatingIP api.NetworkFloatingIPsPost, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		err := n.floatingIPValidate(projectName, floatingIP.Address, &floatingIP.NetworkFloatingIPPut)
+		if err != nil {
+			return err
+		}
+
+		err = n.floatingIPCreate(projectName, floatingIP)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { _ = n.floatingIPDelete(floatingIP.Address) })
+
+		// Notify all other members so that whichever hosts the instance advertises the address.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(projectName).CreateNetworkFloatingIP(n.name, floatingIP)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Apply the floating IPs on local member.
+	err := n.floatingIPSetup()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// FloatingIPUpdate updates a floating IP.
+func (n *physical) FloatingIPUpdate(address string, req api.NetworkFloatingIPPut, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		curFloatingIPID, curFloatingIP, err := n.floatingIPGet(address)
+		if err != nil {
+			return err
+		}
+
+		err = n.floatingIPValidate(curFloatingIP.Project, address, &req)
+		if err != nil {
+			return err
+		}
+
+		curFloatingIPEtagHash, err := localUtil.EtagHash(curFloatingIP.Etag())
+		if err != nil {
+			return err
+		}
+
+		newFloatingIP := api.NetworkFloatingIP{
+			Address:              curFloatingIP.Address,
+			NetworkFloatingIPPut: req,
+		}
+
+		newFloatingIPEtagHash, err := localUtil.EtagHash(newFloatingIP.Etag())
+		if err != nil {
+			return err
+		}
+
+		if curFloatingIPEtagHash == newFloatingIPEtagHash {
+			return nil // Nothing has changed.
+		}
+
+		err = n.floatingIPUpdate(curFloatingIPID, curFloatingIP.Project, address, req)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() {
+			_ = n.floatingIPUpdate(curFloatingIPID, curFloatingIP.Project, address, curFloatingIP.Writable())
+			_ = n.floatingIPSetup()
+		})
+
+		// Notify all other members so that the advertisements move with the change.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(curFloatingIP.Project).UpdateNetworkFloatingIP(n.name, address, req, "")
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Apply the floating IPs on local member.
+	err := n.floatingIPSetup()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// FloatingIPDelete deletes a floating IP.
+func (n *physical) FloatingIPDelete(address string, clientType request.ClientType) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if clientType == request.ClientTypeNormal {
+		_, floatingIP, err := n.floatingIPGet(address)
+		if err != nil {
+			return err
+		}
+
+		err = n.floatingIPDelete(address)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() {
+			_ = n.floatingIPCreate(floatingIP.Project, api.NetworkFloatingIPsPost{
+				Address:              floatingIP.Address,
+				NetworkFloatingIPPut: floatingIP.Writable(),
+			})
+			_ = n.floatingIPSetup()
+		})
+
+		// Notify all other members so that the address is withdrawn everywhere.
+		notifier, err := cluster.NewNotifier(n.state, n.state.Endpoints.NetworkCert(), n.state.ServerCert(), cluster.NotifyAll)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.UseProject(floatingIP.Project).DeleteNetworkFloatingIP(n.name, address)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	n.floatingIPWithdraw(address)
+
+	// Apply the remaining floating IPs on local member.
+	err := n.floatingIPSetup()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package httpboot
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+type server struct {
+	root *os.Root
+	srv  *http.Server
+}
+
+var servers = map[string]*server{}
+var serversMu sync.Mutex
+
+// Handler returns the HTTP handler serving the boot files from root.
+// The files are looked up within root, so symbolic links can't be used to serve other files of the host.
+func Handler(root *os.Root) http.Handler {
+	fileServer := http.FileServerFS(root.FS())
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		fileServer.ServeHTTP(w, r)
+	})
+}
+
+// Start serves the boot files of the network from the directory over HTTP on the address.
+// Any server already running for the network is stopped first.
+func Start(network string, address string, dir string) error {
+	Stop(network)
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return fmt.Errorf("Failed opening boot files directory: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		_ = root.Close()
+		return fmt.Errorf("Failed listening for HTTP boot on %q: %w", address, err)
+	}
+
+	s := &server{
+		root: root,
+		srv: &http.Server{
+			Handler:           Handler(root),
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+
+	go func() {
+		err := s.srv.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("HTTP boot server failed", logger.Ctx{"network": network, "err": err})
+		}
+	}()
+
+	serversMu.Lock()
+	servers[network] = s
+	serversMu.Unlock()
+
+	return nil
+}
+
+// Stop stops the HTTP boot server of the network if running.
+func Stop(network string) {
+	serversMu.Lock()
+	s, found := servers[network]
+	delete(servers, network)
+	serversMu.Unlock()
+
+	if !found {
+		return
+	}
+
+	_ = s.srv.Close()
+	_ = s.root.Close()
+}
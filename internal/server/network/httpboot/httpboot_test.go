@@ -0,0 +1,57 @@
+package httpboot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(t.TempDir(), "secret")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "boot.ipxe"), []byte("#!ipxe\n"), 0o644))
+	require.NoError(t, os.WriteFile(secret, []byte("secret"), 0o600))
+	require.NoError(t, os.Symlink(secret, filepath.Join(dir, "escape")))
+
+	root, err := os.OpenRoot(dir)
+	require.NoError(t, err)
+
+	defer func() { _ = root.Close() }()
+
+	handler := Handler(root)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{name: "File", method: http.MethodGet, path: "/boot.ipxe", status: http.StatusOK, body: "#!ipxe\n"},
+		{name: "Head", method: http.MethodHead, path: "/boot.ipxe", status: http.StatusOK},
+		{name: "Missing file", method: http.MethodGet, path: "/missing", status: http.StatusNotFound},
+		{name: "Symlink out of the volume", method: http.MethodGet, path: "/escape", status: http.StatusInternalServerError},
+		{name: "Path traversal", method: http.MethodGet, path: "/../secret", status: http.StatusNotFound},
+		{name: "Write", method: http.MethodPut, path: "/boot.ipxe", status: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "http://192.0.2.1"+tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			if tt.body != "" {
+				assert.Equal(t, tt.body, rec.Body.String())
+			}
+		})
+	}
+}
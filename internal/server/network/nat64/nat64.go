@@ -0,0 +1,684 @@
+// Package nat64 implements a userspace NAT64 translator.
+//
+// The translator is a stateless IP/ICMP translator (SIIT, RFC 7915) which maps each IPv6 client to an address
+// of an IPv4 pool, the translated traffic then being masqueraded by the host firewall. IPv4 destinations are
+// embedded into a /96 NAT64 prefix. The packets are exchanged with the kernel through a TUN device.
+package nat64
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+const (
+	protoICMP   = 1
+	protoTCP    = 6
+	protoUDP    = 17
+	protoFrag   = 44
+	protoICMPv6 = 58
+)
+
+// bindingTimeout is how long an unused mapping between an IPv6 client and a pool address is kept for.
+const bindingTimeout = 2 * time.Hour
+
+// icmpErrorMaxLen is the maximum length of the translated ICMP error messages, for IPv4 and IPv6.
+var icmpErrorMaxLen = map[int]int{4: 576, 6: 1280}
+
+// Config represents the configuration of a translator.
+type Config struct {
+	Prefix *net.IPNet // NAT64 prefix the IPv4 addresses are mapped into (must be a /96).
+	Pool   *net.IPNet // IPv4 addresses the IPv6 clients are mapped to.
+}
+
+type binding struct {
+	ipv6     [16]byte
+	ipv4     [4]byte
+	lastUsed time.Time
+}
+
+// Translator translates packets between IPv6 and IPv4.
+type Translator struct {
+	prefix   [12]byte
+	poolBase uint32
+	poolSize uint32
+
+	mu   sync.Mutex
+	by6  map[[16]byte]*binding
+	by4  map[[4]byte]*binding
+	next uint32
+	now  func() time.Time
+}
+
+// NewTranslator returns a translator for the prefix and pool.
+func NewTranslator(config Config) (*Translator, error) {
+	ones, bits := config.Prefix.Mask.Size()
+	if ones != 96 || bits != 128 {
+		return nil, fmt.Errorf("Invalid NAT64 prefix %q, must be a /96", config.Prefix.String())
+	}
+
+	ones, bits = config.Pool.Mask.Size()
+	if bits != 32 || ones > 30 {
+		return nil, fmt.Errorf("Invalid NAT64 pool %q, must be an IPv4 subnet of at least a /30", config.Pool.String())
+	}
+
+	t := &Translator{
+		poolBase: binary.BigEndian.Uint32(config.Pool.IP.To4()),
+		poolSize: uint32(1) << (32 - ones),
+		by6:      map[[16]byte]*binding{},
+		by4:      map[[4]byte]*binding{},
+		next:     1,
+		now:      time.Now,
+	}
+
+	copy(t.prefix[:], config.Prefix.IP.To16()[:12])
+
+	return t, nil
+}
+
+// Run translates the packets read from the TUN device until reading fails.
+func (t *Translator) Run(tun io.ReadWriter) error {
+	buf := make([]byte, 65536)
+
+	for {
+		n, err := tun.Read(buf)
+		if err != nil {
+			return err
+		}
+
+		out := t.Translate(buf[:n])
+		if out == nil {
+			continue
+		}
+
+		_, err = tun.Write(out)
+		if err != nil {
+			logger.Debug("Failed sending translated packet", logger.Ctx{"err": err})
+		}
+	}
+}
+
+// Translate returns the packet translated to the other address family, or nil if it must be dropped.
+func (t *Translator) Translate(pkt []byte) []byte {
+	if len(pkt) == 0 {
+		return nil
+	}
+
+	switch pkt[0] >> 4 {
+	case 6:
+		return t.translate6to4(pkt, false)
+	case 4:
+		return t.translate4to6(pkt, false)
+	}
+
+	return nil
+}
+
+// map6 returns the pool address of the IPv6 client, allocating one if allowed.
+func (t *Translator) map6(addr [16]byte, allocate bool) ([4]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+
+	b, found := t.by6[addr]
+	if found {
+		b.lastUsed = now
+		return b.ipv4, nil
+	}
+
+	if !allocate {
+		return [4]byte{}, errors.New("No mapping for IPv6 address")
+	}
+
+	// Look for an unused pool address, skipping the network and broadcast addresses.
+	var ipv4 [4]byte
+	allocated := false
+	for range t.poolSize - 2 {
+		index := t.next
+
+		t.next++
+		if t.next >= t.poolSize-1 {
+			t.next = 1
+		}
+
+		binary.BigEndian.PutUint32(ipv4[:], t.poolBase+index)
+
+		existing, found := t.by4[ipv4]
+		if !found {
+			allocated = true
+			break
+		}
+
+		// Reuse the addresses of the clients which went away.
+		if now.Sub(existing.lastUsed) > bindingTimeout {
+			delete(t.by6, existing.ipv6)
+			allocated = true
+			break
+		}
+	}
+
+	if !allocated {
+		return [4]byte{}, errors.New("NAT64 pool exhausted")
+	}
+
+	b = &binding{ipv6: addr, ipv4: ipv4, lastUsed: now}
+	t.by6[addr] = b
+	t.by4[ipv4] = b
+
+	return ipv4, nil
+}
+
+// map4 returns the IPv6 client the pool address is mapped to.
+func (t *Translator) map4(addr [4]byte) ([16]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, found := t.by4[addr]
+	if !found {
+		return [16]byte{}, errors.New("No mapping for IPv4 address")
+	}
+
+	b.lastUsed = t.now()
+
+	return b.ipv6, nil
+}
+
+// embed returns the IPv6 address of the IPv4 address within the NAT64 prefix.
+func (t *Translator) embed(addr [4]byte) [16]byte {
+	var out [16]byte
+	copy(out[:12], t.prefix[:])
+	copy(out[12:], addr[:])
+
+	return out
+}
+
+// extract returns the IPv4 address embedded in the IPv6 address, if within the NAT64 prefix.
+func (t *Translator) extract(addr [16]byte) ([4]byte, bool) {
+	var out [4]byte
+	if [12]byte(addr[:12]) != t.prefix {
+		return out, false
+	}
+
+	copy(out[:], addr[12:])
+
+	return out, true
+}
+
+// translate6to4 translates an IPv6 packet sent by a client (or the packet quoted by an ICMPv6 error when inner).
+func (t *Translator) translate6to4(pkt []byte, inner bool) []byte {
+	if len(pkt) < 40 || pkt[0]>>4 != 6 {
+		return nil
+	}
+
+	payloadLen := int(binary.BigEndian.Uint16(pkt[4:6]))
+	if 40+payloadLen > len(pkt) {
+		if !inner {
+			return nil
+		}
+
+		// Quoted packets may be truncated.
+		payloadLen = len(pkt) - 40
+	}
+
+	nextHeader := pkt[6]
+	hopLimit := pkt[7]
+	src := [16]byte(pkt[8:24])
+	dst := [16]byte(pkt[24:40])
+	payload := pkt[40 : 40+payloadLen]
+
+	if !inner && hopLimit <= 1 {
+		return nil
+	}
+
+	// The client is the source of the packets it sends and the destination of the quoted ones.
+	var src4, dst4 [4]byte
+	var ok bool
+	var err error
+	if inner {
+		src4, ok = t.extract(src)
+		if !ok {
+			return nil
+		}
+
+		dst4, err = t.map6(dst, false)
+	} else {
+		dst4, ok = t.extract(dst)
+		if !ok {
+			return nil
+		}
+
+		src4, err = t.map6(src, true)
+	}
+
+	if err != nil {
+		return nil
+	}
+
+	// Translate the fragment header into the IPv4 header fields.
+	var fragID uint16
+	var fragOffset uint16
+	var moreFrags bool
+	fragmented := false
+	if nextHeader == protoFrag {
+		if len(payload) < 8 {
+			return nil
+		}
+
+		nextHeader = payload[0]
+		offsetFlags := binary.BigEndian.Uint16(payload[2:4])
+		fragOffset = offsetFlags >> 3
+		moreFrags = offsetFlags&1 == 1
+		fragID = uint16(binary.BigEndian.Uint32(payload[4:8]))
+		payload = payload[8:]
+		fragmented = true
+	}
+
+	proto := nextHeader
+	switch nextHeader {
+	case protoTCP, protoUDP:
+		payload = append([]byte(nil), payload...)
+		if fragOffset == 0 {
+			adjustL4Checksum(payload, nextHeader, src[:], dst[:], src4[:], dst4[:])
+		}
+
+	case protoICMPv6:
+		if fragmented {
+			return nil
+		}
+
+		payload = t.icmp6to4(payload, inner)
+		if payload == nil {
+			return nil
+		}
+
+		proto = protoICMP
+
+	default:
+		return nil
+	}
+
+	out := make([]byte, 20+len(payload))
+	out[0] = 0x45
+	out[1] = pkt[0]<<4 | pkt[1]>>4
+	binary.BigEndian.PutUint16(out[2:4], uint16(len(out)))
+	binary.BigEndian.PutUint16(out[4:6], fragID)
+
+	flags := uint16(0x4000) // Don't fragment.
+	if fragmented {
+		flags = fragOffset
+		if moreFrags {
+			flags |= 0x2000
+		}
+	}
+
+	binary.BigEndian.PutUint16(out[6:8], flags)
+	out[8] = hopLimit
+	if !inner {
+		out[8]--
+	}
+
+	out[9] = proto
+	copy(out[12:16], src4[:])
+	copy(out[16:20], dst4[:])
+	binary.BigEndian.PutUint16(out[10:12], checksum(out[:20], 0))
+	copy(out[20:], payload)
+
+	if proto == protoICMP && !inner {
+		binary.BigEndian.PutUint16(out[22:24], 0)
+		binary.BigEndian.PutUint16(out[22:24], checksum(out[20:], 0))
+	}
+
+	return out
+}
+
+// translate4to6 translates an IPv4 packet sent to a client (or the packet quoted by an ICMP error when inner).
+func (t *Translator) translate4to6(pkt []byte, inner bool) []byte {
+	if len(pkt) < 20 || pkt[0]>>4 != 4 {
+		return nil
+	}
+
+	headerLen := int(pkt[0]&0x0f) * 4
+	totalLen := int(binary.BigEndian.Uint16(pkt[2:4]))
+	if headerLen < 20 || len(pkt) < headerLen {
+		return nil
+	}
+
+	if totalLen > len(pkt) || totalLen < headerLen {
+		if !inner {
+			return nil
+		}
+
+		// Quoted packets may be truncated.
+		totalLen = len(pkt)
+	}
+
+	ttl := pkt[8]
+	proto := pkt[9]
+	src := [4]byte(pkt[12:16])
+	dst := [4]byte(pkt[16:20])
+	payload := pkt[headerLen:totalLen]
+
+	if !inner && ttl <= 1 {
+		return nil
+	}
+
+	// The client is the destination of the packets it receives and the source of the quoted ones.
+	var src6, dst6 [16]byte
+	var err error
+	if inner {
+		src6, err = t.map4(src)
+		dst6 = t.embed(dst)
+	} else {
+		src6 = t.embed(src)
+		dst6, err = t.map4(dst)
+	}
+
+	if err != nil {
+		return nil
+	}
+
+	flags := binary.BigEndian.Uint16(pkt[6:8])
+	fragOffset := flags & 0x1fff
+	moreFrags := flags&0x2000 != 0
+	fragmented := fragOffset != 0 || moreFrags
+
+	nextHeader := proto
+	switch proto {
+	case protoTCP, protoUDP:
+		payload = append([]byte(nil), payload...)
+		if fragOffset == 0 {
+			// A zero UDP checksum isn't allowed over IPv6.
+			if proto == protoUDP && len(payload) >= 8 && binary.BigEndian.Uint16(payload[6:8]) == 0 {
+				if fragmented || inner {
+					return nil
+				}
+
+				binary.BigEndian.PutUint16(payload[6:8], l4Checksum(payload, protoUDP, src6[:], dst6[:]))
+			} else {
+				adjustL4Checksum(payload, proto, src[:], dst[:], src6[:], dst6[:])
+			}
+		}
+
+	case protoICMP:
+		if fragmented {
+			return nil
+		}
+
+		payload = t.icmp4to6(payload, inner)
+		if payload == nil {
+			return nil
+		}
+
+		nextHeader = protoICMPv6
+
+	default:
+		return nil
+	}
+
+	extraLen := 0
+	if fragmented {
+		extraLen = 8
+	}
+
+	out := make([]byte, 40+extraLen+len(payload))
+	binary.BigEndian.PutUint32(out[0:4], 6<<28|uint32(pkt[1])<<20)
+	binary.BigEndian.PutUint16(out[4:6], uint16(extraLen+len(payload)))
+	out[6] = nextHeader
+	out[7] = ttl
+	if !inner {
+		out[7]--
+	}
+
+	copy(out[8:24], src6[:])
+	copy(out[24:40], dst6[:])
+
+	if fragmented {
+		out[6] = protoFrag
+		out[40] = nextHeader
+
+		offsetFlags := fragOffset << 3
+		if moreFrags {
+			offsetFlags |= 1
+		}
+
+		binary.BigEndian.PutUint16(out[42:44], offsetFlags)
+		binary.BigEndian.PutUint32(out[44:48], uint32(binary.BigEndian.Uint16(pkt[4:6])))
+	}
+
+	copy(out[40+extraLen:], payload)
+
+	if nextHeader == protoICMPv6 && !inner {
+		icmp := out[40+extraLen:]
+		binary.BigEndian.PutUint16(icmp[2:4], 0)
+		binary.BigEndian.PutUint16(icmp[2:4], l4Checksum(icmp, protoICMPv6, src6[:], dst6[:]))
+	}
+
+	return out
+}
+
+// icmp6to4 translates an ICMPv6 message into an ICMP one (without checksum).
+func (t *Translator) icmp6to4(msg []byte, inner bool) []byte {
+	if len(msg) < 8 {
+		return nil
+	}
+
+	out := append([]byte(nil), msg...)
+	icmpType := msg[0]
+	icmpCode := msg[1]
+
+	switch icmpType {
+	case 128: // Echo request.
+		out[0] = 8
+		return out
+
+	case 129: // Echo reply.
+		out[0] = 0
+		return out
+	}
+
+	// Errors quoting errors aren't translated.
+	if inner {
+		return nil
+	}
+
+	switch icmpType {
+	case 1: // Destination unreachable.
+		out[0] = 3
+		switch icmpCode {
+		case 0, 2, 3:
+			out[1] = 1 // Host unreachable.
+		case 1:
+			out[1] = 10 // Communication administratively prohibited.
+		case 4:
+			out[1] = 3 // Port unreachable.
+		default:
+			return nil
+		}
+
+		binary.BigEndian.PutUint32(out[4:8], 0)
+
+	case 2: // Packet too big.
+		mtu := binary.BigEndian.Uint32(msg[4:8])
+		if mtu < 68+20 {
+			return nil
+		}
+
+		out[0] = 3
+		out[1] = 4 // Fragmentation needed.
+		binary.BigEndian.PutUint32(out[4:8], min(mtu-20, 0xffff))
+
+	case 3: // Time exceeded.
+		out[0] = 11
+		binary.BigEndian.PutUint32(out[4:8], 0)
+
+	case 4: // Parameter problem.
+		if icmpCode != 1 {
+			return nil
+		}
+
+		out[0] = 3
+		out[1] = 2 // Protocol unreachable.
+		binary.BigEndian.PutUint32(out[4:8], 0)
+
+	default:
+		return nil
+	}
+
+	quoted := t.translate6to4(msg[8:], true)
+	if quoted == nil {
+		return nil
+	}
+
+	out = append(out[:8], quoted...)
+
+	return out[:min(len(out), icmpErrorMaxLen[4]-20)]
+}
+
+// icmp4to6 translates an ICMP message into an ICMPv6 one (without checksum).
+func (t *Translator) icmp4to6(msg []byte, inner bool) []byte {
+	if len(msg) < 8 {
+		return nil
+	}
+
+	out := append([]byte(nil), msg...)
+	icmpType := msg[0]
+	icmpCode := msg[1]
+
+	switch icmpType {
+	case 8: // Echo request.
+		out[0] = 128
+		return out
+
+	case 0: // Echo reply.
+		out[0] = 129
+		return out
+	}
+
+	// Errors quoting errors aren't translated.
+	if inner {
+		return nil
+	}
+
+	switch icmpType {
+	case 3: // Destination unreachable.
+		out[0] = 1
+		binary.BigEndian.PutUint32(out[4:8], 0)
+
+		switch icmpCode {
+		case 0, 1, 5, 6, 7, 8, 11, 12:
+			out[1] = 0 // No route to destination.
+		case 9, 10, 13, 15:
+			out[1] = 1 // Communication administratively prohibited.
+		case 2:
+			out[0] = 4 // Parameter problem.
+			out[1] = 1 // Unrecognized next header.
+			binary.BigEndian.PutUint32(out[4:8], 6)
+		case 3:
+			out[1] = 4 // Port unreachable.
+		case 4:
+			mtu := uint32(binary.BigEndian.Uint16(msg[6:8])) + 20
+			out[0] = 2 // Packet too big.
+			out[1] = 0
+			binary.BigEndian.PutUint32(out[4:8], max(mtu, 1280))
+		default:
+			return nil
+		}
+
+	case 11: // Time exceeded.
+		out[0] = 3
+		binary.BigEndian.PutUint32(out[4:8], 0)
+
+	default:
+		return nil
+	}
+
+	quoted := t.translate4to6(msg[8:], true)
+	if quoted == nil {
+		return nil
+	}
+
+	out = append(out[:8], quoted...)
+
+	return out[:min(len(out), icmpErrorMaxLen[6]-40)]
+}
+
+// checksum returns the one's complement checksum of the data, starting from the given partial sum.
+func checksum(data []byte, initial uint32) uint16 {
+	sum := initial
+	for i := 0; i+1 < len(data); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(data[i:]))
+	}
+
+	if len(data)%2 == 1 {
+		sum += uint32(data[len(data)-1]) << 8
+	}
+
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+
+	return ^uint16(sum)
+}
+
+// l4Checksum returns the checksum of the transport message including the pseudo-header of the addresses.
+func l4Checksum(msg []byte, proto uint8, src []byte, dst []byte) uint16 {
+	var sum uint32
+	for _, addr := range [][]byte{src, dst} {
+		for i := 0; i < len(addr); i += 2 {
+			sum += uint32(binary.BigEndian.Uint16(addr[i:]))
+		}
+	}
+
+	sum += uint32(proto) + uint32(len(msg))
+
+	result := checksum(msg, sum)
+	if result == 0 && proto == protoUDP {
+		return 0xffff
+	}
+
+	return result
+}
+
+// adjustL4Checksum updates the TCP or UDP checksum for the change of the addresses of the pseudo-header,
+// which is all that differs between the IPv4 and IPv6 ones (RFC 1624).
+func adjustL4Checksum(msg []byte, proto uint8, oldSrc []byte, oldDst []byte, newSrc []byte, newDst []byte) {
+	offset := 16
+	if proto == protoUDP {
+		offset = 6
+	}
+
+	if len(msg) < offset+2 {
+		return
+	}
+
+	sum := uint32(^binary.BigEndian.Uint16(msg[offset:]))
+	for _, addr := range [][]byte{oldSrc, oldDst} {
+		for i := 0; i < len(addr); i += 2 {
+			sum += uint32(^binary.BigEndian.Uint16(addr[i:]))
+		}
+	}
+
+	for _, addr := range [][]byte{newSrc, newDst} {
+		for i := 0; i < len(addr); i += 2 {
+			sum += uint32(binary.BigEndian.Uint16(addr[i:]))
+		}
+	}
+
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+
+	result := ^uint16(sum)
+	if result == 0 && proto == protoUDP {
+		result = 0xffff
+	}
+
+	binary.BigEndian.PutUint16(msg[offset:], result)
+}
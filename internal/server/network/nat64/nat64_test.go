@@ -0,0 +1,228 @@
+package nat64
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTranslator(t *testing.T) *Translator {
+	_, prefix, _ := net.ParseCIDR("64:ff9b::/96")
+	_, pool, _ := net.ParseCIDR("100.64.0.0/30")
+
+	translator, err := NewTranslator(Config{Prefix: prefix, Pool: pool})
+	require.NoError(t, err)
+
+	return translator
+}
+
+// ipv6Packet returns an IPv6 packet carrying the payload, computing the transport checksum.
+func ipv6Packet(src string, dst string, proto uint8, payload []byte) []byte {
+	pkt := make([]byte, 40+len(payload))
+	binary.BigEndian.PutUint32(pkt[0:4], 6<<28)
+	binary.BigEndian.PutUint16(pkt[4:6], uint16(len(payload)))
+	pkt[6] = proto
+	pkt[7] = 64
+	copy(pkt[8:24], net.ParseIP(src).To16())
+	copy(pkt[24:40], net.ParseIP(dst).To16())
+	copy(pkt[40:], payload)
+
+	setChecksum(pkt[40:], proto, pkt[8:24], pkt[24:40])
+
+	return pkt
+}
+
+// ipv4Packet returns an IPv4 packet carrying the payload, computing the transport checksum.
+func ipv4Packet(src string, dst string, proto uint8, payload []byte) []byte {
+	pkt := make([]byte, 20+len(payload))
+	pkt[0] = 0x45
+	binary.BigEndian.PutUint16(pkt[2:4], uint16(len(pkt)))
+	pkt[8] = 64
+	pkt[9] = proto
+	copy(pkt[12:16], net.ParseIP(src).To4())
+	copy(pkt[16:20], net.ParseIP(dst).To4())
+	binary.BigEndian.PutUint16(pkt[10:12], checksum(pkt[:20], 0))
+	copy(pkt[20:], payload)
+
+	if proto == protoICMP {
+		binary.BigEndian.PutUint16(pkt[22:24], checksum(pkt[20:], 0))
+	} else {
+		setChecksum(pkt[20:], proto, pkt[12:16], pkt[16:20])
+	}
+
+	return pkt
+}
+
+func setChecksum(msg []byte, proto uint8, src []byte, dst []byte) {
+	offset := map[uint8]int{protoTCP: 16, protoUDP: 6, protoICMPv6: 2}[proto]
+	binary.BigEndian.PutUint16(msg[offset:], 0)
+	binary.BigEndian.PutUint16(msg[offset:], l4Checksum(msg, proto, src, dst))
+}
+
+// validChecksum returns whether the transport checksum of the message is valid.
+func validChecksum(msg []byte, proto uint8, src []byte, dst []byte) bool {
+	var sum uint32
+	for _, addr := range [][]byte{src, dst} {
+		for i := 0; i < len(addr); i += 2 {
+			sum += uint32(binary.BigEndian.Uint16(addr[i:]))
+		}
+	}
+
+	return checksum(msg, sum+uint32(proto)+uint32(len(msg))) == 0
+}
+
+func udpPayload(data string) []byte {
+	msg := make([]byte, 8+len(data))
+	binary.BigEndian.PutUint16(msg[0:2], 40000)
+	binary.BigEndian.PutUint16(msg[2:4], 53)
+	binary.BigEndian.PutUint16(msg[4:6], uint16(len(msg)))
+	copy(msg[8:], data)
+
+	return msg
+}
+
+func echoPayload(icmpType uint8) []byte {
+	return []byte{icmpType, 0, 0, 0, 0x12, 0x34, 0, 1, 'p', 'i', 'n', 'g'}
+}
+
+// Translated packets have valid checksums and use the pool and embedded addresses.
+func TestTranslator_UDP(t *testing.T) {
+	translator := newTestTranslator(t)
+
+	out := translator.Translate(ipv6Packet("2001:db8::10", "64:ff9b::c000:201", protoUDP, udpPayload("hello")))
+	require.NotNil(t, out)
+	assert.Equal(t, uint8(4), out[0]>>4)
+	assert.Equal(t, uint8(63), out[8])
+	assert.Equal(t, "100.64.0.1", net.IP(out[12:16]).String())
+	assert.Equal(t, "192.0.2.1", net.IP(out[16:20]).String())
+	assert.Equal(t, uint16(0), checksum(out[:20], 0))
+	assert.True(t, validChecksum(out[20:], protoUDP, out[12:16], out[16:20]))
+
+	// The reply goes back to the client.
+	reply := translator.Translate(ipv4Packet("192.0.2.1", "100.64.0.1", protoUDP, udpPayload("world")))
+	require.NotNil(t, reply)
+	assert.Equal(t, uint8(6), reply[0]>>4)
+	assert.Equal(t, "64:ff9b::c000:201", net.IP(reply[8:24]).String())
+	assert.Equal(t, "2001:db8::10", net.IP(reply[24:40]).String())
+	assert.True(t, validChecksum(reply[40:], protoUDP, reply[8:24], reply[24:40]))
+}
+
+// Echo messages are translated between ICMPv6 and ICMP.
+func TestTranslator_Echo(t *testing.T) {
+	translator := newTestTranslator(t)
+
+	out := translator.Translate(ipv6Packet("2001:db8::10", "64:ff9b::c000:201", protoICMPv6, echoPayload(128)))
+	require.NotNil(t, out)
+	assert.Equal(t, uint8(protoICMP), out[9])
+	assert.Equal(t, uint8(8), out[20])
+	assert.Equal(t, uint16(0), checksum(out[20:], 0))
+
+	reply := translator.Translate(ipv4Packet("192.0.2.1", "100.64.0.1", protoICMP, echoPayload(0)))
+	require.NotNil(t, reply)
+	assert.Equal(t, uint8(protoICMPv6), reply[6])
+	assert.Equal(t, uint8(129), reply[40])
+	assert.True(t, validChecksum(reply[40:], protoICMPv6, reply[8:24], reply[24:40]))
+}
+
+// ICMP errors are translated along with the packet they quote.
+func TestTranslator_Errors(t *testing.T) {
+	translator := newTestTranslator(t)
+
+	request := ipv6Packet("2001:db8::10", "64:ff9b::c000:201", protoUDP, udpPayload("hello"))
+	out := translator.Translate(request)
+	require.NotNil(t, out)
+
+	// Fragmentation needed becomes packet too big, with room for the larger IPv6 header.
+	icmp := append([]byte{3, 4, 0, 0, 0, 0, 0x05, 0xa0}, out...)
+	reply := translator.Translate(ipv4Packet("198.51.100.1", "100.64.0.1", protoICMP, icmp))
+	require.NotNil(t, reply)
+	assert.Equal(t, uint8(2), reply[40])
+	assert.Equal(t, uint32(1440+20), binary.BigEndian.Uint32(reply[44:48]))
+	assert.Equal(t, "2001:db8::10", net.IP(reply[48+8:48+24]).String())
+	assert.Equal(t, "64:ff9b::c000:201", net.IP(reply[48+24:48+40]).String())
+	assert.True(t, validChecksum(reply[40:], protoICMPv6, reply[8:24], reply[24:40]))
+
+	// Port unreachable is kept as such.
+	icmp = append([]byte{3, 3, 0, 0, 0, 0, 0, 0}, out...)
+	reply = translator.Translate(ipv4Packet("192.0.2.1", "100.64.0.1", protoICMP, icmp))
+	require.NotNil(t, reply)
+	assert.Equal(t, []byte{1, 4}, reply[40:42])
+
+	// Port unreachable sent by the client is translated for the IPv4 host.
+	quoted := ipv6Packet("64:ff9b::c000:201", "2001:db8::10", protoUDP, udpPayload("world"))
+	errOut := translator.Translate(ipv6Packet("2001:db8::10", "64:ff9b::c000:201", protoICMPv6, append([]byte{1, 4, 0, 0, 0, 0, 0, 0}, quoted...)))
+	require.NotNil(t, errOut)
+	assert.Equal(t, []byte{3, 3}, errOut[20:22])
+	assert.Equal(t, uint16(0), checksum(errOut[20:], 0))
+	assert.Equal(t, "192.0.2.1", net.IP(errOut[28+12:28+16]).String())
+	assert.Equal(t, "100.64.0.1", net.IP(errOut[28+16:28+20]).String())
+}
+
+// Clients are mapped to the usable pool addresses until it's exhausted.
+func TestTranslator_Pool(t *testing.T) {
+	translator := newTestTranslator(t)
+
+	now := time.Now()
+	translator.now = func() time.Time { return now }
+
+	for i, client := range []string{"2001:db8::1", "2001:db8::2"} {
+		out := translator.Translate(ipv6Packet(client, "64:ff9b::c000:201", protoUDP, udpPayload("hello")))
+		require.NotNil(t, out)
+		assert.Equal(t, net.IPv4(100, 64, 0, byte(i+1)).To4(), net.IP(out[12:16]))
+	}
+
+	// The same client keeps its address.
+	out := translator.Translate(ipv6Packet("2001:db8::1", "64:ff9b::c000:201", protoUDP, udpPayload("hello")))
+	require.NotNil(t, out)
+	assert.Equal(t, "100.64.0.1", net.IP(out[12:16]).String())
+
+	// The pool is exhausted.
+	out = translator.Translate(ipv6Packet("2001:db8::3", "64:ff9b::c000:201", protoUDP, udpPayload("hello")))
+	assert.Nil(t, out)
+
+	// Unused mappings are reused once expired.
+	now = now.Add(bindingTimeout + time.Minute)
+	out = translator.Translate(ipv6Packet("2001:db8::3", "64:ff9b::c000:201", protoUDP, udpPayload("hello")))
+	require.NotNil(t, out)
+	assert.Equal(t, "100.64.0.1", net.IP(out[12:16]).String())
+
+	// Traffic to unmapped pool addresses is dropped.
+	assert.Nil(t, translator.Translate(ipv4Packet("192.0.2.1", "100.64.0.3", protoUDP, udpPayload("world"))))
+}
+
+// Packets outside of the translated prefix or without remaining hops are dropped.
+func TestTranslator_Drop(t *testing.T) {
+	translator := newTestTranslator(t)
+
+	assert.Nil(t, translator.Translate(ipv6Packet("2001:db8::10", "2001:db8:1::1", protoUDP, udpPayload("hello"))))
+
+	pkt := ipv6Packet("2001:db8::10", "64:ff9b::c000:201", protoUDP, udpPayload("hello"))
+	pkt[7] = 1
+	assert.Nil(t, translator.Translate(pkt))
+
+	assert.Nil(t, translator.Translate([]byte{0x45, 0}))
+}
+
+// IPv4 fragments get an IPv6 fragment header.
+func TestTranslator_Fragment(t *testing.T) {
+	translator := newTestTranslator(t)
+
+	require.NotNil(t, translator.Translate(ipv6Packet("2001:db8::10", "64:ff9b::c000:201", protoUDP, udpPayload("hello"))))
+
+	pkt := ipv4Packet("192.0.2.1", "100.64.0.1", protoUDP, udpPayload("world"))
+	binary.BigEndian.PutUint16(pkt[4:6], 0x1234)
+	binary.BigEndian.PutUint16(pkt[6:8], 0x2000)
+	binary.BigEndian.PutUint16(pkt[10:12], 0)
+	binary.BigEndian.PutUint16(pkt[10:12], checksum(pkt[:20], 0))
+
+	out := translator.Translate(pkt)
+	require.NotNil(t, out)
+	assert.Equal(t, uint8(protoFrag), out[6])
+	assert.Equal(t, uint8(protoUDP), out[40])
+	assert.Equal(t, uint16(1), binary.BigEndian.Uint16(out[42:44]))
+	assert.Equal(t, uint32(0x1234), binary.BigEndian.Uint32(out[44:48]))
+}
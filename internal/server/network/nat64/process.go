@@ -0,0 +1,171 @@
+package nat64
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/ip"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// Start starts the translator of a network in a forknat64 subprocess, which creates the TUN device and its
+// routes. When dns64 is true, the subprocess also runs a DNS64 proxy whose local address is returned.
+// The subprocess isn't tied to the daemon, so translation and name resolution keep working across restarts.
+func Start(execPath string, network string, device string, prefix *net.IPNet, pool *net.IPNet, dns64 bool) (string, error) {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Bind the DNS64 sockets here so their address is known before starting dnsmasq.
+	var files []*os.File
+	dns64Address := ""
+	if dns64 {
+		udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		if err != nil {
+			return "", fmt.Errorf("Failed binding DNS64 proxy: %w", err)
+		}
+
+		defer func() { _ = udpConn.Close() }()
+
+		dns64Address = udpConn.LocalAddr().String()
+
+		tcpAddr, err := net.ResolveTCPAddr("tcp", dns64Address)
+		if err != nil {
+			return "", err
+		}
+
+		tcpListener, err := net.ListenTCP("tcp", tcpAddr)
+		if err != nil {
+			return "", fmt.Errorf("Failed binding DNS64 proxy: %w", err)
+		}
+
+		defer func() { _ = tcpListener.Close() }()
+
+		udpFile, err := udpConn.File()
+		if err != nil {
+			return "", err
+		}
+
+		defer func() { _ = udpFile.Close() }()
+
+		tcpFile, err := tcpListener.File()
+		if err != nil {
+			return "", err
+		}
+
+		defer func() { _ = tcpFile.Close() }()
+
+		files = []*os.File{udpFile, tcpFile}
+	}
+
+	logPath := internalUtil.LogPath(fmt.Sprintf("forknat64.%s.log", network))
+	pidPath := internalUtil.VarPath("networks", network, "forknat64.pid")
+
+	args := []string{"forknat64", "--", device, prefix.String(), pool.String(), fmt.Sprintf("%t", dns64)}
+	p, err := subprocess.NewProcess(execPath, args, logPath, logPath)
+	if err != nil {
+		return "", fmt.Errorf("Failed to create subprocess: %w", err)
+	}
+
+	err = p.StartWithFiles(context.Background(), files)
+	if err != nil {
+		return "", fmt.Errorf("Failed running forknat64: %w", err)
+	}
+
+	reverter.Add(func() { _ = p.Stop() })
+
+	// Poll log file a few times until we see "Started" to indicate successful start.
+	started := false
+	for range 10 {
+		started, err = checkStarted(logPath)
+		if err != nil {
+			return "", fmt.Errorf("Failed starting NAT64 translator: %w", err)
+		}
+
+		if started {
+			break
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	if !started {
+		return "", fmt.Errorf("Failed starting NAT64 translator: Please look in %s", logPath)
+	}
+
+	err = p.Save(pidPath)
+	if err != nil {
+		return "", fmt.Errorf("Failed to save subprocess details: %w", err)
+	}
+
+	reverter.Success()
+
+	return dns64Address, nil
+}
+
+// checkStarted checks for the "Started" line in the log file.
+func checkStarted(logPath string) (bool, error) {
+	file, err := os.Open(logPath)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "Status: Started" {
+			return true, nil
+		}
+
+		if strings.HasPrefix(line, "Error:") {
+			return false, errors.New(line)
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// Kill stops the translator of a network. The TUN device and its routes go away with the subprocess.
+func Kill(network string, device string) error {
+	pidPath := internalUtil.VarPath("networks", network, "forknat64.pid")
+
+	if util.PathExists(pidPath) {
+		p, err := subprocess.ImportProcess(pidPath)
+		if err != nil {
+			return fmt.Errorf("Could not read pid file: %w", err)
+		}
+
+		err = p.Stop()
+		if err != nil && !errors.Is(err, subprocess.ErrNotRunning) {
+			return fmt.Errorf("Unable to kill forknat64: %w", err)
+		}
+
+		err = os.Remove(pidPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Remove any leftover device, such as a persistent one created by a previous translator.
+	_, err := net.InterfaceByName(device)
+	if err == nil {
+		link := &ip.Link{Name: device}
+		err = link.Delete()
+		if err != nil {
+			return fmt.Errorf("Failed deleting NAT64 device %q: %w", device, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package nat64
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// OpenTUN creates a TUN device exchanging raw IP packets. The device isn't persistent and is removed,
+// along with its routes, when the returned file is closed.
+func OpenTUN(name string) (*os.File, error) {
+	f, err := os.OpenFile("/dev/net/tun", os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ifr, err := unix.NewIfreq(name)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("Error creating new ifreq for %q: %w", name, err)
+	}
+
+	ifr.SetUint16(unix.IFF_TUN | unix.IFF_NO_PI)
+
+	err = unix.IoctlIfreq(int(f.Fd()), unix.TUNSETIFF, ifr)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("Failed creating TUN device %q: %w", name, err)
+	}
+
+	return f, nil
+}
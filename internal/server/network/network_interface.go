@@ -57,12 +57,24 @@ type Network interface {
 	// Status.
 	State() (*api.NetworkState, error)
 	Leases(projectName string, clientType request.ClientType) ([]api.NetworkLease, error)
+	Trace(req api.NetworkTracePost) (*api.NetworkTrace, error)
 
 	// Address Forwards.
 	ForwardCreate(forward api.NetworkForwardsPost, clientType request.ClientType) error
 	ForwardUpdate(listenAddress string, newForward api.NetworkForwardPut, clientType request.ClientType) error
 	ForwardDelete(listenAddress string, clientType request.ClientType) error
 
+	// DHCP Reservations.
+	ReservationCreate(reservation api.NetworkReservationsPost, clientType request.ClientType) error
+	ReservationUpdate(name string, newReservation api.NetworkReservationPut, clientType request.ClientType) error
+	ReservationDelete(name string, clientType request.ClientType) error
+	ReservationConflict(hwaddr string, addresses ...net.IP) error
+
+	// Floating IPs.
+	FloatingIPCreate(projectName string, floatingIP api.NetworkFloatingIPsPost, clientType request.ClientType) error
+	FloatingIPUpdate(address string, newFloatingIP api.NetworkFloatingIPPut, clientType request.ClientType) error
+	FloatingIPDelete(address string, clientType request.ClientType) error
+
 	// Load Balancers.
 	LoadBalancerCreate(loadBalancer api.NetworkLoadBalancersPost, clientType request.ClientType) error
 	LoadBalancerUpdate(listenAddress string, newLoadBalancer api.NetworkLoadBalancerPut, clientType request.ClientType) error
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
 	"math/big"
 	"math/rand"
 	"net"
@@ -20,6 +21,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mdlayher/arp"
+	"github.com/mdlayher/ndp"
+
 	"github.com/lxc/incus/v6/internal/iprange"
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
@@ -80,6 +84,21 @@ func MACDevName(mac net.HardwareAddr) string {
 	return fmt.Sprintf("inc%s", devName[2:])
 }
 
+// hwaddrEqual returns whether the two MAC addresses are valid and identical, regardless of their notation.
+func hwaddrEqual(a string, b string) bool {
+	macA, err := net.ParseMAC(a)
+	if err != nil {
+		return false
+	}
+
+	macB, err := net.ParseMAC(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(macA, macB)
+}
+
 // UsedByInstanceDevices looks for instance NIC devices using the network and runs the supplied usageFunc for each.
 // Accepts optional filter arguments to specify a subset of instances.
 func UsedByInstanceDevices(s *state.State, networkProjectName string, networkName string, networkType string, usageFunc func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error, filters ...cluster.InstanceFilter) error {
@@ -450,8 +469,33 @@ func UpdateDNSMasqStatic(s *state.State, networkName string) error {
 		return err
 	}
 
+	// Get the DHCP reservations of the networks.
+	reservations := map[string][]cluster.NetworkReservation{}
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		for _, network := range networks {
+			// Pass api.ProjectDefaultName here, as currently dnsmasq (bridged) networks do not support projects.
+			networkID, err := tx.GetNetworkID(ctx, api.ProjectDefaultName, network)
+			if err != nil {
+				return err
+			}
+
+			reservations[network], err = cluster.GetNetworkReservations(ctx, tx.Tx(), cluster.NetworkReservationFilter{NetworkID: &networkID})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
 	// Build a list of dhcp host entries.
 	entries := map[string][][]string{}
+
+	// Reservations whose MAC address is used by an instance NIC (keyed by network and reservation name).
+	reservationNICs := map[string]bool{}
 	for _, inst := range insts {
 		// Go through all its devices (including profiles).
 		for deviceName, d := range inst.ExpandedDevices() {
@@ -481,6 +525,24 @@ func UpdateDNSMasqStatic(s *state.State, networkName string) error {
 				continue
 			}
 
+			// Use the addresses of a DHCP reservation matching the NIC's MAC address if not statically set.
+			for _, reservation := range reservations[d["parent"]] {
+				if !hwaddrEqual(reservation.Hwaddr, d["hwaddr"]) {
+					continue
+				}
+
+				if d["ipv4.address"] == "" {
+					d["ipv4.address"] = reservation.IPv4Address
+				}
+
+				if d["ipv6.address"] == "" {
+					d["ipv6.address"] = reservation.IPv6Address
+				}
+
+				reservationNICs[d["parent"]+"/"+reservation.Name] = true
+				break
+			}
+
 			// Add the new host entries.
 			_, ok := entries[d["parent"]]
 			if !ok {
@@ -587,6 +649,20 @@ func UpdateDNSMasqStatic(s *state.State, networkName string) error {
 			}
 		}
 
+		// Apply the DHCP reservations.
+		for _, reservation := range reservations[network] {
+			// The MAC address is already covered by the host entry of the instance NIC using it.
+			hwaddr := reservation.Hwaddr
+			if reservationNICs[network+"/"+reservation.Name] {
+				hwaddr = ""
+			}
+
+			err := dnsmasq.UpdateReservationEntry(network, reservation.Name, config, hwaddr, reservation.DUID, reservation.IPv4Address, reservation.IPv6Address)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Signal dnsmasq.
 		err = dnsmasq.Kill(network, true)
 		if err != nil {
@@ -942,6 +1018,10 @@ func usesIPv4Firewall(netConfig map[string]string) bool {
 		return true
 	}
 
+	if util.IsTrue(netConfig["ipv6.nat64"]) {
+		return true
+	}
+
 	return false
 }
 
@@ -1475,6 +1555,21 @@ func ProxyParseAddr(data string) (*deviceConfig.ProxyAddress, error) {
 	return newProxyAddr, nil
 }
 
+// validateGatewayChassisEntry validates an entry of the OVN gateway chassis list.
+// Entries are either a cluster member name or a cluster group name prefixed with "@".
+func validateGatewayChassisEntry(value string) error {
+	name := strings.TrimPrefix(value, "@")
+	if name == "" {
+		return errors.New("Cluster member or group name is required")
+	}
+
+	if strings.ContainsAny(name, "@, ") {
+		return fmt.Errorf("Invalid cluster member or group name %q", name)
+	}
+
+	return nil
+}
+
 func validateExternalInterfaces(value string) error {
 	for _, entry := range strings.Split(value, ",") {
 		entry = strings.TrimSpace(entry)
@@ -1591,3 +1686,182 @@ func ipInPointerRanges(ipAddr net.IP, ipRanges []*iprange.Range) bool {
 
 	return false
 }
+
+// validateBootFilename validates a network boot file name or URL.
+func validateBootFilename(value string) error {
+	if strings.ContainsAny(value, ", ") {
+		return errors.New("Boot file names cannot contain commas or spaces")
+	}
+
+	return nil
+}
+
+// validateVolumeSource validates a custom storage volume reference in the "<pool>/<volume>" format.
+func validateVolumeSource(value string) error {
+	poolName, volumeName, ok := strings.Cut(value, "/")
+	if !ok || poolName == "" || volumeName == "" || strings.Contains(volumeName, "/") {
+		return errors.New("Invalid syntax for volume, must be <pool>/<volume>")
+	}
+
+	return nil
+}
+
+// interfaceForSubnetIP returns the name of the host interface with an address in a subnet containing the IP.
+func interfaceForSubnetIP(address net.IP) (string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			_, addrNet, err := net.ParseCIDR(addr.String())
+			if err != nil {
+				continue
+			}
+
+			if addrNet.Contains(address) {
+				return iface.Name, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// announceAddress sends a gratuitous ARP (IPv4) or an unsolicited neighbour advertisement (IPv6) for the
+// address on the named interface so that neighbours update their caches to point to this host.
+func announceAddress(ifaceName string, address net.IP) error {
+	ifi, err := net.InterfaceByName(ifaceName)
+	if err != nil {
+		return err
+	}
+
+	if address.To4() != nil {
+		netipAddr, ok := netip.AddrFromSlice(address.To4())
+		if !ok {
+			return fmt.Errorf("Invalid IPv4 address: %v", address)
+		}
+
+		c, err := arp.Dial(ifi)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = c.Close() }()
+
+		broadcast := net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+		packet, err := arp.NewPacket(arp.OperationRequest, ifi.HardwareAddr, netipAddr, broadcast, netipAddr)
+		if err != nil {
+			return err
+		}
+
+		return c.WriteTo(packet, broadcast)
+	}
+
+	netipAddr, ok := netip.AddrFromSlice(address)
+	if !ok {
+		return fmt.Errorf("Invalid IPv6 address: %v", address)
+	}
+
+	conn, _, err := ndp.Listen(ifi, ndp.LinkLocal)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	advertisement := &ndp.NeighborAdvertisement{
+		Override:      true,
+		TargetAddress: netipAddr,
+		Options: []ndp.Option{
+			&ndp.LinkLayerAddress{
+				Direction: ndp.Target,
+				Addr:      ifi.HardwareAddr,
+			},
+		},
+	}
+
+	return conn.WriteTo(advertisement, nil, netip.MustParseAddr("ff02::1"))
+}
+
+// floatingIPTargetsApplied holds the instance addresses the floating IPs of each network (keyed by network ID)
+// are currently forwarded to on this member.
+var floatingIPTargetsApplied = map[int64]map[string]net.IP{}
+var floatingIPTargetsAppliedMu sync.Mutex
+
+// floatingIPLockName returns the lock name used when applying the floating IPs of a network.
+func floatingIPLockName(networkID int64) string {
+	return fmt.Sprintf("network_floating_ips_%d", networkID)
+}
+
+// floatingIPBGPOwner returns the BGP prefix owner of a floating IP.
+func floatingIPBGPOwner(networkID int64, address string) string {
+	return fmt.Sprintf("network_%d_floating_ip_%s", networkID, address)
+}
+
+// floatingIPTargetsGet returns a copy of the applied floating IP targets of the network and whether they're known.
+func floatingIPTargetsGet(networkID int64) (map[string]net.IP, bool) {
+	floatingIPTargetsAppliedMu.Lock()
+	defer floatingIPTargetsAppliedMu.Unlock()
+
+	targets, found := floatingIPTargetsApplied[networkID]
+	if !found {
+		return nil, false
+	}
+
+	return maps.Clone(targets), true
+}
+
+// floatingIPTargetsSet replaces the applied floating IP targets of the network.
+func floatingIPTargetsSet(networkID int64, targets map[string]net.IP) {
+	floatingIPTargetsAppliedMu.Lock()
+	defer floatingIPTargetsAppliedMu.Unlock()
+
+	floatingIPTargetsApplied[networkID] = maps.Clone(targets)
+}
+
+// floatingIPTargetsDelete forgets the applied floating IP targets of the network.
+func floatingIPTargetsDelete(networkID int64) {
+	floatingIPTargetsAppliedMu.Lock()
+	defer floatingIPTargetsAppliedMu.Unlock()
+
+	delete(floatingIPTargetsApplied, networkID)
+}
+
+// floatingIPTargetsUpdate records the new targets of the floating IPs in the applied targets of the network and
+// returns the floating IPs whose target changed. A missing target means the floating IP isn't forwarded anymore.
+func floatingIPTargetsUpdate(networkID int64, floatingIPs []api.NetworkFloatingIP, targets map[string]net.IP) []api.NetworkFloatingIP {
+	floatingIPTargetsAppliedMu.Lock()
+	defer floatingIPTargetsAppliedMu.Unlock()
+
+	applied, found := floatingIPTargetsApplied[networkID]
+	if !found {
+		applied = map[string]net.IP{}
+		floatingIPTargetsApplied[networkID] = applied
+	}
+
+	var changed []api.NetworkFloatingIP
+	for _, floatingIP := range floatingIPs {
+		target := targets[floatingIP.Address]
+		if target.Equal(applied[floatingIP.Address]) {
+			continue
+		}
+
+		if target == nil {
+			delete(applied, floatingIP.Address)
+		} else {
+			applied[floatingIP.Address] = target
+		}
+
+		changed = append(changed, floatingIP)
+	}
+
+	return changed
+}
@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
+	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
 	"github.com/lxc/incus/v6/shared/util"
 )
 
@@ -101,3 +106,81 @@ func DetachInterface(s *state.State, bridgeName string, devName string) error {
 
 	return nil
 }
+
+// bridgeBootMounts tracks the custom storage volume mounted for the boot file services of each bridge.
+var bridgeBootMounts = map[string]string{}
+var bridgeBootMountsMu sync.Mutex
+
+// bridgeBootVolume returns the pool, project and name of the custom storage volume serving the boot files.
+func bridgeBootVolume(s *state.State, networkProject string, source string) (storagePools.Pool, string, string, error) {
+	poolName, volumeName, _ := strings.Cut(source, "/")
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("Failed loading storage pool %q: %w", poolName, err)
+	}
+
+	volumeProject, err := project.StorageVolumeProject(s.DB.Cluster, networkProject, db.StoragePoolVolumeTypeCustom)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return pool, volumeProject, volumeName, nil
+}
+
+// bridgeBootMount mounts the custom storage volume serving the boot files of the bridge and returns its path.
+// Any other volume previously mounted for the bridge is unmounted.
+func bridgeBootMount(s *state.State, networkProject string, networkName string, source string) (string, error) {
+	pool, volumeProject, volumeName, err := bridgeBootVolume(s, networkProject, source)
+	if err != nil {
+		return "", err
+	}
+
+	bridgeBootMountsMu.Lock()
+	defer bridgeBootMountsMu.Unlock()
+
+	if bridgeBootMounts[networkName] != source {
+		err = bridgeBootUnmountLocked(s, networkProject, networkName)
+		if err != nil {
+			return "", err
+		}
+
+		_, err = pool.MountCustomVolume(volumeProject, volumeName, nil)
+		if err != nil {
+			return "", fmt.Errorf("Failed mounting storage volume %q: %w", source, err)
+		}
+
+		bridgeBootMounts[networkName] = source
+	}
+
+	return storageDrivers.GetVolumeMountPath(pool.Name(), storageDrivers.VolumeTypeCustom, project.StorageVolume(volumeProject, volumeName)), nil
+}
+
+// bridgeBootUnmount unmounts the custom storage volume serving the boot files of the bridge (if any).
+func bridgeBootUnmount(s *state.State, networkProject string, networkName string) error {
+	bridgeBootMountsMu.Lock()
+	defer bridgeBootMountsMu.Unlock()
+
+	return bridgeBootUnmountLocked(s, networkProject, networkName)
+}
+
+func bridgeBootUnmountLocked(s *state.State, networkProject string, networkName string) error {
+	source, ok := bridgeBootMounts[networkName]
+	if !ok {
+		return nil
+	}
+
+	pool, volumeProject, volumeName, err := bridgeBootVolume(s, networkProject, source)
+	if err != nil {
+		return err
+	}
+
+	_, err = pool.UnmountCustomVolume(volumeProject, volumeName, nil)
+	if err != nil {
+		return fmt.Errorf("Failed unmounting storage volume %q: %w", source, err)
+	}
+
+	delete(bridgeBootMounts, networkName)
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/network/dhcpv6pd"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// prefixDelegationPool returns the IPv6 prefix delegation pool and delegated prefix length from the network config.
+func prefixDelegationPool(config map[string]string) (*net.IPNet, int, error) {
+	_, pool, err := net.ParseCIDR(config["ipv6.dhcp.pd.prefix"])
+	if err != nil {
+		return nil, 0, fmt.Errorf("Failed parsing ipv6.dhcp.pd.prefix: %w", err)
+	}
+
+	prefixLen := 64
+	if config["ipv6.dhcp.pd.size"] != "" {
+		prefixLen, err = strconv.Atoi(config["ipv6.dhcp.pd.size"])
+		if err != nil {
+			return nil, 0, fmt.Errorf("Failed parsing ipv6.dhcp.pd.size: %w", err)
+		}
+	}
+
+	return pool, prefixLen, nil
+}
+
+// prefixDelegationValidate checks the IPv6 prefix delegation pool against the rest of the network config.
+func prefixDelegationValidate(config map[string]string) error {
+	if config["ipv6.dhcp.pd.prefix"] == "" {
+		return nil
+	}
+
+	if util.IsNoneOrEmpty(config["ipv6.address"]) {
+		return errors.New(`"ipv6.dhcp.pd.prefix" requires "ipv6.address" to be set`)
+	}
+
+	if util.IsFalse(config["ipv6.dhcp"]) {
+		return errors.New(`"ipv6.dhcp.pd.prefix" requires "ipv6.dhcp" to be enabled`)
+	}
+
+	pdPool, pdLen, err := prefixDelegationPool(config)
+	if err != nil {
+		return err
+	}
+
+	poolLen, _ := pdPool.Mask.Size()
+	if pdLen < poolLen {
+		return fmt.Errorf(`"ipv6.dhcp.pd.size" (%d) cannot be shorter than the "ipv6.dhcp.pd.prefix" pool (/%d)`, pdLen, poolLen)
+	}
+
+	ipv6Net, _ := ParseIPCIDRToNet(config["ipv6.address"])
+	if ipv6Net != nil && (SubnetContains(pdPool, ipv6Net) || SubnetContains(ipv6Net, pdPool)) {
+		return errors.New(`"ipv6.dhcp.pd.prefix" cannot overlap with the "ipv6.address" subnet`)
+	}
+
+	return nil
+}
+
+// prefixDelegationStore records the IPv6 prefixes delegated on a network in the cluster database, so that
+// the members of a cluster don't delegate the same prefix.
+type prefixDelegationStore struct {
+	state     *state.State
+	networkID int64
+	pool      *net.IPNet
+	prefixLen int
+}
+
+// newPrefixDelegationStore returns the store of the prefixes delegated from the pool configured on the network.
+func newPrefixDelegationStore(s *state.State, networkID int64, config map[string]string) (*prefixDelegationStore, error) {
+	pool, prefixLen, err := prefixDelegationPool(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &prefixDelegationStore{state: s, networkID: networkID, pool: pool, prefixLen: prefixLen}, nil
+}
+
+// inPool returns whether the prefix can be delegated with the current configuration.
+func (s *prefixDelegationStore) inPool(prefix string) bool {
+	_, subnet, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return false
+	}
+
+	ones, _ := subnet.Mask.Size()
+
+	return ones == s.prefixLen && s.pool.Contains(subnet.IP)
+}
+
+// Leases returns the delegations handled by this member.
+func (s *prefixDelegationStore) Leases() ([]dhcpv6pd.Lease, error) {
+	var delegations []db.NetworkPrefixDelegation
+
+	err := s.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		delegations, err = tx.GetNetworkPrefixDelegations(ctx, s.networkID, true)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	leases := make([]dhcpv6pd.Lease, 0, len(delegations))
+	for _, delegation := range delegations {
+		leases = append(leases, dhcpv6pd.Lease{
+			DUID:    delegation.DUID,
+			IAID:    delegation.IAID,
+			Prefix:  delegation.Prefix,
+			NextHop: delegation.NextHop,
+			Hwaddr:  delegation.Hwaddr,
+			Expiry:  delegation.Expiry,
+		})
+	}
+
+	return leases, nil
+}
+
+// Lease returns the delegation of the client's identity association, allocating the first prefix of the pool
+// not delegated by any member if needed. The allocation and recording happen in a single transaction.
+func (s *prefixDelegationStore) Lease(lease dhcpv6pd.Lease, commit bool) (*dhcpv6pd.Lease, error) {
+	err := s.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		delegations, err := tx.GetNetworkPrefixDelegations(ctx, s.networkID, false)
+		if err != nil {
+			return err
+		}
+
+		lease.Prefix = ""
+		used := make([]string, 0, len(delegations))
+		for _, delegation := range delegations {
+			if delegation.DUID == lease.DUID && delegation.IAID == lease.IAID && s.inPool(delegation.Prefix) {
+				lease.Prefix = delegation.Prefix
+				continue
+			}
+
+			used = append(used, delegation.Prefix)
+		}
+
+		if lease.Prefix == "" {
+			prefix, err := dhcpv6pd.AllocatePrefix(s.pool, s.prefixLen, used)
+			if err != nil {
+				return err
+			}
+
+			lease.Prefix = prefix.String()
+		}
+
+		if !commit {
+			return nil
+		}
+
+		return tx.UpsertNetworkPrefixDelegation(ctx, s.networkID, db.NetworkPrefixDelegation{
+			Prefix:  lease.Prefix,
+			DUID:    lease.DUID,
+			IAID:    lease.IAID,
+			Hwaddr:  lease.Hwaddr,
+			NextHop: lease.NextHop,
+			Expiry:  lease.Expiry,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &lease, nil
+}
+
+// Release removes the delegation of the client's identity association.
+func (s *prefixDelegationStore) Release(duid string, iaid uint32) error {
+	return s.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteNetworkPrefixDelegation(ctx, s.networkID, duid, iaid)
+	})
+}
@@ -0,0 +1,379 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/internal/server/state"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+// projectLimitsDevicePrefixes maps the traffic directions to the prefix of the devices shaping them.
+// The ingress direction is the traffic received by the instances, the egress direction the traffic they send.
+var projectLimitsDevicePrefixes = map[string]string{
+	"ingress": "incin-",
+	"egress":  "incout-",
+}
+
+// projectLimitsRates records the rates currently applied to the project shaping devices of this server.
+var projectLimitsRates = map[string]int64{}
+
+var projectLimitsMu sync.Mutex
+
+// ProjectLimitsDeviceName returns the name of the device shaping the traffic of the project in the given direction.
+func ProjectLimitsDeviceName(projectName string, direction string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(projectName))
+
+	return fmt.Sprintf("%s%08x", projectLimitsDevicePrefixes[direction], hash.Sum32())
+}
+
+// ProjectLimitsDevice returns the device which the NICs of the project must redirect their traffic in the given
+// direction to, or an empty string if the project doesn't limit that direction.
+func ProjectLimitsDevice(projectName string, config map[string]string, direction string) string {
+	if config[fmt.Sprintf("limits.network.%s", direction)] == "" {
+		return ""
+	}
+
+	return ProjectLimitsDeviceName(projectName, direction)
+}
+
+// ProjectLimitsSetup creates or updates the devices shaping the aggregate traffic of the NICs of the project on this
+// server according to its "limits.network.ingress" and "limits.network.egress" settings.
+//
+// Returns true if shaping got enabled or disabled for a direction, in which case the traffic control rules of the
+// running NICs of the project must be refreshed so that they redirect their traffic to the shaping device or stop
+// doing so, before calling ProjectLimitsCleanup.
+func ProjectLimitsSetup(projectName string, config map[string]string) (bool, error) {
+	projectLimitsMu.Lock()
+	defer projectLimitsMu.Unlock()
+
+	changed := false
+
+	for direction := range projectLimitsDevicePrefixes {
+		devName := ProjectLimitsDeviceName(projectName, direction)
+
+		var rate int64
+		if config[fmt.Sprintf("limits.network.%s", direction)] != "" {
+			var err error
+
+			rate, err = units.ParseBitSizeString(config[fmt.Sprintf("limits.network.%s", direction)])
+			if err != nil {
+				return false, err
+			}
+		}
+
+		currentRate, found := projectLimitsRates[devName]
+		if found && currentRate == rate && (rate == 0 || InterfaceExists(devName)) {
+			continue
+		}
+
+		// Stop shaping. The device is only removed by ProjectLimitsCleanup, once the running NICs were refreshed
+		// and don't redirect their traffic to it anymore.
+		if rate == 0 {
+			if (found && currentRate > 0) || InterfaceExists(devName) {
+				changed = true
+			}
+
+			projectLimitsRates[devName] = 0
+
+			continue
+		}
+
+		exists := InterfaceExists(devName)
+		if !exists {
+			ifb := &ip.Ifb{Link: ip.Link{Name: devName}}
+			err := ifb.Add()
+			if err != nil {
+				return false, fmt.Errorf("Failed to create project shaping device %q: %w", devName, err)
+			}
+		}
+
+		link := &ip.Link{Name: devName}
+		err := link.SetUp()
+		if err != nil {
+			return false, fmt.Errorf("Failed to bring up project shaping device %q: %w", devName, err)
+		}
+
+		qdiscHTB := &ip.QdiscHTB{Qdisc: ip.Qdisc{Dev: devName, Handle: "1:0", Parent: "root"}}
+		err = qdiscHTB.Delete()
+		if err != nil && !errors.Is(err, unix.ENOENT) {
+			return false, err
+		}
+
+		qdiscHTB = &ip.QdiscHTB{Qdisc: ip.Qdisc{Dev: devName, Handle: "1:0", Parent: "root"}, Default: 0x10}
+		err = qdiscHTB.Add()
+		if err != nil {
+			return false, fmt.Errorf("Failed to create root tc qdisc: %w", err)
+		}
+
+		classHTB := &ip.ClassHTB{Class: ip.Class{Dev: devName, Parent: "1:0", Classid: "1:10"}, Rate: fmt.Sprintf("%dbit", rate)}
+		err = classHTB.Add()
+		if err != nil {
+			return false, fmt.Errorf("Failed to create limit tc class: %w", err)
+		}
+
+		if currentRate == 0 || !exists {
+			changed = true
+		}
+
+		projectLimitsRates[devName] = rate
+	}
+
+	return changed, nil
+}
+
+// ProjectLimitsCleanup removes the shaping devices of the projects which don't exist anymore or don't limit the
+// direction they shape anymore.
+func ProjectLimitsCleanup(projectNames []string) error {
+	projectLimitsMu.Lock()
+	defer projectLimitsMu.Unlock()
+
+	devNames := []string{}
+	for _, projectName := range projectNames {
+		for direction := range projectLimitsDevicePrefixes {
+			devNames = append(devNames, ProjectLimitsDeviceName(projectName, direction))
+		}
+	}
+
+	entries, err := os.ReadDir("/sys/class/net")
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		devName := entry.Name()
+		if slices.Contains(devNames, devName) && projectLimitsRates[devName] > 0 {
+			continue
+		}
+
+		for _, prefix := range projectLimitsDevicePrefixes {
+			if !strings.HasPrefix(devName, prefix) {
+				continue
+			}
+
+			err := InterfaceRemove(devName)
+			if err != nil {
+				return fmt.Errorf("Failed to remove project shaping device %q: %w", devName, err)
+			}
+
+			delete(projectLimitsRates, devName)
+		}
+	}
+
+	return nil
+}
+
+// projectCountersSample is the last counters read from the host side interface of a NIC.
+type projectCountersSample struct {
+	Project  string                  `json:"project"`
+	Ifindex  int64                   `json:"ifindex"`
+	Counters api.ProjectStateNetwork `json:"counters"`
+}
+
+// projectCountersSamples holds the counters of the host side interfaces of the NICs, keyed by interface name, as
+// last accounted to their project. It is persisted so that no traffic is lost when the daemon restarts.
+var projectCountersSamples map[string]projectCountersSample
+
+var projectCountersMu sync.Mutex
+
+// projectCountersPath returns the path of the file the last accounted counters are persisted to.
+func projectCountersPath() string {
+	return internalUtil.VarPath("project-network-counters.json")
+}
+
+// projectCountersLoad loads the persisted counters if not loaded yet.
+// Returns true if there were none, in which case the counters read next may already have been accounted for and
+// must only be used as the baseline.
+func projectCountersLoad() bool {
+	if projectCountersSamples != nil {
+		return false
+	}
+
+	projectCountersSamples = map[string]projectCountersSample{}
+
+	content, err := os.ReadFile(projectCountersPath())
+	if err != nil {
+		return true
+	}
+
+	err = json.Unmarshal(content, &projectCountersSamples)
+	if err != nil {
+		projectCountersSamples = map[string]projectCountersSample{}
+		return true
+	}
+
+	return false
+}
+
+// projectCountersSave persists the last accounted counters.
+func projectCountersSave() error {
+	content, err := json.Marshal(projectCountersSamples)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(projectCountersPath(), content, 0o600)
+}
+
+// projectCountersRead reads the counters of the host side interface of a NIC.
+// The host side interface sends what the instance receives and receives what the instance sends.
+func projectCountersRead(projectName string, hostName string) (*projectCountersSample, error) {
+	values := map[string]int64{}
+
+	for _, name := range []string{"ifindex", "statistics/rx_bytes", "statistics/tx_bytes", "statistics/rx_packets", "statistics/tx_packets"} {
+		content, err := os.ReadFile(fmt.Sprintf("/sys/class/net/%s/%s", hostName, name))
+		if err != nil {
+			return nil, err
+		}
+
+		values[name], err = strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing %q of %q: %w", name, hostName, err)
+		}
+	}
+
+	return &projectCountersSample{
+		Project: projectName,
+		Ifindex: values["ifindex"],
+		Counters: api.ProjectStateNetwork{
+			BytesReceived:   values["statistics/tx_bytes"],
+			BytesSent:       values["statistics/rx_bytes"],
+			PacketsReceived: values["statistics/tx_packets"],
+			PacketsSent:     values["statistics/rx_packets"],
+		},
+	}, nil
+}
+
+// projectCountersDelta returns the traffic of the interface since the previous sample.
+// Everything is counted if the interface is new or was re-created since the previous sample.
+func projectCountersDelta(previous *projectCountersSample, sample projectCountersSample) api.ProjectStateNetwork {
+	delta := sample.Counters
+
+	if previous != nil && previous.Project == sample.Project && previous.Ifindex == sample.Ifindex && previous.Counters.BytesReceived <= sample.Counters.BytesReceived && previous.Counters.BytesSent <= sample.Counters.BytesSent {
+		delta.BytesReceived -= previous.Counters.BytesReceived
+		delta.BytesSent -= previous.Counters.BytesSent
+		delta.PacketsReceived -= previous.Counters.PacketsReceived
+		delta.PacketsSent -= previous.Counters.PacketsSent
+	}
+
+	return delta
+}
+
+// projectCountersAdd adds the traffic of each project to its cumulative counters.
+func projectCountersAdd(s *state.State, deltas map[string]api.ProjectStateNetwork) error {
+	return s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		for projectName, delta := range deltas {
+			if delta == (api.ProjectStateNetwork{}) {
+				continue
+			}
+
+			err := tx.AddProjectNetworkCounters(ctx, projectName, delta)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ProjectCountersUpdate reads the counters of the host side interfaces of the running local NICs (mapping the
+// interface names to their project), adds the traffic since they were last accounted for to the cumulative
+// counters of their project and persists the new counters. Interfaces which aren't given anymore are forgotten.
+func ProjectCountersUpdate(s *state.State, hostNames map[string]string) error {
+	projectCountersMu.Lock()
+	defer projectCountersMu.Unlock()
+
+	baseline := projectCountersLoad()
+
+	deltas := map[string]api.ProjectStateNetwork{}
+	samples := map[string]projectCountersSample{}
+
+	for hostName, projectName := range hostNames {
+		sample, err := projectCountersRead(projectName, hostName)
+		if err != nil {
+			logger.Warn("Failed reading NIC counters", logger.Ctx{"project": projectName, "interface": hostName, "err": err})
+			continue
+		}
+
+		samples[hostName] = *sample
+
+		if baseline {
+			continue
+		}
+
+		var previous *projectCountersSample
+		previousSample, found := projectCountersSamples[hostName]
+		if found {
+			previous = &previousSample
+		}
+
+		delta := projectCountersDelta(previous, *sample)
+
+		total := deltas[projectName]
+		total.BytesReceived += delta.BytesReceived
+		total.BytesSent += delta.BytesSent
+		total.PacketsReceived += delta.PacketsReceived
+		total.PacketsSent += delta.PacketsSent
+		deltas[projectName] = total
+	}
+
+	err := projectCountersAdd(s, deltas)
+	if err != nil {
+		return err
+	}
+
+	projectCountersSamples = samples
+
+	return projectCountersSave()
+}
+
+// ProjectCountersFlush adds the traffic of the host side interface of a NIC since it was last accounted for to the
+// cumulative counters of its project and forgets the interface.
+// It must be called when the NIC stops, before its host side interface goes away.
+func ProjectCountersFlush(s *state.State, projectName string, hostName string) error {
+	projectCountersMu.Lock()
+	defer projectCountersMu.Unlock()
+
+	baseline := projectCountersLoad()
+
+	previousSample, found := projectCountersSamples[hostName]
+	if !found && baseline {
+		return nil
+	}
+
+	sample, err := projectCountersRead(projectName, hostName)
+	if err != nil {
+		return err
+	}
+
+	var previous *projectCountersSample
+	if found {
+		previous = &previousSample
+	}
+
+	err = projectCountersAdd(s, map[string]api.ProjectStateNetwork{projectName: projectCountersDelta(previous, *sample)})
+	if err != nil {
+		return err
+	}
+
+	delete(projectCountersSamples, hostName)
+
+	return projectCountersSave()
+}
@@ -0,0 +1,55 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func Test_projectCountersDelta(t *testing.T) {
+	sample := projectCountersSample{
+		Project:  "p1",
+		Ifindex:  10,
+		Counters: api.ProjectStateNetwork{BytesReceived: 1000, BytesSent: 500, PacketsReceived: 10, PacketsSent: 5},
+	}
+
+	tests := []struct {
+		name     string
+		previous *projectCountersSample
+		want     api.ProjectStateNetwork
+	}{
+		{
+			name:     "New interface",
+			previous: nil,
+			want:     sample.Counters,
+		},
+		{
+			name:     "Same interface",
+			previous: &projectCountersSample{Project: "p1", Ifindex: 10, Counters: api.ProjectStateNetwork{BytesReceived: 400, BytesSent: 100, PacketsReceived: 4, PacketsSent: 1}},
+			want:     api.ProjectStateNetwork{BytesReceived: 600, BytesSent: 400, PacketsReceived: 6, PacketsSent: 4},
+		},
+		{
+			name:     "Re-created interface",
+			previous: &projectCountersSample{Project: "p1", Ifindex: 9, Counters: api.ProjectStateNetwork{BytesReceived: 400, BytesSent: 100}},
+			want:     sample.Counters,
+		},
+		{
+			name:     "Interface moved to another project",
+			previous: &projectCountersSample{Project: "p2", Ifindex: 10, Counters: api.ProjectStateNetwork{BytesReceived: 400, BytesSent: 100}},
+			want:     sample.Counters,
+		},
+		{
+			name:     "Counters reset",
+			previous: &projectCountersSample{Project: "p1", Ifindex: 10, Counters: api.ProjectStateNetwork{BytesReceived: 4000, BytesSent: 100}},
+			want:     sample.Counters,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, projectCountersDelta(tt.previous, sample))
+		})
+	}
+}
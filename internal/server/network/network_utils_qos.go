@@ -0,0 +1,193 @@
+package network
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+// qosDefaultClassMinor is the minor number of the class used for the traffic of the NICs not selecting a QoS class,
+// when the network doesn't define a class named "default".
+const qosDefaultClassMinor = 0xffff
+
+// qosDefaultClassMinRate is the rate guaranteed to the classes not setting one, as well as to the implicit default
+// class when the guaranteed rates of the other classes add up to the total rate of the network.
+const qosDefaultClassMinRate = 8000
+
+// qosRedirectPriority is the priority of the filters redirecting the traffic to the QoS device of the network,
+// the filters exempting the control traffic come first.
+const qosRedirectPriority = 4
+
+// qosValidRate validates a QoS bit rate.
+func qosValidRate(value string) error {
+	_, err := units.ParseBitSizeString(value)
+	return err
+}
+
+// qosClassRates returns the guaranteed and maximum bit rates of a QoS class, the maximum defaulting to the total rate
+// of the network.
+func qosClassRates(config map[string]string, className string, totalRate int64) (int64, int64, error) {
+	guaranteed := int64(qosDefaultClassMinRate)
+	if config[fmt.Sprintf("qos.class.%s.guaranteed", className)] != "" {
+		var err error
+
+		guaranteed, err = units.ParseBitSizeString(config[fmt.Sprintf("qos.class.%s.guaranteed", className)])
+		if err != nil {
+			return -1, -1, err
+		}
+	}
+
+	maxRate := totalRate
+	if config[fmt.Sprintf("qos.class.%s.max", className)] != "" {
+		var err error
+
+		maxRate, err = units.ParseBitSizeString(config[fmt.Sprintf("qos.class.%s.max", className)])
+		if err != nil {
+			return -1, -1, err
+		}
+	}
+
+	return guaranteed, maxRate, nil
+}
+
+// QoSClassNames returns the sorted names of the QoS classes defined in the network config.
+func QoSClassNames(config map[string]string) []string {
+	names := []string{}
+
+	for k := range config {
+		if !strings.HasPrefix(k, "qos.class.") {
+			continue
+		}
+
+		fields := strings.Split(k, ".")
+		if len(fields) != 4 || slices.Contains(names, fields[2]) {
+			continue
+		}
+
+		names = append(names, fields[2])
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// QoSClassExists returns whether the network config defines the QoS class.
+func QoSClassExists(config map[string]string, className string) bool {
+	return config["qos.rate"] != "" && slices.Contains(QoSClassNames(config), className)
+}
+
+// qosClassMinor returns the minor number of the traffic control class of a QoS class.
+// It is derived from the class name so that it stays the same across configuration changes.
+func qosClassMinor(className string) uint32 {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(className))
+
+	return 0x10 + hash.Sum32()%(0xfff0-0x10)
+}
+
+// QoSClassID returns the traffic control class ID which the traffic of the QoS class must be classified into.
+func QoSClassID(className string) uint32 {
+	return 1<<16 | qosClassMinor(className)
+}
+
+// QoSNICSetup classifies the traffic sent by the host interface of an instance NIC into the QoS class of its network
+// and marks it with the DSCP value of the class, if any. An empty class name clears the classification.
+func QoSNICSetup(s *state.State, projectName string, instanceName string, hostName string, networkConfig map[string]string, className string) error {
+	err := s.Firewall.InstanceClearQoS(projectName, instanceName, hostName)
+	if err != nil {
+		return err
+	}
+
+	if className == "" {
+		return nil
+	}
+
+	if !QoSClassExists(networkConfig, className) {
+		return fmt.Errorf("QoS class %q isn't defined on the network", className)
+	}
+
+	dscp := -1
+	if networkConfig[fmt.Sprintf("qos.class.%s.dscp", className)] != "" {
+		dscp, err = strconv.Atoi(networkConfig[fmt.Sprintf("qos.class.%s.dscp", className)])
+		if err != nil {
+			return fmt.Errorf("Invalid DSCP value for QoS class %q: %w", className, err)
+		}
+	}
+
+	return s.Firewall.InstanceSetupQoS(projectName, instanceName, hostName, QoSClassID(className), dscp)
+}
+
+// qosConfig returns the QoS keys of the network config.
+func qosConfig(config map[string]string) map[string]string {
+	qos := map[string]string{}
+	for k, v := range config {
+		if strings.HasPrefix(k, "qos.") {
+			qos[k] = v
+		}
+	}
+
+	return qos
+}
+
+// bridgeQoSDevice returns the name of the device shaping the traffic of the QoS classes of the network.
+func bridgeQoSDevice(networkName string) string {
+	return fmt.Sprintf("%s-qos", networkName)
+}
+
+// qosControlFilters returns the filters of the device exempting the control traffic of the network (ARP, DHCP, DHCPv6
+// and neighbor discovery) as well as the traffic sent to the host addresses from the redirect to its QoS device.
+// Matching packets are passed on unshaped, classified into the parent when it's a HTB qdisc.
+func qosControlFilters(dev string, parent string, hostAddresses []net.IP) []*ip.U32Filter {
+	newFilter := func(protocol string, priority uint16, keys ...ip.U32Key) *ip.U32Filter {
+		return &ip.U32Filter{Filter: ip.Filter{Dev: dev, Parent: parent, Protocol: protocol, Flowid: parent, Priority: priority}, Keys: keys}
+	}
+
+	addressKeys := func(offset int32, address net.IP) []ip.U32Key {
+		keys := []ip.U32Key{}
+		for i := 0; i < len(address); i += 4 {
+			keys = append(keys, ip.U32Key{Offset: offset + int32(i), Value: binary.BigEndian.Uint32(address[i : i+4]), Mask: 0xffffffff})
+		}
+
+		return keys
+	}
+
+	// Only IPv4 packets without options are matched by the port of their transport header.
+	ipv4Options := ip.U32Key{Offset: 0, Value: 0x05000000, Mask: 0x0f000000}
+	ipv4UDP := ip.U32Key{Offset: 8, Value: unix.IPPROTO_UDP << 16, Mask: 0x00ff0000}
+	ipv6UDP := ip.U32Key{Offset: 4, Value: unix.IPPROTO_UDP << 8, Mask: 0x0000ff00}
+	ipv6ICMP := ip.U32Key{Offset: 4, Value: unix.IPPROTO_ICMPV6 << 8, Mask: 0x0000ff00}
+
+	filters := []*ip.U32Filter{
+		newFilter("arp", 1, ip.U32Key{}),
+		newFilter("ip", 2, ipv4Options, ipv4UDP, ip.U32Key{Offset: 20, Value: 67, Mask: 0x0000ffff}),
+		newFilter("ip", 2, ipv4Options, ipv4UDP, ip.U32Key{Offset: 20, Value: 68, Mask: 0x0000ffff}),
+		newFilter("ipv6", 3, ipv6UDP, ip.U32Key{Offset: 40, Value: 546, Mask: 0x0000ffff}),
+		newFilter("ipv6", 3, ipv6UDP, ip.U32Key{Offset: 40, Value: 547, Mask: 0x0000ffff}),
+	}
+
+	// Router and neighbor solicitations and advertisements, as well as redirects.
+	for icmpType := uint32(133); icmpType <= 137; icmpType++ {
+		filters = append(filters, newFilter("ipv6", 3, ipv6ICMP, ip.U32Key{Offset: 40, Value: icmpType << 24, Mask: 0xff000000}))
+	}
+
+	for _, address := range hostAddresses {
+		if address.To4() != nil {
+			filters = append(filters, newFilter("ip", 2, addressKeys(16, address.To4())...))
+		} else {
+			filters = append(filters, newFilter("ipv6", 3, addressKeys(24, address.To16())...))
+		}
+	}
+
+	return filters
+}
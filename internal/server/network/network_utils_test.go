@@ -4,8 +4,13 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
 
 	"github.com/lxc/incus/v6/internal/iprange"
+	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/shared/api"
 )
 
 func Example_parseIPRange() {
@@ -180,3 +185,125 @@ func Example_complementRanges() {
 	// Range2: 10.1.1.1-10.1.1.9, 10.1.1.101-10.1.1.199, 10.1.1.231-10.1.1.254
 	// Range3: 10.1.1.1-10.1.1.9, 10.1.1.26-10.1.1.254
 }
+
+func Test_floatingIPTargetsUpdate(t *testing.T) {
+	const networkID = -1
+	defer floatingIPTargetsDelete(networkID)
+
+	floatingIPs := []api.NetworkFloatingIP{
+		{Address: "192.0.2.10"},
+		{Address: "2001:db8::10"},
+	}
+
+	steps := []struct {
+		name    string
+		targets map[string]net.IP
+		want    []string
+	}{
+		{
+			name:    "Initial targets",
+			targets: map[string]net.IP{"192.0.2.10": net.ParseIP("10.0.0.2"), "2001:db8::10": net.ParseIP("fd00::2")},
+			want:    []string{"192.0.2.10", "2001:db8::10"},
+		},
+		{
+			name:    "Unchanged targets",
+			targets: map[string]net.IP{"192.0.2.10": net.ParseIP("10.0.0.2"), "2001:db8::10": net.ParseIP("fd00::2")},
+			want:    nil,
+		},
+		{
+			name:    "Changed IPv4 target",
+			targets: map[string]net.IP{"192.0.2.10": net.ParseIP("10.0.0.3"), "2001:db8::10": net.ParseIP("fd00::2")},
+			want:    []string{"192.0.2.10"},
+		},
+		{
+			name:    "Removed IPv6 target",
+			targets: map[string]net.IP{"192.0.2.10": net.ParseIP("10.0.0.3")},
+			want:    []string{"2001:db8::10"},
+		},
+		{
+			name:    "Still removed IPv6 target",
+			targets: map[string]net.IP{"192.0.2.10": net.ParseIP("10.0.0.3")},
+			want:    nil,
+		},
+	}
+
+	for _, step := range steps {
+		changed := floatingIPTargetsUpdate(networkID, floatingIPs, step.targets)
+
+		var got []string
+		for _, floatingIP := range changed {
+			got = append(got, floatingIP.Address)
+		}
+
+		assert.Equal(t, step.want, got, step.name)
+
+		applied, found := floatingIPTargetsGet(networkID)
+		assert.True(t, found, step.name)
+		assert.Equal(t, len(step.targets), len(applied), step.name)
+	}
+}
+
+func Test_floatingIPValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		wantErr bool
+	}{
+		{name: "Empty config", config: nil},
+		{name: "Valid config", config: map[string]string{"announce.interface": "eth0", "announce.bgp": "true", "target.address": "192.0.2.1", "user.foo": "bar"}},
+		{name: "Invalid interface", config: map[string]string{"announce.interface": "eth0/1"}, wantErr: true},
+		{name: "Invalid BGP flag", config: map[string]string{"announce.bgp": "maybe"}, wantErr: true},
+		{name: "Invalid target address", config: map[string]string{"target.address": "192.0.2.1/24"}, wantErr: true},
+		{name: "Unknown key", config: map[string]string{"foo": "bar"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := floatingIPValidateConfig(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_QoSClassNames(t *testing.T) {
+	config := map[string]string{
+		"qos.rate":                 "100Mbit",
+		"qos.class.web.guaranteed": "10Mbit",
+		"qos.class.web.max":        "50Mbit",
+		"qos.class.db.priority":    "1",
+		"qos.class.invalid":        "1",
+		"ipv4.address":             "10.0.0.1/24",
+	}
+
+	assert.Equal(t, []string{"db", "web"}, QoSClassNames(config))
+	assert.Equal(t, []string{}, QoSClassNames(map[string]string{"qos.rate": "100Mbit"}))
+
+	assert.True(t, QoSClassExists(config, "web"))
+	assert.False(t, QoSClassExists(config, "invalid"))
+	assert.False(t, QoSClassExists(map[string]string{"qos.class.web.max": "50Mbit"}, "web"))
+}
+
+func Test_qosControlFilters(t *testing.T) {
+	filters := qosControlFilters("incusbr0", "ffff:0", []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("fd00::1")})
+
+	// ARP, DHCP, DHCPv6, neighbor discovery and one filter per host address.
+	assert.Len(t, filters, 12)
+
+	for _, filter := range filters {
+		assert.Equal(t, "incusbr0", filter.Dev)
+		assert.Equal(t, "ffff:0", filter.Flowid)
+		assert.Less(t, filter.Priority, uint16(qosRedirectPriority))
+		assert.Empty(t, filter.Actions)
+	}
+
+	assert.Equal(t, ip.U32Key{Offset: 16, Value: 0x0a000001, Mask: 0xffffffff}, filters[10].Keys[0])
+	assert.Equal(t, "ipv6", filters[11].Protocol)
+	assert.Len(t, filters[11].Keys, 4)
+	assert.Equal(t, ip.U32Key{Offset: 36, Value: 1, Mask: 0xffffffff}, filters[11].Keys[3])
+
+	assert.Len(t, qosControlFilters("eth0", "1:0", nil), 10)
+}
@@ -83,6 +83,8 @@ const (
 	ovnExtIDIncusProjectID  = "incus_project_id"
 	ovnExtIDIncusPortGroup  = "incus_port_group"
 	ovnExtIDIncusLocation   = "incus_location"
+	ovnExtIDIncusACLRule    = "incus_acl_rule"
+	ovnExtIDIncusDHCPBIOS   = "incus_dhcp_bios"
 )
 
 // OVNIPv6RAOpts IPv6 router advertisements options that can be applied to a router.
@@ -100,6 +102,7 @@ type OVNIPv6RAOpts struct {
 type OVNDHCPOptsSet struct {
 	UUID OVNDHCPOptionsUUID
 	CIDR *net.IPNet
+	BIOS bool // Whether the options are for the clients booting with BIOS firmware.
 }
 
 // OVNDHCPv4Opts IPv4 DHCP options that can be applied to a switch port.
@@ -114,6 +117,10 @@ type OVNDHCPv4Opts struct {
 	Netmask            string
 	DNSSearchList      []string
 	StaticRoutes       string
+	BootServer         net.IP
+	BootFilename       string
+	BootFilenameIPXE   string
+	BIOS               bool // Whether the options are for the clients booting with BIOS firmware.
 }
 
 // OVNDHCPv6Opts IPv6 DHCP option set that can be created (and then applied to a switch port by resulting ID).
@@ -146,6 +153,7 @@ type OVNACLRule struct {
 	Priority  int    // Priority (between 0 and 32767, inclusive). Higher values take precedence.
 	Log       bool   // Whether or not to log matched packets.
 	LogName   string // Log label name (requires Log be true).
+	Counter   string // Name used to track the hit counters of the rule (optional).
 }
 
 // OVNQoSRule represents a QoS rule that can be added to a logical switch.
@@ -157,6 +165,14 @@ type OVNQoSRule struct {
 	Priority  int
 }
 
+// OVNMirror represents a port mirror sending a copy of the traffic of a logical switch port to a remote sink.
+type OVNMirror struct {
+	Filter string // Either "from-lport" or "to-lport".
+	Type   string // Either "gre" or "erspan".
+	Sink   net.IP
+	Index  int
+}
+
 // OVNLoadBalancerTarget represents an OVN load balancer Virtual IP target.
 type OVNLoadBalancerTarget struct {
 	Address net.IP
@@ -189,6 +205,7 @@ type OVNRouterRoute struct {
 	NextHop net.IP
 	Port    OVNRouterPort
 	Discard bool
+	BFD     bool
 }
 
 // OVNRouterPolicy represents a router policy.
@@ -292,6 +309,29 @@ func (o *NB) DeleteLogicalRouter(ctx context.Context, routerName OVNRouter) erro
 		return err
 	}
 
+	// Delete the BFD sessions used by the router's static routes.
+	for _, uuid := range logicalRouter.StaticRoutes {
+		route := ovnNB.LogicalRouterStaticRoute{
+			UUID: uuid,
+		}
+
+		err = o.get(ctx, &route)
+		if err != nil {
+			return err
+		}
+
+		if route.BFD == nil {
+			continue
+		}
+
+		deleteOps, err := o.client.Where(&ovnNB.BFD{UUID: *route.BFD}).Delete()
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, deleteOps...)
+	}
+
 	// Apply the changes.
 	resp, err := o.client.Transact(ctx, operations...)
 	if err != nil {
@@ -680,6 +720,32 @@ func (o *NB) CreateLogicalRouterRoute(ctx context.Context, routerName OVNRouter,
 			staticRoute.Nexthop = route.NextHop.String()
 		}
 
+		// Monitor the next hop using BFD.
+		if route.BFD && !route.Discard {
+			bfd := ovnNB.BFD{
+				LogicalPort: string(route.Port),
+				DstIP:       route.NextHop.String(),
+			}
+
+			err = o.get(ctx, &bfd)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return err
+			}
+
+			if bfd.UUID == "" {
+				bfd.UUID = fmt.Sprintf("bfd_%d", i)
+
+				createOps, err := o.client.Create(&bfd)
+				if err != nil {
+					return err
+				}
+
+				operations = append(operations, createOps...)
+			}
+
+			staticRoute.BFD = &bfd.UUID
+		}
+
 		createOps, err := o.client.Create(&staticRoute)
 		if err != nil {
 			return err
@@ -718,8 +784,42 @@ func (o *NB) CreateLogicalRouterRoute(ctx context.Context, routerName OVNRouter,
 	return nil
 }
 
-// DeleteLogicalRouterRoute deletes a static route from the logical router.
+// DeleteLogicalRouterRoute deletes the static routes matching the prefixes from the logical router.
 func (o *NB) DeleteLogicalRouterRoute(ctx context.Context, routerName OVNRouter, prefixes ...net.IPNet) error {
+	return o.deleteLogicalRouterRoutes(ctx, routerName, func(route ovnNB.LogicalRouterStaticRoute) bool {
+		for _, prefix := range prefixes {
+			// Normal CIDR entry or IP-only entry.
+			ones, bits := prefix.Mask.Size()
+			if route.IPPrefix == prefix.String() || (ones == bits && route.IPPrefix == prefix.IP.String()) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// DeleteLogicalRouterRouteNextHop deletes the static routes matching both the prefix and the next hop of one
+// of the provided routes from the logical router. This allows removing a single ECMP route.
+func (o *NB) DeleteLogicalRouterRouteNextHop(ctx context.Context, routerName OVNRouter, routes ...OVNRouterRoute) error {
+	return o.deleteLogicalRouterRoutes(ctx, routerName, func(existing ovnNB.LogicalRouterStaticRoute) bool {
+		for _, route := range routes {
+			if existing.IPPrefix != route.Prefix.String() {
+				continue
+			}
+
+			if route.Discard && existing.Nexthop == "discard" || !route.Discard && existing.Nexthop == route.NextHop.String() {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// deleteLogicalRouterRoutes deletes the static routes selected by the match function from the logical router,
+// along with the BFD sessions which are no longer used.
+func (o *NB) deleteLogicalRouterRoutes(ctx context.Context, routerName OVNRouter, match func(route ovnNB.LogicalRouterStaticRoute) bool) error {
 	// Get the logical router.
 	logicalRouter, err := o.GetLogicalRouter(ctx, routerName)
 	if err != nil {
@@ -743,26 +843,11 @@ func (o *NB) DeleteLogicalRouterRoute(ctx context.Context, routerName OVNRouter,
 
 	// Delete the requested routes.
 	operations := []ovsdb.Operation{}
-	for _, prefix := range prefixes {
-		var route ovnNB.LogicalRouterStaticRoute
-
-		// Look for a matching entry.
-		for _, existing := range existingRoutes {
-			// Normal CIDR entry.
-			if existing.IPPrefix == prefix.String() {
-				route = existing
-				break
-			}
-
-			// IP-only entry.
-			ones, bits := prefix.Mask.Size()
-			if ones == bits && existing.IPPrefix == prefix.IP.String() {
-				route = existing
-				break
-			}
-		}
-
-		if route.UUID == "" {
+	deletedRoutes := map[string]bool{}
+	deletedBFDs := []string{}
+	for _, route := range existingRoutes {
+		// Look for matching entries, there may be several when using ECMP.
+		if !match(route) {
 			continue
 		}
 
@@ -773,6 +858,11 @@ func (o *NB) DeleteLogicalRouterRoute(ctx context.Context, routerName OVNRouter,
 		}
 
 		operations = append(operations, deleteOps...)
+		deletedRoutes[route.UUID] = true
+
+		if route.BFD != nil && !slices.Contains(deletedBFDs, *route.BFD) {
+			deletedBFDs = append(deletedBFDs, *route.BFD)
+		}
 
 		// Remove from the router.
 		updateOps, err := o.client.Where(logicalRouter).Mutate(logicalRouter, ovsModel.Mutation{
@@ -787,6 +877,28 @@ func (o *NB) DeleteLogicalRouterRoute(ctx context.Context, routerName OVNRouter,
 		operations = append(operations, updateOps...)
 	}
 
+	// Delete the BFD sessions which are no longer used by any route.
+	for _, bfdUUID := range deletedBFDs {
+		inUse := false
+		for _, existing := range existingRoutes {
+			if !deletedRoutes[existing.UUID] && existing.BFD != nil && *existing.BFD == bfdUUID {
+				inUse = true
+				break
+			}
+		}
+
+		if inUse {
+			continue
+		}
+
+		deleteOps, err := o.client.Where(&ovnNB.BFD{UUID: bfdUUID}).Delete()
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, deleteOps...)
+	}
+
 	if len(operations) == 0 {
 		return nil
 	}
@@ -805,6 +917,29 @@ func (o *NB) DeleteLogicalRouterRoute(ctx context.Context, routerName OVNRouter,
 	return nil
 }
 
+// GetLogicalRouterPortBFD returns the status of the BFD sessions on the logical router port, keyed by destination IP.
+func (o *NB) GetLogicalRouterPortBFD(ctx context.Context, portName OVNRouterPort) (map[string]string, error) {
+	bfds := []ovnNB.BFD{}
+	err := o.client.WhereCache(func(bfd *ovnNB.BFD) bool {
+		return bfd.LogicalPort == string(portName)
+	}).List(ctx, &bfds)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make(map[string]string, len(bfds))
+	for _, bfd := range bfds {
+		if bfd.Status == nil {
+			status[bfd.DstIP] = ovnNB.BFDStatusAdminDown
+			continue
+		}
+
+		status[bfd.DstIP] = *bfd.Status
+	}
+
+	return status, nil
+}
+
 // GetLogicalRouterPort gets the OVN database record for the logical router port.
 func (o *NB) GetLogicalRouterPort(ctx context.Context, portName OVNRouterPort) (*ovnNB.LogicalRouterPort, error) {
 	logicalRouterPort := &ovnNB.LogicalRouterPort{
@@ -1375,6 +1510,12 @@ func (o *NB) UpdateLogicalSwitchDHCPv4Options(ctx context.Context, switchName OV
 	dhcpOption.ExternalIDs[ovnExtIDIncusSwitch] = string(switchName)
 	dhcpOption.Cidr = subnet.String()
 
+	if opts.BIOS {
+		dhcpOption.ExternalIDs[ovnExtIDIncusDHCPBIOS] = "true"
+	} else {
+		delete(dhcpOption.ExternalIDs, ovnExtIDIncusDHCPBIOS)
+	}
+
 	dhcpOption.Options["server_id"] = opts.ServerID.String()
 	dhcpOption.Options["server_mac"] = opts.ServerMAC.String()
 	dhcpOption.Options["lease_time"] = fmt.Sprintf("%d", opts.LeaseTime/time.Second)
@@ -1432,6 +1573,27 @@ func (o *NB) UpdateLogicalSwitchDHCPv4Options(ctx context.Context, switchName OV
 		delete(dhcpOption.Options, "classless_static_route")
 	}
 
+	if opts.BootServer != nil {
+		dhcpOption.Options["next_server"] = opts.BootServer.String()
+		dhcpOption.Options["tftp_server_address"] = opts.BootServer.String()
+	} else {
+		delete(dhcpOption.Options, "next_server")
+		delete(dhcpOption.Options, "tftp_server_address")
+	}
+
+	if opts.BootFilename != "" {
+		dhcpOption.Options["bootfile_name"] = fmt.Sprintf(`"%s"`, opts.BootFilename)
+	} else {
+		delete(dhcpOption.Options, "bootfile_name")
+	}
+
+	// OVN answers iPXE clients with the alternative boot file.
+	if opts.BootFilenameIPXE != "" {
+		dhcpOption.Options["bootfile_name_alt"] = fmt.Sprintf(`"%s"`, opts.BootFilenameIPXE)
+	} else {
+		delete(dhcpOption.Options, "bootfile_name_alt")
+	}
+
 	// Prepare the changes.
 	operations := []ovsdb.Operation{}
 	if dhcpOption.UUID == "" {
@@ -1575,6 +1737,7 @@ func (o *NB) GetLogicalSwitchDHCPOptions(ctx context.Context, switchName OVNSwit
 		dhcpOpts = append(dhcpOpts, OVNDHCPOptsSet{
 			UUID: OVNDHCPOptionsUUID(dhcpOption.UUID),
 			CIDR: cidr,
+			BIOS: dhcpOption.ExternalIDs[ovnExtIDIncusDHCPBIOS] == "true",
 		})
 	}
 
@@ -1961,6 +2124,34 @@ func (o *NB) GetLogicalSwitchPortIPs(ctx context.Context, portName OVNSwitchPort
 	return addresses, nil
 }
 
+// GetLogicalSwitchPortHardwareAddress returns the MAC address of the logical switch port.
+func (o *NB) GetLogicalSwitchPortHardwareAddress(ctx context.Context, portName OVNSwitchPort) (string, error) {
+	lsp := ovnNB.LogicalSwitchPort{
+		Name: string(portName),
+	}
+
+	err := o.get(ctx, &lsp)
+	if err != nil {
+		return "", err
+	}
+
+	addresses := slices.Clone(lsp.Addresses)
+	if lsp.DynamicAddresses != nil {
+		addresses = append(addresses, *lsp.DynamicAddresses)
+	}
+
+	for _, address := range addresses {
+		for _, entry := range strings.Split(address, " ") {
+			hwaddr, err := net.ParseMAC(entry)
+			if err == nil {
+				return hwaddr.String(), nil
+			}
+		}
+	}
+
+	return "", ErrNotFound
+}
+
 // GetLogicalSwitchPortDynamicIPs returns a list of dynamic IPs for a switch port.
 func (o *NB) GetLogicalSwitchPortDynamicIPs(ctx context.Context, portName OVNSwitchPort) ([]net.IP, error) {
 	lsp := &ovnNB.LogicalSwitchPort{
@@ -2094,6 +2285,89 @@ func (o *NB) UpdateLogicalSwitchPortOptions(ctx context.Context, portName OVNSwi
 	return nil
 }
 
+// UpdateLogicalSwitchPortMirrors replaces the mirrors of a logical switch port (removing them all if none are provided).
+func (o *NB) UpdateLogicalSwitchPortMirrors(ctx context.Context, portName OVNSwitchPort, mirrors ...OVNMirror) error {
+	// Get the logical switch port.
+	lsp := ovnNB.LogicalSwitchPort{
+		Name: string(portName),
+	}
+
+	err := o.get(ctx, &lsp)
+	if err != nil {
+		return err
+	}
+
+	operations := []ovsdb.Operation{}
+
+	// Delete the existing mirrors.
+	for _, mirrorUUID := range lsp.MirrorRules {
+		mirror := ovnNB.Mirror{
+			UUID: mirrorUUID,
+		}
+
+		err := o.get(ctx, &mirror)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+
+			return err
+		}
+
+		deleteOps, err := o.client.Where(&mirror).Delete()
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, deleteOps...)
+	}
+
+	// Add the new mirrors.
+	lsp.MirrorRules = []string{}
+	for i, entry := range mirrors {
+		mirror := ovnNB.Mirror{
+			UUID:   fmt.Sprintf("mirror%d", i),
+			Name:   fmt.Sprintf("%s-mirror-%s", portName, entry.Filter),
+			Filter: entry.Filter,
+			Type:   entry.Type,
+			Sink:   entry.Sink.String(),
+			Index:  entry.Index,
+			ExternalIDs: map[string]string{
+				ovnExtIDIncusSwitchPort: string(portName),
+			},
+		}
+
+		createOps, err := o.client.Create(&mirror)
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, createOps...)
+		lsp.MirrorRules = append(lsp.MirrorRules, mirror.UUID)
+	}
+
+	// Update the record.
+	updateOps, err := o.client.Where(&lsp).Update(&lsp)
+	if err != nil {
+		return err
+	}
+
+	operations = append(operations, updateOps...)
+
+	// Apply the changes.
+	resp, err := o.client.Transact(ctx, operations...)
+	if err != nil {
+		return err
+	}
+
+	_, err = ovsdb.CheckOperationResults(resp, operations)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // UpdateLogicalSwitchPortDNS sets up the switch port DNS records for the DNS name.
 // Returns the DNS record UUID, IPv4 and IPv6 addresses used for DNS records.
 func (o *NB) UpdateLogicalSwitchPortDNS(ctx context.Context, switchName OVNSwitch, portName OVNSwitchPort, dnsName string, dnsIPs []net.IP) (OVNDNSUUID, error) {
@@ -2709,6 +2983,32 @@ func (o *NB) SetChassisGroupPriority(ctx context.Context, haChassisGroupName OVN
 	return nil
 }
 
+// GetChassisGroupPriorities returns the priority of each chassis ID in the chassis group.
+func (o *NB) GetChassisGroupPriorities(ctx context.Context, haChassisGroupName OVNChassisGroup) (map[string]int, error) {
+	// Get the chassis group.
+	haGroup := ovnNB.HAChassisGroup{
+		Name: string(haChassisGroupName),
+	}
+
+	err := o.get(ctx, &haGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	priorities := make(map[string]int, len(haGroup.HaChassis))
+	for _, entry := range haGroup.HaChassis {
+		chassis := ovnNB.HAChassis{UUID: entry}
+		err = o.get(ctx, &chassis)
+		if err != nil {
+			return nil, err
+		}
+
+		priorities[chassis.ChassisName] = chassis.Priority
+	}
+
+	return priorities, nil
+}
+
 // GetPortGroupInfo returns the port group UUID or empty string if port doesn't exist, and whether the port group has
 // any ACL rules defined on it.
 func (o *NB) GetPortGroupInfo(ctx context.Context, portGroupName OVNPortGroup) (OVNPortGroupUUID, bool, error) {
@@ -2967,6 +3267,24 @@ func (o *NB) UpdatePortGroupACLRules(ctx context.Context, portGroupName OVNPortG
 	return nil
 }
 
+// GetACLRuleCounterNames returns the counter names of the ACL rules matching the prefix, indexed by ACL UUID.
+func (o *NB) GetACLRuleCounterNames(ctx context.Context, prefix string) (map[string]string, error) {
+	acls := []ovnNB.ACL{}
+	err := o.client.WhereCache(func(acl *ovnNB.ACL) bool {
+		return strings.HasPrefix(acl.ExternalIDs[ovnExtIDIncusACLRule], prefix)
+	}).List(ctx, &acls)
+	if err != nil {
+		return nil, err
+	}
+
+	counterNames := make(map[string]string, len(acls))
+	for _, acl := range acls {
+		counterNames[acl.UUID] = acl.ExternalIDs[ovnExtIDIncusACLRule]
+	}
+
+	return counterNames, nil
+}
+
 // AddLogicalSwitchQoSRules applies a set of rules to the specified logical switch port.
 func (o *NB) AddLogicalSwitchQoSRules(ctx context.Context, switchName OVNSwitch, switchPortName OVNSwitchPort, qosRules ...OVNQoSRule) error {
 	var operations []ovsdb.Operation
@@ -3127,6 +3445,10 @@ func (o *NB) aclRuleAddOperations(ctx context.Context, entityTable string, entit
 
 		maps.Copy(acl.ExternalIDs, externalIDs)
 
+		if rule.Counter != "" {
+			acl.ExternalIDs[ovnExtIDIncusACLRule] = rule.Counter
+		}
+
 		createOps, err := o.client.Create(&acl)
 		if err != nil {
 			return nil, err
@@ -4163,6 +4485,8 @@ func (o *NB) GetLogicalRouterRoutes(ctx context.Context, routerName OVNRouter) (
 
 		routerRoute.Prefix = *prefix
 		routerRoute.NextHop = net.ParseIP(route.Nexthop)
+		routerRoute.Discard = route.Nexthop == "discard"
+		routerRoute.BFD = route.BFD != nil
 
 		if route.OutputPort != nil {
 			routerRoute.Port = OVNRouterPort(*route.OutputPort)
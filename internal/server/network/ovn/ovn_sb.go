@@ -24,6 +24,12 @@ import (
 type SB struct {
 	client ovsdbClient.Client
 	cookie ovsdbClient.MonitorCookie
+
+	// Needed for command line tools.
+	dbAddr        string
+	sslCACert     string
+	sslClientCert string
+	sslClientKey  string
 }
 
 // NewSB initializes new OVN client for Southbound operations.
@@ -133,6 +139,7 @@ func NewSB(dbAddr string, sslCACert string, sslClientCert string, sslClientKey s
 
 	// Set up monitor for the tables we use.
 	monitorCookie, err := ovn.Monitor(context.TODO(), ovn.NewMonitor(
+		ovsdbClient.WithTable(&ovnSB.BFD{}),
 		ovsdbClient.WithTable(&ovnSB.Chassis{}),
 		ovsdbClient.WithTable(&ovnSB.PortBinding{}),
 		ovsdbClient.WithTable(&ovnSB.ServiceMonitor{})))
@@ -191,8 +198,12 @@ func NewSB(dbAddr string, sslCACert string, sslClientCert string, sslClientKey s
 
 	// Create the SB struct.
 	client := &SB{
-		client: ovn,
-		cookie: monitorCookie,
+		client:        ovn,
+		cookie:        monitorCookie,
+		dbAddr:        dbAddr,
+		sslCACert:     sslCACert,
+		sslClientCert: sslClientCert,
+		sslClientKey:  sslClientKey,
 	}
 
 	// Set finalizer to stop the monitor.
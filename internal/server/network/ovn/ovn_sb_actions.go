@@ -5,9 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
+
+	"github.com/lxc/incus/v6/shared/subprocess"
+
 	ovnNB "github.com/lxc/incus/v6/internal/server/network/ovn/schema/ovn-nb"
 	ovnSB "github.com/lxc/incus/v6/internal/server/network/ovn/schema/ovn-sb"
 )
@@ -41,6 +47,23 @@ func (o *SB) GetLogicalRouterPortActiveChassisHostname(ctx context.Context, ovnR
 	return chassis.Hostname, nil
 }
 
+// GetChassisHostnames returns a map of chassis names to the hostname of the chassis.
+func (o *SB) GetChassisHostnames(ctx context.Context) (map[string]string, error) {
+	chassis := []ovnSB.Chassis{}
+
+	err := o.client.List(ctx, &chassis)
+	if err != nil {
+		return nil, err
+	}
+
+	hostnames := make(map[string]string, len(chassis))
+	for _, entry := range chassis {
+		hostnames[entry.Name] = entry.Hostname
+	}
+
+	return hostnames, nil
+}
+
 // GetServiceHealth returns the current health record for a particular server and port.
 func (o *SB) GetServiceHealth(ctx context.Context, address string, protocol string, port int) (string, error) {
 	services := []ovnSB.ServiceMonitor{}
@@ -96,3 +119,112 @@ func (o *SB) CheckLoadBalancerOnline(ctx context.Context, lb ovnNB.LoadBalancer)
 
 	return false, nil
 }
+
+// GetLogicalFlowCookies returns the OpenFlow cookies of the logical flows generated from the northbound ACLs,
+// indexed by ACL UUID.
+func (o *SB) GetLogicalFlowCookies(ctx context.Context, aclUUIDs []string) (map[string][]uint32, error) {
+	cookies := make(map[string][]uint32, len(aclUUIDs))
+	if len(aclUUIDs) == 0 {
+		return cookies, nil
+	}
+
+	// The logical flows aren't part of our monitored tables, so query them directly, all in one transaction.
+	operations := make([]ovsdb.Operation, 0, len(aclUUIDs))
+	for _, aclUUID := range aclUUIDs {
+		// Logical flows reference the northbound record through the first 8 characters of its UUID.
+		if len(aclUUID) < 8 {
+			return nil, fmt.Errorf("Invalid ACL UUID %q", aclUUID)
+		}
+
+		operations = append(operations, ovsdb.Operation{
+			Op:      ovsdb.OperationSelect,
+			Table:   "Logical_Flow",
+			Columns: []string{"_uuid"},
+			Where: []ovsdb.Condition{{
+				Column:   "external_ids",
+				Function: ovsdb.ConditionIncludes,
+				Value:    ovsdb.OvsMap{GoMap: map[any]any{"stage-hint": aclUUID[:8]}},
+			}},
+		})
+	}
+
+	resp, err := o.client.Transact(ctx, operations...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = ovsdb.CheckOperationResults(resp, operations)
+	if err != nil {
+		return nil, err
+	}
+
+	// The OpenFlow cookie of a logical flow is the first 32 bits of its UUID.
+	for i, aclUUID := range aclUUIDs {
+		aclCookies := make([]uint32, 0, len(resp[i].Rows))
+		for _, row := range resp[i].Rows {
+			uuid, ok := row["_uuid"].(ovsdb.UUID)
+			if !ok || len(uuid.GoUUID) < 8 {
+				continue
+			}
+
+			cookie, err := strconv.ParseUint(uuid.GoUUID[:8], 16, 32)
+			if err != nil {
+				continue
+			}
+
+			aclCookies = append(aclCookies, uint32(cookie))
+		}
+
+		cookies[aclUUID] = aclCookies
+	}
+
+	return cookies, nil
+}
+
+// Trace runs ovn-trace against the southbound database to simulate the path of the microflow through the
+// logical datapath and returns its detailed output.
+func (o *SB) Trace(ctx context.Context, datapath string, microflow string) (string, error) {
+	args := []string{"--db", o.dbAddr}
+
+	// Write the certificates to disk for the command line tool.
+	if strings.Contains(o.dbAddr, "ssl:") {
+		tmpDir, err := os.MkdirTemp("", "incus_ovn_trace_")
+		if err != nil {
+			return "", err
+		}
+
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+
+		for _, file := range []struct {
+			option  string
+			name    string
+			content string
+		}{
+			{"--ca-cert", "ca.crt", o.sslCACert},
+			{"--certificate", "client.crt", o.sslClientCert},
+			{"--private-key", "client.key", o.sslClientKey},
+		} {
+			if file.content == "" {
+				continue
+			}
+
+			path := filepath.Join(tmpDir, file.name)
+
+			err = os.WriteFile(path, []byte(file.content), 0o600)
+			if err != nil {
+				return "", err
+			}
+
+			args = append(args, file.option, path)
+		}
+	}
+
+	args = append(args, "--detailed", datapath, microflow)
+
+	output, err := subprocess.RunCommandContext(ctx, "ovn-trace", args...)
+	if err != nil {
+		return "", fmt.Errorf("Failed running ovn-trace: %w", err)
+	}
+
+	return output, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/lxc/incus/v6/internal/server/ip"
 	ovsSwitch "github.com/lxc/incus/v6/internal/server/network/ovs/schema/ovs"
+	"github.com/lxc/incus/v6/shared/subprocess"
 	"github.com/lxc/incus/v6/shared/util"
 )
 
@@ -728,3 +730,61 @@ func (o *VSwitch) GetOVNSouthboundDBRemoteAddress(ctx context.Context) (string,
 
 	return val, nil
 }
+
+// FlowCounters represents the packet and byte counters of OpenFlow flows.
+type FlowCounters struct {
+	Packets uint64
+	Bytes   uint64
+}
+
+// GetBridgeFlowCounters returns the counters of the flows on a bridge, summed by flow cookie.
+func (o *VSwitch) GetBridgeFlowCounters(ctx context.Context, bridgeName string) (map[uint64]FlowCounters, error) {
+	// Flow statistics aren't stored in the database, so retrieve them through OpenFlow.
+	output, err := subprocess.RunCommandContext(ctx, "ovs-ofctl", "dump-flows", bridgeName)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFlowCounters(output), nil
+}
+
+// parseFlowCounters parses the counters of the flows from the output of ovs-ofctl dump-flows, summed by flow cookie.
+func parseFlowCounters(output string) map[uint64]FlowCounters {
+	counters := map[uint64]FlowCounters{}
+	for _, line := range strings.Split(output, "\n") {
+		var cookie, packets, bytes uint64
+		var hasCookie bool
+
+		// Each flow is formatted as a list of "key=value" fields separated by commas, ending with the actions.
+		match, _, _ := strings.Cut(line, " actions=")
+		for _, field := range strings.FieldsFunc(match, func(r rune) bool { return r == ',' || r == ' ' }) {
+			key, value, found := strings.Cut(field, "=")
+			if !found {
+				continue
+			}
+
+			var err error
+
+			switch key {
+			case "cookie":
+				cookie, err = strconv.ParseUint(strings.TrimPrefix(value, "0x"), 16, 64)
+				hasCookie = err == nil
+			case "n_packets":
+				packets, _ = strconv.ParseUint(value, 10, 64)
+			case "n_bytes":
+				bytes, _ = strconv.ParseUint(value, 10, 64)
+			}
+		}
+
+		if !hasCookie {
+			continue
+		}
+
+		entry := counters[cookie]
+		entry.Packets += packets
+		entry.Bytes += bytes
+		counters[cookie] = entry
+	}
+
+	return counters
+}
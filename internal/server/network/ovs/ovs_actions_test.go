@@ -0,0 +1,50 @@
+package ovs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseFlowCounters(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   map[uint64]FlowCounters
+	}{
+		{
+			name: "Flows are summed by cookie",
+			output: `NXST_FLOW reply (xid=0x4):
+ cookie=0x8f2a1c3d, duration=120.5s, table=44, n_packets=10, n_bytes=840, idle_age=3, priority=2001,ip,reg15=0x2,metadata=0x1,nw_src=192.0.2.1 actions=resubmit(,45)
+ cookie=0x8f2a1c3d, duration=120.5s, table=44, n_packets=2, n_bytes=208, idle_age=3, priority=2001,ipv6,reg15=0x2,metadata=0x1 actions=resubmit(,45)
+ cookie=0x1234, duration=120.5s, table=8, n_packets=0, n_bytes=0, priority=100,metadata=0x1 actions=load:0x1->NXM_NX_REG10[0],set_field:0x2->reg15,resubmit(,9)
+`,
+			want: map[uint64]FlowCounters{
+				0x8f2a1c3d: {Packets: 12, Bytes: 1048},
+				0x1234:     {},
+			},
+		},
+		{
+			name: "Fields of the actions are ignored",
+			output: ` cookie=0x0, duration=5.1s, table=0, n_packets=4, n_bytes=240, priority=0 actions=learn(table=10,cookie=0x99,n_packets=1)
+`,
+			want: map[uint64]FlowCounters{
+				0: {Packets: 4, Bytes: 240},
+			},
+		},
+		{
+			name: "Lines without cookie are ignored",
+			output: `NXST_FLOW reply (xid=0x4):
+ duration=5.1s, table=0, n_packets=4, n_bytes=240, priority=0 actions=NORMAL
+ cookie=0xzz, duration=5.1s, table=0, n_packets=4, n_bytes=240, priority=0 actions=NORMAL
+`,
+			want: map[uint64]FlowCounters{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseFlowCounters(tt.output))
+		})
+	}
+}
@@ -0,0 +1,594 @@
+package zone
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	incusDNS "github.com/lxc/incus/v6/internal/server/dns"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+const (
+	// dnssecAlgorithm is the algorithm used for the DNSSEC keys (ECDSA P-256 with SHA-256).
+	dnssecAlgorithm = dns.ECDSAP256SHA256
+
+	// dnssecKeyTTL is the TTL of the DNSKEY records.
+	dnssecKeyTTL = 3600
+
+	// dnssecPropagationDelay is how long a new ZSK is published before being used and how long a retired
+	// ZSK remains published, so that caches and secondary servers catch up with the change.
+	dnssecPropagationDelay = 24 * time.Hour
+
+	// dnssecSignatureValidity is the validity period of the generated signatures.
+	// Signatures are re-used until they reach half of their validity period.
+	dnssecSignatureValidity = 14 * 24 * time.Hour
+)
+
+// dnssecSignatures caches the generated signatures so that unchanged record sets aren't signed on every render.
+var dnssecSignatures = map[string]*dns.RRSIG{}
+var dnssecSignaturesMu sync.Mutex
+
+// dnssecKey represents a DNSSEC key and whether it is currently used for signing.
+type dnssecKey struct {
+	dnskey  *dns.DNSKEY
+	private crypto.Signer
+	signing bool
+}
+
+// dnssecLifetime returns the lifetime of a key type from the zone config (0 for no automatic rollover).
+func dnssecLifetime(config map[string]string, keyType string, defaultDays int) time.Duration {
+	days := defaultDays
+
+	value := config[fmt.Sprintf("dnssec.%s.lifetime", keyType)]
+	if value != "" {
+		days, _ = strconv.Atoi(value)
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// dnssecNewKey generates a new DNSSEC key for the zone.
+func dnssecNewKey(zoneName string, flags uint16, now time.Time, activation time.Time) (*dbCluster.NetworkZoneKey, error) {
+	dnskey := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: dns.Fqdn(zoneName), Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: dnssecKeyTTL},
+		Flags:     flags,
+		Protocol:  3,
+		Algorithm: dnssecAlgorithm,
+	}
+
+	private, err := dnskey.Generate(256)
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating DNSSEC key: %w", err)
+	}
+
+	return &dbCluster.NetworkZoneKey{
+		Flags:          int(flags),
+		Algorithm:      int(dnssecAlgorithm),
+		PublicKey:      dnskey.PublicKey,
+		PrivateKey:     dnskey.PrivateKeyString(private),
+		CreationDate:   now,
+		ActivationDate: activation,
+	}, nil
+}
+
+// dnssecRotateKeys generates the missing DNSSEC keys of the zone and rolls over the expired ones.
+//
+// ZSKs are pre-published: a new ZSK is added to the DNSKEY records ahead of being used for signing and the
+// previous ZSK is only removed once the new one has been in use for long enough.
+// KSKs use double signing: a new KSK immediately signs the DNSKEY records alongside the previous one, which keeps
+// signing until the new DS record has been published in the parent zone and the KSK is retired (dnssecRetireKSK).
+func dnssecRotateKeys(ctx context.Context, tx *sql.Tx, zoneID int64, zoneName string, config map[string]string, now time.Time) error {
+	id := int(zoneID)
+	keys, err := dbCluster.GetNetworkZoneKeys(ctx, tx, dbCluster.NetworkZoneKeyFilter{NetworkZoneID: &id})
+	if err != nil {
+		return fmt.Errorf("Failed loading DNSSEC keys: %w", err)
+	}
+
+	for _, flags := range []uint16{dns.ZONE | dns.SEP, dns.ZONE} {
+		var typeKeys []dbCluster.NetworkZoneKey
+		for _, key := range keys {
+			if key.Flags == int(flags) && key.Algorithm == int(dnssecAlgorithm) {
+				typeKeys = append(typeKeys, key)
+			}
+		}
+
+		isKSK := flags&dns.SEP != 0
+
+		// Generate the initial key.
+		if len(typeKeys) == 0 {
+			key, err := dnssecNewKey(zoneName, flags, now, now)
+			if err != nil {
+				return err
+			}
+
+			_, err = dbCluster.CreateNetworkZoneKey(ctx, tx, *key)
+			if err != nil {
+				return fmt.Errorf("Failed storing DNSSEC key: %w", err)
+			}
+
+			continue
+		}
+
+		// Keys are ordered by ID, so the last one is the newest.
+		newest := typeKeys[len(typeKeys)-1]
+
+		var lifetime time.Duration
+		var activation time.Time
+		if isKSK {
+			// The previous KSK is only removed once the DS record of the new one has been published, so don't
+			// start another rollover while one is still in progress.
+			if len(typeKeys) > 1 {
+				continue
+			}
+
+			lifetime = dnssecLifetime(config, "ksk", 365)
+			activation = now
+		} else {
+			lifetime = dnssecLifetime(config, "zsk", 30)
+			activation = now.Add(dnssecPropagationDelay)
+
+			// Remove the retired keys.
+			if now.After(newest.ActivationDate.Add(dnssecPropagationDelay)) {
+				for _, key := range typeKeys[:len(typeKeys)-1] {
+					err = dbCluster.DeleteNetworkZoneKey(ctx, tx, key.ID)
+					if err != nil {
+						return fmt.Errorf("Failed removing retired DNSSEC key: %w", err)
+					}
+				}
+			}
+		}
+
+		// Introduce a new key once the newest one has expired.
+		if lifetime > 0 && !newest.ActivationDate.After(now) && now.After(newest.ActivationDate.Add(lifetime)) {
+			key, err := dnssecNewKey(zoneName, flags, now, activation)
+			if err != nil {
+				return err
+			}
+
+			_, err = dbCluster.CreateNetworkZoneKey(ctx, tx, *key)
+			if err != nil {
+				return fmt.Errorf("Failed storing DNSSEC key: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// dnssecRetireKSK removes the previous KSKs of the zone once the DS record of the newest one is in the parent zone.
+func dnssecRetireKSK(ctx context.Context, tx *sql.Tx, zoneID int64) error {
+	id := int(zoneID)
+	keys, err := dbCluster.GetNetworkZoneKeys(ctx, tx, dbCluster.NetworkZoneKeyFilter{NetworkZoneID: &id})
+	if err != nil {
+		return fmt.Errorf("Failed loading DNSSEC keys: %w", err)
+	}
+
+	var ksks []dbCluster.NetworkZoneKey
+	for _, key := range keys {
+		if key.Flags&dns.SEP != 0 {
+			ksks = append(ksks, key)
+		}
+	}
+
+	if len(ksks) < 2 {
+		return api.StatusErrorf(http.StatusBadRequest, "No key signing key rollover in progress")
+	}
+
+	for _, key := range ksks[:len(ksks)-1] {
+		err = dbCluster.DeleteNetworkZoneKey(ctx, tx, key.ID)
+		if err != nil {
+			return fmt.Errorf("Failed removing retired DNSSEC key: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// dnssecDeleteKeys removes all the DNSSEC keys of the zone.
+func dnssecDeleteKeys(ctx context.Context, tx *sql.Tx, zoneID int64) error {
+	id := int(zoneID)
+	keys, err := dbCluster.GetNetworkZoneKeys(ctx, tx, dbCluster.NetworkZoneKeyFilter{NetworkZoneID: &id})
+	if err != nil {
+		return fmt.Errorf("Failed loading DNSSEC keys: %w", err)
+	}
+
+	for _, key := range keys {
+		err = dbCluster.DeleteNetworkZoneKey(ctx, tx, key.ID)
+		if err != nil {
+			return fmt.Errorf("Failed removing DNSSEC key: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// dnssecKeys returns the DNSSEC keys of the zone.
+func (d *zone) dnssecKeys() ([]dnssecKey, error) {
+	var dbKeys []dbCluster.NetworkZoneKey
+
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		zoneID := int(d.id)
+		dbKeys, err = dbCluster.GetNetworkZoneKeys(ctx, tx.Tx(), dbCluster.NetworkZoneKeyFilter{NetworkZoneID: &zoneID})
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading DNSSEC keys: %w", err)
+	}
+
+	return dnssecParseKeys(d.info.Name, dbKeys, time.Now())
+}
+
+// dnssecParseKeys converts the stored keys and selects the ones used for signing.
+// All KSKs sign the DNSKEY records while only the newest active ZSK signs the rest of the zone.
+func dnssecParseKeys(zoneName string, dbKeys []dbCluster.NetworkZoneKey, now time.Time) ([]dnssecKey, error) {
+	keys := []dnssecKey{}
+	activeZSK := -1
+
+	for _, dbKey := range dbKeys {
+		dnskey := &dns.DNSKEY{
+			Hdr:       dns.RR_Header{Name: dns.Fqdn(zoneName), Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: dnssecKeyTTL},
+			Flags:     uint16(dbKey.Flags),
+			Protocol:  3,
+			Algorithm: uint8(dbKey.Algorithm),
+			PublicKey: dbKey.PublicKey,
+		}
+
+		private, err := dnskey.NewPrivateKey(dbKey.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing DNSSEC key %d: %w", dnskey.KeyTag(), err)
+		}
+
+		signer, ok := private.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("Unsupported DNSSEC key %d", dnskey.KeyTag())
+		}
+
+		key := dnssecKey{dnskey: dnskey, private: signer}
+
+		if dnskey.Flags&dns.SEP != 0 {
+			key.signing = true
+		} else if !dbKey.ActivationDate.After(now) {
+			activeZSK = len(keys)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if activeZSK >= 0 {
+		keys[activeZSK].signing = true
+	}
+
+	return keys, nil
+}
+
+// dnssecSignZone adds the DNSKEY, RRSIG and (optionally) NSEC records to the zone records.
+func dnssecSignZone(zoneName string, records []dns.RR, keys []dnssecKey, withNSEC bool, now time.Time) ([]dns.RR, error) {
+	zoneName = dns.CanonicalName(zoneName)
+
+	// Drop the trailing SOA record and add the DNSKEY records.
+	signed := []dns.RR{}
+	var soa *dns.SOA
+	for _, rr := range records {
+		if rr.Header().Rrtype == dns.TypeSOA {
+			if soa != nil {
+				continue
+			}
+
+			soa = rr.(*dns.SOA)
+		}
+
+		signed = append(signed, rr)
+	}
+
+	if soa == nil {
+		return nil, fmt.Errorf("Missing SOA record in zone %q", zoneName)
+	}
+
+	for _, key := range keys {
+		signed = append(signed, key.dnskey)
+	}
+
+	// Find the delegations, their records aren't authoritative and so aren't signed.
+	delegations := []string{}
+	for _, rr := range signed {
+		owner := dns.CanonicalName(rr.Header().Name)
+		if rr.Header().Rrtype == dns.TypeNS && owner != zoneName {
+			delegations = append(delegations, owner)
+		}
+	}
+
+	isDelegated := func(owner string, rrtype uint16) bool {
+		for _, delegation := range delegations {
+			if owner == delegation && (rrtype == dns.TypeDS || rrtype == dns.TypeNSEC) {
+				return false
+			}
+
+			if dns.IsSubDomain(delegation, owner) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// Group the records into sets.
+	type rrsetKey struct {
+		owner  string
+		rrtype uint16
+	}
+
+	rrsets := map[rrsetKey][]dns.RR{}
+	owners := map[string][]uint16{}
+	for _, rr := range signed {
+		owner := dns.CanonicalName(rr.Header().Name)
+		key := rrsetKey{owner: owner, rrtype: rr.Header().Rrtype}
+
+		if rrsets[key] == nil {
+			owners[owner] = append(owners[owner], key.rrtype)
+		}
+
+		rrsets[key] = append(rrsets[key], rr)
+	}
+
+	// Build the NSEC chain.
+	if withNSEC {
+		names := make([]string, 0, len(owners))
+		for owner := range owners {
+			// Glue records aren't part of the chain.
+			if owner != zoneName && isDelegated(owner, dns.TypeNSEC) {
+				continue
+			}
+
+			names = append(names, owner)
+		}
+
+		slices.SortFunc(names, incusDNS.CompareNames)
+
+		for i, owner := range names {
+			// The NSEC record itself is always signed, including at delegations.
+			types := append(slices.Clone(owners[owner]), dns.TypeNSEC, dns.TypeRRSIG)
+			slices.Sort(types)
+
+			nsec := &dns.NSEC{
+				Hdr:        dns.RR_Header{Name: owner, Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: min(soa.Minttl, soa.Hdr.Ttl)},
+				NextDomain: names[(i+1)%len(names)],
+				TypeBitMap: slices.Compact(types),
+			}
+
+			key := rrsetKey{owner: owner, rrtype: dns.TypeNSEC}
+			rrsets[key] = []dns.RR{nsec}
+			signed = append(signed, nsec)
+		}
+	}
+
+	// Sign the records sets.
+	inception := now.Add(-time.Hour)
+	expiration := now.Add(dnssecSignatureValidity)
+
+	rrsetKeys := make([]rrsetKey, 0, len(rrsets))
+	for key := range rrsets {
+		rrsetKeys = append(rrsetKeys, key)
+	}
+
+	slices.SortFunc(rrsetKeys, func(a rrsetKey, b rrsetKey) int {
+		cmp := incusDNS.CompareNames(a.owner, b.owner)
+		if cmp != 0 {
+			return cmp
+		}
+
+		return int(a.rrtype) - int(b.rrtype)
+	})
+
+	for _, key := range rrsetKeys {
+		if isDelegated(key.owner, key.rrtype) {
+			continue
+		}
+
+		for _, signingKey := range keys {
+			if !signingKey.signing {
+				continue
+			}
+
+			// The KSKs only sign the DNSKEY records and the ZSK signs everything else.
+			isKSK := signingKey.dnskey.Flags&dns.SEP != 0
+			if isKSK != (key.rrtype == dns.TypeDNSKEY) {
+				continue
+			}
+
+			rrset := rrsets[key]
+			rrsig := dnssecCachedSignature(signingKey.dnskey, rrset, now)
+			if rrsig != nil {
+				signed = append(signed, rrsig)
+				continue
+			}
+
+			rrsig = &dns.RRSIG{
+				Hdr:        dns.RR_Header{Name: key.owner, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: rrset[0].Header().Ttl},
+				Algorithm:  signingKey.dnskey.Algorithm,
+				SignerName: zoneName,
+				KeyTag:     signingKey.dnskey.KeyTag(),
+				OrigTtl:    rrset[0].Header().Ttl,
+				Inception:  uint32(inception.Unix()),
+				Expiration: uint32(expiration.Unix()),
+			}
+
+			err := rrsig.Sign(signingKey.private, rrset)
+			if err != nil {
+				return nil, fmt.Errorf("Failed signing %s records of %q: %w", dns.TypeToString[key.rrtype], key.owner, err)
+			}
+
+			dnssecCacheSignature(signingKey.dnskey, rrset, rrsig)
+			signed = append(signed, rrsig)
+		}
+	}
+
+	// Zone transfers must end with the SOA record.
+	signed = append(signed, soa)
+
+	return signed, nil
+}
+
+// dnssecSignatureCacheKey returns the cache key of the signature of a record set by a key.
+func dnssecSignatureCacheKey(dnskey *dns.DNSKEY, rrset []dns.RR) string {
+	rrs := make([]string, 0, len(rrset))
+	for _, rr := range rrset {
+		rrs = append(rrs, rr.String())
+	}
+
+	slices.Sort(rrs)
+
+	return dnskey.Hdr.Name + " " + dnskey.PublicKey + "\n" + strings.Join(rrs, "\n")
+}
+
+// dnssecCachedSignature returns the cached signature of the record set if it is still in the first half of its
+// validity period (nil otherwise).
+func dnssecCachedSignature(dnskey *dns.DNSKEY, rrset []dns.RR, now time.Time) *dns.RRSIG {
+	dnssecSignaturesMu.Lock()
+	defer dnssecSignaturesMu.Unlock()
+
+	rrsig := dnssecSignatures[dnssecSignatureCacheKey(dnskey, rrset)]
+	if rrsig == nil || now.Add(dnssecSignatureValidity/2).Unix() >= int64(rrsig.Expiration) {
+		return nil
+	}
+
+	return rrsig
+}
+
+// dnssecCacheSignature stores the signature of the record set, dropping the signatures due for renewal.
+func dnssecCacheSignature(dnskey *dns.DNSKEY, rrset []dns.RR, rrsig *dns.RRSIG) {
+	dnssecSignaturesMu.Lock()
+	defer dnssecSignaturesMu.Unlock()
+
+	renewal := time.Now().Add(dnssecSignatureValidity / 2).Unix()
+	for k, v := range dnssecSignatures {
+		if renewal >= int64(v.Expiration) {
+			delete(dnssecSignatures, k)
+		}
+	}
+
+	dnssecSignatures[dnssecSignatureCacheKey(dnskey, rrset)] = rrsig
+}
+
+// sign adds the DNSSEC records to the rendered zone if DNSSEC is enabled.
+func (d *zone) sign(sb *strings.Builder, withNSEC bool) (*strings.Builder, error) {
+	if !util.IsTrue(d.info.Config["dnssec.enabled"]) {
+		return sb, nil
+	}
+
+	keys, err := d.dnssecKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	records := []dns.RR{}
+	zoneRR := dns.NewZoneParser(strings.NewReader(sb.String()), "", "")
+	for {
+		rr, ok := zoneRR.Next()
+		if !ok {
+			err := zoneRR.Err()
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing zone %q: %w", d.info.Name, err)
+			}
+
+			break
+		}
+
+		records = append(records, rr)
+	}
+
+	signed, err := dnssecSignZone(d.info.Name, records, keys, withNSEC, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	out := &strings.Builder{}
+	for _, rr := range signed {
+		out.WriteString(rr.String())
+		out.WriteString("\n")
+	}
+
+	return out, nil
+}
+
+// RotateKeys generates the missing DNSSEC keys of the zone and rolls over the expired ones.
+func (d *zone) RotateKeys() error {
+	if !util.IsTrue(d.info.Config["dnssec.enabled"]) {
+		return nil
+	}
+
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dnssecRotateKeys(ctx, tx.Tx(), d.id, d.info.Name, d.info.Config, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+
+	// Drop the rendered zones.
+	d.state.DNS.InvalidateZones()
+
+	return nil
+}
+
+// RetireKSK removes the previous key signing keys of the zone once the DS record of the new one is published.
+func (d *zone) RetireKSK() error {
+	if !util.IsTrue(d.info.Config["dnssec.enabled"]) {
+		return api.StatusErrorf(http.StatusBadRequest, "DNSSEC isn't enabled on the zone")
+	}
+
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dnssecRetireKSK(ctx, tx.Tx(), d.id)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Drop the rendered zones.
+	d.state.DNS.InvalidateZones()
+
+	return nil
+}
+
+// DSRecords returns the DS records of the zone's key signing keys.
+func (d *zone) DSRecords() ([]string, error) {
+	if !util.IsTrue(d.info.Config["dnssec.enabled"]) {
+		return nil, nil
+	}
+
+	keys, err := d.dnssecKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	records := []string{}
+	for _, key := range keys {
+		if key.dnskey.Flags&dns.SEP == 0 {
+			continue
+		}
+
+		ds := key.dnskey.ToDS(dns.SHA256)
+		if ds == nil {
+			continue
+		}
+
+		records = append(records, ds.String())
+	}
+
+	return records, nil
+}
@@ -0,0 +1,81 @@
+package zone
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+)
+
+const testZone = `
+incus.example.net. 3600 IN SOA ns1.example.net. hostmaster.incus.example.net. 1 120 60 86400 30
+incus.example.net. 300 IN NS ns1.example.net.
+c1.incus.example.net. 300 IN A 10.0.0.2
+incus.example.net. 3600 IN SOA ns1.example.net. hostmaster.incus.example.net. 1 120 60 86400 30
+`
+
+func testZoneRecords(t *testing.T, content string) []dns.RR {
+	records := []dns.RR{}
+	zoneRR := dns.NewZoneParser(strings.NewReader(content), "", "")
+	for rr, ok := zoneRR.Next(); ok; rr, ok = zoneRR.Next() {
+		records = append(records, rr)
+	}
+
+	require.NoError(t, zoneRR.Err())
+
+	return records
+}
+
+func testRRSIG(records []dns.RR, rrtype uint16) *dns.RRSIG {
+	for _, rr := range records {
+		rrsig, ok := rr.(*dns.RRSIG)
+		if ok && rrsig.TypeCovered == rrtype {
+			return rrsig
+		}
+	}
+
+	return nil
+}
+
+// Signatures of unchanged record sets are re-used until they reach half of their validity.
+func TestDNSSECSignZoneCache(t *testing.T) {
+	now := time.Now()
+
+	dbKeys := []dbCluster.NetworkZoneKey{}
+	for _, flags := range []uint16{dns.ZONE | dns.SEP, dns.ZONE} {
+		key, err := dnssecNewKey("incus.example.net", flags, now, now)
+		require.NoError(t, err)
+
+		dbKeys = append(dbKeys, *key)
+	}
+
+	keys, err := dnssecParseKeys("incus.example.net", dbKeys, now)
+	require.NoError(t, err)
+
+	first, err := dnssecSignZone("incus.example.net", testZoneRecords(t, testZone), keys, true, now)
+	require.NoError(t, err)
+
+	second, err := dnssecSignZone("incus.example.net", testZoneRecords(t, testZone), keys, true, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	assert.Same(t, testRRSIG(first, dns.TypeA), testRRSIG(second, dns.TypeA))
+	assert.Same(t, testRRSIG(first, dns.TypeDNSKEY), testRRSIG(second, dns.TypeDNSKEY))
+
+	// Changed record sets are signed again.
+	changed := strings.Replace(testZone, "10.0.0.2", "10.0.0.3", 1)
+	third, err := dnssecSignZone("incus.example.net", testZoneRecords(t, changed), keys, true, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	assert.NotSame(t, testRRSIG(first, dns.TypeA), testRRSIG(third, dns.TypeA))
+
+	// Signatures are renewed once half-way through their validity.
+	fourth, err := dnssecSignZone("incus.example.net", testZoneRecords(t, testZone), keys, true, now.Add(dnssecSignatureValidity/2))
+	require.NoError(t, err)
+
+	assert.NotSame(t, testRRSIG(first, dns.TypeA), testRRSIG(fourth, dns.TypeA))
+}
@@ -21,6 +21,7 @@ type NetworkZone interface {
 	UsedBy() ([]string, error)
 	Content() (*strings.Builder, error)
 	SOA() (*strings.Builder, error)
+	DSRecords() ([]string, error)
 
 	// Records.
 	AddRecord(req api.NetworkZoneRecordsPost) error
@@ -35,5 +36,7 @@ type NetworkZone interface {
 
 	// Modifications.
 	Update(config *api.NetworkZonePut, clientType request.ClientType) error
+	RotateKeys() error
+	RetireKSK() error
 	Delete() error
 }
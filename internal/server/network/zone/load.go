@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
@@ -26,6 +27,10 @@ func LoadByName(s *state.State, name string) (NetworkZone, error) {
 			return err
 		}
 
+		if len(dbZone) == 0 {
+			return api.StatusErrorf(http.StatusNotFound, "Network zone not found")
+		}
+
 		if len(dbZone) != 1 {
 			return fmt.Errorf("Loading network zone named %s returned an unexpected amount of results: %d", name, len(dbZone))
 		}
@@ -152,6 +157,14 @@ func Create(s *state.State, projectName string, zoneInfo *api.NetworkZonesPost)
 			return err
 		}
 
+		// Generate the DNSSEC keys.
+		if util.IsTrue(zoneInfo.Config["dnssec.enabled"]) {
+			err = dnssecRotateKeys(ctx, tx.Tx(), id, zoneInfo.Name, zoneInfo.Config, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -164,6 +177,9 @@ func Create(s *state.State, projectName string, zoneInfo *api.NetworkZonesPost)
 		return err
 	}
 
+	// Drop the rendered zones.
+	s.DNS.InvalidateZones()
+
 	return nil
 }
 
@@ -59,6 +59,9 @@ func (d *zone) AddRecord(req api.NetworkZoneRecordsPost) error {
 		return err
 	}
 
+	// Drop the rendered zones.
+	d.state.DNS.InvalidateZones()
+
 	return nil
 }
 
@@ -186,6 +189,9 @@ func (d *zone) UpdateRecord(name string, req api.NetworkZoneRecordPut, clientTyp
 		return err
 	}
 
+	// Drop the rendered zones.
+	d.state.DNS.InvalidateZones()
+
 	return nil
 }
 
@@ -221,6 +227,9 @@ func (d *zone) DeleteRecord(name string) error {
 		return err
 	}
 
+	// Drop the rendered zones.
+	d.state.DNS.InvalidateZones()
+
 	return nil
 }
 
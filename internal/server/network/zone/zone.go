@@ -186,6 +186,41 @@ func (d *zone) validateConfig(info *api.NetworkZonePut) error {
 	//  shortdesc: Admin contact email for DNS server
 	rules["dns.contact"] = validate.Optional(validate.IsAny)
 
+	// gendoc:generate(entity=network_zone, group=common, key=dns.query.subnets)
+	//
+	// ---
+	//  type: string set
+	//  required: no
+	//  shortdesc: Comma-separated list of subnets allowed to query the built-in DNS server for records in the zone
+	rules["dns.query.subnets"] = validate.Optional(validate.IsListOf(validate.IsNetwork))
+
+	// gendoc:generate(entity=network_zone, group=common, key=dnssec.enabled)
+	//
+	// ---
+	//  type: bool
+	//  required: no
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to sign the zone with DNSSEC
+	rules["dnssec.enabled"] = validate.Optional(validate.IsBool)
+
+	// gendoc:generate(entity=network_zone, group=common, key=dnssec.ksk.lifetime)
+	//
+	// ---
+	//  type: integer
+	//  required: no
+	//  defaultdesc: `365`
+	//  shortdesc: Number of days after which a new key signing key is introduced (`0` to disable automatic rollover)
+	rules["dnssec.ksk.lifetime"] = validate.Optional(validate.IsUint32)
+
+	// gendoc:generate(entity=network_zone, group=common, key=dnssec.zsk.lifetime)
+	//
+	// ---
+	//  type: integer
+	//  required: no
+	//  defaultdesc: `30`
+	//  shortdesc: Number of days after which a new zone signing key is introduced (`0` to disable automatic rollover)
+	rules["dnssec.zsk.lifetime"] = validate.Optional(validate.IsUint32)
+
 	// gendoc:generate(entity=network_zone, group=common, key=network.nat)
 	//
 	// ---
@@ -308,6 +343,14 @@ func (d *zone) Update(config *api.NetworkZonePut, clientType request.ClientType)
 				return err
 			}
 
+			// Generate the DNSSEC keys.
+			if util.IsTrue(config.Config["dnssec.enabled"]) {
+				err = dnssecRotateKeys(ctx, tx.Tx(), d.id, d.info.Name, config.Config, time.Now())
+				if err != nil {
+					return err
+				}
+			}
+
 			return nil
 		})
 		if err != nil {
@@ -355,6 +398,16 @@ func (d *zone) Update(config *api.NetworkZonePut, clientType request.ClientType)
 		if err != nil {
 			return err
 		}
+
+		// Remove the DNSSEC keys once disabled, a later re-enable starts over with new keys.
+		if util.IsTrue(oldConfig.Config["dnssec.enabled"]) && !util.IsTrue(config.Config["dnssec.enabled"]) {
+			err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				return dnssecDeleteKeys(ctx, tx.Tx(), d.id)
+			})
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// Trigger a refresh of the TSIG entries.
@@ -363,6 +416,9 @@ func (d *zone) Update(config *api.NetworkZonePut, clientType request.ClientType)
 		return err
 	}
 
+	// Drop the rendered zones.
+	d.state.DNS.InvalidateZones()
+
 	reverter.Success()
 	return nil
 }
@@ -394,6 +450,9 @@ func (d *zone) Delete() error {
 		return err
 	}
 
+	// Drop the rendered zones.
+	d.state.DNS.InvalidateZones()
+
 	return nil
 }
 
@@ -631,7 +690,7 @@ func (d *zone) Content() (*strings.Builder, error) {
 		return nil, err
 	}
 
-	return sb, nil
+	return d.sign(sb, true)
 }
 
 // SOA returns just the DNS zone SOA record.
@@ -672,5 +731,5 @@ func (d *zone) SOA() (*strings.Builder, error) {
 		return nil, err
 	}
 
-	return sb, nil
+	return d.sign(sb, false)
 }
@@ -0,0 +1,133 @@
+package nflog
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"syscall"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+)
+
+// copyRange is the number of bytes of each packet copied to userspace (enough for the network and transport headers).
+const copyRange = 128
+
+// Listen binds to the netfilter log group and calls the handler for every packet logged to it.
+func Listen(ctx context.Context, group uint16, handler func(packet *Packet)) error {
+	sock, err := nl.Subscribe(unix.NETLINK_NETFILTER)
+	if err != nil {
+		return fmt.Errorf("Failed creating netfilter netlink socket: %w", err)
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	reverter.Add(sock.Close)
+
+	// Bind to the log group.
+	err = sendConfig(sock, group, nfulaCfgCmd, []byte{nfulnlCfgCmdBind})
+	if err != nil {
+		return fmt.Errorf("Failed binding to netfilter log group %d: %w", group, err)
+	}
+
+	// Request the packet headers to be copied.
+	mode := make([]byte, 6)
+	binary.BigEndian.PutUint32(mode[0:4], copyRange)
+	mode[4] = nfulnlCopyPacket
+
+	err = sendConfig(sock, group, nfulaCfgMode, mode)
+	if err != nil {
+		return fmt.Errorf("Failed configuring netfilter log group %d: %w", group, err)
+	}
+
+	// This goroutine waits for the context to be cancelled and then closes the socket causing `Receive` to return an error and exit the goroutine below.
+	go func() {
+		<-ctx.Done()
+		sock.Close()
+	}()
+
+	go func() {
+		for {
+			msgs, _, err := sock.Receive()
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				// The kernel drops messages when the socket buffer is full, keep going.
+				if errors.Is(err, unix.ENOBUFS) || errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+					continue
+				}
+
+				logger.Warn("Failed receiving netfilter log messages", logger.Ctx{"group": group, "err": err})
+				return
+			}
+
+			for _, msg := range msgs {
+				if msg.Header.Type != (unix.NFNL_SUBSYS_ULOG<<8)|nfulnlMsgPacket {
+					continue
+				}
+
+				packet, err := parsePacket(msg.Data)
+				if err != nil {
+					continue
+				}
+
+				handler(packet)
+			}
+		}
+	}()
+
+	reverter.Success()
+
+	return nil
+}
+
+// sendConfig sends a configuration command for the log group and waits for the kernel acknowledgement.
+func sendConfig(sock *nl.NetlinkSocket, group uint16, attrType int, value []byte) error {
+	resID := make([]byte, 2)
+	binary.BigEndian.PutUint16(resID, group)
+
+	req := nl.NewNetlinkRequest((unix.NFNL_SUBSYS_ULOG<<8)|nfulnlMsgConfig, unix.NLM_F_REQUEST|unix.NLM_F_ACK)
+	req.AddData(&nl.Nfgenmsg{
+		NfgenFamily: unix.AF_UNSPEC,
+		Version:     unix.NFNETLINK_V0,
+		ResId:       nl.NativeEndian().Uint16(resID),
+	})
+
+	req.AddData(nl.NewRtAttr(attrType, value))
+
+	err := sock.Send(req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		msgs, _, err := sock.Receive()
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range msgs {
+			if msg.Header.Type != unix.NLMSG_ERROR || msg.Header.Seq != req.Seq {
+				continue
+			}
+
+			if len(msg.Data) < 4 {
+				return errors.New("Invalid netlink acknowledgement")
+			}
+
+			errno := int32(nl.NativeEndian().Uint32(msg.Data[0:4]))
+			if errno != 0 {
+				return syscall.Errno(-errno)
+			}
+
+			return nil
+		}
+	}
+}
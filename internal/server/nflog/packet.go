@@ -0,0 +1,190 @@
+package nflog
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// Netfilter log message types (see linux/netfilter/nfnetlink_log.h).
+const (
+	nfulnlMsgPacket = 0
+	nfulnlMsgConfig = 1
+)
+
+// Netfilter log packet attributes.
+const (
+	nfulaPacketHdr     = 1
+	nfulaIfindexIndev  = 4
+	nfulaIfindexOutdev = 5
+	nfulaHwaddr        = 8
+	nfulaPayload       = 9
+	nfulaPrefix        = 10
+)
+
+// Netfilter log configuration attributes and values.
+const (
+	nfulaCfgCmd  = 1
+	nfulaCfgMode = 2
+
+	nfulnlCfgCmdBind = 1
+
+	nfulnlCopyPacket = 2
+)
+
+// ErrInvalidPacket is returned when a logged packet cannot be decoded.
+var ErrInvalidPacket = errors.New("Invalid netfilter log packet")
+
+// Packet represents a packet logged by netfilter.
+type Packet struct {
+	Prefix          string
+	InIndex         int
+	OutIndex        int
+	HardwareAddress net.HardwareAddr
+
+	Protocol        string
+	Source          net.IP
+	Destination     net.IP
+	SourcePort      uint16
+	DestinationPort uint16
+	ICMPType        uint8
+	ICMPCode        uint8
+}
+
+// parsePacket decodes the attributes of a netfilter log packet message (following the nfgenmsg header).
+func parsePacket(data []byte) (*Packet, error) {
+	if len(data) < nl.SizeofNfgenmsg {
+		return nil, ErrInvalidPacket
+	}
+
+	attrs, err := nl.ParseRouteAttr(data[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return nil, ErrInvalidPacket
+	}
+
+	packet := &Packet{}
+	var hwProtocol uint16
+	var payload []byte
+
+	for _, attr := range attrs {
+		value := attr.Value
+
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nfulaPacketHdr:
+			if len(value) >= 2 {
+				hwProtocol = binary.BigEndian.Uint16(value[0:2])
+			}
+
+		case nfulaIfindexIndev:
+			if len(value) >= 4 {
+				packet.InIndex = int(binary.BigEndian.Uint32(value[0:4]))
+			}
+
+		case nfulaIfindexOutdev:
+			if len(value) >= 4 {
+				packet.OutIndex = int(binary.BigEndian.Uint32(value[0:4]))
+			}
+
+		case nfulaHwaddr:
+			if len(value) >= 4 {
+				hwLen := int(binary.BigEndian.Uint16(value[0:2]))
+				if hwLen > 0 && 4+hwLen <= len(value) {
+					packet.HardwareAddress = net.HardwareAddr(append([]byte{}, value[4:4+hwLen]...))
+				}
+			}
+
+		case nfulaPayload:
+			payload = value
+
+		case nfulaPrefix:
+			packet.Prefix = strings.TrimSpace(strings.TrimRight(string(value), "\x00"))
+		}
+	}
+
+	if payload == nil {
+		return nil, ErrInvalidPacket
+	}
+
+	err = packet.parsePayload(payload, hwProtocol)
+	if err != nil {
+		return nil, err
+	}
+
+	return packet, nil
+}
+
+// parsePayload extracts the addresses, protocol and ports from the network header of the packet.
+func (p *Packet) parsePayload(payload []byte, hwProtocol uint16) error {
+	if len(payload) < 1 {
+		return ErrInvalidPacket
+	}
+
+	// Fallback to the IP version when the hardware protocol isn't known.
+	if hwProtocol != unix.ETH_P_IP && hwProtocol != unix.ETH_P_IPV6 {
+		switch payload[0] >> 4 {
+		case 4:
+			hwProtocol = unix.ETH_P_IP
+		case 6:
+			hwProtocol = unix.ETH_P_IPV6
+		default:
+			return ErrInvalidPacket
+		}
+	}
+
+	var protocol uint8
+	var transport []byte
+
+	if hwProtocol == unix.ETH_P_IP {
+		if len(payload) < 20 {
+			return ErrInvalidPacket
+		}
+
+		headerLength := int(payload[0]&0x0f) * 4
+		if headerLength < 20 || len(payload) < headerLength {
+			return ErrInvalidPacket
+		}
+
+		protocol = payload[9]
+		p.Source = net.IP(append([]byte{}, payload[12:16]...))
+		p.Destination = net.IP(append([]byte{}, payload[16:20]...))
+		transport = payload[headerLength:]
+	} else {
+		if len(payload) < 40 {
+			return ErrInvalidPacket
+		}
+
+		protocol = payload[6]
+		p.Source = net.IP(append([]byte{}, payload[8:24]...))
+		p.Destination = net.IP(append([]byte{}, payload[24:40]...))
+		transport = payload[40:]
+	}
+
+	switch protocol {
+	case unix.IPPROTO_TCP:
+		p.Protocol = "tcp"
+	case unix.IPPROTO_UDP:
+		p.Protocol = "udp"
+	case unix.IPPROTO_ICMP:
+		p.Protocol = "icmp4"
+	case unix.IPPROTO_ICMPV6:
+		p.Protocol = "icmp6"
+	default:
+		p.Protocol = strconv.Itoa(int(protocol))
+	}
+
+	// Extract the ports or the ICMP type and code.
+	if (p.Protocol == "tcp" || p.Protocol == "udp") && len(transport) >= 4 {
+		p.SourcePort = binary.BigEndian.Uint16(transport[0:2])
+		p.DestinationPort = binary.BigEndian.Uint16(transport[2:4])
+	} else if (p.Protocol == "icmp4" || p.Protocol == "icmp6") && len(transport) >= 2 {
+		p.ICMPType = transport[0]
+		p.ICMPCode = transport[1]
+	}
+
+	return nil
+}
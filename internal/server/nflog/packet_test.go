@@ -0,0 +1,98 @@
+package nflog
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// buildMessage returns a netfilter log packet message with the provided attributes.
+func buildMessage(attrs ...*nl.RtAttr) []byte {
+	data := make([]byte, nl.SizeofNfgenmsg)
+	for _, attr := range attrs {
+		data = append(data, attr.Serialize()...)
+	}
+
+	return data
+}
+
+// A logged IPv4 UDP packet is decoded along with its metadata.
+func TestParsePacket_IPv4(t *testing.T) {
+	hdr := make([]byte, 4)
+	binary.BigEndian.PutUint16(hdr, unix.ETH_P_IP)
+
+	indev := make([]byte, 4)
+	binary.BigEndian.PutUint32(indev, 12)
+
+	hwaddr := make([]byte, 12)
+	binary.BigEndian.PutUint16(hwaddr, 6)
+	copy(hwaddr[4:], []byte{0x00, 0x16, 0x3e, 0x01, 0x02, 0x03})
+
+	payload := make([]byte, 28)
+	payload[0] = 0x45
+	payload[9] = unix.IPPROTO_UDP
+	copy(payload[12:16], net.ParseIP("10.0.0.2").To4())
+	copy(payload[16:20], net.ParseIP("1.1.1.1").To4())
+	binary.BigEndian.PutUint16(payload[20:22], 40000)
+	binary.BigEndian.PutUint16(payload[22:24], 53)
+
+	packet, err := parsePacket(buildMessage(
+		nl.NewRtAttr(nfulaPacketHdr, hdr),
+		nl.NewRtAttr(nfulaIfindexIndev, indev),
+		nl.NewRtAttr(nfulaHwaddr, hwaddr),
+		nl.NewRtAttr(nfulaPrefix, []byte("incusbr0-incus_acl1-egress-0\x00")),
+		nl.NewRtAttr(nfulaPayload, payload),
+	))
+	require.NoError(t, err)
+
+	assert.Equal(t, "incusbr0-incus_acl1-egress-0", packet.Prefix)
+	assert.Equal(t, 12, packet.InIndex)
+	assert.Equal(t, "00:16:3e:01:02:03", packet.HardwareAddress.String())
+	assert.Equal(t, "udp", packet.Protocol)
+	assert.Equal(t, "10.0.0.2", packet.Source.String())
+	assert.Equal(t, "1.1.1.1", packet.Destination.String())
+	assert.Equal(t, uint16(40000), packet.SourcePort)
+	assert.Equal(t, uint16(53), packet.DestinationPort)
+}
+
+// A logged ICMPv6 packet is decoded using the IP version when the hardware protocol is missing.
+func TestParsePacket_IPv6(t *testing.T) {
+	payload := make([]byte, 44)
+	payload[0] = 0x60
+	payload[6] = unix.IPPROTO_ICMPV6
+	copy(payload[8:24], net.ParseIP("fd42::1"))
+	copy(payload[24:40], net.ParseIP("fd42::2"))
+	payload[40] = 128
+
+	packet, err := parsePacket(buildMessage(nl.NewRtAttr(nfulaPayload, payload)))
+	require.NoError(t, err)
+
+	assert.Equal(t, "icmp6", packet.Protocol)
+	assert.Equal(t, "fd42::1", packet.Source.String())
+	assert.Equal(t, "fd42::2", packet.Destination.String())
+	assert.Equal(t, uint8(128), packet.ICMPType)
+	assert.Equal(t, uint8(0), packet.ICMPCode)
+}
+
+// Messages without a usable payload are rejected.
+func TestParsePacket_Invalid(t *testing.T) {
+	cases := map[string][]byte{
+		"short":      {0x00},
+		"no payload": buildMessage(nl.NewRtAttr(nfulaPrefix, []byte("foo\x00"))),
+		"truncated":  buildMessage(nl.NewRtAttr(nfulaPayload, []byte{0x45, 0x00})),
+		"not ip":     buildMessage(nl.NewRtAttr(nfulaPayload, []byte{0x00, 0x00})),
+	}
+
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := parsePacket(data)
+			assert.True(t, errors.Is(err, ErrInvalidPacket))
+		})
+	}
+}
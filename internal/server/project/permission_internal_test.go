@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/idmap"
 )
 
@@ -75,3 +76,112 @@ func TestParseHostIDMapRange(t *testing.T) {
 		assert.Equal(t, idmaps, expected)
 	}
 }
+
+func TestCheckRestrictionsBondNIC(t *testing.T) {
+	p := api.Project{
+		Name: "p1",
+		ProjectPut: api.ProjectPut{
+			Config: map[string]string{
+				"restricted":                 "true",
+				"restricted.devices.nic":     "allow",
+				"restricted.networks.access": "eth0,eth1",
+			},
+		},
+	}
+
+	tests := []struct {
+		parent  string
+		wantErr bool
+	}{
+		{parent: "eth0,eth1"},
+		{parent: "eth1, eth0"},
+		{parent: "eth0,eth2", wantErr: true},
+		{parent: "eth2,eth0", wantErr: true},
+		{parent: "eth0,eth1,eth2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.parent, func(t *testing.T) {
+			instances := []api.Instance{{
+				Name: "c1",
+				Type: "container",
+				InstancePut: api.InstancePut{
+					Devices: map[string]map[string]string{
+						"eth0": {"type": "nic", "nictype": "bond", "parent": tt.parent},
+					},
+				},
+			}}
+
+			err := checkRestrictions(p, instances, nil)
+			if tt.wantErr {
+				assert.EqualError(t, err, `Invalid device "eth0" on container "c1" of project "p1": Network not allowed in project`)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckNetworkLimitsNICs(t *testing.T) {
+	tests := []struct {
+		name    string
+		device  map[string]string
+		wantErr bool
+	}{
+		{name: "bridged network", device: map[string]string{"type": "nic", "network": "incusbr0"}},
+		{name: "routed", device: map[string]string{"type": "nic", "nictype": "routed", "parent": "eth0"}},
+		{name: "disk", device: map[string]string{"type": "disk", "pool": "default", "path": "/"}},
+		{name: "OVN network", device: map[string]string{"type": "nic", "network": "ovn0"}, wantErr: true},
+		{name: "OVN NIC type", device: map[string]string{"type": "nic", "nictype": "ovn", "network": "other"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instances := []api.Instance{{
+				Name: "c1",
+				InstancePut: api.InstancePut{
+					Devices: map[string]map[string]string{"eth0": tt.device},
+				},
+			}}
+
+			err := checkNetworkLimitsNICs(instances, []string{"ovn0"})
+			if tt.wantErr {
+				assert.EqualError(t, err, `Project network limits can't be used with the OVN NIC "eth0" of instance "c1"`)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckRestrictionsNICMirror(t *testing.T) {
+	instances := []api.Instance{{
+		Name: "c1",
+		Type: "container",
+		InstancePut: api.InstancePut{
+			Devices: map[string]map[string]string{
+				"eth0": {"type": "nic", "network": "ovn0", "mirror.remote": "192.0.2.10"},
+			},
+		},
+	}}
+
+	p := api.Project{
+		Name: "p1",
+		ProjectPut: api.ProjectPut{
+			Config: map[string]string{
+				"restricted": "true",
+			},
+		},
+	}
+
+	err := checkRestrictions(p, instances, nil)
+	assert.EqualError(t, err, `Invalid device "eth0" on container "c1" of project "p1": Mirroring the traffic to a remote collector is forbidden`)
+
+	p.Config["restricted.devices.nic.mirror"] = "allow"
+	assert.NoError(t, checkRestrictions(p, instances, nil))
+
+	// Mirroring to another local NIC is always allowed.
+	p.Config["restricted.devices.nic.mirror"] = "block"
+	instances[0].Devices["eth0"] = map[string]string{"type": "nic", "network": "ovn0", "mirror.target": "c2/eth0"}
+	assert.NoError(t, checkRestrictions(p, instances, nil))
+}
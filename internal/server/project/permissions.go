@@ -407,7 +407,9 @@ func checkRestrictionsAndAggregateLimits(tx *db.ClusterTx, info *projectInfo) er
 		}
 	}
 
-	if len(aggregateKeys) == 0 && !isRestricted {
+	hasNetworkLimits := projectHasNetworkLimits(info.Project.Config)
+
+	if len(aggregateKeys) == 0 && !isRestricted && !hasNetworkLimits {
 		return nil
 	}
 
@@ -423,6 +425,18 @@ func checkRestrictionsAndAggregateLimits(tx *db.ClusterTx, info *projectInfo) er
 		return err
 	}
 
+	if hasNetworkLimits {
+		ovnNetworks, err := getOVNNetworks(tx, info.Project)
+		if err != nil {
+			return err
+		}
+
+		err = checkNetworkLimitsNICs(info.Instances, ovnNetworks)
+		if err != nil {
+			return err
+		}
+	}
+
 	if isRestricted {
 		err = checkRestrictions(info.Project, info.Instances, info.Profiles)
 		if err != nil {
@@ -669,6 +683,15 @@ func checkRestrictions(project api.Project, instances []api.Instance, profiles [
 				return nil
 			}
 
+		case "restricted.devices.serial":
+			devicesChecks["serial"] = func(device map[string]string) error {
+				if restrictionValue != "allow" && device["listen"] != "" {
+					return errors.New("Serial devices listening on the host are forbidden")
+				}
+
+				return nil
+			}
+
 		case "restricted.devices.nic":
 			devicesChecks["nic"] = func(device map[string]string) error {
 				// Check if the NICs are allowed at all.
@@ -681,6 +704,11 @@ func checkRestrictions(project api.Project, instances []api.Instance, profiles [
 					}
 				}
 
+				// Remote mirroring sends copies of the traffic from the underlay network to any address.
+				if device["mirror.remote"] != "" && project.Config["restricted.devices.nic.mirror"] != "allow" {
+					return errors.New("Mirroring the traffic to a remote collector is forbidden")
+				}
+
 				// Check if the NIC's parent/network setting is allowed based on the
 				// restricted.devices.nic and restricted.networks.access settings.
 				if device["network"] != "" {
@@ -688,8 +716,16 @@ func checkRestrictions(project api.Project, instances []api.Instance, profiles [
 						return errors.New("Network not allowed in project")
 					}
 				} else if device["parent"] != "" {
-					if !NetworkAllowed(project.Config, device["parent"], false) {
-						return errors.New("Network not allowed in project")
+					// Bond NICs use a comma-separated list of parent devices.
+					parents := []string{device["parent"]}
+					if device["nictype"] == "bond" {
+						parents = util.SplitNTrimSpace(device["parent"], ",", -1, true)
+					}
+
+					for _, parent := range parents {
+						if !NetworkAllowed(project.Config, parent, false) {
+							return errors.New("Network not allowed in project")
+						}
 					}
 				}
 
@@ -722,6 +758,15 @@ func checkRestrictions(project api.Project, instances []api.Instance, profiles [
 					}
 
 				case "allow":
+					// Network sources aren't host paths, only allow them when the paths aren't restricted.
+					if device["pool"] == "" && instance.IsNetworkDiskSource(device["source"]) {
+						if project.Config["restricted.devices.disk.paths"] != "" {
+							return fmt.Errorf("Network disk source %q not allowed", device["source"])
+						}
+
+						return nil
+					}
+
 					if device["pool"] == "" {
 						allowed, _ := CheckRestrictedDevicesDiskPaths(project.Config, device["source"])
 						if !allowed {
@@ -905,7 +950,9 @@ var allRestrictions = map[string]string{
 	"restricted.devices.usb":               "block",
 	"restricted.devices.pci":               "block",
 	"restricted.devices.proxy":             "block",
+	"restricted.devices.serial":            "block",
 	"restricted.devices.nic":               "managed",
+	"restricted.devices.nic.mirror":        "block",
 	"restricted.devices.disk":              "managed",
 	"restricted.devices.disk.paths":        "",
 	"restricted.idmap.uid":                 "",
@@ -1134,6 +1181,30 @@ func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]
 				return fmt.Errorf("Can't change %q in project %q: %w", key, projectName, err)
 			}
 
+		case "limits.network.ingress":
+			fallthrough
+		case "limits.network.egress":
+			if config[key] == "" {
+				continue
+			}
+
+			project := api.Project{
+				Name: projectName,
+				ProjectPut: api.ProjectPut{
+					Config: config,
+				},
+			}
+
+			ovnNetworks, err := getOVNNetworks(tx, project)
+			if err != nil {
+				return err
+			}
+
+			err = checkNetworkLimitsNICs(info.Instances, ovnNetworks)
+			if err != nil {
+				return fmt.Errorf("Can't change %q in project %q: %w", key, projectName, err)
+			}
+
 		case "limits.processes":
 			fallthrough
 		case "limits.cpu":
@@ -1162,6 +1233,46 @@ func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]
 	return nil
 }
 
+// projectHasNetworkLimits returns whether the project limits the aggregate network traffic of its instances.
+func projectHasNetworkLimits(config map[string]string) bool {
+	return config["limits.network.ingress"] != "" || config["limits.network.egress"] != ""
+}
+
+// getOVNNetworks returns the names of the OVN networks the NICs of the project's instances can connect to.
+func getOVNNetworks(tx *db.ClusterTx, project api.Project) ([]string, error) {
+	networks, err := tx.GetCreatedNetworksByProject(context.Background(), NetworkProjectFromRecord(&project))
+	if err != nil {
+		return nil, fmt.Errorf("Fetch project networks from database: %w", err)
+	}
+
+	ovnNetworks := []string{}
+	for _, network := range networks {
+		if network.Type == "ovn" {
+			ovnNetworks = append(ovnNetworks, network.Name)
+		}
+	}
+
+	return ovnNetworks, nil
+}
+
+// checkNetworkLimitsNICs checks that the instances of a project limiting its network traffic don't have OVN NICs, as
+// the project network limits only apply to the bridged, routed and p2p NICs.
+func checkNetworkLimitsNICs(instances []api.Instance, ovnNetworks []string) error {
+	for _, inst := range instances {
+		for devName, dev := range inst.Devices {
+			if dev["type"] != "nic" {
+				continue
+			}
+
+			if dev["nictype"] == "ovn" || (dev["network"] != "" && slices.Contains(ovnNetworks, dev["network"])) {
+				return fmt.Errorf("Project network limits can't be used with the OVN NIC %q of instance %q", devName, inst.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
 // Check that limits.instances, i.e. the total limit of containers/virtual machines allocated
 // to the user is equal to or above the current count.
 func validateTotalInstanceCountLimit(instances []api.Instance, value, project string) error {
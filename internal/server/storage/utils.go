@@ -1084,6 +1084,61 @@ func VolumeUsedByDaemon(s *state.State, poolName string, volumeName string) (boo
 	return false, nil
 }
 
+// VolumeUsedByNetworks finds networks serving files from a custom volume (through `boot.volume`) and passes
+// them to networkFunc for evaluation.
+func VolumeUsedByNetworks(s *state.State, poolName string, projectName string, volumeName string, networkFunc func(networkProject string, network api.Network) error) error {
+	source := fmt.Sprintf("%s/%s", poolName, volumeName)
+
+	var usedBy []api.Network
+	var usedByProjects []string
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		projectNetworks, err := tx.GetCreatedNetworks(ctx)
+		if err != nil {
+			return err
+		}
+
+		for networkProject, networks := range projectNetworks {
+			for _, network := range networks {
+				if network.Config["boot.volume"] != source {
+					continue
+				}
+
+				// The volume is looked up in the project the network's custom volumes are stored in.
+				dbProject, err := cluster.GetProject(ctx, tx.Tx(), networkProject)
+				if err != nil {
+					return fmt.Errorf("Failed loading project %q: %w", networkProject, err)
+				}
+
+				p, err := dbProject.ToAPI(ctx, tx.Tx())
+				if err != nil {
+					return fmt.Errorf("Failed loading config for project %q: %w", networkProject, err)
+				}
+
+				if project.StorageVolumeProjectFromRecord(p, db.StoragePoolVolumeTypeCustom) != projectName {
+					continue
+				}
+
+				usedBy = append(usedBy, network)
+				usedByProjects = append(usedByProjects, networkProject)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, network := range usedBy {
+		err = networkFunc(usedByProjects[i], network)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // FallbackMigrationType returns the fallback migration transport to use based on volume content type.
 func FallbackMigrationType(contentType drivers.ContentType) migration.MigrationFSType {
 	if drivers.IsContentBlock(contentType) {
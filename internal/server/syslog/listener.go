@@ -3,6 +3,7 @@ package syslog
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net"
 	"os"
 	"strings"
@@ -18,7 +19,9 @@ import (
 )
 
 // Listen starts the log monitor.
-func Listen(ctx context.Context, eventServer *events.Server) error {
+// The optional aclContext function is used to add structured details to the ACL log events and returns the
+// project they belong to.
+func Listen(ctx context.Context, eventServer *events.Server, aclContext func(message string) (string, map[string]string)) error {
 	var listenConfig net.ListenConfig
 
 	sockFile := internalUtil.VarPath("syslog.socket")
@@ -133,7 +136,15 @@ func Listen(ctx context.Context, eventServer *events.Server) error {
 				event.Context["application"] = applicationName
 			}
 
-			err = eventServer.Send("", api.EventTypeNetworkACL, event)
+			projectName := ""
+			if aclContext != nil {
+				var flowContext map[string]string
+
+				projectName, flowContext = aclContext(message)
+				maps.Copy(event.Context, flowContext)
+			}
+
+			err = eventServer.Send(projectName, api.EventTypeNetworkACL, event)
 			if err != nil {
 				continue
 			}
@@ -529,6 +529,31 @@ var APIExtensions = []string{
 	"daemon_storage_logs",
 	"instances_debug_repair",
 	"network_io_bus_ovn",
+	"network_bfd",
+	"network_acl_stats",
+	"network_acl_flow_logs",
+	"network_zones_dns_queries",
+	"network_zones_dnssec",
+	"network_capture",
+	"instance_nic_mirroring",
+	"network_bridge_ipv6_prefix_delegation",
+	"network_nat64",
+	"network_boot",
+	"network_reservations",
+	"network_lease_history",
+	"network_floating_ips",
+	"network_trace",
+	"network_address_set_selectors",
+	"project_network_limits",
+	"network_qos",
+	"proxy_tls_http",
+	"network_host_acls",
+	"network_topology",
+	"network_ovn_gateway_chassis",
+	"device_serial",
+	"device_watchdog",
+	"disk_network_sources",
+	"nic_bond",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
@@ -68,6 +68,7 @@ const (
 	EventLifecycleInstanceStarted                   = "instance-started"
 	EventLifecycleInstanceStopped                   = "instance-stopped"
 	EventLifecycleInstanceUpdated                   = "instance-updated"
+	EventLifecycleInstanceWatchdog                  = "instance-watchdog"
 	EventLifecycleNetworkACLCreated                 = "network-acl-created"
 	EventLifecycleNetworkACLDeleted                 = "network-acl-deleted"
 	EventLifecycleNetworkACLRenamed                 = "network-acl-renamed"
@@ -76,8 +77,13 @@ const (
 	EventLifecycleNetworkAddressSetDeleted          = "network-address-set-deleted"
 	EventLifecycleNetworkAddressSetRenamed          = "network-address-set-renamed"
 	EventLifecycleNetworkAddressSetUpdated          = "network-address-set-updated"
+	EventLifecycleNetworkBFDSessionDown             = "network-bfd-session-down"
+	EventLifecycleNetworkBFDSessionUp               = "network-bfd-session-up"
 	EventLifecycleNetworkCreated                    = "network-created"
 	EventLifecycleNetworkDeleted                    = "network-deleted"
+	EventLifecycleNetworkFloatingIPCreated          = "network-floating-ip-created"
+	EventLifecycleNetworkFloatingIPDeleted          = "network-floating-ip-deleted"
+	EventLifecycleNetworkFloatingIPUpdated          = "network-floating-ip-updated"
 	EventLifecycleNetworkForwardCreated             = "network-forward-created"
 	EventLifecycleNetworkForwardDeleted             = "network-forward-deleted"
 	EventLifecycleNetworkForwardUpdated             = "network-forward-updated"
@@ -85,6 +91,9 @@ const (
 	EventLifecycleNetworkIntegrationDeleted         = "network-integration-deleted"
 	EventLifecycleNetworkIntegrationRenamed         = "network-integration-renamed"
 	EventLifecycleNetworkIntegrationUpdated         = "network-integration-updated"
+	EventLifecycleNetworkLeaseAcquired              = "network-lease-acquired"
+	EventLifecycleNetworkLeaseReleased              = "network-lease-released"
+	EventLifecycleNetworkLeaseRenewed               = "network-lease-renewed"
 	EventLifecycleNetworkLoadBalancerCreated        = "network-load-balancer-created"
 	EventLifecycleNetworkLoadBalancerDeleted        = "network-load-balancer-deleted"
 	EventLifecycleNetworkLoadBalancerUpdated        = "network-load-balancer-updated"
@@ -92,6 +101,9 @@ const (
 	EventLifecycleNetworkPeerDeleted                = "network-peer-deleted"
 	EventLifecycleNetworkPeerUpdated                = "network-peer-updated"
 	EventLifecycleNetworkRenamed                    = "network-renamed"
+	EventLifecycleNetworkReservationCreated         = "network-reservation-created"
+	EventLifecycleNetworkReservationDeleted         = "network-reservation-deleted"
+	EventLifecycleNetworkReservationUpdated         = "network-reservation-updated"
 	EventLifecycleNetworkUpdated                    = "network-updated"
 	EventLifecycleNetworkZoneCreated                = "network-zone-created"
 	EventLifecycleNetworkZoneDeleted                = "network-zone-deleted"
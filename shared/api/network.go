@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // NetworksPost represents the fields of a new network
 //
 // swagger:model
@@ -128,11 +132,11 @@ type NetworkLease struct {
 	// Example: 10:66:6a:2c:89:d9
 	Hwaddr string `json:"hwaddr" yaml:"hwaddr"`
 
-	// The IP address
+	// The IP address (or prefix for delegated prefixes)
 	// Example: 10.0.0.98
 	Address string `json:"address" yaml:"address"`
 
-	// The type of record (static or dynamic)
+	// The type of record (static, dynamic or delegated)
 	// Example: dynamic
 	Type string `json:"type" yaml:"type"`
 
@@ -143,6 +147,45 @@ type NetworkLease struct {
 	Location string `json:"location" yaml:"location"`
 }
 
+// NetworkLeaseEvent represents a past DHCP lease event
+//
+// swagger:model
+//
+// API extension: network_lease_history.
+type NetworkLeaseEvent struct {
+	// The type of event (acquired, renewed or released)
+	// Example: acquired
+	Type string `json:"type" yaml:"type"`
+
+	// When the event was recorded
+	// Example: 2026-10-18T12:30:05Z
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+
+	// The hostname reported by the client
+	// Example: c1
+	Hostname string `json:"hostname" yaml:"hostname"`
+
+	// The MAC address
+	// Example: 10:66:6a:2c:89:d9
+	Hwaddr string `json:"hwaddr" yaml:"hwaddr"`
+
+	// The IP address
+	// Example: 10.0.0.98
+	Address string `json:"address" yaml:"address"`
+
+	// Project of the instance the lease was handed to (if known)
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Name of the instance the lease was handed to (if known)
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// What cluster member the event was recorded on
+	// Example: server01
+	Location string `json:"location" yaml:"location"`
+}
+
 // NetworkState represents the network state
 //
 // swagger:model
@@ -188,6 +231,16 @@ type NetworkState struct {
 	//
 	// API extension: network_state_ovn
 	OVN *NetworkStateOVN `json:"ovn" yaml:"ovn"`
+
+	// Additional BGP information
+	//
+	// API extension: network_bfd
+	BGP *NetworkStateBGP `json:"bgp" yaml:"bgp"`
+
+	// Statistics of the QoS classes
+	//
+	// API extension: network_qos
+	QoS []NetworkStateQoSClass `json:"qos,omitempty" yaml:"qos,omitempty"`
 }
 
 // NetworkStateAddress represents a network address
@@ -346,4 +399,80 @@ type NetworkStateOVN struct {
 	//
 	// API extension: network_ovn_state_addresses
 	UplinkIPv6 string `json:"uplink_ipv6" yaml:"uplink_ipv6"`
+
+	// BFD session state for each uplink gateway
+	// Example: {"10.0.0.1": "up"}
+	//
+	// API extension: network_bfd
+	UplinkGatewayBFD map[string]string `json:"uplink_gateway_bfd,omitempty" yaml:"uplink_gateway_bfd,omitempty"`
+
+	// Priority of each gateway chassis (the highest priority available chassis is active)
+	// Example: {"server01": 32767, "server02": 16383}
+	//
+	// API extension: network_ovn_gateway_chassis
+	GatewayChassis map[string]int `json:"gateway_chassis,omitempty" yaml:"gateway_chassis,omitempty"`
+}
+
+// NetworkStateBGP represents BGP specific state
+//
+// swagger:model
+//
+// API extension: network_bfd.
+type NetworkStateBGP struct {
+	// List of BGP peers configured on the network
+	Peers []NetworkStateBGPPeer `json:"peers" yaml:"peers"`
+}
+
+// NetworkStateBGPPeer represents the state of a single BGP peer
+//
+// swagger:model
+//
+// API extension: network_bfd.
+type NetworkStateBGPPeer struct {
+	// Peer name
+	// Example: router1
+	Name string `json:"name" yaml:"name"`
+
+	// Peer address
+	// Example: 10.0.0.254
+	Address string `json:"address" yaml:"address"`
+
+	// BGP session state
+	// Example: established
+	State string `json:"state" yaml:"state"`
+
+	// BFD session state (empty when BFD isn't enabled)
+	// Example: up
+	BFD string `json:"bfd" yaml:"bfd"`
+}
+
+// NetworkStateQoSClass represents the statistics of a QoS class
+//
+// swagger:model
+//
+// API extension: network_qos.
+type NetworkStateQoSClass struct {
+	// Class name
+	// Example: voice
+	Name string `json:"name" yaml:"name"`
+
+	// Number of bytes sent through the class
+	// Example: 250542118
+	BytesSent int64 `json:"bytes_sent" yaml:"bytes_sent"`
+
+	// Number of packets sent through the class
+	// Example: 1182515
+	PacketsSent int64 `json:"packets_sent" yaml:"packets_sent"`
+
+	// Number of packets dropped by the class
+	// Example: 12
+	PacketsDropped int64 `json:"packets_dropped" yaml:"packets_dropped"`
+
+	// Number of times the class exceeded its rate
+	// Example: 340
+	Overlimits int64 `json:"overlimits" yaml:"overlimits"`
+
+	// Number of bytes currently queued in the class
+	// Example: 0
+	Backlog int64 `json:"backlog" yaml:"backlog"`
 }
@@ -166,3 +166,31 @@ type NetworkACLsPost struct {
 	NetworkACLPost `yaml:",inline"`
 	NetworkACLPut  `yaml:",inline"`
 }
+
+// NetworkACLState represents the state of a network ACL.
+//
+// swagger:model
+//
+// API extension: network_acl_stats.
+type NetworkACLState struct {
+	// Hit counters of the ingress rules (in rule order)
+	Ingress []NetworkACLRuleCounters `json:"ingress" yaml:"ingress"`
+
+	// Hit counters of the egress rules (in rule order)
+	Egress []NetworkACLRuleCounters `json:"egress" yaml:"egress"`
+}
+
+// NetworkACLRuleCounters represents the hit counters of a network ACL rule.
+//
+// swagger:model
+//
+// API extension: network_acl_stats.
+type NetworkACLRuleCounters struct {
+	// Number of packets that matched the rule
+	// Example: 1024
+	Packets uint64 `json:"packets" yaml:"packets"`
+
+	// Number of bytes that matched the rule
+	// Example: 65536
+	Bytes uint64 `json:"bytes" yaml:"bytes"`
+}
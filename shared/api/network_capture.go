@@ -0,0 +1,20 @@
+package api
+
+// NetworkCapturePost represents a packet capture request on a network or an instance NIC.
+//
+// swagger:model
+//
+// API extension: network_capture.
+type NetworkCapturePost struct {
+	// Packet filter expression (pcap-filter syntax)
+	// Example: tcp port 80
+	Filter string `json:"filter" yaml:"filter"`
+
+	// Maximum duration of the capture in seconds (0 for no limit)
+	// Example: 60
+	Duration int `json:"duration" yaml:"duration"`
+
+	// Maximum number of packets to capture (0 for no limit)
+	// Example: 1000
+	Count int `json:"count" yaml:"count"`
+}
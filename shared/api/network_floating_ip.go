@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net"
+	"strings"
+)
+
+// NetworkFloatingIPsPost represents the fields of a new network floating IP
+//
+// swagger:model
+//
+// API extension: network_floating_ips.
+type NetworkFloatingIPsPost struct {
+	NetworkFloatingIPPut `yaml:",inline"`
+
+	// The floating IP address
+	// Example: 192.0.2.10
+	Address string `json:"address" yaml:"address"`
+}
+
+// Normalise normalises the fields in the floating IP so that they are comparable with ones stored.
+func (f *NetworkFloatingIPsPost) Normalise() {
+	ip := net.ParseIP(f.Address)
+	if ip != nil {
+		f.Address = ip.String() // Replace with canonical form if specified.
+	}
+
+	f.NetworkFloatingIPPut.Normalise()
+}
+
+// NetworkFloatingIPPut represents the modifiable fields of a network floating IP
+//
+// swagger:model
+//
+// API extension: network_floating_ips.
+type NetworkFloatingIPPut struct {
+	// Description of the floating IP
+	// Example: Web service address
+	Description string `json:"description" yaml:"description"`
+
+	// Name of the instance the floating IP follows
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Floating IP configuration map (refer to doc/howto/network_floating_ips.md)
+	// Example: {"announce.bgp": "true"}
+	Config ConfigMap `json:"config" yaml:"config"`
+}
+
+// Normalise normalises the fields in the floating IP so that they are comparable with ones stored.
+func (f *NetworkFloatingIPPut) Normalise() {
+	f.Description = strings.TrimSpace(f.Description)
+	f.Instance = strings.TrimSpace(f.Instance)
+}
+
+// NetworkFloatingIP used for displaying a network floating IP.
+//
+// swagger:model
+//
+// API extension: network_floating_ips.
+type NetworkFloatingIP struct {
+	NetworkFloatingIPPut `yaml:",inline"`
+
+	// The floating IP address
+	// Example: 192.0.2.10
+	Address string `json:"address" yaml:"address"`
+
+	// Project of the instance the floating IP follows
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// What cluster member currently announces the floating IP
+	// Example: server01
+	Location string `json:"location" yaml:"location"`
+}
+
+// Etag returns the values used for etag generation.
+func (f *NetworkFloatingIP) Etag() []any {
+	return []any{f.Address, f.Description, f.Instance, f.Config}
+}
+
+// Writable converts a full NetworkFloatingIP struct into a NetworkFloatingIPPut struct (filters read-only fields).
+func (f *NetworkFloatingIP) Writable() NetworkFloatingIPPut {
+	return f.NetworkFloatingIPPut
+}
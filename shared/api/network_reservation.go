@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net"
+	"strings"
+)
+
+// NetworkReservationsPost represents the fields of a new network DHCP reservation
+//
+// swagger:model
+//
+// API extension: network_reservations.
+type NetworkReservationsPost struct {
+	NetworkReservationPut `yaml:",inline"`
+
+	// The name of the reservation (used as the host name)
+	// Example: printer01
+	Name string `json:"name" yaml:"name"`
+}
+
+// NetworkReservationPut represents the modifiable fields of a network DHCP reservation
+//
+// swagger:model
+//
+// API extension: network_reservations.
+type NetworkReservationPut struct {
+	// Description of the reservation
+	// Example: Office printer
+	Description string `json:"description" yaml:"description"`
+
+	// MAC address of the client
+	// Example: 00:16:3e:12:34:56
+	Hwaddr string `json:"hwaddr" yaml:"hwaddr"`
+
+	// DHCPv6 unique identifier (DUID) of the client
+	// Example: 00:01:00:01:2c:d3:4e:5f:00:16:3e:12:34:56
+	DUID string `json:"duid" yaml:"duid"`
+
+	// Reserved IPv4 address
+	// Example: 10.0.0.10
+	IPv4Address string `json:"ipv4_address" yaml:"ipv4_address"`
+
+	// Reserved IPv6 address
+	// Example: fd42:4242:4242:1010::10
+	IPv6Address string `json:"ipv6_address" yaml:"ipv6_address"`
+
+	// Reservation configuration map (refer to doc/howto/network_reservations.md)
+	// Example: {"user.mykey": "foo"}
+	Config ConfigMap `json:"config" yaml:"config"`
+}
+
+// Normalise normalises the fields in the reservation so that they are comparable with ones stored.
+func (r *NetworkReservationPut) Normalise() {
+	r.Description = strings.TrimSpace(r.Description)
+
+	mac, err := net.ParseMAC(r.Hwaddr)
+	if err == nil {
+		r.Hwaddr = mac.String() // Replace with canonical form if specified.
+	}
+
+	r.DUID = strings.ToLower(r.DUID)
+
+	ip := net.ParseIP(r.IPv4Address)
+	if ip != nil {
+		r.IPv4Address = ip.String() // Replace with canonical form if specified.
+	}
+
+	ip = net.ParseIP(r.IPv6Address)
+	if ip != nil {
+		r.IPv6Address = ip.String() // Replace with canonical form if specified.
+	}
+}
+
+// NetworkReservation used for displaying a network DHCP reservation.
+//
+// swagger:model
+//
+// API extension: network_reservations.
+type NetworkReservation struct {
+	NetworkReservationPut `yaml:",inline"`
+
+	// The name of the reservation (used as the host name)
+	// Example: printer01
+	Name string `json:"name" yaml:"name"`
+}
+
+// Etag returns the values used for etag generation.
+func (r *NetworkReservation) Etag() []any {
+	return []any{r.Name, r.Description, r.Hwaddr, r.DUID, r.IPv4Address, r.IPv6Address, r.Config}
+}
+
+// Writable converts a full NetworkReservation struct into a NetworkReservationPut struct (filters read-only fields).
+func (r *NetworkReservation) Writable() NetworkReservationPut {
+	return r.NetworkReservationPut
+}
@@ -0,0 +1,72 @@
+package api
+
+// NetworkTopology represents the graph of the networks and of what is connected to them.
+//
+// swagger:model
+//
+// API extension: network_topology.
+type NetworkTopology struct {
+	// Networks, instances, NICs and network objects of the graph
+	Nodes []NetworkTopologyNode `json:"nodes" yaml:"nodes"`
+
+	// Connections between the nodes of the graph
+	Edges []NetworkTopologyEdge `json:"edges" yaml:"edges"`
+}
+
+// NetworkTopologyNode represents an entity of the network topology.
+//
+// swagger:model
+//
+// API extension: network_topology.
+type NetworkTopologyNode struct {
+	// Unique identifier of the node (API URL of the entity, with the device name as fragment for NICs)
+	// Example: /1.0/networks/incusbr0?project=default
+	ID string `json:"id" yaml:"id"`
+
+	// Kind of entity (network, network-peer, network-integration, network-forward, network-load-balancer, instance or nic)
+	// Example: network
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Name of the entity
+	// Example: incusbr0
+	Name string `json:"name" yaml:"name"`
+
+	// Project of the entity
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Type of the entity (network type, peer type, integration type, instance type or NIC type)
+	// Example: bridge
+	Type string `json:"type" yaml:"type"`
+
+	// Status of the entity as recorded in the database (networks and peers only)
+	// Example: Created
+	Status string `json:"status" yaml:"status"`
+
+	// Cluster member of the entity (instances, NICs and member specific forwards only)
+	// Example: server01
+	Location string `json:"location" yaml:"location"`
+
+	// Addresses of the entity (network subnets, listen addresses or NIC addresses)
+	// Example: ["10.0.0.1/24", "fd42:4242:4242:1010::1/64"]
+	Addresses []string `json:"addresses" yaml:"addresses"`
+}
+
+// NetworkTopologyEdge represents a connection between two entities of the network topology.
+//
+// swagger:model
+//
+// API extension: network_topology.
+type NetworkTopologyEdge struct {
+	// Identifier of the source node
+	// Example: /1.0/instances/c1?project=default#eth0
+	Source string `json:"source" yaml:"source"`
+
+	// Identifier of the target node
+	// Example: /1.0/networks/incusbr0?project=default
+	Target string `json:"target" yaml:"target"`
+
+	// Kind of connection (uplink, peer, integration, forward, load-balancer, target, device or nic)
+	// Example: nic
+	Kind string `json:"kind" yaml:"kind"`
+}
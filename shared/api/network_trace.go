@@ -0,0 +1,77 @@
+package api
+
+// NetworkTracePost represents a packet to trace through a network.
+//
+// swagger:model
+//
+// API extension: network_trace.
+type NetworkTracePost struct {
+	// Source address of the packet
+	// Example: 198.51.100.10
+	Source string `json:"source" yaml:"source"`
+
+	// Destination address of the packet
+	// Example: 192.0.2.1
+	Destination string `json:"destination" yaml:"destination"`
+
+	// Protocol of the packet (tcp, udp, icmp4 or icmp6)
+	// Example: tcp
+	Protocol string `json:"protocol" yaml:"protocol"`
+
+	// Source port of the packet (tcp and udp only)
+	// Example: 40000
+	SourcePort uint64 `json:"source_port" yaml:"source_port"`
+
+	// Destination port of the packet (tcp and udp only)
+	// Example: 443
+	DestinationPort uint64 `json:"destination_port" yaml:"destination_port"`
+
+	// ICMP message type (icmp4 and icmp6 only)
+	// Example: 8
+	ICMPType string `json:"icmp_type" yaml:"icmp_type"`
+
+	// ICMP message code (icmp4 and icmp6 only)
+	// Example: 0
+	ICMPCode string `json:"icmp_code" yaml:"icmp_code"`
+}
+
+// NetworkTrace represents the simulated path of a packet through a network.
+//
+// swagger:model
+//
+// API extension: network_trace.
+type NetworkTrace struct {
+	// Final verdict for the packet (allow, drop or reject)
+	// Example: allow
+	Verdict string `json:"verdict" yaml:"verdict"`
+
+	// Stages the packet went through, in order
+	Steps []NetworkTraceStep `json:"steps" yaml:"steps"`
+
+	// Raw output of the tracing tool (OVN only)
+	// Example: ingress(dp="incus-net1-ls-int", inport="incus-net1-instance-...")
+	Output string `json:"output" yaml:"output"`
+}
+
+// NetworkTraceStep represents a stage of a packet trace.
+//
+// swagger:model
+//
+// API extension: network_trace.
+type NetworkTraceStep struct {
+	// Stage of the packet path (forward, floating-ip, load-balancer, acl or route)
+	// Example: acl
+	Stage string `json:"stage" yaml:"stage"`
+
+	// Rule matching the packet at this stage
+	// Example: web (ingress rule 0)
+	Rule string `json:"rule" yaml:"rule"`
+
+	// Description of what happened to the packet
+	// Example: action=allow, protocol=tcp, destination_port=443
+	Description string `json:"description" yaml:"description"`
+
+	// Verdict of the stage (allow, drop, reject or empty when the packet continues unchanged)
+	// Example: allow
+	Verdict string `json:"verdict" yaml:"verdict"`
+}
@@ -50,6 +50,13 @@ type NetworkZone struct {
 	//
 	// API extension: network_zones_all_projects
 	Project string `json:"project" yaml:"project"`
+
+	// DS records of the zone's key signing keys (when DNSSEC is enabled)
+	// Read only: true
+	// Example: ["incus.example.net.\t3600\tIN\tDS\t2371 13 2 1F987CC6583E92DF0890718C42D6C1EC1C8AB7A1F8F2D2A8A5E1F5D7E1A1B2C3"]
+	//
+	// API extension: network_zones_dnssec
+	DSRecords []string `json:"ds_records,omitempty" yaml:"ds_records,omitempty"`
 }
 
 // Writable converts a full NetworkZone struct into a NetworkZonePut struct (filters read-only fields).
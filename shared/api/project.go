@@ -83,6 +83,35 @@ type ProjectState struct {
 	// Read only: true
 	// Example: {"containers": {"limit": 10, "usage": 4}, "cpu": {"limit": 20, "usage": 16}}
 	Resources map[string]ProjectStateResource `json:"resources" yaml:"resources"`
+
+	// Cumulative network traffic of the instances of the project
+	// Read only: true
+	//
+	// API extension: project_network_limits.
+	Network *ProjectStateNetwork `json:"network,omitempty" yaml:"network,omitempty"`
+}
+
+// ProjectStateNetwork represents the cumulative network traffic of the instances of a project
+//
+// swagger:model
+//
+// API extension: project_network_limits.
+type ProjectStateNetwork struct {
+	// Number of bytes received by the instances
+	// Example: 192021
+	BytesReceived int64 `json:"bytes_received" yaml:"bytes_received"`
+
+	// Number of bytes sent by the instances
+	// Example: 10888579
+	BytesSent int64 `json:"bytes_sent" yaml:"bytes_sent"`
+
+	// Number of packets received by the instances
+	// Example: 1748
+	PacketsReceived int64 `json:"packets_received" yaml:"packets_received"`
+
+	// Number of packets sent by the instances
+	// Example: 964
+	PacketsSent int64 `json:"packets_sent" yaml:"packets_sent"`
 }
 
 // ProjectStateResource represents the state of a particular resource in a project